@@ -0,0 +1,258 @@
+package clients
+
+import (
+	"errors"
+	"github.com/nacos-group/nacos-sdk-go/clients/config_client"
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/common/codec"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// 启动配置的环境变量，与BootstrapConfig的字段一一对应
+const (
+	Env_Server_Addr  = "NACOS_SERVER_ADDR"
+	Env_Endpoint     = "NACOS_ENDPOINT"
+	Env_Context_Path = "NACOS_CONTEXT_PATH"
+	Env_Namespace    = "NACOS_NAMESPACE"
+	Env_Username     = "NACOS_USERNAME"
+	Env_Password     = "NACOS_PASSWORD"
+	Env_Access_Key   = "NACOS_ACCESS_KEY"
+	Env_Secret_Key   = "NACOS_SECRET_KEY"
+	Env_App_Name     = "NACOS_APP_NAME"
+	Env_Timeout_Ms   = "NACOS_TIMEOUT_MS"
+	Env_Cache_Dir    = "NACOS_CACHE_DIR"
+	Env_Log_Dir      = "NACOS_LOG_DIR"
+	Env_Log_Level    = "NACOS_LOG_LEVEL"
+	Env_Tls_Enable   = "NACOS_TLS_ENABLE"
+)
+
+const (
+	// ServerAddr中未指定端口时使用的端口
+	Default_Server_Port = 8848
+	// 启动配置未指定TimeoutMs时的请求超时时间
+	Default_Bootstrap_Timeout_Ms = 10 * 1000
+)
+
+// 启动配置，可从环境变量或YAML/JSON文件读取，用于统一容器部署时创建客户端的方式
+type BootstrapConfig struct {
+	// 逗号分隔的服务端地址，如10.0.0.1:8848,https://nacos.example.com/nacos
+	// 未指定端口时为8848，未指定scheme时按TlsEnable决定，路径作为ContextPath
+	ServerAddr  string `json:"serverAddr" yaml:"serverAddr"`
+	Endpoint    string `json:"endpoint" yaml:"endpoint"`
+	ContextPath string `json:"contextPath" yaml:"contextPath"`
+	Namespace   string `json:"namespace" yaml:"namespace"`
+	Username    string `json:"username" yaml:"username"`
+	Password    string `json:"password" yaml:"password"`
+	AccessKey   string `json:"accessKey" yaml:"accessKey"`
+	SecretKey   string `json:"secretKey" yaml:"secretKey"`
+	AppName     string `json:"appName" yaml:"appName"`
+	// 为0时为Default_Bootstrap_Timeout_Ms
+	TimeoutMs uint64 `json:"timeoutMs" yaml:"timeoutMs"`
+	CacheDir  string `json:"cacheDir" yaml:"cacheDir"`
+	LogDir    string `json:"logDir" yaml:"logDir"`
+	LogLevel  string `json:"logLevel" yaml:"logLevel"`
+	TlsEnable bool   `json:"tlsEnable" yaml:"tlsEnable"`
+}
+
+// 从环境变量读取启动配置，未设置的环境变量对应的字段为空
+func LoadBootstrapFromEnv() (BootstrapConfig, error) {
+	var config BootstrapConfig
+	err := config.applyEnv()
+	return config, err
+}
+
+// 从YAML或JSON文件读取启动配置，按扩展名选择格式
+// 已设置的环境变量覆盖文件中的同名配置，便于通过环境变量注入密码等敏感信息
+func LoadBootstrapFile(path string) (BootstrapConfig, error) {
+	var config BootstrapConfig
+	configType := codec.DetectType(path)
+	if configType != codec.TYPE_JSON && configType != codec.TYPE_YAML {
+		return config, errors.New("[client.LoadBootstrapFile] unsupported bootstrap file:" + path)
+	}
+	c, ok := codec.Get(configType)
+	if !ok {
+		return config, errors.New("[client.LoadBootstrapFile] codec of " + configType + " is not registered, see codec.Register")
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+	if err = c.Unmarshal(data, &config); err != nil {
+		return config, errors.New("[client.LoadBootstrapFile] parse " + path + " failed:" + err.Error())
+	}
+	err = config.applyEnv()
+	return config, err
+}
+
+func (config *BootstrapConfig) applyEnv() error {
+	for env, field := range map[string]*string{
+		Env_Server_Addr:  &config.ServerAddr,
+		Env_Endpoint:     &config.Endpoint,
+		Env_Context_Path: &config.ContextPath,
+		Env_Namespace:    &config.Namespace,
+		Env_Username:     &config.Username,
+		Env_Password:     &config.Password,
+		Env_Access_Key:   &config.AccessKey,
+		Env_Secret_Key:   &config.SecretKey,
+		Env_App_Name:     &config.AppName,
+		Env_Cache_Dir:    &config.CacheDir,
+		Env_Log_Dir:      &config.LogDir,
+		Env_Log_Level:    &config.LogLevel,
+	} {
+		if value, ok := os.LookupEnv(env); ok {
+			*field = strings.TrimSpace(value)
+		}
+	}
+	if value := strings.TrimSpace(os.Getenv(Env_Timeout_Ms)); value != "" {
+		timeoutMs, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return errors.New("invalid " + Env_Timeout_Ms + ": " + value)
+		}
+		config.TimeoutMs = timeoutMs
+	}
+	if value := strings.TrimSpace(os.Getenv(Env_Tls_Enable)); value != "" {
+		tlsEnable, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("invalid " + Env_Tls_Enable + ": " + value)
+		}
+		config.TlsEnable = tlsEnable
+	}
+	return nil
+}
+
+// 转换为创建客户端所需的ClientConfig和ServerConfig，ServerAddr和Endpoint至少配置一个
+func (config BootstrapConfig) Build() (constant.ClientConfig, []constant.ServerConfig, error) {
+	timeoutMs := config.TimeoutMs
+	if timeoutMs == 0 {
+		timeoutMs = Default_Bootstrap_Timeout_Ms
+	}
+	clientConfig := constant.ClientConfig{
+		TimeoutMs:      timeoutMs,
+		ListenInterval: 30 * 1000,
+		BeatInterval:   5 * 1000,
+		NamespaceId:    config.Namespace,
+		AppName:        config.AppName,
+		Endpoint:       config.Endpoint,
+		ContextPath:    config.ContextPath,
+		AccessKey:      config.AccessKey,
+		SecretKey:      config.SecretKey,
+		Username:       config.Username,
+		Password:       config.Password,
+		CacheDir:       config.CacheDir,
+		LogDir:         config.LogDir,
+		LogLevel:       config.LogLevel,
+		TLSConfig:      constant.TLSConfig{Enable: config.TlsEnable},
+	}
+	if clientConfig.ListenInterval <= timeoutMs {
+		clientConfig.ListenInterval = timeoutMs + 10*1000
+	}
+	serverConfigs, err := parseServerAddr(config.ServerAddr, config.ContextPath)
+	if err != nil {
+		return clientConfig, nil, err
+	}
+	if len(serverConfigs) == 0 && config.Endpoint == "" {
+		return clientConfig, nil, errors.New("[client.Bootstrap] serverAddr or endpoint is required, set " + Env_Server_Addr + " or " + Env_Endpoint)
+	}
+	return clientConfig, serverConfigs, nil
+}
+
+// 解析逗号分隔的服务端地址，地址中未指定路径时使用contextPath
+func parseServerAddr(serverAddr string, contextPath string) ([]constant.ServerConfig, error) {
+	var serverConfigs []constant.ServerConfig
+	for _, addr := range strings.Split(serverAddr, ",") {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+		scheme, rawUrl := "", addr
+		if index := strings.Index(addr, "://"); index >= 0 {
+			scheme = addr[:index]
+		} else {
+			rawUrl = "http://" + addr
+		}
+		u, err := url.Parse(rawUrl)
+		if err != nil || u.Hostname() == "" || (scheme != "" && scheme != "http" && scheme != "https") {
+			return nil, errors.New("[client.Bootstrap] invalid server address:" + addr)
+		}
+		port := uint64(Default_Server_Port)
+		if u.Port() != "" {
+			if port, err = strconv.ParseUint(u.Port(), 10, 64); err != nil || port == 0 || port > 65535 {
+				return nil, errors.New("[client.Bootstrap] invalid server address:" + addr)
+			}
+		}
+		serverConfig := constant.ServerConfig{IpAddr: u.Hostname(), Port: port, Scheme: scheme, ContextPath: contextPath}
+		if path := strings.TrimRight(u.Path, "/"); path != "" {
+			serverConfig.ContextPath = path
+		}
+		serverConfigs = append(serverConfigs, serverConfig)
+	}
+	return serverConfigs, nil
+}
+
+func bootstrapProperties(config BootstrapConfig) (map[string]interface{}, error) {
+	clientConfig, serverConfigs, err := config.Build()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		constant.KEY_CLIENT_CONFIG:  clientConfig,
+		constant.KEY_SERVER_CONFIGS: serverConfigs,
+	}, nil
+}
+
+// 按环境变量NACOS_SERVER_ADDR、NACOS_NAMESPACE、NACOS_USERNAME等创建服务发现客户端，见BootstrapConfig
+func NewNamingClientFromEnv() (naming_client.INamingClient, error) {
+	config, err := LoadBootstrapFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return newNamingClientFromBootstrap(config)
+}
+
+// 按环境变量创建配置客户端，见NewNamingClientFromEnv
+func NewConfigClientFromEnv() (config_client.IConfigClient, error) {
+	config, err := LoadBootstrapFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return newConfigClientFromBootstrap(config)
+}
+
+// 按启动配置文件创建服务发现客户端，见LoadBootstrapFile
+func NewNamingClientFromFile(path string) (naming_client.INamingClient, error) {
+	config, err := LoadBootstrapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return newNamingClientFromBootstrap(config)
+}
+
+// 按启动配置文件创建配置客户端，见LoadBootstrapFile
+func NewConfigClientFromFile(path string) (config_client.IConfigClient, error) {
+	config, err := LoadBootstrapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return newConfigClientFromBootstrap(config)
+}
+
+func newNamingClientFromBootstrap(config BootstrapConfig) (naming_client.INamingClient, error) {
+	properties, err := bootstrapProperties(config)
+	if err != nil {
+		return nil, err
+	}
+	return CreateNamingClient(properties)
+}
+
+func newConfigClientFromBootstrap(config BootstrapConfig) (config_client.IConfigClient, error) {
+	properties, err := bootstrapProperties(config)
+	if err != nil {
+		return nil, err
+	}
+	return CreateConfigClient(properties)
+}
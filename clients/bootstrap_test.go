@@ -0,0 +1,88 @@
+package clients
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadBootstrapFromEnv(t *testing.T) {
+	os.Setenv(Env_Server_Addr, "10.0.0.1, https://nacos.example.com/nacos-gw,[::1]:9848")
+	os.Setenv(Env_Namespace, "dev")
+	os.Setenv(Env_Username, "nacos")
+	os.Setenv(Env_Timeout_Ms, "3000")
+	defer func() {
+		for _, env := range []string{Env_Server_Addr, Env_Namespace, Env_Username, Env_Timeout_Ms} {
+			os.Unsetenv(env)
+		}
+	}()
+	config, err := LoadBootstrapFromEnv()
+	assert.Nil(t, err)
+	clientConfig, serverConfigs, err := config.Build()
+	assert.Nil(t, err)
+	assert.Equal(t, "dev", clientConfig.NamespaceId)
+	assert.Equal(t, "nacos", clientConfig.Username)
+	assert.Equal(t, uint64(3000), clientConfig.TimeoutMs)
+	assert.Equal(t, []constant.ServerConfig{
+		{IpAddr: "10.0.0.1", Port: 8848},
+		{IpAddr: "nacos.example.com", Port: 8848, Scheme: "https", ContextPath: "/nacos-gw"},
+		{IpAddr: "::1", Port: 9848},
+	}, serverConfigs)
+
+	os.Setenv(Env_Timeout_Ms, "3s")
+	_, err = LoadBootstrapFromEnv()
+	assert.NotNil(t, err)
+}
+
+func TestBootstrapConfig_Build(t *testing.T) {
+	_, _, err := BootstrapConfig{}.Build()
+	assert.NotNil(t, err, "serverAddr or endpoint is required")
+	_, _, err = BootstrapConfig{ServerAddr: "10.0.0.1:70000"}.Build()
+	assert.NotNil(t, err)
+	_, _, err = BootstrapConfig{ServerAddr: "ftp://10.0.0.1"}.Build()
+	assert.NotNil(t, err)
+
+	clientConfig, serverConfigs, err := BootstrapConfig{Endpoint: "acm.aliyun.com", TimeoutMs: 60000}.Build()
+	assert.Nil(t, err)
+	assert.Empty(t, serverConfigs)
+	assert.True(t, clientConfig.TimeoutMs < clientConfig.ListenInterval)
+}
+
+func TestLoadBootstrapFile(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "nacos-bootstrap")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "nacos.json")
+	ioutil.WriteFile(path, []byte(`{"serverAddr":"10.0.0.1:8848","namespace":"dev","password":"file"}`), 0644)
+	os.Setenv(Env_Password, "env")
+	defer os.Unsetenv(Env_Password)
+	config, err := LoadBootstrapFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.0.1:8848", config.ServerAddr)
+	assert.Equal(t, "dev", config.Namespace)
+	assert.Equal(t, "env", config.Password, "env should override the file")
+
+	yamlPath := filepath.Join(dir, "nacos.yaml")
+	ioutil.WriteFile(yamlPath, []byte("serverAddr: 10.0.0.2:8848\ntimeoutMs: 3000\ntlsEnable: true\n"), 0644)
+	config, err = LoadBootstrapFile(yamlPath)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.0.2:8848", config.ServerAddr)
+	assert.Equal(t, uint64(3000), config.TimeoutMs)
+	assert.True(t, config.TlsEnable)
+
+	_, err = LoadBootstrapFile(filepath.Join(dir, "nacos.properties"))
+	assert.NotNil(t, err)
+}
+
+func TestNewNamingClientFromFile(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "nacos-bootstrap")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "nacos.json")
+	ioutil.WriteFile(path, []byte(`{"serverAddr":"127.0.0.1:8848","cacheDir":"`+dir+`","logDir":"`+dir+`"}`), 0644)
+	client, err := NewNamingClientFromFile(path)
+	assert.Nil(t, err)
+	assert.NotNil(t, client)
+	client.Close()
+}
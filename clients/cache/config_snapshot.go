@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"fmt"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 与Java客户端LocalConfigInfoProcessor一致的目录结构：
+// 快照：<dir>/snapshot[-tenant]/[tenant/]group/dataId，每次从服务端获取成功后写入
+// 容灾：<dir>/data/config-data[-tenant]/[tenant/]group/dataId，由用户维护，存在时优先使用
+func configFilePath(dir string, kind string, dataId string, group string, tenant string) string {
+	if tenant == "" {
+		return filepath.Join(dir, kind, group, dataId)
+	}
+	return filepath.Join(dir, kind+"-tenant", tenant, group, dataId)
+}
+
+// 与Java客户端一致的配置快照目录：<cacheDir>/config/<serverName>_nacos
+// 固定服务端列表时serverName为"fixed-ip_port[_ip_port...][-namespace]"，
+// 使用地址服务器时为"custom-endpoint_port_contextPath_serverlist[_namespace]"
+func GetJavaConfigSnapshotDir(cacheDir string, serverConfigs []constant.ServerConfig, clientConfig constant.ClientConfig) string {
+	var serverName string
+	if clientConfig.Endpoint != "" {
+		endpoint := clientConfig.Endpoint
+		if !strings.Contains(endpoint, ":") {
+			endpoint += ":8080"
+		}
+		contextPath := strings.Trim(clientConfig.ContextPath, "/")
+		if contextPath == "" {
+			contextPath = "nacos"
+		}
+		serverName = "custom-" + strings.Join([]string{endpoint, contextPath, "serverlist"}, "_")
+		if clientConfig.NamespaceId != "" {
+			serverName += "_" + clientConfig.NamespaceId
+		}
+	} else {
+		addresses := make([]string, 0, len(serverConfigs))
+		for _, server := range serverConfigs {
+			addresses = append(addresses, fmt.Sprintf("%s:%d", server.IpAddr, server.Port))
+		}
+		serverName = "fixed-" + strings.Join(addresses, "_")
+		if clientConfig.NamespaceId != "" {
+			serverName += "-" + clientConfig.NamespaceId
+		}
+	}
+	serverName = strings.NewReplacer("/", "_", ":", "_").Replace(serverName)
+	return filepath.Join(cacheDir, "config", serverName+"_nacos")
+}
+
+func GetConfigSnapshotFile(dir string, dataId string, group string, tenant string) string {
+	return configFilePath(dir, "snapshot", dataId, group, tenant)
+}
+
+func GetConfigFailoverFile(dir string, dataId string, group string, tenant string) string {
+	return configFilePath(filepath.Join(dir, "data"), "config-data", dataId, group, tenant)
+}
+
+// content为空时删除快照
+func WriteConfigSnapshot(dir string, dataId string, group string, tenant string, content string) error {
+	fileName := GetConfigSnapshotFile(dir, dataId, group, tenant)
+	if content == "" {
+		err := os.Remove(fileName)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return writeFileAtomic(fileName, []byte(content))
+}
+
+func ReadConfigSnapshot(dir string, dataId string, group string, tenant string) (string, error) {
+	b, err := ioutil.ReadFile(GetConfigSnapshotFile(dir, dataId, group, tenant))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// 容灾文件不存在时返回false
+func ReadConfigFailover(dir string, dataId string, group string, tenant string) (string, bool) {
+	b, err := ioutil.ReadFile(GetConfigFailoverFile(dir, dataId, group, tenant))
+	if err != nil {
+		return "", false
+	}
+	return string(b), true
+}
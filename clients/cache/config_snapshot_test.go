@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigSnapshot(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "nacos-config")
+	defer os.RemoveAll(dir)
+	assert.Equal(t, filepath.Join(dir, "snapshot", "group", "dataId"), GetConfigSnapshotFile(dir, "dataId", "group", ""))
+	assert.Equal(t, filepath.Join(dir, "snapshot-tenant", "tenant", "group", "dataId"), GetConfigSnapshotFile(dir, "dataId", "group", "tenant"))
+
+	assert.Nil(t, WriteConfigSnapshot(dir, "dataId", "group", "tenant", "content"))
+	content, err := ReadConfigSnapshot(dir, "dataId", "group", "tenant")
+	assert.Nil(t, err)
+	assert.Equal(t, "content", content)
+
+	assert.Nil(t, WriteConfigSnapshot(dir, "dataId", "group", "tenant", ""))
+	_, err = ReadConfigSnapshot(dir, "dataId", "group", "tenant")
+	assert.NotNil(t, err)
+}
+
+func TestReadConfigFailover(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "nacos-config")
+	defer os.RemoveAll(dir)
+	_, ok := ReadConfigFailover(dir, "dataId", "group", "")
+	assert.False(t, ok)
+
+	fileName := GetConfigFailoverFile(dir, "dataId", "group", "")
+	assert.Equal(t, filepath.Join(dir, "data", "config-data", "group", "dataId"), fileName)
+	os.MkdirAll(filepath.Dir(fileName), os.ModePerm)
+	ioutil.WriteFile(fileName, []byte("failover"), 0666)
+	content, ok := ReadConfigFailover(dir, "dataId", "group", "")
+	assert.True(t, ok)
+	assert.Equal(t, "failover", content)
+}
+
+func TestGetJavaConfigSnapshotDir(t *testing.T) {
+	servers := []constant.ServerConfig{{IpAddr: "10.0.0.1", Port: 8848}, {IpAddr: "10.0.0.2", Port: 8848}}
+	assert.Equal(t, filepath.Join("/cache", "config", "fixed-10.0.0.1_8848_10.0.0.2_8848_nacos"),
+		GetJavaConfigSnapshotDir("/cache", servers, constant.ClientConfig{}))
+	assert.Equal(t, filepath.Join("/cache", "config", "fixed-10.0.0.1_8848_10.0.0.2_8848-dev_nacos"),
+		GetJavaConfigSnapshotDir("/cache", servers, constant.ClientConfig{NamespaceId: "dev"}))
+	assert.Equal(t, filepath.Join("/cache", "config", "custom-acm.aliyun.com_8080_nacos_serverlist_dev_nacos"),
+		GetJavaConfigSnapshotDir("/cache", nil, constant.ClientConfig{Endpoint: "acm.aliyun.com", ContextPath: "/nacos", NamespaceId: "dev"}))
+}
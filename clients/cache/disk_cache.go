@@ -1,69 +1,260 @@
 package cache
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
 	"github.com/go-errors/errors"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/common/serializer"
 	"github.com/nacos-group/nacos-sdk-go/common/util"
 	"github.com/nacos-group/nacos-sdk-go/model"
 	"github.com/nacos-group/nacos-sdk-go/utils"
+	"hash/crc32"
 	"io/ioutil"
-	"log"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 )
 
 func GetFileName(cacheKey string, cacheDir string) string {
 	return cacheDir + string(os.PathSeparator) + cacheKey
 }
 
+// 服务缓存文件格式：首行为"#nacos-cache <版本> <写入时间毫秒> <内容crc32>"，之后为服务的JSON
+// 使用非JSON的序列化方式时版本为v2，首行末尾追加序列化方式的名称
+// 没有首行的旧版本缓存文件仍可读取，写入时间取文件修改时间
+const (
+	Cache_Header_Prefix     = "#nacos-cache"
+	Cache_Format_Version    = "v1"
+	Cache_Format_Version_V2 = "v2"
+	Corrupt_Dir             = "corrupt"
+)
+
+// 与Java客户端一致的服务缓存文件名：URL编码后的"group@@service"，集群不为空时追加"@@clusters"
+func GetJavaServiceFileName(serviceName string, clusters string) string {
+	return utils.GetServiceCacheKey(url.QueryEscape(serviceName), clusters)
+}
+
+// 按Java客户端的格式写入服务缓存，内容为不带首行的JSON，写入时间取文件修改时间
+func WriteServicesToJavaFile(service model.Service, cacheDir string) {
+	sb, err := serializer.Default().Marshal(service)
+	if err != nil {
+		logger.Errorf("failed to marshal service:%s ,err:%s", service.Name, err.Error())
+		return
+	}
+	domFileName := GetFileName(GetJavaServiceFileName(service.Name, service.Clusters), cacheDir)
+	if err = writeFileAtomic(domFileName, sb); err != nil {
+		logger.Errorf("faild to write name cache:%s ,value:%s ,err:%s", domFileName, string(sb), err.Error())
+	}
+}
+
+// Java客户端写入的缓存文件名中"@@"被编码，读取时还原为缓存key
+func serviceCacheKey(fileName string) string {
+	if !strings.Contains(fileName, url.QueryEscape(constant.SERVICE_INFO_SPLITER)) {
+		return fileName
+	}
+	if key, err := url.QueryUnescape(fileName); err == nil {
+		return key
+	}
+	return fileName
+}
+
 func WriteServicesToFile(service model.Service, cacheDir string) {
-	util.MkdirIfNecessary(cacheDir)
-	sb, _ := json.Marshal(service)
+	WriteServicesToFileWithSerializer(service, cacheDir, serializer.Default())
+}
+
+func WriteServicesToFileWithSerializer(service model.Service, cacheDir string, s serializer.Serializer) {
+	b, err := encodeService(service, s)
+	if err != nil {
+		logger.Errorf("failed to marshal service:%s ,err:%s", service.Name, err.Error())
+		return
+	}
 	domFileName := GetFileName(utils.GetServiceCacheKey(service.Name, service.Clusters), cacheDir)
+	err = writeFileAtomic(domFileName, b)
+	if err != nil {
+		logger.Errorf("faild to write name cache:%s ,value:%s ,err:%s", domFileName, string(b), err.Error())
+	}
 
-	err := ioutil.WriteFile(domFileName, sb, 0666)
+}
+
+// 返回带首行的服务缓存内容
+func encodeService(service model.Service, s serializer.Serializer) ([]byte, error) {
+	sb, err := s.Marshal(service)
 	if err != nil {
-		log.Printf("[ERROR]:faild to write name cache:%s ,value:%s ,err:%s \n", domFileName, string(sb), err.Error())
+		return nil, err
+	}
+	header := fmt.Sprintf("%s %s %d %08x\n", Cache_Header_Prefix, Cache_Format_Version, utils.CurrentMillis(), crc32.ChecksumIEEE(sb))
+	if s.Name() != serializer.NAME_JSON {
+		header = fmt.Sprintf("%s %s %d %08x %s\n", Cache_Header_Prefix, Cache_Format_Version_V2, utils.CurrentMillis(), crc32.ChecksumIEEE(sb), s.Name())
 	}
+	return append([]byte(header), sb...), nil
+}
 
+// 先写入同目录下的临时文件再重命名，避免写入中途崩溃留下不完整的文件
+func writeFileAtomic(fileName string, data []byte) error {
+	dir := filepath.Dir(fileName)
+	if err := util.MkdirIfNecessary(dir); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(dir, "."+filepath.Base(fileName)+".tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpName, 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmpName, fileName)
+	}
+	if err != nil {
+		os.Remove(tmpName)
+	}
+	return err
 }
 
 func ReadServicesFromFile(cacheDir string) map[string]model.Service {
+	return ReadServicesFromFileWithTTL(cacheDir, 0)
+}
+
+// 忽略写入时间早于ttl之前的缓存，ttl不大于0时不过期
+// 首行校验失败或无法解析的文件会被移到cacheDir下的corrupt目录
+func ReadServicesFromFileWithTTL(cacheDir string, ttl time.Duration) map[string]model.Service {
+	return ReadServicesFromFileWithSerializer(cacheDir, ttl, serializer.Default())
+}
+
+// JSON格式的缓存文件总能读取，其他格式的文件只有与s的名称相同时才读取，否则忽略
+func ReadServicesFromFileWithSerializer(cacheDir string, ttl time.Duration, s serializer.Serializer) map[string]model.Service {
 	files, err := ioutil.ReadDir(cacheDir)
 	if err != nil {
-		log.Printf("[ERROR]:read cacheDir:%s failed!err:%s \n", cacheDir, err.Error())
+		logger.Errorf("read cacheDir:%s failed!err:%s", cacheDir, err.Error())
 		return nil
 	}
 	serviceMap := map[string]model.Service{}
 	for _, f := range files {
+		//只读取服务缓存文件，忽略子目录、写入中的临时文件和容灾开关等其他文件
+		key := serviceCacheKey(f.Name())
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") || !strings.Contains(key, constant.SERVICE_INFO_SPLITER) {
+			continue
+		}
 		fileName := GetFileName(f.Name(), cacheDir)
 		b, err := ioutil.ReadFile(fileName)
 		if err != nil {
-			log.Printf("[ERROR]:failed to read name cache file:%s,err:%s! ", fileName, err.Error())
+			logger.Errorf("failed to read name cache file:%s,err:%s! ", fileName, err.Error())
 			continue
 		}
 
-		s := string(b)
-		service := utils.JsonToService(s)
-
-		if service == nil {
+		service, writeTime, err := decodeService(b, f.ModTime(), s)
+		if err == errUnknownCacheVersion || err == errSerializerMismatch {
+			logger.Warnf("ignore name cache file:%s,err:%s", fileName, err.Error())
+			continue
+		}
+		if err != nil {
+			logger.Errorf("name cache file:%s is corrupt,err:%s", fileName, err.Error())
+			quarantine(cacheDir, f.Name())
+			continue
+		}
+		if ttl > 0 && time.Since(writeTime) > ttl {
+			logger.Infof("name cache file:%s is expired, written at:%s", fileName, writeTime.String())
+			continue
+		}
+		if len(service.Hosts) == 0 {
+			logger.Warnf("instance list is empty,name cache file:%s", fileName)
 			continue
 		}
 
-		serviceMap[f.Name()] = *service
+		serviceMap[key] = service
 	}
 
-	log.Printf("finish loading name cache, total: " + strconv.Itoa(len(files)))
+	logger.Infof("finish loading name cache, total: %d", len(serviceMap))
 	return serviceMap
 }
 
+var (
+	errUnknownCacheVersion = errors.New("unknown cache format version")
+	errSerializerMismatch  = errors.New("cache written by another serializer")
+)
+
+// 解析服务缓存内容，返回errUnknownCacheVersion或errSerializerMismatch时应忽略该缓存，其他错误表示缓存已损坏
+func decodeService(b []byte, modTime time.Time, s serializer.Serializer) (model.Service, time.Time, error) {
+	var service model.Service
+	body, writeTime, name, err := parseCacheFile(b, modTime)
+	if err != nil {
+		return service, writeTime, err
+	}
+	decoder := s
+	if name == serializer.NAME_JSON && s.Name() != serializer.NAME_JSON {
+		decoder = serializer.Default()
+	} else if name != s.Name() {
+		return service, writeTime, errSerializerMismatch
+	}
+	err = decoder.Unmarshal(body, &service)
+	return service, writeTime, err
+}
+
+// 返回首行之后的内容、写入时间和序列化方式的名称，没有首行时按旧格式返回全部内容和modTime
+func parseCacheFile(b []byte, modTime time.Time) ([]byte, time.Time, string, error) {
+	if !bytes.HasPrefix(b, []byte(Cache_Header_Prefix+" ")) {
+		return b, modTime, serializer.NAME_JSON, nil
+	}
+	index := bytes.IndexByte(b, '\n')
+	if index < 0 {
+		return nil, modTime, serializer.NAME_JSON, errors.New("cache header is incomplete")
+	}
+	fields := strings.Fields(string(b[:index]))
+	name := serializer.NAME_JSON
+	switch {
+	case len(fields) >= 2 && fields[1] == Cache_Format_Version:
+		if len(fields) != 4 {
+			return nil, modTime, name, errors.New("cache header is malformed")
+		}
+	case len(fields) >= 2 && fields[1] == Cache_Format_Version_V2:
+		if len(fields) != 5 {
+			return nil, modTime, name, errors.New("cache header is malformed")
+		}
+		name = fields[4]
+	default:
+		return nil, modTime, name, errUnknownCacheVersion
+	}
+	millis, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, modTime, name, errors.New("cache header is malformed")
+	}
+	body := b[index+1:]
+	if fmt.Sprintf("%08x", crc32.ChecksumIEEE(body)) != fields[3] {
+		return nil, modTime, name, errors.New("cache checksum mismatch")
+	}
+	return body, time.Unix(0, millis*int64(time.Millisecond)), name, nil
+}
+
+// 将损坏的缓存文件移到corrupt目录保留现场，不再参与加载
+func quarantine(cacheDir string, name string) {
+	corruptDir := filepath.Join(cacheDir, Corrupt_Dir)
+	if err := util.MkdirIfNecessary(corruptDir); err != nil {
+		logger.Errorf("failed to create dir:%s,err:%s", corruptDir, err.Error())
+		return
+	}
+	if err := os.Rename(GetFileName(name, cacheDir), filepath.Join(corruptDir, name)); err != nil {
+		logger.Errorf("failed to quarantine name cache file:%s,err:%s", name, err.Error())
+	}
+}
+
 func WriteConfigToFile(cacheKey string, cacheDir string, content string) {
-	util.MkdirIfNecessary(cacheDir)
 	fileName := GetFileName(cacheKey, cacheDir)
-	err := ioutil.WriteFile(fileName, []byte(content), 0666)
+	err := writeFileAtomic(fileName, []byte(content))
 	if err != nil {
-		log.Printf("[ERROR]:faild to write config  cache:%s ,value:%s ,err:%s \n", fileName, string(content), err.Error())
+		logger.Errorf("faild to write config  cache:%s ,value:%s ,err:%s", fileName, string(content), err.Error())
 	}
 }
 
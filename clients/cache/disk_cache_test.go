@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"encoding/base64"
+	"github.com/nacos-group/nacos-sdk-go/common/serializer"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteServicesToFile_Atomic(t *testing.T) {
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	WriteServicesToFile(model.Service{Name: "DEFAULT_GROUP@@DEMO", Hosts: []model.Instance{{Ip: "10.0.0.10", Port: 80}}}, cacheDir)
+
+	files, _ := ioutil.ReadDir(cacheDir)
+	assert.Equal(t, 1, len(files), "temp file should be renamed")
+	b, _ := ioutil.ReadFile(GetFileName("DEFAULT_GROUP@@DEMO", cacheDir))
+	assert.True(t, strings.HasPrefix(string(b), Cache_Header_Prefix+" "+Cache_Format_Version+" "))
+
+	services := ReadServicesFromFile(cacheDir)
+	assert.Equal(t, uint64(80), services["DEFAULT_GROUP@@DEMO"].Hosts[0].Port)
+}
+
+func TestReadServicesFromFile_Legacy(t *testing.T) {
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	ioutil.WriteFile(GetFileName("DEFAULT_GROUP@@DEMO", cacheDir), []byte(`{"name":"DEFAULT_GROUP@@DEMO","hosts":[{"ip":"10.0.0.10","port":80}]}`), 0666)
+
+	services := ReadServicesFromFile(cacheDir)
+	assert.Equal(t, 1, len(services))
+	assert.Equal(t, "10.0.0.10", services["DEFAULT_GROUP@@DEMO"].Hosts[0].Ip)
+}
+
+func TestReadServicesFromFile_Corrupt(t *testing.T) {
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	WriteServicesToFile(model.Service{Name: "DEFAULT_GROUP@@DEMO", Hosts: []model.Instance{{Ip: "10.0.0.10", Port: 80}}}, cacheDir)
+	fileName := GetFileName("DEFAULT_GROUP@@DEMO", cacheDir)
+	b, _ := ioutil.ReadFile(fileName)
+	ioutil.WriteFile(fileName, b[:len(b)-5], 0666)
+	ioutil.WriteFile(GetFileName("DEFAULT_GROUP@@BROKEN", cacheDir), []byte(`{"name":"DEFAULT_GROUP@@BROKEN","ho`), 0666)
+
+	assert.Equal(t, 0, len(ReadServicesFromFile(cacheDir)))
+	_, err := os.Stat(fileName)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(cacheDir, Corrupt_Dir, "DEFAULT_GROUP@@DEMO"))
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(cacheDir, Corrupt_Dir, "DEFAULT_GROUP@@BROKEN"))
+	assert.Nil(t, err)
+}
+
+func TestReadServicesFromFileWithTTL(t *testing.T) {
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	WriteServicesToFile(model.Service{Name: "DEFAULT_GROUP@@DEMO", Hosts: []model.Instance{{Ip: "10.0.0.10", Port: 80}}}, cacheDir)
+
+	assert.Equal(t, 1, len(ReadServicesFromFileWithTTL(cacheDir, time.Hour)))
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 0, len(ReadServicesFromFileWithTTL(cacheDir, 10*time.Millisecond)))
+	_, err := os.Stat(GetFileName("DEFAULT_GROUP@@DEMO", cacheDir))
+	assert.Nil(t, err, "expired cache should not be quarantined")
+}
+
+// 以base64包装JSON，模拟非JSON格式的序列化方式
+type base64Serializer struct{}
+
+func (base64Serializer) Name() string {
+	return "base64"
+}
+
+func (base64Serializer) Marshal(v interface{}) ([]byte, error) {
+	data, err := serializer.Default().Marshal(v)
+	return []byte(base64.StdEncoding.EncodeToString(data)), err
+}
+
+func (base64Serializer) Unmarshal(data []byte, v interface{}) error {
+	decoded, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return err
+	}
+	return serializer.Default().Unmarshal(decoded, v)
+}
+
+func TestReadServicesFromFileWithSerializer(t *testing.T) {
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	WriteServicesToFileWithSerializer(model.Service{Name: "DEFAULT_GROUP@@DEMO", Hosts: []model.Instance{{Ip: "10.0.0.10", Port: 80}}},
+		cacheDir, base64Serializer{})
+	WriteServicesToFile(model.Service{Name: "DEFAULT_GROUP@@JSON", Hosts: []model.Instance{{Ip: "10.0.0.11", Port: 80}}}, cacheDir)
+	b, _ := ioutil.ReadFile(filepath.Join(cacheDir, "DEFAULT_GROUP@@DEMO"))
+	assert.True(t, strings.HasPrefix(string(b), Cache_Header_Prefix+" "+Cache_Format_Version_V2+" "))
+
+	// JSON格式的缓存总能读取
+	services := ReadServicesFromFileWithSerializer(cacheDir, 0, base64Serializer{})
+	assert.Equal(t, 2, len(services))
+	assert.Equal(t, "10.0.0.10", services["DEFAULT_GROUP@@DEMO"].Hosts[0].Ip)
+
+	// 其他序列化方式写入的缓存被忽略而不是视为损坏
+	services = ReadServicesFromFile(cacheDir)
+	assert.Equal(t, 1, len(services))
+	_, err := os.Stat(filepath.Join(cacheDir, Corrupt_Dir, "DEFAULT_GROUP@@DEMO"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestWriteServicesToJavaFile(t *testing.T) {
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	WriteServicesToJavaFile(model.Service{Name: "DEFAULT_GROUP@@DEMO", Clusters: "c1", Hosts: []model.Instance{{Ip: "10.0.0.10", Port: 80}}}, cacheDir)
+
+	assert.Equal(t, "DEFAULT_GROUP%40%40DEMO@@c1", GetJavaServiceFileName("DEFAULT_GROUP@@DEMO", "c1"))
+	b, err := ioutil.ReadFile(GetFileName("DEFAULT_GROUP%40%40DEMO@@c1", cacheDir))
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(b), "{"))
+
+	// Java客户端写入的未指定集群的服务文件名中不含"@@"
+	ioutil.WriteFile(GetFileName("DEFAULT_GROUP%40%40ORDER", cacheDir), []byte(`{"name":"DEFAULT_GROUP@@ORDER","hosts":[{"ip":"10.0.0.11","port":80}]}`), 0666)
+	services := ReadServicesFromFile(cacheDir)
+	assert.Equal(t, 2, len(services))
+	assert.Equal(t, uint64(80), services["DEFAULT_GROUP@@DEMO@@c1"].Hosts[0].Port)
+	assert.Equal(t, "10.0.0.11", services["DEFAULT_GROUP@@ORDER"].Hosts[0].Ip)
+}
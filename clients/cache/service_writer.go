@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/serializer"
+	"github.com/nacos-group/nacos-sdk-go/common/store"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/utils"
+	"sync"
+	"time"
+)
+
+// 合并一段时间内同一服务的多次变更，只把最后一次写入磁盘
+type ServiceWriter struct {
+	cacheDir   string
+	delay      time.Duration
+	serializer serializer.Serializer
+	javaLayout bool
+	store      store.Store
+	prefix     string
+	mutex      sync.Mutex
+	writeMutex sync.Mutex
+	pending    map[string]model.Service
+	timers     map[string]*time.Timer
+}
+
+func NewServiceWriter(cacheDir string, delay time.Duration) *ServiceWriter {
+	return NewServiceWriterWithSerializer(cacheDir, delay, serializer.Default())
+}
+
+func NewServiceWriterWithSerializer(cacheDir string, delay time.Duration, s serializer.Serializer) *ServiceWriter {
+	return &ServiceWriter{
+		cacheDir:   cacheDir,
+		delay:      delay,
+		serializer: s,
+		pending:    map[string]model.Service{},
+		timers:     map[string]*time.Timer{},
+	}
+}
+
+// 按Java客户端的文件名和格式写入缓存
+func NewJavaServiceWriter(cacheDir string, delay time.Duration) *ServiceWriter {
+	w := NewServiceWriter(cacheDir, delay)
+	w.javaLayout = true
+	return w
+}
+
+// 写入Store而不是cacheDir，key见WriteServicesToStore
+func NewStoreServiceWriter(s store.Store, prefix string, delay time.Duration, ser serializer.Serializer) *ServiceWriter {
+	w := NewServiceWriterWithSerializer("", delay, ser)
+	w.store = s
+	w.prefix = prefix
+	return w
+}
+
+func (w *ServiceWriter) write(service model.Service) {
+	if w.store != nil {
+		WriteServicesToStore(w.store, w.prefix, service, w.serializer)
+		return
+	}
+	if w.javaLayout {
+		WriteServicesToJavaFile(service, w.cacheDir)
+		return
+	}
+	WriteServicesToFileWithSerializer(service, w.cacheDir, w.serializer)
+}
+
+func (w *ServiceWriter) Write(service model.Service) {
+	if w.delay <= 0 {
+		w.writeMutex.Lock()
+		w.write(service)
+		w.writeMutex.Unlock()
+		return
+	}
+	key := utils.GetServiceCacheKey(service.Name, service.Clusters)
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.pending[key] = service
+	if _, ok := w.timers[key]; !ok {
+		w.timers[key] = time.AfterFunc(w.delay, func() {
+			w.flushKey(key)
+		})
+	}
+}
+
+func (w *ServiceWriter) flushKey(key string) {
+	w.writeMutex.Lock()
+	defer w.writeMutex.Unlock()
+	w.mutex.Lock()
+	service, ok := w.pending[key]
+	delete(w.pending, key)
+	delete(w.timers, key)
+	w.mutex.Unlock()
+	if ok {
+		w.write(service)
+	}
+}
+
+// 立即写入所有尚未落盘的服务
+func (w *ServiceWriter) Flush() {
+	w.writeMutex.Lock()
+	defer w.writeMutex.Unlock()
+	w.mutex.Lock()
+	pending := w.pending
+	for _, timer := range w.timers {
+		timer.Stop()
+	}
+	w.pending = map[string]model.Service{}
+	w.timers = map[string]*time.Timer{}
+	w.mutex.Unlock()
+	for _, service := range pending {
+		w.write(service)
+	}
+}
@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestServiceWriter_Write(t *testing.T) {
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	writer := NewServiceWriter(cacheDir, 200*time.Millisecond)
+	for i := 1; i <= 3; i++ {
+		writer.Write(model.Service{Name: "DEFAULT_GROUP@@DEMO", Hosts: []model.Instance{{Ip: "10.0.0.10", Port: uint64(i)}}})
+	}
+	assert.Equal(t, 0, len(ReadServicesFromFile(cacheDir)), "write should be deferred")
+
+	time.Sleep(400 * time.Millisecond)
+	services := ReadServicesFromFile(cacheDir)
+	assert.Equal(t, uint64(3), services["DEFAULT_GROUP@@DEMO"].Hosts[0].Port, "only the last change should be written")
+}
+
+func TestServiceWriter_Flush(t *testing.T) {
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	writer := NewServiceWriter(cacheDir, time.Hour)
+	writer.Write(model.Service{Name: "DEFAULT_GROUP@@DEMO", Hosts: []model.Instance{{Ip: "10.0.0.10", Port: 80}}})
+	writer.Flush()
+	services := ReadServicesFromFile(cacheDir)
+	assert.Equal(t, 1, len(services))
+}
@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/common/serializer"
+	"github.com/nacos-group/nacos-sdk-go/common/store"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/utils"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type fileStore struct {
+	dir string
+}
+
+// 以dir下的文件保存数据的Store，key中的"/"对应子目录
+func NewFileStore(dir string) store.Store {
+	return &fileStore{dir: dir}
+}
+
+func (s *fileStore) fileName(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(key))
+}
+
+func (s *fileStore) Get(key string) ([]byte, error) {
+	b, err := ioutil.ReadFile(s.fileName(key))
+	if os.IsNotExist(err) {
+		return nil, store.ErrNotFound
+	}
+	return b, err
+}
+
+func (s *fileStore) Put(key string, value []byte) error {
+	return writeFileAtomic(s.fileName(key), value)
+}
+
+func (s *fileStore) Delete(key string) error {
+	err := os.Remove(s.fileName(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// 只列出prefix所在目录下的文件，忽略子目录和写入中的临时文件
+func (s *fileStore) Keys(prefix string) ([]string, error) {
+	dir, base := path.Split(prefix)
+	files, err := ioutil.ReadDir(s.fileName(dir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, f := range files {
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") || !strings.HasPrefix(f.Name(), base) {
+			continue
+		}
+		keys = append(keys, dir+f.Name())
+	}
+	return keys, nil
+}
+
+// 服务缓存在Store中的key：<prefix><group@@service[@@clusters]>
+func WriteServicesToStore(s store.Store, prefix string, service model.Service, ser serializer.Serializer) {
+	b, err := encodeService(service, ser)
+	if err != nil {
+		logger.Errorf("failed to marshal service:%s ,err:%s", service.Name, err.Error())
+		return
+	}
+	key := prefix + utils.GetServiceCacheKey(service.Name, service.Clusters)
+	if err = s.Put(key, b); err != nil {
+		logger.Errorf("faild to write name cache:%s to store,err:%s", key, err.Error())
+	}
+}
+
+// 与ReadServicesFromFileWithSerializer一致，损坏的缓存直接从Store中删除
+func ReadServicesFromStore(s store.Store, prefix string, ttl time.Duration, ser serializer.Serializer) map[string]model.Service {
+	keys, err := s.Keys(prefix)
+	if err != nil {
+		logger.Errorf("list name cache from store failed!prefix:%s err:%s", prefix, err.Error())
+		return nil
+	}
+	serviceMap := map[string]model.Service{}
+	for _, key := range keys {
+		b, err := s.Get(key)
+		if err != nil {
+			logger.Errorf("failed to read name cache:%s from store,err:%s", key, err.Error())
+			continue
+		}
+		// 没有首行的缓存无法得知写入时间，视为刚写入
+		service, writeTime, err := decodeService(b, time.Now(), ser)
+		if err == errUnknownCacheVersion || err == errSerializerMismatch {
+			logger.Warnf("ignore name cache:%s,err:%s", key, err.Error())
+			continue
+		}
+		if err != nil {
+			logger.Errorf("name cache:%s is corrupt,err:%s", key, err.Error())
+			if err = s.Delete(key); err != nil {
+				logger.Errorf("failed to delete name cache:%s,err:%s", key, err.Error())
+			}
+			continue
+		}
+		if ttl > 0 && time.Since(writeTime) > ttl {
+			logger.Infof("name cache:%s is expired, written at:%s", key, writeTime.String())
+			continue
+		}
+		if len(service.Hosts) == 0 {
+			logger.Warnf("instance list is empty,name cache:%s", key)
+			continue
+		}
+		serviceMap[strings.TrimPrefix(key, prefix)] = service
+	}
+	logger.Infof("finish loading name cache from store, total: %d", len(serviceMap))
+	return serviceMap
+}
+
+// 配置快照在Store中的key：<prefix><group>/<dataId>，content为空时删除快照
+func WriteConfigSnapshotToStore(s store.Store, prefix string, dataId string, group string, content string) error {
+	key := prefix + group + "/" + dataId
+	if content == "" {
+		return s.Delete(key)
+	}
+	return s.Put(key, []byte(content))
+}
+
+func ReadConfigSnapshotFromStore(s store.Store, prefix string, dataId string, group string) (string, error) {
+	b, err := s.Get(prefix + group + "/" + dataId)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
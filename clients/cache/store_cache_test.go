@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/serializer"
+	"github.com/nacos-group/nacos-sdk-go/common/store"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileStore(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "nacos-store")
+	defer os.RemoveAll(dir)
+	s := NewFileStore(dir)
+	_, err := s.Get("naming/public/DEFAULT_GROUP@@DEMO")
+	assert.Equal(t, store.ErrNotFound, err)
+	keys, err := s.Keys("naming/public/")
+	assert.Nil(t, err)
+	assert.Empty(t, keys)
+
+	assert.Nil(t, s.Put("naming/public/DEFAULT_GROUP@@DEMO", []byte("demo")))
+	assert.Nil(t, s.Put("naming/public/DEFAULT_GROUP@@ORDER", []byte("order")))
+	assert.Nil(t, s.Put("naming/dev/DEFAULT_GROUP@@DEMO", []byte("dev")))
+	b, err := ioutil.ReadFile(filepath.Join(dir, "naming", "public", "DEFAULT_GROUP@@DEMO"))
+	assert.Nil(t, err)
+	assert.Equal(t, "demo", string(b))
+
+	keys, err = s.Keys("naming/public/DEFAULT_GROUP@@")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"naming/public/DEFAULT_GROUP@@DEMO", "naming/public/DEFAULT_GROUP@@ORDER"}, keys)
+	assert.Nil(t, s.Delete("naming/public/DEFAULT_GROUP@@DEMO"))
+	assert.Nil(t, s.Delete("naming/public/DEFAULT_GROUP@@DEMO"))
+	keys, _ = s.Keys("naming/public/")
+	assert.Equal(t, []string{"naming/public/DEFAULT_GROUP@@ORDER"}, keys)
+}
+
+func TestReadServicesFromStore(t *testing.T) {
+	s := store.NewMemoryStore()
+	WriteServicesToStore(s, "naming/public/", model.Service{Name: "DEFAULT_GROUP@@DEMO", Hosts: []model.Instance{{Ip: "10.0.0.10", Port: 80}}}, serializer.Default())
+	WriteServicesToStore(s, "naming/dev/", model.Service{Name: "DEFAULT_GROUP@@DEMO", Hosts: []model.Instance{{Ip: "10.0.1.10", Port: 80}}}, serializer.Default())
+	s.Put("naming/public/DEFAULT_GROUP@@BROKEN", []byte(Cache_Header_Prefix+" "+Cache_Format_Version+" 0 00000000\n{}"))
+
+	services := ReadServicesFromStore(s, "naming/public/", time.Hour, serializer.Default())
+	assert.Equal(t, 1, len(services))
+	assert.Equal(t, "10.0.0.10", services["DEFAULT_GROUP@@DEMO"].Hosts[0].Ip)
+	_, err := s.Get("naming/public/DEFAULT_GROUP@@BROKEN")
+	assert.Equal(t, store.ErrNotFound, err, "corrupt cache should be deleted")
+
+	// JSON格式的缓存总能读取
+	assert.Equal(t, 1, len(ReadServicesFromStore(s, "naming/public/", time.Hour, base64Serializer{})))
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 0, len(ReadServicesFromStore(s, "naming/public/", 10*time.Millisecond, serializer.Default())))
+}
+
+func TestConfigSnapshotStore(t *testing.T) {
+	s := store.NewMemoryStore()
+	assert.Nil(t, WriteConfigSnapshotToStore(s, "config/public/", "app.yaml", "DEFAULT_GROUP", "a: 1"))
+	content, err := ReadConfigSnapshotFromStore(s, "config/public/", "app.yaml", "DEFAULT_GROUP")
+	assert.Nil(t, err)
+	assert.Equal(t, "a: 1", content)
+
+	assert.Nil(t, WriteConfigSnapshotToStore(s, "config/public/", "app.yaml", "DEFAULT_GROUP", ""))
+	_, err = ReadConfigSnapshotFromStore(s, "config/public/", "app.yaml", "DEFAULT_GROUP")
+	assert.Equal(t, store.ErrNotFound, err)
+}
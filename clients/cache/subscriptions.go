@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// 订阅文件保存在缓存目录下的独立目录中，避免被当作服务缓存读取
+const Subscriptions_Dir = "subscriptions"
+
+type ServiceSubscription struct {
+	ServiceName string `json:"serviceName"` // group@@service
+	Clusters    string `json:"clusters"`
+}
+
+type ConfigSubscription struct {
+	DataId string `json:"dataId"`
+	Group  string `json:"group"`
+}
+
+// 客户端运行时的订阅和监听，用于重启后自动恢复
+type Subscriptions struct {
+	Services []ServiceSubscription `json:"services,omitempty"`
+	Configs  []ConfigSubscription  `json:"configs,omitempty"`
+}
+
+// kind为naming或config，不同命名空间的订阅分别保存
+func GetSubscriptionFile(cacheDir string, kind string, namespace string) string {
+	if namespace == "" {
+		namespace = "public"
+	}
+	return filepath.Join(cacheDir, Subscriptions_Dir, kind+"_"+namespace+".json")
+}
+
+func WriteSubscriptions(fileName string, subscriptions Subscriptions) error {
+	data, err := json.Marshal(subscriptions)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(fileName, data)
+}
+
+// 文件不存在时返回空的订阅
+func ReadSubscriptions(fileName string) (Subscriptions, error) {
+	var subscriptions Subscriptions
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return subscriptions, nil
+		}
+		return subscriptions, err
+	}
+	err = json.Unmarshal(data, &subscriptions)
+	return subscriptions, err
+}
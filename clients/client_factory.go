@@ -5,6 +5,7 @@ import (
 	"github.com/nacos-group/nacos-sdk-go/clients/config_client"
 	"github.com/nacos-group/nacos-sdk-go/clients/nacos_client"
 	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/clients/operator_client"
 	"github.com/nacos-group/nacos-sdk-go/common/constant"
 	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
 )
@@ -17,13 +18,15 @@ func CreateConfigClient(properties map[string]interface{}) (iClient config_clien
 		err = errSetConfig
 		return
 	}
-	nacosClient.SetHttpAgent(&http_agent.HttpAgent{})
+	if err = setHttpAgent(nacosClient); err != nil {
+		return
+	}
 	config, errNew := config_client.NewConfigClient(nacosClient)
 	if errNew != nil {
 		err = errNew
 		return
 	}
-	iClient = &config
+	iClient = config
 	return
 }
 
@@ -34,7 +37,9 @@ func CreateNamingClient(properties map[string]interface{}) (iClient naming_clien
 		err = errSetConfig
 		return
 	}
-	nacosClient.SetHttpAgent(&http_agent.HttpAgent{})
+	if err = setHttpAgent(nacosClient); err != nil {
+		return
+	}
 	naming, errNew := naming_client.NewNamingClient(nacosClient)
 	if errNew != nil {
 		err = errNew
@@ -44,6 +49,38 @@ func CreateNamingClient(properties map[string]interface{}) (iClient naming_clien
 	return
 }
 
+// 创建服务端运维相关的客户端
+func CreateOperatorClient(properties map[string]interface{}) (iClient operator_client.IOperatorClient, err error) {
+	nacosClient, errSetConfig := setConfig(properties)
+	if errSetConfig != nil {
+		err = errSetConfig
+		return
+	}
+	if err = setHttpAgent(nacosClient); err != nil {
+		return
+	}
+	operator, errNew := operator_client.NewOperatorClient(nacosClient)
+	if errNew != nil {
+		err = errNew
+		return
+	}
+	iClient = &operator
+	return
+}
+
+// 按ClientConfig中的TLS配置创建HttpAgent
+func setHttpAgent(nacosClient nacos_client.INacosClient) error {
+	clientConfig, err := nacosClient.GetClientConfig()
+	if err != nil {
+		return err
+	}
+	httpAgent, err := http_agent.NewHttpAgentWithConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+	return nacosClient.SetHttpAgent(httpAgent)
+}
+
 func setConfig(properties map[string]interface{}) (iClient nacos_client.INacosClient, err error) {
 	client := nacos_client.NacosClient{}
 	if clientConfigTmp, exist := properties[constant.KEY_CLIENT_CONFIG]; exist {
@@ -70,7 +107,8 @@ func setConfig(properties map[string]interface{}) (iClient nacos_client.INacosCl
 		}
 	} else {
 		clientConfig, _ := client.GetClientConfig()
-		if len(clientConfig.Endpoint) <= 0 {
+		//仅使用本地缓存或通过sidecar访问时可以不配置服务端
+		if len(clientConfig.Endpoint) <= 0 && !clientConfig.CacheOnly && clientConfig.Agent == nil {
 			err = errors.New("server configs not found in properties")
 			return
 		}
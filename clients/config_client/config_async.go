@@ -0,0 +1,27 @@
+package config_client
+
+import (
+	"context"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+// 在后台获取配置，完成后向返回的channel写入一个结果，channel有缓冲，不读取也不会阻塞后台协程
+func (client *ConfigClient) GetConfigAsync(ctx context.Context, param vo.ConfigParam) <-chan model.ConfigResult {
+	result := make(chan model.ConfigResult, 1)
+	go func() {
+		content, err := client.GetConfigWithContext(ctx, param)
+		result <- model.ConfigResult{Content: content, Err: err}
+	}()
+	return result
+}
+
+// 在后台发布配置，完成后向返回的channel写入一个结果
+func (client *ConfigClient) PublishConfigAsync(ctx context.Context, param vo.ConfigParam) <-chan model.AsyncResult {
+	result := make(chan model.AsyncResult, 1)
+	go func() {
+		success, err := client.PublishConfigWithContext(ctx, param)
+		result <- model.AsyncResult{Success: success, Err: err}
+	}()
+	return result
+}
@@ -1,40 +1,65 @@
 package config_client
 
 import (
+	"context"
 	"errors"
 	"github.com/nacos-group/nacos-sdk-go/clients/cache"
 	"github.com/nacos-group/nacos-sdk-go/clients/nacos_client"
+	"github.com/nacos-group/nacos-sdk-go/common/codec"
 	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/encryption"
 	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
 	"github.com/nacos-group/nacos-sdk-go/common/logger"
 	"github.com/nacos-group/nacos-sdk-go/common/nacos_error"
-	"github.com/nacos-group/nacos-sdk-go/common/util"
+	"github.com/nacos-group/nacos-sdk-go/common/nacos_server"
+	"github.com/nacos-group/nacos-sdk-go/common/server_list"
+	"github.com/nacos-group/nacos-sdk-go/common/store"
+	"github.com/nacos-group/nacos-sdk-go/common/validator"
 	"github.com/nacos-group/nacos-sdk-go/utils"
 	"github.com/nacos-group/nacos-sdk-go/vo"
 
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/kms"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"os"
 	"strconv"
 	"strings"
 	"sync"
-	"time"
 )
 
 type ConfigClient struct {
 	nacos_client.INacosClient
-	kmsClient      *kms.Client
+	kmsPlugin      encryption.Plugin
 	localConfigs   []vo.ConfigParam
 	mutex          sync.Mutex
 	configProxy    ConfigProxy
 	configCacheDir string
+	snapshotDir    string
+	snapshotStore  store.Store
+	snapshotPrefix string
+	closeChan      chan struct{}
+	closeOnce      *sync.Once
+	listener       *configListener
+	restorer       *listenRestorer
+	readCache      *configReadCache
 }
 
-func NewConfigClient(nc nacos_client.INacosClient) (ConfigClient, error) {
-	config := ConfigClient{}
+func NewConfigClient(nc nacos_client.INacosClient) (*ConfigClient, error) {
+	return newConfigClient(nc, nil)
+}
+
+// 使用已有的NacosServer创建客户端，用于多个命名空间的客户端共享服务端列表和鉴权信息
+func NewConfigClientWithServer(nc nacos_client.INacosClient, nacosServer nacos_server.NacosServer) (*ConfigClient, error) {
+	return newConfigClient(nc, &nacosServer)
+}
+
+// 返回指针，恢复监听等后台协程与调用方使用同一个客户端的localConfigs和锁
+func newConfigClient(nc nacos_client.INacosClient, nacosServer *nacos_server.NacosServer) (*ConfigClient, error) {
+	config := &ConfigClient{}
 	config.INacosClient = nc
+	config.closeChan = make(chan struct{})
+	config.closeOnce = &sync.Once{}
+	config.listener = newConfigListener()
 	clientConfig, err := nc.GetClientConfig()
 	if err != nil {
 		return config, err
@@ -47,46 +72,102 @@ func NewConfigClient(nc nacos_client.INacosClient) (ConfigClient, error) {
 	if err != nil {
 		return config, err
 	}
-	err = logger.InitLog(clientConfig.LogDir)
-	if err != nil {
+	if clientConfig.Logger != nil {
+		logger.SetLogger(clientConfig.Logger)
+	} else if err = logger.InitLog(clientConfig.LogDir, clientConfig.LogLevel); err != nil {
 		return config, err
 	}
+	config.readCache = newConfigReadCache(clientConfig.ConfigCache)
 	config.configCacheDir = clientConfig.CacheDir + string(os.PathSeparator) + "config"
-	config.configProxy, err = NewConfigProxy(serverConfig, clientConfig, httpAgent)
+	config.snapshotDir = clientConfig.ConfigSnapshotDir
+	if config.snapshotDir == "" && clientConfig.JavaCacheLayout {
+		config.snapshotDir = cache.GetJavaConfigSnapshotDir(clientConfig.CacheDir, serverConfig, clientConfig)
+	} else if config.snapshotDir == "" {
+		config.snapshotDir = config.configCacheDir
+	}
+	if clientConfig.CacheStore != nil {
+		// 快照在Store中的key前缀：config/<namespace>/，容灾文件仍从snapshotDir读取
+		namespace := clientConfig.NamespaceId
+		if namespace == "" {
+			namespace = constant.DEFAULT_NAMESPACE_ID
+		}
+		config.snapshotStore = clientConfig.CacheStore
+		config.snapshotPrefix = "config/" + namespace + "/"
+	}
+	if nacosServer != nil {
+		config.configProxy = ConfigProxy{nacosServer: *nacosServer, content: clientConfig.ConfigContent}
+	} else {
+		config.configProxy, err = NewConfigProxy(serverConfig, clientConfig, httpAgent)
+	}
 	if clientConfig.OpenKMS {
-		kmsClient, err := kms.NewClientWithAccessKey(clientConfig.RegionId, clientConfig.AccessKey, clientConfig.SecretKey)
+		kmsClient, err := newKmsClient(clientConfig)
 		if err != nil {
 			return config, err
 		}
-		config.kmsClient = kmsClient
+		config.kmsPlugin = encryption.NewKmsPlugin(kmsClient, clientConfig.KMSKeyId)
+	}
+	if err == nil {
+		if transport := config.configProxy.nacosServer.Transport(); transport != nil {
+			config.startRemoteListener(transport)
+		}
+		if config.restorer = newListenRestorer(clientConfig); config.restorer != nil {
+			config.restoreListening()
+		}
 	}
 
 	return config, err
 }
 
+func newKmsClient(clientConfig constant.ClientConfig) (*kms.Client, error) {
+	if clientConfig.AccessKey == "" && clientConfig.RamRoleName != "" {
+		return kms.NewClientWithEcsRamRole(clientConfig.RegionId, clientConfig.RamRoleName)
+	}
+	if clientConfig.SecurityToken != "" {
+		return kms.NewClientWithStsToken(clientConfig.RegionId, clientConfig.AccessKey, clientConfig.SecretKey, clientConfig.SecurityToken)
+	}
+	return kms.NewClientWithAccessKey(clientConfig.RegionId, clientConfig.AccessKey, clientConfig.SecretKey)
+}
+
 func (client *ConfigClient) sync() (clientConfig constant.ClientConfig,
 	serverConfigs []constant.ServerConfig, agent http_agent.IHttpAgent, err error) {
 	clientConfig, err = client.GetClientConfig()
 	if err != nil {
-		log.Println(err, ";do you call client.SetClientConfig()?")
+		logger.Errorf("%s;do you call client.SetClientConfig()?", err.Error())
 	}
 	if err == nil {
 		serverConfigs, err = client.GetServerConfig()
 		if err != nil {
-			log.Println(err, ";do you call client.SetServerConfig()?")
+			logger.Errorf("%s;do you call client.SetServerConfig()?", err.Error())
 		}
 	}
 	if err == nil {
 		agent, err = client.GetHttpAgent()
 		if err != nil {
-			log.Println(err, ";do you call client.SetHttpAgent()?")
+			logger.Errorf("%s;do you call client.SetHttpAgent()?", err.Error())
 		}
 	}
 	return
 }
 
 func (client *ConfigClient) GetConfig(param vo.ConfigParam) (content string, err error) {
-	content, err = client.getConfigInner(param)
+	return client.GetConfigWithContext(context.Background(), param)
+}
+
+func (client *ConfigClient) GetConfigWithContext(ctx context.Context, param vo.ConfigParam) (content string, err error) {
+	if err = validator.New("GetConfig").DataId("dataId", param.DataId).Group("group", param.Group).Err(); err != nil {
+		return "", err
+	}
+	if param.Beta {
+		content, err = client.getBetaConfig(ctx, param)
+	} else if data, ok := client.validatedConfig(param.DataId, param.Group); ok {
+		return data, nil
+	} else if client.readCache != nil && param.Tag == "" {
+		content, err = client.readCache.get(ctx, client.configKey(param.DataId, param.Group), func(ctx context.Context) (string, error) {
+			return client.getConfigInner(ctx, param)
+		})
+	} else {
+		content, err = client.getConfigInner(ctx, param)
+	}
 
 	if err != nil {
 		return "", err
@@ -95,84 +176,271 @@ func (client *ConfigClient) GetConfig(param vo.ConfigParam) (content string, err
 	return client.decrypt(param.DataId, content)
 }
 
-func (client *ConfigClient) decrypt(dataId, content string) (string, error) {
-	if strings.HasPrefix(dataId, "cipher-") && client.kmsClient != nil {
-		request := kms.CreateDecryptRequest()
-		request.Method = "POST"
-		request.Scheme = "https"
-		request.AcceptFormat = "json"
-		request.CiphertextBlob = content
-		response, err := client.kmsClient.Decrypt(request)
-		if err != nil {
-			return "", errors.New("ksm decrypt failed")
+func (client *ConfigClient) configKey(dataId, group string) string {
+	clientConfig, _ := client.GetClientConfig()
+	return utils.GetConfigCacheKey(dataId, group, clientConfig.NamespaceId)
+}
+
+// 发布或删除成功后使GetConfig的缓存失效
+func (client *ConfigClient) invalidateReadCache(dataId, group string, success bool) {
+	if success && client.readCache != nil {
+		client.readCache.invalidate(client.configKey(dataId, group))
+	}
+}
+
+// 获取配置并按配置类型解析到v中，v应为指针
+func (client *ConfigClient) GetConfigAs(param vo.ConfigParam, v interface{}) error {
+	return client.GetConfigAsWithContext(context.Background(), param, v)
+}
+
+func (client *ConfigClient) GetConfigAsWithContext(ctx context.Context, param vo.ConfigParam, v interface{}) error {
+	if param.Type != "" {
+		if _, err := getCodec(param, ""); err != nil {
+			return err
+		}
+	}
+	ctx, responseInfo := nacos_server.WithResponseInfo(ctx)
+	content, err := client.GetConfigWithContext(ctx, param)
+	if err != nil {
+		return err
+	}
+	// 命中缓存时没有响应头，使用监听时记录的类型
+	serverType := responseConfigType(responseInfo)
+	if serverType == "" {
+		serverType = client.listenedConfigType(param.DataId, param.Group)
+	}
+	c, err := getCodec(param, serverType)
+	if err != nil {
+		return err
+	}
+	return c.Unmarshal([]byte(content), v)
+}
+
+// 监听配置，配置变化时将新内容解析到newValue返回的对象中并回调onChange
+func (client *ConfigClient) ListenConfigAs(param vo.ConfigParam, newValue func() interface{},
+	onChange func(value interface{}, err error)) error {
+	if newValue == nil || onChange == nil {
+		return errors.New("[client.ListenConfigAs] newValue and onChange can not be nil")
+	}
+	if param.Type != "" {
+		if _, err := getCodec(param, ""); err != nil {
+			return err
+		}
+	}
+	param.OnChange = func(namespace, group, dataId, data string) {
+		value := newValue()
+		c, err := getCodec(param, client.listenedConfigType(dataId, group))
+		if err == nil {
+			err = c.Unmarshal([]byte(data), value)
 		}
-		content = response.Plaintext
+		onChange(value, err)
 	}
+	return client.ListenConfig(param)
+}
 
-	return content, nil
+// 优先使用param.Type，其次为服务端返回的类型serverType，均未指定时根据dataId的扩展名推断
+// 服务端未设置类型时返回text，serverType没有注册的Codec时忽略
+func getCodec(param vo.ConfigParam, serverType string) (codec.Codec, error) {
+	configType := param.Type
+	if configType == "" && serverType != "" {
+		if c, ok := codec.Get(serverType); ok {
+			return c, nil
+		}
+	}
+	if configType == "" {
+		configType = codec.DetectType(param.DataId)
+	}
+	if configType == "" {
+		return nil, errors.New("[client.GetConfigAs] can not detect config type of dataId:" + param.DataId + ", please set param.Type")
+	}
+	c, ok := codec.Get(configType)
+	if !ok {
+		return nil, errors.New("[client.GetConfigAs] no codec registered for config type:" + configType)
+	}
+	return c, nil
+}
+
+// OpenKMS时cipher-前缀的配置使用KMS，其他配置使用按前缀注册的插件，均未匹配时不加解密
+func (client *ConfigClient) encryptionPlugin(dataId string) encryption.Plugin {
+	if client.kmsPlugin != nil && strings.HasPrefix(dataId, encryption.Default_Cipher_Prefix) {
+		return client.kmsPlugin
+	}
+	if plugin, ok := encryption.Find(dataId); ok {
+		return plugin
+	}
+	return nil
 }
 
-func (client *ConfigClient) getConfigInner(param vo.ConfigParam) (content string, err error) {
-	if len(param.DataId) <= 0 {
-		err = errors.New("[client.GetConfig] param.dataId can not be empty")
+func (client *ConfigClient) decrypt(dataId, content string) (string, error) {
+	plugin := client.encryptionPlugin(dataId)
+	if plugin == nil || content == "" {
+		return content, nil
 	}
-	if len(param.Group) <= 0 {
-		err = errors.New("[client.GetConfig] param.group can not be empty")
+	plaintext, err := plugin.Decrypt(content)
+	if err != nil {
+		return "", nacos_error.Wrap("[client.GetConfig] decrypt config failed, dataId:"+dataId, err)
 	}
+	return plaintext, nil
+}
+
+func (client *ConfigClient) encrypt(dataId, content string) (string, error) {
+	plugin := client.encryptionPlugin(dataId)
+	if plugin == nil {
+		return content, nil
+	}
+	ciphertext, err := plugin.Encrypt(content)
+	if err != nil {
+		return "", nacos_error.Wrap("[client.PublishConfig] encrypt config failed, dataId:"+dataId, err)
+	}
+	return ciphertext, nil
+}
+
+// beta配置不读取或写入本地快照
+func (client *ConfigClient) getBetaConfig(ctx context.Context, param vo.ConfigParam) (string, error) {
+	clientConfig, _ := client.GetClientConfig()
+	return client.configProxy.GetBetaConfigProxy(ctx, param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+}
+
+func (client *ConfigClient) getConfigInner(ctx context.Context, param vo.ConfigParam) (content string, err error) {
+	content, fromServer, err := client.fetchConfig(ctx, param)
+	if err == nil && fromServer {
+		clientConfig, _ := client.GetClientConfig()
+		client.saveSnapshot(param.DataId, param.Group, clientConfig.NamespaceId, content)
+	}
+	return content, err
+}
+
+// 依次从容灾文件、服务端和快照获取配置，fromServer表示内容来自服务端，由调用方决定是否写入快照
+func (client *ConfigClient) fetchConfig(ctx context.Context, param vo.ConfigParam) (content string, fromServer bool, err error) {
 	clientConfig, _ := client.GetClientConfig()
-	cacheKey := utils.GetConfigCacheKey(param.DataId, param.Group, clientConfig.NamespaceId)
-	content, err = client.configProxy.GetConfigProxy(param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+	tenant := clientConfig.NamespaceId
+	// 优先使用用户维护的容灾文件
+	if failover, ok := cache.ReadConfigFailover(client.snapshotDir, param.DataId, param.Group, tenant); ok {
+		logger.Warnf("[client.GetConfig] use failover config, dataId:%s group:%s tenant:%s", param.DataId, param.Group, tenant)
+		return failover, false, nil
+	}
+	content, err = client.configProxy.GetConfigProxy(ctx, param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
 
 	if err != nil {
-		log.Printf("[ERROR] get config from server error:%s ", err.Error())
-		if _, ok := err.(*nacos_error.NacosError); ok {
-			nacosErr := err.(*nacos_error.NacosError)
-			if nacosErr.ErrorCode() == "404" {
-				cache.WriteConfigToFile(cacheKey, client.configCacheDir, "")
-				return "", errors.New("config not found")
-			}
-			if nacosErr.ErrorCode() == "403" {
-				return "", errors.New("get config forbidden")
-			}
+		logger.Errorf("get config from server error:%s ", err.Error())
+		if errors.Is(err, nacos_error.ErrNotFound) {
+			client.saveSnapshot(param.DataId, param.Group, tenant, "")
+			return "", false, nacos_error.NewNacosError(strconv.Itoa(http.StatusNotFound), "config not found", err)
+		}
+		if errors.Is(err, nacos_error.ErrForbidden) {
+			return "", false, nacos_error.NewNacosError(strconv.Itoa(http.StatusForbidden), "get config forbidden", err)
 		}
-		content, err = cache.ReadConfigFromFile(cacheKey, client.configCacheDir)
+		serverErr := err
+		content, err = client.readSnapshot(param.DataId, param.Group, tenant)
+		client.configProxy.nacosServer.Metrics().ObserveDiskCache("config", err == nil)
 		if err != nil {
-			log.Printf("[ERROR] get config from cache  error:%s ", err.Error())
-			return "", errors.New("read config from both server and cache fail")
+			logger.Errorf("get config from snapshot error:%s ", err.Error())
+			return "", false, nacos_error.Wrap("read config from both server and cache fail", serverErr)
 		}
+		return content, false, nil
+	}
+	return content, true, nil
+}
+
+func (client *ConfigClient) readSnapshot(dataId, group, tenant string) (string, error) {
+	if client.snapshotStore != nil {
+		return cache.ReadConfigSnapshotFromStore(client.snapshotStore, client.snapshotPrefix, dataId, group)
+	}
+	return cache.ReadConfigSnapshot(client.snapshotDir, dataId, group, tenant)
+}
 
+func (client *ConfigClient) saveSnapshot(dataId, group, tenant, content string) {
+	var err error
+	if client.snapshotStore != nil {
+		err = cache.WriteConfigSnapshotToStore(client.snapshotStore, client.snapshotPrefix, dataId, group, content)
 	} else {
-		cache.WriteConfigToFile(cacheKey, client.configCacheDir, content)
+		err = cache.WriteConfigSnapshot(client.snapshotDir, dataId, group, tenant, content)
+	}
+	if err != nil {
+		logger.Errorf("save config snapshot failed, dataId:%s group:%s tenant:%s err:%s", dataId, group, tenant, err.Error())
 	}
-	return content, nil
 }
 
 func (client *ConfigClient) PublishConfig(param vo.ConfigParam) (published bool,
 	err error) {
-	if len(param.DataId) <= 0 {
-		err = errors.New("[client.PublishConfig] param.dataId can not be empty")
-	}
-	if len(param.Group) <= 0 {
-		err = errors.New("[client.PublishConfig] param.group can not be empty")
+	return client.PublishConfigWithContext(context.Background(), param)
+}
+
+func (client *ConfigClient) PublishConfigWithContext(ctx context.Context, param vo.ConfigParam) (published bool,
+	err error) {
+	if err = validator.New("PublishConfig").DataId("dataId", param.DataId).Group("group", param.Group).
+		ContentWithLimit("content", param.Content, client.maxContentSize()).Err(); err != nil {
+		return false, err
 	}
-	if len(param.Content) <= 0 {
-		err = errors.New("[client.PublishConfig] param.content can not be empty")
+	if param.Content, err = client.encrypt(param.DataId, param.Content); err != nil {
+		return false, err
 	}
 	clientConfig, _ := client.GetClientConfig()
-	return client.configProxy.PublishConfigProxy(param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+	published, err = client.configProxy.PublishConfigProxy(ctx, param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+	client.invalidateReadCache(param.DataId, param.Group, published)
+	return published, err
 }
 
-func (client *ConfigClient) DeleteConfig(param vo.ConfigParam) (deleted bool,
+func (client *ConfigClient) maxContentSize() int {
+	clientConfig, _ := client.GetClientConfig()
+	if clientConfig.ConfigContent == nil {
+		return 0
+	}
+	return clientConfig.ConfigContent.MaxContentSize
+}
+
+// 服务端配置内容被其他客户端修改时发布失败
+var ErrConfigCasConflict = nacos_error.NewNacosError(strconv.Itoa(http.StatusConflict), "[client.PublishConfigCas] config has been modified by others", nil)
+
+// 仅当服务端当前内容的md5等于param.CasMd5时发布，否则返回ErrConfigCasConflict
+func (client *ConfigClient) PublishConfigCas(param vo.ConfigParam) (published bool,
 	err error) {
-	if len(param.DataId) <= 0 {
-		err = errors.New("[client.DeleteConfig] param.dataId can not be empty")
+	return client.PublishConfigCasWithContext(context.Background(), param)
+}
+
+func (client *ConfigClient) PublishConfigCasWithContext(ctx context.Context, param vo.ConfigParam) (published bool,
+	err error) {
+	if err = validator.New("PublishConfigCas").DataId("dataId", param.DataId).Group("group", param.Group).
+		ContentWithLimit("content", param.Content, client.maxContentSize()).Required("casMd5", param.CasMd5).Err(); err != nil {
+		return false, err
 	}
-	if len(param.Group) <= 0 {
-		err = errors.New("[client.DeleteConfig] param.group can not be empty")
+	// 加密的配置在服务端保存的是密文，CasMd5应为密文的md5
+	if param.Content, err = client.encrypt(param.DataId, param.Content); err != nil {
+		return false, err
 	}
+	clientConfig, _ := client.GetClientConfig()
+	published, err = client.configProxy.PublishConfigCasProxy(ctx, param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+	client.invalidateReadCache(param.DataId, param.Group, published)
+	return published, err
+}
+
+// 停止dataId和group对应配置的beta发布
+func (client *ConfigClient) StopBeta(param vo.ConfigParam) (bool, error) {
+	return client.StopBetaWithContext(context.Background(), param)
+}
 
+func (client *ConfigClient) StopBetaWithContext(ctx context.Context, param vo.ConfigParam) (bool, error) {
+	if err := validator.New("StopBeta").DataId("dataId", param.DataId).Group("group", param.Group).Err(); err != nil {
+		return false, err
+	}
 	clientConfig, _ := client.GetClientConfig()
-	return client.configProxy.DeleteConfigProxy(param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+	return client.configProxy.StopBetaProxy(ctx, param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+}
+
+func (client *ConfigClient) DeleteConfig(param vo.ConfigParam) (deleted bool,
+	err error) {
+	return client.DeleteConfigWithContext(context.Background(), param)
+}
+
+func (client *ConfigClient) DeleteConfigWithContext(ctx context.Context, param vo.ConfigParam) (deleted bool,
+	err error) {
+	if err = validator.New("DeleteConfig").DataId("dataId", param.DataId).Group("group", param.Group).Err(); err != nil {
+		return false, err
+	}
+	clientConfig, _ := client.GetClientConfig()
+	deleted, err = client.configProxy.DeleteConfigProxy(ctx, param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+	client.invalidateReadCache(param.DataId, param.Group, deleted)
+	return deleted, err
 }
 
 func (client *ConfigClient) AddConfigToListen(params []vo.ConfigParam) (err error) {
@@ -198,97 +466,43 @@ func (client *ConfigClient) AddConfigToListen(params []vo.ConfigParam) (err erro
 }
 
 func (client *ConfigClient) ListenConfig(param vo.ConfigParam) (err error) {
-	go func() {
-		for {
-			clientConfig, serverConfigs, agent, err := client.sync()
-			// 创建计时器
-			var timer *time.Timer
-			if err == nil {
-				timer = time.NewTimer(time.Duration(clientConfig.ListenInterval) * time.Millisecond)
-			}
-			client.listenConfigTask(clientConfig, serverConfigs, agent, param)
-			<-timer.C
-		}
-	}()
-
-	return nil
+	return client.ListenConfigWithContext(context.Background(), param)
 }
 
-func (client *ConfigClient) listenConfigTask(clientConfig constant.ClientConfig,
-	serverConfigs []constant.ServerConfig, agent http_agent.IHttpAgent, param vo.ConfigParam) {
-	var listeningConfigs string
-	// 检查&拼接监听参数
-	client.mutex.Lock()
-	if len(param.DataId) <= 0 {
-		log.Fatalf("[client.ListenConfig] DataId can not be empty")
-		return
-	}
-	if len(param.Group) <= 0 {
-		log.Fatalf("[client.ListenConfig] Group can not be empty")
-		return
-	}
-	var tenant string
-	if len(clientConfig.NamespaceId) > 0 {
-		tenant = clientConfig.NamespaceId
-	}
-
-	for _, config := range client.localConfigs {
-		if config.Group == param.Group && config.DataId == param.DataId {
-			param.Content = config.Content
-			break
-		}
-	}
-
-	var md5 string
-	if len(param.Content) > 0 {
-		md5 = util.Md5(param.Content)
+// 注册监听，ctx 结束后取消本次注册的回调
+func (client *ConfigClient) ListenConfigWithContext(ctx context.Context, param vo.ConfigParam) (err error) {
+	v := validator.New("ListenConfig").DataId("dataId", param.DataId).Group("group", param.Group)
+	if param.OnChange == nil && param.OnChangeEvent == nil {
+		v.Add("onChange", "can not be nil")
 	}
-	if len(tenant) > 0 {
-		listeningConfigs += param.DataId + constant.SPLIT_CONFIG_INNER + param.Group + constant.SPLIT_CONFIG_INNER +
-			md5 + constant.SPLIT_CONFIG_INNER + tenant + constant.SPLIT_CONFIG
-	} else {
-		listeningConfigs += param.DataId + constant.SPLIT_CONFIG_INNER + param.Group + constant.SPLIT_CONFIG_INNER +
-			md5 + constant.SPLIT_CONFIG
+	if err = v.Err(); err != nil {
+		return err
 	}
-
-	client.mutex.Unlock()
-	// http 请求
-	params := make(map[string]string)
-	params[constant.KEY_LISTEN_CONFIGS] = listeningConfigs
-	var changed string
-
-	for _, serverConfig := range client.configProxy.GetServerList() {
-		path := client.buildBasePath(serverConfig) + "/listener"
-		changedTmp, err := listen(agent, path, clientConfig.TimeoutMs, clientConfig.ListenInterval, params)
-		if err == nil {
-			changed = changedTmp
-			break
-		} else {
-			if _, ok := err.(*nacos_error.NacosError); ok {
-				changed = changedTmp
-				break
-			} else {
-				log.Println("[client.ListenConfig] listen config error:", err.Error())
+	id := client.addListener(param)
+	if ctx.Done() != nil {
+		go func() {
+			select {
+			case <-ctx.Done():
+				client.removeListener(param.DataId, param.Group, id)
+			case <-client.closeChan:
 			}
-		}
-	}
-
-	if strings.ToLower(strings.Trim(changed, " ")) == "" {
-		log.Println("[client.ListenConfig] no change")
-	} else {
-		log.Print("[client.ListenConfig] config changed:" + changed)
-		client.updateLocalConfig(changed, param)
+		}()
 	}
+	return nil
 }
 
+// extraHeaders为上报客户端身份等附加的请求头
 func listen(agent http_agent.IHttpAgent, path string,
 	timeoutMs uint64, listenInterval uint64,
-	params map[string]string) (changed string, err error) {
+	params map[string]string, extraHeaders map[string]string) (changed string, err error) {
 	header := map[string][]string{
 		"Content-Type":         {"application/x-www-form-urlencoded"},
 		"Long-Pulling-Timeout": {strconv.FormatUint(listenInterval, 10)},
 	}
-	log.Println("[client.ListenConfig] request url:", path, " ;params:", params, " ;header:", header)
+	for k, v := range extraHeaders {
+		header[k] = []string{v}
+	}
+	logger.Debugf("[client.ListenConfig] request url:%s ;params:%v ;header:%v", path, params, header)
 	var response *http.Response
 	response, err = agent.Post(path, header, timeoutMs, params)
 	if err == nil {
@@ -307,55 +521,6 @@ func listen(agent http_agent.IHttpAgent, path string,
 	return
 }
 
-func (client *ConfigClient) updateLocalConfig(changed string, param vo.ConfigParam) {
-	client.mutex.Lock()
-	defer client.mutex.Unlock()
-	changedConfigs := strings.Split(changed, "%01")
-	for _, config := range changedConfigs {
-		attrs := strings.Split(config, "%02")
-		if len(attrs) == 2 {
-			content, err := client.getConfigInner(vo.ConfigParam{
-				DataId: attrs[0],
-				Group:  attrs[1],
-			})
-			if err != nil {
-				log.Println("[client.updateLocalConfig] update config failed:", err.Error())
-			} else {
-				client.putLocalConfig(vo.ConfigParam{
-					DataId:  attrs[0],
-					Group:   attrs[1],
-					Content: content,
-				})
-
-				// call listener:
-				decrept, _ := client.decrypt(attrs[0], content)
-				param.OnChange("", attrs[1], attrs[0], decrept)
-
-			}
-		} else if len(attrs) == 3 {
-			content, err := client.getConfigInner(vo.ConfigParam{
-				DataId: attrs[0],
-				Group:  attrs[1],
-			})
-			if err != nil {
-				log.Println("[client.updateLocalConfig] update config failed:", err.Error())
-			} else {
-				client.putLocalConfig(vo.ConfigParam{
-					DataId:  attrs[0],
-					Group:   attrs[1],
-					Content: content,
-				})
-
-				// call listener:
-				decrept, _ := client.decrypt(attrs[0], content)
-				param.OnChange(attrs[2], attrs[1], attrs[0], decrept)
-			}
-		}
-	}
-	log.Println("[client.updateLocalConfig] update config complete")
-	log.Println("[client.localConfig] ", client.localConfigs)
-}
-
 func (client *ConfigClient) putLocalConfig(config vo.ConfigParam) {
 	if len(config.DataId) > 0 && len(config.Group) > 0 {
 		exist := false
@@ -373,11 +538,52 @@ func (client *ConfigClient) putLocalConfig(config vo.ConfigParam) {
 			client.localConfigs = append(client.localConfigs, config)
 		}
 	}
-	log.Println("[client.putLocalConfig] putLocalConfig success")
+	logger.Infof("[client.putLocalConfig] putLocalConfig success")
 }
 
 func (client *ConfigClient) buildBasePath(serverConfig constant.ServerConfig) (basePath string) {
-	basePath = "http://" + serverConfig.IpAddr + ":" +
+	clientConfig, _ := client.GetClientConfig()
+	basePath = server_list.GetScheme(serverConfig, clientConfig.TLSConfig.Enable) + "://" + serverConfig.IpAddr + ":" +
 		strconv.FormatUint(serverConfig.Port, 10) + serverConfig.ContextPath + constant.CONFIG_PATH
 	return
 }
+
+// 运行时更新客户端配置，已有的订阅和监听不受影响
+// 生效的有TimeoutMs、用户名密码、签名凭证、RetryPolicy、CircuitBreaker和RateLimit，其余字段需重新创建客户端
+func (client *ConfigClient) UpdateClientConfig(opts ...constant.ClientOption) error {
+	clientConfig, err := client.GetClientConfig()
+	if err != nil {
+		return err
+	}
+	for _, opt := range opts {
+		opt(&clientConfig)
+	}
+	if err = client.SetClientConfig(clientConfig); err != nil {
+		return err
+	}
+	clientConfig, _ = client.GetClientConfig()
+	client.configProxy.nacosServer.UpdateClientConfig(clientConfig)
+	return nil
+}
+
+// 运行时替换服务端列表，已有的订阅和监听不受影响
+func (client *ConfigClient) UpdateServerConfig(serverConfigs []constant.ServerConfig) error {
+	if len(serverConfigs) == 0 {
+		return errors.New("[client.UpdateServerConfig] server configs can not be empty")
+	}
+	if err := client.SetServerConfig(serverConfigs); err != nil {
+		return err
+	}
+	return client.configProxy.nacosServer.UpdateServerConfig(serverConfigs)
+}
+
+// 关闭客户端，停止所有配置监听
+func (client *ConfigClient) Close() error {
+	if client.closeOnce != nil {
+		client.closeOnce.Do(func() {
+			close(client.closeChan)
+			client.configProxy.nacosServer.Stop()
+		})
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package config_client
 
 import (
+	"context"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/model"
 	"github.com/nacos-group/nacos-sdk-go/vo"
 )
 
@@ -22,6 +25,12 @@ type IConfigClient interface {
 	// tenant ==>nacos.namespace optional
 	GetConfig(param vo.ConfigParam) (string, error)
 
+	// 获取配置并解析到v中，配置类型取param.Type，未指定时优先使用服务端返回的类型，其次根据dataId的扩展名推断
+	// 内置json、properties、yaml和toml，其他类型通过codec.Register注册
+	// dataId  require
+	// group   require
+	GetConfigAs(param vo.ConfigParam, v interface{}) error
+
 	// 发布配置
 	// dataId  require
 	// group   require
@@ -29,6 +38,18 @@ type IConfigClient interface {
 	// tenant ==>nacos.namespace optional
 	PublishConfig(param vo.ConfigParam) (bool, error)
 
+	// 发布配置，仅当服务端当前内容的md5等于casMd5时写入，否则返回ErrConfigCasConflict
+	// dataId  require
+	// group   require
+	// content require
+	// casMd5  require
+	PublishConfigCas(param vo.ConfigParam) (bool, error)
+
+	// 停止beta发布
+	// dataId  require
+	// group   require
+	StopBeta(param vo.ConfigParam) (bool, error)
+
 	// 删除配置
 	// dataId  require
 	// group   require
@@ -40,4 +61,81 @@ type IConfigClient interface {
 	// group   require
 	// tenant ==>nacos.namespace optional
 	ListenConfig(params vo.ConfigParam) (err error)
+
+	// 取消监听配置，dataId和group对应的所有回调都不再通知
+	// dataId  require
+	// group   require
+	CancelListenConfig(params vo.ConfigParam) (err error)
+
+	// 监听配置，配置变化时将新内容解析到newValue返回的对象中并回调onChange
+	// dataId  require
+	// group   require
+	ListenConfigAs(param vo.ConfigParam, newValue func() interface{}, onChange func(value interface{}, err error)) error
+
+	// 分页搜索配置，Search为blur时dataId和group支持*通配符
+	SearchConfig(param vo.SearchConfigParam) (*model.ConfigPage, error)
+
+	// 监听group下dataId以DataIdPrefix开头的所有配置，之后新创建的匹配配置也会被监听
+	// group         require
+	// dataIdPrefix  require
+	ListenConfigWithPrefix(param vo.ConfigPrefixParam) error
+
+	// 监听分组下的所有配置，之后新创建的配置也会被监听
+	// group          require
+	// onChangeEvent  require
+	ListenConfigGroup(param vo.ConfigGroupParam) error
+
+	// 分页获取配置的修改历史
+	// dataId  require
+	// group   require
+	GetConfigHistory(param vo.ConfigHistoryParam) (*model.ConfigHistoryPage, error)
+
+	// 获取id对应历史的上一条历史
+	// id      require
+	// dataId  require
+	// group   require
+	GetPreviousConfig(param vo.ConfigHistoryDetailParam) (*model.ConfigHistory, error)
+
+	// 将配置回滚到id对应操作之前的状态
+	// id      require
+	// dataId  require
+	// group   require
+	RollbackConfig(param vo.ConfigHistoryDetailParam) (bool, error)
+
+	// 按与控制台一致的格式将配置导出为zip
+	// namespaceId为空时导出客户端所在命名空间，group、appName、dataId optional
+	ExportConfigs(param vo.ExportConfigParam) ([]byte, error)
+
+	// 导入ExportConfigs或控制台导出的zip
+	// content require
+	// policy  optional，默认ABORT
+	ImportConfigs(param vo.ImportConfigParam) (*model.ConfigImportResult, error)
+
+	// 以下方法与上面的同名方法一致，可通过ctx取消请求或设置超时
+	GetConfigWithContext(ctx context.Context, param vo.ConfigParam) (string, error)
+	GetConfigAsWithContext(ctx context.Context, param vo.ConfigParam, v interface{}) error
+	PublishConfigWithContext(ctx context.Context, param vo.ConfigParam) (bool, error)
+	PublishConfigCasWithContext(ctx context.Context, param vo.ConfigParam) (bool, error)
+	StopBetaWithContext(ctx context.Context, param vo.ConfigParam) (bool, error)
+	DeleteConfigWithContext(ctx context.Context, param vo.ConfigParam) (bool, error)
+	ListenConfigWithContext(ctx context.Context, params vo.ConfigParam) (err error)
+	SearchConfigWithContext(ctx context.Context, param vo.SearchConfigParam) (*model.ConfigPage, error)
+	ListenConfigWithPrefixWithContext(ctx context.Context, param vo.ConfigPrefixParam) error
+	ListenConfigGroupWithContext(ctx context.Context, param vo.ConfigGroupParam) error
+	GetConfigHistoryWithContext(ctx context.Context, param vo.ConfigHistoryParam) (*model.ConfigHistoryPage, error)
+	GetPreviousConfigWithContext(ctx context.Context, param vo.ConfigHistoryDetailParam) (*model.ConfigHistory, error)
+	RollbackConfigWithContext(ctx context.Context, param vo.ConfigHistoryDetailParam) (bool, error)
+	ExportConfigsWithContext(ctx context.Context, param vo.ExportConfigParam) ([]byte, error)
+	ImportConfigsWithContext(ctx context.Context, param vo.ImportConfigParam) (*model.ConfigImportResult, error)
+
+	// 在后台获取和发布配置，完成后向返回的channel写入一个结果
+	GetConfigAsync(ctx context.Context, param vo.ConfigParam) <-chan model.ConfigResult
+	PublishConfigAsync(ctx context.Context, param vo.ConfigParam) <-chan model.AsyncResult
+
+	// 运行时更新客户端配置和服务端列表，无需重新创建客户端
+	UpdateClientConfig(opts ...constant.ClientOption) error
+	UpdateServerConfig(serverConfigs []constant.ServerConfig) error
+
+	// 关闭客户端，停止所有配置监听，见nacos_client.CloseableClient
+	Close() error
 }
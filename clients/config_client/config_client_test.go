@@ -1,15 +1,31 @@
 package config_client
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/golang/mock/gomock"
+	"github.com/nacos-group/nacos-sdk-go/clients/cache"
 	"github.com/nacos-group/nacos-sdk-go/clients/nacos_client"
+	"github.com/nacos-group/nacos-sdk-go/common/audit"
 	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/encryption"
+	"github.com/nacos-group/nacos-sdk-go/common/event"
 	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/common/util"
+	"github.com/nacos-group/nacos-sdk-go/common/validator"
 	"github.com/nacos-group/nacos-sdk-go/mock"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/utils"
 	"github.com/nacos-group/nacos-sdk-go/vo"
 	"github.com/stretchr/testify/assert"
+	"io/ioutil"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -65,7 +81,7 @@ var serverConfigsTest = []constant.ServerConfig{serverConfigTest}
 
 var httpAgentTest = mock.MockIHttpAgent{}
 
-func cretateConfigClientTest() ConfigClient {
+func cretateConfigClientTest() *ConfigClient {
 	nc := nacos_client.NacosClient{}
 	nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
 	nc.SetClientConfig(clientConfigTest)
@@ -74,7 +90,7 @@ func cretateConfigClientTest() ConfigClient {
 	return client
 }
 
-func cretateConfigClientHttpTest(mockHttpAgent http_agent.IHttpAgent) ConfigClient {
+func cretateConfigClientHttpTest(mockHttpAgent http_agent.IHttpAgent) *ConfigClient {
 	nc := nacos_client.NacosClient{}
 	nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
 	nc.SetClientConfig(clientConfigTest)
@@ -139,7 +155,7 @@ func Test_GetConfigWithErrorResponse_401(t *testing.T) {
 	defer controller.Finish()
 	mockHttpAgent := mock.NewMockIHttpAgent(controller)
 	client := cretateConfigClientHttpTest(mockHttpAgent)
-	mockHttpAgent.EXPECT().Request(gomock.Eq(http.MethodGet),
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(clientConfigTest.TimeoutMs),
@@ -155,7 +171,7 @@ func Test_GetConfigWithErrorResponse_404(t *testing.T) {
 	defer controller.Finish()
 	mockHttpAgent := mock.NewMockIHttpAgent(controller)
 	client := cretateConfigClientHttpTest(mockHttpAgent)
-	mockHttpAgent.EXPECT().Request(gomock.Eq(http.MethodGet),
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(clientConfigTest.TimeoutMs),
@@ -172,7 +188,7 @@ func Test_GetConfigWithErrorResponse_403(t *testing.T) {
 	defer controller.Finish()
 	mockHttpAgent := mock.NewMockIHttpAgent(controller)
 	client := cretateConfigClientHttpTest(mockHttpAgent)
-	mockHttpAgent.EXPECT().Request(gomock.Eq(http.MethodGet),
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(clientConfigTest.TimeoutMs),
@@ -191,7 +207,7 @@ func Test_GetConfigWithCache(t *testing.T) {
 	mockHttpAgent := mock.NewMockIHttpAgent(controller)
 	client := cretateConfigClientHttpTest(mockHttpAgent)
 
-	mockHttpAgent.EXPECT().Request(gomock.Eq(http.MethodGet),
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(clientConfigTest.TimeoutMs),
@@ -201,7 +217,7 @@ func Test_GetConfigWithCache(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, "content", content)
 
-	mockHttpAgent.EXPECT().Request(gomock.Eq(http.MethodGet),
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(clientConfigTest.TimeoutMs),
@@ -212,6 +228,30 @@ func Test_GetConfigWithCache(t *testing.T) {
 	assert.Equal(t, "content", content)
 }
 
+func Test_GetConfigWithFailover(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	client := createConfigClientTest(t, mockHttpAgent)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(1).Return(http_agent.FakeHttpResponse(200, "content"), nil)
+	content, err := client.GetConfig(configParamTest)
+	assert.Nil(t, err)
+	assert.Equal(t, "content", content)
+	snapshot, _ := cache.ReadConfigSnapshot(client.snapshotDir, "dataId", "group", "")
+	assert.Equal(t, "content", snapshot)
+
+	failoverFile := cache.GetConfigFailoverFile(client.snapshotDir, "dataId", "group", "")
+	os.MkdirAll(filepath.Dir(failoverFile), os.ModePerm)
+	ioutil.WriteFile(failoverFile, []byte("failover"), 0666)
+	content, err = client.GetConfig(configParamTest)
+	assert.Nil(t, err)
+	assert.Equal(t, "failover", content)
+}
+
 // PublishConfig
 
 func Test_PublishConfigWithoutDataId(t *testing.T) {
@@ -257,13 +297,263 @@ func Test_PublishConfig(t *testing.T) {
 	assert.True(t, success)
 }
 
+func Test_PublishConfigWithAttributes(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	client := createConfigClientTest(t, mockHttpAgent)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"), gomock.Any(), gomock.Any(),
+		gomock.Eq(map[string]string{"dataId": "dataId", "group": "group", "content": "a: b", "appName": "app",
+			"type": "yaml", "config_tags": "t1,t2", "desc": "description"})).Times(1).
+		Return(http_agent.FakeHttpResponse(200, "true"), nil)
+
+	success, err := client.PublishConfig(vo.ConfigParam{
+		DataId:     "dataId",
+		Group:      "group",
+		Content:    "a: b",
+		AppName:    "app",
+		Type:       "yaml",
+		ConfigTags: "t1,t2",
+		Desc:       "description"})
+	assert.Nil(t, err)
+	assert.True(t, success)
+}
+
+func Test_SearchConfigWithNamespace(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	client := createConfigClientTest(t, mockHttpAgent)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"), gomock.Any(), gomock.Any(),
+		gomock.Eq(map[string]string{"search": "blur", "dataId": "*", "group": "group", "config_tags": "t1",
+			"appName": "app", "pageNo": "1", "pageSize": "10", "tenant": "dev"})).Times(1).
+		Return(http_agent.FakeHttpResponse(200, `{"totalCount":1,"pageNumber":1,"pagesAvailable":1,"pageItems":[`+
+			`{"dataId":"dataId","group":"group","tenant":"dev","appName":"app","type":"yaml"}]}`), nil)
+
+	page, err := client.SearchConfig(vo.SearchConfigParam{
+		DataId:      "*",
+		Group:       "group",
+		Tag:         "t1",
+		AppName:     "app",
+		PageNo:      1,
+		PageSize:    10,
+		NamespaceId: "dev"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, page.TotalCount)
+	assert.Equal(t, "dev", page.PageItems[0].Tenant)
+	assert.Equal(t, "app", page.PageItems[0].AppName)
+}
+
+func Test_GetAndPublishConfigAsync(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	client := createConfigClientTest(t, mockHttpAgent)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		Return(http_agent.FakeHttpResponse(200, "true"), nil)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		Return(http_agent.FakeHttpResponse(200, "content"), nil)
+
+	published := <-client.PublishConfigAsync(context.Background(), vo.ConfigParam{DataId: "dataId", Group: "group", Content: "content"})
+	assert.Nil(t, published.Err)
+	assert.True(t, published.Success)
+	result := <-client.GetConfigAsync(context.Background(), vo.ConfigParam{DataId: "dataId", Group: "group"})
+	assert.Nil(t, result.Err)
+	assert.Equal(t, "content", result.Content)
+
+	result = <-client.GetConfigAsync(context.Background(), vo.ConfigParam{Group: "group"})
+	assert.NotNil(t, result.Err)
+}
+
+func Test_PublishConfigCas(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	client := createConfigClientTest(t, mockHttpAgent)
+	gomock.InOrder(
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+			gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+			gomock.Any(), gomock.Any(), gomock.Any(),
+		).Times(1).DoAndReturn(func(ctx, method, path interface{}, header http.Header, timeoutMs, params interface{}) (*http.Response, error) {
+			assert.Equal(t, []string{util.Md5("old")}, header["casMd5"])
+			return http_agent.FakeHttpResponse(200, "true"), nil
+		}),
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+			gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+			gomock.Any(), gomock.Any(), gomock.Any(),
+		).Times(1).Return(http_agent.FakeHttpResponse(200, "false"), nil),
+		// 服务端以错误响应报告冲突时不重试
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+			gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+			gomock.Any(), gomock.Any(), gomock.Any(),
+		).Times(1).Return(http_agent.FakeHttpResponse(409, "config has been modified"), nil),
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+			gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+			gomock.Any(), gomock.Any(), gomock.Any(),
+		).Times(1).Return(http_agent.FakeHttpResponse(500,
+			`{"code":500,"message":"RESOURCE_CONFLICT","data":"Cas publish fail, server md5 may have changed."}`), nil),
+	)
+	param := vo.ConfigParam{DataId: "dataId", Group: "group", Content: "new", CasMd5: util.Md5("old")}
+	success, err := client.PublishConfigCas(param)
+	assert.Nil(t, err)
+	assert.True(t, success)
+
+	for i := 0; i < 3; i++ {
+		success, err = client.PublishConfigCas(param)
+		assert.Equal(t, ErrConfigCasConflict, err)
+		assert.False(t, success)
+	}
+
+	_, err = client.PublishConfigCas(vo.ConfigParam{DataId: "dataId", Group: "group", Content: "new"})
+	assert.NotNil(t, err)
+}
+
+func Test_BetaConfig(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	client := createConfigClientTest(t, mockHttpAgent)
+	gomock.InOrder(
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+			gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+			gomock.Any(), gomock.Any(), gomock.Any(),
+		).Times(1).DoAndReturn(func(ctx, method, path interface{}, header http.Header, timeoutMs, params interface{}) (*http.Response, error) {
+			assert.Equal(t, []string{"10.0.0.10,10.0.0.11"}, header["betaIps"])
+			return http_agent.FakeHttpResponse(200, "true"), nil
+		}),
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+			gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+			gomock.Any(), gomock.Any(), gomock.Eq(map[string]string{"dataId": "dataId", "group": "group", "beta": "true"}),
+		).Times(1).Return(http_agent.FakeHttpResponse(200, `{"code":200,"data":{"dataId":"dataId","group":"group","content":"beta","betaIps":"10.0.0.10,10.0.0.11"}}`), nil),
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodDelete),
+			gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+			gomock.Any(), gomock.Any(), gomock.Eq(map[string]string{"dataId": "dataId", "group": "group", "beta": "true"}),
+		).Times(1).Return(http_agent.FakeHttpResponse(200, `{"code":200,"message":"stop beta ok","data":true}`), nil),
+	)
+	success, err := client.PublishConfig(vo.ConfigParam{DataId: "dataId", Group: "group", Content: "beta", BetaIps: "10.0.0.10,10.0.0.11"})
+	assert.Nil(t, err)
+	assert.True(t, success)
+
+	content, err := client.GetConfig(vo.ConfigParam{DataId: "dataId", Group: "group", Beta: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "beta", content)
+
+	success, err = client.StopBeta(vo.ConfigParam{DataId: "dataId", Group: "group"})
+	assert.Nil(t, err)
+	assert.True(t, success)
+}
+
+func Test_GetConfigAs(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	client := createConfigClientTest(t, mockHttpAgent)
+	gomock.InOrder(
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+			gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+			gomock.Any(), gomock.Any(), gomock.Any(),
+		).Times(1).Return(http_agent.FakeHttpResponse(200, `{"name":"demo","port":8080}`), nil),
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+			gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+			gomock.Any(), gomock.Any(), gomock.Any(),
+		).Times(1).Return(http_agent.FakeHttpResponse(200, "name=demo\nport=8080"), nil),
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+			gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+			gomock.Any(), gomock.Any(), gomock.Any(),
+		).Times(1).Return(configTypeResponse("name: demo\nport: 8080", "yaml"), nil),
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+			gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+			gomock.Any(), gomock.Any(), gomock.Any(),
+		).Times(1).Return(configTypeResponse("name=demo", "text"), nil),
+	)
+	type appConfig struct {
+		Name string `json:"name" properties:"name"`
+		Port int    `json:"port" properties:"port"`
+	}
+
+	var jsonConfig appConfig
+	err := client.GetConfigAs(vo.ConfigParam{DataId: "app.json", Group: "group"}, &jsonConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, appConfig{Name: "demo", Port: 8080}, jsonConfig)
+
+	var propertiesConfig appConfig
+	err = client.GetConfigAs(vo.ConfigParam{DataId: "app", Group: "group", Type: "properties"}, &propertiesConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, appConfig{Name: "demo", Port: 8080}, propertiesConfig)
+
+	// 未指定类型时使用服务端返回的类型
+	var yamlConfig appConfig
+	err = client.GetConfigAs(vo.ConfigParam{DataId: "app", Group: "group"}, &yamlConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, appConfig{Name: "demo", Port: 8080}, yamlConfig)
+
+	// 服务端返回text且无法根据扩展名推断时返回错误
+	err = client.GetConfigAs(vo.ConfigParam{DataId: "app", Group: "group"}, &propertiesConfig)
+	assert.NotNil(t, err)
+	err = client.GetConfigAs(vo.ConfigParam{DataId: "app", Group: "group", Type: "xml"}, &propertiesConfig)
+	assert.NotNil(t, err)
+}
+
+func configTypeResponse(content, configType string) *http.Response {
+	response := http_agent.FakeHttpResponse(200, content)
+	response.Header.Set(constant.CONFIG_TYPE_HEADER, configType)
+	return response
+}
+
+func Test_EncryptedConfig(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	secretKey, _ := encryption.GenerateAesSecretKey()
+	plugin, _ := encryption.NewAesPlugin(secretKey)
+	encryption.Register("cipher-test-", plugin)
+	defer encryption.Unregister("cipher-test-")
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	client := createConfigClientTest(t, mockHttpAgent)
+	var ciphertext string
+	gomock.InOrder(
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+			gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+			gomock.Any(), gomock.Any(), gomock.Any(),
+		).Times(1).DoAndReturn(func(ctx, method, path interface{}, header http.Header, timeoutMs interface{}, params map[string]string) (*http.Response, error) {
+			ciphertext = params["content"]
+			return http_agent.FakeHttpResponse(200, "true"), nil
+		}),
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+			gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+			gomock.Any(), gomock.Any(), gomock.Any(),
+		).Times(1).DoAndReturn(func(ctx, method, path, header, timeoutMs, params interface{}) (*http.Response, error) {
+			return http_agent.FakeHttpResponse(200, ciphertext), nil
+		}),
+	)
+	success, err := client.PublishConfig(vo.ConfigParam{DataId: "cipher-test-app", Group: "group", Content: "password=123"})
+	assert.Nil(t, err)
+	assert.True(t, success)
+	assert.NotEqual(t, "password=123", ciphertext)
+
+	content, err := client.GetConfig(vo.ConfigParam{DataId: "cipher-test-app", Group: "group"})
+	assert.Nil(t, err)
+	assert.Equal(t, "password=123", content)
+}
+
 func Test_PublishConfigWithErrorResponse(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
 
 	mockHttpAgent := mock.NewMockIHttpAgent(controller)
 	client := cretateConfigClientHttpTest(mockHttpAgent)
-	mockHttpAgent.EXPECT().Request(gomock.Eq(http.MethodPost),
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(clientConfigTest.TimeoutMs),
@@ -280,7 +570,7 @@ func Test_PublishConfigWithErrorResponse_200(t *testing.T) {
 
 	mockHttpAgent := mock.NewMockIHttpAgent(controller)
 	client := cretateConfigClientHttpTest(mockHttpAgent)
-	mockHttpAgent.EXPECT().Request(gomock.Eq(http.MethodPost),
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(clientConfigTest.TimeoutMs),
@@ -320,7 +610,7 @@ func Test_DeleteConfigWithErrorResponse_200(t *testing.T) {
 	mockHttpAgent := mock.NewMockIHttpAgent(controller)
 	client := cretateConfigClientHttpTest(mockHttpAgent)
 
-	mockHttpAgent.EXPECT().Request(gomock.Eq(http.MethodDelete),
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodDelete),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(clientConfigTest.TimeoutMs),
@@ -338,7 +628,7 @@ func Test_DeleteConfigWithErrorResponse_401(t *testing.T) {
 	mockHttpAgent := mock.NewMockIHttpAgent(controller)
 	client := cretateConfigClientHttpTest(mockHttpAgent)
 
-	mockHttpAgent.EXPECT().Request(gomock.Eq(http.MethodDelete),
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodDelete),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(clientConfigTest.TimeoutMs),
@@ -418,127 +708,523 @@ func TestListenConfig(t *testing.T) {
 	assert.Equal(t, "abc", content)
 }
 
-// listenConfigTask
+// listenConfigBatch
+
+var listenClientConfigTest = constant.ClientConfig{
+	TimeoutMs:      10 * 1000,
+	ListenInterval: 30 * 1000,
+}
+
+// 创建使用mock http agent的客户端，缓存目录为测试结束后删除的临时目录，options用于调整客户端配置
+func createConfigClientTest(t *testing.T, mockHttpAgent http_agent.IHttpAgent, options ...func(clientConfig *constant.ClientConfig)) *ConfigClient {
+	cacheDir, err := ioutil.TempDir("", "nacos-config")
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		os.RemoveAll(cacheDir)
+	})
+	nc := nacos_client.NacosClient{}
+	nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	clientConfig := listenClientConfigTest
+	clientConfig.CacheDir = cacheDir
+	for _, option := range options {
+		option(&clientConfig)
+	}
+	nc.SetClientConfig(clientConfig)
+	nc.SetHttpAgent(mockHttpAgent)
+	client, err := NewConfigClient(&nc)
+	assert.Nil(t, err)
+	return client
+}
+
+func newCacheDataTest(tenant string, content string, onChange listenerFunc) *cacheData {
+	return &cacheData{
+		dataId:    "dataId",
+		group:     "group",
+		tenant:    tenant,
+		md5:       util.Md5(content),
+		listeners: map[int64]listenerFunc{1: onChange},
+	}
+}
 
-func Test_listenConfigTask_NoChange(t *testing.T) {
+func Test_listenConfigBatch_NoChange(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
 
 	mockHttpAgent := mock.NewMockIHttpAgent(controller)
-	client := cretateConfigClientHttpTest(mockHttpAgent)
+	client := createConfigClientTest(t, mockHttpAgent)
 	mockHttpAgent.EXPECT().Post(
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs/listener"),
 		gomock.AssignableToTypeOf(headerTest),
-		gomock.Eq(clientConfigTest.TimeoutMs),
+		gomock.Eq(listenClientConfigTest.TimeoutMs),
 		gomock.Eq(map[string]string{
-			"Listening-Configs": "dataIdgroup9a0364b9e99bb480dd25e1f0284c8555tenant",
+			"Listening-Configs": "dataId" + constant.SPLIT_CONFIG_INNER + "group" + constant.SPLIT_CONFIG_INNER +
+				"9a0364b9e99bb480dd25e1f0284c8555" + constant.SPLIT_CONFIG_INNER + "tenant" + constant.SPLIT_CONFIG,
 		}),
 	).Times(1).Return(http_agent.FakeHttpResponse(200, ""), nil)
 
 	changeCount := 0
-	client.listenConfigTask(clientConfigTest, serverConfigsTest, mockHttpAgent, vo.ConfigParam{
-		DataId:  "dataId",
-		Group:   "group",
-		Content: "content",
-		OnChange: func(namespace, group, dataId, data string) {
+	err := client.listenConfigBatch(listenClientConfigTest, mockHttpAgent, []*cacheData{
+		newCacheDataTest("tenant", "content", func(namespace, group, dataId, data string) {
 			changeCount = changeCount + 1
-		}})
+		})})
 
-	assert.Equal(t, 0, changeCount)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(client.listener.notifyChan))
 }
 
-func Test_listenConfigTask_Change_WithTenant(t *testing.T) {
+func Test_listenConfigBatch_Change_WithTenant(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
-	client := cretateConfigClientTest()
 	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	client := createConfigClientTest(t, mockHttpAgent)
 	mockHttpAgent.EXPECT().Post(
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs/listener"),
 		gomock.AssignableToTypeOf(headerTest),
-		gomock.Eq(clientConfigTest.TimeoutMs),
+		gomock.Eq(listenClientConfigTest.TimeoutMs),
 		gomock.Eq(map[string]string{
-			"Listening-Configs": "dataIdgroup9a0364b9e99bb480dd25e1f0284c8555tenant",
+			"Listening-Configs": "dataId" + constant.SPLIT_CONFIG_INNER + "group" + constant.SPLIT_CONFIG_INNER +
+				"9a0364b9e99bb480dd25e1f0284c8555" + constant.SPLIT_CONFIG_INNER + "tenant" + constant.SPLIT_CONFIG,
 		}),
 	).Times(1).Return(http_agent.FakeHttpResponse(200, "dataId%02group%02tenant%01"), nil)
 
-	mockHttpAgent.EXPECT().Get(
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
-		gomock.AssignableToTypeOf(headerTest),
-		gomock.Eq(clientConfigTest.TimeoutMs),
-		gomock.Eq(map[string]string{
-			"dataId": "dataId",
-			"group":  "group",
-			"tenant": "tenant",
-		}),
+		gomock.Any(), gomock.Any(), gomock.Any(),
 	).Times(1).Return(http_agent.FakeHttpResponse(200, "content2"), nil)
 
-	_ = client.SetHttpAgent(mockHttpAgent)
-	_ = client.SetClientConfig(clientConfigTest)
-	_ = client.SetServerConfig(serverConfigsTest)
-	configData := ""
-	client.listenConfigTask(clientConfigTest, serverConfigsTest, mockHttpAgent, vo.ConfigParam{
-		DataId:  "dataId",
-		Group:   "group",
-		Content: "content",
-		OnChange: func(namespace, group, dataId, data string) {
+	var namespace, configData string
+	err := client.listenConfigBatch(listenClientConfigTest, mockHttpAgent, []*cacheData{
+		newCacheDataTest("tenant", "content", func(ns, group, dataId, data string) {
+			namespace = ns
 			configData = data
-		}})
-
+		})})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(client.listener.notifyChan))
+	(<-client.listener.notifyChan)()
+	assert.Equal(t, "tenant", namespace)
 	assert.Equal(t, "content2", configData)
 }
 
-func Test_listenConfigTask_Change_NoTenant(t *testing.T) {
+func Test_listenConfigBatch_WithAuth(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
-	client := cretateConfigClientTest()
 	mockHttpAgent := mock.NewMockIHttpAgent(controller)
-	mockHttpAgent.EXPECT().Post(
-		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs/listener"),
-		gomock.AssignableToTypeOf(headerTest),
-		gomock.Eq(clientConfigTest.TimeoutMs),
-		gomock.Eq(map[string]string{
-			"Listening-Configs": "dataIdgroup9a0364b9e99bb480dd25e1f0284c8555",
-		}),
-	).Times(1).Return(http_agent.FakeHttpResponse(200, "dataId%02group%01"), nil)
+	mockHttpAgent.EXPECT().Post(gomock.Eq("http://console.nacos.io:80/nacos"+constant.AUTH_LOGIN_PATH),
+		gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(http_agent.FakeHttpResponse(200, `{"accessToken":"token","tokenTtl":18000}`), nil).AnyTimes()
 
-	mockHttpAgent.EXPECT().Get(
-		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
-		gomock.AssignableToTypeOf(headerTest),
-		gomock.Eq(clientConfigTest.TimeoutMs),
-		gomock.Eq(map[string]string{
-			"dataId": "dataId",
-			"group":  "group",
+	cacheDir, _ := ioutil.TempDir("", "nacos-config")
+	defer os.RemoveAll(cacheDir)
+	nc := nacos_client.NacosClient{}
+	nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	clientConfig := listenClientConfigTest
+	clientConfig.CacheDir = cacheDir
+	clientConfig.Username = "nacos"
+	clientConfig.Password = "nacos"
+	clientConfig.AccessKey = "ak"
+	clientConfig.SecretKey = "sk"
+	nc.SetClientConfig(clientConfig)
+	nc.SetHttpAgent(mockHttpAgent)
+	client, err := NewConfigClient(&nc)
+	assert.Nil(t, err)
+	defer client.Close()
+
+	mockHttpAgent.EXPECT().Post(gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs/listener"),
+		gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(url string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			assert.Equal(t, "token", params["accessToken"])
+			assert.NotEmpty(t, params[constant.KEY_LISTEN_CONFIGS])
+			assert.Equal(t, []string{"ak"}, header["Spas-AccessKey"])
+			assert.NotEmpty(t, header["Spas-Signature"])
+			assert.NotEmpty(t, header["Timestamp"])
+			return http_agent.FakeHttpResponse(200, ""), nil
+		}).Times(1)
+
+	assert.Nil(t, client.listenConfigBatch(clientConfig, mockHttpAgent, []*cacheData{
+		newCacheDataTest("tenant", "content", func(namespace, group, dataId, data string) {})}))
+}
+
+func Test_listenConfigBatch_ChangeWithSameMd5(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	client := createConfigClientTest(t, mockHttpAgent)
+	mockHttpAgent.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(1).Return(http_agent.FakeHttpResponse(200, "dataId%02group%01"), nil)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(1).Return(http_agent.FakeHttpResponse(200, "content"), nil)
+
+	err := client.listenConfigBatch(listenClientConfigTest, mockHttpAgent, []*cacheData{
+		newCacheDataTest("", "content", func(namespace, group, dataId, data string) {})})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(client.listener.notifyChan), "listener should not be notified when md5 is unchanged")
+}
+
+func Test_listenConfigBatch_Validate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	client := createConfigClientTest(t, mockHttpAgent)
+	var rejected []event.ConfigRejectedEvent
+	client.configProxy.nacosServer.Events().Subscribe(func(e event.Event) {
+		rejected = append(rejected, e.(event.ConfigRejectedEvent))
+	}, event.TYPE_CONFIG_REJECTED)
+	mockHttpAgent.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(3).Return(http_agent.FakeHttpResponse(200, "dataId%02group%01"), nil)
+	gomock.InOrder(
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(2).Return(http_agent.FakeHttpResponse(200, "bad"), nil),
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(http_agent.FakeHttpResponse(200, "good"), nil),
+	)
+
+	var received []string
+	cd := newCacheDataTest("", "content", func(namespace, group, dataId, data string) {
+		received = append(received, data)
+	})
+	cd.data = "content"
+	cd.validators = map[int64]func(content string) error{1: func(content string) error {
+		if content == "bad" {
+			return errors.New("invalid content")
+		}
+		return nil
+	}}
+	client.listener.cacheMap.Store(utils.GetConfigCacheKey("dataId", "group", ""), cd)
+
+	// 未通过校验时不通知，GetConfig返回上次校验通过的内容，长轮询上报被拒绝内容的md5
+	assert.Nil(t, client.listenConfigBatch(listenClientConfigTest, mockHttpAgent, []*cacheData{cd}))
+	assert.Equal(t, 0, len(client.listener.notifyChan))
+	assert.Equal(t, 1, len(rejected))
+	assert.Equal(t, util.Md5("bad"), rejected[0].Md5)
+	content, err := client.GetConfig(vo.ConfigParam{DataId: "dataId", Group: "group"})
+	assert.Nil(t, err)
+	assert.Equal(t, "content", content)
+	assert.Contains(t, cd.listeningConfig(), util.Md5("bad"))
+
+	// 同一内容不重复校验，再次变化后重新校验
+	assert.Nil(t, client.listenConfigBatch(listenClientConfigTest, mockHttpAgent, []*cacheData{cd}))
+	assert.Equal(t, 1, len(rejected))
+	assert.Nil(t, client.listenConfigBatch(listenClientConfigTest, mockHttpAgent, []*cacheData{cd}))
+	assert.Equal(t, 1, len(client.listener.notifyChan))
+	(<-client.listener.notifyChan)()
+	assert.Equal(t, []string{"good"}, received)
+	_, ok := client.validatedConfig("dataId", "group")
+	assert.False(t, ok)
+}
+
+func Test_listenConfigBatch_RejectedNotSnapshotted(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	client := createConfigClientTest(t, mockHttpAgent)
+	mockHttpAgent.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(2).Return(http_agent.FakeHttpResponse(200, "dataId%02group%01"), nil)
+	gomock.InOrder(
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(http_agent.FakeHttpResponse(200, "good"), nil),
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(http_agent.FakeHttpResponse(200, "bad"), nil),
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			AnyTimes().Return(nil, errors.New("connection refused")),
+	)
+
+	cd := newCacheDataTest("", "content", func(namespace, group, dataId, data string) {})
+	cd.validators = map[int64]func(content string) error{1: func(content string) error {
+		if content == "bad" {
+			return errors.New("invalid content")
+		}
+		return nil
+	}}
+	client.listener.cacheMap.Store(utils.GetConfigCacheKey("dataId", "group", ""), cd)
+	assert.Nil(t, client.listenConfigBatch(listenClientConfigTest, mockHttpAgent, []*cacheData{cd}))
+	assert.Nil(t, client.listenConfigBatch(listenClientConfigTest, mockHttpAgent, []*cacheData{cd}))
+	assert.Equal(t, util.Md5("bad"), cd.getRejectedMd5())
+
+	// 服务端不可用时从快照读取，快照中为上次校验通过的内容
+	assert.Nil(t, client.CancelListenConfig(vo.ConfigParam{DataId: "dataId", Group: "group"}))
+	content, err := client.GetConfig(vo.ConfigParam{DataId: "dataId", Group: "group"})
+	assert.Nil(t, err)
+	assert.Equal(t, "good", content)
+}
+
+func Test_listenConfigBatch_ChangeEvent(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	client := createConfigClientTest(t, mockHttpAgent)
+	mockHttpAgent.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(2).Return(http_agent.FakeHttpResponse(200, "dataId%02group%01"), nil)
+	gomock.InOrder(
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(http_agent.FakeHttpResponse(200, "content2"), nil),
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			MinTimes(1).DoAndReturn(func(...interface{}) (*http.Response, error) {
+			return http_agent.FakeHttpResponse(404, "config data not exist"), nil
 		}),
-	).Times(1).Return(http_agent.FakeHttpResponse(200, "content2"), nil)
+	)
 
-	_ = client.SetHttpAgent(mockHttpAgent)
-	_ = client.SetClientConfig(clientConfigTest)
-	_ = client.SetServerConfig(serverConfigsTest)
-	configData := ""
-	client.listenConfigTask(clientConfigTest, serverConfigsTest, mockHttpAgent, vo.ConfigParam{
-		DataId:  "dataId",
-		Group:   "group",
-		Content: "content",
-		OnChange: func(namespace, group, dataId, data string) {
-			configData = data
-		},
+	var received []string
+	var events []model.ConfigChangeEvent
+	cd := newCacheDataTest("", "content", func(namespace, group, dataId, data string) {
+		received = append(received, data)
 	})
+	cd.data = "content"
+	cd.changeListeners = map[int64]changeListenerFunc{2: func(e model.ConfigChangeEvent) {
+		events = append(events, e)
+	}}
 
-	assert.Equal(t, "content2", configData)
+	assert.Nil(t, client.listenConfigBatch(listenClientConfigTest, mockHttpAgent, []*cacheData{cd}))
+	(<-client.listener.notifyChan)()
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, model.ConfigChangeEvent{Group: "group", DataId: "dataId", OldContent: "content", NewContent: "content2",
+		OldMd5: util.Md5("content"), NewMd5: util.Md5("content2"), ChangeType: model.Config_Change_Modified}, events[0])
+
+	// 服务端删除配置后只以DELETED事件通知OnChangeEvent
+	assert.Nil(t, client.listenConfigBatch(listenClientConfigTest, mockHttpAgent, []*cacheData{cd}))
+	(<-client.listener.notifyChan)()
+	assert.Equal(t, 2, len(events))
+	assert.Equal(t, model.Config_Change_Deleted, events[1].ChangeType)
+	assert.Equal(t, "content2", events[1].OldContent)
+	assert.Equal(t, "", events[1].NewContent)
+	assert.Equal(t, []string{"content2"}, received)
+	assert.Equal(t, "", cd.getMd5())
+}
+
+func Test_listenConfigBatch_ConfigType(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	client := createConfigClientTest(t, mockHttpAgent)
+	mockHttpAgent.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(1).Return(http_agent.FakeHttpResponse(200, "app%02group%01"), nil)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(1).Return(configTypeResponse("port: 8080", "yaml"), nil)
+
+	cd := &cacheData{dataId: "app", group: "group", listeners: map[int64]listenerFunc{}}
+	client.listener.cacheMap.Store(utils.GetConfigCacheKey("app", "group", ""), cd)
+	assert.Nil(t, client.listenConfigBatch(listenClientConfigTest, mockHttpAgent, []*cacheData{cd}))
+	// ListenConfigAs按监听时服务端返回的类型解析
+	assert.Equal(t, "yaml", client.listenedConfigType("app", "group"))
+	c, err := getCodec(vo.ConfigParam{DataId: "app"}, client.listenedConfigType("app", "group"))
+	assert.Nil(t, err)
+	var v struct{ Port int }
+	assert.Nil(t, c.Unmarshal([]byte("port: 8080"), &v))
+	assert.Equal(t, 8080, v.Port)
+}
+
+func Test_listenConfigBatch_Audit(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	cacheDir, _ := ioutil.TempDir("", "nacos-config")
+	defer os.RemoveAll(cacheDir)
+	var records []audit.Record
+	nc := nacos_client.NacosClient{}
+	nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	clientConfig := listenClientConfigTest
+	clientConfig.CacheDir = cacheDir
+	clientConfig.AuditSink = audit.SinkFunc(func(record audit.Record) error {
+		records = append(records, record)
+		return nil
+	})
+	nc.SetClientConfig(clientConfig)
+	nc.SetHttpAgent(mockHttpAgent)
+	client, err := NewConfigClient(&nc)
+	assert.Nil(t, err)
+
+	mockHttpAgent.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(3).Return(http_agent.FakeHttpResponse(200, "dataId%02group%01"), nil)
+	modified := http_agent.FakeHttpResponse(200, "content2")
+	modified.Header.Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+	gomock.InOrder(
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(modified, nil),
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(http_agent.FakeHttpResponse(200, "bad"), nil),
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			MinTimes(1).DoAndReturn(func(...interface{}) (*http.Response, error) {
+			return http_agent.FakeHttpResponse(404, "config data not exist"), nil
+		}),
+	)
+	cd := newCacheDataTest("", "content", func(namespace, group, dataId, data string) {})
+	cd.validators = map[int64]func(content string) error{1: func(content string) error {
+		if content == "bad" {
+			return errors.New("invalid content")
+		}
+		return nil
+	}}
+	client.listener.cacheMap.Store(utils.GetConfigCacheKey("dataId", "group", ""), cd)
+
+	for i := 0; i < 3; i++ {
+		assert.Nil(t, client.listenConfigBatch(clientConfig, mockHttpAgent, []*cacheData{cd}))
+	}
+	assert.Equal(t, 3, len(records))
+	assert.Equal(t, audit.Module_Config, records[0].Module)
+	assert.Equal(t, "dataId", records[0].Name)
+	assert.Equal(t, model.Config_Change_Modified, records[0].ChangeType)
+	assert.Equal(t, util.Md5("content"), records[0].OldMd5)
+	assert.Equal(t, util.Md5("content2"), records[0].NewMd5)
+	assert.Equal(t, "console.nacos.io:80", records[0].Server)
+	assert.Equal(t, int64(1136214245000), records[0].ServerTime)
+	assert.Equal(t, audit.Change_Rejected, records[1].ChangeType)
+	assert.Equal(t, util.Md5("bad"), records[1].NewMd5)
+	assert.Equal(t, model.Config_Change_Deleted, records[2].ChangeType)
+	assert.Equal(t, util.Md5("content2"), records[2].OldMd5)
+	assert.Equal(t, "", records[2].NewMd5)
+}
+
+func Test_cacheData_OrderedNotify(t *testing.T) {
+	client := cretateConfigClientTest()
+	var received []string
+	cd := newCacheDataTest("", "content", func(namespace, group, dataId, data string) {
+		received = append(received, data)
+	})
+	assert.True(t, cd.update(util.Md5("content1"), "content1"))
+	assert.False(t, cd.update(util.Md5("content2"), "content2"), "queue is already scheduled")
+	client.scheduleNotify(cd)
+	assert.Equal(t, 1, len(client.listener.notifyChan))
+	(<-client.listener.notifyChan)()
+	assert.Equal(t, []string{"content1", "content2"}, received)
+
+	assert.True(t, cd.update(util.Md5("content3"), "content3"), "queue should be rescheduled after drained")
 }
 
-func Test_listenConfigTaskWithoutLocalConfigs(t *testing.T) {
+func Test_listenConfigBatchWithoutServer(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
 	client := cretateConfigClientTest()
 	mockHttpAgent := mock.NewMockIHttpAgent(controller)
-	client.listenConfigTask(clientConfigTest, serverConfigsTest, mockHttpAgent,
-		vo.ConfigParam{
-			DataId:  "dataId",
-			Group:   "group",
-			Content: "content",
+	client.listenConfigBatch(clientConfigTest, mockHttpAgent, []*cacheData{
+		newCacheDataTest("", "content", func(namespace, group, dataId, data string) {})})
+}
+
+func Test_ListenAndCancelListenConfig(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	mockHttpAgent.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			time.Sleep(100 * time.Millisecond)
+			return http_agent.FakeHttpResponse(200, ""), nil
+		})
+	client := createConfigClientTest(t, mockHttpAgent)
+	defer client.Close()
+
+	onChange := func(namespace, group, dataId, data string) {}
+	assert.NotNil(t, client.ListenConfig(vo.ConfigParam{Group: "group", OnChange: onChange}))
+	assert.Nil(t, client.ListenConfig(vo.ConfigParam{DataId: "dataId", Group: "group", OnChange: onChange}))
+	assert.Nil(t, client.ListenConfig(vo.ConfigParam{DataId: "dataId", Group: "group", OnChange: onChange}))
+	assert.Equal(t, 1, len(client.listeningBatches()))
+	assert.Equal(t, 1, len(client.listeningBatches()[0]))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.Nil(t, client.ListenConfigWithContext(ctx, vo.ConfigParam{DataId: "dataId2", Group: "group", OnChange: onChange}))
+	assert.Equal(t, 2, len(client.listeningBatches()[0]))
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 1, len(client.listeningBatches()[0]))
+
+	assert.Nil(t, client.CancelListenConfig(vo.ConfigParam{DataId: "dataId", Group: "group"}))
+	assert.Equal(t, 0, len(client.listeningBatches()))
+}
+
+func Test_listeningBatchesWithBatchSize(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	mockHttpAgent.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			time.Sleep(100 * time.Millisecond)
+			return http_agent.FakeHttpResponse(200, ""), nil
 		})
-	//assert.Equal(t, false, client.listening)
+	client := createConfigClientTest(t, mockHttpAgent)
+	defer client.Close()
+	clientConfig, _ := client.GetClientConfig()
+	clientConfig.LongPoll = &constant.LongPollConfig{BatchSize: 2}
+	_ = client.SetClientConfig(clientConfig)
+
+	onChange := func(namespace, group, dataId, data string) {}
+	for i := 0; i < 5; i++ {
+		assert.Nil(t, client.ListenConfig(vo.ConfigParam{DataId: "dataId" + strconv.Itoa(i), Group: "group", OnChange: onChange}))
+	}
+	batches := client.listeningBatches()
+	assert.Equal(t, 3, len(batches))
+	assert.Equal(t, 2, len(batches[0]))
+	assert.Equal(t, 1, len(batches[2]))
+	assert.Equal(t, "dataId4", batches[2][0].dataId)
+
+	assert.Nil(t, client.CancelListenConfig(vo.ConfigParam{DataId: "dataId0", Group: "group"}))
+	batches = client.listeningBatches()
+	assert.Equal(t, 2, len(batches))
+	assert.Equal(t, "dataId1", batches[0][0].dataId)
+	assert.Equal(t, "dataId4", batches[1][1].dataId)
+}
+
+func Test_listenConfigBatch_AdaptiveTimeout(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	client := createConfigClientTest(t, mockHttpAgent)
+	clientConfig := listenClientConfigTest
+	clientConfig.LongPoll = &constant.LongPollConfig{TimeoutMs: 20 * 1000, Adaptive: true}
+
+	var holds []string
+	var timeouts []uint64
+	mockHttpAgent.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(3).
+		DoAndReturn(func(path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			holds = append(holds, header.Get("Long-Pulling-Timeout"))
+			timeouts = append(timeouts, timeoutMs)
+			if len(holds) == 1 {
+				return nil, errors.New("connection reset by peer")
+			}
+			return http_agent.FakeHttpResponse(200, ""), nil
+		})
+	batch := []*cacheData{newCacheDataTest("tenant", "content", func(namespace, group, dataId, data string) {})}
+	assert.NotNil(t, client.listenConfigBatch(clientConfig, mockHttpAgent, batch))
+	assert.Nil(t, client.listenConfigBatch(clientConfig, mockHttpAgent, batch))
+	assert.Nil(t, client.listenConfigBatch(clientConfig, mockHttpAgent, batch))
+	assert.Equal(t, []string{"20000", "10000", "15000"}, holds)
+	assert.Equal(t, []uint64{30000, 15000, 22500}, timeouts)
+}
+
+func Test_ListenConfigReplay(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	mockHttpAgent.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			time.Sleep(100 * time.Millisecond)
+			return http_agent.FakeHttpResponse(200, ""), nil
+		})
+	client := createConfigClientTest(t, mockHttpAgent)
+	defer client.Close()
+	client.localConfigs = []vo.ConfigParam{{DataId: "dataId", Group: "group", Content: "content"}}
+
+	received := make(chan string, 1)
+	assert.Nil(t, client.ListenConfig(vo.ConfigParam{DataId: "dataId", Group: "group",
+		OnChange: func(namespace, group, dataId, data string) {
+			received <- data
+		}}))
+	select {
+	case data := <-received:
+		assert.Equal(t, "content", data)
+	case <-time.After(time.Second):
+		t.Fatal("listener should be invoked with current content")
+	}
+
+	clientConfig, _ := client.GetClientConfig()
+	clientConfig.NotReplayOnListen = true
+	assert.Nil(t, client.SetClientConfig(clientConfig))
+	assert.Nil(t, client.ListenConfig(vo.ConfigParam{DataId: "dataId", Group: "group",
+		OnChange: func(namespace, group, dataId, data string) {
+			received <- data
+		}}))
+	select {
+	case <-received:
+		t.Fatal("listener should not be invoked when replay is disabled")
+	case <-time.After(100 * time.Millisecond):
+	}
 }
 
 // listen
@@ -562,7 +1248,7 @@ func Test_listen(t *testing.T) {
 
 	_ = client.SetHttpAgent(mockHttpAgent)
 
-	changed, err := listen(mockHttpAgent, path, clientConfigTest.TimeoutMs, clientConfigTest.ListenInterval, param)
+	changed, err := listen(mockHttpAgent, path, clientConfigTest.TimeoutMs, clientConfigTest.ListenInterval, param, nil)
 	assert.Equal(t, changed, changedString)
 	assert.Nil(t, err)
 }
@@ -585,7 +1271,7 @@ func Test_listenWithErrorResponse_401(t *testing.T) {
 
 	_ = client.SetHttpAgent(mockHttpAgent)
 
-	_, err := listen(mockHttpAgent, path, clientConfigTest.TimeoutMs, clientConfigTest.ListenInterval, param)
+	_, err := listen(mockHttpAgent, path, clientConfigTest.TimeoutMs, clientConfigTest.ListenInterval, param, nil)
 	assert.NotNil(t, err)
 }
 
@@ -652,3 +1338,77 @@ func Test_AddConfigToListen(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, resultConfigs, client.localConfigs)
 }
+
+func Test_PublishConfigWithCompression(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	content := strings.Repeat("{\"rule\":\"allow\"},", 100)
+	mockHttpAgent.EXPECT().RequestWithBody(gomock.Any(), gomock.Eq(http.MethodPost), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx context.Context, method string, path string, header http.Header, timeoutMs uint64, body []byte) (*http.Response, error) {
+			u, err := url.Parse(path)
+			assert.Nil(t, err)
+			assert.Equal(t, "dataId", u.Query().Get("dataId"))
+			assert.Equal(t, "", u.Query().Get("content"))
+			assert.Equal(t, "gzip", header["Content-Encoding"][0])
+			assert.True(t, len(body) < len(content))
+			form, err := utils.DecompressData(body)
+			assert.Nil(t, err)
+			values, err := url.ParseQuery(string(form))
+			assert.Nil(t, err)
+			assert.Equal(t, content, values.Get("content"))
+			return http_agent.FakeHttpResponse(200, "true"), nil
+		})
+	// 未达到压缩阈值的内容仍以表单发布
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		Return(http_agent.FakeHttpResponse(200, "true"), nil)
+	client := createConfigClientTest(t, mockHttpAgent, func(clientConfig *constant.ClientConfig) {
+		clientConfig.ConfigContent = &constant.ConfigContentConfig{CompressThreshold: 1024}
+	})
+	defer client.Close()
+
+	success, err := client.PublishConfig(vo.ConfigParam{DataId: "dataId", Group: "group", Content: content})
+	assert.Nil(t, err)
+	assert.True(t, success)
+	success, err = client.PublishConfig(vo.ConfigParam{DataId: "dataId", Group: "group", Content: "content"})
+	assert.Nil(t, err)
+	assert.True(t, success)
+}
+
+func Test_PublishConfigExceedsMaxContentSize(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	client := createConfigClientTest(t, mockHttpAgent, func(clientConfig *constant.ClientConfig) {
+		clientConfig.ConfigContent = &constant.ConfigContentConfig{MaxContentSize: 16}
+	})
+	defer client.Close()
+
+	_, err := client.PublishConfig(vo.ConfigParam{DataId: "dataId", Group: "group", Content: strings.Repeat("a", 17)})
+	var ve *validator.ValidationError
+	assert.True(t, errors.As(err, &ve))
+	assert.Contains(t, err.Error(), "exceeds the limit of 16 bytes")
+}
+
+func Test_GetConfigWithGzip(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	compressed, err := utils.CompressData([]byte("large content"))
+	assert.Nil(t, err)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx context.Context, method string, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			assert.Equal(t, "gzip", header["Accept-Encoding"][0])
+			response := http_agent.FakeHttpResponse(200, string(compressed))
+			response.Header.Set("Content-Encoding", "gzip")
+			return response, nil
+		})
+	client := createConfigClientTest(t, mockHttpAgent, func(clientConfig *constant.ClientConfig) {
+		clientConfig.ConfigContent = &constant.ConfigContentConfig{AcceptGzip: true}
+	})
+	defer client.Close()
+
+	content, err := client.GetConfig(vo.ConfigParam{DataId: "dataId", Group: "group"})
+	assert.Nil(t, err)
+	assert.Equal(t, "large content", content)
+}
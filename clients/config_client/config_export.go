@@ -0,0 +1,75 @@
+package config_client
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/common/nacos_error"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// 导入配置时遇到已存在配置的处理方式
+const (
+	// 停止导入，已存在的配置及其后的配置均不导入
+	Import_Policy_Abort = "ABORT"
+	// 跳过已存在的配置
+	Import_Policy_Skip = "SKIP"
+	// 覆盖已存在的配置
+	Import_Policy_Overwrite = "OVERWRITE"
+)
+
+// Policy为ABORT且有配置已存在时返回，此时ConfigImportResult.FailData为未导入的配置
+var ErrConfigImportAborted = nacos_error.NewNacosError(strconv.Itoa(http.StatusConflict), "[client.ImportConfigs] import aborted because of existing config", nil)
+
+func (client *ConfigClient) ExportConfigs(param vo.ExportConfigParam) ([]byte, error) {
+	return client.ExportConfigsWithContext(context.Background(), param)
+}
+
+// 导出命名空间下的配置为zip，可用于备份或通过ImportConfigs导入到其他命名空间或集群
+func (client *ConfigClient) ExportConfigsWithContext(ctx context.Context, param vo.ExportConfigParam) ([]byte, error) {
+	clientConfig, _ := client.GetClientConfig()
+	if len(param.NamespaceId) == 0 {
+		param.NamespaceId = clientConfig.NamespaceId
+	}
+	return client.configProxy.ExportConfigProxy(ctx, param, clientConfig.AccessKey, clientConfig.SecretKey)
+}
+
+func (client *ConfigClient) ImportConfigs(param vo.ImportConfigParam) (*model.ConfigImportResult, error) {
+	return client.ImportConfigsWithContext(context.Background(), param)
+}
+
+func (client *ConfigClient) ImportConfigsWithContext(ctx context.Context, param vo.ImportConfigParam) (*model.ConfigImportResult, error) {
+	if len(param.Content) == 0 {
+		return nil, errors.New("[client.ImportConfigs] param.content can not be empty")
+	}
+	if _, err := zip.NewReader(bytes.NewReader(param.Content), int64(len(param.Content))); err != nil {
+		return nil, nacos_error.Wrap("[client.ImportConfigs] param.content is not a valid zip", err)
+	}
+	param.Policy = strings.ToUpper(param.Policy)
+	switch param.Policy {
+	case "":
+		param.Policy = Import_Policy_Abort
+	case Import_Policy_Abort, Import_Policy_Skip, Import_Policy_Overwrite:
+	default:
+		return nil, errors.New("[client.ImportConfigs] unknown policy:" + param.Policy)
+	}
+	clientConfig, _ := client.GetClientConfig()
+	if len(param.NamespaceId) == 0 {
+		param.NamespaceId = clientConfig.NamespaceId
+	}
+	logger.Infof("import configs namespace:<%s>,policy:<%s>,size:<%d>", param.NamespaceId, param.Policy, len(param.Content))
+	result, err := client.configProxy.ImportConfigProxy(ctx, param, clientConfig.AccessKey, clientConfig.SecretKey)
+	if err != nil {
+		return result, err
+	}
+	if param.Policy == Import_Policy_Abort && len(result.FailData) > 0 {
+		return result, ErrConfigImportAborted
+	}
+	return result, nil
+}
@@ -0,0 +1,90 @@
+package config_client
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/mock"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func createConfigZipTest(t *testing.T) []byte {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	file, err := writer.Create("group/dataId")
+	assert.Nil(t, err)
+	file.Write([]byte("hello world"))
+	assert.Nil(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestExportConfigs(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	content := createConfigZipTest(t)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"), gomock.Any(), gomock.Any(),
+		gomock.Eq(map[string]string{"export": "true", "group": "group"})).Times(1).
+		Return(http_agent.FakeHttpResponse(200, string(content)), nil)
+	client := createConfigClientTest(t, mockHttpAgent)
+	defer client.Close()
+
+	exported, err := client.ExportConfigs(vo.ExportConfigParam{Group: "group"})
+	assert.Nil(t, err)
+	assert.Equal(t, content, exported)
+}
+
+func TestImportConfigs(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	content := createConfigZipTest(t)
+	mockHttpAgent.EXPECT().RequestWithBody(gomock.Any(), gomock.Eq(http.MethodPost), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
+		DoAndReturn(func(ctx context.Context, method string, path string, header http.Header, timeoutMs uint64, body []byte) (*http.Response, error) {
+			u, err := url.Parse(path)
+			assert.Nil(t, err)
+			assert.Equal(t, "/nacos/v1/cs/configs", u.Path)
+			assert.Equal(t, "true", u.Query().Get("import"))
+			assert.Equal(t, "dev", u.Query().Get("namespace"))
+
+			_, mediaParams, err := mime.ParseMediaType(header.Get("Content-Type"))
+			assert.Nil(t, err)
+			file, err := multipart.NewReader(bytes.NewReader(body), mediaParams["boundary"]).NextPart()
+			assert.Nil(t, err)
+			assert.Equal(t, "file", file.FormName())
+			uploaded, _ := ioutil.ReadAll(file)
+			assert.Equal(t, content, uploaded)
+
+			if u.Query().Get("policy") == Import_Policy_Skip {
+				return http_agent.FakeHttpResponse(200, `{"code":200,"message":"导入成功","data":{"succCount":0,"skipCount":1,"skipData":[{"dataId":"dataId","group":"group"}]}}`), nil
+			}
+			assert.Equal(t, Import_Policy_Abort, u.Query().Get("policy"))
+			return http_agent.FakeHttpResponse(200, `{"code":200,"message":"导入成功","data":{"succCount":0,"failData":[{"dataId":"dataId","group":"group"}]}}`), nil
+		})
+	client := createConfigClientTest(t, mockHttpAgent)
+	defer client.Close()
+
+	_, err := client.ImportConfigs(vo.ImportConfigParam{NamespaceId: "dev", Content: []byte("not a zip")})
+	assert.NotNil(t, err)
+	_, err = client.ImportConfigs(vo.ImportConfigParam{NamespaceId: "dev", Content: content, Policy: "replace"})
+	assert.NotNil(t, err)
+
+	result, err := client.ImportConfigs(vo.ImportConfigParam{NamespaceId: "dev", Content: content, Policy: "skip"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result.SkipCount)
+	assert.Equal(t, "dataId", result.SkipData[0].DataId)
+
+	result, err = client.ImportConfigs(vo.ImportConfigParam{NamespaceId: "dev", Content: content})
+	assert.Equal(t, ErrConfigImportAborted, err)
+	assert.Equal(t, 1, len(result.FailData))
+}
@@ -0,0 +1,98 @@
+package config_client
+
+import (
+	"context"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/common/validator"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+// 配置历史的操作类型
+const (
+	History_Op_Insert = "I"
+	History_Op_Update = "U"
+	History_Op_Delete = "D"
+)
+
+func (client *ConfigClient) GetConfigHistory(param vo.ConfigHistoryParam) (*model.ConfigHistoryPage, error) {
+	return client.GetConfigHistoryWithContext(context.Background(), param)
+}
+
+// 加密配置的历史内容会被解密后返回
+func (client *ConfigClient) GetConfigHistoryWithContext(ctx context.Context, param vo.ConfigHistoryParam) (*model.ConfigHistoryPage, error) {
+	if err := validator.New("GetConfigHistory").DataId("dataId", param.DataId).Group("group", param.Group).Err(); err != nil {
+		return nil, err
+	}
+	clientConfig, _ := client.GetClientConfig()
+	page, err := client.configProxy.GetConfigHistoryProxy(ctx, param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+	if err != nil {
+		return nil, err
+	}
+	for i := range page.PageItems {
+		if err = client.decryptHistory(&page.PageItems[i]); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+func (client *ConfigClient) GetPreviousConfig(param vo.ConfigHistoryDetailParam) (*model.ConfigHistory, error) {
+	return client.GetPreviousConfigWithContext(context.Background(), param)
+}
+
+// 获取param.Id对应历史的上一条历史，即该次修改之前的上一次修改
+func (client *ConfigClient) GetPreviousConfigWithContext(ctx context.Context, param vo.ConfigHistoryDetailParam) (*model.ConfigHistory, error) {
+	if err := checkHistoryDetailParam("GetPreviousConfig", param); err != nil {
+		return nil, err
+	}
+	return client.getConfigHistoryDetail(ctx, param, true)
+}
+
+func (client *ConfigClient) RollbackConfig(param vo.ConfigHistoryDetailParam) (bool, error) {
+	return client.RollbackConfigWithContext(context.Background(), param)
+}
+
+// 将配置恢复到param.Id对应操作之前的状态，与控制台的回滚一致：
+// 新建操作的回滚会删除配置，修改和删除操作的回滚会重新发布操作前的内容
+func (client *ConfigClient) RollbackConfigWithContext(ctx context.Context, param vo.ConfigHistoryDetailParam) (bool, error) {
+	if err := checkHistoryDetailParam("RollbackConfig", param); err != nil {
+		return false, err
+	}
+	history, err := client.getConfigHistoryDetail(ctx, param, false)
+	if err != nil {
+		return false, err
+	}
+	logger.Infof("rollback config dataId:<%s>,group:<%s>,historyId:<%s>,opType:<%s>",
+		param.DataId, param.Group, param.Id, history.OpType)
+	if history.OpType == History_Op_Insert {
+		return client.DeleteConfigWithContext(ctx, vo.ConfigParam{DataId: param.DataId, Group: param.Group})
+	}
+	return client.PublishConfigWithContext(ctx, vo.ConfigParam{
+		DataId:  param.DataId,
+		Group:   param.Group,
+		Content: history.Content,
+		AppName: history.AppName,
+	})
+}
+
+func (client *ConfigClient) getConfigHistoryDetail(ctx context.Context, param vo.ConfigHistoryDetailParam, previous bool) (*model.ConfigHistory, error) {
+	clientConfig, _ := client.GetClientConfig()
+	history, err := client.configProxy.GetConfigHistoryDetailProxy(ctx, param, previous, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+	if err != nil {
+		return nil, err
+	}
+	if err = client.decryptHistory(history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (client *ConfigClient) decryptHistory(history *model.ConfigHistory) (err error) {
+	history.Content, err = client.decrypt(history.DataId, history.Content)
+	return err
+}
+
+func checkHistoryDetailParam(method string, param vo.ConfigHistoryDetailParam) error {
+	return validator.New(method).Required("id", param.Id).DataId("dataId", param.DataId).Group("group", param.Group).Err()
+}
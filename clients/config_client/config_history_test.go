@@ -0,0 +1,88 @@
+package config_client
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/mock"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestGetConfigHistory(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/history"), gomock.Any(), gomock.Any(),
+		gomock.Eq(map[string]string{"search": "accurate", "dataId": "dataId", "group": "group", "pageNo": "1", "pageSize": "10"})).Times(1).
+		Return(http_agent.FakeHttpResponse(200, `{"totalCount":2,"pageNumber":1,"pagesAvailable":1,"pageItems":[`+
+			`{"id":"12","lastId":-1,"dataId":"dataId","group":"group","content":"v2","opType":"U","srcIp":"10.0.0.1","lastModifiedTime":1600000000000},`+
+			`{"id":"11","lastId":-1,"dataId":"dataId","group":"group","content":"v1","opType":"I","srcIp":"10.0.0.1","lastModifiedTime":1500000000000}]}`), nil)
+	client := createConfigClientTest(t, mockHttpAgent)
+	defer client.Close()
+
+	_, err := client.GetConfigHistory(vo.ConfigHistoryParam{Group: "group"})
+	assert.NotNil(t, err)
+	page, err := client.GetConfigHistory(vo.ConfigHistoryParam{DataId: "dataId", Group: "group", PageNo: 1, PageSize: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, page.TotalCount)
+	assert.Equal(t, "12", page.PageItems[0].Id)
+	assert.Equal(t, History_Op_Update, page.PageItems[0].OpType)
+	assert.Equal(t, "v1", page.PageItems[1].Content)
+	assert.Equal(t, int64(1500000000000), page.PageItems[1].LastModifiedTime)
+}
+
+func TestGetPreviousConfig(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/history/previous"), gomock.Any(), gomock.Any(),
+		gomock.Eq(map[string]string{"id": "12", "dataId": "dataId", "group": "group"})).Times(1).
+		Return(http_agent.FakeHttpResponse(200, `{"id":"11","dataId":"dataId","group":"group","content":"v1","opType":"I"}`), nil)
+	client := createConfigClientTest(t, mockHttpAgent)
+	defer client.Close()
+
+	_, err := client.GetPreviousConfig(vo.ConfigHistoryDetailParam{DataId: "dataId", Group: "group"})
+	assert.NotNil(t, err)
+	history, err := client.GetPreviousConfig(vo.ConfigHistoryDetailParam{Id: "12", DataId: "dataId", Group: "group"})
+	assert.Nil(t, err)
+	assert.Equal(t, "11", history.Id)
+	assert.Equal(t, "v1", history.Content)
+}
+
+func TestRollbackConfig(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	historyUrl := "http://console.nacos.io:80/nacos/v1/cs/history"
+	configUrl := "http://console.nacos.io:80/nacos/v1/cs/configs"
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet), gomock.Eq(historyUrl),
+		gomock.Any(), gomock.Any(), gomock.Eq(map[string]string{"nid": "12", "dataId": "dataId", "group": "group"})).Times(1).
+		Return(http_agent.FakeHttpResponse(200, `{"id":"12","dataId":"dataId","group":"group","appName":"app","content":"v1","opType":"U"}`), nil)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet), gomock.Eq(historyUrl),
+		gomock.Any(), gomock.Any(), gomock.Eq(map[string]string{"nid": "11", "dataId": "dataId", "group": "group"})).Times(1).
+		Return(http_agent.FakeHttpResponse(200, `{"id":"11","dataId":"dataId","group":"group","content":"v0","opType":"I"}`), nil)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost), gomock.Eq(configUrl),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx, method, path, header, timeoutMs interface{}, params map[string]string) (*http.Response, error) {
+			assert.Equal(t, "v1", params["content"])
+			assert.Equal(t, "app", params["appName"])
+			return http_agent.FakeHttpResponse(200, "true"), nil
+		})
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodDelete), gomock.Eq(configUrl),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		Return(http_agent.FakeHttpResponse(200, "true"), nil)
+	client := createConfigClientTest(t, mockHttpAgent)
+	defer client.Close()
+
+	success, err := client.RollbackConfig(vo.ConfigHistoryDetailParam{Id: "12", DataId: "dataId", Group: "group"})
+	assert.Nil(t, err)
+	assert.True(t, success)
+	// 回滚新建操作会删除配置
+	success, err = client.RollbackConfig(vo.ConfigHistoryDetailParam{Id: "11", DataId: "dataId", Group: "group"})
+	assert.Nil(t, err)
+	assert.True(t, success)
+}
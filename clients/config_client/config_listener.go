@@ -0,0 +1,775 @@
+package config_client
+
+import (
+	"context"
+	"errors"
+	"github.com/nacos-group/nacos-sdk-go/common/audit"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/event"
+	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/common/nacos_error"
+	"github.com/nacos-group/nacos-sdk-go/common/nacos_server"
+	"github.com/nacos-group/nacos-sdk-go/common/rate_limiter"
+	"github.com/nacos-group/nacos-sdk-go/common/tracing"
+	"github.com/nacos-group/nacos-sdk-go/common/util"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/utils"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// 单个长轮询请求中默认最多包含的配置数
+	perTaskConfigSize = 3000
+	// 自适应调整时服务端挂起时间的下限
+	Min_Long_Poll_Timeout_Ms = 5 * 1000
+	// 执行监听回调的协程数
+	Default_Notify_Worker_Num = 4
+	// 长轮询失败后的重试间隔
+	listenRetryInterval = 2 * time.Second
+)
+
+type listenerFunc func(namespace, group, dataId, data string)
+
+type changeListenerFunc func(event model.ConfigChangeEvent)
+
+// 一个被监听的配置，md5和data为最近一次通知给监听者的内容的md5和解密后的内容
+type cacheData struct {
+	mutex     sync.Mutex
+	dataId    string
+	group     string
+	tenant    string
+	md5       string
+	data      string
+	listeners map[int64]listenerFunc
+	// 通过OnChangeEvent注册的监听者，与listeners共用id
+	changeListeners map[int64]changeListenerFunc
+	// 监听者的校验函数，任一校验失败时拒绝本次变化
+	validators map[int64]func(content string) error
+	// 最近一次未通过校验的内容的md5，长轮询时上报该md5，避免服务端对同一内容反复通知
+	rejectedMd5 string
+	// 待执行的回调，同一时刻至多由一个worker按入队顺序执行
+	pending   []func()
+	notifying bool
+	// 串行化同一配置的刷新，保证按拉取的先后顺序更新内容
+	refreshMutex sync.Mutex
+	// 最近一次拉取时服务端返回的配置类型
+	configType string
+}
+
+// 监听时上报的md5，最近一次变化未通过校验时上报被拒绝的内容的md5，避免服务端对同一内容反复通知
+func (cd *cacheData) listeningMd5() string {
+	cd.mutex.Lock()
+	defer cd.mutex.Unlock()
+	if cd.rejectedMd5 != "" {
+		return cd.rejectedMd5
+	}
+	return cd.md5
+}
+
+func (cd *cacheData) listeningConfig() string {
+	md5 := cd.listeningMd5()
+	if len(cd.tenant) > 0 {
+		return cd.dataId + constant.SPLIT_CONFIG_INNER + cd.group + constant.SPLIT_CONFIG_INNER +
+			md5 + constant.SPLIT_CONFIG_INNER + cd.tenant + constant.SPLIT_CONFIG
+	}
+	return cd.dataId + constant.SPLIT_CONFIG_INNER + cd.group + constant.SPLIT_CONFIG_INNER +
+		md5 + constant.SPLIT_CONFIG
+}
+
+func (cd *cacheData) getMd5() string {
+	cd.mutex.Lock()
+	defer cd.mutex.Unlock()
+	return cd.md5
+}
+
+func (cd *cacheData) getConfigType() string {
+	cd.mutex.Lock()
+	defer cd.mutex.Unlock()
+	return cd.configType
+}
+
+func (cd *cacheData) setConfigType(configType string) {
+	cd.mutex.Lock()
+	defer cd.mutex.Unlock()
+	cd.configType = configType
+}
+
+func (cd *cacheData) getRejectedMd5() string {
+	cd.mutex.Lock()
+	defer cd.mutex.Unlock()
+	return cd.rejectedMd5
+}
+
+// 依次执行所有校验函数，返回第一个错误
+func (cd *cacheData) validate(data string) error {
+	cd.mutex.Lock()
+	ids := make([]int64, 0, len(cd.validators))
+	for id := range cd.validators {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	validators := make([]func(content string) error, 0, len(ids))
+	for _, id := range ids {
+		validators = append(validators, cd.validators[id])
+	}
+	cd.mutex.Unlock()
+	for _, validate := range validators {
+		if err := validate(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 拒绝md5对应的内容，继续使用上次校验通过的内容
+func (cd *cacheData) reject(md5 string) {
+	cd.mutex.Lock()
+	defer cd.mutex.Unlock()
+	cd.rejectedMd5 = md5
+}
+
+// 最近一次变化未通过校验时返回上次校验通过的内容
+func (cd *cacheData) validatedData() (string, bool) {
+	cd.mutex.Lock()
+	defer cd.mutex.Unlock()
+	return cd.data, cd.rejectedMd5 != ""
+}
+
+// 更新内容并将对所有监听者的通知加入队列，返回是否需要调度执行队列
+// md5为空表示配置已被删除，只通知OnChangeEvent注册的监听者
+func (cd *cacheData) update(md5, data string) bool {
+	cd.mutex.Lock()
+	defer cd.mutex.Unlock()
+	changeEvent := model.ConfigChangeEvent{Namespace: cd.tenant, Group: cd.group, DataId: cd.dataId,
+		OldContent: cd.data, NewContent: data, OldMd5: cd.md5, NewMd5: md5, ChangeType: model.Config_Change_Modified}
+	if cd.md5 == "" {
+		changeEvent.ChangeType = model.Config_Change_Added
+	} else if md5 == "" {
+		changeEvent.ChangeType = model.Config_Change_Deleted
+	}
+	cd.md5 = md5
+	cd.data = data
+	cd.rejectedMd5 = ""
+	ids := make([]int64, 0, len(cd.listeners)+len(cd.changeListeners))
+	for id := range cd.listeners {
+		ids = append(ids, id)
+	}
+	for id := range cd.changeListeners {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		if listener, ok := cd.changeListeners[id]; ok {
+			cd.enqueueEvent(listener, changeEvent)
+		} else if md5 != "" {
+			cd.enqueue(cd.listeners[id])
+		}
+	}
+	return cd.schedule()
+}
+
+// 以当前内容通知listener，调用时需持有mutex
+func (cd *cacheData) enqueue(listener listenerFunc) {
+	tenant, group, dataId, data := cd.tenant, cd.group, cd.dataId, cd.data
+	cd.pending = append(cd.pending, func() { listener(tenant, group, dataId, data) })
+}
+
+// 调用时需持有mutex
+func (cd *cacheData) enqueueEvent(listener changeListenerFunc, changeEvent model.ConfigChangeEvent) {
+	cd.pending = append(cd.pending, func() { listener(changeEvent) })
+}
+
+// 注册时以当前内容回调，事件类型为ADDED，调用时需持有mutex
+func (cd *cacheData) replay(id int64) {
+	if listener, ok := cd.changeListeners[id]; ok {
+		cd.enqueueEvent(listener, model.ConfigChangeEvent{Namespace: cd.tenant, Group: cd.group, DataId: cd.dataId,
+			NewContent: cd.data, NewMd5: cd.md5, ChangeType: model.Config_Change_Added})
+	} else {
+		cd.enqueue(cd.listeners[id])
+	}
+}
+
+// 队列未在执行时标记为执行中并返回true，调用时需持有mutex
+func (cd *cacheData) schedule() bool {
+	if cd.notifying || len(cd.pending) == 0 {
+		return false
+	}
+	cd.notifying = true
+	return true
+}
+
+// 依次执行队列中的回调，队列为空时结束，之后加入的回调需重新调度
+func (cd *cacheData) drain() {
+	for {
+		cd.mutex.Lock()
+		if len(cd.pending) == 0 {
+			cd.notifying = false
+			cd.mutex.Unlock()
+			return
+		}
+		notify := cd.pending[0]
+		cd.pending[0] = nil
+		cd.pending = cd.pending[1:]
+		cd.mutex.Unlock()
+		notify()
+	}
+}
+
+// 监听相关的状态，在ConfigClient的值拷贝之间共享
+type configListener struct {
+	cacheMap   sync.Map
+	listenerId int64
+	startOnce  sync.Once
+	notifyChan chan func()
+	// 正在运行的长轮询分片，key为分片序号
+	shardMutex sync.Mutex
+	shards     map[int]bool
+	// 监听的配置增减时通知重新分片
+	rebalanceChan chan struct{}
+	// 自适应调整后的服务端挂起时间，为0时使用配置的值
+	holdMs uint64
+	// 通过长连接监听的分片等待该通道关闭后立即重新注册监听
+	wakeMutex sync.Mutex
+	wakeChan  chan struct{}
+}
+
+func newConfigListener() *configListener {
+	return &configListener{
+		notifyChan:    make(chan func(), 1024),
+		shards:        map[int]bool{},
+		rebalanceChan: make(chan struct{}, 1),
+	}
+}
+
+// 通知长轮询按当前监听的配置重新分片，新增的分片立即开始长轮询
+func (listener *configListener) rebalance() {
+	select {
+	case listener.rebalanceChan <- struct{}{}:
+	default:
+	}
+}
+
+// 返回下次wake时关闭的通道
+func (listener *configListener) wakeup() <-chan struct{} {
+	listener.wakeMutex.Lock()
+	defer listener.wakeMutex.Unlock()
+	if listener.wakeChan == nil {
+		listener.wakeChan = make(chan struct{})
+	}
+	return listener.wakeChan
+}
+
+// 唤醒所有通过长连接监听的分片
+func (listener *configListener) wake() {
+	listener.wakeMutex.Lock()
+	defer listener.wakeMutex.Unlock()
+	if listener.wakeChan != nil {
+		close(listener.wakeChan)
+		listener.wakeChan = nil
+	}
+}
+
+// 注册监听，返回的id用于取消该回调
+func (client *ConfigClient) addListener(param vo.ConfigParam) int64 {
+	clientConfig, _ := client.GetClientConfig()
+	tenant := clientConfig.NamespaceId
+	key := utils.GetConfigCacheKey(param.DataId, param.Group, tenant)
+
+	client.mutex.Lock()
+	for _, config := range client.localConfigs {
+		if config.Group == param.Group && config.DataId == param.DataId {
+			param.Content = config.Content
+			break
+		}
+	}
+	client.mutex.Unlock()
+	var md5, data string
+	if len(param.Content) > 0 {
+		md5 = util.Md5(param.Content)
+		data, _ = client.decrypt(param.DataId, param.Content)
+	}
+
+	value, loaded := client.listener.cacheMap.LoadOrStore(key, &cacheData{
+		dataId:          param.DataId,
+		group:           param.Group,
+		tenant:          tenant,
+		md5:             md5,
+		data:            data,
+		listeners:       map[int64]listenerFunc{},
+		changeListeners: map[int64]changeListenerFunc{},
+		validators:      map[int64]func(content string) error{},
+	})
+	if !loaded {
+		client.configProxy.nacosServer.Metrics().AddListenConfigs(1)
+	}
+	client.listener.startOnce.Do(client.startListening)
+
+	cd := value.(*cacheData)
+	id := atomic.AddInt64(&client.listener.listenerId, 1)
+	cd.mutex.Lock()
+	if param.OnChangeEvent != nil {
+		cd.changeListeners[id] = param.OnChangeEvent
+	} else {
+		cd.listeners[id] = param.OnChange
+	}
+	if param.Validate != nil {
+		cd.validators[id] = param.Validate
+	}
+	// 内容已知时立即以当前内容回调一次，内容未知时由首次长轮询通知
+	scheduled := false
+	if !clientConfig.NotReplayOnListen && len(cd.md5) > 0 {
+		cd.replay(id)
+		scheduled = cd.schedule()
+	}
+	cd.mutex.Unlock()
+	if scheduled {
+		client.scheduleNotify(cd)
+	}
+	client.releaseRestoredListener(param.DataId, param.Group, id)
+	if !loaded {
+		client.listener.rebalance()
+		client.saveListening()
+	}
+	return id
+}
+
+func (client *ConfigClient) removeListener(dataId, group string, id int64) {
+	clientConfig, _ := client.GetClientConfig()
+	key := utils.GetConfigCacheKey(dataId, group, clientConfig.NamespaceId)
+	value, ok := client.listener.cacheMap.Load(key)
+	if !ok {
+		return
+	}
+	cd := value.(*cacheData)
+	cd.mutex.Lock()
+	delete(cd.listeners, id)
+	delete(cd.changeListeners, id)
+	delete(cd.validators, id)
+	empty := len(cd.listeners) == 0 && len(cd.changeListeners) == 0
+	cd.mutex.Unlock()
+	if empty {
+		if _, ok := client.listener.cacheMap.LoadAndDelete(key); ok {
+			client.unlistenRemote(cd)
+			client.configProxy.nacosServer.Metrics().AddListenConfigs(-1)
+			client.listener.rebalance()
+			client.saveListening()
+		}
+	}
+}
+
+// 取消dataId和group对应配置的所有监听
+func (client *ConfigClient) CancelListenConfig(param vo.ConfigParam) (err error) {
+	clientConfig, _ := client.GetClientConfig()
+	key := utils.GetConfigCacheKey(param.DataId, param.Group, clientConfig.NamespaceId)
+	if value, ok := client.listener.cacheMap.LoadAndDelete(key); ok {
+		client.unlistenRemote(value.(*cacheData))
+		client.configProxy.nacosServer.Metrics().AddListenConfigs(-1)
+		if client.restorer != nil {
+			client.restorer.mutex.Lock()
+			delete(client.restorer.restored, key)
+			client.restorer.mutex.Unlock()
+		}
+		client.listener.rebalance()
+		client.saveListening()
+	}
+	return nil
+}
+
+func (client *ConfigClient) startListening() {
+	for i := 0; i < Default_Notify_Worker_Num; i++ {
+		go client.notifyWorker()
+	}
+	go client.longPolling()
+}
+
+// 将配置的回调队列交给worker执行，同一配置的回调不会被多个worker并发执行
+func (client *ConfigClient) scheduleNotify(cd *cacheData) {
+	select {
+	case client.listener.notifyChan <- cd.drain:
+	case <-client.closeChan:
+	}
+}
+
+func (client *ConfigClient) notifyWorker() {
+	for {
+		select {
+		case <-client.closeChan:
+			return
+		case notify := <-client.listener.notifyChan:
+			notify()
+		}
+	}
+}
+
+// 按分片对所有监听的配置发起长轮询，每个分片由独立的协程轮询，一个分片的变化无需等待其他分片的请求返回
+// 监听的配置增减时重新分片，新增的分片立即开始轮询，多余的分片在下一轮结束，已有分片在下一轮请求中使用新的配置
+// 监听信息保存在客户端，服务端重启后会在下一轮请求中重新注册
+func (client *ConfigClient) longPolling() {
+	for {
+		client.startShards(len(client.listeningBatches()))
+		select {
+		case <-client.closeChan:
+			return
+		case <-client.listener.rebalanceChan:
+			client.listener.wake()
+		case <-time.After(listenRetryInterval):
+		}
+	}
+}
+
+// 为序号小于count且未在运行的分片启动长轮询
+func (client *ConfigClient) startShards(count int) {
+	client.listener.shardMutex.Lock()
+	defer client.listener.shardMutex.Unlock()
+	for index := 0; index < count; index++ {
+		if !client.listener.shards[index] {
+			client.listener.shards[index] = true
+			go client.pollShard(index)
+		}
+	}
+}
+
+// 持续对第index个分片发起长轮询，分片不再存在时结束
+// 长连接可用时改为通过长连接注册监听，长连接断开后回到HTTP长轮询
+func (client *ConfigClient) pollShard(index int) {
+	for {
+		select {
+		case <-client.closeChan:
+			return
+		default:
+		}
+		clientConfig, _, agent, err := client.sync()
+		if err != nil {
+			if !client.waitRetry() {
+				return
+			}
+			continue
+		}
+		client.listener.shardMutex.Lock()
+		batches := client.listeningBatches()
+		if index >= len(batches) {
+			delete(client.listener.shards, index)
+			client.listener.shardMutex.Unlock()
+			return
+		}
+		client.listener.shardMutex.Unlock()
+		if transport := client.remoteTransport(); transport != nil {
+			wakeup := client.listener.wakeup()
+			if err := client.listenConfigBatchRemote(transport, batches[index]); err != nil {
+				if !client.waitRetry() {
+					return
+				}
+			} else if !client.waitRemoteListen(clientConfig, wakeup) {
+				return
+			}
+			continue
+		}
+		if err := client.listenConfigBatch(clientConfig, agent, batches[index]); err != nil && !client.waitRetry() {
+			return
+		}
+	}
+}
+
+func (client *ConfigClient) waitRetry() bool {
+	select {
+	case <-client.closeChan:
+		return false
+	case <-time.After(listenRetryInterval):
+		return true
+	}
+}
+
+// 将监听的配置按key排序后分片，配置增减时各分片重新均衡
+func (client *ConfigClient) listeningBatches() [][]*cacheData {
+	clientConfig, _ := client.GetClientConfig()
+	batchSize := perTaskConfigSize
+	if clientConfig.LongPoll != nil && clientConfig.LongPoll.BatchSize > 0 {
+		batchSize = clientConfig.LongPoll.BatchSize
+	}
+	var keys []string
+	all := map[string]*cacheData{}
+	client.listener.cacheMap.Range(func(key, value interface{}) bool {
+		keys = append(keys, key.(string))
+		all[key.(string)] = value.(*cacheData)
+		return true
+	})
+	sort.Strings(keys)
+	var batches [][]*cacheData
+	for i := 0; i < len(keys); i += batchSize {
+		end := i + batchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		batch := make([]*cacheData, 0, end-i)
+		for _, key := range keys[i:end] {
+			batch = append(batch, all[key])
+		}
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// 对一批配置发起一次长轮询，变化的配置重新拉取内容并通知监听者
+func (client *ConfigClient) listenConfigBatch(clientConfig constant.ClientConfig, agent http_agent.IHttpAgent, batch []*cacheData) error {
+	var listeningConfigs strings.Builder
+	for _, cd := range batch {
+		listeningConfigs.WriteString(cd.listeningConfig())
+	}
+	params := map[string]string{constant.KEY_LISTEN_CONFIGS: listeningConfigs.String()}
+
+	var changed string
+	var err error
+	var lastServer string
+	_, span := client.configProxy.nacosServer.Tracer().Start(context.Background(), "POST "+constant.CONFIG_LISTEN_PATH,
+		tracing.Attribute{Key: tracing.ATTR_NAMESPACE, Value: clientConfig.NamespaceId})
+	defer func() {
+		span.SetAttributes(tracing.Attribute{Key: tracing.ATTR_SERVER_ADDRESS, Value: lastServer},
+			tracing.Attribute{Key: tracing.ATTR_CHANGED, Value: strings.TrimSpace(changed)})
+		tracing.End(span, err)
+	}()
+	for _, serverConfig := range client.configProxy.GetServerList() {
+		if err = client.configProxy.nacosServer.WaitRateLimit(context.Background(), rate_limiter.CATEGORY_LONG_POLL); err != nil {
+			break
+		}
+		path := client.buildBasePath(serverConfig) + "/listener"
+		lastServer = net.JoinHostPort(serverConfig.IpAddr, strconv.FormatUint(serverConfig.Port, 10))
+		holdMs, timeoutMs := client.longPollTimeout(clientConfig)
+		securedParams, headers := client.configProxy.nacosServer.SecureConfigRequest(params, clientConfig.AccessKey, clientConfig.SecretKey)
+		changed, err = listen(agent, path, timeoutMs, holdMs, securedParams, headers)
+		if err == nil {
+			client.adaptLongPollTimeout(clientConfig, true)
+			break
+		}
+		if _, ok := err.(*nacos_error.NacosError); ok {
+			break
+		}
+		client.adaptLongPollTimeout(clientConfig, false)
+		logger.Errorf("[client.ListenConfig] listen config error:%s", err.Error())
+	}
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(changed) == "" {
+		logger.Debugf("[client.ListenConfig] no change")
+		return nil
+	}
+	logger.Infof("[client.ListenConfig] config changed:%s", changed)
+	byKey := map[string]*cacheData{}
+	for _, cd := range batch {
+		byKey[cd.dataId+constant.SPLIT_CONFIG_INNER+cd.group] = cd
+	}
+	for _, config := range strings.Split(changed, "%01") {
+		attrs := strings.Split(config, "%02")
+		if len(attrs) < 2 {
+			continue
+		}
+		if cd, ok := byKey[attrs[0]+constant.SPLIT_CONFIG_INNER+attrs[1]]; ok {
+			client.refreshCacheData(cd)
+		}
+	}
+	return nil
+}
+
+// 返回服务端挂起长轮询的时间和请求的超时时间
+func (client *ConfigClient) longPollTimeout(clientConfig constant.ClientConfig) (holdMs uint64, timeoutMs uint64) {
+	if clientConfig.LongPoll == nil {
+		return clientConfig.ListenInterval, clientConfig.TimeoutMs
+	}
+	holdMs = clientConfig.LongPoll.TimeoutMs
+	if holdMs == 0 {
+		holdMs = clientConfig.ListenInterval
+	}
+	if adapted := atomic.LoadUint64(&client.listener.holdMs); clientConfig.LongPoll.Adaptive && adapted > 0 && adapted < holdMs {
+		holdMs = adapted
+	}
+	return holdMs, holdMs + holdMs/2
+}
+
+// 开启自适应时，网络错误后将挂起时间减半，成功后每次恢复配置值的四分之一
+func (client *ConfigClient) adaptLongPollTimeout(clientConfig constant.ClientConfig, success bool) {
+	if clientConfig.LongPoll == nil || !clientConfig.LongPoll.Adaptive {
+		return
+	}
+	holdMs, _ := client.longPollTimeout(clientConfig)
+	maxMs := clientConfig.LongPoll.TimeoutMs
+	if maxMs == 0 {
+		maxMs = clientConfig.ListenInterval
+	}
+	if success {
+		if holdMs >= maxMs {
+			return
+		}
+		holdMs += maxMs / 4
+		if holdMs > maxMs {
+			holdMs = maxMs
+		}
+	} else {
+		holdMs = holdMs / 2
+		if holdMs < Min_Long_Poll_Timeout_Ms {
+			holdMs = Min_Long_Poll_Timeout_Ms
+		}
+		logger.Warnf("[client.ListenConfig] long poll failed, reduce long poll timeout to %dms", holdMs)
+	}
+	atomic.StoreUint64(&client.listener.holdMs, holdMs)
+}
+
+func (client *ConfigClient) refreshCacheData(cd *cacheData) {
+	cd.refreshMutex.Lock()
+	defer cd.refreshMutex.Unlock()
+	ctx, responseInfo := nacos_server.WithResponseInfo(context.Background())
+	// 校验通过后才写入快照，避免服务端不可用时从快照读到被拒绝的内容
+	content, fromServer, err := client.fetchConfig(ctx, vo.ConfigParam{
+		DataId: cd.dataId,
+		Group:  cd.group,
+	})
+	if errors.Is(err, nacos_error.ErrNotFound) {
+		client.deleteCacheData(cd)
+		return
+	}
+	if err != nil {
+		logger.Errorf("[client.updateLocalConfig] update config failed:%s", err.Error())
+		return
+	}
+	if configType := responseConfigType(responseInfo); configType != "" {
+		cd.setConfigType(configType)
+	}
+
+	// md5只在持有refreshMutex时更新，内容未变化或已被拒绝时不重复通知和校验
+	md5 := util.Md5(content)
+	if md5 == cd.getMd5() {
+		if fromServer {
+			client.saveSnapshot(cd.dataId, cd.group, cd.tenant, content)
+		}
+		cd.reject("")
+		return
+	}
+	if md5 == cd.getRejectedMd5() {
+		return
+	}
+	data, _ := client.decrypt(cd.dataId, content)
+	if err := cd.validate(data); err != nil {
+		cd.reject(md5)
+		client.audit(cd, audit.Change_Rejected, cd.getMd5(), md5, responseInfo)
+		logger.Errorf("[client.updateLocalConfig] config rejected by validation, dataId:%s group:%s md5:%s err:%s", cd.dataId, cd.group, md5, err.Error())
+		client.configProxy.nacosServer.Metrics().IncConfigRejected()
+		client.configProxy.nacosServer.Events().Publish(event.ConfigRejectedEvent{Namespace: cd.tenant, Group: cd.group, DataId: cd.dataId, Md5: md5, Err: err})
+		return
+	}
+	if fromServer {
+		client.saveSnapshot(cd.dataId, cd.group, cd.tenant, content)
+	}
+	client.mutex.Lock()
+	client.putLocalConfig(vo.ConfigParam{
+		DataId:  cd.dataId,
+		Group:   cd.group,
+		Content: content,
+	})
+	client.mutex.Unlock()
+	if client.readCache != nil {
+		client.readCache.put(utils.GetConfigCacheKey(cd.dataId, cd.group, cd.tenant), content)
+	}
+	changeType, oldMd5 := model.Config_Change_Modified, cd.getMd5()
+	if oldMd5 == "" {
+		changeType = model.Config_Change_Added
+	}
+	if cd.update(md5, data) {
+		client.scheduleNotify(cd)
+	}
+	client.audit(cd, changeType, oldMd5, md5, responseInfo)
+}
+
+// 服务端删除了配置时清空内容，内容此前已知时通知DELETED事件，删除不经过校验
+func (client *ConfigClient) deleteCacheData(cd *cacheData) {
+	if cd.getMd5() == "" {
+		cd.reject("")
+		return
+	}
+	client.mutex.Lock()
+	for i, config := range client.localConfigs {
+		if config.DataId == cd.dataId && config.Group == cd.group {
+			client.localConfigs = append(client.localConfigs[:i], client.localConfigs[i+1:]...)
+			break
+		}
+	}
+	client.mutex.Unlock()
+	if client.readCache != nil {
+		client.readCache.invalidate(utils.GetConfigCacheKey(cd.dataId, cd.group, cd.tenant))
+	}
+	oldMd5 := cd.getMd5()
+	if cd.update("", "") {
+		client.scheduleNotify(cd)
+	}
+	client.audit(cd, model.Config_Change_Deleted, oldMd5, "", nil)
+}
+
+// 配置了AuditSink时记录配置的变化，responseInfo中为返回新内容的服务端和响应头，为nil或未填充时不记录服务端信息
+func (client *ConfigClient) audit(cd *cacheData, changeType, oldMd5, newMd5 string, responseInfo *nacos_server.ResponseInfo) {
+	clientConfig, _ := client.GetClientConfig()
+	if clientConfig.AuditSink == nil {
+		return
+	}
+	record := audit.Record{
+		Module:     audit.Module_Config,
+		Namespace:  cd.tenant,
+		Group:      cd.group,
+		Name:       cd.dataId,
+		ChangeType: changeType,
+		OldMd5:     oldMd5,
+		NewMd5:     newMd5,
+	}
+	if responseInfo != nil && responseInfo.Header != nil {
+		record.Server = responseInfo.Server
+		record.ServerTime = lastModifiedMillis(responseInfo.Header.Get("Last-Modified"))
+	}
+	audit.Write(clientConfig.AuditSink, record)
+}
+
+// 服务端以HTTP日期格式返回Last-Modified，经过部分网关时可能为毫秒时间戳，无法解析时返回0
+func lastModifiedMillis(value string) int64 {
+	if value == "" {
+		return 0
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return t.UnixNano() / int64(time.Millisecond)
+	}
+	millis, _ := strconv.ParseInt(value, 10, 64)
+	return millis
+}
+
+// 服务端返回的配置类型，从容灾文件或快照读取时为空
+func responseConfigType(responseInfo *nacos_server.ResponseInfo) string {
+	if responseInfo == nil || responseInfo.Header == nil {
+		return ""
+	}
+	return responseInfo.Header.Get(constant.CONFIG_TYPE_HEADER)
+}
+
+// 监听中的配置最近一次拉取时服务端返回的配置类型，未监听时为空
+func (client *ConfigClient) listenedConfigType(dataId, group string) string {
+	clientConfig, _ := client.GetClientConfig()
+	value, ok := client.listener.cacheMap.Load(utils.GetConfigCacheKey(dataId, group, clientConfig.NamespaceId))
+	if !ok {
+		return ""
+	}
+	return value.(*cacheData).getConfigType()
+}
+
+// 监听的配置最近一次变化未通过校验时，返回上次校验通过的解密后的内容
+func (client *ConfigClient) validatedConfig(dataId, group string) (string, bool) {
+	clientConfig, _ := client.GetClientConfig()
+	value, ok := client.listener.cacheMap.Load(utils.GetConfigCacheKey(dataId, group, clientConfig.NamespaceId))
+	if !ok {
+		return "", false
+	}
+	return value.(*cacheData).validatedData()
+}
@@ -0,0 +1,178 @@
+package config_client
+
+import (
+	"context"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/common/validator"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// 前缀监听定时搜索新配置的默认间隔
+	Default_Prefix_Discovery_Interval = 30 * time.Second
+	// 搜索配置时每页的大小
+	prefixSearchPageSize = 100
+)
+
+func (client *ConfigClient) SearchConfig(param vo.SearchConfigParam) (*model.ConfigPage, error) {
+	return client.SearchConfigWithContext(context.Background(), param)
+}
+
+func (client *ConfigClient) SearchConfigWithContext(ctx context.Context, param vo.SearchConfigParam) (*model.ConfigPage, error) {
+	clientConfig, _ := client.GetClientConfig()
+	if len(param.NamespaceId) == 0 {
+		param.NamespaceId = clientConfig.NamespaceId
+	}
+	return client.configProxy.SearchConfigProxy(ctx, param, param.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+}
+
+// 监听group下dataId以DataIdPrefix开头的所有配置，之后新创建的匹配配置也会被监听
+func (client *ConfigClient) ListenConfigWithPrefix(param vo.ConfigPrefixParam) error {
+	return client.ListenConfigWithPrefixWithContext(context.Background(), param)
+}
+
+// ctx 结束后取消本次注册的所有监听并停止搜索新配置
+func (client *ConfigClient) ListenConfigWithPrefixWithContext(ctx context.Context, param vo.ConfigPrefixParam) error {
+	v := validator.New("ListenConfigWithPrefix").DataId("dataIdPrefix", param.DataIdPrefix).Group("group", param.Group)
+	if param.OnChange == nil {
+		v.Add("onChange", "can not be nil")
+	}
+	if err := v.Err(); err != nil {
+		return err
+	}
+	interval := param.DiscoveryInterval
+	if interval <= 0 {
+		interval = Default_Prefix_Discovery_Interval
+	}
+	return client.startPrefixListener(ctx, &prefixListener{
+		method: "ListenConfigWithPrefix",
+		client: client,
+		param:  param,
+		ids:    map[string]int64{},
+	}, interval)
+}
+
+// 监听分组下的所有配置，之后新创建的配置也会被监听，适用于将应用的配置都放在同一分组下的场景
+func (client *ConfigClient) ListenConfigGroup(param vo.ConfigGroupParam) error {
+	return client.ListenConfigGroupWithContext(context.Background(), param)
+}
+
+// ctx 结束后取消本次注册的所有监听并停止搜索新配置
+func (client *ConfigClient) ListenConfigGroupWithContext(ctx context.Context, param vo.ConfigGroupParam) error {
+	v := validator.New("ListenConfigGroup").Group("group", param.Group)
+	if param.OnChangeEvent == nil {
+		v.Add("onChangeEvent", "can not be nil")
+	}
+	if err := v.Err(); err != nil {
+		return err
+	}
+	interval := param.DiscoveryInterval
+	if interval <= 0 {
+		interval = Default_Prefix_Discovery_Interval
+	}
+	return client.startPrefixListener(ctx, &prefixListener{
+		method:        "ListenConfigGroup",
+		client:        client,
+		param:         vo.ConfigPrefixParam{Group: param.Group},
+		onChangeEvent: param.OnChangeEvent,
+		ids:           map[string]int64{},
+	}, interval)
+}
+
+func (client *ConfigClient) startPrefixListener(ctx context.Context, pl *prefixListener, interval time.Duration) error {
+	if err := pl.discover(ctx); err != nil {
+		return err
+	}
+	go pl.run(ctx, interval)
+	return nil
+}
+
+// 一次前缀或分组监听，DataIdPrefix为空时监听整个分组，ids记录已注册监听的dataId及其监听id
+type prefixListener struct {
+	mutex         sync.Mutex
+	method        string
+	client        *ConfigClient
+	param         vo.ConfigPrefixParam
+	onChangeEvent func(event model.ConfigChangeEvent)
+	ids           map[string]int64
+}
+
+// 搜索所有匹配的配置，并为尚未监听的dataId注册监听
+func (pl *prefixListener) discover(ctx context.Context) error {
+	dataIds, err := pl.client.searchDataIds(ctx, pl.param.Group, pl.param.DataIdPrefix)
+	if err != nil {
+		return err
+	}
+	pl.mutex.Lock()
+	defer pl.mutex.Unlock()
+	for _, dataId := range dataIds {
+		if _, ok := pl.ids[dataId]; ok {
+			continue
+		}
+		pl.ids[dataId] = pl.client.addListener(vo.ConfigParam{
+			DataId:        dataId,
+			Group:         pl.param.Group,
+			OnChange:      pl.param.OnChange,
+			OnChangeEvent: pl.onChangeEvent,
+		})
+		logger.Infof("[client.%s] listen config dataId:%s group:%s", pl.method, dataId, pl.param.Group)
+	}
+	return nil
+}
+
+func (pl *prefixListener) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := pl.discover(ctx); err != nil {
+				logger.Warnf("[client.%s] discover config failed, prefix:%s group:%s err:%s",
+					pl.method, pl.param.DataIdPrefix, pl.param.Group, err.Error())
+			}
+		case <-ctx.Done():
+			pl.cancel()
+			return
+		case <-pl.client.closeChan:
+			return
+		}
+	}
+}
+
+func (pl *prefixListener) cancel() {
+	pl.mutex.Lock()
+	defer pl.mutex.Unlock()
+	for dataId, id := range pl.ids {
+		pl.client.removeListener(dataId, pl.param.Group, id)
+	}
+	pl.ids = map[string]int64{}
+}
+
+// 按前缀模糊搜索并翻页，prefix为空时返回分组下的所有配置，服务端的模糊匹配可能多返回结果，因此在本地再按前缀过滤
+func (client *ConfigClient) searchDataIds(ctx context.Context, group, prefix string) ([]string, error) {
+	var dataIds []string
+	for pageNo := uint32(1); ; pageNo++ {
+		page, err := client.SearchConfigWithContext(ctx, vo.SearchConfigParam{
+			Search:   "blur",
+			DataId:   prefix + "*",
+			Group:    group,
+			PageNo:   pageNo,
+			PageSize: prefixSearchPageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.PageItems {
+			if item.Group == group && strings.HasPrefix(item.DataId, prefix) {
+				dataIds = append(dataIds, item.DataId)
+			}
+		}
+		if len(page.PageItems) < prefixSearchPageSize || int(pageNo) >= page.PagesAvailable {
+			return dataIds, nil
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package config_client
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/mock"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestListenConfigWithPrefix(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	var searched int32
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context, method string, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			assert.Equal(t, "blur", params["search"])
+			assert.Equal(t, "app.*", params["dataId"])
+			if atomic.AddInt32(&searched, 1) == 1 {
+				return http_agent.FakeHttpResponse(200, `{"totalCount":2,"pageNumber":1,"pagesAvailable":1,"pageItems":[`+
+					`{"dataId":"app.db","group":"group"},{"dataId":"xapp.db","group":"group"}]}`), nil
+			}
+			return http_agent.FakeHttpResponse(200, `{"totalCount":2,"pageNumber":1,"pagesAvailable":1,"pageItems":[`+
+				`{"dataId":"app.db","group":"group"},{"dataId":"app.cache","group":"group"}]}`), nil
+		})
+	mockHttpAgent.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			time.Sleep(100 * time.Millisecond)
+			return http_agent.FakeHttpResponse(200, ""), nil
+		})
+	client := createConfigClientTest(t, mockHttpAgent)
+	defer client.Close()
+
+	onChange := func(namespace, group, dataId, data string) {}
+	assert.NotNil(t, client.ListenConfigWithPrefix(vo.ConfigPrefixParam{Group: "group", OnChange: onChange}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := client.ListenConfigWithPrefixWithContext(ctx, vo.ConfigPrefixParam{
+		Group:             "group",
+		DataIdPrefix:      "app.",
+		DiscoveryInterval: 50 * time.Millisecond,
+		OnChange:          onChange,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(client.listeningBatches()[0]))
+
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, 2, len(client.listeningBatches()[0]))
+
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 0, len(client.listeningBatches()))
+}
+
+func TestListenConfigGroup(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	var searched int32
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context, method string, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			assert.Equal(t, "*", params["dataId"])
+			assert.Equal(t, "group", params["group"])
+			if atomic.AddInt32(&searched, 1) == 1 {
+				return http_agent.FakeHttpResponse(200, `{"totalCount":2,"pageNumber":1,"pagesAvailable":1,"pageItems":[`+
+					`{"dataId":"app.db","group":"group"},{"dataId":"app.db","group":"group2"}]}`), nil
+			}
+			return http_agent.FakeHttpResponse(200, `{"totalCount":2,"pageNumber":1,"pagesAvailable":1,"pageItems":[`+
+				`{"dataId":"app.db","group":"group"},{"dataId":"cache.yaml","group":"group"}]}`), nil
+		})
+	mockHttpAgent.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			time.Sleep(100 * time.Millisecond)
+			return http_agent.FakeHttpResponse(200, ""), nil
+		})
+	client := createConfigClientTest(t, mockHttpAgent)
+	defer client.Close()
+
+	onChangeEvent := func(event model.ConfigChangeEvent) {}
+	assert.NotNil(t, client.ListenConfigGroup(vo.ConfigGroupParam{Group: "group"}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := client.ListenConfigGroupWithContext(ctx, vo.ConfigGroupParam{
+		Group:             "group",
+		DiscoveryInterval: 50 * time.Millisecond,
+		OnChangeEvent:     onChangeEvent,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(client.listeningBatches()[0]))
+
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, 2, len(client.listeningBatches()[0]))
+
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 0, len(client.listeningBatches()))
+}
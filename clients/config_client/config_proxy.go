@@ -1,24 +1,35 @@
 package config_client
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"github.com/buger/jsonparser"
 	"github.com/nacos-group/nacos-sdk-go/common/constant"
 	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/common/nacos_error"
 	"github.com/nacos-group/nacos-sdk-go/common/nacos_server"
 	"github.com/nacos-group/nacos-sdk-go/common/util"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/utils"
 	"github.com/nacos-group/nacos-sdk-go/vo"
+	"mime/multipart"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 )
 
 type ConfigProxy struct {
 	nacosServer nacos_server.NacosServer
+	content     *constant.ConfigContentConfig
 }
 
 func NewConfigProxy(serverConfig []constant.ServerConfig, clientConfig constant.ClientConfig, httpAgent http_agent.IHttpAgent) (ConfigProxy, error) {
-	proxy := ConfigProxy{}
+	proxy := ConfigProxy{content: clientConfig.ConfigContent}
 	var err error
-	proxy.nacosServer, err = nacos_server.NewNacosServer(serverConfig, httpAgent, clientConfig.TimeoutMs, clientConfig.Endpoint)
+	proxy.nacosServer, err = nacos_server.NewNacosServer(serverConfig, clientConfig, httpAgent)
 	return proxy, err
 
 }
@@ -27,7 +38,7 @@ func (cp *ConfigProxy) GetServerList() []constant.ServerConfig {
 	return cp.nacosServer.GetServerList()
 }
 
-func (cp *ConfigProxy) GetConfigProxy(param vo.ConfigParam, tenant, accessKey, secretKey string) (string, error) {
+func (cp *ConfigProxy) GetConfigProxy(ctx context.Context, param vo.ConfigParam, tenant, accessKey, secretKey string) (string, error) {
 	params := util.TransformObject2Param(param)
 	if len(tenant) > 0 {
 		params["tenant"] = tenant
@@ -36,13 +47,18 @@ func (cp *ConfigProxy) GetConfigProxy(param vo.ConfigParam, tenant, accessKey, s
 	var headers = map[string]string{}
 	headers["accessKey"] = accessKey
 	headers["secretKey"] = secretKey
+	if cp.content != nil && cp.content.AcceptGzip {
+		headers["Accept-Encoding"] = "gzip"
+	}
 
-	result, err := cp.nacosServer.ReqConfigApi(constant.CONFIG_PATH, params, headers, http.MethodGet)
+	result, err := cp.nacosServer.ReqConfigApi(ctx, constant.CONFIG_PATH, params, headers, http.MethodGet)
 	return result, err
 }
 
-func (cp *ConfigProxy) PublishConfigProxy(param vo.ConfigParam, tenant, accessKey, secretKey string) (bool, error) {
+// 获取正在beta发布的配置内容
+func (cp *ConfigProxy) GetBetaConfigProxy(ctx context.Context, param vo.ConfigParam, tenant, accessKey, secretKey string) (string, error) {
 	params := util.TransformObject2Param(param)
+	params["beta"] = "true"
 	if len(tenant) > 0 {
 		params["tenant"] = tenant
 	}
@@ -50,9 +66,32 @@ func (cp *ConfigProxy) PublishConfigProxy(param vo.ConfigParam, tenant, accessKe
 	var headers = map[string]string{}
 	headers["accessKey"] = accessKey
 	headers["secretKey"] = secretKey
-	result, err := cp.nacosServer.ReqConfigApi(constant.CONFIG_PATH, params, headers, http.MethodPost)
+	result, err := cp.nacosServer.ReqConfigApi(ctx, constant.CONFIG_PATH, params, headers, http.MethodGet)
+	if err != nil {
+		return "", err
+	}
+	content, err := jsonparser.GetString([]byte(result), "data", "content")
 	if err != nil {
-		return false, errors.New("[client.PublishConfig] publish config failed:" + err.Error())
+		return "", nacos_error.NewNacosError(strconv.Itoa(http.StatusNotFound), "[client.GetConfig] beta config not found:"+result, nil)
+	}
+	return content, nil
+}
+
+func (cp *ConfigProxy) PublishConfigProxy(ctx context.Context, param vo.ConfigParam, tenant, accessKey, secretKey string) (bool, error) {
+	params := util.TransformObject2Param(param)
+	if len(tenant) > 0 {
+		params["tenant"] = tenant
+	}
+
+	var headers = map[string]string{}
+	headers["accessKey"] = accessKey
+	headers["secretKey"] = secretKey
+	if len(param.BetaIps) > 0 {
+		headers["betaIps"] = param.BetaIps
+	}
+	result, err := cp.publish(ctx, params, headers)
+	if err != nil {
+		return false, nacos_error.Wrap("[client.PublishConfig] publish config failed", err)
 	}
 	if strings.ToLower(strings.Trim(result, " ")) == "true" {
 		return true, nil
@@ -61,17 +100,84 @@ func (cp *ConfigProxy) PublishConfigProxy(param vo.ConfigParam, tenant, accessKe
 	}
 }
 
-func (cp *ConfigProxy) DeleteConfigProxy(param vo.ConfigParam, tenant, accessKey, secretKey string) (bool, error) {
+// 服务端内容的md5与casMd5一致时才写入，否则返回ErrConfigCasConflict
+// 服务端以200和非true的响应，或以409、RESOURCE_CONFLICT错误报告冲突，冲突不重试
+func (cp *ConfigProxy) PublishConfigCasProxy(ctx context.Context, param vo.ConfigParam, tenant, accessKey, secretKey string) (bool, error) {
 	params := util.TransformObject2Param(param)
 	if len(tenant) > 0 {
 		params["tenant"] = tenant
 	}
+
 	var headers = map[string]string{}
 	headers["accessKey"] = accessKey
 	headers["secretKey"] = secretKey
-	result, err := cp.nacosServer.ReqConfigApi(constant.CONFIG_PATH, params, headers, http.MethodDelete)
+	headers["casMd5"] = param.CasMd5
+	result, err := cp.publish(ctx, params, headers)
+	if nacos_error.IsConflict(err) {
+		return false, ErrConfigCasConflict
+	}
 	if err != nil {
-		return false, errors.New("[client.DeleteConfig] deleted config failed:" + err.Error())
+		return false, nacos_error.Wrap("[client.PublishConfigCas] publish config failed", err)
+	}
+	if strings.ToLower(strings.Trim(result, " ")) == "true" {
+		return true, nil
+	}
+	return false, ErrConfigCasConflict
+}
+
+// 内容达到CompressThreshold时，content以gzip压缩的表单作为请求体，其余参数拼接在url中
+func (cp *ConfigProxy) publish(ctx context.Context, params map[string]string, headers map[string]string) (string, error) {
+	if cp.content == nil || cp.content.CompressThreshold <= 0 || len(params["content"]) < cp.content.CompressThreshold {
+		return cp.nacosServer.ReqConfigApi(ctx, constant.CONFIG_PATH, params, headers, http.MethodPost)
+	}
+	form := url.Values{}
+	form.Set("content", params["content"])
+	body, err := utils.CompressData([]byte(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	urlParams := make(map[string]string, len(params))
+	for k, v := range params {
+		if k != "content" {
+			urlParams[k] = v
+		}
+	}
+	headers["Content-Type"] = "application/x-www-form-urlencoded;charset=UTF-8"
+	headers["Content-Encoding"] = "gzip"
+	return cp.nacosServer.ReqConfigApiWithBody(ctx, constant.CONFIG_PATH, urlParams, headers, body, http.MethodPost)
+}
+
+// 停止beta发布，所有客户端恢复使用正式配置
+func (cp *ConfigProxy) StopBetaProxy(ctx context.Context, param vo.ConfigParam, tenant, accessKey, secretKey string) (bool, error) {
+	params := util.TransformObject2Param(param)
+	params["beta"] = "true"
+	if len(tenant) > 0 {
+		params["tenant"] = tenant
+	}
+	var headers = map[string]string{}
+	headers["accessKey"] = accessKey
+	headers["secretKey"] = secretKey
+	result, err := cp.nacosServer.ReqConfigApi(ctx, constant.CONFIG_PATH, params, headers, http.MethodDelete)
+	if err != nil {
+		return false, nacos_error.Wrap("[client.StopBeta] stop beta failed", err)
+	}
+	if stopped, err := jsonparser.GetBoolean([]byte(result), "data"); err == nil && stopped {
+		return true, nil
+	}
+	return false, errors.New("[client.StopBeta] stop beta failed:" + result)
+}
+
+func (cp *ConfigProxy) DeleteConfigProxy(ctx context.Context, param vo.ConfigParam, tenant, accessKey, secretKey string) (bool, error) {
+	params := util.TransformObject2Param(param)
+	if len(tenant) > 0 {
+		params["tenant"] = tenant
+	}
+	var headers = map[string]string{}
+	headers["accessKey"] = accessKey
+	headers["secretKey"] = secretKey
+	result, err := cp.nacosServer.ReqConfigApi(ctx, constant.CONFIG_PATH, params, headers, http.MethodDelete)
+	if err != nil {
+		return false, nacos_error.Wrap("[client.DeleteConfig] deleted config failed", err)
 	}
 	if strings.ToLower(strings.Trim(result, " ")) == "true" {
 		return true, nil
@@ -79,3 +185,132 @@ func (cp *ConfigProxy) DeleteConfigProxy(param vo.ConfigParam, tenant, accessKey
 		return false, errors.New("[client.DeleteConfig] deleted config failed: " + string(result))
 	}
 }
+
+// 分页搜索配置，Search为空时使用blur模糊搜索
+func (cp *ConfigProxy) SearchConfigProxy(ctx context.Context, param vo.SearchConfigParam, tenant, accessKey, secretKey string) (*model.ConfigPage, error) {
+	if len(param.Search) == 0 {
+		param.Search = "blur"
+	}
+	params := util.TransformObject2Param(param)
+	if len(tenant) > 0 {
+		params["tenant"] = tenant
+	}
+	var headers = map[string]string{}
+	headers["accessKey"] = accessKey
+	headers["secretKey"] = secretKey
+	result, err := cp.nacosServer.ReqConfigApi(ctx, constant.CONFIG_PATH, params, headers, http.MethodGet)
+	if err != nil {
+		return nil, nacos_error.Wrap("[client.SearchConfig] search config failed", err)
+	}
+	var page model.ConfigPage
+	if err = json.Unmarshal([]byte(result), &page); err != nil {
+		return nil, nacos_error.Wrap("[client.SearchConfig] parse search result failed", err)
+	}
+	return &page, nil
+}
+
+func (cp *ConfigProxy) GetConfigHistoryProxy(ctx context.Context, param vo.ConfigHistoryParam, tenant, accessKey, secretKey string) (*model.ConfigHistoryPage, error) {
+	params := util.TransformObject2Param(param)
+	params["search"] = "accurate"
+	if len(tenant) > 0 {
+		params["tenant"] = tenant
+	}
+	var headers = map[string]string{}
+	headers["accessKey"] = accessKey
+	headers["secretKey"] = secretKey
+	result, err := cp.nacosServer.ReqConfigApi(ctx, constant.CONFIG_HISTORY_PATH, params, headers, http.MethodGet)
+	if err != nil {
+		return nil, nacos_error.Wrap("[client.GetConfigHistory] get config history failed", err)
+	}
+	var page model.ConfigHistoryPage
+	if err = json.Unmarshal([]byte(result), &page); err != nil {
+		return nil, nacos_error.Wrap("[client.GetConfigHistory] parse config history failed", err)
+	}
+	return &page, nil
+}
+
+// previous为false时获取param.Id对应的历史，为true时获取该历史的上一条历史
+func (cp *ConfigProxy) GetConfigHistoryDetailProxy(ctx context.Context, param vo.ConfigHistoryDetailParam, previous bool, tenant, accessKey, secretKey string) (*model.ConfigHistory, error) {
+	params := map[string]string{
+		"dataId": param.DataId,
+		"group":  param.Group,
+	}
+	path := constant.CONFIG_HISTORY_PATH
+	if previous {
+		path += "/previous"
+		params["id"] = param.Id
+	} else {
+		params["nid"] = param.Id
+	}
+	if len(tenant) > 0 {
+		params["tenant"] = tenant
+	}
+	var headers = map[string]string{}
+	headers["accessKey"] = accessKey
+	headers["secretKey"] = secretKey
+	result, err := cp.nacosServer.ReqConfigApi(ctx, path, params, headers, http.MethodGet)
+	if err != nil {
+		return nil, nacos_error.Wrap("[client.GetConfigHistory] get config history <"+param.Id+"> failed", err)
+	}
+	var history model.ConfigHistory
+	if err = json.Unmarshal([]byte(result), &history); err != nil || len(history.Id) == 0 {
+		return nil, nacos_error.NewNacosError(strconv.Itoa(http.StatusNotFound), "[client.GetConfigHistory] config history <"+param.Id+"> not found:"+result, err)
+	}
+	return &history, nil
+}
+
+// 服务端返回与控制台导出一致的zip
+func (cp *ConfigProxy) ExportConfigProxy(ctx context.Context, param vo.ExportConfigParam, accessKey, secretKey string) ([]byte, error) {
+	params := util.TransformObject2Param(param)
+	params["export"] = "true"
+	var headers = map[string]string{}
+	headers["accessKey"] = accessKey
+	headers["secretKey"] = secretKey
+	result, err := cp.nacosServer.ReqConfigApi(ctx, constant.CONFIG_PATH, params, headers, http.MethodGet)
+	if err != nil {
+		return nil, nacos_error.Wrap("[client.ExportConfigs] export configs failed", err)
+	}
+	return []byte(result), nil
+}
+
+func (cp *ConfigProxy) ImportConfigProxy(ctx context.Context, param vo.ImportConfigParam, accessKey, secretKey string) (*model.ConfigImportResult, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "nacos_config_import.zip")
+	if err != nil {
+		return nil, err
+	}
+	if _, err = part.Write(param.Content); err != nil {
+		return nil, err
+	}
+	if err = writer.Close(); err != nil {
+		return nil, err
+	}
+	params := map[string]string{
+		"import": "true",
+		"policy": param.Policy,
+	}
+	if len(param.NamespaceId) > 0 {
+		params["namespace"] = param.NamespaceId
+	}
+	var headers = map[string]string{}
+	headers["accessKey"] = accessKey
+	headers["secretKey"] = secretKey
+	headers["Content-Type"] = writer.FormDataContentType()
+	result, err := cp.nacosServer.ReqConfigApiWithBody(ctx, constant.CONFIG_PATH, params, headers, body.Bytes(), http.MethodPost)
+	if err != nil {
+		return nil, nacos_error.Wrap("[client.ImportConfigs] import configs failed", err)
+	}
+	var response struct {
+		Code    int                      `json:"code"`
+		Message string                   `json:"message"`
+		Data    model.ConfigImportResult `json:"data"`
+	}
+	if err = json.Unmarshal([]byte(result), &response); err != nil {
+		return nil, nacos_error.Wrap("[client.ImportConfigs] parse import result failed: "+result, err)
+	}
+	if response.Code != http.StatusOK {
+		return &response.Data, nacos_error.NewNacosError(strconv.Itoa(response.Code), "[client.ImportConfigs] import configs failed: "+response.Message, nil)
+	}
+	return &response.Data, nil
+}
@@ -0,0 +1,144 @@
+package config_client
+
+import (
+	"context"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"sync"
+	"time"
+)
+
+const Default_Config_Cache_Max_Entries = 1024
+
+type configCacheEntry struct {
+	content  string
+	expireAt time.Time
+}
+
+// 正在进行的一次获取，同一配置的其他请求等待其完成并共享结果
+// 每次获取为该key的一代，invalidate或put后fetching中不再是该次获取，新的请求发起新一代获取
+type configFetch struct {
+	done    chan struct{}
+	content string
+	err     error
+}
+
+// GetConfig的内存缓存，缓存服务端返回的未解密内容，获取失败的结果不缓存，在ConfigClient的值拷贝之间共享
+type configReadCache struct {
+	ttl        time.Duration
+	maxEntries int
+	mutex      sync.Mutex
+	entries    map[string]configCacheEntry
+	fetching   map[string]*configFetch
+}
+
+func newConfigReadCache(config *constant.ConfigCacheConfig) *configReadCache {
+	if config == nil {
+		return nil
+	}
+	maxEntries := config.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = Default_Config_Cache_Max_Entries
+	}
+	return &configReadCache{
+		ttl:        time.Duration(config.TtlMs) * time.Millisecond,
+		maxEntries: maxEntries,
+		entries:    map[string]configCacheEntry{},
+		fetching:   map[string]*configFetch{},
+	}
+}
+
+// 缓存未过期时直接返回，否则合并同一key的并发请求，只调用一次fetch
+// fetch使用脱离调用方取消的ctx执行，各调用方只按自己的ctx停止等待，不影响其他等待者
+func (c *configReadCache) get(ctx context.Context, key string, fetch func(ctx context.Context) (string, error)) (string, error) {
+	c.mutex.Lock()
+	if entry, ok := c.entries[key]; ok && time.Now().Before(entry.expireAt) {
+		c.mutex.Unlock()
+		return entry.content, nil
+	}
+	f, ok := c.fetching[key]
+	if !ok {
+		f = &configFetch{done: make(chan struct{})}
+		c.fetching[key] = f
+		go c.fetch(detachedContext{ctx}, key, f, fetch)
+	}
+	c.mutex.Unlock()
+	select {
+	case <-f.done:
+		return f.content, f.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// 获取期间key被invalidate或put时，该次获取已被取代，结果只返回给等待者而不写入缓存
+func (c *configReadCache) fetch(ctx context.Context, key string, f *configFetch, fetch func(ctx context.Context) (string, error)) {
+	f.content, f.err = fetch(ctx)
+	c.mutex.Lock()
+	if c.fetching[key] == f {
+		delete(c.fetching, key)
+		if f.err == nil {
+			c.putLocked(key, f.content)
+		}
+	}
+	c.mutex.Unlock()
+	close(f.done)
+}
+
+// 监听到配置变化时更新缓存
+func (c *configReadCache) put(key string, content string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.fetching, key)
+	c.putLocked(key, content)
+}
+
+func (c *configReadCache) putLocked(key string, content string) {
+	if c.ttl <= 0 {
+		return
+	}
+	now := time.Now()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expireAt) {
+				delete(c.entries, k)
+			}
+		}
+		// 仍然超出上限时任意淘汰一个
+		for k := range c.entries {
+			if len(c.entries) < c.maxEntries {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = configCacheEntry{content: content, expireAt: now.Add(c.ttl)}
+}
+
+// 发布或删除配置后使缓存失效
+func (c *configReadCache) invalidate(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.entries, key)
+	delete(c.fetching, key)
+}
+
+// 保留ctx中的值，但不随其取消或超时，请求超时由TimeoutMs控制
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
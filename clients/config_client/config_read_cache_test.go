@@ -0,0 +1,140 @@
+package config_client
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/mock"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_configReadCache_Singleflight(t *testing.T) {
+	c := newConfigReadCache(&constant.ConfigCacheConfig{TtlMs: 60 * 1000})
+	var fetches int32
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (string, error) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		return "content", nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			content, err := c.get(context.Background(), "key", fetch)
+			assert.Nil(t, err)
+			assert.Equal(t, "content", content)
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches), "concurrent gets should share one fetch")
+
+	content, err := c.get(context.Background(), "key", fetch)
+	assert.Nil(t, err)
+	assert.Equal(t, "content", content)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches), "cached content should be returned before ttl")
+
+	c.invalidate("key")
+	_, _ = c.get(context.Background(), "key", fetch)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+}
+
+func Test_configReadCache_CancelAndInvalidate(t *testing.T) {
+	c := newConfigReadCache(&constant.ConfigCacheConfig{TtlMs: 60 * 1000})
+	release := make(chan struct{})
+	started := make(chan struct{})
+	fetch := func(ctx context.Context) (string, error) {
+		close(started)
+		<-release
+		// 发起请求的调用方已取消，共享的获取不受影响
+		return "stale", ctx.Err()
+	}
+
+	// 首个调用方取消只影响自身，其他等待者仍拿到结果
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := c.get(ctx, "key", fetch)
+		first <- err
+	}()
+	<-started
+	second := make(chan string, 1)
+	go func() {
+		content, err := c.get(context.Background(), "key", nil)
+		assert.Nil(t, err)
+		second <- content
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	assert.Equal(t, context.Canceled, <-first)
+
+	// 获取期间失效，旧结果不写入缓存，新的请求重新获取
+	c.invalidate("key")
+	close(release)
+	assert.Equal(t, "stale", <-second)
+	content, err := c.get(context.Background(), "key", func(ctx context.Context) (string, error) { return "fresh", nil })
+	assert.Nil(t, err)
+	assert.Equal(t, "fresh", content)
+}
+
+func Test_configReadCache_ErrorAndEviction(t *testing.T) {
+	c := newConfigReadCache(&constant.ConfigCacheConfig{TtlMs: 60 * 1000, MaxEntries: 2})
+	_, err := c.get(context.Background(), "key", func(ctx context.Context) (string, error) { return "", errors.New("unavailable") })
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, len(c.entries), "failed fetch should not be cached")
+
+	for _, key := range []string{"a", "b", "c"} {
+		c.put(key, key)
+	}
+	assert.Equal(t, 2, len(c.entries))
+	assert.Equal(t, "c", c.entries["c"].content)
+
+	assert.Nil(t, newConfigReadCache(nil))
+	noTtl := newConfigReadCache(&constant.ConfigCacheConfig{})
+	noTtl.put("a", "a")
+	assert.Equal(t, 0, len(noTtl.entries), "only concurrent fetches are merged when ttl is 0")
+}
+
+func Test_GetConfigWithReadCache(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	gomock.InOrder(
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(http_agent.FakeHttpResponse(200, "content1"), nil),
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(http_agent.FakeHttpResponse(200, "true"), nil),
+		mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(http_agent.FakeHttpResponse(200, "content2"), nil),
+	)
+	client := createConfigClientTest(t, mockHttpAgent, func(clientConfig *constant.ClientConfig) {
+		clientConfig.ConfigCache = &constant.ConfigCacheConfig{TtlMs: 60 * 1000}
+	})
+	defer client.Close()
+
+	param := vo.ConfigParam{DataId: "dataId", Group: "group"}
+	for i := 0; i < 3; i++ {
+		content, err := client.GetConfig(param)
+		assert.Nil(t, err)
+		assert.Equal(t, "content1", content)
+	}
+	// 发布成功后缓存失效
+	success, err := client.PublishConfig(vo.ConfigParam{DataId: "dataId", Group: "group", Content: "content2"})
+	assert.Nil(t, err)
+	assert.True(t, success)
+	content, err := client.GetConfig(param)
+	assert.Nil(t, err)
+	assert.Equal(t, "content2", content)
+}
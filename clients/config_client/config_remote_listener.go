@@ -0,0 +1,110 @@
+package config_client
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/common/remote"
+	"github.com/nacos-group/nacos-sdk-go/utils"
+	"time"
+)
+
+// 配置了ClientConfig.Transport时，监听的配置通过长连接注册，配置的变化由服务端通过长连接通知，
+// 收到通知后仍通过HTTP拉取配置内容；长连接断开期间使用HTTP长轮询，重连后重新注册监听
+
+// 注册配置变化通知的处理函数，重连后立即重新注册所有分片的监听
+func (client *ConfigClient) startRemoteListener(transport remote.Transport) {
+	transport.RegisterHandler(remote.TYPE_CONFIG_CHANGE_NOTIFY, client.handleConfigChangeNotify)
+	transport.OnReconnected(client.listener.wake)
+}
+
+func (client *ConfigClient) remoteTransport() remote.Transport {
+	if transport := client.configProxy.nacosServer.Transport(); transport != nil && transport.Connected() {
+		return transport
+	}
+	return nil
+}
+
+// 处理服务端的配置变化通知，只刷新正在监听的配置
+func (client *ConfigClient) handleConfigChangeNotify(body []byte) {
+	var request remote.ConfigChangeNotifyRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		logger.Errorf("[client.ListenConfig] failed to unmarshal config change notify:%s,err:%s", string(body), err.Error())
+		return
+	}
+	clientConfig, _ := client.GetClientConfig()
+	if namespaceOrDefault(request.Tenant) != namespaceOrDefault(clientConfig.NamespaceId) {
+		return
+	}
+	value, ok := client.listener.cacheMap.Load(utils.GetConfigCacheKey(request.DataId, request.Group, clientConfig.NamespaceId))
+	if !ok {
+		return
+	}
+	select {
+	case <-client.closeChan:
+		return
+	default:
+	}
+	logger.Infof("[client.ListenConfig] config changed, dataId:%s group:%s tenant:%s", request.DataId, request.Group, request.Tenant)
+	// 不阻塞长连接上其他推送的处理
+	go client.refreshCacheData(value.(*cacheData))
+}
+
+// 通过长连接注册一批配置的监听，md5已与服务端不一致的配置立即重新拉取
+func (client *ConfigClient) listenConfigBatchRemote(transport remote.Transport, batch []*cacheData) error {
+	request := remote.ConfigBatchListenRequest{Listen: true}
+	byKey := map[string]*cacheData{}
+	for _, cd := range batch {
+		request.ConfigListenContexts = append(request.ConfigListenContexts, remote.ConfigListenContext{
+			DataId: cd.dataId, Group: cd.group, Tenant: cd.tenant, Md5: cd.listeningMd5(),
+		})
+		byKey[cd.dataId+constant.SPLIT_CONFIG_INNER+cd.group] = cd
+	}
+	var response remote.ConfigChangeBatchListenResponse
+	if err := transport.Request(context.Background(), remote.TYPE_CONFIG_BATCH_LISTEN, request, &response); err != nil {
+		logger.Errorf("[client.ListenConfig] listen config with long connection error:%s", err.Error())
+		return err
+	}
+	for _, changed := range response.ChangedConfigs {
+		if cd, ok := byKey[changed.DataId+constant.SPLIT_CONFIG_INNER+changed.Group]; ok {
+			logger.Infof("[client.ListenConfig] config changed, dataId:%s group:%s", changed.DataId, changed.Group)
+			client.refreshCacheData(cd)
+		}
+	}
+	return nil
+}
+
+// 取消监听的配置通知服务端不再推送其变化，长连接不可用时无需通知
+func (client *ConfigClient) unlistenRemote(cd *cacheData) {
+	transport := client.remoteTransport()
+	if transport == nil {
+		return
+	}
+	request := remote.ConfigBatchListenRequest{ConfigListenContexts: []remote.ConfigListenContext{
+		{DataId: cd.dataId, Group: cd.group, Tenant: cd.tenant},
+	}}
+	var response remote.ConfigChangeBatchListenResponse
+	if err := transport.Request(context.Background(), remote.TYPE_CONFIG_BATCH_LISTEN, request, &response); err != nil {
+		logger.Warnf("[client.CancelListenConfig] cancel listening dataId:%s group:%s with long connection error:%s", cd.dataId, cd.group, err.Error())
+	}
+}
+
+// 通过长连接监听时，每隔ListenInterval重新注册一次监听，与服务端核对md5，监听的配置增减或重连后立即重新注册
+func (client *ConfigClient) waitRemoteListen(clientConfig constant.ClientConfig, wakeup <-chan struct{}) bool {
+	select {
+	case <-client.closeChan:
+		return false
+	case <-wakeup:
+		return true
+	case <-time.After(time.Duration(clientConfig.ListenInterval) * time.Millisecond):
+		return true
+	}
+}
+
+func namespaceOrDefault(namespace string) string {
+	if namespace == "" {
+		return constant.DEFAULT_NAMESPACE_ID
+	}
+	return namespace
+}
@@ -0,0 +1,133 @@
+package config_client
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/common/remote"
+	"github.com/nacos-group/nacos-sdk-go/common/util"
+	"github.com/nacos-group/nacos-sdk-go/mock"
+	"github.com/nacos-group/nacos-sdk-go/utils"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func withTransportTest(transport *remote.FakeTransport) func(clientConfig *constant.ClientConfig) {
+	return func(clientConfig *constant.ClientConfig) {
+		clientConfig.Transport = transport.Factory()
+	}
+}
+
+func listenRequestsTest(transport *remote.FakeTransport) []remote.ConfigBatchListenRequest {
+	var requests []remote.ConfigBatchListenRequest
+	for _, body := range transport.Requests(remote.TYPE_CONFIG_BATCH_LISTEN) {
+		var request remote.ConfigBatchListenRequest
+		json.Unmarshal(body, &request)
+		requests = append(requests, request)
+	}
+	return requests
+}
+
+func Test_listenConfigBatchRemote_Change(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	transport := remote.NewFakeTransport()
+	transport.OnRequest = func(requestType string, request []byte) (interface{}, error) {
+		return remote.ConfigChangeBatchListenResponse{
+			Response:       remote.Response{ResultCode: remote.Result_Success},
+			ChangedConfigs: []remote.ConfigContext{{DataId: "dataId", Group: "group"}},
+		}, nil
+	}
+	client := createConfigClientTest(t, mockHttpAgent, withTransportTest(transport))
+	defer client.Close()
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(1).Return(http_agent.FakeHttpResponse(200, "content2"), nil)
+
+	var configData string
+	err := client.listenConfigBatchRemote(client.remoteTransport(), []*cacheData{
+		newCacheDataTest("", "content", func(namespace, group, dataId, data string) {
+			configData = data
+		})})
+	assert.Nil(t, err)
+	requests := listenRequestsTest(transport)
+	assert.Equal(t, 1, len(requests))
+	assert.True(t, requests[0].Listen)
+	assert.Equal(t, []remote.ConfigListenContext{{DataId: "dataId", Group: "group", Md5: util.Md5("content")}}, requests[0].ConfigListenContexts)
+	assert.Equal(t, 1, len(client.listener.notifyChan))
+	(<-client.listener.notifyChan)()
+	assert.Equal(t, "content2", configData)
+}
+
+func Test_handleConfigChangeNotify(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	transport := remote.NewFakeTransport()
+	client := createConfigClientTest(t, mockHttpAgent, withTransportTest(transport))
+	defer client.Close()
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(1).Return(http_agent.FakeHttpResponse(200, "content2"), nil)
+	cd := newCacheDataTest("", "content", func(namespace, group, dataId, data string) {})
+	client.listener.cacheMap.Store(utils.GetConfigCacheKey("dataId", "group", ""), cd)
+
+	// 其他命名空间和未监听配置的通知被忽略
+	assert.Nil(t, transport.Push(remote.TYPE_CONFIG_CHANGE_NOTIFY, remote.ConfigChangeNotifyRequest{DataId: "dataId", Group: "group", Tenant: "dev"}))
+	assert.Nil(t, transport.Push(remote.TYPE_CONFIG_CHANGE_NOTIFY, remote.ConfigChangeNotifyRequest{DataId: "other", Group: "group"}))
+	assert.Nil(t, transport.Push(remote.TYPE_CONFIG_CHANGE_NOTIFY, remote.ConfigChangeNotifyRequest{DataId: "dataId", Group: "group"}))
+	select {
+	case notify := <-client.listener.notifyChan:
+		notify()
+	case <-time.After(3 * time.Second):
+		t.Fatal("listener is not notified")
+	}
+	assert.Equal(t, util.Md5("content2"), cd.getMd5())
+}
+
+func Test_CancelListenConfigWithTransport(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	transport := remote.NewFakeTransport()
+	client := createConfigClientTest(t, mockHttpAgent, withTransportTest(transport))
+	defer client.Close()
+	client.listener.cacheMap.Store(utils.GetConfigCacheKey("dataId", "group", ""),
+		newCacheDataTest("", "content", func(namespace, group, dataId, data string) {}))
+
+	assert.Nil(t, client.CancelListenConfig(vo.ConfigParam{DataId: "dataId", Group: "group"}))
+	requests := listenRequestsTest(transport)
+	assert.Equal(t, 1, len(requests))
+	assert.False(t, requests[0].Listen)
+	assert.Equal(t, []remote.ConfigListenContext{{DataId: "dataId", Group: "group"}}, requests[0].ConfigListenContexts)
+}
+
+func Test_ListenConfigFallBackToHttp(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	// 服务端为1.x版本，无法建立长连接
+	transport := remote.NewFakeTransport()
+	transport.StartErr = errors.New("connection refused")
+	client := createConfigClientTest(t, mockHttpAgent, withTransportTest(transport))
+	defer client.Close()
+	assert.Nil(t, client.configProxy.nacosServer.Transport())
+	assert.Nil(t, client.remoteTransport())
+	assert.True(t, transport.Closed())
+
+	// 连接断开后同样回到HTTP长轮询
+	transport = remote.NewFakeTransport()
+	client = createConfigClientTest(t, mockHttpAgent, withTransportTest(transport))
+	defer client.Close()
+	assert.NotNil(t, client.remoteTransport())
+	transport.Disconnect()
+	assert.Nil(t, client.remoteTransport())
+}
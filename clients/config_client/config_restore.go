@@ -0,0 +1,102 @@
+package config_client
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/clients/cache"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/utils"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"sort"
+	"sync"
+)
+
+// 保存当前监听的配置，客户端重启后恢复，见constant.RestoreSubscriptionsConfig
+type listenRestorer struct {
+	mutex  sync.Mutex
+	file   string
+	config constant.RestoreSubscriptionsConfig
+	// 从文件恢复的监听，key为配置缓存key，value为监听id，用户重新监听同一配置后移除
+	restored map[string]int64
+}
+
+func newListenRestorer(clientConfig constant.ClientConfig) *listenRestorer {
+	if clientConfig.RestoreSubscriptions == nil {
+		return nil
+	}
+	return &listenRestorer{
+		file:     cache.GetSubscriptionFile(clientConfig.CacheDir, "config", clientConfig.NamespaceId),
+		config:   *clientConfig.RestoreSubscriptions,
+		restored: map[string]int64{},
+	}
+}
+
+// 重新监听上次运行时保存的配置，用户已监听的配置不再重复监听
+func (client *ConfigClient) restoreListening() {
+	r := client.restorer
+	subscriptions, err := cache.ReadSubscriptions(r.file)
+	if err != nil {
+		logger.Errorf("[client.restoreListening] read subscriptions from %s failed:%s", r.file, err.Error())
+		return
+	}
+	clientConfig, _ := client.GetClientConfig()
+	restored := 0
+	for _, s := range subscriptions.Configs {
+		key := utils.GetConfigCacheKey(s.DataId, s.Group, clientConfig.NamespaceId)
+		if _, ok := client.listener.cacheMap.Load(key); ok {
+			continue
+		}
+		id := client.addListener(vo.ConfigParam{DataId: s.DataId, Group: s.Group,
+			OnChange: func(namespace, group, dataId, data string) {
+				if r.config.OnConfigChange != nil {
+					r.config.OnConfigChange(namespace, group, dataId, data)
+				}
+			}})
+		r.mutex.Lock()
+		r.restored[key] = id
+		r.mutex.Unlock()
+		restored++
+	}
+	logger.Infof("[client.restoreListening] restore %d listening configs from %s", restored, r.file)
+}
+
+// 用户监听了恢复的配置后移除恢复时注册的回调，id为本次注册的监听
+func (client *ConfigClient) releaseRestoredListener(dataId, group string, id int64) {
+	if client.restorer == nil {
+		return
+	}
+	clientConfig, _ := client.GetClientConfig()
+	key := utils.GetConfigCacheKey(dataId, group, clientConfig.NamespaceId)
+	client.restorer.mutex.Lock()
+	restoredId, ok := client.restorer.restored[key]
+	if ok && restoredId != id {
+		delete(client.restorer.restored, key)
+	}
+	client.restorer.mutex.Unlock()
+	if ok && restoredId != id {
+		client.removeListener(dataId, group, restoredId)
+	}
+}
+
+// 将当前监听的配置写入文件
+func (client *ConfigClient) saveListening() {
+	if client.restorer == nil {
+		return
+	}
+	var subscriptions cache.Subscriptions
+	client.listener.cacheMap.Range(func(key, value interface{}) bool {
+		cd := value.(*cacheData)
+		subscriptions.Configs = append(subscriptions.Configs, cache.ConfigSubscription{DataId: cd.dataId, Group: cd.group})
+		return true
+	})
+	sort.Slice(subscriptions.Configs, func(i, j int) bool {
+		if subscriptions.Configs[i].Group != subscriptions.Configs[j].Group {
+			return subscriptions.Configs[i].Group < subscriptions.Configs[j].Group
+		}
+		return subscriptions.Configs[i].DataId < subscriptions.Configs[j].DataId
+	})
+	client.restorer.mutex.Lock()
+	defer client.restorer.mutex.Unlock()
+	if err := cache.WriteSubscriptions(client.restorer.file, subscriptions); err != nil {
+		logger.Errorf("[client.saveListening] write subscriptions to %s failed:%s", client.restorer.file, err.Error())
+	}
+}
@@ -0,0 +1,121 @@
+package config_client
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/nacos-group/nacos-sdk-go/clients/cache"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/common/util"
+	"github.com/nacos-group/nacos-sdk-go/mock"
+	"github.com/nacos-group/nacos-sdk-go/utils"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 重启前后的客户端共用同一缓存目录
+func withRestoreSubscriptionsTest(cacheDir string, restore *constant.RestoreSubscriptionsConfig) func(clientConfig *constant.ClientConfig) {
+	return func(clientConfig *constant.ClientConfig) {
+		clientConfig.CacheDir = cacheDir
+		clientConfig.RestoreSubscriptions = restore
+	}
+}
+
+func Test_RestoreListening(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	mockHttpAgent.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			time.Sleep(100 * time.Millisecond)
+			return http_agent.FakeHttpResponse(200, ""), nil
+		})
+	cacheDir, _ := ioutil.TempDir("", "nacos-config")
+	defer os.RemoveAll(cacheDir)
+	file := cache.GetSubscriptionFile(cacheDir, "config", "")
+	onChange := func(namespace, group, dataId, data string) {}
+
+	client := createConfigClientTest(t, mockHttpAgent, withRestoreSubscriptionsTest(cacheDir, &constant.RestoreSubscriptionsConfig{}))
+	assert.Nil(t, client.ListenConfig(vo.ConfigParam{DataId: "dataId", Group: "group", OnChange: onChange}))
+	subscriptions, err := cache.ReadSubscriptions(file)
+	assert.Nil(t, err)
+	assert.Equal(t, []cache.ConfigSubscription{{DataId: "dataId", Group: "group"}}, subscriptions.Configs)
+	client.Close()
+
+	// 重启后自动恢复监听，变化通过OnConfigChange回调
+	// 第一个客户端的协程仍持有其指针，使用新变量避免覆盖
+	changed := make(chan string, 1)
+	restored := createConfigClientTest(t, mockHttpAgent, withRestoreSubscriptionsTest(cacheDir, &constant.RestoreSubscriptionsConfig{
+		OnConfigChange: func(namespace, group, dataId, data string) {
+			changed <- dataId + "=" + data
+		},
+	}))
+	defer restored.Close()
+	assert.Equal(t, 1, len(restored.listeningBatches()))
+	value, ok := restored.listener.cacheMap.Load(utils.GetConfigCacheKey("dataId", "group", ""))
+	assert.True(t, ok)
+	cd := value.(*cacheData)
+	if cd.update(util.Md5("content"), "content") {
+		restored.scheduleNotify(cd)
+	}
+	select {
+	case data := <-changed:
+		assert.Equal(t, "dataId=content", data)
+	case <-time.After(3 * time.Second):
+		t.Fatal("restored listener was not notified")
+	}
+
+	// 重新监听后移除恢复的回调，取消监听后不再保存
+	assert.Nil(t, restored.ListenConfig(vo.ConfigParam{DataId: "dataId", Group: "group", OnChange: onChange}))
+	cd.mutex.Lock()
+	assert.Equal(t, 1, len(cd.listeners))
+	cd.mutex.Unlock()
+	assert.Nil(t, restored.CancelListenConfig(vo.ConfigParam{DataId: "dataId", Group: "group"}))
+	subscriptions, err = cache.ReadSubscriptions(file)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(subscriptions.Configs))
+}
+
+func Test_RestoreListeningUpdatesReturnedClient(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	var polled int32
+	mockHttpAgent.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			if atomic.AddInt32(&polled, 1) == 1 {
+				return http_agent.FakeHttpResponse(200, "dataId%02group%01"), nil
+			}
+			time.Sleep(100 * time.Millisecond)
+			return http_agent.FakeHttpResponse(200, ""), nil
+		})
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		AnyTimes().DoAndReturn(func(ctx, method, path, header, timeoutMs, params interface{}) (*http.Response, error) {
+		return http_agent.FakeHttpResponse(200, "content"), nil
+	})
+	cacheDir, _ := ioutil.TempDir("", "nacos-config")
+	defer os.RemoveAll(cacheDir)
+	assert.Nil(t, cache.WriteSubscriptions(cache.GetSubscriptionFile(cacheDir, "config", ""),
+		cache.Subscriptions{Configs: []cache.ConfigSubscription{{DataId: "dataId", Group: "group"}}}))
+
+	// 恢复的监听在后台协程中更新的是调用方持有的客户端
+	client := createConfigClientTest(t, mockHttpAgent, withRestoreSubscriptionsTest(cacheDir, &constant.RestoreSubscriptionsConfig{}))
+	defer client.Close()
+	var content string
+	for i := 0; i < 100 && content == ""; i++ {
+		time.Sleep(10 * time.Millisecond)
+		client.mutex.Lock()
+		for _, config := range client.localConfigs {
+			if config.DataId == "dataId" && config.Group == "group" {
+				content = config.Content
+			}
+		}
+		client.mutex.Unlock()
+	}
+	assert.Equal(t, "content", content)
+}
@@ -0,0 +1,112 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	nsema "github.com/toolkits/concurrent/semaphore"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// 跨分组、跨命名空间查询的默认并发数
+const Default_Cross_Query_Concurrency = 5
+
+// 跨分组、跨命名空间查询中失败的部分，key为分组名或命名空间id
+type CrossQueryError struct {
+	Errors map[string]error
+}
+
+func (e *CrossQueryError) Error() string {
+	keys := make([]string, 0, len(e.Errors))
+	for key := range e.Errors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	msgs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		msgs = append(msgs, fmt.Sprintf("%s:%s", key, e.Errors[key].Error()))
+	}
+	return fmt.Sprintf("%d of cross query failed: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// 在多个分组中并发查询同一个服务，返回分组名到实例列表的映射
+// 部分分组查询失败时返回成功分组的结果和*CrossQueryError，空分组名按DEFAULT_GROUP返回
+func SelectInstancesInGroups(ctx context.Context, client naming_client.INamingClient, param vo.SelectInstancesInGroupsParam) (map[string][]model.Instance, error) {
+	groupNames := make([]string, 0, len(param.GroupNames))
+	for _, groupName := range param.GroupNames {
+		if groupName == "" {
+			groupName = constant.DEFAULT_GROUP
+		}
+		groupNames = append(groupNames, groupName)
+	}
+	return crossQuery(groupNames, param.Concurrency, func(groupName string) ([]model.Instance, error) {
+		return client.SelectInstancesWithContext(ctx, vo.SelectInstancesParam{
+			Clusters:    param.Clusters,
+			ServiceName: param.ServiceName,
+			GroupName:   groupName,
+			HealthyOnly: param.HealthyOnly,
+			Selector:    param.Selector,
+		})
+	})
+}
+
+// 在多个命名空间中并发查询同一个服务，返回命名空间id到实例列表的映射
+// 部分命名空间查询失败时返回成功命名空间的结果和*CrossQueryError
+func (mc *MultiTenantClient) SelectInstancesInNamespaces(ctx context.Context, param vo.SelectInstancesInNamespacesParam) (map[string][]model.Instance, error) {
+	return crossQuery(param.NamespaceIds, param.Concurrency, func(namespaceId string) ([]model.Instance, error) {
+		client, err := mc.NamingClient(namespaceId)
+		if err != nil {
+			return nil, err
+		}
+		return client.SelectInstancesWithContext(ctx, vo.SelectInstancesParam{
+			Clusters:    param.Clusters,
+			ServiceName: param.ServiceName,
+			GroupName:   param.GroupName,
+			HealthyOnly: param.HealthyOnly,
+			Selector:    param.Selector,
+		})
+	})
+}
+
+// 以不超过concurrency的并发数对每个key执行查询，重复的key只查询一次
+func crossQuery(keys []string, concurrency int, query func(key string) ([]model.Instance, error)) (map[string][]model.Instance, error) {
+	if concurrency <= 0 {
+		concurrency = Default_Cross_Query_Concurrency
+	}
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	result := map[string][]model.Instance{}
+	errs := map[string]error{}
+	seen := map[string]bool{}
+	sema := nsema.NewSemaphore(concurrency)
+	for _, key := range keys {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		wg.Add(1)
+		sema.Acquire()
+		go func(key string) {
+			defer wg.Done()
+			defer sema.Release()
+			instances, err := query(key)
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				errs[key] = err
+			} else {
+				result[key] = instances
+			}
+		}(key)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return result, &CrossQueryError{Errors: errs}
+	}
+	return result, nil
+}
@@ -0,0 +1,89 @@
+package clients
+
+import (
+	"context"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/mock"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestSelectInstancesInGroups(t *testing.T) {
+	client := mock.NewFakeNamingClient()
+	for _, param := range []vo.RegisterInstanceParam{
+		{ServiceName: "order", Ip: "10.0.0.10", Port: 80, Weight: 1, Enable: true, Healthy: true},
+		{ServiceName: "order", GroupName: "staging", Ip: "10.0.1.10", Port: 80, Weight: 1, Enable: true, Healthy: true},
+		{ServiceName: "order", GroupName: "staging", Ip: "10.0.1.11", Port: 80, Weight: 1, Enable: true, Healthy: false},
+	} {
+		_, err := client.RegisterInstance(param)
+		assert.Nil(t, err)
+	}
+
+	result, err := SelectInstancesInGroups(context.Background(), client, vo.SelectInstancesInGroupsParam{
+		ServiceName: "order",
+		GroupNames:  []string{"", "staging", "prod", "staging"},
+		HealthyOnly: true,
+		Concurrency: 2,
+	})
+	assert.NotNil(t, err)
+	crossErr, ok := err.(*CrossQueryError)
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(crossErr.Errors))
+	assert.NotNil(t, crossErr.Errors["prod"])
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, "10.0.0.10", result[constant.DEFAULT_GROUP][0].Ip)
+	assert.Equal(t, 1, len(result["staging"]))
+	assert.Equal(t, "10.0.1.10", result["staging"][0].Ip)
+
+	result, err = SelectInstancesInGroups(context.Background(), client, vo.SelectInstancesInGroupsParam{
+		ServiceName: "order",
+		GroupNames:  []string{constant.DEFAULT_GROUP, "staging"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(result["staging"]))
+	assert.Equal(t, "10.0.1.11", result["staging"][0].Ip)
+}
+
+func TestMultiTenantClient_SelectInstancesInNamespaces(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "nacos-cross-query")
+	assert.Nil(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	mc, err := NewMultiTenantClient(map[string]interface{}{
+		constant.KEY_CLIENT_CONFIG: constant.ClientConfig{
+			TimeoutMs:           10 * 1000,
+			ListenInterval:      30 * 1000,
+			CacheDir:            cacheDir,
+			LogDir:              cacheDir,
+			NotLoadCacheAtStart: true,
+			CacheOnly:           true,
+		},
+		constant.KEY_SERVER_CONFIGS: []constant.ServerConfig{{IpAddr: "127.0.0.1", Port: 8848}},
+	})
+	assert.Nil(t, err)
+	defer mc.Close()
+
+	for _, ns := range []string{"dev", "test"} {
+		naming, err := mc.NamingClient(ns)
+		assert.Nil(t, err)
+		assert.Nil(t, naming.UpdateServiceCache(vo.UpdateServiceCacheParam{
+			ServiceName: "order",
+			Hosts:       []model.Instance{{Ip: "10.0.0.1", Port: 80, Weight: 1, Enable: true, Healthy: true, ClusterName: ns}},
+		}))
+	}
+
+	result, err := mc.SelectInstancesInNamespaces(context.Background(), vo.SelectInstancesInNamespacesParam{
+		NamespaceIds: []string{"dev", "test", "prod"},
+		ServiceName:  "order",
+		HealthyOnly:  true,
+	})
+	assert.NotNil(t, err)
+	assert.NotNil(t, err.(*CrossQueryError).Errors["prod"])
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, "dev", result["dev"][0].ClusterName)
+	assert.Equal(t, "test", result["test"][0].ClusterName)
+}
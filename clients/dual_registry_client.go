@@ -0,0 +1,456 @@
+package clients
+
+import (
+	"errors"
+	"github.com/nacos-group/nacos-sdk-go/clients/cache"
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/load_balancer"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/utils"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DualRegistryClient读取服务发现数据的来源
+const (
+	Dual_Read_Primary   = "primary"
+	Dual_Read_Secondary = "secondary"
+	// 合并两个集群的实例，ClusterName、Ip和Port相同的实例以主集群为准
+	Dual_Read_Merged = "merged"
+)
+
+const (
+	// 备集群写失败后的重试间隔
+	Dual_Retry_Interval = 5 * time.Second
+	// 等待写入备集群的操作数上限，超出时直接进入重试
+	dualMirrorQueueSize = 1024
+)
+
+// 备集群的写入状态
+type DualRegistryStatus struct {
+	// 写入备集群累计失败的次数
+	Failures int64
+	// 等待重试的操作数
+	Pending int
+	// 最近一次失败的错误，重试全部成功后清空
+	LastError error
+}
+
+// 在两个nacos集群之间迁移时使用的服务发现客户端
+// 注册、注销和修改实例以主集群的结果为准，并按调用顺序异步写入备集群，备集群失败时在后台重试，不影响主集群；
+// 临时实例在两个集群中分别发送心跳。查询和订阅按ReadMode读取主集群、备集群或两者合并的结果，可在运行时切换
+type DualRegistryClient struct {
+	primary       *naming_client.NamingClient
+	secondary     *naming_client.NamingClient
+	mutex         sync.Mutex
+	readMode      string
+	pending       map[string]dualOperation
+	failures      int64
+	lastErr       error
+	mirrorChan    chan dualOperation
+	retryInterval time.Duration
+	balancerMap   cache.ConcurrentMap
+	subscriptions map[*vo.SubscribeParam]*dualSubscription
+	closeChan     chan struct{}
+	closeOnce     sync.Once
+}
+
+// 对备集群的一次写操作，key相同的操作只保留最后一次
+type dualOperation struct {
+	key string
+	run func() error
+}
+
+// properties与CreateNamingClient相同，作为主集群的配置，备集群使用secondaryServers，
+// 缓存目录为CacheDir下的secondary子目录
+func NewDualRegistryClient(properties map[string]interface{}, secondaryServers []constant.ServerConfig) (*DualRegistryClient, error) {
+	if len(secondaryServers) == 0 {
+		return nil, errors.New("[client.DualRegistryClient] secondary server configs can not be empty")
+	}
+	primary, err := CreateNamingClient(properties)
+	if err != nil {
+		return nil, err
+	}
+	secondaryProperties := copyProperties(properties)
+	secondaryProperties[constant.KEY_SERVER_CONFIGS] = secondaryServers
+	if clientConfig, ok := properties[constant.KEY_CLIENT_CONFIG].(constant.ClientConfig); ok {
+		clientConfig.CacheDir = clientConfig.CacheDir + string(os.PathSeparator) + "secondary"
+		secondaryProperties[constant.KEY_CLIENT_CONFIG] = clientConfig
+	}
+	secondary, err := CreateNamingClient(secondaryProperties)
+	if err != nil {
+		primary.Close()
+		return nil, err
+	}
+	return newDualRegistryClient(primary.(*naming_client.NamingClient), secondary.(*naming_client.NamingClient), Dual_Retry_Interval), nil
+}
+
+func newDualRegistryClient(primary, secondary *naming_client.NamingClient, retryInterval time.Duration) *DualRegistryClient {
+	d := &DualRegistryClient{
+		primary:       primary,
+		secondary:     secondary,
+		readMode:      Dual_Read_Primary,
+		pending:       map[string]dualOperation{},
+		mirrorChan:    make(chan dualOperation, dualMirrorQueueSize),
+		retryInterval: retryInterval,
+		balancerMap:   cache.NewConcurrentMap(),
+		subscriptions: map[*vo.SubscribeParam]*dualSubscription{},
+		closeChan:     make(chan struct{}),
+	}
+	go d.mirrorLoop()
+	return d
+}
+
+// 返回主集群的客户端
+func (d *DualRegistryClient) Primary() naming_client.INamingClient {
+	return d.primary
+}
+
+// 返回备集群的客户端
+func (d *DualRegistryClient) Secondary() naming_client.INamingClient {
+	return d.secondary
+}
+
+func (d *DualRegistryClient) ReadMode() string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.readMode
+}
+
+// 切换查询和订阅的数据来源，已订阅的服务立即以新来源的实例列表回调SubscribeCallback
+func (d *DualRegistryClient) SetReadMode(mode string) error {
+	if mode != Dual_Read_Primary && mode != Dual_Read_Secondary && mode != Dual_Read_Merged {
+		return errors.New("[client.DualRegistryClient] unknown read mode:" + mode)
+	}
+	d.mutex.Lock()
+	d.readMode = mode
+	subscriptions := make([]*dualSubscription, 0, len(d.subscriptions))
+	for _, sub := range d.subscriptions {
+		subscriptions = append(subscriptions, sub)
+	}
+	d.mutex.Unlock()
+	logger.Infof("[client.DualRegistryClient] read mode switched to %s", mode)
+	for _, sub := range subscriptions {
+		sub.notify(mode)
+	}
+	return nil
+}
+
+// 备集群的写入状态
+func (d *DualRegistryClient) SecondaryStatus() DualRegistryStatus {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return DualRegistryStatus{Failures: d.failures, Pending: len(d.pending), LastError: d.lastErr}
+}
+
+// 在主集群注册实例，成功后写入备集群
+func (d *DualRegistryClient) RegisterInstance(param vo.RegisterInstanceParam) (bool, error) {
+	success, err := d.primary.RegisterInstance(param)
+	if err == nil {
+		d.mirror(dualInstanceKey(param.ServiceName, param.GroupName, param.ClusterName, param.Ip, param.Port), func() error {
+			_, err := d.secondary.RegisterInstance(param)
+			return err
+		})
+	}
+	return success, err
+}
+
+// 在主集群注销实例，成功后从备集群注销
+func (d *DualRegistryClient) DeregisterInstance(param vo.DeregisterInstanceParam) (bool, error) {
+	success, err := d.primary.DeregisterInstance(param)
+	if err == nil {
+		d.mirror(dualInstanceKey(param.ServiceName, param.GroupName, param.Cluster, param.Ip, param.Port), func() error {
+			_, err := d.secondary.DeregisterInstance(param)
+			return err
+		})
+	}
+	return success, err
+}
+
+// 在主集群修改实例，成功后修改备集群中的实例
+func (d *DualRegistryClient) UpdateInstance(param vo.UpdateInstanceParam) (bool, error) {
+	success, err := d.primary.UpdateInstance(param)
+	if err == nil {
+		d.mirror("update:"+dualInstanceKey(param.ServiceName, param.GroupName, param.ClusterName, param.Ip, param.Port), func() error {
+			_, err := d.secondary.UpdateInstance(param)
+			return err
+		})
+	}
+	return success, err
+}
+
+func dualInstanceKey(serviceName, groupName, clusterName, ip string, port uint64) string {
+	if groupName == "" {
+		groupName = constant.DEFAULT_GROUP
+	}
+	return utils.GetGroupName(serviceName, groupName) + "#" + clusterName + "#" + ip + ":" + strconv.FormatUint(port, 10)
+}
+
+// 将写操作加入备集群的队列，队列满时直接进入重试
+func (d *DualRegistryClient) mirror(key string, run func() error) {
+	op := dualOperation{key: key, run: run}
+	select {
+	case d.mirrorChan <- op:
+	default:
+		d.mutex.Lock()
+		d.pending[key] = op
+		d.mutex.Unlock()
+	}
+}
+
+func (d *DualRegistryClient) mirrorLoop() {
+	ticker := time.NewTicker(d.retryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.closeChan:
+			return
+		case op := <-d.mirrorChan:
+			d.apply(op)
+		case <-ticker.C:
+			d.retryPending()
+		}
+	}
+}
+
+// 执行一次写操作，失败时记录并等待重试，成功时清除同一key等待重试的旧操作
+func (d *DualRegistryClient) apply(op dualOperation) {
+	err := op.run()
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if err != nil {
+		logger.Warnf("[client.DualRegistryClient] write secondary failed, key:%s err:%s", op.key, err.Error())
+		d.failures++
+		d.lastErr = err
+		d.pending[op.key] = op
+		return
+	}
+	delete(d.pending, op.key)
+	if len(d.pending) == 0 {
+		d.lastErr = nil
+	}
+}
+
+func (d *DualRegistryClient) retryPending() {
+	d.mutex.Lock()
+	ops := make([]dualOperation, 0, len(d.pending))
+	for _, op := range d.pending {
+		ops = append(ops, op)
+	}
+	d.mutex.Unlock()
+	for _, op := range ops {
+		d.apply(op)
+	}
+}
+
+// 按ReadMode查询，合并模式下一个集群失败时只记录日志，两个集群都失败时返回主集群的错误
+func (d *DualRegistryClient) read(api string, query func(client *naming_client.NamingClient) ([]model.Instance, error)) ([]model.Instance, error) {
+	switch d.ReadMode() {
+	case Dual_Read_Secondary:
+		return query(d.secondary)
+	case Dual_Read_Merged:
+		primary, err := query(d.primary)
+		secondary, e := query(d.secondary)
+		if err != nil && e != nil {
+			return nil, err
+		}
+		if err != nil {
+			logger.Warnf("[client.DualRegistryClient] %s from primary failed:%s", api, err.Error())
+		}
+		if e != nil {
+			logger.Warnf("[client.DualRegistryClient] %s from secondary failed:%s", api, e.Error())
+		}
+		return mergeInstances(primary, secondary), nil
+	default:
+		return query(d.primary)
+	}
+}
+
+func mergeInstances(primary, secondary []model.Instance) []model.Instance {
+	merged := make([]model.Instance, 0, len(primary)+len(secondary))
+	exists := map[string]bool{}
+	for _, instances := range [][]model.Instance{primary, secondary} {
+		for _, instance := range instances {
+			key := instance.ClusterName + "#" + instance.Ip + ":" + strconv.FormatUint(instance.Port, 10)
+			if !exists[key] {
+				exists[key] = true
+				merged = append(merged, instance)
+			}
+		}
+	}
+	return merged
+}
+
+func (d *DualRegistryClient) SelectAllInstances(param vo.SelectAllInstancesParam) ([]model.Instance, error) {
+	return d.read("SelectAllInstances", func(client *naming_client.NamingClient) ([]model.Instance, error) {
+		return client.SelectAllInstances(param)
+	})
+}
+
+func (d *DualRegistryClient) SelectInstances(param vo.SelectInstancesParam) ([]model.Instance, error) {
+	return d.read("SelectInstances", func(client *naming_client.NamingClient) ([]model.Instance, error) {
+		return client.SelectInstances(param)
+	})
+}
+
+// 合并模式下从两个集群合并后的健康实例中选择一个
+func (d *DualRegistryClient) SelectOneHealthyInstance(param vo.SelectOneHealthInstanceParam) (*model.Instance, error) {
+	switch d.ReadMode() {
+	case Dual_Read_Primary:
+		return d.primary.SelectOneHealthyInstance(param)
+	case Dual_Read_Secondary:
+		return d.secondary.SelectOneHealthyInstance(param)
+	}
+	if param.GroupName == "" {
+		param.GroupName = constant.DEFAULT_GROUP
+	}
+	candidates, err := d.SelectInstances(vo.SelectInstancesParam{
+		ServiceName: param.ServiceName,
+		GroupName:   param.GroupName,
+		Clusters:    param.Clusters,
+		HealthyOnly: true,
+		Selector:    param.Selector,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, errors.New("healthy instance list is empty!")
+	}
+	balancer := param.LoadBalancer
+	if balancer == nil {
+		key := utils.GetServiceCacheKey(utils.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
+		d.balancerMap.SetIfAbsent(key, load_balancer.NewSmoothWeightedRoundRobinBalancer())
+		value, _ := d.balancerMap.Get(key)
+		balancer = value.(load_balancer.LoadBalancer)
+	}
+	instance := balancer.Select(candidates)
+	return &instance, nil
+}
+
+// 同一服务在两个集群的订阅，按ReadMode将对应集群的实例列表回调给订阅者
+type dualSubscription struct {
+	mutex    sync.Mutex
+	param    *vo.SubscribeParam
+	services [2][]model.SubscribeService
+	received [2]bool
+	params   [2]*vo.SubscribeParam
+}
+
+func dualIncluded(mode string, index int) bool {
+	return mode == Dual_Read_Merged || (index == 0) == (mode != Dual_Read_Secondary)
+}
+
+// 在两个集群订阅服务，SubscribeCallback收到ReadMode对应的实例列表，
+// ChangeCallback只通知ReadMode包含的集群的变化，合并模式下两个集群的变化分别通知
+func (d *DualRegistryClient) Subscribe(param *vo.SubscribeParam) error {
+	sub := &dualSubscription{param: param}
+	for i := range sub.params {
+		index := i
+		clusterParam := *param
+		if param.SubscribeCallback != nil {
+			clusterParam.SubscribeCallback = func(services []model.SubscribeService, err error) {
+				mode := d.ReadMode()
+				if err != nil {
+					if dualIncluded(mode, index) {
+						param.SubscribeCallback(nil, err)
+					}
+					return
+				}
+				sub.update(index, services)
+				if dualIncluded(mode, index) {
+					sub.notify(mode)
+				}
+			}
+		}
+		if callback := param.ChangeCallback; callback != nil {
+			clusterParam.ChangeCallback = func(event model.InstanceChangeEvent) {
+				if dualIncluded(d.ReadMode(), index) {
+					callback(event)
+				}
+			}
+		}
+		sub.params[i] = &clusterParam
+	}
+	d.mutex.Lock()
+	d.subscriptions[param] = sub
+	d.mutex.Unlock()
+	err := d.primary.Subscribe(sub.params[0])
+	if e := d.secondary.Subscribe(sub.params[1]); e != nil {
+		logger.Warnf("[client.DualRegistryClient] subscribe secondary failed:%s", e.Error())
+	}
+	return err
+}
+
+func (s *dualSubscription) update(index int, services []model.SubscribeService) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.services[index] = services
+	s.received[index] = true
+}
+
+// 以mode对应的实例列表回调SubscribeCallback，尚未收到对应集群的实例列表时不回调
+func (s *dualSubscription) notify(mode string) {
+	if s.param.SubscribeCallback == nil {
+		return
+	}
+	s.mutex.Lock()
+	var services []model.SubscribeService
+	received := false
+	for i := range s.services {
+		if !dualIncluded(mode, i) || !s.received[i] {
+			continue
+		}
+		received = true
+		services = mergeSubscribeServices(services, s.services[i])
+	}
+	s.mutex.Unlock()
+	if received {
+		s.param.SubscribeCallback(services, nil)
+	}
+}
+
+func mergeSubscribeServices(merged, services []model.SubscribeService) []model.SubscribeService {
+	exists := map[string]bool{}
+	for _, service := range merged {
+		exists[service.ClusterName+"#"+service.Ip+":"+strconv.FormatUint(service.Port, 10)] = true
+	}
+	for _, service := range services {
+		if !exists[service.ClusterName+"#"+service.Ip+":"+strconv.FormatUint(service.Port, 10)] {
+			merged = append(merged, service)
+		}
+	}
+	return merged
+}
+
+// 按Subscribe时传入的同一个param取消两个集群的订阅
+func (d *DualRegistryClient) Unsubscribe(param *vo.SubscribeParam) error {
+	d.mutex.Lock()
+	sub, ok := d.subscriptions[param]
+	delete(d.subscriptions, param)
+	d.mutex.Unlock()
+	if !ok {
+		return nil
+	}
+	d.primary.Unsubscribe(sub.params[0])
+	d.secondary.Unsubscribe(sub.params[1])
+	return nil
+}
+
+// 停止写入备集群并关闭两个集群的客户端，尚未写入备集群的操作被丢弃
+func (d *DualRegistryClient) Close() error {
+	d.closeOnce.Do(func() {
+		close(d.closeChan)
+	})
+	err := d.primary.Close()
+	if e := d.secondary.Close(); e != nil {
+		err = e
+	}
+	return err
+}
@@ -0,0 +1,150 @@
+package clients
+
+import (
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/nacos-group/nacos-sdk-go/clients/nacos_client"
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/common/retry"
+	"github.com/nacos-group/nacos-sdk-go/mock"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func createDualNamingClientTest(t *testing.T, clientConfig constant.ClientConfig, agent http_agent.IHttpAgent) *naming_client.NamingClient {
+	nc := &nacos_client.NacosClient{}
+	assert.Nil(t, nc.SetClientConfig(clientConfig))
+	if clientConfig.CacheOnly {
+		assert.Nil(t, nc.SetServerConfig([]constant.ServerConfig{}))
+	} else {
+		assert.Nil(t, nc.SetServerConfig([]constant.ServerConfig{{IpAddr: "console.nacos.io", Port: 80, ContextPath: "/nacos"}}))
+	}
+	if agent == nil {
+		agent = &http_agent.HttpAgent{}
+	}
+	assert.Nil(t, nc.SetHttpAgent(agent))
+	client, err := naming_client.NewNamingClient(nc)
+	assert.Nil(t, err)
+	return &client
+}
+
+func TestDualRegistryClient_MirrorWrites(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cacheDir, err := ioutil.TempDir("", "nacos-dual-registry")
+	assert.Nil(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	primaryAgent := mock.NewMockIHttpAgent(ctrl)
+	secondaryAgent := mock.NewMockIHttpAgent(ctrl)
+	for _, agent := range []*mock.MockIHttpAgent{primaryAgent, secondaryAgent} {
+		agent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPut), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			AnyTimes().Return(http_agent.FakeHttpResponse(200, `{"clientBeatInterval":5000}`), nil)
+	}
+	primaryAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(1).Return(http_agent.FakeHttpResponse(200, `ok`), nil)
+	registered := make(chan struct{})
+	var once sync.Once
+	// 重试成功后连接恢复，RedoService会再次注册实例
+	gomock.InOrder(
+		secondaryAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(nil, errors.New("connection refused")),
+		secondaryAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			MinTimes(1).DoAndReturn(func(...interface{}) (*http.Response, error) {
+			once.Do(func() { close(registered) })
+			return http_agent.FakeHttpResponse(200, `ok`), nil
+		}),
+	)
+
+	clientConfig := constant.ClientConfig{TimeoutMs: 10 * 1000, ListenInterval: 30 * 1000, BeatInterval: 5 * 1000,
+		NotLoadCacheAtStart: true, CacheDir: cacheDir, LogDir: cacheDir, RetryPolicy: &retry.RetryPolicy{MaxAttempts: 1}}
+	primary := createDualNamingClientTest(t, clientConfig, primaryAgent)
+	clientConfig.CacheDir = cacheDir + string(os.PathSeparator) + "secondary"
+	secondary := createDualNamingClientTest(t, clientConfig, secondaryAgent)
+	d := newDualRegistryClient(primary, secondary, 50*time.Millisecond)
+	defer d.Close()
+
+	success, err := d.RegisterInstance(vo.NewInstance("10.0.0.10", 80).Service("DEMO").Build())
+	assert.Nil(t, err)
+	assert.True(t, success)
+	select {
+	case <-registered:
+	case <-time.After(3 * time.Second):
+		t.Fatal("secondary registration not retried")
+	}
+	time.Sleep(20 * time.Millisecond)
+	status := d.SecondaryStatus()
+	assert.Equal(t, int64(1), status.Failures)
+	assert.Equal(t, 0, status.Pending)
+	assert.Nil(t, status.LastError)
+}
+
+func TestDualRegistryClient_ReadMode(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "nacos-dual-registry")
+	assert.Nil(t, err)
+	defer os.RemoveAll(cacheDir)
+	clientConfig := constant.ClientConfig{TimeoutMs: 10 * 1000, ListenInterval: 30 * 1000, NotLoadCacheAtStart: true,
+		CacheOnly: true, CacheDir: cacheDir, LogDir: cacheDir}
+	primary := createDualNamingClientTest(t, clientConfig, nil)
+	clientConfig.CacheDir = cacheDir + string(os.PathSeparator) + "secondary"
+	secondary := createDualNamingClientTest(t, clientConfig, nil)
+	d := newDualRegistryClient(primary, secondary, Dual_Retry_Interval)
+	defer d.Close()
+	// 仅使用本地缓存时只能订阅已缓存的服务
+	assert.Nil(t, primary.UpdateServiceCache(vo.UpdateServiceCacheParam{ServiceName: "DEMO"}))
+	assert.Nil(t, secondary.UpdateServiceCache(vo.UpdateServiceCacheParam{ServiceName: "DEMO"}))
+
+	notified := make(chan []model.SubscribeService, 10)
+	param := &vo.SubscribeParam{ServiceName: "DEMO", SubscribeCallback: func(services []model.SubscribeService, err error) {
+		notified <- services
+	}}
+	assert.Nil(t, d.Subscribe(param))
+	defer d.Unsubscribe(param)
+	waitNotified := func(count int) {
+		for {
+			select {
+			case services := <-notified:
+				if len(services) == count {
+					return
+				}
+			case <-time.After(3 * time.Second):
+				t.Fatalf("%d instances were not notified", count)
+			}
+		}
+	}
+
+	shared := model.Instance{Ip: "10.0.0.1", Port: 80, Weight: 1, Enable: true, Healthy: true}
+	assert.Nil(t, primary.UpdateServiceCache(vo.UpdateServiceCacheParam{ServiceName: "DEMO", Hosts: []model.Instance{shared}}))
+	waitNotified(1)
+	assert.Nil(t, secondary.UpdateServiceCache(vo.UpdateServiceCacheParam{ServiceName: "DEMO", Hosts: []model.Instance{
+		shared, {Ip: "10.0.1.1", Port: 80, Weight: 1, Enable: true, Healthy: true}}}))
+
+	instances, err := d.SelectAllInstances(vo.SelectAllInstancesParam{ServiceName: "DEMO"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(instances))
+
+	assert.NotNil(t, d.SetReadMode("unknown"))
+	assert.Nil(t, d.SetReadMode(Dual_Read_Secondary))
+	// 切换读取来源时以新来源的实例列表回调订阅者
+	waitNotified(2)
+	instances, err = d.SelectAllInstances(vo.SelectAllInstancesParam{ServiceName: "DEMO"})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(instances))
+
+	assert.Nil(t, d.SetReadMode(Dual_Read_Merged))
+	instances, err = d.SelectAllInstances(vo.SelectAllInstancesParam{ServiceName: "DEMO"})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(instances))
+	instance, err := d.SelectOneHealthyInstance(vo.SelectOneHealthInstanceParam{ServiceName: "DEMO"})
+	assert.Nil(t, err)
+	assert.NotNil(t, instance)
+}
@@ -0,0 +1,307 @@
+package clients
+
+import (
+	"errors"
+	"github.com/nacos-group/nacos-sdk-go/clients/cache"
+	"github.com/nacos-group/nacos-sdk-go/clients/nacos_client"
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/load_balancer"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/utils"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"os"
+	"strings"
+	"sync"
+)
+
+// 实例元数据中记录实例来自哪个nacos集群的key
+const Source_Cluster_Metadata_Key = "nacos.cluster"
+
+// 一个nacos集群，通常对应一个可用区或地域
+type ClusterConfig struct {
+	// 集群名，用于标记实例来源，不能重复
+	Name string
+	// 集群所在的可用区，实例元数据中没有可用区时以此为准
+	Zone          string
+	ServerConfigs []constant.ServerConfig
+}
+
+// 同时访问多个nacos集群的服务发现客户端
+// 查询和订阅合并各集群的实例列表，实例元数据中记录来源集群；设置ClientConfig.Locality时优先选择同可用区的实例
+type MultiClusterClient struct {
+	mutex         sync.Mutex
+	clusters      []ClusterConfig
+	clients       []*naming_client.NamingClient
+	loadBalancer  load_balancer.LoadBalancer
+	locality      *load_balancer.LocalityConfig
+	zoneKey       string
+	balancerMap   cache.ConcurrentMap
+	subscriptions map[*vo.SubscribeParam]*multiClusterSubscription
+}
+
+// properties与CreateNamingClient相同，服务端配置取自各集群的ServerConfigs
+// 各集群使用CacheDir下以集群名命名的子目录作为缓存目录
+func NewMultiClusterClient(properties map[string]interface{}, clusters []ClusterConfig) (*MultiClusterClient, error) {
+	if len(clusters) == 0 {
+		return nil, errors.New("[client.MultiClusterClient] clusters can not be empty")
+	}
+	names := map[string]bool{}
+	for _, cluster := range clusters {
+		if cluster.Name == "" || names[cluster.Name] {
+			return nil, errors.New("[client.MultiClusterClient] cluster name is empty or duplicated:" + cluster.Name)
+		}
+		names[cluster.Name] = true
+	}
+	properties = copyProperties(properties)
+	properties[constant.KEY_SERVER_CONFIGS] = []constant.ServerConfig{}
+	nacosClient, err := setConfig(properties)
+	if err != nil {
+		return nil, err
+	}
+	clientConfig, _ := nacosClient.GetClientConfig()
+	mc := &MultiClusterClient{
+		clusters:      clusters,
+		loadBalancer:  clientConfig.LoadBalancer,
+		locality:      clientConfig.Locality,
+		zoneKey:       load_balancer.Default_Zone_Metadata_Key,
+		balancerMap:   cache.NewConcurrentMap(),
+		subscriptions: map[*vo.SubscribeParam]*multiClusterSubscription{},
+	}
+	if clientConfig.Locality != nil && clientConfig.Locality.MetadataKey != "" {
+		mc.zoneKey = clientConfig.Locality.MetadataKey
+	}
+	// 合并后统一按可用区选择，各集群的客户端不再单独区分
+	clientConfig.Locality = nil
+	for _, cluster := range clusters {
+		config := clientConfig
+		config.CacheDir = clientConfig.CacheDir + string(os.PathSeparator) + cluster.Name
+		nc := &nacos_client.NacosClient{}
+		if err = nc.SetClientConfig(config); err == nil {
+			err = nc.SetServerConfig(cluster.ServerConfigs)
+		}
+		if err == nil {
+			err = setHttpAgent(nc)
+		}
+		var naming naming_client.NamingClient
+		if err == nil {
+			naming, err = naming_client.NewNamingClient(nc)
+		}
+		if err != nil {
+			mc.Close()
+			return nil, err
+		}
+		mc.clients = append(mc.clients, &naming)
+	}
+	return mc, nil
+}
+
+func copyProperties(properties map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(properties)+1)
+	for k, v := range properties {
+		copied[k] = v
+	}
+	return copied
+}
+
+// 返回指定集群的服务发现客户端，用于在某个集群注册实例等操作
+func (mc *MultiClusterClient) NamingClient(cluster string) (naming_client.INamingClient, error) {
+	for i, c := range mc.clusters {
+		if c.Name == cluster {
+			return mc.clients[i], nil
+		}
+	}
+	return nil, errors.New("[client.MultiClusterClient] cluster not found:" + cluster)
+}
+
+// 为实例记录来源集群，实例元数据中没有可用区时使用集群的可用区，不修改缓存中的实例
+func (mc *MultiClusterClient) tag(cluster ClusterConfig, instances []model.Instance) []model.Instance {
+	tagged := make([]model.Instance, len(instances))
+	for i, instance := range instances {
+		instance.Metadata = mc.tagMetadata(cluster, instance.Metadata)
+		tagged[i] = instance
+	}
+	return tagged
+}
+
+func (mc *MultiClusterClient) tagMetadata(cluster ClusterConfig, metadata map[string]string) map[string]string {
+	tagged := make(map[string]string, len(metadata)+2)
+	for k, v := range metadata {
+		tagged[k] = v
+	}
+	tagged[Source_Cluster_Metadata_Key] = cluster.Name
+	if _, ok := tagged[mc.zoneKey]; !ok && cluster.Zone != "" {
+		tagged[mc.zoneKey] = cluster.Zone
+	}
+	return tagged
+}
+
+// 合并各集群的查询结果，部分集群失败时只记录日志，全部失败时返回最后一个错误
+func (mc *MultiClusterClient) merge(api string, query func(client *naming_client.NamingClient) ([]model.Instance, error)) ([]model.Instance, error) {
+	var result []model.Instance
+	var err error
+	failed := 0
+	for i, client := range mc.clients {
+		instances, e := query(client)
+		if e != nil {
+			logger.Warnf("[client.MultiClusterClient] %s from cluster:%s failed:%s", api, mc.clusters[i].Name, e.Error())
+			err = e
+			failed++
+			continue
+		}
+		result = append(result, mc.tag(mc.clusters[i], instances)...)
+	}
+	if failed == len(mc.clients) {
+		return nil, err
+	}
+	return result, nil
+}
+
+// 合并各集群的所有实例
+func (mc *MultiClusterClient) SelectAllInstances(param vo.SelectAllInstancesParam) ([]model.Instance, error) {
+	return mc.merge("SelectAllInstances", func(client *naming_client.NamingClient) ([]model.Instance, error) {
+		return client.SelectAllInstances(param)
+	})
+}
+
+// 合并各集群的实例
+func (mc *MultiClusterClient) SelectInstances(param vo.SelectInstancesParam) ([]model.Instance, error) {
+	return mc.merge("SelectInstances", func(client *naming_client.NamingClient) ([]model.Instance, error) {
+		return client.SelectInstances(param)
+	})
+}
+
+// 从各集群的健康实例中选择一个，设置ClientConfig.Locality时优先选择同可用区的实例，同可用区的实例不足时溢出到其他可用区
+func (mc *MultiClusterClient) SelectOneHealthyInstance(param vo.SelectOneHealthInstanceParam) (*model.Instance, error) {
+	if param.GroupName == "" {
+		param.GroupName = constant.DEFAULT_GROUP
+	}
+	candidates, err := mc.SelectInstances(vo.SelectInstancesParam{
+		ServiceName: param.ServiceName,
+		GroupName:   param.GroupName,
+		Clusters:    param.Clusters,
+		HealthyOnly: true,
+		Selector:    param.Selector,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, errors.New("healthy instance list is empty!")
+	}
+	balancer := param.LoadBalancer
+	if balancer == nil {
+		balancer = mc.loadBalancer
+	}
+	if balancer == nil {
+		// 默认每个服务使用独立的平滑加权轮询
+		key := utils.GetServiceCacheKey(utils.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
+		mc.balancerMap.SetIfAbsent(key, load_balancer.NewSmoothWeightedRoundRobinBalancer())
+		value, _ := mc.balancerMap.Get(key)
+		balancer = value.(load_balancer.LoadBalancer)
+	}
+	if mc.locality != nil {
+		balancer = load_balancer.NewZoneAwareBalancer(balancer, *mc.locality)
+	}
+	instance := balancer.Select(candidates)
+	return &instance, nil
+}
+
+type multiClusterSubscription struct {
+	mutex    sync.Mutex
+	services [][]model.SubscribeService
+	params   []*vo.SubscribeParam
+}
+
+// 在所有集群订阅服务，SubscribeCallback收到合并后的实例列表，ChangeCallback按集群分别通知
+// 回调中的实例元数据记录来源集群，部分集群订阅失败时在后台重试，全部失败时返回最后一个错误
+func (mc *MultiClusterClient) Subscribe(param *vo.SubscribeParam) error {
+	sub := &multiClusterSubscription{services: make([][]model.SubscribeService, len(mc.clients))}
+	for i := range mc.clients {
+		cluster := mc.clusters[i]
+		index := i
+		clusterParam := *param
+		if callback := param.SubscribeCallback; callback != nil {
+			clusterParam.SubscribeCallback = func(services []model.SubscribeService, err error) {
+				if err != nil {
+					callback(nil, err)
+					return
+				}
+				callback(sub.merge(index, mc.tagSubscribeServices(cluster, services)), nil)
+			}
+		}
+		if callback := param.ChangeCallback; callback != nil {
+			clusterParam.ChangeCallback = func(event model.InstanceChangeEvent) {
+				event.Added = mc.tag(cluster, event.Added)
+				event.Removed = mc.tag(cluster, event.Removed)
+				event.Modified = mc.tag(cluster, event.Modified)
+				callback(event)
+			}
+		}
+		sub.params = append(sub.params, &clusterParam)
+	}
+	mc.mutex.Lock()
+	mc.subscriptions[param] = sub
+	mc.mutex.Unlock()
+	var err error
+	failed := 0
+	for i, client := range mc.clients {
+		if e := client.Subscribe(sub.params[i]); e != nil {
+			logger.Warnf("[client.MultiClusterClient] subscribe cluster:%s failed:%s", mc.clusters[i].Name, e.Error())
+			err = e
+			failed++
+		}
+	}
+	if failed == len(mc.clients) {
+		return err
+	}
+	return nil
+}
+
+// 记录集群的最新实例列表并返回所有集群合并后的列表
+func (s *multiClusterSubscription) merge(index int, services []model.SubscribeService) []model.SubscribeService {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.services[index] = services
+	var merged []model.SubscribeService
+	for _, clusterServices := range s.services {
+		merged = append(merged, clusterServices...)
+	}
+	return merged
+}
+
+func (mc *MultiClusterClient) tagSubscribeServices(cluster ClusterConfig, services []model.SubscribeService) []model.SubscribeService {
+	tagged := make([]model.SubscribeService, len(services))
+	for i, service := range services {
+		service.Metadata = mc.tagMetadata(cluster, service.Metadata)
+		tagged[i] = service
+	}
+	return tagged
+}
+
+// 按Subscribe时传入的同一个param取消所有集群的订阅
+func (mc *MultiClusterClient) Unsubscribe(param *vo.SubscribeParam) error {
+	mc.mutex.Lock()
+	sub, ok := mc.subscriptions[param]
+	delete(mc.subscriptions, param)
+	mc.mutex.Unlock()
+	if !ok {
+		return nil
+	}
+	for i, client := range mc.clients {
+		client.Unsubscribe(sub.params[i])
+	}
+	return nil
+}
+
+// 关闭所有集群的客户端
+func (mc *MultiClusterClient) Close() error {
+	var err error
+	for _, client := range mc.clients {
+		if e := client.Close(); e != nil {
+			err = e
+		}
+	}
+	return err
+}
@@ -0,0 +1,123 @@
+package clients
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/load_balancer"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func createMultiClusterClientTest(t *testing.T, cacheDir string, locality *load_balancer.LocalityConfig) *MultiClusterClient {
+	mc, err := NewMultiClusterClient(map[string]interface{}{
+		constant.KEY_CLIENT_CONFIG: constant.ClientConfig{
+			TimeoutMs:           10 * 1000,
+			ListenInterval:      30 * 1000,
+			CacheDir:            cacheDir,
+			LogDir:              cacheDir,
+			NotLoadCacheAtStart: true,
+			CacheOnly:           true,
+			Locality:            locality,
+		},
+	}, []ClusterConfig{{Name: "sh", Zone: "zone-a"}, {Name: "bj", Zone: "zone-b"}})
+	assert.Nil(t, err)
+	return mc
+}
+
+func updateClusterServiceTest(t *testing.T, mc *MultiClusterClient, cluster string, hosts ...model.Instance) {
+	client, err := mc.NamingClient(cluster)
+	assert.Nil(t, err)
+	assert.Nil(t, client.UpdateServiceCache(vo.UpdateServiceCacheParam{ServiceName: "DEMO", Hosts: hosts}))
+}
+
+func TestMultiClusterClient_SelectInstances(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "nacos-multi-cluster")
+	assert.Nil(t, err)
+	defer os.RemoveAll(cacheDir)
+	mc := createMultiClusterClientTest(t, cacheDir, nil)
+	defer mc.Close()
+
+	updateClusterServiceTest(t, mc, "sh", model.Instance{Ip: "10.0.0.1", Port: 80, Weight: 1, Enable: true, Healthy: true})
+	updateClusterServiceTest(t, mc, "bj", model.Instance{Ip: "10.0.1.1", Port: 80, Weight: 1, Enable: true, Healthy: true,
+		Metadata: map[string]string{"zone": "zone-c"}})
+	instances, err := mc.SelectAllInstances(vo.SelectAllInstancesParam{ServiceName: "DEMO"})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(instances))
+	assert.Equal(t, map[string]string{Source_Cluster_Metadata_Key: "sh", "zone": "zone-a"}, instances[0].Metadata)
+	// 实例元数据中的可用区优先
+	assert.Equal(t, map[string]string{Source_Cluster_Metadata_Key: "bj", "zone": "zone-c"}, instances[1].Metadata)
+
+	_, err = mc.NamingClient("gz")
+	assert.NotNil(t, err)
+	_, err = NewMultiClusterClient(map[string]interface{}{}, []ClusterConfig{{Name: "sh"}, {Name: "sh"}})
+	assert.NotNil(t, err)
+}
+
+func TestMultiClusterClient_SelectOneHealthyInstance_Locality(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "nacos-multi-cluster")
+	assert.Nil(t, err)
+	defer os.RemoveAll(cacheDir)
+	mc := createMultiClusterClientTest(t, cacheDir, &load_balancer.LocalityConfig{Zone: "zone-a", MinInstances: 2})
+	defer mc.Close()
+
+	local := model.Instance{Ip: "10.0.0.1", Port: 80, Weight: 1, Enable: true, Healthy: true}
+	updateClusterServiceTest(t, mc, "bj", model.Instance{Ip: "10.0.1.1", Port: 80, Weight: 1, Enable: true, Healthy: true})
+	updateClusterServiceTest(t, mc, "sh", local, model.Instance{Ip: "10.0.0.2", Port: 80, Weight: 1, Enable: true, Healthy: true})
+	for i := 0; i < 10; i++ {
+		instance, err := mc.SelectOneHealthyInstance(vo.SelectOneHealthInstanceParam{ServiceName: "DEMO"})
+		assert.Nil(t, err)
+		assert.Equal(t, "zone-a", instance.Metadata["zone"])
+	}
+
+	// 同可用区的实例不足时溢出到其他可用区
+	updateClusterServiceTest(t, mc, "sh", local)
+	zones := map[string]int{}
+	for i := 0; i < 10; i++ {
+		instance, err := mc.SelectOneHealthyInstance(vo.SelectOneHealthInstanceParam{ServiceName: "DEMO"})
+		assert.Nil(t, err)
+		zones[instance.Metadata["zone"]]++
+	}
+	assert.Equal(t, map[string]int{"zone-a": 5, "zone-b": 5}, zones)
+}
+
+func TestMultiClusterClient_Subscribe(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "nacos-multi-cluster")
+	assert.Nil(t, err)
+	defer os.RemoveAll(cacheDir)
+	mc := createMultiClusterClientTest(t, cacheDir, nil)
+	defer mc.Close()
+
+	updateClusterServiceTest(t, mc, "sh")
+	updateClusterServiceTest(t, mc, "bj")
+	merged := make(chan []model.SubscribeService, 10)
+	param := &vo.SubscribeParam{
+		ServiceName: "DEMO",
+		SubscribeCallback: func(services []model.SubscribeService, err error) {
+			merged <- services
+		},
+	}
+	assert.Nil(t, mc.Subscribe(param))
+	updateClusterServiceTest(t, mc, "sh", model.Instance{Ip: "10.0.0.1", Port: 80, Weight: 1, Enable: true, Healthy: true})
+	updateClusterServiceTest(t, mc, "bj", model.Instance{Ip: "10.0.1.1", Port: 80, Weight: 1, Enable: true, Healthy: true})
+	var services []model.SubscribeService
+	for len(services) < 2 {
+		select {
+		case services = <-merged:
+		case <-time.After(3 * time.Second):
+			t.Fatal("merged instances were not notified")
+		}
+	}
+	clusters := map[string]bool{}
+	for _, service := range services {
+		clusters[service.Metadata[Source_Cluster_Metadata_Key]] = true
+	}
+	assert.Equal(t, map[string]bool{"sh": true, "bj": true}, clusters)
+
+	assert.Nil(t, mc.Unsubscribe(param))
+	client, _ := mc.NamingClient("sh")
+	assert.Equal(t, 0, len(client.GetSubscribedServices()))
+}
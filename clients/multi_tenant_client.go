@@ -0,0 +1,142 @@
+package clients
+
+import (
+	"errors"
+	"github.com/nacos-group/nacos-sdk-go/clients/config_client"
+	"github.com/nacos-group/nacos-sdk-go/clients/nacos_client"
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/common/nacos_server"
+	"os"
+	"sync"
+)
+
+// 同时访问多个命名空间的客户端工厂
+// 各命名空间共享同一个HttpAgent、服务端列表和鉴权token，缓存目录、订阅和心跳按命名空间隔离
+type MultiTenantClient struct {
+	mutex         sync.Mutex
+	clientConfig  constant.ClientConfig
+	serverConfigs []constant.ServerConfig
+	httpAgent     http_agent.IHttpAgent
+	nacosServer   nacos_server.NacosServer
+	namingClients map[string]*naming_client.NamingClient
+	configClients map[string]*config_client.ConfigClient
+	closed        bool
+}
+
+// properties与CreateNamingClient相同，ClientConfig中的NamespaceId会被忽略
+func NewMultiTenantClient(properties map[string]interface{}) (*MultiTenantClient, error) {
+	nacosClient, err := setConfig(properties)
+	if err != nil {
+		return nil, err
+	}
+	clientConfig, _ := nacosClient.GetClientConfig()
+	serverConfigs, _ := nacosClient.GetServerConfig()
+	httpAgent, err := http_agent.NewHttpAgentWithConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	nacosServer, err := nacos_server.NewNacosServer(serverConfigs, clientConfig, httpAgent)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiTenantClient{
+		clientConfig:  clientConfig,
+		serverConfigs: serverConfigs,
+		httpAgent:     httpAgent,
+		nacosServer:   nacosServer,
+		namingClients: map[string]*naming_client.NamingClient{},
+		configClients: map[string]*config_client.ConfigClient{},
+	}, nil
+}
+
+// 返回指定命名空间的服务发现客户端，同一命名空间多次调用返回同一个客户端
+func (mc *MultiTenantClient) NamingClient(namespaceId string) (naming_client.INamingClient, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if mc.closed {
+		return nil, errors.New("[client.MultiTenantClient] client is closed")
+	}
+	if client, ok := mc.namingClients[namespaceId]; ok {
+		return client, nil
+	}
+	nc, err := mc.tenantNacosClient(namespaceId)
+	if err != nil {
+		return nil, err
+	}
+	naming, err := naming_client.NewNamingClientWithServer(nc, mc.nacosServer.Shared())
+	if err != nil {
+		return nil, err
+	}
+	mc.namingClients[namespaceId] = &naming
+	return &naming, nil
+}
+
+// 返回指定命名空间的配置客户端，同一命名空间多次调用返回同一个客户端
+func (mc *MultiTenantClient) ConfigClient(namespaceId string) (config_client.IConfigClient, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if mc.closed {
+		return nil, errors.New("[client.MultiTenantClient] client is closed")
+	}
+	if client, ok := mc.configClients[namespaceId]; ok {
+		return client, nil
+	}
+	nc, err := mc.tenantNacosClient(namespaceId)
+	if err != nil {
+		return nil, err
+	}
+	config, err := config_client.NewConfigClientWithServer(nc, mc.nacosServer.Shared())
+	if err != nil {
+		return nil, err
+	}
+	mc.configClients[namespaceId] = config
+	return config, nil
+}
+
+// 每个命名空间使用CacheDir下以命名空间命名的子目录作为缓存目录
+func (mc *MultiTenantClient) tenantNacosClient(namespaceId string) (nacos_client.INacosClient, error) {
+	clientConfig := mc.clientConfig
+	clientConfig.NamespaceId = namespaceId
+	tenantDir := namespaceId
+	if tenantDir == "" {
+		tenantDir = "public"
+	}
+	clientConfig.CacheDir = mc.clientConfig.CacheDir + string(os.PathSeparator) + tenantDir
+	if clientConfig.ConfigSnapshotDir != "" {
+		clientConfig.ConfigSnapshotDir = mc.clientConfig.ConfigSnapshotDir + string(os.PathSeparator) + tenantDir
+	}
+	nc := &nacos_client.NacosClient{}
+	if err := nc.SetClientConfig(clientConfig); err != nil {
+		return nil, err
+	}
+	if err := nc.SetServerConfig(mc.serverConfigs); err != nil {
+		return nil, err
+	}
+	nc.SetHttpAgent(mc.httpAgent)
+	return nc, nil
+}
+
+// 关闭所有命名空间的客户端，并停止共享的服务端列表刷新和token刷新
+func (mc *MultiTenantClient) Close() error {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if mc.closed {
+		return nil
+	}
+	mc.closed = true
+	var err error
+	for _, client := range mc.namingClients {
+		if e := client.Close(); e != nil {
+			err = e
+		}
+	}
+	for _, client := range mc.configClients {
+		if e := client.Close(); e != nil {
+			err = e
+		}
+	}
+	mc.nacosServer.Stop()
+	return err
+}
@@ -0,0 +1,44 @@
+package clients
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestMultiTenantClient(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "nacos-multi-tenant")
+	assert.Nil(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	mc, err := NewMultiTenantClient(map[string]interface{}{
+		constant.KEY_CLIENT_CONFIG: constant.ClientConfig{
+			TimeoutMs:           10 * 1000,
+			ListenInterval:      30 * 1000,
+			CacheDir:            cacheDir,
+			LogDir:              cacheDir,
+			NotLoadCacheAtStart: true,
+		},
+		constant.KEY_SERVER_CONFIGS: []constant.ServerConfig{{IpAddr: "127.0.0.1", Port: 8848}},
+	})
+	assert.Nil(t, err)
+
+	naming1, err := mc.NamingClient("ns1")
+	assert.Nil(t, err)
+	naming1Again, err := mc.NamingClient("ns1")
+	assert.Nil(t, err)
+	assert.True(t, naming1 == naming1Again)
+	naming2, err := mc.NamingClient("ns2")
+	assert.Nil(t, err)
+	assert.False(t, naming1 == naming2)
+
+	config1, err := mc.ConfigClient("ns1")
+	assert.Nil(t, err)
+	assert.NotNil(t, config1)
+
+	assert.Nil(t, mc.Close())
+	_, err = mc.NamingClient("ns3")
+	assert.NotNil(t, err)
+}
@@ -4,10 +4,11 @@ import (
 	"errors"
 	"github.com/nacos-group/nacos-sdk-go/common/constant"
 	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
 	"github.com/nacos-group/nacos-sdk-go/utils"
-	"log"
 	"os"
 	"strconv"
+	"sync"
 )
 
 /**
@@ -20,6 +21,7 @@ import (
 **/
 
 type NacosClient struct {
+	mutex              sync.RWMutex
 	clientConfigValid  bool
 	serverConfigsValid bool
 	agent              http_agent.IHttpAgent
@@ -55,7 +57,9 @@ func (client *NacosClient) SetClientConfig(config constant.ClientConfig) (err er
 	if config.LogDir == "" {
 		config.LogDir = utils.GetCurrentPath() + string(os.PathSeparator) + "log"
 	}
-	log.Printf("[INFO] logDir:<%s>   cacheDir:<%s>", config.LogDir, config.CacheDir)
+	logger.Infof("logDir:<%s>   cacheDir:<%s>", config.LogDir, config.CacheDir)
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
 	client.clientConfig = config
 	client.clientConfigValid = true
 
@@ -79,6 +83,8 @@ func (client *NacosClient) SetServerConfig(configs []constant.ServerConfig) (err
 			configs[i].ContextPath = constant.DEFAULT_CONTEXT_PATH
 		}
 	}
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
 	client.serverConfigs = configs
 	client.serverConfigsValid = true
 	return
@@ -86,6 +92,8 @@ func (client *NacosClient) SetServerConfig(configs []constant.ServerConfig) (err
 
 // 获取 clientConfig
 func (client *NacosClient) GetClientConfig() (config constant.ClientConfig, err error) {
+	client.mutex.RLock()
+	defer client.mutex.RUnlock()
 	config = client.clientConfig
 	if !client.clientConfigValid {
 		err = errors.New("[client.GetClientConfig] invalid client config")
@@ -95,6 +103,8 @@ func (client *NacosClient) GetClientConfig() (config constant.ClientConfig, err
 
 // 获取serverConfigs
 func (client *NacosClient) GetServerConfig() (configs []constant.ServerConfig, err error) {
+	client.mutex.RLock()
+	defer client.mutex.RUnlock()
 	configs = client.serverConfigs
 	if !client.serverConfigsValid {
 		err = errors.New("[client.GetServerConfig] invalid server configs")
@@ -24,3 +24,8 @@ type INacosClient interface {
 	SetHttpAgent(http_agent.IHttpAgent) error
 	GetHttpAgent() (http_agent.IHttpAgent, error)
 }
+
+// 关闭客户端，停止后台协程并释放占用的资源，关闭后客户端不可再使用
+type CloseableClient interface {
+	Close() error
+}
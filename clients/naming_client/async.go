@@ -0,0 +1,28 @@
+package naming_client
+
+import (
+	"context"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+// 在后台注册实例，完成后向返回的channel写入一个结果，channel有缓冲，不读取也不会阻塞后台协程
+// 启动时需注册多个实例时可并发调用后依次读取结果
+func (sc *NamingClient) RegisterInstanceAsync(ctx context.Context, param vo.RegisterInstanceParam) <-chan model.AsyncResult {
+	result := make(chan model.AsyncResult, 1)
+	go func() {
+		success, err := sc.RegisterInstanceWithContext(ctx, param)
+		result <- model.AsyncResult{Success: success, Err: err}
+	}()
+	return result
+}
+
+// 在后台注销实例，完成后向返回的channel写入一个结果
+func (sc *NamingClient) DeregisterInstanceAsync(ctx context.Context, param vo.DeregisterInstanceParam) <-chan model.AsyncResult {
+	result := make(chan model.AsyncResult, 1)
+	go func() {
+		success, err := sc.DeregisterInstanceWithContext(ctx, param)
+		result <- model.AsyncResult{Success: success, Err: err}
+	}()
+	return result
+}
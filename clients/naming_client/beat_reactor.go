@@ -1,13 +1,19 @@
 package naming_client
 
 import (
+	"context"
+	"errors"
 	"github.com/nacos-group/nacos-sdk-go/clients/cache"
 	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/event"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
 	"github.com/nacos-group/nacos-sdk-go/model"
 	"github.com/nacos-group/nacos-sdk-go/utils"
 	nsema "github.com/toolkits/concurrent/semaphore"
-	"log"
+	"math/rand"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -18,12 +24,18 @@ type BeatReactor struct {
 	beatThreadCount     int
 	beatThreadSemaphore *nsema.Semaphore
 	beatRecordMap       cache.ConcurrentMap
+	beatInfoMutex       sync.RWMutex
+	stopChan            chan struct{}
+	stopOnce            sync.Once
 }
 
 const Default_Beat_Thread_Num = 20
 
-func NewBeatReactor(serviceProxy NamingProxy, clientBeatInterval int64) BeatReactor {
-	br := BeatReactor{}
+// 心跳连续失败时的最大重试间隔
+const maxBeatBackoff = time.Minute
+
+func NewBeatReactor(serviceProxy NamingProxy, clientBeatInterval int64) *BeatReactor {
+	br := &BeatReactor{}
 	if clientBeatInterval <= 0 {
 		clientBeatInterval = 5 * 1000
 	}
@@ -33,6 +45,7 @@ func NewBeatReactor(serviceProxy NamingProxy, clientBeatInterval int64) BeatReac
 	br.beatThreadCount = Default_Beat_Thread_Num
 	br.beatRecordMap = cache.NewConcurrentMap()
 	br.beatThreadSemaphore = nsema.NewSemaphore(br.beatThreadCount)
+	br.stopChan = make(chan struct{})
 	return br
 }
 
@@ -41,42 +54,129 @@ func buildKey(serviceName string, ip string, port uint64) string {
 }
 
 func (br *BeatReactor) AddBeatInfo(serviceName string, beatInfo model.BeatInfo) {
-	log.Printf("[INFO] adding beat: <%s> to beat map.\n", utils.ToJsonString(beatInfo))
+	logger.Infof("adding beat: <%s> to beat map.", utils.ToJsonString(beatInfo))
 	k := buildKey(serviceName, beatInfo.Ip, beatInfo.Port)
 	br.beatMap.Set(k, &beatInfo)
 	go br.sendInstanceBeat(k, &beatInfo)
 }
 
+// 实例是否由该客户端发送心跳，即是否为通过该客户端注册的临时实例
+func (br *BeatReactor) HasBeatInfo(serviceName string, ip string, port uint64) bool {
+	return br.beatMap.Has(buildKey(serviceName, ip, port))
+}
+
+// 修改实例心跳携带的权重、可用状态和元数据，实例不由该客户端发送心跳时返回false
+func (br *BeatReactor) UpdateBeatInfo(serviceName string, ip string, port uint64, weight float64, enable bool, metadata map[string]string) bool {
+	data, exist := br.beatMap.Get(buildKey(serviceName, ip, port))
+	if !exist {
+		return false
+	}
+	br.updateBeatInfo(data.(*model.BeatInfo), func(info *model.BeatInfo) {
+		info.Weight = weight
+		info.Disabled = !enable
+		info.Metadata = metadata
+	})
+	return true
+}
+
+// 暂停或恢复实例的心跳，实例不由该客户端发送心跳时返回false
+func (br *BeatReactor) PauseBeat(serviceName string, ip string, port uint64, paused bool) bool {
+	data, exist := br.beatMap.Get(buildKey(serviceName, ip, port))
+	if !exist {
+		return false
+	}
+	br.updateBeatInfo(data.(*model.BeatInfo), func(info *model.BeatInfo) {
+		info.Paused = paused
+	})
+	return true
+}
+
+// 心跳协程与修改、停止心跳的调用方并发访问BeatInfo，均需持有beatInfoMutex
+func (br *BeatReactor) updateBeatInfo(beatInfo *model.BeatInfo, update func(info *model.BeatInfo)) {
+	br.beatInfoMutex.Lock()
+	defer br.beatInfoMutex.Unlock()
+	update(beatInfo)
+}
+
+func (br *BeatReactor) snapshot(beatInfo *model.BeatInfo) model.BeatInfo {
+	br.beatInfoMutex.RLock()
+	defer br.beatInfoMutex.RUnlock()
+	return *beatInfo
+}
+
 func (br *BeatReactor) RemoveBeatInfo(serviceName string, ip string, port uint64) {
-	log.Printf("[INFO] remove beat: %s@%s:%d from beat map.\n", serviceName, ip, port)
+	logger.Infof("remove beat: %s@%s:%d from beat map.", serviceName, ip, port)
 	k := buildKey(serviceName, ip, port)
 	data, exist := br.beatMap.Get(k)
 	if exist {
-		beatInfo := data.(*model.BeatInfo)
-		beatInfo.Stopped = true
+		br.updateBeatInfo(data.(*model.BeatInfo), func(info *model.BeatInfo) {
+			info.Stopped = true
+		})
 	}
 	br.beatMap.Remove(k)
 }
 
+// 每个实例独立调度心跳：间隔使用服务端返回的clientBeatInterval并加入随机抖动，
+// 服务端找不到实例时重新注册，连续失败时按指数退避
 func (br *BeatReactor) sendInstanceBeat(k string, beatInfo *model.BeatInfo) {
+	br.updateBeatInfo(beatInfo, func(info *model.BeatInfo) {
+		if info.Period <= 0 {
+			info.Period = time.Duration(br.clientBeatInterval) * time.Millisecond
+		}
+	})
+	failures := 0
 	for {
+		//暂停期间不发送心跳，恢复后在下一个周期继续
+		if info := br.snapshot(beatInfo); info.Paused {
+			if info.Stopped || !br.waitNextBeat(info.Period) {
+				return
+			}
+			continue
+		}
 		br.beatThreadSemaphore.Acquire()
 		//进行心跳通信
-		beatInterval, err := br.serviceProxy.SendBeat(*beatInfo)
+		info := br.snapshot(beatInfo)
+		beatInterval, err := br.serviceProxy.SendBeat(context.Background(), info)
+		if errors.Is(err, ErrBeatResourceNotFound) && !info.Stopped {
+			logger.Warnf("instance[%s] not found on server, register it again", k)
+			err = br.reRegister(info)
+			br.serviceProxy.publishEvent(event.InstanceReregisteredEvent{
+				Namespace:   br.serviceProxy.clientConfig.NamespaceId,
+				ServiceName: info.ServiceName,
+				Ip:          info.Ip,
+				Port:        info.Port,
+				Err:         err,
+			})
+		}
 		if err != nil {
-			log.Printf("[ERROR]:beat to server return error:%s \n", err.Error())
+			logger.Errorf("beat to server return error:%s", err.Error())
+			br.serviceProxy.nacosServer.Metrics().IncBeatFailures()
 			br.beatThreadSemaphore.Release()
-			t := time.NewTimer(beatInfo.Period)
-			<-t.C
+			failures++
+			br.serviceProxy.publishEvent(event.HeartbeatFailedEvent{
+				Namespace:   br.serviceProxy.clientConfig.NamespaceId,
+				ServiceName: info.ServiceName,
+				Ip:          info.Ip,
+				Port:        info.Port,
+				Failures:    failures,
+				Err:         err,
+			})
+			if !br.waitNextBeat(beatBackoff(info.Period, failures)) {
+				return
+			}
 			continue
 		}
+		failures = 0
 		if beatInterval > 0 {
-			beatInfo.Period = time.Duration(time.Millisecond.Nanoseconds() * beatInterval)
+			br.updateBeatInfo(beatInfo, func(info *model.BeatInfo) {
+				info.Period = time.Duration(time.Millisecond.Nanoseconds() * beatInterval)
+			})
 		}
 
 		//如果当前实例注销，则进行停止心跳
-		if beatInfo.Stopped {
-			log.Printf("[INFO] intance[%s] stop heartBeating\n", k)
+		info = br.snapshot(beatInfo)
+		if info.Stopped {
+			logger.Infof("intance[%s] stop heartBeating", k)
 			br.beatThreadSemaphore.Release()
 			return
 		}
@@ -84,7 +184,80 @@ func (br *BeatReactor) sendInstanceBeat(k string, beatInfo *model.BeatInfo) {
 		br.beatRecordMap.Set(k, utils.CurrentMillis())
 		br.beatThreadSemaphore.Release()
 
-		t := time.NewTimer(beatInfo.Period)
-		<-t.C
+		if !br.waitNextBeat(withJitter(info.Period)) {
+			return
+		}
+	}
+}
+
+func (br *BeatReactor) reRegister(beatInfo model.BeatInfo) error {
+	groupName := constant.DEFAULT_GROUP
+	if index := strings.Index(beatInfo.ServiceName, constant.SERVICE_INFO_SPLITER); index > 0 {
+		groupName = beatInfo.ServiceName[:index]
+	}
+	_, err := br.serviceProxy.RegisterInstance(context.Background(), beatInfo.ServiceName, groupName, model.Instance{
+		Ip:          beatInfo.Ip,
+		Port:        beatInfo.Port,
+		Weight:      beatInfo.Weight,
+		Metadata:    beatInfo.Metadata,
+		ClusterName: beatInfo.Cluster,
+		Enable:      !beatInfo.Disabled,
+		Healthy:     true,
+		Ephemeral:   true,
+	})
+	return err
+}
+
+// 在间隔上加入±10%的随机抖动，避免大量实例同时发送心跳
+func withJitter(period time.Duration) time.Duration {
+	jitter := int64(period) / 10
+	if jitter <= 0 {
+		return period
+	}
+	return period - time.Duration(jitter) + time.Duration(rand.Int63n(2*jitter))
+}
+
+// 连续失败时间隔按2的幂次增长，最多增长到maxBeatBackoff
+func beatBackoff(period time.Duration, failures int) time.Duration {
+	backoff := period
+	for i := 1; i < failures && backoff < maxBeatBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBeatBackoff && period < maxBeatBackoff {
+		backoff = maxBeatBackoff
 	}
+	return withJitter(backoff)
+}
+
+// 等待下一次心跳，BeatReactor停止时返回false
+func (br *BeatReactor) waitNextBeat(period time.Duration) bool {
+	t := time.NewTimer(period)
+	defer t.Stop()
+	select {
+	case <-br.stopChan:
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// 返回当前仍在发送心跳的实例
+func (br *BeatReactor) BeatInfos() []model.BeatInfo {
+	var beatInfos []model.BeatInfo
+	for item := range br.beatMap.IterBuffered() {
+		beatInfos = append(beatInfos, br.snapshot(item.Val.(*model.BeatInfo)))
+	}
+	return beatInfos
+}
+
+// 停止所有心跳
+func (br *BeatReactor) Stop() {
+	br.stopOnce.Do(func() {
+		for item := range br.beatMap.IterBuffered() {
+			br.updateBeatInfo(item.Val.(*model.BeatInfo), func(info *model.BeatInfo) {
+				info.Stopped = true
+			})
+		}
+		close(br.stopChan)
+	})
 }
@@ -1,10 +1,18 @@
 package naming_client
 
 import (
+	"github.com/golang/mock/gomock"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/event"
+	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/mock"
 	"github.com/nacos-group/nacos-sdk-go/model"
 	"github.com/nacos-group/nacos-sdk-go/utils"
 	"github.com/stretchr/testify/assert"
+	"net/http"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestBeatReactor_AddBeatInfo(t *testing.T) {
@@ -56,3 +64,121 @@ func TestBeatReactor_RemoveBeatInfo(t *testing.T) {
 	assert.ObjectsAreEqual(result.(model.BeatInfo), beatInfo2)
 
 }
+
+func TestBeatReactor_beatBackoff(t *testing.T) {
+	period := 5 * time.Second
+	for _, c := range []struct {
+		failures int
+		expected time.Duration
+	}{
+		{1, 5 * time.Second},
+		{2, 10 * time.Second},
+		{3, 20 * time.Second},
+		{5, time.Minute},
+		{100, time.Minute},
+	} {
+		backoff := beatBackoff(period, c.failures)
+		assert.True(t, backoff >= c.expected*9/10 && backoff <= c.expected*11/10, "failures:%d backoff:%s", c.failures, backoff)
+	}
+}
+
+func TestBeatReactor_ReRegisterWhenNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	gomock.InOrder(
+		mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPut),
+			gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/beat"),
+			gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+			Return(http_agent.FakeHttpResponse(200, `{"clientBeatInterval":5000,"code":20404}`), nil),
+		mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+			gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+			gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+			DoAndReturn(func(ctx, method, path, header, timeoutMs interface{}, params map[string]string) (*http.Response, error) {
+				assert.Equal(t, "public", params["groupName"])
+				assert.Equal(t, "public@@Test", params["serviceName"])
+				assert.Equal(t, "127.0.0.1", params["ip"])
+				return http_agent.FakeHttpResponse(200, `ok`), nil
+			}),
+	)
+
+	proxy, _ := NewNamingProxy(clientConfigTest, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	events := make(chan event.Event, 1)
+	proxy.nacosServer.Events().Subscribe(func(e event.Event) { events <- e }, event.TYPE_INSTANCE_REREGISTERED)
+	br := NewBeatReactor(proxy, 5000)
+	defer br.Stop()
+	br.AddBeatInfo("public@@Test", model.BeatInfo{
+		Ip:          "127.0.0.1",
+		Port:        8080,
+		ServiceName: "public@@Test",
+		Period:      time.Hour,
+	})
+	for i := 0; i < 100 && br.beatRecordMap.Count() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 1, br.beatRecordMap.Count())
+	assert.Equal(t, event.InstanceReregisteredEvent{ServiceName: "public@@Test", Ip: "127.0.0.1", Port: 8080}, <-events)
+}
+
+func TestBeatReactor_HeartbeatFailedEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPut),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/beat"),
+		gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		Return(http_agent.FakeHttpResponse(400, `bad request`), nil)
+
+	proxy, _ := NewNamingProxy(clientConfigTest, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	events := make(chan event.Event, 1)
+	proxy.nacosServer.Events().Subscribe(func(e event.Event) { events <- e }, event.TYPE_HEARTBEAT_FAILED)
+	br := NewBeatReactor(proxy, 5000)
+	defer br.Stop()
+	br.AddBeatInfo("public@@Test", model.BeatInfo{
+		Ip:          "127.0.0.1",
+		Port:        8080,
+		ServiceName: "public@@Test",
+		Period:      time.Hour,
+	})
+	e := (<-events).(event.HeartbeatFailedEvent)
+	assert.Equal(t, "public@@Test", e.ServiceName)
+	assert.Equal(t, 1, e.Failures)
+	assert.NotNil(t, e.Err)
+}
+
+func TestBeatReactor_PauseBeat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	var beats int32
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPut),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/beat"),
+		gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx, method, path, header, timeoutMs interface{}, params map[string]string) (*http.Response, error) {
+			atomic.AddInt32(&beats, 1)
+			return http_agent.FakeHttpResponse(200, `{"clientBeatInterval":20}`), nil
+		})
+
+	proxy, _ := NewNamingProxy(clientConfigTest, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	br := NewBeatReactor(proxy, 5000)
+	defer br.Stop()
+	assert.False(t, br.PauseBeat("public@@Test", "127.0.0.1", 8080, true))
+	br.AddBeatInfo("public@@Test", model.BeatInfo{
+		Ip:          "127.0.0.1",
+		Port:        8080,
+		ServiceName: "public@@Test",
+		Period:      20 * time.Millisecond,
+	})
+	for i := 0; i < 100 && atomic.LoadInt32(&beats) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.True(t, br.PauseBeat("public@@Test", "127.0.0.1", 8080, true))
+	time.Sleep(50 * time.Millisecond)
+	paused := atomic.LoadInt32(&beats)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, paused, atomic.LoadInt32(&beats), "no beat should be sent while paused")
+
+	assert.True(t, br.PauseBeat("public@@Test", "127.0.0.1", 8080, false))
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, atomic.LoadInt32(&beats) > paused)
+}
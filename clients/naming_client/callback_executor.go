@@ -0,0 +1,212 @@
+package naming_client
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/common/monitor"
+	"sync"
+)
+
+const (
+	Default_Callback_Workers    = 8
+	Default_Callback_Queue_Size = 64
+)
+
+// 一个服务的一次回调通知
+type callbackTask struct {
+	key string
+	// 通知的是完整的实例列表，可以被同一服务之后的通知替换
+	coalesce bool
+	run      func()
+}
+
+// 订阅回调的执行器，见constant.CallbackExecutorConfig
+type callbackExecutor interface {
+	execute(task *callbackTask)
+	stop()
+}
+
+// config为nil或Mode为Callback_Mode_Sync时返回nil，回调同步执行
+func newCallbackExecutor(config *constant.CallbackExecutorConfig, metrics *monitor.Recorder) callbackExecutor {
+	if config == nil || config.Mode == "" || config.Mode == constant.Callback_Mode_Sync {
+		return nil
+	}
+	size := config.QueueSize
+	if size <= 0 {
+		size = Default_Callback_Queue_Size
+	}
+	coalesce := config.Overflow == constant.Overflow_Coalesce
+	if config.Mode == constant.Callback_Mode_Serial {
+		return &serialExecutor{size: size, coalesce: coalesce, metrics: metrics, queues: map[string]*callbackQueue{}}
+	}
+	if config.Mode != constant.Callback_Mode_Pool {
+		logger.Warnf("unknown callback mode:%s, use %s", config.Mode, constant.Callback_Mode_Pool)
+	}
+	workers := config.Workers
+	if workers <= 0 {
+		workers = Default_Callback_Workers
+	}
+	return newPoolExecutor(workers, callbackQueue{size: size, coalesce: coalesce, metrics: metrics})
+}
+
+// 异步执行的回调panic时只记录日志，避免执行协程退出
+func runCallback(task *callbackTask) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("subscribe callback of service:%s panic:%v", task.key, r)
+		}
+	}()
+	task.run()
+}
+
+// 有界的回调队列，满时丢弃最早的回调
+type callbackQueue struct {
+	tasks    []*callbackTask
+	size     int
+	coalesce bool
+	metrics  *monitor.Recorder
+}
+
+func (q *callbackQueue) push(task *callbackTask) {
+	if q.coalesce && task.coalesce {
+		for i, pending := range q.tasks {
+			if pending.coalesce && pending.key == task.key {
+				q.tasks[i] = task
+				return
+			}
+		}
+	}
+	if len(q.tasks) >= q.size {
+		logger.Warnf("callback queue is full, drop the oldest callback of service:%s", q.tasks[0].key)
+		q.metrics.IncCallbackDropped()
+		q.tasks = q.tasks[1:]
+	}
+	q.tasks = append(q.tasks, task)
+}
+
+func (q *callbackQueue) pop() (*callbackTask, bool) {
+	if len(q.tasks) == 0 {
+		return nil, false
+	}
+	task := q.tasks[0]
+	q.tasks[0] = nil
+	q.tasks = q.tasks[1:]
+	return task, true
+}
+
+// 取出最早的一个服务不在busy中的回调
+func (q *callbackQueue) popIdle(busy map[string]bool) (*callbackTask, bool) {
+	for i, task := range q.tasks {
+		if busy[task.key] {
+			continue
+		}
+		copy(q.tasks[i:], q.tasks[i+1:])
+		q.tasks[len(q.tasks)-1] = nil
+		q.tasks = q.tasks[:len(q.tasks)-1]
+		return task, true
+	}
+	return nil, false
+}
+
+// 所有服务共享一个队列和固定数量的协程，同一服务同时最多执行一个回调，保证按通知顺序执行
+type poolExecutor struct {
+	mutex   sync.Mutex
+	cond    *sync.Cond
+	queue   callbackQueue
+	running map[string]bool
+	stopped bool
+}
+
+func newPoolExecutor(workers int, queue callbackQueue) *poolExecutor {
+	e := &poolExecutor{queue: queue, running: map[string]bool{}}
+	e.cond = sync.NewCond(&e.mutex)
+	for i := 0; i < workers; i++ {
+		go e.work()
+	}
+	return e
+}
+
+func (e *poolExecutor) execute(task *callbackTask) {
+	e.mutex.Lock()
+	if e.stopped {
+		e.mutex.Unlock()
+		return
+	}
+	e.queue.push(task)
+	e.mutex.Unlock()
+	e.cond.Signal()
+}
+
+func (e *poolExecutor) work() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	for {
+		if e.stopped {
+			return
+		}
+		task, ok := e.queue.popIdle(e.running)
+		if !ok {
+			e.cond.Wait()
+			continue
+		}
+		e.running[task.key] = true
+		e.mutex.Unlock()
+		runCallback(task)
+		e.mutex.Lock()
+		delete(e.running, task.key)
+		// 同一服务的后续回调可能因该回调执行中而未被其他协程取出
+		e.cond.Broadcast()
+	}
+}
+
+func (e *poolExecutor) stop() {
+	e.mutex.Lock()
+	e.stopped = true
+	e.mutex.Unlock()
+	e.cond.Broadcast()
+}
+
+// 每个服务一个队列，有待执行的回调时启动一个协程按顺序执行，执行完后退出
+type serialExecutor struct {
+	mutex    sync.Mutex
+	size     int
+	coalesce bool
+	metrics  *monitor.Recorder
+	queues   map[string]*callbackQueue
+	stopped  bool
+}
+
+func (e *serialExecutor) execute(task *callbackTask) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.stopped {
+		return
+	}
+	queue, ok := e.queues[task.key]
+	if !ok {
+		queue = &callbackQueue{size: e.size, coalesce: e.coalesce, metrics: e.metrics}
+		e.queues[task.key] = queue
+		go e.drain(task.key, queue)
+	}
+	queue.push(task)
+}
+
+func (e *serialExecutor) drain(key string, queue *callbackQueue) {
+	for {
+		e.mutex.Lock()
+		task, ok := queue.pop()
+		if !ok || e.stopped {
+			delete(e.queues, key)
+			e.mutex.Unlock()
+			return
+		}
+		e.mutex.Unlock()
+		runCallback(task)
+	}
+}
+
+func (e *serialExecutor) stop() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.stopped = true
+}
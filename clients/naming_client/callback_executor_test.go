@@ -0,0 +1,127 @@
+package naming_client
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func waitCallbacks(wg *sync.WaitGroup, t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("callbacks not executed")
+	}
+}
+
+func TestNewCallbackExecutor_Sync(t *testing.T) {
+	assert.Nil(t, newCallbackExecutor(nil, nil))
+	assert.Nil(t, newCallbackExecutor(&constant.CallbackExecutorConfig{Mode: constant.Callback_Mode_Sync}, nil))
+}
+
+func TestPoolExecutor_Execute(t *testing.T) {
+	executor := newCallbackExecutor(&constant.CallbackExecutorConfig{Mode: constant.Callback_Mode_Pool, Workers: 2}, nil)
+	defer executor.stop()
+	wg := sync.WaitGroup{}
+	wg.Add(3)
+	executor.execute(&callbackTask{key: "a", run: func() {
+		defer wg.Done()
+		panic("callback panic")
+	}})
+	executor.execute(&callbackTask{key: "a", run: wg.Done})
+	executor.execute(&callbackTask{key: "b", run: wg.Done})
+	waitCallbacks(&wg, t)
+}
+
+func TestPoolExecutor_KeepOrderPerService(t *testing.T) {
+	executor := newCallbackExecutor(&constant.CallbackExecutorConfig{Mode: constant.Callback_Mode_Pool, Workers: 8, QueueSize: 1000}, nil)
+	defer executor.stop()
+	var mutex sync.Mutex
+	orders := map[string][]int{}
+	inFlight := map[string]int{}
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		for _, key := range []string{"a", "b", "c"} {
+			index, key := i, key
+			wg.Add(1)
+			executor.execute(&callbackTask{key: key, run: func() {
+				defer wg.Done()
+				mutex.Lock()
+				inFlight[key]++
+				assert.Equal(t, 1, inFlight[key], "callbacks of the same service should not run concurrently")
+				mutex.Unlock()
+				time.Sleep(time.Millisecond)
+				mutex.Lock()
+				inFlight[key]--
+				orders[key] = append(orders[key], index)
+				mutex.Unlock()
+			}})
+		}
+	}
+	waitCallbacks(&wg, t)
+	for key, order := range orders {
+		assert.Equal(t, 50, len(order), key)
+		for i := range order {
+			assert.Equal(t, i, order[i], key)
+		}
+	}
+}
+
+func TestSerialExecutor_KeepOrderPerService(t *testing.T) {
+	executor := newCallbackExecutor(&constant.CallbackExecutorConfig{Mode: constant.Callback_Mode_Serial, QueueSize: 100}, nil)
+	defer executor.stop()
+	var mutex sync.Mutex
+	var order []int
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		index := i
+		wg.Add(1)
+		executor.execute(&callbackTask{key: "a", run: func() {
+			defer wg.Done()
+			mutex.Lock()
+			order = append(order, index)
+			mutex.Unlock()
+		}})
+	}
+	waitCallbacks(&wg, t)
+	for i := range order {
+		assert.Equal(t, i, order[i])
+	}
+}
+
+func TestCallbackQueue_DropOldest(t *testing.T) {
+	queue := callbackQueue{size: 2}
+	queue.push(&callbackTask{key: "1", coalesce: true})
+	queue.push(&callbackTask{key: "2"})
+	queue.push(&callbackTask{key: "3"})
+	assert.Equal(t, 2, len(queue.tasks))
+	task, _ := queue.pop()
+	assert.Equal(t, "2", task.key)
+	task, _ = queue.pop()
+	assert.Equal(t, "3", task.key)
+	_, ok := queue.pop()
+	assert.False(t, ok)
+}
+
+func TestCallbackQueue_Coalesce(t *testing.T) {
+	queue := callbackQueue{size: 10, coalesce: true}
+	first := &callbackTask{key: "a", coalesce: true}
+	latest := &callbackTask{key: "a", coalesce: true}
+	change := &callbackTask{key: "a"}
+	queue.push(first)
+	queue.push(change)
+	queue.push(latest)
+	queue.push(&callbackTask{key: "b", coalesce: true})
+	assert.Equal(t, 3, len(queue.tasks))
+	task, _ := queue.pop()
+	assert.True(t, task == latest)
+	task, _ = queue.pop()
+	assert.True(t, task == change)
+}
@@ -0,0 +1,158 @@
+package naming_client
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/clients/cache"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/utils"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 与Java客户端一致的容灾开关文件名，文件内容为1时开启容灾，为0时关闭
+const Failover_Switch_File = "00-00---000-VIPSRV_FAILOVER_SWITCH-000---00-00"
+
+const (
+	failoverSwitchCheckInterval = 5 * time.Second
+	failoverBackupInterval      = 24 * time.Hour
+	failoverFirstBackupDelay    = 10 * time.Second
+)
+
+// 容灾：开关打开或服务端不可用时，从容灾目录中读取服务实例
+type FailoverReactor struct {
+	failoverDir  string
+	hostReactor  *HostReactor
+	mutex        sync.RWMutex
+	serviceMap   map[string]model.Service
+	switchOn     int32
+	lastModified int64
+	stopChan     chan struct{}
+	stopOnce     sync.Once
+}
+
+func NewFailoverReactor(hostReactor *HostReactor, cacheDir string) *FailoverReactor {
+	fr := &FailoverReactor{
+		failoverDir: cacheDir + string(os.PathSeparator) + "failover",
+		hostReactor: hostReactor,
+		serviceMap:  map[string]model.Service{},
+		stopChan:    make(chan struct{}),
+	}
+	//开关关闭时服务端不可用也会读取快照，启动时即加载
+	fr.loadFailoverServices()
+	fr.checkSwitch()
+	go fr.switchRefresher()
+	go fr.backupRefresher()
+	return fr
+}
+
+func (fr *FailoverReactor) IsFailoverSwitch() bool {
+	return atomic.LoadInt32(&fr.switchOn) == 1
+}
+
+func (fr *FailoverReactor) GetService(serviceName string, clusters string) (model.Service, bool) {
+	fr.mutex.RLock()
+	defer fr.mutex.RUnlock()
+	service, ok := fr.serviceMap[utils.GetServiceCacheKey(serviceName, clusters)]
+	return service, ok
+}
+
+func (fr *FailoverReactor) switchRefresher() {
+	ticker := time.NewTicker(failoverSwitchCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-fr.stopChan:
+			return
+		case <-ticker.C:
+			fr.checkSwitch()
+		}
+	}
+}
+
+// 开关文件有变化时重新读取，开启时加载容灾目录中的服务
+func (fr *FailoverReactor) checkSwitch() {
+	switchFile := cache.GetFileName(Failover_Switch_File, fr.failoverDir)
+	info, err := os.Stat(switchFile)
+	if err != nil {
+		if atomic.SwapInt32(&fr.switchOn, 0) == 1 {
+			logger.Infof("failover switch file removed, failover mode is off")
+		}
+		fr.lastModified = 0
+		return
+	}
+	modified := info.ModTime().UnixNano()
+	if modified == fr.lastModified {
+		return
+	}
+	fr.lastModified = modified
+	content, err := ioutil.ReadFile(switchFile)
+	if err != nil {
+		logger.Errorf("failed to read failover switch file:%s,err:%s", switchFile, err.Error())
+		return
+	}
+	if strings.TrimSpace(string(content)) == "1" {
+		fr.loadFailoverServices()
+		atomic.StoreInt32(&fr.switchOn, 1)
+		logger.Infof("failover mode is on")
+	} else {
+		atomic.StoreInt32(&fr.switchOn, 0)
+		logger.Infof("failover mode is off")
+	}
+}
+
+func (fr *FailoverReactor) loadFailoverServices() {
+	serviceMap := cache.ReadServicesFromFile(fr.failoverDir)
+	if serviceMap == nil {
+		serviceMap = map[string]model.Service{}
+	}
+	fr.mutex.Lock()
+	fr.serviceMap = serviceMap
+	fr.mutex.Unlock()
+}
+
+func (fr *FailoverReactor) backupRefresher() {
+	delay := failoverBackupInterval
+	if files, err := ioutil.ReadDir(fr.failoverDir); err != nil || len(files) == 0 {
+		delay = failoverFirstBackupDelay
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	for {
+		select {
+		case <-fr.stopChan:
+			return
+		case <-timer.C:
+			fr.backup()
+			timer.Reset(failoverBackupInterval)
+		}
+	}
+}
+
+// 将当前缓存的服务写入容灾目录并重新加载，作为下次容灾时的快照
+func (fr *FailoverReactor) backup() {
+	if fr.IsFailoverSwitch() {
+		return
+	}
+	for _, v := range fr.hostReactor.serviceInfoMap.Items() {
+		service := v.(model.Service)
+		if len(service.Hosts) == 0 {
+			continue
+		}
+		if fr.hostReactor.cacheConfig.JavaLayout {
+			cache.WriteServicesToJavaFile(service, fr.failoverDir)
+		} else {
+			cache.WriteServicesToFile(service, fr.failoverDir)
+		}
+	}
+	fr.loadFailoverServices()
+}
+
+func (fr *FailoverReactor) Stop() {
+	fr.stopOnce.Do(func() {
+		close(fr.stopChan)
+	})
+}
@@ -0,0 +1,69 @@
+package naming_client
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/nacos-group/nacos-sdk-go/clients/cache"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/mock"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+)
+
+func TestFailoverReactor_Switch(t *testing.T) {
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	failoverDir := cacheDir + string(os.PathSeparator) + "failover"
+	cache.WriteServicesToFile(model.Service{Name: "DEFAULT_GROUP@@DEMO", Hosts: []model.Instance{{Ip: "10.0.0.10", Port: 80}}}, failoverDir)
+
+	hr := NewHostReactor(NamingProxy{}, cacheDir, 20, true, NewSubscribeCallback(), false, 0, nil, 0, 0, true, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{})
+	fr := NewFailoverReactor(hr, cacheDir)
+	defer fr.Stop()
+	assert.False(t, fr.IsFailoverSwitch())
+
+	ioutil.WriteFile(cache.GetFileName(Failover_Switch_File, failoverDir), []byte("1"), 0666)
+	fr.checkSwitch()
+	assert.True(t, fr.IsFailoverSwitch())
+	service, ok := fr.GetService("DEFAULT_GROUP@@DEMO", "")
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(service.Hosts))
+
+	hr.failoverReactor = fr
+	service, err := hr.GetServiceInfo(context.Background(), "DEFAULT_GROUP@@DEMO", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.0.10", service.Hosts[0].Ip)
+
+	os.Remove(cache.GetFileName(Failover_Switch_File, failoverDir))
+	fr.checkSwitch()
+	assert.False(t, fr.IsFailoverSwitch())
+}
+
+func TestFailoverReactor_ServerDownSwitchOff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/list"),
+		gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		Return(http_agent.FakeHttpResponse(503, "server down"), nil)
+
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	failoverDir := cacheDir + string(os.PathSeparator) + "failover"
+	cache.WriteServicesToFile(model.Service{Name: "DEFAULT_GROUP@@DEMO", Hosts: []model.Instance{{Ip: "10.0.0.10", Port: 80}}}, failoverDir)
+
+	proxy, _ := NewNamingProxy(clientConfigTest, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	hr := NewHostReactor(proxy, cacheDir, 20, true, NewSubscribeCallback(), false, 0, nil, 0, 0, false, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{})
+	defer hr.Stop()
+	assert.False(t, hr.failoverReactor.IsFailoverSwitch())
+
+	service, err := hr.GetServiceInfo(context.Background(), "DEFAULT_GROUP@@DEMO", "")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(service.Hosts))
+	assert.Equal(t, "10.0.0.10", service.Hosts[0].Ip)
+}
@@ -1,9 +1,401 @@
 package naming_client
 
 import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/nacos-group/nacos-sdk-go/clients/cache"
+	"github.com/nacos-group/nacos-sdk-go/common/audit"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/health_check"
+	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/common/serializer"
+	"github.com/nacos-group/nacos-sdk-go/common/store"
+	"github.com/nacos-group/nacos-sdk-go/mock"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestHostReactor_GetServiceInfo(t *testing.T) {
 
 }
+
+func TestHostReactor_asyncUpdateServiceRateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	var queryCount int64
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/list"),
+		gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context, method string, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			atomic.AddInt64(&queryCount, 1)
+			time.Sleep(100 * time.Millisecond)
+			return http_agent.FakeHttpResponse(200, `{"name":"`+params["serviceName"]+`","cacheMillis":0,"hosts":[{"ip":"10.0.0.10","port":80}]}`), nil
+		})
+
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	proxy, _ := NewNamingProxy(clientConfigTest, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	rateLimit := 5
+	hr := NewHostReactor(proxy, cacheDir, 20, true, NewSubscribeCallback(), false, rateLimit, nil, 0, 0, false, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{})
+	for i := 0; i < 20; i++ {
+		hr.serviceInfoMap.Set("DEFAULT_GROUP@@DEMO"+strconv.Itoa(i), model.Service{Name: "DEFAULT_GROUP@@DEMO" + strconv.Itoa(i)})
+	}
+	start := time.Now()
+	time.Sleep(3 * time.Second)
+	elapsed := time.Since(start).Seconds()
+
+	ceiling := int64(float64(rateLimit)*elapsed) + int64(rateLimit) + 1
+	count := atomic.LoadInt64(&queryCount)
+	assert.True(t, count > 0, "background refresh should query the server")
+	assert.True(t, count <= ceiling, "query count %d exceeds ceiling %d", count, ceiling)
+}
+
+func TestHostReactor_updateServiceNowCoalesce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/list"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx context.Context, method string, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			time.Sleep(200 * time.Millisecond)
+			return http_agent.FakeHttpResponse(200, `{"name":"DEFAULT_GROUP@@DEMO","cacheMillis":1000,"hosts":[{"ip":"10.0.0.10","port":80}]}`), nil
+		})
+
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	proxy, _ := NewNamingProxy(clientConfigTest, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	hr := NewHostReactor(proxy, cacheDir, 20, true, NewSubscribeCallback(), false, 0, nil, 0, 0, false, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{})
+	defer hr.Stop()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, hr.updateServiceNow(context.Background(), "DEFAULT_GROUP@@DEMO", ""))
+		}()
+	}
+	wg.Wait()
+	assert.False(t, hr.updatingMap.Has("DEFAULT_GROUP@@DEMO"))
+	// 查询耗时200ms，刷新间隔延长到2s
+	assert.Equal(t, uint64(2000), hr.refreshInterval("DEFAULT_GROUP@@DEMO", 1000)/100*100)
+}
+
+func TestHostReactor_refreshInterval(t *testing.T) {
+	hr := &HostReactor{latencyMap: cache.NewConcurrentMap()}
+	assert.Equal(t, uint64(1000), hr.refreshInterval("a", 1000))
+	hr.recordLatency("a", 50*time.Millisecond)
+	assert.Equal(t, uint64(1000), hr.refreshInterval("a", 1000))
+	hr.recordLatency("a", 450*time.Millisecond)
+	// 平滑后的耗时为(50*3+450)/4=150ms
+	assert.Equal(t, uint64(1500), hr.refreshInterval("a", 1000))
+	hr.recordLatency("a", 10*time.Second)
+	assert.Equal(t, uint64(1000*Max_Refresh_Backoff), hr.refreshInterval("a", 1000))
+}
+
+func TestHostReactor_hostsChecksum(t *testing.T) {
+	hosts := []model.Instance{
+		{Ip: "10.0.0.10", Port: 80, ClusterName: "a", Metadata: map[string]string{"zone": "a", "version": "1"}},
+		{Ip: "10.0.0.11", Port: 80, ClusterName: "a"},
+	}
+	reordered := []model.Instance{hosts[1], hosts[0]}
+	assert.Equal(t, hostsChecksum(hosts), hostsChecksum(reordered))
+	assert.Equal(t, "10.0.0.10", hosts[0].Ip, "checksum should not reorder the input")
+
+	changed := []model.Instance{hosts[0], {Ip: "10.0.0.11", Port: 80, ClusterName: "a", Weight: 2}}
+	assert.NotEqual(t, hostsChecksum(hosts), hostsChecksum(changed))
+	changed = []model.Instance{{Ip: "10.0.0.10", Port: 80, ClusterName: "a", Metadata: map[string]string{"zone": "a", "version": "2"}}, hosts[1]}
+	assert.NotEqual(t, hostsChecksum(hosts), hostsChecksum(changed))
+	changed = []model.Instance{{Ip: "10.0.0.10", Port: 80, ClusterName: "a", Metadata: map[string]string{"zone": "a", "version": "1"}, Healthy: true}, hosts[1]}
+	assert.NotEqual(t, hostsChecksum(hosts), hostsChecksum(changed))
+	assert.Equal(t, 12, reflect.TypeOf(model.Instance{}).NumField(), "hostsChecksum should cover new fields of model.Instance")
+}
+
+func TestHostReactor_hostsEqual(t *testing.T) {
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	hr := NewHostReactor(NamingProxy{}, cacheDir, 1, true, NewSubscribeCallback(), false, 0, nil, 0, 0, true, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{})
+	hosts := []model.Instance{{Ip: "10.0.0.10", Port: 80}, {Ip: "10.0.0.11", Port: 80}}
+	reordered := []model.Instance{hosts[1], hosts[0]}
+	checksum := hostsChecksum(reordered)
+	// 缓存中没有校验和时计算旧列表的校验和
+	assert.True(t, hr.hostsEqual("DEFAULT_GROUP@@DEMO", hosts, reordered, checksum))
+	hr.checksumMap.Set("DEFAULT_GROUP@@DEMO", checksum+1)
+	assert.False(t, hr.hostsEqual("DEFAULT_GROUP@@DEMO", hosts, reordered, checksum))
+	assert.False(t, hr.hostsEqual("DEFAULT_GROUP@@DEMO", hosts, hosts[:1], checksum))
+}
+
+func benchmarkHostsTest(n int) []model.Instance {
+	hosts := make([]model.Instance, 0, n)
+	for i := 0; i < n; i++ {
+		hosts = append(hosts, model.Instance{
+			Ip:          "10.0." + strconv.Itoa(i/256) + "." + strconv.Itoa(i%256),
+			Port:        8080,
+			Weight:      1,
+			ClusterName: "a",
+			ServiceName: "DEFAULT_GROUP@@DEMO",
+			Metadata:    map[string]string{"version": "1.0.0", "zone": "cn-hangzhou-a", "protocol": "http"},
+			Enable:      true,
+			Healthy:     true,
+		})
+	}
+	return hosts
+}
+
+// 旧的比较方式：对新旧列表排序后反射比较
+func BenchmarkInstancesEqual_DeepEqual(b *testing.B) {
+	oldHosts, newHosts := benchmarkHostsTest(200), benchmarkHostsTest(200)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		reflect.DeepEqual(sortInstances(oldHosts), sortInstances(newHosts))
+	}
+}
+
+// 默认的比较方式：旧列表的校验和已缓存，只需计算新列表的校验和，该结果同时作为下次比较的旧校验和
+func BenchmarkInstancesEqual_Checksum(b *testing.B) {
+	oldHosts, newHosts := benchmarkHostsTest(200), benchmarkHostsTest(200)
+	hr := &HostReactor{checksumMap: cache.NewConcurrentMap()}
+	hr.checksumMap.Set("DEFAULT_GROUP@@DEMO", hostsChecksum(oldHosts))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		hr.hostsEqual("DEFAULT_GROUP@@DEMO", oldHosts, newHosts, hostsChecksum(newHosts))
+	}
+}
+
+func TestHostReactor_GetServiceInfoCacheOnly(t *testing.T) {
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	cache.WriteServicesToFile(model.Service{Name: "DEFAULT_GROUP@@DEMO", Hosts: []model.Instance{{Ip: "10.0.0.10", Port: 80}}}, cacheDir)
+
+	hr := NewHostReactor(NamingProxy{}, cacheDir, 20, true, NewSubscribeCallback(), false, 0, nil, 0, 0, true, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{})
+	service, err := hr.GetServiceInfo(context.Background(), "DEFAULT_GROUP@@DEMO", "")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(service.Hosts))
+
+	_, err = hr.GetServiceInfo(context.Background(), "DEFAULT_GROUP@@OTHER", "")
+	assert.Equal(t, ErrCacheOnlyMode, err)
+}
+
+func TestHostReactor_GetServiceInfoError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/list"),
+		gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		Return(http_agent.FakeHttpResponse(500, "server error"), nil)
+
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	proxy, _ := NewNamingProxy(clientConfigTest, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	hr := NewHostReactor(proxy, cacheDir, 20, true, NewSubscribeCallback(), false, 0, nil, 0, 0, false, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{})
+	defer hr.Stop()
+
+	_, err := hr.GetServiceInfo(context.Background(), "DEFAULT_GROUP@@DEMO", "")
+	assert.NotNil(t, err)
+	assert.False(t, hr.serviceInfoMap.Has("DEFAULT_GROUP@@DEMO"), "placeholder should be removed when the first query fails")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = hr.GetServiceInfo(ctx, "DEFAULT_GROUP@@OTHER", "")
+	assert.Equal(t, context.Canceled, err)
+	assert.False(t, hr.serviceInfoMap.Has("DEFAULT_GROUP@@OTHER"))
+}
+
+func TestHostReactor_CacheStore(t *testing.T) {
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	s := store.NewMemoryStore()
+	cache.WriteServicesToStore(s, "naming/public/", model.Service{Name: "DEFAULT_GROUP@@DEMO", Hosts: []model.Instance{{Ip: "10.0.0.10", Port: 80}}}, serializer.Default())
+
+	hr := NewHostReactor(NamingProxy{}, cacheDir, 1, false, NewSubscribeCallback(), false, 0, nil, 0, 0, true, PushReceiverConfig{},
+		ServiceCacheConfig{Store: s, StorePrefix: "naming/public/"}, SerializerConfig{})
+	service, err := hr.GetServiceInfo(context.Background(), "DEFAULT_GROUP@@DEMO", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.0.10", service.Hosts[0].Ip)
+
+	hr.ProcessServiceJson(`{"name":"DEFAULT_GROUP@@ORDER","clusters":"","hosts":[{"ip":"10.0.0.11","port":80}]}`)
+	keys, _ := s.Keys("naming/public/")
+	assert.Equal(t, []string{"naming/public/DEFAULT_GROUP@@DEMO", "naming/public/DEFAULT_GROUP@@ORDER"}, keys)
+	files, _ := ioutil.ReadDir(cacheDir)
+	assert.Empty(t, files)
+}
+
+func TestHostReactor_ProcessServiceJsonChangeEvent(t *testing.T) {
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	subCallback := NewSubscribeCallback()
+	var events []model.InstanceChangeEvent
+	changeFunc := func(event model.InstanceChangeEvent) {
+		events = append(events, event)
+	}
+	subCallback.AddChangeFuncs("DEFAULT_GROUP@@DEMO", "", &changeFunc)
+	hr := NewHostReactor(NamingProxy{}, cacheDir, 1, true, subCallback, true, 0, nil, 0, 0, true, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{})
+
+	hr.ProcessServiceJson(`{"name":"DEFAULT_GROUP@@DEMO","clusters":"","hosts":[{"ip":"10.0.0.10","port":80,"weight":1},{"ip":"10.0.0.11","port":80,"weight":1}]}`)
+	hr.ProcessServiceJson(`{"name":"DEFAULT_GROUP@@DEMO","clusters":"","hosts":[{"ip":"10.0.0.11","port":80,"weight":2},{"ip":"10.0.0.12","port":80,"weight":1}]}`)
+	// 实例列表不变时不通知
+	hr.ProcessServiceJson(`{"name":"DEFAULT_GROUP@@DEMO","clusters":"","hosts":[{"ip":"10.0.0.12","port":80,"weight":1},{"ip":"10.0.0.11","port":80,"weight":2}]}`)
+
+	assert.Len(t, events, 2)
+	assert.Len(t, events[0].Added, 2)
+	assert.Empty(t, events[0].Removed)
+	assert.Equal(t, "10.0.0.12", events[1].Added[0].Ip)
+	assert.Equal(t, "10.0.0.10", events[1].Removed[0].Ip)
+	assert.Equal(t, "10.0.0.11", events[1].Modified[0].Ip)
+	assert.Equal(t, float64(2), events[1].Modified[0].Weight)
+}
+
+func TestHostReactor_Audit(t *testing.T) {
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	ch := make(chan audit.Record, 10)
+	proxy := NamingProxy{clientConfig: constant.ClientConfig{NamespaceId: "dev", AuditSink: audit.NewChanSink(ch)}}
+	hr := NewHostReactor(proxy, cacheDir, 1, true, NewSubscribeCallback(), true, 0, nil, 0, 0, true, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{})
+
+	hr.ProcessServiceJson(`{"name":"group@@DEMO","clusters":"c1","lastRefTime":1000,"hosts":[{"ip":"10.0.0.10","port":80,"weight":1}]}`)
+	hr.ProcessServiceJson(`{"name":"group@@DEMO","clusters":"c1","lastRefTime":2000,"hosts":[{"ip":"10.0.0.10","port":80,"weight":1}]}`)
+	hr.ProcessServiceJson(`{"name":"group@@DEMO","clusters":"c1","lastRefTime":3000,"hosts":[{"ip":"10.0.0.11","port":80,"weight":1}]}`)
+
+	assert.Equal(t, 2, len(ch))
+	record := <-ch
+	assert.Equal(t, audit.Record{Time: record.Time, Module: audit.Module_Naming, Namespace: "dev", Group: "group", Name: "DEMO", Clusters: "c1",
+		Added: []model.Instance{{Ip: "10.0.0.10", Port: 80, Weight: 1}}, ServerTime: 1000}, record)
+	record = <-ch
+	assert.Equal(t, "10.0.0.11", record.Added[0].Ip)
+	assert.Equal(t, "10.0.0.10", record.Removed[0].Ip)
+	assert.Equal(t, int64(3000), record.ServerTime)
+}
+
+func TestHostReactor_GetServiceInfoMapSnapshot(t *testing.T) {
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	hr := NewHostReactor(NamingProxy{}, cacheDir, 1, true, NewSubscribeCallback(), true, 0, nil, 0, 0, true, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{})
+	hr.ProcessServiceJson(`{"name":"DEFAULT_GROUP@@DEMO","clusters":"a","hosts":[{"ip":"10.0.0.10","port":80}]}`)
+
+	snapshot := hr.GetServiceInfoMapSnapshot()
+	assert.Len(t, snapshot, 1)
+	service := snapshot["DEFAULT_GROUP@@DEMO@@a"]
+	assert.Equal(t, "10.0.0.10", service.Hosts[0].Ip)
+	assert.True(t, hr.GetUpdateTimeMap()["DEFAULT_GROUP@@DEMO@@a"] > 0)
+
+	// 修改快照不影响缓存
+	service.Hosts[0].Ip = "10.0.0.11"
+	assert.Equal(t, "10.0.0.10", hr.GetServiceInfoMapSnapshot()["DEFAULT_GROUP@@DEMO@@a"].Hosts[0].Ip)
+}
+
+func TestHostReactor_evictServices(t *testing.T) {
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	subCallback := NewSubscribeCallback()
+	changeFunc := func(event model.InstanceChangeEvent) {}
+	subCallback.AddChangeFuncs("DEFAULT_GROUP@@SUB", "", &changeFunc)
+	hr := NewHostReactor(NamingProxy{}, cacheDir, 1, true, subCallback, true, 0, nil, 0, 0, true, PushReceiverConfig{}, ServiceCacheConfig{MaxEntries: 2, IdleMs: 60 * 1000}, SerializerConfig{})
+
+	for _, name := range []string{"SUB", "A", "B"} {
+		hr.ProcessServiceJson(`{"name":"DEFAULT_GROUP@@` + name + `","clusters":"","hosts":[{"ip":"10.0.0.10","port":80}]}`)
+		time.Sleep(5 * time.Millisecond)
+	}
+	hr.evictServices()
+	// 超出上限时优先淘汰最久未访问且未订阅的服务
+	services := hr.GetCachedServices()
+	assert.Len(t, services, 2)
+	assert.Equal(t, "DEFAULT_GROUP@@B", services[0].Name)
+	assert.Equal(t, "DEFAULT_GROUP@@SUB", services[1].Name)
+
+	// 未订阅的服务超过空闲时间被淘汰
+	hr.accessTimeMap.Set("DEFAULT_GROUP@@B", uint64(0))
+	hr.accessTimeMap.Set("DEFAULT_GROUP@@SUB", uint64(0))
+	hr.evictServices()
+	services = hr.GetCachedServices()
+	assert.Len(t, services, 1)
+	assert.Equal(t, "DEFAULT_GROUP@@SUB", services[0].Name)
+
+	assert.True(t, hr.PurgeService("DEFAULT_GROUP@@SUB", ""))
+	assert.False(t, hr.PurgeService("DEFAULT_GROUP@@SUB", ""))
+	assert.Empty(t, hr.GetCachedServices())
+	assert.False(t, subCallback.subscribed("DEFAULT_GROUP@@SUB"), "purge should remove callbacks")
+}
+
+func TestHostReactor_releaseUnsubscribed(t *testing.T) {
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	subCallback := NewSubscribeCallback()
+	hr := NewHostReactor(NamingProxy{}, cacheDir, 1, true, subCallback, true, 0, nil, 0, 0, true, PushReceiverConfig{}, ServiceCacheConfig{UnsubscribeGraceMs: 50}, SerializerConfig{})
+	hr.ProcessServiceJson(`{"name":"DEFAULT_GROUP@@DEMO","clusters":"a,b","hosts":[{"ip":"10.0.0.10","port":80}]}`)
+	callback := func(services []model.SubscribeService, err error) {}
+	changeFunc := func(event model.InstanceChangeEvent) {}
+	subCallback.AddCallbackFuncs("DEFAULT_GROUP@@DEMO", "a,b", &callback)
+	subCallback.AddChangeFuncs("DEFAULT_GROUP@@DEMO", "a,b", &changeFunc)
+	assert.Equal(t, []model.SubscribedService{{ServiceName: "DEFAULT_GROUP@@DEMO", Clusters: "a,b", Subscribers: 2}}, hr.GetSubscribedServices())
+
+	// 仍有订阅时不停止刷新
+	subCallback.RemoveCallbackFuncs("DEFAULT_GROUP@@DEMO", "a,b", &callback)
+	hr.markUnsubscribed("DEFAULT_GROUP@@DEMO@@a,b")
+	time.Sleep(60 * time.Millisecond)
+	hr.releaseUnsubscribed()
+	assert.Len(t, hr.GetCachedServices(), 1)
+
+	// 宽限期内重新订阅不停止刷新
+	subCallback.RemoveChangeFuncs("DEFAULT_GROUP@@DEMO", "a,b", &changeFunc)
+	hr.markUnsubscribed("DEFAULT_GROUP@@DEMO@@a,b")
+	subCallback.AddChangeFuncs("DEFAULT_GROUP@@DEMO", "a,b", &changeFunc)
+	hr.markSubscribed("DEFAULT_GROUP@@DEMO@@a,b")
+	time.Sleep(60 * time.Millisecond)
+	hr.releaseUnsubscribed()
+	assert.Len(t, hr.GetCachedServices(), 1)
+
+	subCallback.RemoveChangeFuncs("DEFAULT_GROUP@@DEMO", "a,b", &changeFunc)
+	hr.markUnsubscribed("DEFAULT_GROUP@@DEMO@@a,b")
+	hr.releaseUnsubscribed()
+	assert.Len(t, hr.GetCachedServices(), 1, "should wait for the grace period")
+	time.Sleep(60 * time.Millisecond)
+	hr.releaseUnsubscribed()
+	assert.Empty(t, hr.GetCachedServices())
+	assert.Empty(t, hr.GetSubscribedServices())
+}
+
+func TestHostReactor_HealthCheck(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+	closed, _ := net.Listen("tcp", "127.0.0.1:0")
+	closed.Close()
+	openPort := uint64(listener.Addr().(*net.TCPAddr).Port)
+	closedPort := uint64(closed.Addr().(*net.TCPAddr).Port)
+
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	cache.WriteServicesToFile(model.Service{Name: "DEFAULT_GROUP@@DEMO", Hosts: []model.Instance{
+		{Ip: "127.0.0.1", Port: openPort, Weight: 1, Healthy: true, Enable: true},
+		{Ip: "127.0.0.1", Port: closedPort, Weight: 1, Healthy: true, Enable: true},
+	}}, cacheDir)
+	hr := NewHostReactor(NamingProxy{}, cacheDir, 20, false, NewSubscribeCallback(), false, 0, nil, 0, 0, true, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{})
+	hr.startHealthCheck(health_check.HealthCheckConfig{IntervalMs: 10, Fall: 1})
+	defer hr.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	client := NamingClient{hostReactor: hr}
+	instances, err := client.SelectInstances(vo.SelectInstancesParam{ServiceName: "DEMO", HealthyOnly: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(instances))
+	assert.Equal(t, openPort, instances[0].Port)
+
+	// 本地健康状态不修改缓存
+	cached, _ := hr.serviceInfoMap.Get("DEFAULT_GROUP@@DEMO")
+	assert.True(t, cached.(model.Service).Hosts[1].Healthy)
+}
@@ -1,13 +1,26 @@
 package naming_client
 
 import (
-	"encoding/json"
+	"context"
 	"github.com/nacos-group/nacos-sdk-go/clients/cache"
+	"github.com/nacos-group/nacos-sdk-go/common/audit"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/event"
+	"github.com/nacos-group/nacos-sdk-go/common/health_check"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/common/rate_limiter"
+	"github.com/nacos-group/nacos-sdk-go/common/serializer"
+	"github.com/nacos-group/nacos-sdk-go/common/store"
 	"github.com/nacos-group/nacos-sdk-go/model"
 	"github.com/nacos-group/nacos-sdk-go/utils"
 	nsema "github.com/toolkits/concurrent/semaphore"
-	"log"
+	"hash/fnv"
+	"math"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -16,19 +29,83 @@ type HostReactor struct {
 	cacheDir             string
 	updateThreadNum      int
 	serviceProxy         NamingProxy
-	pushReceiver         PushReceiver
+	pushReceiver         *PushReceiver
+	failoverReactor      *FailoverReactor
 	subCallback          SubscribeCallback
 	updateTimeMap        cache.ConcurrentMap
 	updateCacheWhenEmpty bool
+	updatingMap          cache.ConcurrentMap
+	latencyMap           cache.ConcurrentMap
+	updateRateLimiter    *rate_limiter.TokenBucket
+	instancesEqual       func(oldHosts []model.Instance, newHosts []model.Instance) bool
+	serializer           serializer.Serializer
+	checksumMap          cache.ConcurrentMap
+	cacheSerializer      serializer.Serializer
+	serviceWriter        *cache.ServiceWriter
+	cacheTTL             time.Duration
+	accessTimeMap        cache.ConcurrentMap
+	unsubscribedMap      cache.ConcurrentMap
+	cacheConfig          ServiceCacheConfig
+	evictMutex           sync.Mutex
+	cacheOnly            bool
+	healthChecker        *health_check.HealthChecker
+	stopChan             chan struct{}
+	stopOnce             sync.Once
+	remoteSubscribed     cache.ConcurrentMap
 }
 
 const Default_Update_Thread_Num = 20
 
-func NewHostReactor(serviceProxy NamingProxy, cacheDir string, updateThreadNum int, notLoadCacheAtStart bool, subCallback SubscribeCallback, updateCacheWhenEmpty bool) HostReactor {
+const (
+	// 后台刷新间隔不小于最近查询耗时的该倍数，服务端变慢时自动降低刷新频率
+	Refresh_Latency_Factor = 10
+	// 按查询耗时延长后的刷新间隔不超过服务CacheMillis的该倍数
+	Max_Refresh_Backoff = 6
+)
+
+// 正在进行的一次服务查询，同一服务的其他查询等待其完成并共享结果
+type serviceUpdate struct {
+	done chan struct{}
+	err  error
+}
+
+// 内存中服务缓存的淘汰策略，MaxEntries和IdleMs均为0时不淘汰
+// MaxEntries：缓存的服务数上限，超出时优先淘汰最久未访问且未订阅的服务
+// IdleMs：未订阅的服务超过该时间未被查询即淘汰
+// UnsubscribeGraceMs：服务的最后一个订阅取消后，超过该时间仍未重新订阅即停止后台刷新，为0时使用Default_Unsubscribe_Grace_Ms
+// JavaLayout：缓存文件和容灾文件使用与Java客户端相同的文件名和JSON格式，忽略序列化配置
+// Store：服务缓存写入Store而不是cacheDir，key的前缀为StorePrefix，设置后忽略JavaLayout，容灾文件仍从cacheDir读取
+type ServiceCacheConfig struct {
+	MaxEntries         int
+	IdleMs             uint64
+	UnsubscribeGraceMs uint64
+	JavaLayout         bool
+	Store              store.Store
+	StorePrefix        string
+}
+
+const Default_Unsubscribe_Grace_Ms = 30 * 1000
+
+// 服务数据的序列化方式，为nil时使用serializer.Default()
+// Wire：解析服务端推送和查询返回的JSON
+// Cache：读写服务缓存文件，为nil时与Wire相同
+type SerializerConfig struct {
+	Wire  serializer.Serializer
+	Cache serializer.Serializer
+}
+
+func NewHostReactor(serviceProxy NamingProxy, cacheDir string, updateThreadNum int, notLoadCacheAtStart bool, subCallback SubscribeCallback, updateCacheWhenEmpty bool, updateRateLimit int,
+	instancesEqual func(oldHosts []model.Instance, newHosts []model.Instance) bool, cacheWriteDelayMs uint64, cacheTTLMs uint64, cacheOnly bool, pushConfig PushReceiverConfig,
+	cacheConfig ServiceCacheConfig, serializerConfig SerializerConfig) *HostReactor {
 	if updateThreadNum <= 0 {
 		updateThreadNum = Default_Update_Thread_Num
 	}
-	hr := HostReactor{
+	wireSerializer := serializer.OrDefault(serializerConfig.Wire)
+	cacheSerializer := wireSerializer
+	if serializerConfig.Cache != nil {
+		cacheSerializer = serializerConfig.Cache
+	}
+	hr := &HostReactor{
 		serviceProxy:         serviceProxy,
 		cacheDir:             cacheDir,
 		updateThreadNum:      updateThreadNum,
@@ -36,9 +113,43 @@ func NewHostReactor(serviceProxy NamingProxy, cacheDir string, updateThreadNum i
 		subCallback:          subCallback,
 		updateTimeMap:        cache.NewConcurrentMap(),
 		updateCacheWhenEmpty: updateCacheWhenEmpty,
+		updatingMap:          cache.NewConcurrentMap(),
+		latencyMap:           cache.NewConcurrentMap(),
+		instancesEqual:       instancesEqual,
+		serializer:           wireSerializer,
+		checksumMap:          cache.NewConcurrentMap(),
+		cacheSerializer:      cacheSerializer,
+		serviceWriter:        cache.NewServiceWriterWithSerializer(cacheDir, time.Duration(cacheWriteDelayMs)*time.Millisecond, cacheSerializer),
+		cacheTTL:             time.Duration(cacheTTLMs) * time.Millisecond,
+		accessTimeMap:        cache.NewConcurrentMap(),
+		unsubscribedMap:      cache.NewConcurrentMap(),
+		cacheConfig:          cacheConfig,
+		cacheOnly:            cacheOnly,
+		stopChan:             make(chan struct{}),
+		remoteSubscribed:     cache.NewConcurrentMap(),
+	}
+	if cacheConfig.Store != nil {
+		hr.serviceWriter = cache.NewStoreServiceWriter(cacheConfig.Store, cacheConfig.StorePrefix, time.Duration(cacheWriteDelayMs)*time.Millisecond, cacheSerializer)
+	} else if cacheConfig.JavaLayout {
+		hr.cacheSerializer = serializer.Default()
+		hr.serviceWriter = cache.NewJavaServiceWriter(cacheDir, time.Duration(cacheWriteDelayMs)*time.Millisecond)
 	}
-	pr := NewPushRecevier(&hr)
-	hr.pushReceiver = *pr
+	if hr.cacheConfig.UnsubscribeGraceMs == 0 {
+		hr.cacheConfig.UnsubscribeGraceMs = Default_Unsubscribe_Grace_Ms
+	}
+	if updateRateLimit > 0 {
+		hr.updateRateLimiter = rate_limiter.NewTokenBucket(float64(updateRateLimit), updateRateLimit)
+	}
+	//仅使用缓存时不与服务端交互：只加载磁盘缓存，不启动推送接收和后台刷新
+	if cacheOnly {
+		hr.loadCacheFromDisk()
+		return hr
+	}
+	hr.pushReceiver = NewPushRecevier(hr, pushConfig)
+	if transport := serviceProxy.nacosServer.Transport(); transport != nil {
+		hr.startRemoteSubscriber(transport)
+	}
+	hr.failoverReactor = NewFailoverReactor(hr, cacheDir)
 	if !notLoadCacheAtStart {
 		hr.loadCacheFromDisk()
 	}
@@ -47,87 +158,556 @@ func NewHostReactor(serviceProxy NamingProxy, cacheDir string, updateThreadNum i
 }
 
 func (hr *HostReactor) loadCacheFromDisk() {
-	serviceMap := cache.ReadServicesFromFile(hr.cacheDir)
+	var serviceMap map[string]model.Service
+	if hr.cacheConfig.Store != nil {
+		serviceMap = cache.ReadServicesFromStore(hr.cacheConfig.Store, hr.cacheConfig.StorePrefix, hr.cacheTTL, hr.cacheSerializer)
+	} else {
+		serviceMap = cache.ReadServicesFromFileWithSerializer(hr.cacheDir, hr.cacheTTL, hr.cacheSerializer)
+	}
 	if serviceMap == nil || len(serviceMap) == 0 {
 		return
 	}
 	for k, v := range serviceMap {
 		hr.serviceInfoMap.Set(k, v)
+		hr.checksumMap.Remove(k)
+		hr.touchService(k)
 	}
+	hr.evictServices()
+	hr.serviceProxy.publishEvent(event.CacheLoadedEvent{
+		Namespace: hr.serviceProxy.clientConfig.NamespaceId,
+		CacheDir:  hr.cacheDir,
+		Services:  len(serviceMap),
+	})
 }
 
 func (hr *HostReactor) ProcessServiceJson(result string) {
-	service := utils.JsonToService(result)
-	if service == nil {
+	var service model.Service
+	if err := hr.serializer.Unmarshal([]byte(result), &service); err != nil {
+		logger.Errorf("failed to unmarshal json string:%s err:%v", result, err.Error())
+		return
+	}
+	if len(service.Hosts) == 0 {
+		logger.Warnf("instance list is empty,json string:%s", result)
 		return
 	}
 	cacheKey := utils.GetServiceCacheKey(service.Name, service.Clusters)
-
-	oldDomain, ok := hr.serviceInfoMap.Get(cacheKey)
-	if ok && !hr.updateCacheWhenEmpty {
+	if hr.serviceInfoMap.Has(cacheKey) && !hr.updateCacheWhenEmpty {
 		//if instance list is empty,not to update cache
 		if len(result) == 0 {
-			log.Printf("[ERROR]:do not have useful host, ignore it, name:%s \n", service.Name)
+			logger.Errorf("do not have useful host, ignore it, name:%s", service.Name)
 			return
 		}
 	}
-	if !ok || ok && !reflect.DeepEqual(service.Hosts, oldDomain.(model.Service).Hosts) {
+	hr.processService(service)
+}
+
+// 直接写入服务缓存，与收到推送时一样写入磁盘缓存并通知订阅者，实例列表可为空
+// 未开启CacheOnly时写入的数据会在下次从服务端刷新时被覆盖
+func (hr *HostReactor) PutService(service model.Service) {
+	if service.LastRefTime == 0 {
+		service.LastRefTime = uint64(utils.CurrentMillis())
+	}
+	hr.processService(service)
+	hr.evictServices()
+}
+
+func (hr *HostReactor) processService(service model.Service) {
+	cacheKey := utils.GetServiceCacheKey(service.Name, service.Clusters)
+	oldDomain, ok := hr.serviceInfoMap.Get(cacheKey)
+	checksum := hostsChecksum(service.Hosts)
+	if !ok || ok && !hr.hostsEqual(cacheKey, oldDomain.(model.Service).Hosts, service.Hosts, checksum) {
 		if !ok {
-			log.Println("[INFO] service not found in cache " + cacheKey)
+			logger.Infof("service not found in cache %s", cacheKey)
 		} else {
-			log.Printf("[INFO] service key:%s was updated to:%s \n", cacheKey, utils.ToJsonString(service))
+			logger.Infof("service key:%s was updated to:%s", cacheKey, utils.ToJsonString(service))
 		}
-		cache.WriteServicesToFile(*service, hr.cacheDir)
-		hr.subCallback.ServiceChanged(service)
+		hr.serviceWriter.Write(service)
+		hr.subCallback.ServiceChanged(&service)
+		var oldHosts []model.Instance
+		if ok {
+			oldHosts = oldDomain.(model.Service).Hosts
+		}
+		changeEvent := diffInstances(service.Name, service.Clusters, oldHosts, service.Hosts)
+		hr.subCallback.InstancesChanged(changeEvent)
+		hr.audit(service, changeEvent)
 	}
 	hr.updateTimeMap.Set(cacheKey, uint64(utils.CurrentMillis()))
-	hr.serviceInfoMap.Set(cacheKey, *service)
+	hr.serviceInfoMap.Set(cacheKey, service)
+	hr.checksumMap.Set(cacheKey, checksum)
+	hr.accessTimeMap.SetIfAbsent(cacheKey, uint64(utils.CurrentMillis()))
+}
+
+// 配置了AuditSink时记录实例列表的变化
+func (hr *HostReactor) audit(service model.Service, changeEvent model.InstanceChangeEvent) {
+	sink := hr.serviceProxy.clientConfig.AuditSink
+	if sink == nil || changeEvent.IsEmpty() {
+		return
+	}
+	groupName, serviceName := constant.DEFAULT_GROUP, service.Name
+	if parts := strings.SplitN(service.Name, constant.SERVICE_INFO_SPLITER, 2); len(parts) == 2 {
+		groupName, serviceName = parts[0], parts[1]
+	}
+	audit.Write(sink, audit.Record{
+		Module:     audit.Module_Naming,
+		Namespace:  hr.serviceProxy.clientConfig.NamespaceId,
+		Group:      groupName,
+		Name:       serviceName,
+		Clusters:   service.Clusters,
+		Added:      changeEvent.Added,
+		Removed:    changeEvent.Removed,
+		Modified:   changeEvent.Modified,
+		ServerTime: int64(service.LastRefTime),
+	})
+}
+
+// 配置了InstancesEqual时使用自定义的比较，否则比较实例列表的校验和，
+// 缓存中实例列表的校验和在上次更新时已计算，避免每次推送和轮询都对旧列表做反射比较
+func (hr *HostReactor) hostsEqual(cacheKey string, oldHosts []model.Instance, newHosts []model.Instance, newChecksum uint64) bool {
+	if hr.instancesEqual != nil {
+		return hr.instancesEqual(oldHosts, newHosts)
+	}
+	if len(oldHosts) != len(newHosts) {
+		return false
+	}
+	oldChecksum, ok := hr.checksumMap.Get(cacheKey)
+	if !ok {
+		return hostsChecksum(oldHosts) == newChecksum
+	}
+	return oldChecksum.(uint64) == newChecksum
+}
+
+// 忽略实例顺序的实例列表校验和，逐字段写入fnv哈希，比序列化后计算或反射比较开销更小，
+// model.Instance增加字段时需同步修改
+func hostsChecksum(hosts []model.Instance) uint64 {
+	h := fnv.New64a()
+	buf := make([]byte, 0, 256)
+	var keys []string
+	for _, host := range sortInstances(hosts) {
+		buf = buf[:0]
+		buf = appendChecksumString(buf, host.InstanceId)
+		buf = appendChecksumString(buf, host.Ip)
+		buf = strconv.AppendUint(buf, host.Port, 10)
+		buf = strconv.AppendUint(append(buf, 0), math.Float64bits(host.Weight), 16)
+		buf = appendChecksumString(append(buf, 0), host.ClusterName)
+		buf = appendChecksumString(buf, host.ServiceName)
+		for _, flag := range []bool{host.Valid, host.Marked, host.Enable, host.Healthy, host.Ephemeral} {
+			buf = strconv.AppendBool(buf, flag)
+		}
+		keys = keys[:0]
+		for key := range host.Metadata {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		buf = strconv.AppendInt(append(buf, 0), int64(len(keys)), 10)
+		for _, key := range keys {
+			buf = appendChecksumString(buf, key)
+			buf = appendChecksumString(buf, host.Metadata[key])
+		}
+		h.Write(buf)
+	}
+	return h.Sum64()
+}
+
+// 写入长度前缀，避免相邻字段拼接后产生歧义
+func appendChecksumString(buf []byte, value string) []byte {
+	buf = strconv.AppendInt(buf, int64(len(value)), 10)
+	buf = append(buf, ':')
+	return append(buf, value...)
+}
+
+// 排序键只计算一次，避免比较时反复拼接字符串
+func sortInstances(hosts []model.Instance) []model.Instance {
+	keys := make([]string, len(hosts))
+	index := make([]int, len(hosts))
+	for i, host := range hosts {
+		keys[i] = instanceSortKey(host)
+		index[i] = i
+	}
+	sort.Slice(index, func(i, j int) bool {
+		return keys[index[i]] < keys[index[j]]
+	})
+	sorted := make([]model.Instance, len(hosts))
+	for i, j := range index {
+		sorted[i] = hosts[j]
+	}
+	return sorted
+}
+
+// 对比新旧实例列表，实例内容不同时计为修改
+func diffInstances(serviceName string, clusters string, oldHosts []model.Instance, newHosts []model.Instance) model.InstanceChangeEvent {
+	event := model.InstanceChangeEvent{ServiceName: serviceName, Clusters: clusters}
+	oldMap := make(map[string]model.Instance, len(oldHosts))
+	for _, host := range oldHosts {
+		oldMap[instanceIdentity(host)] = host
+	}
+	for _, host := range sortInstances(newHosts) {
+		key := instanceIdentity(host)
+		old, ok := oldMap[key]
+		if !ok {
+			event.Added = append(event.Added, host)
+		} else if !reflect.DeepEqual(old, host) {
+			event.Modified = append(event.Modified, host)
+		}
+		delete(oldMap, key)
+	}
+	for _, host := range sortInstances(oldHosts) {
+		if _, ok := oldMap[instanceIdentity(host)]; ok {
+			event.Removed = append(event.Removed, host)
+		}
+	}
+	return event
 }
 
-func (hr *HostReactor) GetServiceInfo(serviceName string, clusters string) model.Service {
+func instanceIdentity(instance model.Instance) string {
+	return instance.ClusterName + constant.NAMING_INSTANCE_ID_SPLITTER + instance.Ip + constant.NAMING_INSTANCE_ID_SPLITTER +
+		strconv.Itoa(int(instance.Port))
+}
+
+func instanceSortKey(instance model.Instance) string {
+	return instance.ClusterName + constant.NAMING_INSTANCE_ID_SPLITTER + instance.Ip + constant.NAMING_INSTANCE_ID_SPLITTER +
+		strconv.Itoa(int(instance.Port)) + constant.NAMING_INSTANCE_ID_SPLITTER + instance.InstanceId
+}
+
+// 停止后台刷新和推送接收，并将尚未落盘的服务缓存写入磁盘
+func (hr *HostReactor) Stop() {
+	hr.stopOnce.Do(func() {
+		close(hr.stopChan)
+		if hr.pushReceiver != nil {
+			hr.pushReceiver.Stop()
+		}
+		if hr.failoverReactor != nil {
+			hr.failoverReactor.Stop()
+		}
+		if hr.healthChecker != nil {
+			hr.healthChecker.Stop()
+		}
+		hr.serviceWriter.Flush()
+	})
+}
+
+// 开启客户端健康检查后，本地检查不健康的实例的Healthy为false
+func (hr *HostReactor) GetServiceInfo(ctx context.Context, serviceName string, clusters string) (model.Service, error) {
+	service, err := hr.getServiceInfo(ctx, serviceName, clusters)
+	return hr.applyLocalHealth(service), err
+}
+
+func (hr *HostReactor) getServiceInfo(ctx context.Context, serviceName string, clusters string) (model.Service, error) {
+	if hr.failoverReactor != nil && hr.failoverReactor.IsFailoverSwitch() {
+		if service, ok := hr.failoverReactor.GetService(serviceName, clusters); ok {
+			return service, nil
+		}
+	}
 	key := utils.GetServiceCacheKey(serviceName, clusters)
 	cacheService, ok := hr.serviceInfoMap.Get(key)
 	if !ok {
+		if hr.cacheOnly {
+			return model.Service{Name: serviceName, Clusters: clusters}, ErrCacheOnlyMode
+		}
 		cacheService = model.Service{Name: serviceName, Clusters: clusters}
 		hr.serviceInfoMap.Set(key, cacheService)
-		hr.updateServiceNow(serviceName, clusters)
+		hr.checksumMap.Remove(key)
+		hr.touchService(key)
+		hr.evictServices()
+		if err := hr.updateServiceNow(ctx, serviceName, clusters); err != nil {
+			if hr.failoverReactor != nil {
+				//服务端不可用时使用容灾目录中的快照
+				service, ok := hr.failoverReactor.GetService(serviceName, clusters)
+				hr.serviceProxy.nacosServer.Metrics().ObserveDiskCache("naming", ok)
+				if ok {
+					return service, nil
+				}
+			}
+			hr.removePlaceholder(key)
+			return model.Service{Name: serviceName, Clusters: clusters}, err
+		}
 	}
-	newService, _ := hr.serviceInfoMap.Get(key)
+	hr.touchService(key)
+	newService, ok := hr.serviceInfoMap.Get(key)
+	if !ok {
+		return cacheService.(model.Service), nil
+	}
+	return newService.(model.Service), nil
+}
 
-	return newService.(model.Service)
+// 开启客户端健康检查，检查缓存中所有健康且启用的实例
+func (hr *HostReactor) startHealthCheck(config health_check.HealthCheckConfig) {
+	hr.healthChecker = health_check.NewHealthChecker(config, hr.healthCheckTargets)
 }
 
-func (hr *HostReactor) GetAllServiceInfo(nameSpace string, groupName string, clusters string) []model.Service {
-	result, err := hr.serviceProxy.GetAllServiceInfoList(nameSpace, groupName, clusters)
-	if err != nil {
-		log.Printf("[ERROR]:query all services info return error!nameSpace:%s cluster:%s groupName:%s  err:%s \n", nameSpace, clusters, groupName, err.Error())
-		return nil
+func (hr *HostReactor) healthCheckTargets() []string {
+	var targets []string
+	for _, v := range hr.serviceInfoMap.Items() {
+		for _, host := range v.(model.Service).Hosts {
+			if host.Healthy && host.Enable {
+				targets = append(targets, health_check.Address(host.Ip, host.Port))
+			}
+		}
 	}
-	if result == "" {
-		log.Printf("[ERROR]:query all services info is empty!nameSpace:%s cluster:%s groupName:%s \n", nameSpace, clusters, groupName)
-		return nil
+	return targets
+}
+
+// 返回的服务中本地检查不健康的实例标记为不健康，不修改缓存
+func (hr *HostReactor) applyLocalHealth(service model.Service) model.Service {
+	if hr.healthChecker == nil || len(service.Hosts) == 0 {
+		return service
+	}
+	var hosts []model.Instance
+	for i, host := range service.Hosts {
+		if !host.Healthy || hr.healthChecker.Healthy(host.Ip, host.Port) {
+			continue
+		}
+		if hosts == nil {
+			hosts = make([]model.Instance, len(service.Hosts))
+			copy(hosts, service.Hosts)
+		}
+		hosts[i].Healthy = false
+	}
+	if hosts != nil {
+		service.Hosts = hosts
 	}
+	return service
+}
 
-	var data []model.Service
-	err = json.Unmarshal([]byte(result), &data)
-	if err != nil {
-		log.Printf("[ERROR]: the result of quering all services info json.Unmarshal error !nameSpace:%s cluster:%s groupName:%s \n", nameSpace, clusters, groupName)
-		return nil
+func (hr *HostReactor) touchService(key string) {
+	hr.accessTimeMap.Set(key, uint64(utils.CurrentMillis()))
+}
+
+// 按ServiceCacheConfig淘汰服务缓存，被淘汰的服务不再后台刷新，其订阅回调也一并移除
+func (hr *HostReactor) evictServices() {
+	if hr.cacheConfig.MaxEntries <= 0 && hr.cacheConfig.IdleMs == 0 {
+		return
+	}
+	hr.evictMutex.Lock()
+	defer hr.evictMutex.Unlock()
+	type entry struct {
+		key        string
+		accessTime uint64
+		subscribed bool
+	}
+	now := uint64(utils.CurrentMillis())
+	var entries []entry
+	for _, key := range hr.serviceInfoMap.Keys() {
+		accessTime, ok := hr.accessTimeMap.Get(key)
+		if !ok {
+			accessTime = now
+		}
+		e := entry{key: key, accessTime: accessTime.(uint64), subscribed: hr.subCallback.subscribed(key)}
+		if hr.cacheConfig.IdleMs > 0 && !e.subscribed && now-e.accessTime > hr.cacheConfig.IdleMs {
+			logger.Infof("evict idle service:%s from cache", key)
+			hr.removeService(key)
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if hr.cacheConfig.MaxEntries <= 0 || len(entries) <= hr.cacheConfig.MaxEntries {
+		return
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].subscribed != entries[j].subscribed {
+			return !entries[i].subscribed
+		}
+		return entries[i].accessTime < entries[j].accessTime
+	})
+	for _, e := range entries[:len(entries)-hr.cacheConfig.MaxEntries] {
+		if e.subscribed {
+			logger.Warnf("service cache exceeds max entries:%d, evict subscribed service:%s and remove its callbacks", hr.cacheConfig.MaxEntries, e.key)
+		} else {
+			logger.Infof("service cache exceeds max entries:%d, evict service:%s", hr.cacheConfig.MaxEntries, e.key)
+		}
+		hr.removeService(e.key)
 	}
-	return data
 }
 
-func (hr *HostReactor) updateServiceNow(serviceName string, clusters string) {
-	result, err := hr.serviceProxy.QueryList(serviceName, clusters, hr.pushReceiver.port, false)
-	if err != nil {
-		log.Printf("[ERROR]:query list return error!servieName:%s cluster:%s  err:%s \n", serviceName, clusters, err.Error())
+// 服务重新订阅时取消待停止的后台刷新，长连接可用时通过长连接订阅
+func (hr *HostReactor) markSubscribed(key string) {
+	hr.unsubscribedMap.Remove(key)
+	if !hr.remoteSubscribed.Has(key) {
+		hr.subscribeRemote(key)
+	}
+}
+
+// 服务已没有订阅时记录取消时间，超过UnsubscribeGraceMs后停止后台刷新
+func (hr *HostReactor) markUnsubscribed(key string) {
+	if !hr.subCallback.subscribed(key) {
+		hr.unsubscribedMap.Set(key, uint64(utils.CurrentMillis()))
+	}
+}
+
+// 移除取消订阅超过UnsubscribeGraceMs的服务，下次查询时重新从服务端获取
+func (hr *HostReactor) releaseUnsubscribed() {
+	now := uint64(utils.CurrentMillis())
+	for key, v := range hr.unsubscribedMap.Items() {
+		if hr.subCallback.subscribed(key) {
+			hr.unsubscribedMap.Remove(key)
+			continue
+		}
+		if now-v.(uint64) >= hr.cacheConfig.UnsubscribeGraceMs {
+			logger.Infof("service:%s has no subscriber, stop refreshing it", key)
+			hr.removeService(key)
+		}
+	}
+}
+
+// 返回所有有订阅回调的服务
+func (hr *HostReactor) GetSubscribedServices() []model.SubscribedService {
+	var services []model.SubscribedService
+	for _, key := range hr.subCallback.subscribedKeys() {
+		service := model.SubscribedService{ServiceName: key, Subscribers: hr.subCallback.subscribers(key)}
+		// 缓存key为group@@service[@@clusters]
+		if parts := strings.SplitN(key, constant.SERVICE_INFO_SPLITER, 3); len(parts) == 3 {
+			service.ServiceName = parts[0] + constant.SERVICE_INFO_SPLITER + parts[1]
+			service.Clusters = parts[2]
+		}
+		services = append(services, service)
+	}
+	return services
+}
+
+// 从内存缓存中移除服务并取消其订阅，磁盘缓存保留用于容灾
+func (hr *HostReactor) removeService(key string) {
+	hr.serviceInfoMap.Remove(key)
+	hr.checksumMap.Remove(key)
+	hr.updateTimeMap.Remove(key)
+	hr.latencyMap.Remove(key)
+	hr.accessTimeMap.Remove(key)
+	hr.unsubscribedMap.Remove(key)
+	hr.subCallback.removeAll(key)
+	if hr.pushReceiver != nil {
+		hr.pushReceiver.forget(key)
+	}
+	hr.unsubscribeRemote(key)
+}
+
+// 首次查询失败时移除占位的空服务，已被并发查询或推送填充的服务保留
+// 已订阅的服务保留占位，由后台刷新在服务端恢复后继续拉取并通知订阅者
+func (hr *HostReactor) removePlaceholder(key string) {
+	if hr.subCallback.subscribed(key) {
 		return
 	}
-	if result == "" {
-		log.Printf("[ERROR]:query list is empty!servieName:%s cluster:%s \n", serviceName, clusters)
+	v, ok := hr.serviceInfoMap.Get(key)
+	if !ok {
 		return
 	}
+	if service := v.(model.Service); service.LastRefTime != 0 || len(service.Hosts) != 0 {
+		return
+	}
+	hr.serviceInfoMap.Remove(key)
+	hr.checksumMap.Remove(key)
+	hr.accessTimeMap.Remove(key)
+	if hr.pushReceiver != nil {
+		hr.pushReceiver.forget(key)
+	}
+}
+
+// 返回内存中缓存的所有服务
+func (hr *HostReactor) GetCachedServices() []model.Service {
+	var services []model.Service
+	for _, v := range hr.serviceInfoMap.Items() {
+		services = append(services, v.(model.Service))
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return utils.GetServiceCacheKey(services[i].Name, services[i].Clusters) < utils.GetServiceCacheKey(services[j].Name, services[j].Clusters)
+	})
+	return services
+}
+
+// 返回内存中缓存的服务，key为服务名与集群组成的缓存key，实例列表为副本，修改不影响缓存
+func (hr *HostReactor) GetServiceInfoMapSnapshot() map[string]model.Service {
+	snapshot := map[string]model.Service{}
+	for k, v := range hr.serviceInfoMap.Items() {
+		service := v.(model.Service)
+		service.Hosts = append([]model.Instance(nil), service.Hosts...)
+		snapshot[k] = service
+	}
+	return snapshot
+}
+
+// 返回每个缓存的服务最后一次从服务端或推送更新的时间，单位毫秒
+func (hr *HostReactor) GetUpdateTimeMap() map[string]uint64 {
+	updateTimes := map[string]uint64{}
+	for k, v := range hr.updateTimeMap.Items() {
+		updateTimes[k] = v.(uint64)
+	}
+	return updateTimes
+}
+
+// 手动移除服务缓存，服务不在缓存中时返回false
+func (hr *HostReactor) PurgeService(serviceName string, clusters string) bool {
+	key := utils.GetServiceCacheKey(serviceName, clusters)
+	if !hr.serviceInfoMap.Has(key) {
+		return false
+	}
+	hr.removeService(key)
+	return true
+}
+
+// 同一服务同时只有一个查询，查询进行中时其他调用等待其完成并返回相同的结果
+func (hr *HostReactor) updateServiceNow(ctx context.Context, serviceName string, clusters string) error {
+	key := utils.GetServiceCacheKey(serviceName, clusters)
+	update := &serviceUpdate{done: make(chan struct{})}
+	if !hr.updatingMap.SetIfAbsent(key, update) {
+		if v, ok := hr.updatingMap.Get(key); ok {
+			running := v.(*serviceUpdate)
+			select {
+			case <-running.done:
+				return running.err
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		// 查询恰好结束，重新发起
+		return hr.updateServiceNow(ctx, serviceName, clusters)
+	}
+	start := time.Now()
+	update.err = hr.queryService(ctx, serviceName, clusters)
+	hr.recordLatency(key, time.Since(start))
+	hr.updatingMap.Remove(key)
+	close(update.done)
+	return update.err
+}
+
+// 已通过长连接订阅的服务由长连接推送变化，查询时不再要求服务端通过UDP推送
+func (hr *HostReactor) queryService(ctx context.Context, serviceName string, clusters string) error {
+	udpPort := hr.pushReceiver.Port()
+	if hr.pushedByRemote(utils.GetServiceCacheKey(serviceName, clusters)) {
+		udpPort = 0
+	}
+	result, err := hr.serviceProxy.QueryList(ctx, serviceName, clusters, udpPort, false)
+	if err != nil {
+		logger.Errorf("query list return error!servieName:%s cluster:%s  err:%s", serviceName, clusters, err.Error())
+		return err
+	}
+	if result == "" {
+		logger.Errorf("query list is empty!servieName:%s cluster:%s", serviceName, clusters)
+		return nil
+	}
 	hr.ProcessServiceJson(result)
+	return nil
+}
+
+// 记录平滑后的查询耗时，单位毫秒
+func (hr *HostReactor) recordLatency(key string, latency time.Duration) {
+	ms := uint64(latency / time.Millisecond)
+	if v, ok := hr.latencyMap.Get(key); ok {
+		ms = (v.(uint64)*3 + ms) / 4
+	}
+	hr.latencyMap.Set(key, ms)
+}
+
+// 服务的后台刷新间隔，单位毫秒
+func (hr *HostReactor) refreshInterval(key string, cacheMillis uint64) uint64 {
+	v, ok := hr.latencyMap.Get(key)
+	if !ok {
+		return cacheMillis
+	}
+	interval := v.(uint64) * Refresh_Latency_Factor
+	if interval <= cacheMillis {
+		return cacheMillis
+	}
+	if cacheMillis > 0 && interval > cacheMillis*Max_Refresh_Backoff {
+		return cacheMillis * Max_Refresh_Backoff
+	}
+	return interval
 }
 
 func (hr *HostReactor) asyncUpdateService() {
@@ -135,19 +715,43 @@ func (hr *HostReactor) asyncUpdateService() {
 	for {
 		for _, v := range hr.serviceInfoMap.Items() {
 			service := v.(model.Service)
-			lastRefTime, ok := hr.updateTimeMap.Get(utils.GetServiceCacheKey(service.Name, service.Clusters))
+			cacheKey := utils.GetServiceCacheKey(service.Name, service.Clusters)
+			lastRefTime, ok := hr.updateTimeMap.Get(cacheKey)
 			if !ok {
 				lastRefTime = uint64(0)
 			}
-			if uint64(utils.CurrentMillis())-lastRefTime.(uint64) > service.CacheMillis {
+			if hr.pushedByRemote(cacheKey) {
+				continue
+			}
+			if uint64(utils.CurrentMillis())-lastRefTime.(uint64) > hr.refreshInterval(cacheKey, service.CacheMillis) {
+				//上一次查询还未完成，不重复调度
+				if hr.updatingMap.Has(cacheKey) {
+					continue
+				}
+				if hr.updateRateLimiter != nil {
+					hr.updateRateLimiter.Acquire()
+				}
 				sema.Acquire()
+				// 停止时长连接随之关闭，不再回退到HTTP查询
+				select {
+				case <-hr.stopChan:
+					sema.Release()
+					return
+				default:
+				}
 				go func() {
-					hr.updateServiceNow(service.Name, service.Clusters)
+					hr.updateServiceNow(context.Background(), service.Name, service.Clusters)
 					sema.Release()
 				}()
 			}
 		}
-		time.Sleep(1 * time.Second)
+		hr.releaseUnsubscribed()
+		hr.evictServices()
+		select {
+		case <-hr.stopChan:
+			return
+		case <-time.After(1 * time.Second):
+		}
 	}
 
 }
@@ -1,31 +1,144 @@
 package naming_client
 
 import (
+	"context"
+	"fmt"
 	"github.com/nacos-group/nacos-sdk-go/clients/cache"
 	"github.com/nacos-group/nacos-sdk-go/clients/nacos_client"
 	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/event"
+	"github.com/nacos-group/nacos-sdk-go/common/load_balancer"
 	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/common/monitor"
+	"github.com/nacos-group/nacos-sdk-go/common/nacos_server"
+	"github.com/nacos-group/nacos-sdk-go/common/shutdown"
+	"github.com/nacos-group/nacos-sdk-go/common/validator"
 	"github.com/nacos-group/nacos-sdk-go/model"
 	"github.com/nacos-group/nacos-sdk-go/utils"
 	"github.com/nacos-group/nacos-sdk-go/vo"
 	"github.com/pkg/errors"
-	"math"
-	"math/rand"
 	"os"
+	"path"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
 type NamingClient struct {
 	nacos_client.INacosClient
-	hostReactor  HostReactor
-	serviceProxy NamingProxy
-	subCallback  SubscribeCallback
-	beatReactor  BeatReactor
-	indexMap     cache.ConcurrentMap
+	hostReactor       *HostReactor
+	serviceProxy      NamingProxy
+	subCallback       SubscribeCallback
+	beatReactor       *BeatReactor
+	balancerMap       cache.ConcurrentMap
+	hashRingMap       cache.ConcurrentMap
+	virtualNodes      int
+	loadBalancer      load_balancer.LoadBalancer
+	warmUp            time.Duration
+	locality          *load_balancer.LocalityConfig
+	protectThreshold  float64
+	deregisterOnClose bool
+	// 取消EnableAutoDeregister注册的退出钩子
+	removeShutdownHook func()
+	restorer           *subscriptionRestorer
+	redoService        *RedoService
+}
+
+const (
+	Default_Page_Size = 10
+	// SearchService在本地过滤时每次从服务端拉取的服务数
+	Default_Search_Page_Size = 500
+	// DrainInstance降低权重的次数
+	Default_Drain_Steps = 10
+)
+
+var ErrCacheOnlyMode = errors.New("naming client is running in cache-only mode")
+
+// 批量操作的并发数
+const Default_Batch_Concurrency = 10
+
+// 批量操作中失败的实例，key为实例在参数中的下标
+type BatchError struct {
+	Errors map[int]error
+}
+
+func (e *BatchError) Error() string {
+	indexes := make([]int, 0, len(e.Errors))
+	for i := range e.Errors {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	msgs := make([]string, 0, len(indexes))
+	for _, i := range indexes {
+		msgs = append(msgs, fmt.Sprintf("instances[%d]:%s", i, e.Errors[i].Error()))
+	}
+	return fmt.Sprintf("%d of batch failed: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// 以最多Default_Batch_Concurrency的并发对indexes中的每个下标执行fn，返回失败的下标及错误
+// ctx结束后不再启动新的调用，未执行的下标记为ctx的错误
+func runBatch(ctx context.Context, indexes []int, fn func(i int) error) map[int]error {
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	errs := map[int]error{}
+	sema := make(chan struct{}, Default_Batch_Concurrency)
+	for n, i := range indexes {
+		err := ctx.Err()
+		if err == nil {
+			select {
+			case sema <- struct{}{}:
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+		}
+		if err != nil {
+			wg.Wait()
+			for _, j := range indexes[n:] {
+				errs[j] = err
+			}
+			return errs
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sema }()
+			if err := fn(i); err != nil {
+				mutex.Lock()
+				errs[i] = err
+				mutex.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	return errs
+}
+
+func batchResult(errs map[int]error) (bool, error) {
+	if len(errs) > 0 {
+		return false, &BatchError{Errors: errs}
+	}
+	return true, nil
+}
+
+func batchIndexes(n int) []int {
+	indexes := make([]int, n)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	return indexes
 }
 
 func NewNamingClient(nc nacos_client.INacosClient) (NamingClient, error) {
+	return newNamingClient(nc, nil)
+}
+
+// 使用已有的NacosServer创建客户端，用于多个命名空间的客户端共享服务端列表和鉴权信息
+func NewNamingClientWithServer(nc nacos_client.INacosClient, nacosServer nacos_server.NacosServer) (NamingClient, error) {
+	return newNamingClient(nc, &nacosServer)
+}
+
+func newNamingClient(nc nacos_client.INacosClient, nacosServer *nacos_server.NacosServer) (NamingClient, error) {
 	naming := NamingClient{}
 	clientConfig, err :=
 		nc.GetClientConfig()
@@ -40,28 +153,212 @@ func NewNamingClient(nc nacos_client.INacosClient) (NamingClient, error) {
 	if err != nil {
 		return naming, err
 	}
-	err = logger.InitLog(clientConfig.LogDir)
-	if err != nil {
+	if clientConfig.Logger != nil {
+		logger.SetLogger(clientConfig.Logger)
+	} else if err = logger.InitLog(clientConfig.LogDir, clientConfig.LogLevel); err != nil {
 		return naming, err
 	}
 	naming.subCallback = NewSubscribeCallback()
-	naming.serviceProxy, err = NewNamingProxy(clientConfig, serverConfig, httpAgent)
-	if err != nil {
+	metrics := monitor.NewRecorder(clientConfig.EnableMetrics, clientConfig.MetricsRegistry)
+	naming.subCallback.executor = newCallbackExecutor(clientConfig.CallbackExecutor, metrics)
+	naming.subCallback.metrics = metrics
+	if nacosServer != nil {
+		naming.serviceProxy = NamingProxy{clientConfig: clientConfig, nacosServer: *nacosServer,
+			localIp: newLocalIpDetector(clientConfig, *nacosServer)}
+	} else if naming.serviceProxy, err = NewNamingProxy(clientConfig, serverConfig, httpAgent); err != nil {
 		return naming, err
 	}
-	naming.hostReactor = NewHostReactor(naming.serviceProxy, clientConfig.CacheDir+string(os.PathSeparator)+"naming",
-		clientConfig.UpdateThreadNum, clientConfig.NotLoadCacheAtStart, naming.subCallback, clientConfig.UpdateCacheWhenEmpty)
+	naming.hostReactor = NewHostReactor(naming.serviceProxy, namingCacheDir(clientConfig),
+		clientConfig.UpdateThreadNum, clientConfig.NotLoadCacheAtStart, naming.subCallback, clientConfig.UpdateCacheWhenEmpty,
+		clientConfig.UpdateRateLimit, clientConfig.InstancesEqual, clientConfig.CacheWriteDelayMs, clientConfig.CacheTTLMs,
+		clientConfig.CacheOnly, PushReceiverConfig{Ip: clientConfig.UdpIp, Port: clientConfig.UdpPort, OnError: clientConfig.OnPushError},
+		ServiceCacheConfig{MaxEntries: clientConfig.MaxCachedServices, IdleMs: clientConfig.CachedServiceIdleMs,
+			UnsubscribeGraceMs: clientConfig.UnsubscribeGraceMs, JavaLayout: clientConfig.JavaCacheLayout,
+			Store: clientConfig.CacheStore, StorePrefix: namingStorePrefix(clientConfig)},
+		SerializerConfig{Wire: clientConfig.Serializer, Cache: clientConfig.CacheSerializer})
+	if clientConfig.HealthCheck != nil {
+		naming.hostReactor.startHealthCheck(*clientConfig.HealthCheck)
+	}
 	naming.beatReactor = NewBeatReactor(naming.serviceProxy, clientConfig.BeatInterval)
-	naming.indexMap = cache.NewConcurrentMap()
+	naming.balancerMap = cache.NewConcurrentMap()
+	naming.hashRingMap = cache.NewConcurrentMap()
+	naming.virtualNodes = clientConfig.HashRingVirtualNodes
+	naming.loadBalancer = clientConfig.LoadBalancer
+	naming.warmUp = time.Duration(clientConfig.WarmUpMs) * time.Millisecond
+	naming.locality = clientConfig.Locality
+	naming.protectThreshold = clientConfig.ProtectThreshold
+	naming.deregisterOnClose = clientConfig.DeregisterOnClose
+	if clientConfig.EnableAutoDeregister && !clientConfig.CacheOnly {
+		naming.removeShutdownHook = shutdown.Register(func() {
+			naming.DeregisterAllInstances()
+			naming.beatReactor.Stop()
+		})
+	}
+	naming.redoService = NewRedoService(naming.serviceProxy, naming.hostReactor)
+	if naming.restorer = newSubscriptionRestorer(clientConfig); naming.restorer != nil {
+		go naming.restoreSubscriptions()
+	}
 
 	return naming, nil
 }
 
+// 与Java客户端共享缓存时使用CacheDir/naming/<namespaceId>
+func namingCacheDir(clientConfig constant.ClientConfig) string {
+	cacheDir := clientConfig.CacheDir + string(os.PathSeparator) + "naming"
+	if !clientConfig.JavaCacheLayout {
+		return cacheDir
+	}
+	namespace := clientConfig.NamespaceId
+	if namespace == "" {
+		namespace = constant.DEFAULT_NAMESPACE_ID
+	}
+	return cacheDir + string(os.PathSeparator) + namespace
+}
+
+// 服务缓存在ClientConfig.CacheStore中的key前缀：naming/<namespace>/，多个命名空间可共享同一个Store
+func namingStorePrefix(clientConfig constant.ClientConfig) string {
+	namespace := clientConfig.NamespaceId
+	if namespace == "" {
+		namespace = constant.DEFAULT_NAMESPACE_ID
+	}
+	return "naming/" + namespace + "/"
+}
+
 // 注册服务实例
 func (sc *NamingClient) RegisterInstance(param vo.RegisterInstanceParam) (bool, error) {
+	return sc.RegisterInstanceWithContext(context.Background(), param)
+}
+
+func (sc *NamingClient) RegisterInstanceWithContext(ctx context.Context, param vo.RegisterInstanceParam) (bool, error) {
+	if sc.hostReactor.cacheOnly {
+		return false, ErrCacheOnlyMode
+	}
+	registration, err := sc.newRegistration("RegisterInstance", param)
+	if err != nil {
+		return false, err
+	}
+	if err = sc.register(ctx, registration); err != nil {
+		return false, err
+	}
+	return true, nil
+
+}
+
+// 校验并补全后待注册的实例
+type registration struct {
+	serviceName string
+	groupName   string
+	instance    model.Instance
+	beatInfo    model.BeatInfo
+}
+
+func (sc *NamingClient) newRegistration(method string, param vo.RegisterInstanceParam) (registration, error) {
+	if err := validator.New(method).ServiceName("serviceName", param.ServiceName).GroupName("groupName", param.GroupName).
+		Port("port", param.Port).Weight("weight", param.Weight).Err(); err != nil {
+		return registration{}, err
+	}
+	if param.GroupName == "" {
+		param.GroupName = constant.DEFAULT_GROUP
+	}
+	if param.Ip == "" {
+		if param.Ip = sc.serviceProxy.LocalIp(); param.Ip == "" {
+			return registration{}, errors.New("[client." + method + "] Ip is empty and no local ip is available")
+		}
+	}
+	return registration{
+		serviceName: utils.GetGroupName(param.ServiceName, param.GroupName),
+		groupName:   param.GroupName,
+		instance: model.Instance{
+			Ip:          param.Ip,
+			Port:        param.Port,
+			Metadata:    param.Metadata,
+			ClusterName: param.ClusterName,
+			Healthy:     param.Healthy,
+			Enable:      param.Enable,
+			Weight:      param.Weight,
+			Ephemeral:   param.Ephemeral,
+		},
+		beatInfo: model.BeatInfo{
+			Ip:          param.Ip,
+			Port:        param.Port,
+			Metadata:    param.Metadata,
+			ServiceName: utils.GetGroupName(param.ServiceName, param.GroupName),
+			Cluster:     param.ClusterName,
+			Weight:      param.Weight,
+			Period:      utils.GetDurationWithDefault(param.Metadata, constant.HEART_BEAT_INTERVAL, time.Second*5),
+			Disabled:    !param.Enable,
+		},
+	}, nil
+}
+
+func (sc *NamingClient) register(ctx context.Context, r registration) error {
+	if _, err := sc.serviceProxy.RegisterInstance(ctx, r.serviceName, r.groupName, r.instance); err != nil {
+		return err
+	}
+	sc.registered(r)
+	return nil
+}
+
+// 注册成功后，临时实例开始发送心跳并缓存用于重连后重新注册
+func (sc *NamingClient) registered(r registration) {
+	if r.instance.Ephemeral {
+		sc.beatReactor.AddBeatInfo(r.serviceName, r.beatInfo)
+		sc.redoService.CacheInstance(r.serviceName, r.groupName, r.instance)
+	}
+}
+
+// 注销服务实例
+func (sc *NamingClient) DeregisterInstance(param vo.DeregisterInstanceParam) (bool, error) {
+	return sc.DeregisterInstanceWithContext(context.Background(), param)
+}
+
+func (sc *NamingClient) DeregisterInstanceWithContext(ctx context.Context, param vo.DeregisterInstanceParam) (bool, error) {
+	if sc.hostReactor.cacheOnly {
+		return false, ErrCacheOnlyMode
+	}
+	if err := validator.New("DeregisterInstance").ServiceName("serviceName", param.ServiceName).GroupName("groupName", param.GroupName).
+		Port("port", param.Port).Err(); err != nil {
+		return false, err
+	}
+	if param.GroupName == "" {
+		param.GroupName = constant.DEFAULT_GROUP
+	}
+	if param.Ip == "" {
+		param.Ip = sc.serviceProxy.LocalIp()
+	}
+	serviceName := utils.GetGroupName(param.ServiceName, param.GroupName)
+	//通过该客户端注册的临时实例一定以临时实例注销，其余实例以参数为准
+	ephemeral := param.Ephemeral || sc.beatReactor.HasBeatInfo(serviceName, param.Ip, param.Port)
+	_, err := sc.serviceProxy.DeregisterInstance(ctx, serviceName, param.Ip, param.Port, param.Cluster, ephemeral)
+	if err != nil {
+		return false, err
+	}
+	if ephemeral {
+		sc.beatReactor.RemoveBeatInfo(serviceName, param.Ip, param.Port)
+		sc.redoService.RemoveInstance(serviceName, param.Ip, param.Port)
+	}
+	return true, nil
+}
+
+// 修改服务实例的权重、可用状态和元数据，适用于持久化实例和通过该客户端注册的临时实例
+func (sc *NamingClient) UpdateInstance(param vo.UpdateInstanceParam) (bool, error) {
+	return sc.UpdateInstanceWithContext(context.Background(), param)
+}
+
+func (sc *NamingClient) UpdateInstanceWithContext(ctx context.Context, param vo.UpdateInstanceParam) (bool, error) {
+	if sc.hostReactor.cacheOnly {
+		return false, ErrCacheOnlyMode
+	}
+	if err := validator.New("UpdateInstance").ServiceName("serviceName", param.ServiceName).GroupName("groupName", param.GroupName).
+		Required("ip", param.Ip).Port("port", param.Port).Weight("weight", param.Weight).Err(); err != nil {
+		return false, err
+	}
 	if param.GroupName == "" {
 		param.GroupName = constant.DEFAULT_GROUP
 	}
+	serviceName := utils.GetGroupName(param.ServiceName, param.GroupName)
+	//通过该客户端注册的临时实例以临时实例修改，之后的心跳携带新的权重和元数据，无需重新注册
+	ephemeral := param.Ephemeral || sc.beatReactor.HasBeatInfo(serviceName, param.Ip, param.Port)
 	instance := model.Instance{
 		Ip:          param.Ip,
 		Port:        param.Port,
@@ -70,66 +367,397 @@ func (sc *NamingClient) RegisterInstance(param vo.RegisterInstanceParam) (bool,
 		Healthy:     param.Healthy,
 		Enable:      param.Enable,
 		Weight:      param.Weight,
-		Ephemeral:   param.Ephemeral,
+		Ephemeral:   ephemeral,
+	}
+	_, err := sc.serviceProxy.UpdateInstance(ctx, serviceName, param.GroupName, instance)
+	if err != nil {
+		return false, err
+	}
+	if ephemeral {
+		sc.beatReactor.UpdateBeatInfo(serviceName, param.Ip, param.Port, param.Weight, param.Enable, param.Metadata)
+		sc.redoService.UpdateInstance(serviceName, param.Ip, param.Port, param.Weight, param.Enable, param.Metadata)
+	}
+	return true, nil
+}
+
+// 在duration内分Steps次将实例权重均匀降为0，之后将实例设为不可用，用于发布前平滑摘除流量
+// 阻塞直到下线完成，ctx取消时停止并保留当前权重
+func (sc *NamingClient) DrainInstance(param vo.DrainInstanceParam, duration time.Duration) (bool, error) {
+	return sc.DrainInstanceWithContext(context.Background(), param, duration)
+}
+
+func (sc *NamingClient) DrainInstanceWithContext(ctx context.Context, param vo.DrainInstanceParam, duration time.Duration) (bool, error) {
+	if sc.hostReactor.cacheOnly {
+		return false, ErrCacheOnlyMode
+	}
+	if err := validator.New("DrainInstance").ServiceName("serviceName", param.ServiceName).GroupName("groupName", param.GroupName).
+		Required("ip", param.Ip).Port("port", param.Port).Err(); err != nil {
+		return false, err
+	}
+	if param.GroupName == "" {
+		param.GroupName = constant.DEFAULT_GROUP
+	}
+	service, err := sc.hostReactor.GetServiceInfo(ctx, utils.GetGroupName(param.ServiceName, param.GroupName), param.ClusterName)
+	if err != nil {
+		return false, err
+	}
+	var current *model.Instance
+	for i, host := range service.Hosts {
+		if host.Ip == param.Ip && host.Port == param.Port {
+			current = &service.Hosts[i]
+			break
+		}
+	}
+	if current == nil {
+		return false, errors.New("[client.DrainInstance] instance not found")
+	}
+	steps := param.Steps
+	if steps <= 0 {
+		steps = Default_Drain_Steps
 	}
-	beatInfo := model.BeatInfo{
+	update := vo.UpdateInstanceParam{
 		Ip:          param.Ip,
 		Port:        param.Port,
-		Metadata:    param.Metadata,
-		ServiceName: utils.GetGroupName(param.ServiceName, param.GroupName),
-		Cluster:     param.ClusterName,
-		Weight:      param.Weight,
-		Period:      utils.GetDurationWithDefault(param.Metadata, constant.HEART_BEAT_INTERVAL, time.Second*5),
+		ClusterName: current.ClusterName,
+		ServiceName: param.ServiceName,
+		GroupName:   param.GroupName,
+		Ephemeral:   param.Ephemeral,
+		Healthy:     current.Healthy,
+		Metadata:    current.Metadata,
+		Enable:      true,
+	}
+	interval := duration / time.Duration(steps)
+	for i := 1; i <= steps+1; i++ {
+		if i > 1 {
+			timer := time.NewTimer(interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return false, ctx.Err()
+			case <-timer.C:
+			}
+		} else if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		if i <= steps {
+			update.Weight = current.Weight * float64(steps-i) / float64(steps)
+		} else {
+			update.Enable = false
+		}
+		if _, err = sc.UpdateInstanceWithContext(ctx, update); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// 暂停通过该客户端注册的临时实例的心跳，服务端在心跳超时后将实例标记为不健康，用于维护期间摘除流量而不注销实例
+// 暂停期间连接恢复时不重新注册该实例
+func (sc *NamingClient) PauseHeartbeat(param vo.HeartbeatParam) (bool, error) {
+	return sc.pauseHeartbeat("PauseHeartbeat", param, true)
+}
+
+// 恢复实例的心跳，服务端已摘除实例时以暂停前的权重和元数据重新注册
+func (sc *NamingClient) ResumeHeartbeat(param vo.HeartbeatParam) (bool, error) {
+	return sc.pauseHeartbeat("ResumeHeartbeat", param, false)
+}
+
+func (sc *NamingClient) pauseHeartbeat(method string, param vo.HeartbeatParam, paused bool) (bool, error) {
+	if sc.hostReactor.cacheOnly {
+		return false, ErrCacheOnlyMode
+	}
+	if err := validator.New(method).ServiceName("serviceName", param.ServiceName).GroupName("groupName", param.GroupName).
+		Required("ip", param.Ip).Port("port", param.Port).Err(); err != nil {
+		return false, err
 	}
-	_, err := sc.serviceProxy.RegisterInstance(utils.GetGroupName(param.ServiceName, param.GroupName), param.GroupName, instance)
+	if param.GroupName == "" {
+		param.GroupName = constant.DEFAULT_GROUP
+	}
+	serviceName := utils.GetGroupName(param.ServiceName, param.GroupName)
+	if !sc.beatReactor.PauseBeat(serviceName, param.Ip, param.Port, paused) {
+		return false, errors.New("[client." + method + "] instance is not registered as ephemeral instance by this client")
+	}
+	sc.redoService.PauseInstance(serviceName, param.Ip, param.Port, paused)
+	return true, nil
+}
+
+// 批量注册服务实例，同一服务的实例通过服务端的批量接口一次注册
+// 服务端不支持批量接口时改为各实例并发注册，部分失败时返回*BatchError
+func (sc *NamingClient) BatchRegisterInstance(param vo.BatchRegisterInstanceParam) (bool, error) {
+	return sc.BatchRegisterInstanceWithContext(context.Background(), param)
+}
+
+func (sc *NamingClient) BatchRegisterInstanceWithContext(ctx context.Context, param vo.BatchRegisterInstanceParam) (bool, error) {
+	if sc.hostReactor.cacheOnly {
+		return false, ErrCacheOnlyMode
+	}
+	errs := map[int]error{}
+	registrations := make([]registration, len(param.Instances))
+	var serviceNames []string
+	byService := map[string][]int{}
+	for i, instance := range param.Instances {
+		r, err := sc.newRegistration("BatchRegisterInstance", instance)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		registrations[i] = r
+		if _, ok := byService[r.serviceName]; !ok {
+			serviceNames = append(serviceNames, r.serviceName)
+		}
+		byService[r.serviceName] = append(byService[r.serviceName], i)
+	}
+	var singles []int
+	for _, serviceName := range serviceNames {
+		indexes := byService[serviceName]
+		instances := make([]model.Instance, 0, len(indexes))
+		for _, i := range indexes {
+			instances = append(instances, registrations[i].instance)
+		}
+		_, err := sc.serviceProxy.BatchRegisterInstance(ctx, serviceName, registrations[indexes[0]].groupName, instances)
+		if err == ErrBatchUnsupported {
+			singles = append(singles, indexes...)
+			continue
+		}
+		for _, i := range indexes {
+			if err != nil {
+				errs[i] = err
+			} else {
+				sc.registered(registrations[i])
+			}
+		}
+	}
+	for i, err := range runBatch(ctx, singles, func(i int) error {
+		return sc.register(ctx, registrations[i])
+	}) {
+		errs[i] = err
+	}
+	return batchResult(errs)
+}
+
+// 批量注销服务实例，各实例并发注销，部分失败时返回*BatchError
+func (sc *NamingClient) BatchDeregisterInstance(param vo.BatchDeregisterInstanceParam) (bool, error) {
+	return sc.BatchDeregisterInstanceWithContext(context.Background(), param)
+}
+
+func (sc *NamingClient) BatchDeregisterInstanceWithContext(ctx context.Context, param vo.BatchDeregisterInstanceParam) (bool, error) {
+	return batchResult(runBatch(ctx, batchIndexes(len(param.Instances)), func(i int) error {
+		_, err := sc.DeregisterInstanceWithContext(ctx, param.Instances[i])
+		return err
+	}))
+}
+
+// 获取服务列表
+func (sc *NamingClient) GetService(param vo.GetServiceParam) (model.Service, error) {
+	return sc.GetServiceWithContext(context.Background(), param)
+}
+
+func (sc *NamingClient) GetServiceWithContext(ctx context.Context, param vo.GetServiceParam) (model.Service, error) {
+	if err := validator.New("GetService").ServiceName("serviceName", param.ServiceName).GroupName("groupName", param.GroupName).Err(); err != nil {
+		return model.Service{}, err
+	}
+	if param.GroupName == "" {
+		param.GroupName = constant.DEFAULT_GROUP
+	}
+	return sc.hostReactor.GetServiceInfo(ctx, utils.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
+}
+
+// 创建服务
+func (sc *NamingClient) CreateService(param vo.CreateServiceParam) (bool, error) {
+	return sc.CreateServiceWithContext(context.Background(), param)
+}
+
+func (sc *NamingClient) CreateServiceWithContext(ctx context.Context, param vo.CreateServiceParam) (bool, error) {
+	if sc.hostReactor.cacheOnly {
+		return false, ErrCacheOnlyMode
+	}
+	if err := validator.New("CreateService").ServiceName("serviceName", param.ServiceName).GroupName("groupName", param.GroupName).Err(); err != nil {
+		return false, err
+	}
+	if param.GroupName == "" {
+		param.GroupName = constant.DEFAULT_GROUP
+	}
+	_, err := sc.serviceProxy.CreateService(ctx, param.ServiceName, param.GroupName, param.ProtectThreshold, param.Metadata, param.Selector)
 	if err != nil {
 		return false, err
 	}
-	if instance.Ephemeral {
-		sc.beatReactor.AddBeatInfo(utils.GetGroupName(param.ServiceName, param.GroupName), beatInfo)
+	return true, nil
+}
+
+// 修改服务的保护阈值、元数据和实例选择器
+func (sc *NamingClient) UpdateService(param vo.UpdateServiceParam) (bool, error) {
+	return sc.UpdateServiceWithContext(context.Background(), param)
+}
+
+func (sc *NamingClient) UpdateServiceWithContext(ctx context.Context, param vo.UpdateServiceParam) (bool, error) {
+	if sc.hostReactor.cacheOnly {
+		return false, ErrCacheOnlyMode
+	}
+	if err := validator.New("UpdateService").ServiceName("serviceName", param.ServiceName).GroupName("groupName", param.GroupName).Err(); err != nil {
+		return false, err
+	}
+	if param.GroupName == "" {
+		param.GroupName = constant.DEFAULT_GROUP
+	}
+	_, err := sc.serviceProxy.UpdateService(ctx, param.ServiceName, param.GroupName, param.ProtectThreshold, param.Metadata, param.Selector)
+	if err != nil {
+		return false, err
 	}
 	return true, nil
+}
 
+// 删除服务
+func (sc *NamingClient) DeleteService(param vo.DeleteServiceParam) (bool, error) {
+	return sc.DeleteServiceWithContext(context.Background(), param)
 }
 
-// 注销服务实例
-func (sc *NamingClient) DeregisterInstance(param vo.DeregisterInstanceParam) (bool, error) {
+func (sc *NamingClient) DeleteServiceWithContext(ctx context.Context, param vo.DeleteServiceParam) (bool, error) {
+	if sc.hostReactor.cacheOnly {
+		return false, ErrCacheOnlyMode
+	}
+	if err := validator.New("DeleteService").ServiceName("serviceName", param.ServiceName).GroupName("groupName", param.GroupName).Err(); err != nil {
+		return false, err
+	}
 	if param.GroupName == "" {
 		param.GroupName = constant.DEFAULT_GROUP
 	}
-	_, err := sc.serviceProxy.DeregisterInstance(utils.GetGroupName(param.ServiceName, param.GroupName), param.Ip, param.Port, param.Cluster, param.Ephemeral)
+	_, err := sc.serviceProxy.DeleteService(ctx, param.ServiceName, param.GroupName)
 	if err != nil {
 		return false, err
 	}
-	sc.beatReactor.RemoveBeatInfo(utils.GetGroupName(param.ServiceName, param.GroupName), param.Ip, param.Port)
 	return true, nil
 }
 
-// 获取服务列表
-func (sc *NamingClient) GetService(param vo.GetServiceParam) (model.Service, error) {
+// 从服务端获取服务的保护阈值、元数据、实例选择器和集群信息，不经过本地缓存
+func (sc *NamingClient) GetServiceDetail(param vo.GetServiceDetailParam) (model.ServiceMeta, error) {
+	return sc.GetServiceDetailWithContext(context.Background(), param)
+}
+
+func (sc *NamingClient) GetServiceDetailWithContext(ctx context.Context, param vo.GetServiceDetailParam) (model.ServiceMeta, error) {
+	if sc.hostReactor.cacheOnly {
+		return model.ServiceMeta{}, ErrCacheOnlyMode
+	}
+	if err := validator.New("GetServiceDetail").ServiceName("serviceName", param.ServiceName).GroupName("groupName", param.GroupName).Err(); err != nil {
+		return model.ServiceMeta{}, err
+	}
 	if param.GroupName == "" {
 		param.GroupName = constant.DEFAULT_GROUP
 	}
-	service := sc.hostReactor.GetServiceInfo(utils.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
-	return service, nil
+	service, err := sc.serviceProxy.GetServiceDetail(ctx, param.ServiceName, param.GroupName)
+	if err != nil {
+		return model.ServiceMeta{}, err
+	}
+	return *service, nil
 }
 
-func (sc *NamingClient) GetAllServicesInfo(param vo.GetAllServiceInfoParam) ([]model.Service, error) {
+// 分页获取服务名列表
+func (sc *NamingClient) GetAllServicesInfo(param vo.GetAllServiceInfoParam) (model.ServiceList, error) {
+	return sc.GetAllServicesInfoWithContext(context.Background(), param)
+}
+
+func (sc *NamingClient) GetAllServicesInfoWithContext(ctx context.Context, param vo.GetAllServiceInfoParam) (model.ServiceList, error) {
+	if sc.hostReactor.cacheOnly {
+		return model.ServiceList{}, ErrCacheOnlyMode
+	}
 	if param.GroupName == "" {
 		param.GroupName = constant.DEFAULT_GROUP
 	}
-	if param.NameSpace == "" {
-		param.NameSpace = constant.DEFAULT_NAMESPACE_ID
+	pageNo, pageSize := pageOf(param.PageNo, param.PageSize)
+	serviceList, err := sc.serviceProxy.GetServiceList(ctx, param.NameSpace, pageNo, pageSize, param.GroupName, nil)
+	if err != nil {
+		return model.ServiceList{}, err
 	}
-	service := sc.hostReactor.GetAllServiceInfo(param.NameSpace, param.GroupName, strings.Join(param.Clusters, ","))
-	return service, nil
+	return *serviceList, nil
+}
+
+// 按通配符和标签表达式查找服务，Pattern不为空时拉取分组下的全部服务后在本地过滤和分页
+func (sc *NamingClient) SearchService(param vo.SearchServiceParam) (model.ServiceList, error) {
+	return sc.SearchServiceWithContext(context.Background(), param)
+}
+
+func (sc *NamingClient) SearchServiceWithContext(ctx context.Context, param vo.SearchServiceParam) (model.ServiceList, error) {
+	if sc.hostReactor.cacheOnly {
+		return model.ServiceList{}, ErrCacheOnlyMode
+	}
+	if param.GroupName == "" {
+		param.GroupName = constant.DEFAULT_GROUP
+	}
+	if param.Pattern != "" {
+		if _, err := path.Match(param.Pattern, ""); err != nil {
+			return model.ServiceList{}, errors.New("[client.SearchService] invalid pattern:" + param.Pattern)
+		}
+	}
+	pageNo, pageSize := pageOf(param.PageNo, param.PageSize)
+	if param.Pattern == "" {
+		serviceList, err := sc.serviceProxy.GetServiceList(ctx, param.NameSpace, pageNo, pageSize, param.GroupName, param.Selector)
+		if err != nil {
+			return model.ServiceList{}, err
+		}
+		return *serviceList, nil
+	}
+	matched, err := sc.searchServiceNames(ctx, param.NameSpace, param.GroupName, param.Pattern, param.Selector)
+	if err != nil {
+		return model.ServiceList{}, err
+	}
+	result := model.ServiceList{Count: int64(len(matched)), Doms: []string{}}
+	start := (pageNo - 1) * pageSize
+	if start < len(matched) {
+		end := start + pageSize
+		if end > len(matched) {
+			end = len(matched)
+		}
+		result.Doms = matched[start:end]
+	}
+	return result, nil
+}
+
+// 拉取分组下的全部服务名，pattern不为空时只返回匹配的服务
+func (sc *NamingClient) searchServiceNames(ctx context.Context, namespace, groupName, pattern string, selector *model.ExpressionSelector) ([]string, error) {
+	var matched []string
+	for page := 1; ; page++ {
+		serviceList, err := sc.serviceProxy.GetServiceList(ctx, namespace, page, Default_Search_Page_Size, groupName, selector)
+		if err != nil {
+			return nil, err
+		}
+		for _, dom := range serviceList.Doms {
+			if ok, _ := path.Match(pattern, dom); pattern == "" || ok {
+				matched = append(matched, dom)
+			}
+		}
+		if len(serviceList.Doms) < Default_Search_Page_Size || int64(page*Default_Search_Page_Size) >= serviceList.Count {
+			return matched, nil
+		}
+	}
+}
+
+func pageOf(pageNo, pageSize uint32) (int, int) {
+	if pageNo == 0 {
+		pageNo = 1
+	}
+	if pageSize == 0 {
+		pageSize = Default_Page_Size
+	}
+	return int(pageNo), int(pageSize)
 }
 
 func (sc *NamingClient) SelectAllInstances(param vo.SelectAllInstancesParam) ([]model.Instance, error) {
+	return sc.SelectAllInstancesWithContext(context.Background(), param)
+}
+
+func (sc *NamingClient) SelectAllInstancesWithContext(ctx context.Context, param vo.SelectAllInstancesParam) ([]model.Instance, error) {
+	if err := validator.New("SelectAllInstances").ServiceName("serviceName", param.ServiceName).GroupName("groupName", param.GroupName).Err(); err != nil {
+		return []model.Instance{}, err
+	}
 	if param.GroupName == "" {
 		param.GroupName = constant.DEFAULT_GROUP
 	}
-	service := sc.hostReactor.GetServiceInfo(utils.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
+	service, err := sc.hostReactor.GetServiceInfo(ctx, utils.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
+	if err != nil {
+		return []model.Instance{}, err
+	}
+	if service, err = filterService(service, param.Selector); err != nil {
+		return []model.Instance{}, err
+	}
 	if service.Hosts == nil || len(service.Hosts) == 0 {
 		return []model.Instance{}, errors.New("instance list is empty!")
 	}
@@ -137,10 +765,23 @@ func (sc *NamingClient) SelectAllInstances(param vo.SelectAllInstancesParam) ([]
 }
 
 func (sc *NamingClient) SelectInstances(param vo.SelectInstancesParam) ([]model.Instance, error) {
+	return sc.SelectInstancesWithContext(context.Background(), param)
+}
+
+func (sc *NamingClient) SelectInstancesWithContext(ctx context.Context, param vo.SelectInstancesParam) ([]model.Instance, error) {
+	if err := validator.New("SelectInstances").ServiceName("serviceName", param.ServiceName).GroupName("groupName", param.GroupName).Err(); err != nil {
+		return []model.Instance{}, err
+	}
 	if param.GroupName == "" {
 		param.GroupName = constant.DEFAULT_GROUP
 	}
-	service := sc.hostReactor.GetServiceInfo(utils.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
+	service, err := sc.hostReactor.GetServiceInfo(ctx, utils.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
+	if err != nil {
+		return []model.Instance{}, err
+	}
+	if service, err = filterService(service, param.Selector); err != nil {
+		return []model.Instance{}, err
+	}
 	return sc.selectInstances(service, param.HealthyOnly)
 }
 
@@ -149,9 +790,10 @@ func (sc *NamingClient) selectInstances(service model.Service, healthy bool) ([]
 		return []model.Instance{}, errors.New("instance list is empty!")
 	}
 	hosts := service.Hosts
+	protected := healthy && sc.reachProtectThreshold(service)
 	var result []model.Instance
 	for _, host := range hosts {
-		if host.Healthy == healthy && host.Enable && host.Weight > 0 {
+		if (host.Healthy == healthy || protected) && host.Enable && host.Weight > 0 {
 			result = append(result, host)
 		}
 	}
@@ -159,70 +801,158 @@ func (sc *NamingClient) selectInstances(service model.Service, healthy bool) ([]
 }
 
 func (sc *NamingClient) SelectOneHealthyInstance(param vo.SelectOneHealthInstanceParam) (*model.Instance, error) {
+	return sc.SelectOneHealthyInstanceWithContext(context.Background(), param)
+}
+
+func (sc *NamingClient) SelectOneHealthyInstanceWithContext(ctx context.Context, param vo.SelectOneHealthInstanceParam) (*model.Instance, error) {
+	if err := validator.New("SelectOneHealthyInstance").ServiceName("serviceName", param.ServiceName).GroupName("groupName", param.GroupName).Err(); err != nil {
+		return nil, err
+	}
 	if param.GroupName == "" {
 		param.GroupName = constant.DEFAULT_GROUP
 	}
-	service := sc.hostReactor.GetServiceInfo(utils.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
+	service, err := sc.hostReactor.GetServiceInfo(ctx, utils.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
+	if err != nil {
+		return nil, err
+	}
+	if service, err = filterService(service, param.Selector); err != nil {
+		return nil, err
+	}
+	balancer := param.LoadBalancer
+	if balancer == nil {
+		balancer = sc.loadBalancer
+	}
+	if balancer != nil {
+		return sc.selectOneHealthyInstanceWithBalancer(service, sc.wrapBalancer(balancer))
+	}
 	return sc.selectOneHealthyInstances(service)
 }
 
-func (sc *NamingClient) selectOneHealthyInstances(service model.Service) (*model.Instance, error) {
+func (sc *NamingClient) selectOneHealthyInstanceWithBalancer(service model.Service, balancer load_balancer.LoadBalancer) (*model.Instance, error) {
+	result, err := sc.healthyCandidates(service)
+	if err != nil {
+		return nil, err
+	}
+	instance := balancer.Select(result)
+	return &instance, nil
+}
+
+// 参与选择的实例：健康、启用且权重大于0，达到保护阈值时不健康的实例也参与选择
+func (sc *NamingClient) healthyCandidates(service model.Service) ([]model.Instance, error) {
 	if service.Hosts == nil || len(service.Hosts) == 0 {
 		return nil, errors.New("instance list is empty!")
 	}
-	hosts := service.Hosts
+	protected := sc.reachProtectThreshold(service)
 	var result []model.Instance
-	mw := 0
-	for _, host := range hosts {
-		if host.Healthy && host.Enable && host.Weight > 0 {
-			cw := int(math.Ceil(host.Weight))
-			if cw > mw {
-				mw = cw
-			}
+	for _, host := range service.Hosts {
+		if (host.Healthy || protected) && host.Enable && host.Weight > 0 {
 			result = append(result, host)
 		}
 	}
 	if len(result) == 0 {
 		return nil, errors.New("healthy instance list is empty!")
 	}
+	return result, nil
+}
 
-	randomInstances := random(result, mw)
+// 健康实例占比不高于保护阈值时返回true，此时与服务端的保护语义一致，不健康的实例也参与选择，
+// 避免少量健康实例承接全部流量后相继被压垮
+func (sc *NamingClient) reachProtectThreshold(service model.Service) bool {
+	threshold := service.ProtectThreshold
+	if threshold <= 0 {
+		threshold = sc.protectThreshold
+	}
+	if threshold <= 0 || len(service.Hosts) == 0 {
+		return false
+	}
+	healthy := 0
+	for _, host := range service.Hosts {
+		if host.Healthy {
+			healthy++
+		}
+	}
+	return float64(healthy)/float64(len(service.Hosts)) <= threshold
+}
+
+// 默认每个服务使用独立的平滑加权轮询
+func (sc *NamingClient) selectOneHealthyInstances(service model.Service) (*model.Instance, error) {
+	if service.Hosts == nil || len(service.Hosts) == 0 {
+		return nil, errors.New("instance list is empty!")
+	}
 	key := utils.GetServiceCacheKey(service.Name, service.Clusters)
-	i, indexOk := sc.indexMap.Get(key)
-	var index int
+	balancer, ok := sc.balancerMap.Get(key)
+	if !ok {
+		sc.balancerMap.SetIfAbsent(key, load_balancer.NewSmoothWeightedRoundRobinBalancer())
+		balancer, _ = sc.balancerMap.Get(key)
+	}
+	return sc.selectOneHealthyInstanceWithBalancer(service, sc.wrapBalancer(balancer.(load_balancer.LoadBalancer)))
+}
 
-	if !indexOk {
-		index = rand.Intn(len(randomInstances))
-	} else {
-		index = i.(int)
-		index += 1
-		if index >= len(randomInstances) {
-			index = index % len(randomInstances)
-		}
+// 按hashKey在健康实例组成的一致性哈希环上选择实例，相同的hashKey总是落到同一个实例上，用于有状态服务的粘性路由
+// 哈希环按服务缓存，参与选择的实例变化时重新构建，param.LoadBalancer不生效
+func (sc *NamingClient) SelectInstanceByHash(param vo.SelectOneHealthInstanceParam, hashKey string) (*model.Instance, error) {
+	return sc.SelectInstanceByHashWithContext(context.Background(), param, hashKey)
+}
+
+func (sc *NamingClient) SelectInstanceByHashWithContext(ctx context.Context, param vo.SelectOneHealthInstanceParam, hashKey string) (*model.Instance, error) {
+	if err := validator.New("SelectInstanceByHash").ServiceName("serviceName", param.ServiceName).GroupName("groupName", param.GroupName).Err(); err != nil {
+		return nil, err
 	}
+	if param.GroupName == "" {
+		param.GroupName = constant.DEFAULT_GROUP
+	}
+	service, err := sc.hostReactor.GetServiceInfo(ctx, utils.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
+	if err != nil {
+		return nil, err
+	}
+	if service, err = filterService(service, param.Selector); err != nil {
+		return nil, err
+	}
+	candidates, err := sc.healthyCandidates(service)
+	if err != nil {
+		return nil, err
+	}
+	key := utils.GetServiceCacheKey(service.Name, service.Clusters) + "#" + param.Selector
+	instance, _ := sc.hashRing(key, candidates).Get(hashKey)
+	return &instance, nil
+}
 
-	sc.indexMap.Set(key, index)
-	return &randomInstances[index], nil
+type hashRingEntry struct {
+	checksum uint64
+	ring     *load_balancer.HashRing
 }
 
-func random(instances []model.Instance, mw int) []model.Instance {
-	if len(instances) <= 1 || mw <= 1 {
-		return instances
+// 参与选择的实例的校验和与构建时不同时重新构建哈希环
+func (sc *NamingClient) hashRing(key string, candidates []model.Instance) *load_balancer.HashRing {
+	checksum := hostsChecksum(candidates)
+	if entry, ok := sc.hashRingMap.Get(key); ok && entry.(*hashRingEntry).checksum == checksum {
+		return entry.(*hashRingEntry).ring
 	}
-	//实例交叉插入列表，避免列表中是连续的实例
-	var result = make([]model.Instance, 0)
-	for i := 1; i <= mw; i++ {
-		for _, host := range instances {
-			if int(math.Ceil(host.Weight)) >= i {
-				result = append(result, host)
-			}
-		}
+	ring := load_balancer.NewHashRing(candidates, sc.virtualNodes)
+	sc.hashRingMap.Set(key, &hashRingEntry{checksum: checksum, ring: ring})
+	return ring
+}
+
+// ClientConfig.WarmUpMs大于0时对新注册的实例预热，设置了ClientConfig.Locality时同可用区优先
+func (sc *NamingClient) wrapBalancer(balancer load_balancer.LoadBalancer) load_balancer.LoadBalancer {
+	if sc.warmUp > 0 {
+		balancer = load_balancer.NewWarmUpBalancer(balancer, sc.warmUp, "")
+	}
+	if sc.locality != nil {
+		balancer = load_balancer.NewZoneAwareBalancer(balancer, *sc.locality)
 	}
-	return result
+	return balancer
 }
 
 // 服务监听
 func (sc *NamingClient) Subscribe(param *vo.SubscribeParam) error {
+	return sc.SubscribeWithContext(context.Background(), param)
+}
+
+func (sc *NamingClient) SubscribeWithContext(ctx context.Context, param *vo.SubscribeParam) error {
+	if err := validator.New("Subscribe").ServiceName("serviceName", param.ServiceName).GroupName("groupName", param.GroupName).Err(); err != nil {
+		return err
+	}
 	if param.GroupName == "" {
 		param.GroupName = constant.DEFAULT_GROUP
 	}
@@ -231,17 +961,187 @@ func (sc *NamingClient) Subscribe(param *vo.SubscribeParam) error {
 		GroupName:   param.GroupName,
 		Clusters:    param.Clusters,
 	}
+	if param.Selector != "" {
+		selector, err := parseSelector(param.Selector)
+		if err != nil {
+			return err
+		}
+		// 直接替换param中的回调，Unsubscribe按同一个param取消时仍能匹配
+		if callback := param.SubscribeCallback; callback != nil {
+			param.SubscribeCallback = func(services []model.SubscribeService, err error) {
+				callback(selector.filterSubscribeServices(services), err)
+			}
+		}
+		if callback := param.ChangeCallback; callback != nil {
+			param.ChangeCallback = func(event model.InstanceChangeEvent) {
+				if event, ok := selector.filterChangeEvent(event); ok {
+					callback(event)
+				}
+			}
+		}
+	}
 
-	sc.subCallback.AddCallbackFuncs(utils.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","), &param.SubscribeCallback)
-	_, err := sc.GetService(serviceParam)
+	if param.SubscribeCallback != nil {
+		sc.subCallback.AddCallbackFuncs(utils.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","), &param.SubscribeCallback)
+	}
+	if param.ChangeCallback != nil {
+		sc.subCallback.AddChangeFuncs(utils.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","), &param.ChangeCallback)
+	}
+	key := utils.GetServiceCacheKey(utils.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
+	sc.hostReactor.markSubscribed(key)
+	sc.redoService.CacheSubscription(utils.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
+	if param.SubscribeCallback != nil || param.ChangeCallback != nil {
+		sc.releaseRestoredSubscription(key)
+		sc.saveSubscriptions()
+	}
+	_, err := sc.GetServiceWithContext(ctx, serviceParam)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-//取消服务监听
+// 返回内存中缓存的所有服务
+func (sc *NamingClient) GetCachedServices() []model.Service {
+	return sc.hostReactor.GetCachedServices()
+}
+
+// 返回内存中缓存的服务，key为服务名与集群组成的缓存key，可用于健康检查接口和调试
+func (sc *NamingClient) GetCachedServiceInfoMapSnapshot() map[string]model.Service {
+	return sc.hostReactor.GetServiceInfoMapSnapshot()
+}
+
+// 返回每个缓存的服务最后一次更新的时间，单位毫秒，key与GetCachedServiceInfoMapSnapshot一致
+func (sc *NamingClient) GetUpdateTimeMap() map[string]uint64 {
+	return sc.hostReactor.GetUpdateTimeMap()
+}
+
+// 从内存中移除服务缓存并取消该服务的订阅，服务不在缓存中时返回false
+func (sc *NamingClient) PurgeServiceCache(param vo.PurgeServiceCacheParam) bool {
+	if param.GroupName == "" {
+		param.GroupName = constant.DEFAULT_GROUP
+	}
+	purged := sc.hostReactor.PurgeService(utils.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
+	sc.saveSubscriptions()
+	return purged
+}
+
+// 直接写入服务缓存并通知订阅者，开启CacheOnly时可作为不依赖服务端的内嵌实现用于单元测试和离线场景
+// 未开启CacheOnly时写入的数据会在下次从服务端刷新时被覆盖
+func (sc *NamingClient) UpdateServiceCache(param vo.UpdateServiceCacheParam) error {
+	if param.ServiceName == "" {
+		return errors.New("[client.UpdateServiceCache] serviceName can not be empty")
+	}
+	if param.GroupName == "" {
+		param.GroupName = constant.DEFAULT_GROUP
+	}
+	serviceName := utils.GetGroupName(param.ServiceName, param.GroupName)
+	hosts := make([]model.Instance, len(param.Hosts))
+	for i, host := range param.Hosts {
+		if host.ServiceName == "" {
+			host.ServiceName = serviceName
+		}
+		hosts[i] = host
+	}
+	sc.hostReactor.PutService(model.Service{
+		Name:     serviceName,
+		Clusters: strings.Join(param.Clusters, ","),
+		Hosts:    hosts,
+		Metadata: param.Metadata,
+	})
+	return nil
+}
+
+// 取消服务监听
 func (sc *NamingClient) Unsubscribe(param *vo.SubscribeParam) error {
 	sc.subCallback.RemoveCallbackFuncs(utils.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","), &param.SubscribeCallback)
+	sc.subCallback.RemoveChangeFuncs(utils.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","), &param.ChangeCallback)
+	sc.hostReactor.markUnsubscribed(utils.GetServiceCacheKey(utils.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ",")))
+	sc.redoService.RemoveSubscription(utils.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
+	sc.saveSubscriptions()
 	return nil
 }
+
+// 返回所有有订阅回调的服务
+func (sc *NamingClient) GetSubscribedServices() []model.SubscribedService {
+	return sc.hostReactor.GetSubscribedServices()
+}
+
+// 订阅生命周期事件，types为空时订阅所有类型，返回的id用于取消订阅
+// 多命名空间客户端共享同一事件总线，可通过事件中的Namespace区分
+func (sc *NamingClient) SubscribeEvent(listener event.Listener, types ...event.EventType) int64 {
+	return sc.serviceProxy.nacosServer.Events().Subscribe(listener, types...)
+}
+
+func (sc *NamingClient) UnsubscribeEvent(id int64) {
+	sc.serviceProxy.nacosServer.Events().Unsubscribe(id)
+}
+
+// 运行时更新客户端配置，已有的订阅和监听不受影响
+// 生效的有TimeoutMs、用户名密码、签名凭证、RetryPolicy、CircuitBreaker和RateLimit，其余字段需重新创建客户端
+func (sc *NamingClient) UpdateClientConfig(opts ...constant.ClientOption) error {
+	clientConfig, err := sc.GetClientConfig()
+	if err != nil {
+		return err
+	}
+	for _, opt := range opts {
+		opt(&clientConfig)
+	}
+	if err = sc.SetClientConfig(clientConfig); err != nil {
+		return err
+	}
+	clientConfig, _ = sc.GetClientConfig()
+	sc.serviceProxy.nacosServer.UpdateClientConfig(clientConfig)
+	return nil
+}
+
+// 运行时替换服务端列表，已有的订阅和监听不受影响
+func (sc *NamingClient) UpdateServerConfig(serverConfigs []constant.ServerConfig) error {
+	if len(serverConfigs) == 0 {
+		return errors.New("[client.UpdateServerConfig] server configs can not be empty")
+	}
+	if err := sc.SetServerConfig(serverConfigs); err != nil {
+		return err
+	}
+	return sc.serviceProxy.nacosServer.UpdateServerConfig(serverConfigs)
+}
+
+// 注销通过该客户端注册的所有临时实例并停止其心跳，用于在自己的退出流程中摘除流量，返回最后一个注销失败的错误
+func (sc *NamingClient) DeregisterAllInstances() error {
+	return sc.DeregisterAllInstancesWithContext(context.Background())
+}
+
+func (sc *NamingClient) DeregisterAllInstancesWithContext(ctx context.Context) error {
+	if sc.hostReactor.cacheOnly {
+		return ErrCacheOnlyMode
+	}
+	var err error
+	for _, beatInfo := range sc.beatReactor.BeatInfos() {
+		_, e := sc.serviceProxy.DeregisterInstance(ctx, beatInfo.ServiceName, beatInfo.Ip, beatInfo.Port, beatInfo.Cluster, true)
+		if e != nil {
+			logger.Errorf("deregister instance %s@%s:%d failed,err:%s", beatInfo.ServiceName, beatInfo.Ip, beatInfo.Port, e.Error())
+			err = e
+			continue
+		}
+		sc.beatReactor.RemoveBeatInfo(beatInfo.ServiceName, beatInfo.Ip, beatInfo.Port)
+		sc.redoService.RemoveInstance(beatInfo.ServiceName, beatInfo.Ip, beatInfo.Port)
+	}
+	return err
+}
+
+// 关闭客户端，停止心跳和服务刷新，并将服务缓存写入磁盘
+func (sc *NamingClient) Close() error {
+	var err error
+	if sc.removeShutdownHook != nil {
+		sc.removeShutdownHook()
+	}
+	if sc.deregisterOnClose && !sc.hostReactor.cacheOnly {
+		err = sc.DeregisterAllInstances()
+	}
+	sc.redoService.Stop()
+	sc.beatReactor.Stop()
+	sc.hostReactor.Stop()
+	sc.subCallback.stop()
+	sc.serviceProxy.nacosServer.Stop()
+	return err
+}
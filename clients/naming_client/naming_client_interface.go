@@ -1,8 +1,12 @@
 package naming_client
 
 import (
+	"context"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/event"
 	"github.com/nacos-group/nacos-sdk-go/model"
 	"github.com/nacos-group/nacos-sdk-go/vo"
+	"time"
 )
 
 /**
@@ -21,6 +25,19 @@ type INamingClient interface {
 	RegisterInstance(param vo.RegisterInstanceParam) (bool, error)
 	// 注销服务实例
 	DeregisterInstance(param vo.DeregisterInstanceParam) (bool, error)
+	// 修改服务实例的权重、元数据等信息
+	UpdateInstance(param vo.UpdateInstanceParam) (bool, error)
+	// 在duration内逐步将实例权重降为0后将实例设为不可用
+	DrainInstance(param vo.DrainInstanceParam, duration time.Duration) (bool, error)
+	// 暂停和恢复通过该客户端注册的临时实例的心跳，暂停后服务端在心跳超时后将实例标记为不健康，用于维护期间摘除流量
+	PauseHeartbeat(param vo.HeartbeatParam) (bool, error)
+	ResumeHeartbeat(param vo.HeartbeatParam) (bool, error)
+	// 批量注册服务实例，部分失败时返回*BatchError
+	BatchRegisterInstance(param vo.BatchRegisterInstanceParam) (bool, error)
+	// 批量注销服务实例，部分失败时返回*BatchError
+	BatchDeregisterInstance(param vo.BatchDeregisterInstanceParam) (bool, error)
+	// 注销通过该客户端注册的所有临时实例并停止心跳，用于进程退出前摘除流量
+	DeregisterAllInstances() error
 	// 获取服务信息
 	GetService(param vo.GetServiceParam) (model.Service, error)
 	//获取所有的实例列表
@@ -29,11 +46,77 @@ type INamingClient interface {
 	SelectInstances(param vo.SelectInstancesParam) ([]model.Instance, error)
 	//获取一个健康的实例
 	SelectOneHealthyInstance(param vo.SelectOneHealthInstanceParam) (*model.Instance, error)
+	// 按hashKey在一致性哈希环上选择一个健康的实例，相同的hashKey总是落到同一个实例上
+	SelectInstanceByHash(param vo.SelectOneHealthInstanceParam, hashKey string) (*model.Instance, error)
 	// 服务监听
 	Subscribe(param *vo.SubscribeParam) error
 	//取消监听
 	Unsubscribe(param *vo.SubscribeParam) error
+	// 返回所有有订阅回调的服务
+	GetSubscribedServices() []model.SubscribedService
 
-	//获取全部服务信息
-	GetAllServicesInfo(param vo.GetAllServiceInfoParam) ([]model.Service, error)
+	// 创建服务
+	CreateService(param vo.CreateServiceParam) (bool, error)
+	// 修改服务的保护阈值、元数据和实例选择器
+	UpdateService(param vo.UpdateServiceParam) (bool, error)
+	// 删除服务，服务下仍有实例时删除失败
+	DeleteService(param vo.DeleteServiceParam) (bool, error)
+	// 从服务端获取服务的保护阈值、元数据、实例选择器和集群信息
+	GetServiceDetail(param vo.GetServiceDetailParam) (model.ServiceMeta, error)
+
+	// 分页获取服务名列表
+	GetAllServicesInfo(param vo.GetAllServiceInfoParam) (model.ServiceList, error)
+	// 按服务名通配符和标签表达式分页查找服务
+	SearchService(param vo.SearchServiceParam) (model.ServiceList, error)
+	// 定时拉取分组下的服务名列表，服务新增或删除时回调
+	WatchAllServices(param vo.WatchServicesParam) error
+
+	// 返回内存中缓存的所有服务
+	GetCachedServices() []model.Service
+	// 返回内存中缓存的服务，key为服务名与集群组成的缓存key
+	GetCachedServiceInfoMapSnapshot() map[string]model.Service
+	// 返回每个缓存的服务最后一次更新的时间，单位毫秒
+	GetUpdateTimeMap() map[string]uint64
+	// 从内存中移除服务缓存并取消该服务的订阅
+	PurgeServiceCache(param vo.PurgeServiceCacheParam) bool
+	// 直接写入服务缓存并通知订阅者，配合CacheOnly用于单元测试和离线场景
+	UpdateServiceCache(param vo.UpdateServiceCacheParam) error
+
+	// 订阅服务端切换、服务端不健康、收到推送、加载磁盘缓存、心跳失败、重新注册等生命周期事件
+	// types为空时订阅所有类型，返回的id用于取消订阅
+	SubscribeEvent(listener event.Listener, types ...event.EventType) int64
+	UnsubscribeEvent(id int64)
+
+	// 以下方法与上面的同名方法一致，可通过ctx取消请求或设置超时
+	RegisterInstanceWithContext(ctx context.Context, param vo.RegisterInstanceParam) (bool, error)
+	DeregisterInstanceWithContext(ctx context.Context, param vo.DeregisterInstanceParam) (bool, error)
+	UpdateInstanceWithContext(ctx context.Context, param vo.UpdateInstanceParam) (bool, error)
+	DrainInstanceWithContext(ctx context.Context, param vo.DrainInstanceParam, duration time.Duration) (bool, error)
+	BatchRegisterInstanceWithContext(ctx context.Context, param vo.BatchRegisterInstanceParam) (bool, error)
+	BatchDeregisterInstanceWithContext(ctx context.Context, param vo.BatchDeregisterInstanceParam) (bool, error)
+	DeregisterAllInstancesWithContext(ctx context.Context) error
+	GetServiceWithContext(ctx context.Context, param vo.GetServiceParam) (model.Service, error)
+	SelectAllInstancesWithContext(ctx context.Context, param vo.SelectAllInstancesParam) ([]model.Instance, error)
+	SelectInstancesWithContext(ctx context.Context, param vo.SelectInstancesParam) ([]model.Instance, error)
+	SelectOneHealthyInstanceWithContext(ctx context.Context, param vo.SelectOneHealthInstanceParam) (*model.Instance, error)
+	SelectInstanceByHashWithContext(ctx context.Context, param vo.SelectOneHealthInstanceParam, hashKey string) (*model.Instance, error)
+	SubscribeWithContext(ctx context.Context, param *vo.SubscribeParam) error
+	CreateServiceWithContext(ctx context.Context, param vo.CreateServiceParam) (bool, error)
+	UpdateServiceWithContext(ctx context.Context, param vo.UpdateServiceParam) (bool, error)
+	DeleteServiceWithContext(ctx context.Context, param vo.DeleteServiceParam) (bool, error)
+	GetServiceDetailWithContext(ctx context.Context, param vo.GetServiceDetailParam) (model.ServiceMeta, error)
+	GetAllServicesInfoWithContext(ctx context.Context, param vo.GetAllServiceInfoParam) (model.ServiceList, error)
+	SearchServiceWithContext(ctx context.Context, param vo.SearchServiceParam) (model.ServiceList, error)
+	WatchAllServicesWithContext(ctx context.Context, param vo.WatchServicesParam) error
+
+	// 在后台注册和注销实例，完成后向返回的channel写入一个结果
+	RegisterInstanceAsync(ctx context.Context, param vo.RegisterInstanceParam) <-chan model.AsyncResult
+	DeregisterInstanceAsync(ctx context.Context, param vo.DeregisterInstanceParam) <-chan model.AsyncResult
+
+	// 运行时更新客户端配置和服务端列表，无需重新创建客户端
+	UpdateClientConfig(opts ...constant.ClientOption) error
+	UpdateServerConfig(serverConfigs []constant.ServerConfig) error
+
+	// 关闭客户端，见nacos_client.CloseableClient
+	Close() error
 }
@@ -1,18 +1,33 @@
 package naming_client
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/golang/mock/gomock"
 	"github.com/nacos-group/nacos-sdk-go/clients/nacos_client"
 	"github.com/nacos-group/nacos-sdk-go/common/constant"
 	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/common/local_ip"
+	"github.com/nacos-group/nacos-sdk-go/common/monitor"
+	"github.com/nacos-group/nacos-sdk-go/common/nacos_error"
+	"github.com/nacos-group/nacos-sdk-go/common/rate_limiter"
+	"github.com/nacos-group/nacos-sdk-go/common/retry"
+	"github.com/nacos-group/nacos-sdk-go/common/tracing"
+	"github.com/nacos-group/nacos-sdk-go/common/validator"
 	"github.com/nacos-group/nacos-sdk-go/mock"
 	"github.com/nacos-group/nacos-sdk-go/model"
 	"github.com/nacos-group/nacos-sdk-go/utils"
 	"github.com/nacos-group/nacos-sdk-go/vo"
 	"github.com/stretchr/testify/assert"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 var clientConfigTest = constant.ClientConfig{
@@ -43,7 +58,7 @@ func Test_RegisterServiceInstance_withoutGroupeName(t *testing.T) {
 	}()
 	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
 
-	mockIHttpAgent.EXPECT().Request(gomock.Eq("POST"),
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("POST"),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(uint64(20*1000)),
@@ -76,6 +91,194 @@ func Test_RegisterServiceInstance_withoutGroupeName(t *testing.T) {
 	assert.Equal(t, true, success)
 }
 
+func Test_RegisterServiceInstance_withAppIdentity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	checkIdentity := func(ctx context.Context, method string, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		assert.Equal(t, "order-service", header[constant.CLIENT_APP_NAME_HEADER][0])
+		assert.Equal(t, "env=prod,zone=a", header[constant.CLIENT_LABELS_HEADER][0])
+		assert.Equal(t, "order-service", params[constant.KEY_APP])
+		if method == http.MethodPut {
+			return http_agent.FakeHttpResponse(200, `{"clientBeatInterval":5000}`), nil
+		}
+		return http_agent.FakeHttpResponse(200, `ok`), nil
+	}
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(checkIdentity)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPut),
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(checkIdentity)
+
+	nc := nacos_client.NacosClient{}
+	nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	clientConfig := clientConfigTest
+	clientConfig.ListenInterval = 30 * 1000
+	clientConfig.AppName = "order-service"
+	clientConfig.Labels = map[string]string{"zone": "a", "env": "prod"}
+	nc.SetClientConfig(clientConfig)
+	nc.SetHttpAgent(mockIHttpAgent)
+	client, err := NewNamingClient(&nc)
+	assert.Nil(t, err)
+	defer client.Close()
+	success, err := client.RegisterInstance(vo.RegisterInstanceParam{ServiceName: "DEMO", Ip: "10.0.0.10", Port: 80, Ephemeral: true})
+	assert.Nil(t, err)
+	assert.True(t, success)
+}
+
+func TestNamingClient_DeregisterAllInstances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
+		Return(http_agent.FakeHttpResponse(200, `ok`), nil)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPut),
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		Return(http_agent.FakeHttpResponse(200, `{"clientBeatInterval":5000}`), nil)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodDelete),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
+		Return(http_agent.FakeHttpResponse(200, `ok`), nil)
+
+	nc := nacos_client.NacosClient{}
+	nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	clientConfig := clientConfigTest
+	clientConfig.ListenInterval = 30 * 1000
+	clientConfig.EnableAutoDeregister = true
+	nc.SetClientConfig(clientConfig)
+	nc.SetHttpAgent(mockIHttpAgent)
+	client, err := NewNamingClient(&nc)
+	assert.Nil(t, err)
+	defer client.Close()
+	assert.NotNil(t, client.removeShutdownHook)
+	for _, port := range []uint64{80, 81} {
+		_, err = client.RegisterInstance(vo.RegisterInstanceParam{ServiceName: "DEMO", Ip: "10.0.0.10", Port: port, Ephemeral: true})
+		assert.Nil(t, err)
+	}
+
+	assert.Nil(t, client.DeregisterAllInstances())
+	assert.Equal(t, 0, len(client.beatReactor.BeatInfos()))
+	assert.Equal(t, 0, len(client.redoService.Instances()))
+	assert.Nil(t, client.DeregisterAllInstances(), "instances should not be deregistered twice")
+}
+
+func TestNamingClient_PauseAndResumeHeartbeat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		Return(http_agent.FakeHttpResponse(200, `ok`), nil)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPut),
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		Return(http_agent.FakeHttpResponse(200, `{"clientBeatInterval":5000}`), nil)
+
+	nc := nacos_client.NacosClient{}
+	nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	clientConfig := clientConfigTest
+	clientConfig.ListenInterval = 30 * 1000
+	nc.SetClientConfig(clientConfig)
+	nc.SetHttpAgent(mockIHttpAgent)
+	client, err := NewNamingClient(&nc)
+	assert.Nil(t, err)
+	defer client.Close()
+	_, err = client.RegisterInstance(vo.RegisterInstanceParam{ServiceName: "DEMO", Ip: "10.0.0.10", Port: 80, Weight: 1,
+		Metadata: map[string]string{"version": "1.0.0"}, Ephemeral: true})
+	assert.Nil(t, err)
+
+	_, err = client.PauseHeartbeat(vo.HeartbeatParam{ServiceName: "DEMO", Ip: "10.0.0.10", Port: 81})
+	assert.NotNil(t, err)
+	success, err := client.PauseHeartbeat(vo.HeartbeatParam{ServiceName: "DEMO", Ip: "10.0.0.10", Port: 80})
+	assert.Nil(t, err)
+	assert.True(t, success)
+	beatInfo := client.beatReactor.BeatInfos()[0]
+	assert.True(t, beatInfo.Paused)
+	assert.Equal(t, "1.0.0", beatInfo.Metadata["version"])
+	assert.True(t, client.redoService.Instances()[0].Paused)
+	// 暂停期间重连不重新注册实例
+	client.redoService.Redo()
+
+	success, err = client.ResumeHeartbeat(vo.HeartbeatParam{ServiceName: "DEMO", Ip: "10.0.0.10", Port: 80})
+	assert.Nil(t, err)
+	assert.True(t, success)
+	assert.False(t, client.beatReactor.BeatInfos()[0].Paused)
+	assert.False(t, client.redoService.Instances()[0].Paused)
+}
+
+func Test_RegisterInstanceAsync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
+		DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			// 并发调用各自读取响应体，每次返回新的响应
+			return http_agent.FakeHttpResponse(200, `ok`), nil
+		})
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPut),
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			return http_agent.FakeHttpResponse(200, `{"clientBeatInterval":5000}`), nil
+		})
+
+	nc := nacos_client.NacosClient{}
+	nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	clientConfig := clientConfigTest
+	clientConfig.ListenInterval = 30 * 1000
+	nc.SetClientConfig(clientConfig)
+	nc.SetHttpAgent(mockIHttpAgent)
+	client, err := NewNamingClient(&nc)
+	assert.Nil(t, err)
+	defer client.Close()
+	first := client.RegisterInstanceAsync(context.Background(), vo.RegisterInstanceParam{ServiceName: "DEMO", Ip: "10.0.0.10", Port: 80})
+	second := client.RegisterInstanceAsync(context.Background(), vo.RegisterInstanceParam{ServiceName: "DEMO", Ip: "10.0.0.11", Port: 80})
+	for _, result := range []<-chan model.AsyncResult{first, second} {
+		r := <-result
+		assert.Nil(t, r.Err)
+		assert.True(t, r.Success)
+	}
+
+	r := <-client.RegisterInstanceAsync(context.Background(), vo.RegisterInstanceParam{Ip: "10.0.0.12", Port: 80})
+	assert.NotNil(t, r.Err)
+	assert.False(t, r.Success)
+}
+
+func Test_RegisterServiceInstance_DetectIp(t *testing.T) {
+	os.Setenv(local_ip.Env_Advertise_Ip, "10.0.0.20")
+	defer os.Unsetenv(local_ip.Env_Advertise_Ip)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			assert.Equal(t, "10.0.0.20", params["ip"])
+			return http_agent.FakeHttpResponse(200, `ok`), nil
+		})
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/list"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			assert.Equal(t, "10.0.0.20", params["clientIp"])
+			return http_agent.FakeHttpResponse(200, `{"name":"DEFAULT_GROUP@@DEMO","hosts":[]}`), nil
+		})
+
+	proxy, err := NewNamingProxy(clientConfigTest, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	assert.Nil(t, err)
+	client := NamingClient{serviceProxy: proxy, beatReactor: NewBeatReactor(proxy, 5000),
+		hostReactor: &HostReactor{serviceProxy: proxy}}
+	success, err := client.RegisterInstance(vo.RegisterInstanceParam{ServiceName: "DEMO", Port: 80})
+	assert.Nil(t, err)
+	assert.True(t, success)
+	_, err = proxy.QueryList(context.Background(), "DEFAULT_GROUP@@DEMO", "", 0, false)
+	assert.Nil(t, err)
+}
+
 func Test_RegisterServiceInstance_withGroupeName(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer func() {
@@ -83,7 +286,7 @@ func Test_RegisterServiceInstance_withGroupeName(t *testing.T) {
 	}()
 	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
 
-	mockIHttpAgent.EXPECT().Request(gomock.Eq("POST"),
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("POST"),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(uint64(20*1000)),
@@ -124,7 +327,7 @@ func Test_RegisterServiceInstance_withCluster(t *testing.T) {
 	}()
 	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
 
-	mockIHttpAgent.EXPECT().Request(gomock.Eq("POST"),
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("POST"),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(uint64(20*1000)),
@@ -166,7 +369,7 @@ func Test_RegisterServiceInstance_401(t *testing.T) {
 	}()
 	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
 
-	mockIHttpAgent.EXPECT().Request(gomock.Eq("POST"),
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("POST"),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(uint64(20*1000)),
@@ -200,6 +403,101 @@ func Test_RegisterServiceInstance_401(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestNamingClient_RegisterServiceNotRetryable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer func() {
+		ctrl.Finish()
+	}()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("POST"),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+		gomock.AssignableToTypeOf(http.Header{}),
+		gomock.Eq(uint64(20*1000)),
+		gomock.Any()).Times(1).
+		Return(http_agent.FakeHttpResponse(403, `forbidden`), nil)
+
+	clientConfig := clientConfigTest
+	clientConfig.RetryPolicy = &retry.RetryPolicy{
+		MaxAttempts:          3,
+		InitialDelay:         10 * time.Millisecond,
+		RetryableStatusCodes: []int{502, 503},
+	}
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	proxy, _ := NewNamingProxy(clientConfig, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	client := NamingClient{
+		serviceProxy: proxy,
+		hostReactor:  NewHostReactor(proxy, cacheDir, 20, true, NewSubscribeCallback(), false, 0, nil, 0, 0, false, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{}),
+		beatReactor:  NewBeatReactor(proxy, 5000),
+	}
+	result, err := client.RegisterInstance(vo.RegisterInstanceParam{
+		ServiceName: "DEMO",
+		Ip:          "10.0.0.10",
+		Port:        80,
+		GroupName:   "test_group",
+	})
+	assert.Equal(t, false, result)
+	assert.True(t, errors.Is(err, nacos_error.ErrForbidden))
+}
+
+func TestNamingClient_RegisterServiceRetryAllServers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer func() {
+		ctrl.Finish()
+	}()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+
+	var servers []constant.ServerConfig
+	for i := 1; i <= 5; i++ {
+		servers = append(servers, constant.ServerConfig{IpAddr: fmt.Sprintf("10.0.0.%d", i), Port: 8848, ContextPath: "/nacos"})
+	}
+	// 未配置重试策略时，服务端多于3个也要每个都尝试一次
+	requested := map[string]bool{}
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("POST"), gomock.Any(),
+		gomock.AssignableToTypeOf(http.Header{}), gomock.Any(), gomock.Any()).Times(len(servers)).
+		DoAndReturn(func(ctx context.Context, method string, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			requested[path] = true
+			return http_agent.FakeHttpResponse(503, `unavailable`), nil
+		})
+
+	proxy, _ := NewNamingProxy(clientConfigTest, servers, mockIHttpAgent)
+	_, err := proxy.RegisterInstance(context.Background(), utils.GetGroupName("DEMO", "test_group"), "test_group", model.Instance{Ip: "10.0.0.10", Port: 80, Weight: 1, Enable: true, Healthy: true, Ephemeral: true})
+	assert.NotNil(t, err)
+	assert.Equal(t, len(servers), len(requested))
+}
+
+func TestNamingClient_RegisterServiceTimeoutBudget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer func() {
+		ctrl.Finish()
+	}()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("POST"),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+		gomock.AssignableToTypeOf(http.Header{}),
+		gomock.Eq(uint64(500)),
+		gomock.Any()).Times(1).
+		Return(http_agent.FakeHttpResponse(503, `unavailable`), nil)
+
+	clientConfig := clientConfigTest
+	clientConfig.RetryPolicy = &retry.RetryPolicy{
+		MaxAttempts:          3,
+		InitialDelay:         200 * time.Millisecond,
+		RetryableStatusCodes: []int{503},
+	}
+	clientConfig.Timeout = &constant.TimeoutConfig{RequestTimeoutMs: 500, TotalTimeoutMs: 50, SlowThresholdMs: 10}
+	clientConfig.EnableMetrics = true
+	proxy, _ := NewNamingProxy(clientConfig, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	slowCount := monitor.SlowRequests.Count(constant.SERVICE_PATH)
+	start := time.Now()
+	_, err := proxy.RegisterInstance(context.Background(), utils.GetGroupName("DEMO", "test_group"), "test_group", model.Instance{Ip: "10.0.0.10", Port: 80, Weight: 1, Enable: true, Healthy: true, Ephemeral: true})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.True(t, time.Since(start) < 200*time.Millisecond, "retry delay should be cut by the total timeout")
+	assert.Equal(t, slowCount+1, monitor.SlowRequests.Count(constant.SERVICE_PATH))
+}
+
 func TestNamingProxy_DeristerService_WithoutGroupName(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer func() {
@@ -207,7 +505,7 @@ func TestNamingProxy_DeristerService_WithoutGroupName(t *testing.T) {
 	}()
 	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
 
-	mockIHttpAgent.EXPECT().Request(gomock.Eq("DELETE"),
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("DELETE"),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(uint64(20*1000)),
@@ -239,7 +537,7 @@ func TestNamingProxy_DeristerService_WithGroupName(t *testing.T) {
 	}()
 	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
 
-	mockIHttpAgent.EXPECT().Request(gomock.Eq("DELETE"),
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("DELETE"),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(uint64(20*1000)),
@@ -272,7 +570,7 @@ func TestNamingProxy_DeristerService_401(t *testing.T) {
 	}()
 	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
 
-	mockIHttpAgent.EXPECT().Request(gomock.Eq("DELETE"),
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("DELETE"),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(uint64(20*1000)),
@@ -371,7 +669,7 @@ func TestNamingProxy_GetService_WithoutGroupName(t *testing.T) {
 	}()
 	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
 
-	mockIHttpAgent.EXPECT().Request(gomock.Eq("GET"),
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("GET"),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/list"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(uint64(20*1000)),
@@ -399,7 +697,7 @@ func TestNamingClient_SelectAllInstancs(t *testing.T) {
 	}()
 	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
 
-	mockIHttpAgent.EXPECT().Request(gomock.Eq("GET"),
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("GET"),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/list"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(uint64(20*1000)),
@@ -635,6 +933,48 @@ func TestNamingClient_SelectInstances_Healthy(t *testing.T) {
 	assert.Equal(t, 2, len(instances))
 }
 
+func TestNamingClient_SelectInstances_ProtectThreshold(t *testing.T) {
+	services := model.Service{
+		Name:     "DEFAULT_GROUP@@DEMO",
+		Clusters: "a",
+		Hosts: []model.Instance{
+			{Ip: "10.10.10.10", Port: 80, Weight: 1, Enable: true, Healthy: true},
+			{Ip: "10.10.10.11", Port: 80, Weight: 1, Enable: true, Healthy: false},
+			{Ip: "10.10.10.12", Port: 80, Weight: 1, Enable: true, Healthy: false},
+			{Ip: "10.10.10.13", Port: 80, Weight: 1, Enable: false, Healthy: false},
+		},
+	}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	nc := nacos_client.NacosClient{}
+	nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	clientConfig := clientConfigTest
+	clientConfig.ListenInterval = 30 * 1000
+	clientConfig.ProtectThreshold = 0.3
+	nc.SetClientConfig(clientConfig)
+	nc.SetHttpAgent(mock.NewMockIHttpAgent(ctrl))
+	client, err := NewNamingClient(&nc)
+	assert.Nil(t, err)
+
+	// 健康占比0.25不高于阈值，不健康但可用的实例也被返回
+	instances, err := client.selectInstances(services, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(instances))
+	selected := map[string]bool{}
+	for i := 0; i < 6; i++ {
+		instance, err := client.selectOneHealthyInstances(services)
+		assert.Nil(t, err)
+		selected[instance.Ip] = true
+	}
+	assert.Equal(t, 3, len(selected))
+
+	// 服务自身的阈值优先
+	services.ProtectThreshold = 0.2
+	instances, err = client.selectInstances(services, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(instances))
+}
+
 func TestNamingClient_SelectInstances_Unhealthy(t *testing.T) {
 	services := model.Service(model.Service{
 		Name:            "DEFAULT_GROUP@@DEMO",
@@ -752,3 +1092,896 @@ func TestNamingClient_SelectInstances_Empty(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Equal(t, 0, len(instances))
 }
+
+func TestNamingClient_CloseWithDeregister(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer func() {
+		ctrl.Finish()
+	}()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("PUT"),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/beat"),
+		gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		Return(http_agent.FakeHttpResponse(200, `{"clientBeatInterval":5000}`), nil)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("DELETE"),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+		gomock.AssignableToTypeOf(http.Header{}),
+		gomock.Eq(uint64(20*1000)),
+		gomock.Eq(map[string]string{
+			"namespaceId": "",
+			"serviceName": "DEFAULT_GROUP@@DEMO",
+			"clusterName": "",
+			"ip":          "10.0.0.10",
+			"port":        "80",
+			"ephemeral":   "true",
+		})).Times(1).
+		Return(http_agent.FakeHttpResponse(200, `ok`), nil)
+
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	proxy, _ := NewNamingProxy(clientConfigTest, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	client := NamingClient{
+		serviceProxy:      proxy,
+		hostReactor:       NewHostReactor(proxy, cacheDir, 20, true, NewSubscribeCallback(), false, 0, nil, 0, 0, false, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{}),
+		beatReactor:       NewBeatReactor(proxy, 5000),
+		deregisterOnClose: true,
+	}
+	client.beatReactor.AddBeatInfo("DEFAULT_GROUP@@DEMO", model.BeatInfo{
+		Ip:          "10.0.0.10",
+		Port:        80,
+		ServiceName: "DEFAULT_GROUP@@DEMO",
+		Period:      time.Hour,
+	})
+	assert.Nil(t, client.Close())
+	for _, beatInfo := range client.beatReactor.BeatInfos() {
+		assert.True(t, beatInfo.Stopped, "beat should be stopped after close")
+	}
+}
+
+func newBatchTestClient(t *testing.T, mockIHttpAgent *mock.MockIHttpAgent) (NamingClient, func()) {
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPut),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/beat"),
+		gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx, method, path, header, timeoutMs, params interface{}) (*http.Response, error) {
+			return http_agent.FakeHttpResponse(200, `{"clientBeatInterval":5000}`), nil
+		})
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	proxy, _ := NewNamingProxy(clientConfigTest, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	hostReactor := NewHostReactor(proxy, cacheDir, 20, true, NewSubscribeCallback(), false, 0, nil, 0, 0, false, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{})
+	client := NamingClient{
+		serviceProxy: proxy,
+		hostReactor:  hostReactor,
+		beatReactor:  NewBeatReactor(proxy, 5000),
+		redoService:  NewRedoService(proxy, hostReactor),
+	}
+	return client, func() {
+		client.Close()
+		os.RemoveAll(cacheDir)
+	}
+}
+
+func TestNamingClient_BatchRegisterInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	var mutex sync.Mutex
+	batches := map[string][]model.Instance{}
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/batch"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
+		DoAndReturn(func(ctx, method, path, header, timeoutMs interface{}, params map[string]string) (*http.Response, error) {
+			var instances []model.Instance
+			assert.Nil(t, json.Unmarshal([]byte(params["instances"]), &instances))
+			mutex.Lock()
+			batches[params["serviceName"]] = instances
+			mutex.Unlock()
+			return http_agent.FakeHttpResponse(200, `ok`), nil
+		})
+	client, cleanup := newBatchTestClient(t, mockIHttpAgent)
+	defer cleanup()
+
+	success, err := client.BatchRegisterInstance(vo.BatchRegisterInstanceParam{
+		Instances: []vo.RegisterInstanceParam{
+			{ServiceName: "DEMO", Ip: "10.0.0.10", Port: 80, Weight: 1, Ephemeral: true},
+			{ServiceName: "DEMO", Ip: "10.0.0.11", Port: 80, Weight: 1, Ephemeral: true},
+			{ServiceName: "OTHER", Ip: "10.0.0.12", Port: 80, Weight: 1, Ephemeral: true},
+			{ServiceName: "", Ip: "10.0.0.13", Port: 80},
+		},
+	})
+	assert.False(t, success)
+	batchErr, ok := err.(*BatchError)
+	assert.True(t, ok)
+	assert.Len(t, batchErr.Errors, 1)
+	assert.NotNil(t, batchErr.Errors[3])
+	assert.Len(t, batches["DEFAULT_GROUP@@DEMO"], 2)
+	assert.Len(t, batches["DEFAULT_GROUP@@OTHER"], 1)
+	assert.True(t, client.beatReactor.HasBeatInfo("DEFAULT_GROUP@@DEMO", "10.0.0.11", 80))
+}
+
+func TestNamingClient_BatchRegisterInstanceUnsupported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	var batchCalls, singleCalls int32
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/batch"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx, method, path, header, timeoutMs interface{}, params map[string]string) (*http.Response, error) {
+			atomic.AddInt32(&batchCalls, 1)
+			return http_agent.FakeHttpResponse(404, `not found`), nil
+		})
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(4).
+		DoAndReturn(func(ctx, method, path, header, timeoutMs interface{}, params map[string]string) (*http.Response, error) {
+			atomic.AddInt32(&singleCalls, 1)
+			return http_agent.FakeHttpResponse(200, `ok`), nil
+		})
+	client, cleanup := newBatchTestClient(t, mockIHttpAgent)
+	defer cleanup()
+
+	param := vo.BatchRegisterInstanceParam{
+		Instances: []vo.RegisterInstanceParam{
+			{ServiceName: "DEMO", Ip: "10.0.0.10", Port: 80, Weight: 1, Ephemeral: true},
+			{ServiceName: "DEMO", Ip: "10.0.0.11", Port: 80, Weight: 1, Ephemeral: true},
+		},
+	}
+	success, err := client.BatchRegisterInstance(param)
+	assert.True(t, success)
+	assert.Nil(t, err)
+	calls := atomic.LoadInt32(&batchCalls)
+
+	// 已知服务端不支持批量接口后直接逐个注册
+	success, err = client.BatchRegisterInstance(param)
+	assert.True(t, success)
+	assert.Nil(t, err)
+	assert.Equal(t, calls, atomic.LoadInt32(&batchCalls))
+	assert.Equal(t, int32(4), atomic.LoadInt32(&singleCalls))
+}
+
+func TestNamingClient_BatchDeregisterInstanceCanceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client, cleanup := newBatchTestClient(t, mock.NewMockIHttpAgent(ctrl))
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	success, err := client.BatchDeregisterInstanceWithContext(ctx, vo.BatchDeregisterInstanceParam{
+		Instances: []vo.DeregisterInstanceParam{
+			{ServiceName: "DEMO", Ip: "10.0.0.10", Port: 80},
+			{ServiceName: "DEMO", Ip: "10.0.0.11", Port: 80},
+		},
+	})
+	assert.False(t, success)
+	batchErr, ok := err.(*BatchError)
+	assert.True(t, ok)
+	assert.Len(t, batchErr.Errors, 2)
+	assert.Equal(t, context.Canceled, batchErr.Errors[0])
+}
+
+func TestNamingClient_BatchDeregisterInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer func() {
+		ctrl.Finish()
+	}()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("DELETE"),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+		gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx, method, path, header, timeoutMs interface{}, params map[string]string) (*http.Response, error) {
+			if params["ip"] == "10.0.0.11" {
+				return http_agent.FakeHttpResponse(400, `bad request`), nil
+			}
+			return http_agent.FakeHttpResponse(200, `ok`), nil
+		})
+
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	proxy, _ := NewNamingProxy(clientConfigTest, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	client := NamingClient{
+		serviceProxy: proxy,
+		hostReactor:  NewHostReactor(proxy, cacheDir, 20, true, NewSubscribeCallback(), false, 0, nil, 0, 0, false, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{}),
+		beatReactor:  NewBeatReactor(proxy, 5000),
+	}
+	defer client.Close()
+
+	success, err := client.BatchDeregisterInstance(vo.BatchDeregisterInstanceParam{
+		Instances: []vo.DeregisterInstanceParam{
+			{ServiceName: "DEMO", Ip: "10.0.0.10", Port: 80, Ephemeral: true},
+			{ServiceName: "DEMO", Ip: "10.0.0.11", Port: 80, Ephemeral: true},
+			{ServiceName: "DEMO", Ip: "10.0.0.12", Port: 80, Ephemeral: true},
+		},
+	})
+	assert.False(t, success)
+	batchErr, ok := err.(*BatchError)
+	assert.True(t, ok)
+	assert.Len(t, batchErr.Errors, 1)
+	assert.NotNil(t, batchErr.Errors[1])
+}
+
+func TestNamingClient_UpdateInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer func() {
+		ctrl.Finish()
+	}()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("PUT"),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+		gomock.AssignableToTypeOf(http.Header{}),
+		gomock.Eq(uint64(20*1000)),
+		gomock.Eq(map[string]string{
+			"namespaceId": "",
+			"serviceName": "DEFAULT_GROUP@@DEMO",
+			"groupName":   "DEFAULT_GROUP",
+			"clusterName": "",
+			"ip":          "10.0.0.10",
+			"port":        "80",
+			"weight":      "2",
+			"enabled":     "true",
+			"healthy":     "true",
+			"metadata":    `{"version":"2"}`,
+			"ephemeral":   "false",
+		})).Times(1).
+		Return(http_agent.FakeHttpResponse(200, `ok`), nil)
+
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	proxy, _ := NewNamingProxy(clientConfigTest, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	client := NamingClient{
+		serviceProxy: proxy,
+		hostReactor:  NewHostReactor(proxy, cacheDir, 20, true, NewSubscribeCallback(), false, 0, nil, 0, 0, true, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{}),
+		beatReactor:  NewBeatReactor(proxy, 5000),
+	}
+	success, err := client.UpdateInstance(vo.UpdateInstanceParam{
+		ServiceName: "DEMO",
+		Ip:          "10.0.0.10",
+		Port:        80,
+		Weight:      2,
+		Enable:      true,
+		Healthy:     true,
+		Metadata:    map[string]string{"version": "2"},
+	})
+	assert.Equal(t, ErrCacheOnlyMode, err)
+	assert.False(t, success)
+
+	client.hostReactor = NewHostReactor(proxy, cacheDir, 20, true, NewSubscribeCallback(), false, 0, nil, 0, 0, false, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{})
+	defer client.Close()
+	success, err = client.UpdateInstance(vo.UpdateInstanceParam{
+		ServiceName: "DEMO",
+		Ip:          "10.0.0.10",
+		Port:        80,
+		Weight:      2,
+		Enable:      true,
+		Healthy:     true,
+		Metadata:    map[string]string{"version": "2"},
+	})
+	assert.Nil(t, err)
+	assert.True(t, success)
+}
+
+func TestNamingClient_DeregisterRegisteredEphemeralInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer func() {
+		ctrl.Finish()
+	}()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("DELETE"),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx, method, path, header, timeoutMs interface{}, params map[string]string) (*http.Response, error) {
+			assert.Equal(t, "true", params["ephemeral"])
+			return http_agent.FakeHttpResponse(200, `ok`), nil
+		})
+
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	proxy, _ := NewNamingProxy(clientConfigTest, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	client := NamingClient{
+		serviceProxy: proxy,
+		hostReactor:  NewHostReactor(proxy, cacheDir, 20, true, NewSubscribeCallback(), false, 0, nil, 0, 0, false, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{}),
+		beatReactor:  NewBeatReactor(proxy, 5000),
+	}
+	defer client.Close()
+	// 模拟通过该客户端注册的临时实例，不启动心跳
+	client.beatReactor.beatMap.Set(buildKey("DEFAULT_GROUP@@DEMO", "10.0.0.10", 80), &model.BeatInfo{
+		Ip:          "10.0.0.10",
+		Port:        80,
+		ServiceName: "DEFAULT_GROUP@@DEMO",
+		Period:      time.Hour,
+	})
+
+	success, err := client.DeregisterInstance(vo.DeregisterInstanceParam{
+		ServiceName: "DEMO",
+		Ip:          "10.0.0.10",
+		Port:        80,
+	})
+	assert.Nil(t, err)
+	assert.True(t, success)
+	assert.False(t, client.beatReactor.HasBeatInfo("DEFAULT_GROUP@@DEMO", "10.0.0.10", 80))
+}
+
+func TestNamingClient_GetAllServicesInfoAndSearchService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer func() {
+		ctrl.Finish()
+	}()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	gomock.InOrder(
+		mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("GET"),
+			gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/service/list"),
+			gomock.AssignableToTypeOf(http.Header{}),
+			gomock.Eq(uint64(20*1000)),
+			gomock.Eq(map[string]string{
+				"namespaceId": "ns1",
+				"groupName":   "DEFAULT_GROUP",
+				"pageNo":      "2",
+				"pageSize":    "10",
+			})).Times(1).
+			Return(http_agent.FakeHttpResponse(200, `{"count":12,"doms":["order-11","user-12"]}`), nil),
+		mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("GET"),
+			gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/service/list"),
+			gomock.AssignableToTypeOf(http.Header{}),
+			gomock.Eq(uint64(20*1000)),
+			gomock.Eq(map[string]string{
+				"namespaceId": "",
+				"groupName":   "DEFAULT_GROUP",
+				"pageNo":      "1",
+				"pageSize":    "500",
+			})).Times(1).
+			Return(http_agent.FakeHttpResponse(200, `{"count":4,"doms":["order-1","user-1","order-2","order-3"]}`), nil),
+	)
+
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	proxy, _ := NewNamingProxy(clientConfigTest, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	client := NamingClient{
+		serviceProxy: proxy,
+		hostReactor:  NewHostReactor(proxy, cacheDir, 20, true, NewSubscribeCallback(), false, 0, nil, 0, 0, false, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{}),
+		beatReactor:  NewBeatReactor(proxy, 5000),
+	}
+	serviceList, err := client.GetAllServicesInfo(vo.GetAllServiceInfoParam{NameSpace: "ns1", PageNo: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, model.ServiceList{Count: 12, Doms: []string{"order-11", "user-12"}}, serviceList)
+
+	serviceList, err = client.SearchService(vo.SearchServiceParam{Pattern: "order-*", PageNo: 2, PageSize: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, model.ServiceList{Count: 3, Doms: []string{"order-3"}}, serviceList)
+
+	_, err = client.SearchService(vo.SearchServiceParam{Pattern: "order-["})
+	assert.NotNil(t, err)
+}
+
+func TestNamingClient_WatchAllServices(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	gomock.InOrder(
+		mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("GET"),
+			gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/service/list"),
+			gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+			Return(http_agent.FakeHttpResponse(200, `{"count":3,"doms":["order-1","order-2","user-1"]}`), nil),
+		mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("GET"),
+			gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/service/list"),
+			gomock.Any(), gomock.Any(), gomock.Any()).MinTimes(1).
+			Return(http_agent.FakeHttpResponse(200, `{"count":2,"doms":["order-2","order-3"]}`), nil),
+	)
+
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	proxy, _ := NewNamingProxy(clientConfigTest, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	client := NamingClient{
+		serviceProxy: proxy,
+		hostReactor:  NewHostReactor(proxy, cacheDir, 20, true, NewSubscribeCallback(), false, 0, nil, 0, 0, false, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{}),
+		beatReactor:  NewBeatReactor(proxy, 5000),
+	}
+	defer client.hostReactor.Stop()
+
+	assert.NotNil(t, client.WatchAllServices(vo.WatchServicesParam{}))
+	events := make(chan model.ServiceListChangeEvent, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.Nil(t, client.WatchAllServicesWithContext(ctx, vo.WatchServicesParam{
+		Pattern:  "order-*",
+		Interval: 50 * time.Millisecond,
+		Callback: func(event model.ServiceListChangeEvent) {
+			events <- event
+		},
+	}))
+	assert.Equal(t, model.ServiceListChangeEvent{GroupName: "DEFAULT_GROUP", Added: []string{"order-1", "order-2"}}, <-events)
+	select {
+	case event := <-events:
+		assert.Equal(t, model.ServiceListChangeEvent{GroupName: "DEFAULT_GROUP", Added: []string{"order-3"}, Removed: []string{"order-1"}}, event)
+	case <-time.After(3 * time.Second):
+		t.Fatal("service list change not notified")
+	}
+}
+
+func TestNamingClient_UpdateClientConfigAndServerConfig(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer func() {
+		ctrl.Finish()
+	}()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	gomock.InOrder(
+		mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("DELETE"),
+			gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+			gomock.AssignableToTypeOf(http.Header{}),
+			gomock.Eq(uint64(5*1000)),
+			gomock.Any()).Times(1).
+			Return(http_agent.FakeHttpResponse(200, `ok`), nil),
+		mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("DELETE"),
+			gomock.Eq("http://127.0.0.1:8848/nacos/v1/ns/instance"),
+			gomock.AssignableToTypeOf(http.Header{}),
+			gomock.Eq(uint64(5*1000)),
+			gomock.Any()).Times(1).
+			Return(http_agent.FakeHttpResponse(200, `ok`), nil),
+	)
+
+	clientConfig := clientConfigTest
+	clientConfig.ListenInterval = 30 * 1000
+	nc := nacos_client.NacosClient{}
+	nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	assert.Nil(t, nc.SetClientConfig(clientConfig))
+	nc.SetHttpAgent(mockIHttpAgent)
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	proxy, _ := NewNamingProxy(clientConfig, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	client := NamingClient{
+		INacosClient: &nc,
+		serviceProxy: proxy,
+		hostReactor:  NewHostReactor(proxy, cacheDir, 20, true, NewSubscribeCallback(), false, 0, nil, 0, 0, false, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{}),
+		beatReactor:  NewBeatReactor(proxy, 5000),
+	}
+	param := vo.DeregisterInstanceParam{ServiceName: "DEMO", Ip: "10.0.0.10", Port: 80, Ephemeral: true}
+
+	assert.Nil(t, client.UpdateClientConfig(constant.WithTimeoutMs(5*1000)))
+	clientConfig, _ = client.GetClientConfig()
+	assert.Equal(t, uint64(5*1000), clientConfig.TimeoutMs)
+	_, err := client.DeregisterInstance(param)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, client.UpdateServerConfig(nil))
+	assert.Nil(t, client.UpdateServerConfig([]constant.ServerConfig{{IpAddr: "127.0.0.1", Port: 8848}}))
+	_, err = client.DeregisterInstance(param)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, client.UpdateClientConfig(constant.WithTimeoutMs(0)), "invalid config should be rejected")
+}
+
+func TestNamingProxy_RateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/list"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		Return(http_agent.FakeHttpResponse(200, `{"name":"DEFAULT_GROUP@@DEMO","hosts":[]}`), nil)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
+		Return(http_agent.FakeHttpResponse(200, `ok`), nil)
+
+	clientConfig := clientConfigTest
+	clientConfig.RateLimit = &rate_limiter.RateLimitConfig{QueryPerSecond: 1, FastFail: true}
+	proxy, _ := NewNamingProxy(clientConfig, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	ctx := context.Background()
+	_, err := proxy.QueryList(ctx, "DEFAULT_GROUP@@DEMO", "", 0, false)
+	assert.Nil(t, err)
+	_, err = proxy.QueryList(ctx, "DEFAULT_GROUP@@DEMO", "", 0, false)
+	assert.Equal(t, rate_limiter.ErrRateLimited, err)
+
+	// 写请求不限流
+	for i := 0; i < 2; i++ {
+		_, err = proxy.RegisterInstance(ctx, "DEFAULT_GROUP@@DEMO", "DEFAULT_GROUP", model.Instance{Ip: "10.0.0.10", Port: 80})
+		assert.Nil(t, err)
+	}
+}
+
+func TestNamingClient_UpdateInstance_Ephemeral(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		Return(http_agent.FakeHttpResponse(200, `ok`), nil)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPut),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx, method, path, header, timeoutMs interface{}, params map[string]string) (*http.Response, error) {
+			assert.Equal(t, "true", params["ephemeral"])
+			assert.Equal(t, "false", params["enabled"])
+			return http_agent.FakeHttpResponse(200, `ok`), nil
+		})
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPut),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/beat"),
+		gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		Return(http_agent.FakeHttpResponse(200, `{"clientBeatInterval":5000}`), nil)
+
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	proxy, _ := NewNamingProxy(clientConfigTest, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	client := NamingClient{
+		serviceProxy: proxy,
+		hostReactor:  NewHostReactor(proxy, cacheDir, 20, true, NewSubscribeCallback(), false, 0, nil, 0, 0, false, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{}),
+		beatReactor:  NewBeatReactor(proxy, 5000),
+	}
+	defer client.Close()
+	_, err := client.RegisterInstance(vo.RegisterInstanceParam{
+		ServiceName: "DEMO",
+		Ip:          "10.0.0.10",
+		Port:        80,
+		Weight:      1,
+		Enable:      true,
+		Healthy:     true,
+		Ephemeral:   true,
+		Metadata:    map[string]string{"version": "1"},
+	})
+	assert.Nil(t, err)
+
+	success, err := client.UpdateInstance(vo.UpdateInstanceParam{
+		ServiceName: "DEMO",
+		Ip:          "10.0.0.10",
+		Port:        80,
+		Weight:      0.5,
+		Enable:      false,
+		Healthy:     true,
+		Metadata:    map[string]string{"version": "2"},
+	})
+	assert.Nil(t, err)
+	assert.True(t, success)
+	beatInfos := client.beatReactor.BeatInfos()
+	assert.Equal(t, 1, len(beatInfos))
+	assert.Equal(t, 0.5, beatInfos[0].Weight)
+	assert.True(t, beatInfos[0].Disabled)
+	assert.Equal(t, map[string]string{"version": "2"}, beatInfos[0].Metadata)
+}
+
+func TestNamingClient_ServiceOperations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/service"),
+		gomock.AssignableToTypeOf(http.Header{}),
+		gomock.Eq(uint64(20*1000)),
+		gomock.Eq(map[string]string{
+			"namespaceId":      "",
+			"serviceName":      "DEMO",
+			"groupName":        "DEFAULT_GROUP",
+			"protectThreshold": "0.5",
+			"metadata":         `{"owner":"team-a"}`,
+			"selector":         `{"type":"none","expression":""}`,
+		})).Times(1).
+		Return(http_agent.FakeHttpResponse(200, `ok`), nil)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPut),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/service"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx, method, path, header, timeoutMs interface{}, params map[string]string) (*http.Response, error) {
+			assert.Equal(t, `{"type":"label","expression":"CONSUMER.label.env = PROVIDER.label.env"}`, params["selector"])
+			return http_agent.FakeHttpResponse(200, `ok`), nil
+		})
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/service"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		Return(http_agent.FakeHttpResponse(200, `{"namespaceId":"public","groupName":"DEFAULT_GROUP","name":"DEMO",`+
+			`"protectThreshold":0.5,"metadata":{"owner":"team-a"},"selector":{"type":"none"},`+
+			`"clusters":[{"name":"DEFAULT","metadata":{}}]}`), nil)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodDelete),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/service"),
+		gomock.Any(), gomock.Any(), gomock.Any()).MinTimes(1).
+		Return(http_agent.FakeHttpResponse(400, `service DEFAULT_GROUP@@DEMO not empty`), nil)
+
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	proxy, _ := NewNamingProxy(clientConfigTest, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	client := NamingClient{
+		serviceProxy: proxy,
+		hostReactor:  NewHostReactor(proxy, cacheDir, 20, true, NewSubscribeCallback(), false, 0, nil, 0, 0, false, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{}),
+		beatReactor:  NewBeatReactor(proxy, 5000),
+	}
+	defer client.Close()
+
+	_, err := client.CreateService(vo.CreateServiceParam{})
+	assert.NotNil(t, err)
+	success, err := client.CreateService(vo.CreateServiceParam{
+		ServiceName:      "DEMO",
+		ProtectThreshold: 0.5,
+		Metadata:         map[string]string{"owner": "team-a"},
+	})
+	assert.Nil(t, err)
+	assert.True(t, success)
+
+	success, err = client.UpdateService(vo.UpdateServiceParam{
+		ServiceName:      "DEMO",
+		ProtectThreshold: 0.5,
+		Metadata:         map[string]string{"owner": "team-a"},
+		Selector:         &model.ExpressionSelector{Type: "label", Expression: "CONSUMER.label.env = PROVIDER.label.env"},
+	})
+	assert.Nil(t, err)
+	assert.True(t, success)
+
+	service, err := client.GetServiceDetail(vo.GetServiceDetailParam{ServiceName: "DEMO"})
+	assert.Nil(t, err)
+	assert.Equal(t, "DEMO", service.Name)
+	assert.Equal(t, 0.5, service.ProtectThreshold)
+	assert.Equal(t, map[string]string{"owner": "team-a"}, service.Metadata)
+	assert.Equal(t, "none", service.Selector.Type)
+	assert.Equal(t, 1, len(service.Clusters))
+
+	success, err = client.DeleteService(vo.DeleteServiceParam{ServiceName: "DEMO"})
+	assert.NotNil(t, err)
+	assert.False(t, success)
+}
+
+type spanContextKey struct{}
+
+type testSpan struct {
+	name   string
+	attrs  map[string]string
+	status tracing.StatusCode
+	ended  bool
+}
+
+func (s *testSpan) SetAttributes(attrs ...tracing.Attribute) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+func (s *testSpan) RecordError(err error)                                 {}
+func (s *testSpan) SetStatus(code tracing.StatusCode, description string) { s.status = code }
+func (s *testSpan) End()                                                  { s.ended = true }
+
+type testTracer struct {
+	mutex sync.Mutex
+	spans []*testSpan
+}
+
+func (tr *testTracer) Tracer(name string) tracing.Tracer {
+	return tr
+}
+
+func (tr *testTracer) Start(ctx context.Context, spanName string, attrs ...tracing.Attribute) (context.Context, tracing.Span) {
+	span := &testSpan{name: spanName, attrs: map[string]string{}}
+	span.SetAttributes(attrs...)
+	tr.mutex.Lock()
+	tr.spans = append(tr.spans, span)
+	tr.mutex.Unlock()
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+func TestNamingProxy_Tracing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/list"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			// 请求在span的ctx中发出，拦截器可从中获取span
+			assert.NotNil(t, ctx.Value(spanContextKey{}))
+			return http_agent.FakeHttpResponse(200, `{"name":"DEFAULT_GROUP@@DEMO","hosts":[]}`), nil
+		})
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodDelete),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+		gomock.Any(), gomock.Any(), gomock.Any()).MinTimes(1).
+		Return(http_agent.FakeHttpResponse(400, `bad request`), nil)
+
+	tracer := &testTracer{}
+	clientConfig := clientConfigTest
+	clientConfig.NamespaceId = "dev"
+	clientConfig.TracerProvider = tracer
+	proxy, _ := NewNamingProxy(clientConfig, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	_, err := proxy.QueryList(context.Background(), "DEFAULT_GROUP@@DEMO", "", 0, false)
+	assert.Nil(t, err)
+	_, err = proxy.DeregisterInstance(context.Background(), "DEFAULT_GROUP@@DEMO", "10.0.0.10", 80, "", true)
+	assert.NotNil(t, err)
+
+	assert.Equal(t, 2, len(tracer.spans))
+	span := tracer.spans[0]
+	assert.Equal(t, "GET /v1/ns/instance/list", span.name)
+	assert.True(t, span.ended)
+	assert.Equal(t, tracing.STATUS_UNSET, span.status)
+	assert.Equal(t, "dev", span.attrs[tracing.ATTR_NAMESPACE])
+	assert.Equal(t, "DEFAULT_GROUP@@DEMO", span.attrs[tracing.ATTR_SERVICE_NAME])
+	assert.Equal(t, "console.nacos.io:80", span.attrs[tracing.ATTR_SERVER_ADDRESS])
+	assert.Equal(t, "1", span.attrs[tracing.ATTR_ATTEMPTS])
+
+	span = tracer.spans[1]
+	assert.Equal(t, "DELETE /v1/ns/instance", span.name)
+	assert.Equal(t, tracing.STATUS_ERROR, span.status)
+}
+
+func TestNamingClient_UpdateServiceCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	// 不配置服务端，mock的HttpAgent没有预期调用，任何网络请求都会导致测试失败
+	nc := nacos_client.NacosClient{}
+	nc.SetServerConfig(nil)
+	clientConfig := clientConfigTest
+	clientConfig.ListenInterval = 30 * 1000
+	clientConfig.CacheDir = cacheDir
+	clientConfig.CacheOnly = true
+	nc.SetClientConfig(clientConfig)
+	nc.SetHttpAgent(mock.NewMockIHttpAgent(ctrl))
+	client, err := NewNamingClient(&nc)
+	assert.Nil(t, err)
+	defer client.Close()
+
+	_, err = client.SelectAllInstances(vo.SelectAllInstancesParam{ServiceName: "DEMO"})
+	assert.Equal(t, ErrCacheOnlyMode, err)
+	assert.NotNil(t, client.UpdateServiceCache(vo.UpdateServiceCacheParam{}))
+
+	changed := make(chan []model.SubscribeService, 2)
+	assert.Nil(t, client.UpdateServiceCache(vo.UpdateServiceCacheParam{
+		ServiceName: "DEMO",
+		Hosts:       []model.Instance{{Ip: "10.0.0.10", Port: 80, Weight: 1, Enable: true, Healthy: true}},
+	}))
+	assert.Nil(t, client.Subscribe(&vo.SubscribeParam{
+		ServiceName: "DEMO",
+		SubscribeCallback: func(services []model.SubscribeService, err error) {
+			changed <- services
+		},
+	}))
+	instances, err := client.SelectInstances(vo.SelectInstancesParam{ServiceName: "DEMO", HealthyOnly: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(instances))
+	assert.Equal(t, "DEFAULT_GROUP@@DEMO", instances[0].ServiceName)
+
+	assert.Nil(t, client.UpdateServiceCache(vo.UpdateServiceCacheParam{
+		ServiceName: "DEMO",
+		Hosts: []model.Instance{
+			{Ip: "10.0.0.10", Port: 80, Weight: 1, Enable: true, Healthy: true},
+			{Ip: "10.0.0.11", Port: 80, Weight: 1, Enable: true, Healthy: true},
+		},
+	}))
+	select {
+	case services := <-changed:
+		assert.Equal(t, 2, len(services))
+	case <-time.After(3 * time.Second):
+		t.Fatal("subscriber was not notified")
+	}
+
+	assert.True(t, client.PurgeServiceCache(vo.PurgeServiceCacheParam{ServiceName: "DEMO"}))
+	_, err = client.SelectAllInstances(vo.SelectAllInstancesParam{ServiceName: "DEMO"})
+	assert.Equal(t, ErrCacheOnlyMode, err)
+}
+
+func TestNamingClient_RegisterInstance_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	// 参数不合法时不发送请求，mock的HttpAgent没有预期调用
+	nc := nacos_client.NacosClient{}
+	nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	clientConfig := clientConfigTest
+	clientConfig.ListenInterval = 30 * 1000
+	nc.SetClientConfig(clientConfig)
+	nc.SetHttpAgent(mock.NewMockIHttpAgent(ctrl))
+	client, err := NewNamingClient(&nc)
+	assert.Nil(t, err)
+	defer client.Close()
+
+	success, err := client.RegisterInstance(vo.RegisterInstanceParam{
+		ServiceName: "DEFAULT_GROUP@@DEMO",
+		Ip:          "10.0.0.10",
+		Port:        0,
+		Weight:      -1,
+	})
+	assert.False(t, success)
+	var ve *validator.ValidationError
+	assert.True(t, errors.As(err, &ve))
+	assert.Equal(t, 3, len(ve.Errors))
+}
+
+func TestNamingClient_SelectInstanceByHash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	nc := nacos_client.NacosClient{}
+	nc.SetServerConfig(nil)
+	clientConfig := clientConfigTest
+	clientConfig.ListenInterval = 30 * 1000
+	clientConfig.CacheDir = cacheDir
+	clientConfig.CacheOnly = true
+	nc.SetClientConfig(clientConfig)
+	nc.SetHttpAgent(mock.NewMockIHttpAgent(ctrl))
+	client, err := NewNamingClient(&nc)
+	assert.Nil(t, err)
+	defer client.Close()
+
+	hosts := []model.Instance{
+		{Ip: "10.0.0.10", Port: 80, Weight: 1, Enable: true, Healthy: true},
+		{Ip: "10.0.0.11", Port: 80, Weight: 1, Enable: true, Healthy: true},
+		{Ip: "10.0.0.12", Port: 80, Weight: 1, Enable: true, Healthy: true},
+	}
+	assert.Nil(t, client.UpdateServiceCache(vo.UpdateServiceCacheParam{ServiceName: "DEMO", Hosts: hosts}))
+	param := vo.SelectOneHealthInstanceParam{ServiceName: "DEMO"}
+	selected, err := client.SelectInstanceByHash(param, "user-1")
+	assert.Nil(t, err)
+	ring, _ := client.hashRingMap.Get("DEFAULT_GROUP@@DEMO#")
+	for i := 0; i < 10; i++ {
+		instance, _ := client.SelectInstanceByHash(param, "user-1")
+		assert.Equal(t, selected.Ip, instance.Ip)
+	}
+	current, _ := client.hashRingMap.Get("DEFAULT_GROUP@@DEMO#")
+	assert.True(t, ring == current, "the ring should be reused when instances are not changed")
+
+	// 移除未被选中的实例不影响选择结果，移除选中的实例后重新构建哈希环
+	var remains []model.Instance
+	for _, host := range hosts {
+		if host.Ip == selected.Ip || len(remains) == 0 {
+			remains = append(remains, host)
+		}
+	}
+	assert.Nil(t, client.UpdateServiceCache(vo.UpdateServiceCacheParam{ServiceName: "DEMO", Hosts: remains}))
+	instance, _ := client.SelectInstanceByHash(param, "user-1")
+	assert.Equal(t, selected.Ip, instance.Ip)
+	for i := range hosts {
+		if hosts[i].Ip == selected.Ip {
+			hosts[i].Healthy = false
+		}
+	}
+	assert.Nil(t, client.UpdateServiceCache(vo.UpdateServiceCacheParam{ServiceName: "DEMO", Hosts: hosts}))
+	instance, _ = client.SelectInstanceByHash(param, "user-1")
+	assert.NotEqual(t, selected.Ip, instance.Ip)
+
+	assert.Nil(t, client.UpdateServiceCache(vo.UpdateServiceCacheParam{ServiceName: "DEMO"}))
+	_, err = client.SelectInstanceByHash(param, "user-1")
+	assert.NotNil(t, err)
+}
+
+func TestNamingClient_DrainInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/list"),
+		gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		Return(http_agent.FakeHttpResponse(200, `{"name":"DEFAULT_GROUP@@DEMO","hosts":[{"ip":"10.0.0.10","port":80,"weight":4,
+			"enabled":true,"healthy":true,"clusterName":"c1","metadata":{"version":"2"}}]}`), nil)
+	var updates []map[string]string
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPut),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(3).
+		DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			updates = append(updates, params)
+			return http_agent.FakeHttpResponse(200, `ok`), nil
+		})
+
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	proxy, _ := NewNamingProxy(clientConfigTest, []constant.ServerConfig{serverConfigTest}, mockIHttpAgent)
+	client := NamingClient{
+		serviceProxy: proxy,
+		hostReactor:  NewHostReactor(proxy, cacheDir, 20, true, NewSubscribeCallback(), false, 0, nil, 0, 0, false, PushReceiverConfig{}, ServiceCacheConfig{}, SerializerConfig{}),
+		beatReactor:  NewBeatReactor(proxy, 5000),
+	}
+	start := time.Now()
+	success, err := client.DrainInstance(vo.DrainInstanceParam{ServiceName: "DEMO", Ip: "10.0.0.10", Port: 80, Steps: 2}, 100*time.Millisecond)
+	assert.Nil(t, err)
+	assert.True(t, success)
+	assert.True(t, time.Since(start) >= 100*time.Millisecond)
+	assert.Equal(t, 3, len(updates))
+	assert.Equal(t, []string{"2", "0", "0"}, []string{updates[0]["weight"], updates[1]["weight"], updates[2]["weight"]})
+	assert.Equal(t, []string{"true", "true", "false"}, []string{updates[0]["enabled"], updates[1]["enabled"], updates[2]["enabled"]})
+	assert.Equal(t, "c1", updates[2]["clusterName"])
+	assert.Equal(t, `{"version":"2"}`, updates[2]["metadata"])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.DrainInstanceWithContext(ctx, vo.DrainInstanceParam{ServiceName: "DEMO", Ip: "10.0.0.10", Port: 80}, time.Second)
+	assert.Equal(t, context.Canceled, err)
+	_, err = client.DrainInstance(vo.DrainInstanceParam{ServiceName: "DEMO", Ip: "10.0.0.11", Port: 80}, time.Second)
+	assert.NotNil(t, err)
+}
+
+func Test_namingCacheDir(t *testing.T) {
+	sep := string(os.PathSeparator)
+	assert.Equal(t, "/cache"+sep+"naming", namingCacheDir(constant.ClientConfig{CacheDir: "/cache", NamespaceId: "dev"}))
+	assert.Equal(t, "/cache"+sep+"naming"+sep+"dev", namingCacheDir(constant.ClientConfig{CacheDir: "/cache", NamespaceId: "dev", JavaCacheLayout: true}))
+	assert.Equal(t, "/cache"+sep+"naming"+sep+"public", namingCacheDir(constant.ClientConfig{CacheDir: "/cache", JavaCacheLayout: true}))
+}
@@ -1,37 +1,77 @@
 package naming_client
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/buger/jsonparser"
 	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/event"
 	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/common/local_ip"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/common/nacos_error"
 	"github.com/nacos-group/nacos-sdk-go/common/nacos_server"
 	"github.com/nacos-group/nacos-sdk-go/model"
 	"github.com/nacos-group/nacos-sdk-go/utils"
-	"log"
+	"net"
 	"net/http"
 	"strconv"
+	"sync/atomic"
 )
 
 type NamingProxy struct {
 	clientConfig constant.ClientConfig
 	nacosServer  nacos_server.NacosServer
+	localIp      *local_ip.Detector
+	// 服务端不支持批量注册接口时置为1，之后不再尝试
+	batchUnsupported *int32
 }
 
 func NewNamingProxy(clientCfg constant.ClientConfig, serverCfgs []constant.ServerConfig, httpAgent http_agent.IHttpAgent) (NamingProxy, error) {
-	srvProxy := NamingProxy{}
+	srvProxy := NamingProxy{batchUnsupported: new(int32)}
 	srvProxy.clientConfig = clientCfg
 	var err error
-	srvProxy.nacosServer, err = nacos_server.NewNacosServer(serverCfgs, httpAgent, clientCfg.TimeoutMs, clientCfg.Endpoint)
+	srvProxy.nacosServer, err = nacos_server.NewNacosServer(serverCfgs, clientCfg, httpAgent)
 	if err != nil {
 		return srvProxy, err
 	}
+	srvProxy.localIp = newLocalIpDetector(clientCfg, srvProxy.nacosServer)
 	return srvProxy, nil
 }
 
-func (proxy *NamingProxy) RegisterInstance(serviceName string, groupName string, instance model.Instance) (string, error) {
-	log.Printf("[INFO] register instance namespaceId:<%s>,serviceName:<%s> with instance:<%s> \n", proxy.clientConfig.NamespaceId, serviceName, utils.ToJsonString(instance))
+// 以当前的服务端地址探测出口ip
+func newLocalIpDetector(clientCfg constant.ClientConfig, nacosServer nacos_server.NacosServer) *local_ip.Detector {
+	var config local_ip.LocalIpConfig
+	if clientCfg.LocalIp != nil {
+		config = *clientCfg.LocalIp
+	}
+	return local_ip.NewDetector(config, func() []string {
+		var addrs []string
+		for _, server := range nacosServer.GetServerList() {
+			addrs = append(addrs, net.JoinHostPort(server.IpAddr, strconv.FormatUint(server.Port, 10)))
+		}
+		return addrs
+	})
+}
+
+// 上报给服务端的本机ip，用于注册时未指定ip的实例和接收推送的地址，见local_ip.LocalIpConfig
+func (proxy *NamingProxy) LocalIp() string {
+	if proxy.localIp != nil {
+		if ip := proxy.localIp.IP(); ip != "" {
+			return ip
+		}
+	}
+	return utils.LocalIP()
+}
+
+func (proxy *NamingProxy) publishEvent(e event.Event) {
+	proxy.nacosServer.Events().Publish(e)
+}
+
+func (proxy *NamingProxy) RegisterInstance(ctx context.Context, serviceName string, groupName string, instance model.Instance) (string, error) {
+	logger.Infof("register instance namespaceId:<%s>,serviceName:<%s> with instance:<%s>", proxy.clientConfig.NamespaceId, serviceName, utils.ToJsonString(instance))
 	params := map[string]string{}
 	params["namespaceId"] = proxy.clientConfig.NamespaceId
 	params["serviceName"] = serviceName
@@ -44,11 +84,71 @@ func (proxy *NamingProxy) RegisterInstance(serviceName string, groupName string,
 	params["healthy"] = strconv.FormatBool(instance.Healthy)
 	params["metadata"] = utils.ToJsonString(instance.Metadata)
 	params["ephemeral"] = strconv.FormatBool(instance.Ephemeral)
-	return proxy.nacosServer.ReqApi(constant.SERVICE_PATH, params, http.MethodPost)
+	if proxy.clientConfig.AppName != "" {
+		params[constant.KEY_APP] = proxy.clientConfig.AppName
+	}
+	return proxy.nacosServer.ReqApi(ctx, constant.SERVICE_PATH, params, http.MethodPost)
+}
+
+// 服务端不支持批量注册接口时返回该错误，调用方应改为逐个注册
+var ErrBatchUnsupported = errors.New("batch register is not supported by server")
+
+// 通过批量接口注册同一服务的多个实例，一次请求全部成功或全部失败
+// 服务端返回404、405或501时视为不支持，不重试，记录后返回ErrBatchUnsupported，之后的调用不再请求服务端
+func (proxy *NamingProxy) BatchRegisterInstance(ctx context.Context, serviceName string, groupName string, instances []model.Instance) (string, error) {
+	if proxy.batchUnsupported != nil && atomic.LoadInt32(proxy.batchUnsupported) == 1 {
+		return "", ErrBatchUnsupported
+	}
+	logger.Infof("batch register instance namespaceId:<%s>,serviceName:<%s> with instances:<%s>", proxy.clientConfig.NamespaceId, serviceName, utils.ToJsonString(instances))
+	params := map[string]string{}
+	params["namespaceId"] = proxy.clientConfig.NamespaceId
+	params["serviceName"] = serviceName
+	params["groupName"] = groupName
+	params["instances"] = utils.ToJsonString(instances)
+	if proxy.clientConfig.AppName != "" {
+		params[constant.KEY_APP] = proxy.clientConfig.AppName
+	}
+	result, err := proxy.nacosServer.ReqApi(nacos_server.WithNoRetry(ctx, batchUnsupportedCodes...), constant.SERVICE_BATCH_PATH, params, http.MethodPost)
+	if err != nil && batchUnsupported(err) {
+		logger.Warnf("server does not support batch register, register instances one by one, err:%s", err.Error())
+		if proxy.batchUnsupported != nil {
+			atomic.StoreInt32(proxy.batchUnsupported, 1)
+		}
+		return "", ErrBatchUnsupported
+	}
+	return result, err
+}
+
+var batchUnsupportedCodes = []int{http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented}
+
+func batchUnsupported(err error) bool {
+	for _, code := range batchUnsupportedCodes {
+		if errors.Is(err, nacos_error.NewNacosError(strconv.Itoa(code), "", nil)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (proxy *NamingProxy) UpdateInstance(ctx context.Context, serviceName string, groupName string, instance model.Instance) (string, error) {
+	logger.Infof("update instance namespaceId:<%s>,serviceName:<%s> with instance:<%s>", proxy.clientConfig.NamespaceId, serviceName, utils.ToJsonString(instance))
+	params := map[string]string{}
+	params["namespaceId"] = proxy.clientConfig.NamespaceId
+	params["serviceName"] = serviceName
+	params["groupName"] = groupName
+	params["clusterName"] = instance.ClusterName
+	params["ip"] = instance.Ip
+	params["port"] = strconv.Itoa(int(instance.Port))
+	params["weight"] = strconv.FormatFloat(instance.Weight, 'f', -1, 64)
+	params["enabled"] = strconv.FormatBool(instance.Enable)
+	params["healthy"] = strconv.FormatBool(instance.Healthy)
+	params["metadata"] = utils.ToJsonString(instance.Metadata)
+	params["ephemeral"] = strconv.FormatBool(instance.Ephemeral)
+	return proxy.nacosServer.ReqApi(ctx, constant.SERVICE_PATH, params, http.MethodPut)
 }
 
-func (proxy *NamingProxy) DeregisterInstance(serviceName string, ip string, port uint64, clusterName string, ephemeral bool) (string, error) {
-	log.Printf("[INFO] deregister instance namespaceId:<%s>,serviceName:<%s> with instance:<%s:%d@%s> \n", proxy.clientConfig.NamespaceId, serviceName, ip, port, clusterName)
+func (proxy *NamingProxy) DeregisterInstance(ctx context.Context, serviceName string, ip string, port uint64, clusterName string, ephemeral bool) (string, error) {
+	logger.Infof("deregister instance namespaceId:<%s>,serviceName:<%s> with instance:<%s:%d@%s>", proxy.clientConfig.NamespaceId, serviceName, ip, port, clusterName)
 	params := map[string]string{}
 	params["namespaceId"] = proxy.clientConfig.NamespaceId
 	params["serviceName"] = serviceName
@@ -56,24 +156,33 @@ func (proxy *NamingProxy) DeregisterInstance(serviceName string, ip string, port
 	params["ip"] = ip
 	params["port"] = strconv.Itoa(int(port))
 	params["ephemeral"] = strconv.FormatBool(ephemeral)
-	return proxy.nacosServer.ReqApi(constant.SERVICE_PATH, params, http.MethodDelete)
+	return proxy.nacosServer.ReqApi(ctx, constant.SERVICE_PATH, params, http.MethodDelete)
 }
 
-func (proxy *NamingProxy) SendBeat(info model.BeatInfo) (int64, error) {
-	log.Printf("[INFO] namespaceId:<%s> sending beat to server:<%s> \n", proxy.clientConfig.NamespaceId, utils.ToJsonString(info))
+// 服务端找不到心跳对应的实例（例如服务端重启后），需要重新注册
+var ErrBeatResourceNotFound = nacos_error.NewNacosError(strconv.Itoa(http.StatusNotFound), "instance not found on server", nil)
+
+func (proxy *NamingProxy) SendBeat(ctx context.Context, info model.BeatInfo) (int64, error) {
+	logger.Infof("namespaceId:<%s> sending beat to server:<%s>", proxy.clientConfig.NamespaceId, utils.ToJsonString(info))
 	params := map[string]string{}
 	params["namespaceId"] = proxy.clientConfig.NamespaceId
 	params["serviceName"] = info.ServiceName
 	params["beat"] = utils.ToJsonString(info)
+	if proxy.clientConfig.AppName != "" {
+		params[constant.KEY_APP] = proxy.clientConfig.AppName
+	}
 	api := constant.SERVICE_BASE_PATH + "/instance/beat"
-	result, err := proxy.nacosServer.ReqApi(api, params, http.MethodPut)
+	result, err := proxy.nacosServer.ReqApi(ctx, api, params, http.MethodPut)
 	if err != nil {
 		return 0, err
 	}
 	if result != "" {
+		if code, err := jsonparser.GetInt([]byte(result), "code"); err == nil && code == constant.RESOURCE_NOT_FOUND {
+			return 0, ErrBeatResourceNotFound
+		}
 		interVal, err := jsonparser.GetInt([]byte(result), "clientBeatInterval")
 		if err != nil {
-			return 0, errors.New(fmt.Sprintf("[ERROR] namespaceId:<%s> sending beat to server:<%s> get 'clientBeatInterval' from <%s> error:<%s>", proxy.clientConfig.NamespaceId, utils.ToJsonString(info), result, err.Error()))
+			return 0, nacos_error.Wrap(fmt.Sprintf("namespaceId:<%s> sending beat to server:<%s> get 'clientBeatInterval' from <%s> failed", proxy.clientConfig.NamespaceId, utils.ToJsonString(info), result), err)
 		} else {
 			return interVal, nil
 		}
@@ -82,9 +191,13 @@ func (proxy *NamingProxy) SendBeat(info model.BeatInfo) (int64, error) {
 
 }
 
-func (proxy *NamingProxy) GetServiceList(pageNo int, pageSize int, groupName string, selector *model.ExpressionSelector) (*model.ServiceList, error) {
+// namespaceId为空时使用ClientConfig.NamespaceId
+func (proxy *NamingProxy) GetServiceList(ctx context.Context, namespaceId string, pageNo int, pageSize int, groupName string, selector *model.ExpressionSelector) (*model.ServiceList, error) {
+	if namespaceId == "" {
+		namespaceId = proxy.clientConfig.NamespaceId
+	}
 	params := map[string]string{}
-	params["namespaceId"] = proxy.clientConfig.NamespaceId
+	params["namespaceId"] = namespaceId
 	params["groupName"] = groupName
 	params["pageNo"] = strconv.Itoa(pageNo)
 	params["pageSize"] = strconv.Itoa(pageSize)
@@ -101,7 +214,7 @@ func (proxy *NamingProxy) GetServiceList(pageNo int, pageSize int, groupName str
 	}
 
 	api := constant.SERVICE_BASE_PATH + "/service/list"
-	result, err := proxy.nacosServer.ReqApi(api, params, http.MethodGet)
+	result, err := proxy.nacosServer.ReqApi(ctx, api, params, http.MethodGet)
 	if err != nil {
 		return nil, err
 	}
@@ -112,31 +225,86 @@ func (proxy *NamingProxy) GetServiceList(pageNo int, pageSize int, groupName str
 	serviceList := model.ServiceList{}
 	count, err := jsonparser.GetInt([]byte(result), "count")
 	if err != nil {
-		return nil, errors.New(fmt.Sprintf("[ERROR] namespaceId:<%s> get service list pageNo:<%d> pageSize:<%d> selector:<%s> from <%s> get 'count' from <%s> error:<%s>", proxy.clientConfig.NamespaceId, pageNo, pageSize, utils.ToJsonString(selector), groupName, result, err.Error()))
+		return nil, nacos_error.Wrap(fmt.Sprintf("namespaceId:<%s> get service list pageNo:<%d> pageSize:<%d> selector:<%s> from <%s> get 'count' from <%s> failed", namespaceId, pageNo, pageSize, utils.ToJsonString(selector), groupName, result), err)
 	}
 	var doms []string
 	_, err = jsonparser.ArrayEach([]byte(result), func(value []byte, dataType jsonparser.ValueType, offset int, err error) {
 		doms = append(doms, string(value))
 	}, "doms")
 	if err != nil {
-		return nil, errors.New(fmt.Sprintf("[ERROR] namespaceId:<%s> get service list pageNo:<%d> pageSize:<%d> selector:<%s> from <%s> get 'doms' from <%s> error:<%s> ", proxy.clientConfig.NamespaceId, pageNo, pageSize, utils.ToJsonString(selector), groupName, result, err.Error()))
+		return nil, nacos_error.Wrap(fmt.Sprintf("namespaceId:<%s> get service list pageNo:<%d> pageSize:<%d> selector:<%s> from <%s> get 'doms' from <%s> failed", namespaceId, pageNo, pageSize, utils.ToJsonString(selector), groupName, result), err)
 	}
 	serviceList.Count = count
 	serviceList.Doms = doms
 	return &serviceList, nil
 }
 
-func (proxy *NamingProxy) ServerHealthy() bool {
+// 创建或修改服务时的参数，selector为nil时不按标签过滤实例
+func (proxy *NamingProxy) serviceParams(serviceName string, groupName string, protectThreshold float64,
+	metadata map[string]string, selector *model.ExpressionSelector) map[string]string {
+	params := map[string]string{}
+	params["namespaceId"] = proxy.clientConfig.NamespaceId
+	params["serviceName"] = serviceName
+	params["groupName"] = groupName
+	params["protectThreshold"] = strconv.FormatFloat(protectThreshold, 'f', -1, 64)
+	params["metadata"] = utils.ToJsonString(metadata)
+	if selector == nil {
+		selector = &model.ExpressionSelector{Type: "none"}
+	}
+	params["selector"] = utils.ToJsonString(selector)
+	return params
+}
+
+func (proxy *NamingProxy) CreateService(ctx context.Context, serviceName string, groupName string, protectThreshold float64,
+	metadata map[string]string, selector *model.ExpressionSelector) (string, error) {
+	logger.Infof("create service namespaceId:<%s>,serviceName:<%s>,groupName:<%s>", proxy.clientConfig.NamespaceId, serviceName, groupName)
+	params := proxy.serviceParams(serviceName, groupName, protectThreshold, metadata, selector)
+	return proxy.nacosServer.ReqApi(ctx, constant.SERVICE_INFO_PATH, params, http.MethodPost)
+}
+
+func (proxy *NamingProxy) UpdateService(ctx context.Context, serviceName string, groupName string, protectThreshold float64,
+	metadata map[string]string, selector *model.ExpressionSelector) (string, error) {
+	logger.Infof("update service namespaceId:<%s>,serviceName:<%s>,groupName:<%s>", proxy.clientConfig.NamespaceId, serviceName, groupName)
+	params := proxy.serviceParams(serviceName, groupName, protectThreshold, metadata, selector)
+	return proxy.nacosServer.ReqApi(ctx, constant.SERVICE_INFO_PATH, params, http.MethodPut)
+}
+
+func (proxy *NamingProxy) DeleteService(ctx context.Context, serviceName string, groupName string) (string, error) {
+	logger.Infof("delete service namespaceId:<%s>,serviceName:<%s>,groupName:<%s>", proxy.clientConfig.NamespaceId, serviceName, groupName)
+	params := map[string]string{}
+	params["namespaceId"] = proxy.clientConfig.NamespaceId
+	params["serviceName"] = serviceName
+	params["groupName"] = groupName
+	return proxy.nacosServer.ReqApi(ctx, constant.SERVICE_INFO_PATH, params, http.MethodDelete)
+}
+
+func (proxy *NamingProxy) GetServiceDetail(ctx context.Context, serviceName string, groupName string) (*model.ServiceMeta, error) {
+	params := map[string]string{}
+	params["namespaceId"] = proxy.clientConfig.NamespaceId
+	params["serviceName"] = serviceName
+	params["groupName"] = groupName
+	result, err := proxy.nacosServer.ReqApi(ctx, constant.SERVICE_INFO_PATH, params, http.MethodGet)
+	if err != nil {
+		return nil, err
+	}
+	var service model.ServiceMeta
+	if err = json.Unmarshal([]byte(result), &service); err != nil {
+		return nil, nacos_error.Wrap(fmt.Sprintf("namespaceId:<%s> get service:<%s@@%s> detail from <%s> failed", proxy.clientConfig.NamespaceId, groupName, serviceName, result), err)
+	}
+	return &service, nil
+}
+
+func (proxy *NamingProxy) ServerHealthy(ctx context.Context) bool {
 	api := constant.SERVICE_BASE_PATH + "/operator/metrics"
-	result, err := proxy.nacosServer.ReqApi(api, map[string]string{}, http.MethodGet)
+	result, err := proxy.nacosServer.ReqApi(ctx, api, map[string]string{}, http.MethodGet)
 	if err != nil {
-		log.Printf("[ERROR]:namespaceId:[%s] sending server healthy failed!,result:%s error:%s", proxy.clientConfig.NamespaceId, result, err.Error())
+		logger.Errorf("namespaceId:[%s] sending server healthy failed!,result:%s error:%s", proxy.clientConfig.NamespaceId, result, err.Error())
 		return false
 	}
 	if result != "" {
 		status, err := jsonparser.GetString([]byte(result), "status")
 		if err != nil {
-			log.Printf("[ERROR]:namespaceId:[%s] sending server healthy failed!,result:%s error:%s", proxy.clientConfig.NamespaceId, result, err.Error())
+			logger.Errorf("namespaceId:[%s] sending server healthy failed!,result:%s error:%s", proxy.clientConfig.NamespaceId, result, err.Error())
 		} else {
 			return status == "UP"
 		}
@@ -144,23 +312,14 @@ func (proxy *NamingProxy) ServerHealthy() bool {
 	return false
 }
 
-func (proxy *NamingProxy) QueryList(serviceName string, clusters string, udpPort int, healthyOnly bool) (string, error) {
+func (proxy *NamingProxy) QueryList(ctx context.Context, serviceName string, clusters string, udpPort int, healthyOnly bool) (string, error) {
 	param := make(map[string]string)
 	param["namespaceId"] = proxy.clientConfig.NamespaceId
 	param["serviceName"] = serviceName
 	param["clusters"] = clusters
 	param["udpPort"] = strconv.Itoa(udpPort)
 	param["healthyOnly"] = strconv.FormatBool(healthyOnly)
-	param["clientIp"] = utils.LocalIP()
+	param["clientIp"] = proxy.LocalIp()
 	api := constant.SERVICE_PATH + "/list"
-	return proxy.nacosServer.ReqApi(api, param, http.MethodGet)
-}
-
-func (proxy *NamingProxy) GetAllServiceInfoList(namespace string, groupName string, clusters string) (string, error) {
-	param := make(map[string]string)
-	param["namespaceId"] = proxy.clientConfig.NamespaceId
-	param["clusters"] = clusters
-	param["groupName"] = groupName
-	api := constant.SERVICE_INFO_PATH + "/getAll"
-	return proxy.nacosServer.ReqApi(api, param, http.MethodGet)
+	return proxy.nacosServer.ReqApi(ctx, api, param, http.MethodGet)
 }
@@ -1,20 +1,35 @@
 package naming_client
 
 import (
+	"context"
 	"encoding/json"
+	"github.com/buger/jsonparser"
+	"github.com/nacos-group/nacos-sdk-go/common/event"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/common/tracing"
+	"github.com/nacos-group/nacos-sdk-go/model"
 	"github.com/nacos-group/nacos-sdk-go/utils"
 	"log"
 	"math/rand"
 	"net"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 )
 
 type PushReceiver struct {
 	port        int
 	host        string
+	fixedPort   bool
+	onError     func(data []byte, err error)
 	hostReactor *HostReactor
+	mutex       sync.Mutex
+	conn        *net.UDPConn
+	stopChan    chan struct{}
+	stopOnce    sync.Once
+	// 每个服务最近一次推送的lastRefTime，服务端未收到ACK时以相同的lastRefTime重发
+	lastPushes map[string]int64
 }
 
 type PushData struct {
@@ -23,24 +38,87 @@ type PushData struct {
 	LastRefTime int64  `json:"lastRefTime"`
 }
 
-func NewPushRecevier(hostReactor *HostReactor) *PushReceiver {
-	pr := PushReceiver{
+// 推送接收的监听地址，Ip为空时监听所有网卡，Port为0时在54951-55950中随机选择
+type PushReceiverConfig struct {
+	Ip      string
+	Port    int
+	OnError func(data []byte, err error)
+}
+
+// UDP数据包的最大长度，压缩前较大的推送可能接近该长度
+const maxPushPacketSize = 64 * 1024
+
+func NewPushRecevier(hostReactor *HostReactor, config PushReceiverConfig) *PushReceiver {
+	pr := &PushReceiver{
+		host:        config.Ip,
+		port:        config.Port,
+		fixedPort:   config.Port > 0,
+		onError:     config.OnError,
 		hostReactor: hostReactor,
+		stopChan:    make(chan struct{}),
+		lastPushes:  map[string]int64{},
 	}
 	go pr.startServer()
-	return &pr
+	return pr
+}
+
+// 返回实际监听的端口，尚未开始监听时为0，此时服务端不向客户端推送
+func (us *PushReceiver) Port() int {
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+	if us.conn == nil {
+		return 0
+	}
+	return us.conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+// 停止接收推送并释放UDP端口
+func (us *PushReceiver) Stop() {
+	us.stopOnce.Do(func() {
+		close(us.stopChan)
+		us.mutex.Lock()
+		defer us.mutex.Unlock()
+		if us.conn != nil {
+			us.conn.Close()
+		}
+	})
+}
+
+// 服务从缓存中移除时清理其推送记录，避免记录随服务变化无限增长
+func (us *PushReceiver) forget(key string) {
+	us.mutex.Lock()
+	delete(us.lastPushes, key)
+	us.mutex.Unlock()
+}
+
+func (us *PushReceiver) stopped() bool {
+	select {
+	case <-us.stopChan:
+		return true
+	default:
+		return false
+	}
+}
+
+func (us *PushReceiver) pushError(data []byte, err error) {
+	logger.Errorf("failed to process push data.err:%s", err.Error())
+	us.hostReactor.serviceProxy.nacosServer.Metrics().IncPushErrors()
+	us.hostReactor.serviceProxy.nacosServer.Metrics().IncPushDropped("invalid")
+	if us.onError != nil {
+		us.onError(data, err)
+	}
 }
 
 func (us *PushReceiver) tryListen() (*net.UDPConn, bool) {
-	addr, err := net.ResolveUDPAddr("udp", us.host+":"+strconv.Itoa(us.port))
+	addr, err := net.ResolveUDPAddr("udp", net.JoinHostPort(us.host, strconv.Itoa(us.port)))
 	if err != nil {
-		log.Printf("[ERROR]: Can't resolve address,err: %s \n", err.Error())
+		logger.Errorf("Can't resolve address,err: %s", err.Error())
 		return nil, false
 	}
 
 	conn, err := net.ListenUDP("udp", addr)
 	if err != nil {
-		log.Printf("Error listening %s:%d,err:%s \n", us.host, us.port, err.Error())
+		logger.Errorf("error listening %s:%d,err:%s", us.host, us.port, err.Error())
 		return nil, false
 	}
 
@@ -51,14 +129,15 @@ func (us *PushReceiver) startServer() {
 	var conn *net.UDPConn
 
 	for i := 0; i < 3; i++ {
-		r := rand.New(rand.NewSource(time.Now().UnixNano()))
-		port := r.Intn(1000) + 54951
-		us.port = port
+		if !us.fixedPort {
+			r := rand.New(rand.NewSource(time.Now().UnixNano()))
+			us.port = r.Intn(1000) + 54951
+		}
 		conn1, ok := us.tryListen()
 
 		if ok {
 			conn = conn1
-			log.Println("[INFO] udp server start, port: " + strconv.Itoa(port))
+			logger.Infof("udp server start, port: %d", us.port)
 			break
 		}
 
@@ -68,33 +147,62 @@ func (us *PushReceiver) startServer() {
 		}
 	}
 
+	us.mutex.Lock()
+	if us.stopped() {
+		us.mutex.Unlock()
+		conn.Close()
+		return
+	}
+	us.conn = conn
+	us.mutex.Unlock()
+
 	defer conn.Close()
-	for {
+	for !us.stopped() {
 		us.handleClient(conn)
 	}
 }
 
 func (us *PushReceiver) handleClient(conn *net.UDPConn) {
-	data := make([]byte, 4024)
+	data := make([]byte, maxPushPacketSize)
 	n, remoteAddr, err := conn.ReadFromUDP(data)
 	if err != nil {
-		log.Printf("[ERROR]:failed to read UDP msg because of %s \n", err.Error())
+		if !us.stopped() {
+			logger.Errorf("failed to read UDP msg because of %s", err.Error())
+		}
 		return
 	}
+	_, span := us.hostReactor.serviceProxy.nacosServer.Tracer().Start(context.Background(), "nacos push",
+		tracing.Attribute{Key: tracing.ATTR_SERVER_ADDRESS, Value: remoteAddr.String()})
+	defer func() {
+		tracing.End(span, err)
+	}()
 
-	s := utils.TryDecompressData(data[:n])
-	log.Println("[INFO] receive push: "+s+" from: ", remoteAddr)
+	s, err := utils.DecompressData(data[:n])
+	if err != nil {
+		us.pushError(data[:n], err)
+		return
+	}
+	logger.Infof("receive push: %s from: %s", s, remoteAddr)
 
 	var pushData PushData
-	err1 := json.Unmarshal([]byte(s), &pushData)
-	if err1 != nil {
-		log.Printf("[ERROR] failed to process push data.err:%s \n", err1.Error())
+	if err = us.hostReactor.serializer.Unmarshal(s, &pushData); err != nil {
+		us.pushError(s, err)
 		return
 	}
+	span.SetAttributes(tracing.Attribute{Key: tracing.ATTR_PUSH_TYPE, Value: pushData.PushType})
+	us.hostReactor.serviceProxy.nacosServer.Metrics().IncPushReceived(pushData.PushType)
 	ack := make(map[string]string)
 
 	if pushData.PushType == "dom" || pushData.PushType == "service" {
-		us.hostReactor.ProcessServiceJson(pushData.Data)
+		serviceName, _ := jsonparser.GetString([]byte(pushData.Data), "name")
+		span.SetAttributes(tracing.Attribute{Key: tracing.ATTR_SERVICE_NAME, Value: serviceName})
+		// 丢弃的推送同样回复ACK，避免服务端继续重发
+		if reason := us.dropReason(pushData); reason != "" {
+			logger.Infof("drop %s push of service:%s, lastRefTime:%d", reason, serviceName, pushData.LastRefTime)
+			us.hostReactor.serviceProxy.nacosServer.Metrics().IncPushDropped(reason)
+		} else {
+			us.hostReactor.ProcessServiceJson(pushData.Data)
+		}
 
 		ack["type"] = "push-ack"
 		ack["lastRefTime"] = strconv.FormatInt(pushData.LastRefTime, 10)
@@ -112,4 +220,38 @@ func (us *PushReceiver) handleClient(conn *net.UDPConn) {
 
 	bs, _ := json.Marshal(ack)
 	conn.WriteToUDP(bs, remoteAddr)
+	us.publishPushReceived(pushData, remoteAddr.String())
+}
+
+// 相同lastRefTime的重发推送为duplicate，服务数据比缓存旧的推送为out_of_order，否则返回空
+func (us *PushReceiver) dropReason(pushData PushData) string {
+	data := []byte(pushData.Data)
+	name, _ := jsonparser.GetString(data, "name")
+	clusters, _ := jsonparser.GetString(data, "clusters")
+	cacheKey := utils.GetServiceCacheKey(name, clusters)
+	us.mutex.Lock()
+	duplicate := us.lastPushes[cacheKey] == pushData.LastRefTime
+	us.lastPushes[cacheKey] = pushData.LastRefTime
+	us.mutex.Unlock()
+	if duplicate {
+		return "duplicate"
+	}
+	refTime, err := jsonparser.GetInt(data, "lastRefTime")
+	if err != nil {
+		return ""
+	}
+	if cached, ok := us.hostReactor.serviceInfoMap.Get(cacheKey); ok && uint64(refTime) < cached.(model.Service).LastRefTime {
+		return "out_of_order"
+	}
+	return ""
+}
+
+func (us *PushReceiver) publishPushReceived(pushData PushData, from string) {
+	proxy := &us.hostReactor.serviceProxy
+	e := event.PushReceivedEvent{Namespace: proxy.clientConfig.NamespaceId, PushType: pushData.PushType, From: from}
+	if pushData.PushType == "dom" || pushData.PushType == "service" {
+		e.ServiceName, _ = jsonparser.GetString([]byte(pushData.Data), "name")
+		e.Clusters, _ = jsonparser.GetString([]byte(pushData.Data), "clusters")
+	}
+	proxy.publishEvent(e)
 }
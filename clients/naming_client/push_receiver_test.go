@@ -0,0 +1,136 @@
+package naming_client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/monitor"
+	"github.com/nacos-group/nacos-sdk-go/common/nacos_server"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestPushReceiver_HandleGzipPush(t *testing.T) {
+	probe, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	assert.Nil(t, err)
+	port := probe.LocalAddr().(*net.UDPAddr).Port
+	probe.Close()
+
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	pushErrors := make(chan error, 1)
+	hr := NewHostReactor(NamingProxy{}, cacheDir, 1, true, NewSubscribeCallback(), false, 0, nil, 0, 0, false,
+		PushReceiverConfig{Ip: "127.0.0.1", Port: port, OnError: func(data []byte, err error) {
+			pushErrors <- err
+		}}, ServiceCacheConfig{}, SerializerConfig{})
+	defer hr.Stop()
+	for i := 0; i < 100 && hr.pushReceiver.Port() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, port, hr.pushReceiver.Port())
+
+	conn, err := net.Dial("udp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	push, _ := json.Marshal(PushData{
+		PushType:    "service",
+		Data:        `{"name":"DEFAULT_GROUP@@DEMO","clusters":"","hosts":[{"ip":"10.0.0.10","port":80}]}`,
+		LastRefTime: 1,
+	})
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	writer.Write(push)
+	writer.Close()
+	_, err = conn.Write(buf.Bytes())
+	assert.Nil(t, err)
+
+	ack := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := conn.Read(ack)
+	assert.Nil(t, err)
+	assert.Contains(t, string(ack[:n]), "push-ack")
+	_, ok := hr.serviceInfoMap.Get("DEFAULT_GROUP@@DEMO")
+	assert.True(t, ok)
+
+	_, err = conn.Write([]byte("not json"))
+	assert.Nil(t, err)
+	select {
+	case err := <-pushErrors:
+		assert.NotNil(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("push error callback not called")
+	}
+}
+
+func sendPushTest(t *testing.T, conn net.Conn, lastRefTime int64, data string) {
+	push, _ := json.Marshal(PushData{PushType: "service", Data: data, LastRefTime: lastRefTime})
+	_, err := conn.Write(push)
+	assert.Nil(t, err)
+	ack := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := conn.Read(ack)
+	assert.Nil(t, err)
+	var ackData map[string]string
+	assert.Nil(t, json.Unmarshal(ack[:n], &ackData))
+	assert.Equal(t, "push-ack", ackData["type"])
+	assert.Equal(t, strconv.FormatInt(lastRefTime, 10), ackData["lastRefTime"])
+}
+
+func TestPushReceiver_DropDuplicateAndStalePush(t *testing.T) {
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	nacosServer, err := nacos_server.NewNacosServer(nil, constant.ClientConfig{CacheOnly: true, EnableMetrics: true}, nil)
+	assert.Nil(t, err)
+	hr := NewHostReactor(NamingProxy{nacosServer: nacosServer}, cacheDir, 1, true, NewSubscribeCallback(), false, 0, nil, 0, 0, false,
+		PushReceiverConfig{Ip: "127.0.0.1"}, ServiceCacheConfig{}, SerializerConfig{})
+	defer hr.Stop()
+	for i := 0; i < 100 && hr.pushReceiver.Port() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	conn, err := net.Dial("udp", net.JoinHostPort("127.0.0.1", strconv.Itoa(hr.pushReceiver.Port())))
+	assert.Nil(t, err)
+	defer conn.Close()
+	waitDropped := func(reason string, expected float64) {
+		for i := 0; i < 100 && monitor.PushDropped.Value(reason) < expected; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		assert.Equal(t, expected, monitor.PushDropped.Value(reason))
+	}
+	waitHost := func(ip string) {
+		var service interface{}
+		for i := 0; i < 100; i++ {
+			if service, _ = hr.serviceInfoMap.Get("DEFAULT_GROUP@@DEMO"); service != nil && service.(model.Service).Hosts[0].Ip == ip {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		assert.Equal(t, ip, service.(model.Service).Hosts[0].Ip)
+	}
+	duplicates := monitor.PushDropped.Value("duplicate")
+	outOfOrders := monitor.PushDropped.Value("out_of_order")
+
+	sendPushTest(t, conn, 1, `{"name":"DEFAULT_GROUP@@DEMO","clusters":"","lastRefTime":100,"hosts":[{"ip":"10.0.0.10","port":80}]}`)
+	waitHost("10.0.0.10")
+	// 服务端未收到ACK重发的推送只回复ACK
+	sendPushTest(t, conn, 1, `{"name":"DEFAULT_GROUP@@DEMO","clusters":"","lastRefTime":100,"hosts":[{"ip":"10.0.0.10","port":80}]}`)
+	waitDropped("duplicate", duplicates+1)
+	// 比缓存旧的推送不更新缓存
+	sendPushTest(t, conn, 2, `{"name":"DEFAULT_GROUP@@DEMO","clusters":"","lastRefTime":50,"hosts":[{"ip":"10.0.0.11","port":80}]}`)
+	waitDropped("out_of_order", outOfOrders+1)
+	sendPushTest(t, conn, 3, `{"name":"DEFAULT_GROUP@@DEMO","clusters":"","lastRefTime":200,"hosts":[{"ip":"10.0.0.12","port":80}]}`)
+	waitHost("10.0.0.12")
+
+	// 服务移除后推送记录一并清理
+	hr.removeService("DEFAULT_GROUP@@DEMO")
+	hr.pushReceiver.mutex.Lock()
+	assert.Equal(t, 0, len(hr.pushReceiver.lastPushes))
+	hr.pushReceiver.mutex.Unlock()
+}
@@ -0,0 +1,180 @@
+package naming_client
+
+import (
+	"context"
+	"github.com/nacos-group/nacos-sdk-go/common/event"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/utils"
+	"sync"
+)
+
+// 记录通过客户端注册的临时实例和订阅的服务，与服务端的连接恢复后重新注册实例并立即刷新订阅的服务，
+// 避免网络中断期间服务端摘除实例和推送目标后，要等到下一次心跳或轮询才能恢复，与Java客户端的NamingGrpcRedoService对应
+// 为nil时记录和停止操作均为空操作
+type RedoService struct {
+	serviceProxy  NamingProxy
+	hostReactor   *HostReactor
+	mutex         sync.Mutex
+	instances     map[string]RedoInstance
+	subscriptions map[string]RedoSubscription
+	redoMutex     sync.Mutex
+	listenerId    int64
+}
+
+// 待重新注册的实例
+type RedoInstance struct {
+	ServiceName string // group@@service
+	GroupName   string
+	Instance    model.Instance
+	// 心跳已暂停，重连后不重新注册
+	Paused bool
+}
+
+// 待重新订阅的服务
+type RedoSubscription struct {
+	ServiceName string // group@@service
+	Clusters    string
+}
+
+func NewRedoService(serviceProxy NamingProxy, hostReactor *HostReactor) *RedoService {
+	r := &RedoService{
+		serviceProxy:  serviceProxy,
+		hostReactor:   hostReactor,
+		instances:     map[string]RedoInstance{},
+		subscriptions: map[string]RedoSubscription{},
+	}
+	r.listenerId = serviceProxy.nacosServer.Events().Subscribe(r.onEvent, event.TYPE_SERVER_RECONNECTED)
+	return r
+}
+
+func (r *RedoService) CacheInstance(serviceName string, groupName string, instance model.Instance) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.instances[buildKey(serviceName, instance.Ip, instance.Port)] = RedoInstance{ServiceName: serviceName, GroupName: groupName, Instance: instance}
+}
+
+// 修改实例后以新的权重、可用状态和元数据重新注册
+func (r *RedoService) UpdateInstance(serviceName string, ip string, port uint64, weight float64, enable bool, metadata map[string]string) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	k := buildKey(serviceName, ip, port)
+	if redo, ok := r.instances[k]; ok {
+		redo.Instance.Weight = weight
+		redo.Instance.Enable = enable
+		redo.Instance.Metadata = metadata
+		r.instances[k] = redo
+	}
+}
+
+func (r *RedoService) PauseInstance(serviceName string, ip string, port uint64, paused bool) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	k := buildKey(serviceName, ip, port)
+	if redo, ok := r.instances[k]; ok {
+		redo.Paused = paused
+		r.instances[k] = redo
+	}
+}
+
+func (r *RedoService) RemoveInstance(serviceName string, ip string, port uint64) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.instances, buildKey(serviceName, ip, port))
+}
+
+func (r *RedoService) CacheSubscription(serviceName string, clusters string) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.subscriptions[utils.GetServiceCacheKey(serviceName, clusters)] = RedoSubscription{ServiceName: serviceName, Clusters: clusters}
+}
+
+// 服务的所有订阅都取消后才移除
+func (r *RedoService) RemoveSubscription(serviceName string, clusters string) {
+	if r == nil {
+		return
+	}
+	key := utils.GetServiceCacheKey(serviceName, clusters)
+	if r.hostReactor.subCallback.subscribed(key) {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.subscriptions, key)
+}
+
+func (r *RedoService) Instances() []RedoInstance {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	instances := make([]RedoInstance, 0, len(r.instances))
+	for _, redo := range r.instances {
+		instances = append(instances, redo)
+	}
+	return instances
+}
+
+func (r *RedoService) Subscriptions() []RedoSubscription {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	subscriptions := make([]RedoSubscription, 0, len(r.subscriptions))
+	for _, redo := range r.subscriptions {
+		subscriptions = append(subscriptions, redo)
+	}
+	return subscriptions
+}
+
+func (r *RedoService) onEvent(e event.Event) {
+	if reconnected, ok := e.(event.ServerReconnectedEvent); ok {
+		logger.Infof("connection to nacos server:%s is re-established, redo instances and subscriptions", reconnected.Address)
+		go r.Redo()
+	}
+}
+
+// 重新注册所有实例并刷新所有订阅的服务，同一时刻只执行一次
+func (r *RedoService) Redo() {
+	r.redoMutex.Lock()
+	defer r.redoMutex.Unlock()
+	for _, redo := range r.Instances() {
+		if redo.Paused {
+			continue
+		}
+		_, err := r.serviceProxy.RegisterInstance(context.Background(), redo.ServiceName, redo.GroupName, redo.Instance)
+		if err != nil {
+			logger.Errorf("redo register instance %s:%d of service:%s failed:%s", redo.Instance.Ip, redo.Instance.Port, redo.ServiceName, err.Error())
+		}
+		r.serviceProxy.publishEvent(event.InstanceReregisteredEvent{
+			Namespace:   r.serviceProxy.clientConfig.NamespaceId,
+			ServiceName: redo.ServiceName,
+			Ip:          redo.Instance.Ip,
+			Port:        redo.Instance.Port,
+			Err:         err,
+		})
+	}
+	for _, redo := range r.Subscriptions() {
+		if err := r.hostReactor.updateServiceNow(context.Background(), redo.ServiceName, redo.Clusters); err != nil {
+			logger.Errorf("redo subscribe service:%s clusters:%s failed:%s", redo.ServiceName, redo.Clusters, err.Error())
+		}
+	}
+}
+
+func (r *RedoService) Stop() {
+	if r == nil {
+		return
+	}
+	r.serviceProxy.nacosServer.Events().Unsubscribe(r.listenerId)
+}
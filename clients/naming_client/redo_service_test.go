@@ -0,0 +1,116 @@
+package naming_client
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/nacos-group/nacos-sdk-go/clients/nacos_client"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/event"
+	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/mock"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNamingClient_RedoAfterReconnect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	registered := make(chan map[string]string, 2)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
+		DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			registered <- params
+			return http_agent.FakeHttpResponse(200, `ok`), nil
+		})
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPut),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/beat"),
+		gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		Return(http_agent.FakeHttpResponse(200, `{"clientBeatInterval":5000}`), nil)
+	// 前3次查询失败使连接断开，之后恢复
+	var queries int32
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/list"),
+		gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			if atomic.AddInt32(&queries, 1) <= 3 {
+				return nil, errors.New("connection refused")
+			}
+			return http_agent.FakeHttpResponse(200, `{"name":"DEFAULT_GROUP@@DEMO","hosts":[]}`), nil
+		})
+
+	nc := nacos_client.NacosClient{}
+	nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	clientConfig := clientConfigTest
+	clientConfig.ListenInterval = 30 * 1000
+	nc.SetClientConfig(clientConfig)
+	nc.SetHttpAgent(mockIHttpAgent)
+	client, err := NewNamingClient(&nc)
+	assert.Nil(t, err)
+	defer client.Close()
+	reconnected := make(chan event.Event, 1)
+	client.SubscribeEvent(func(e event.Event) { reconnected <- e }, event.TYPE_SERVER_RECONNECTED)
+
+	_, err = client.RegisterInstance(vo.RegisterInstanceParam{ServiceName: "DEMO", Ip: "10.0.0.10", Port: 80, Weight: 1, Enable: true, Ephemeral: true})
+	assert.Nil(t, err)
+	<-registered
+	assert.Equal(t, 1, len(client.redoService.Instances()))
+	client.GetService(vo.GetServiceParam{ServiceName: "DEMO"})
+	assert.Equal(t, int32(3), atomic.LoadInt32(&queries))
+	client.GetService(vo.GetServiceParam{ServiceName: "DEMO"})
+	select {
+	case e := <-reconnected:
+		assert.Equal(t, "console.nacos.io:80", e.(event.ServerReconnectedEvent).Address)
+	case <-time.After(3 * time.Second):
+		t.Fatal("reconnected event was not published")
+	}
+	select {
+	case params := <-registered:
+		assert.Equal(t, "DEFAULT_GROUP@@DEMO", params["serviceName"])
+		assert.Equal(t, "10.0.0.10", params["ip"])
+		assert.Equal(t, "true", params["ephemeral"])
+	case <-time.After(3 * time.Second):
+		t.Fatal("instance was not registered again after reconnect")
+	}
+}
+
+func TestRedoService_Instances(t *testing.T) {
+	proxy, err := NewNamingProxy(clientConfigTest, []constant.ServerConfig{serverConfigTest}, nil)
+	assert.Nil(t, err)
+	r := NewRedoService(proxy, &HostReactor{serviceProxy: proxy})
+	defer r.Stop()
+	r.CacheInstance("DEFAULT_GROUP@@DEMO", "DEFAULT_GROUP", model.Instance{Ip: "10.0.0.10", Port: 80, Weight: 1, Enable: true, Ephemeral: true})
+	r.UpdateInstance("DEFAULT_GROUP@@DEMO", "10.0.0.10", 80, 2, false, map[string]string{"a": "b"})
+	// 未记录的实例不会被加入
+	r.UpdateInstance("DEFAULT_GROUP@@DEMO", "10.0.0.11", 80, 2, false, nil)
+	assert.Equal(t, []RedoInstance{{ServiceName: "DEFAULT_GROUP@@DEMO", GroupName: "DEFAULT_GROUP",
+		Instance: model.Instance{Ip: "10.0.0.10", Port: 80, Weight: 2, Enable: false, Ephemeral: true, Metadata: map[string]string{"a": "b"}}}}, r.Instances())
+	r.RemoveInstance("DEFAULT_GROUP@@DEMO", "10.0.0.10", 80)
+	assert.Equal(t, 0, len(r.Instances()))
+}
+
+func TestRedoService_Subscriptions(t *testing.T) {
+	proxy, err := NewNamingProxy(clientConfigTest, []constant.ServerConfig{serverConfigTest}, nil)
+	assert.Nil(t, err)
+	subCallback := NewSubscribeCallback()
+	r := NewRedoService(proxy, &HostReactor{serviceProxy: proxy, subCallback: subCallback})
+	defer r.Stop()
+	callback := func(services []model.SubscribeService, err error) {}
+	subCallback.AddCallbackFuncs("DEFAULT_GROUP@@DEMO", "a", &callback)
+	r.CacheSubscription("DEFAULT_GROUP@@DEMO", "a")
+	assert.Equal(t, []RedoSubscription{{ServiceName: "DEFAULT_GROUP@@DEMO", Clusters: "a"}}, r.Subscriptions())
+
+	// 仍有订阅时保留
+	r.RemoveSubscription("DEFAULT_GROUP@@DEMO", "a")
+	assert.Equal(t, 1, len(r.Subscriptions()))
+	subCallback.RemoveCallbackFuncs("DEFAULT_GROUP@@DEMO", "a", &callback)
+	r.RemoveSubscription("DEFAULT_GROUP@@DEMO", "a")
+	assert.Equal(t, 0, len(r.Subscriptions()))
+}
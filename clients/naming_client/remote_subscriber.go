@@ -0,0 +1,146 @@
+package naming_client
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/common/remote"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/utils"
+	"strings"
+)
+
+// 配置了ClientConfig.Transport时，订阅的服务通过长连接向服务端订阅，服务的变化由服务端通过长连接推送，
+// 通过长连接订阅的服务不再后台轮询；长连接断开期间仍使用UDP推送和后台轮询，重连后重新订阅
+
+// 注册长连接推送的处理函数
+func (hr *HostReactor) startRemoteSubscriber(transport remote.Transport) {
+	transport.RegisterHandler(remote.TYPE_NOTIFY_SUBSCRIBER, hr.handleNotifySubscriber)
+	transport.OnReconnected(hr.resubscribeRemote)
+}
+
+func (hr *HostReactor) remoteTransport() remote.Transport {
+	if transport := hr.serviceProxy.nacosServer.Transport(); transport != nil && transport.Connected() {
+		return transport
+	}
+	return nil
+}
+
+// 服务已通过长连接订阅且连接正常时，由服务端推送变化，不需要后台轮询
+func (hr *HostReactor) pushedByRemote(key string) bool {
+	return hr.remoteSubscribed.Has(key) && hr.remoteTransport() != nil
+}
+
+// 通过长连接订阅服务，订阅返回的服务写入缓存，长连接不可用或订阅失败时使用HTTP查询和后台轮询
+func (hr *HostReactor) subscribeRemote(key string) {
+	transport := hr.remoteTransport()
+	if transport == nil {
+		return
+	}
+	groupName, serviceName, clusters := parseServiceKey(key)
+	var response remote.SubscribeServiceResponse
+	err := transport.Request(context.Background(), remote.TYPE_SUBSCRIBE_SERVICE, remote.SubscribeServiceRequest{
+		Namespace:   hr.serviceProxy.clientConfig.NamespaceId,
+		ServiceName: serviceName,
+		GroupName:   groupName,
+		Clusters:    clusters,
+		Subscribe:   true,
+	}, &response)
+	if err != nil {
+		logger.Warnf("subscribe service:%s with long connection failed, fall back to http,err:%s", key, err.Error())
+		return
+	}
+	hr.remoteSubscribed.Set(key, true)
+	hr.processRemoteService(response.ServiceInfo.Service())
+}
+
+// 向服务端取消长连接上的订阅，服务未通过长连接订阅时不发送请求
+func (hr *HostReactor) unsubscribeRemote(key string) {
+	if !hr.remoteSubscribed.Has(key) {
+		return
+	}
+	hr.remoteSubscribed.Remove(key)
+	transport := hr.remoteTransport()
+	if transport == nil {
+		return
+	}
+	groupName, serviceName, clusters := parseServiceKey(key)
+	var response remote.SubscribeServiceResponse
+	err := transport.Request(context.Background(), remote.TYPE_SUBSCRIBE_SERVICE, remote.SubscribeServiceRequest{
+		Namespace:   hr.serviceProxy.clientConfig.NamespaceId,
+		ServiceName: serviceName,
+		GroupName:   groupName,
+		Clusters:    clusters,
+		Subscribe:   false,
+	}, &response)
+	if err != nil {
+		logger.Warnf("unsubscribe service:%s with long connection failed,err:%s", key, err.Error())
+	}
+}
+
+// 服务端不保留断开的连接上的订阅，重连后重新订阅之前订阅的服务和有订阅回调的服务
+func (hr *HostReactor) resubscribeRemote() {
+	keys := append(hr.remoteSubscribed.Keys(), hr.subCallback.subscribedKeys()...)
+	for _, key := range keys {
+		hr.remoteSubscribed.Remove(key)
+	}
+	for _, key := range keys {
+		if !hr.remoteSubscribed.Has(key) {
+			hr.subscribeRemote(key)
+		}
+	}
+}
+
+// 处理服务端推送的服务变化，只更新仍在缓存中的服务，忽略比缓存更旧的推送
+func (hr *HostReactor) handleNotifySubscriber(body []byte) {
+	var request remote.NotifySubscriberRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		logger.Errorf("failed to unmarshal notify subscriber request:%s,err:%s", string(body), err.Error())
+		hr.serviceProxy.nacosServer.Metrics().IncPushErrors()
+		return
+	}
+	if namespaceOrDefault(request.Namespace) != namespaceOrDefault(hr.serviceProxy.clientConfig.NamespaceId) {
+		return
+	}
+	service := request.ServiceInfo.Service()
+	key := utils.GetServiceCacheKey(service.Name, service.Clusters)
+	hr.serviceProxy.nacosServer.Metrics().IncPushReceived(remote.TYPE_NOTIFY_SUBSCRIBER)
+	cached, ok := hr.serviceInfoMap.Get(key)
+	if !ok {
+		return
+	}
+	if cached.(model.Service).LastRefTime > service.LastRefTime {
+		logger.Infof("ignore stale push of service:%s, lastRefTime:%d", key, service.LastRefTime)
+		return
+	}
+	hr.processRemoteService(service)
+}
+
+// 与ProcessServiceJson一致，未开启UpdateCacheWhenEmpty时忽略空的实例列表
+func (hr *HostReactor) processRemoteService(service model.Service) {
+	if len(service.Hosts) == 0 && !hr.updateCacheWhenEmpty {
+		logger.Warnf("instance list of service:%s is empty, ignore it", service.Name)
+		return
+	}
+	hr.processService(service)
+}
+
+// 缓存key为group@@service[@@clusters]
+func parseServiceKey(key string) (groupName string, serviceName string, clusters string) {
+	parts := strings.SplitN(key, constant.SERVICE_INFO_SPLITER, 3)
+	if len(parts) == 1 {
+		return constant.DEFAULT_GROUP, parts[0], ""
+	}
+	if len(parts) == 3 {
+		clusters = parts[2]
+	}
+	return parts[0], parts[1], clusters
+}
+
+func namespaceOrDefault(namespace string) string {
+	if namespace == "" {
+		return constant.DEFAULT_NAMESPACE_ID
+	}
+	return namespace
+}
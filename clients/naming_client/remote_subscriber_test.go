@@ -0,0 +1,147 @@
+package naming_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/nacos-group/nacos-sdk-go/clients/nacos_client"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/common/remote"
+	"github.com/nacos-group/nacos-sdk-go/mock"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+// 创建通过transport订阅服务的客户端，HTTP请求均由mockIHttpAgent处理
+func createRemoteNamingClientTest(t *testing.T, mockIHttpAgent http_agent.IHttpAgent, transport *remote.FakeTransport) NamingClient {
+	nc := nacos_client.NacosClient{}
+	nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	clientConfig := clientConfigTest
+	clientConfig.ListenInterval = 30 * 1000
+	clientConfig.Transport = transport.Factory()
+	assert.Nil(t, nc.SetClientConfig(clientConfig))
+	nc.SetHttpAgent(mockIHttpAgent)
+	client, err := NewNamingClient(&nc)
+	assert.Nil(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// 按请求中的服务返回只有一个实例的服务
+func subscribeResponseTest(ip string, lastRefTime uint64) func(requestType string, body []byte) (interface{}, error) {
+	return func(requestType string, body []byte) (interface{}, error) {
+		var request remote.SubscribeServiceRequest
+		json.Unmarshal(body, &request)
+		return remote.SubscribeServiceResponse{
+			Response: remote.Response{ResultCode: remote.Result_Success},
+			ServiceInfo: remote.ServiceInfo{Name: request.ServiceName, GroupName: request.GroupName, Clusters: request.Clusters,
+				LastRefTime: lastRefTime, Hosts: []model.Instance{{Ip: ip, Port: 80, Weight: 1, Enable: true, Healthy: true}}},
+		}, nil
+	}
+}
+
+func subscribeRequestsTest(transport *remote.FakeTransport) []remote.SubscribeServiceRequest {
+	var requests []remote.SubscribeServiceRequest
+	for _, body := range transport.Requests(remote.TYPE_SUBSCRIBE_SERVICE) {
+		var request remote.SubscribeServiceRequest
+		json.Unmarshal(body, &request)
+		requests = append(requests, request)
+	}
+	return requests
+}
+
+func TestNamingClient_SubscribeWithTransport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	// 通过长连接订阅时不通过HTTP查询服务
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	transport := remote.NewFakeTransport()
+	transport.OnRequest = subscribeResponseTest("10.0.0.1", 100)
+	client := createRemoteNamingClientTest(t, mockIHttpAgent, transport)
+
+	var received [][]model.SubscribeService
+	err := client.Subscribe(&vo.SubscribeParam{ServiceName: "DEMO", SubscribeCallback: func(services []model.SubscribeService, err error) {
+		received = append(received, services)
+	}})
+	assert.Nil(t, err)
+	requests := subscribeRequestsTest(transport)
+	assert.Equal(t, 1, len(requests))
+	assert.Equal(t, remote.SubscribeServiceRequest{ServiceName: "DEMO", GroupName: constant.DEFAULT_GROUP, Subscribe: true}, requests[0])
+	assert.Equal(t, 1, len(received))
+	assert.Equal(t, "10.0.0.1", received[0][0].Ip)
+	assert.True(t, client.hostReactor.pushedByRemote("DEFAULT_GROUP@@DEMO"))
+
+	push := func(namespace string, ip string, lastRefTime uint64) {
+		assert.Nil(t, transport.Push(remote.TYPE_NOTIFY_SUBSCRIBER, remote.NotifySubscriberRequest{Namespace: namespace, ServiceName: "DEMO", GroupName: constant.DEFAULT_GROUP,
+			ServiceInfo: remote.ServiceInfo{Name: "DEMO", GroupName: constant.DEFAULT_GROUP, LastRefTime: lastRefTime,
+				Hosts: []model.Instance{{Ip: ip, Port: 80, Weight: 1, Enable: true, Healthy: true}}}}))
+	}
+	push(constant.DEFAULT_NAMESPACE_ID, "10.0.0.2", 200)
+	assert.Equal(t, 2, len(received))
+	assert.Equal(t, "10.0.0.2", received[1][0].Ip)
+	// 比缓存更旧的推送和其他命名空间的推送被忽略
+	push(constant.DEFAULT_NAMESPACE_ID, "10.0.0.3", 150)
+	push("dev", "10.0.0.4", 300)
+	assert.Equal(t, 2, len(received))
+	service, err := client.GetService(vo.GetServiceParam{ServiceName: "DEMO"})
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.0.2", service.Hosts[0].Ip)
+}
+
+func TestNamingClient_ResubscribeAfterReconnect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	transport := remote.NewFakeTransport()
+	transport.OnRequest = subscribeResponseTest("10.0.0.1", 100)
+	client := createRemoteNamingClientTest(t, mockIHttpAgent, transport)
+	assert.Nil(t, client.Subscribe(&vo.SubscribeParam{ServiceName: "DEMO", SubscribeCallback: func(services []model.SubscribeService, err error) {}}))
+
+	// 断开期间由后台轮询刷新
+	transport.Disconnect()
+	assert.False(t, client.hostReactor.pushedByRemote("DEFAULT_GROUP@@DEMO"))
+	transport.OnRequest = subscribeResponseTest("10.0.0.5", 200)
+	transport.Reconnect()
+	assert.Equal(t, 2, len(subscribeRequestsTest(transport)))
+	assert.True(t, client.hostReactor.pushedByRemote("DEFAULT_GROUP@@DEMO"))
+	service, err := client.GetService(vo.GetServiceParam{ServiceName: "DEMO"})
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.0.5", service.Hosts[0].Ip)
+
+	// 移除服务时取消长连接上的订阅
+	assert.True(t, client.PurgeServiceCache(vo.PurgeServiceCacheParam{ServiceName: "DEMO"}))
+	requests := subscribeRequestsTest(transport)
+	assert.Equal(t, 3, len(requests))
+	assert.False(t, requests[2].Subscribe)
+	assert.Equal(t, "DEMO", requests[2].ServiceName)
+	assert.False(t, client.hostReactor.pushedByRemote("DEFAULT_GROUP@@DEMO"))
+}
+
+func TestNamingClient_SubscribeFallBackToHttp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/list"),
+		gomock.Any(), gomock.Any(), gomock.Any()).MinTimes(1).
+		DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			return http_agent.FakeHttpResponse(200, `{"name":"DEFAULT_GROUP@@DEMO","cacheMillis":10000,"hosts":[{"ip":"10.0.0.1","port":80,"weight":1,"enabled":true,"healthy":true}]}`), nil
+		})
+	// 服务端为1.x版本，无法建立长连接
+	transport := remote.NewFakeTransport()
+	transport.StartErr = errors.New("connection refused")
+	client := createRemoteNamingClientTest(t, mockIHttpAgent, transport)
+	assert.Nil(t, client.serviceProxy.nacosServer.Transport())
+	assert.True(t, transport.Closed())
+
+	assert.Nil(t, client.Subscribe(&vo.SubscribeParam{ServiceName: "DEMO", SubscribeCallback: func(services []model.SubscribeService, err error) {}}))
+	assert.Equal(t, 0, len(transport.Requests(remote.TYPE_SUBSCRIBE_SERVICE)))
+	service, err := client.GetService(vo.GetServiceParam{ServiceName: "DEMO"})
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.0.1", service.Hosts[0].Ip)
+}
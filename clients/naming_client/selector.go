@@ -0,0 +1,108 @@
+package naming_client
+
+import (
+	"errors"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"strings"
+)
+
+// 按实例元数据过滤实例的标签选择器
+// 表达式为以&连接的key=value或key!=value，key可带CONSUMER.label.、PROVIDER.label.等前缀，如CONSUMER.label.env=prod
+type labelSelector []labelRequirement
+
+type labelRequirement struct {
+	key      string
+	value    string
+	notEqual bool
+}
+
+func parseSelector(expression string) (labelSelector, error) {
+	var selector labelSelector
+	for _, clause := range strings.Split(expression, "&") {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			continue
+		}
+		requirement := labelRequirement{}
+		index := strings.Index(clause, "!=")
+		if index >= 0 {
+			requirement.notEqual = true
+			requirement.value = strings.TrimSpace(clause[index+2:])
+		} else if index = strings.Index(clause, "="); index >= 0 {
+			requirement.value = strings.TrimSpace(clause[index+1:])
+		} else {
+			return nil, errors.New("[client.Selector] invalid selector expression:" + expression)
+		}
+		requirement.key = strings.TrimSpace(clause[:index])
+		if i := strings.Index(requirement.key, ".label."); i >= 0 {
+			requirement.key = requirement.key[i+len(".label."):]
+		} else {
+			requirement.key = strings.TrimPrefix(requirement.key, "label.")
+		}
+		if requirement.key == "" {
+			return nil, errors.New("[client.Selector] invalid selector expression:" + expression)
+		}
+		selector = append(selector, requirement)
+	}
+	return selector, nil
+}
+
+func (s labelSelector) matches(metadata map[string]string) bool {
+	for _, requirement := range s {
+		value, ok := metadata[requirement.key]
+		if requirement.notEqual == (ok && value == requirement.value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s labelSelector) filterInstances(instances []model.Instance) []model.Instance {
+	if len(s) == 0 || instances == nil {
+		return instances
+	}
+	result := make([]model.Instance, 0, len(instances))
+	for _, instance := range instances {
+		if s.matches(instance.Metadata) {
+			result = append(result, instance)
+		}
+	}
+	return result
+}
+
+func (s labelSelector) filterSubscribeServices(services []model.SubscribeService) []model.SubscribeService {
+	if len(s) == 0 || services == nil {
+		return services
+	}
+	result := make([]model.SubscribeService, 0, len(services))
+	for _, service := range services {
+		if s.matches(service.Metadata) {
+			result = append(result, service)
+		}
+	}
+	return result
+}
+
+// 过滤selector后event为空时返回false
+func (s labelSelector) filterChangeEvent(event model.InstanceChangeEvent) (model.InstanceChangeEvent, bool) {
+	if len(s) == 0 {
+		return event, !event.IsEmpty()
+	}
+	event.Added = s.filterInstances(event.Added)
+	event.Removed = s.filterInstances(event.Removed)
+	event.Modified = s.filterInstances(event.Modified)
+	return event, !event.IsEmpty()
+}
+
+// 返回按selector过滤Hosts后的服务，selector为空时原样返回
+func filterService(service model.Service, expression string) (model.Service, error) {
+	if expression == "" {
+		return service, nil
+	}
+	selector, err := parseSelector(expression)
+	if err != nil {
+		return service, err
+	}
+	service.Hosts = selector.filterInstances(service.Hosts)
+	return service, nil
+}
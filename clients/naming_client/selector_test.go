@@ -0,0 +1,49 @@
+package naming_client
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseSelector(t *testing.T) {
+	selector, err := parseSelector("CONSUMER.label.env=prod & label.zone!=cn-hangzhou-a&version=1.0")
+	assert.Nil(t, err)
+	assert.Equal(t, labelSelector{
+		{key: "env", value: "prod"},
+		{key: "zone", value: "cn-hangzhou-a", notEqual: true},
+		{key: "version", value: "1.0"},
+	}, selector)
+
+	_, err = parseSelector("env")
+	assert.NotNil(t, err)
+	_, err = parseSelector("=prod")
+	assert.NotNil(t, err)
+}
+
+func TestLabelSelector_Filter(t *testing.T) {
+	selector, err := parseSelector("CONSUMER.label.env=prod&zone!=a")
+	assert.Nil(t, err)
+	instances := []model.Instance{
+		{InstanceId: "1", Metadata: map[string]string{"env": "prod", "zone": "b"}},
+		{InstanceId: "2", Metadata: map[string]string{"env": "prod", "zone": "a"}},
+		{InstanceId: "3", Metadata: map[string]string{"env": "test"}},
+		{InstanceId: "4", Metadata: map[string]string{"env": "prod"}},
+		{InstanceId: "5"},
+	}
+	result := selector.filterInstances(instances)
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, "1", result[0].InstanceId)
+	assert.Equal(t, "4", result[1].InstanceId)
+
+	event, ok := selector.filterChangeEvent(model.InstanceChangeEvent{Removed: instances[2:3]})
+	assert.False(t, ok)
+	event, ok = selector.filterChangeEvent(model.InstanceChangeEvent{Added: instances})
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(event.Added))
+
+	service, err := filterService(model.Service{Hosts: instances}, "env=test")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(service.Hosts))
+	assert.Equal(t, "3", service.Hosts[0].InstanceId)
+}
@@ -0,0 +1,103 @@
+package naming_client
+
+import (
+	"context"
+	"errors"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"path"
+	"sort"
+	"time"
+)
+
+// 监听服务名列表时默认的拉取间隔
+const Default_Watch_Services_Interval = 30 * time.Second
+
+// 定时拉取分组下的服务名列表，服务新增或删除时回调，用于网关等自动发现新服务
+func (sc *NamingClient) WatchAllServices(param vo.WatchServicesParam) error {
+	return sc.WatchAllServicesWithContext(context.Background(), param)
+}
+
+// ctx结束后停止监听，首次拉取失败时返回错误
+func (sc *NamingClient) WatchAllServicesWithContext(ctx context.Context, param vo.WatchServicesParam) error {
+	if sc.hostReactor.cacheOnly {
+		return ErrCacheOnlyMode
+	}
+	if param.Callback == nil {
+		return errors.New("[client.WatchAllServices] callback can not be nil")
+	}
+	if param.Pattern != "" {
+		if _, err := path.Match(param.Pattern, ""); err != nil {
+			return errors.New("[client.WatchAllServices] invalid pattern:" + param.Pattern)
+		}
+	}
+	if param.GroupName == "" {
+		param.GroupName = constant.DEFAULT_GROUP
+	}
+	interval := param.Interval
+	if interval <= 0 {
+		interval = Default_Watch_Services_Interval
+	}
+	w := &serviceWatcher{client: sc, param: param, services: map[string]bool{}}
+	if err := w.refresh(ctx); err != nil {
+		return err
+	}
+	go w.run(ctx, interval)
+	return nil
+}
+
+// 一次服务名列表监听，services为上次拉取到的服务名
+type serviceWatcher struct {
+	client   *NamingClient
+	param    vo.WatchServicesParam
+	services map[string]bool
+}
+
+// 拉取服务名列表并回调与上次的差异，拉取失败时保留上次的列表
+func (w *serviceWatcher) refresh(ctx context.Context) error {
+	names, err := w.client.searchServiceNames(ctx, w.param.NameSpace, w.param.GroupName, w.param.Pattern, nil)
+	if err != nil {
+		return err
+	}
+	event := model.ServiceListChangeEvent{NameSpace: w.param.NameSpace, GroupName: w.param.GroupName}
+	current := make(map[string]bool, len(names))
+	for _, name := range names {
+		current[name] = true
+		if !w.services[name] {
+			event.Added = append(event.Added, name)
+		}
+	}
+	for name := range w.services {
+		if !current[name] {
+			event.Removed = append(event.Removed, name)
+		}
+	}
+	w.services = current
+	if len(event.Added) == 0 && len(event.Removed) == 0 {
+		return nil
+	}
+	sort.Strings(event.Added)
+	sort.Strings(event.Removed)
+	logger.Infof("[client.WatchAllServices] services changed, group:%s added:%v removed:%v", w.param.GroupName, event.Added, event.Removed)
+	w.param.Callback(event)
+	return nil
+}
+
+func (w *serviceWatcher) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.refresh(ctx); err != nil {
+				logger.Warnf("[client.WatchAllServices] refresh services failed, group:%s err:%s", w.param.GroupName, err.Error())
+			}
+		case <-ctx.Done():
+			return
+		case <-w.client.hostReactor.stopChan:
+			return
+		}
+	}
+}
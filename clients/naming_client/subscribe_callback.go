@@ -3,24 +3,32 @@ package naming_client
 import (
 	"errors"
 	"github.com/nacos-group/nacos-sdk-go/clients/cache"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/common/monitor"
 	"github.com/nacos-group/nacos-sdk-go/model"
 	"github.com/nacos-group/nacos-sdk-go/utils"
-	"log"
+	"sort"
 )
 
 type SubscribeCallback struct {
 	callbackFuncsMap cache.ConcurrentMap
+	changeFuncsMap   cache.ConcurrentMap
+	// 为nil时回调同步执行
+	executor callbackExecutor
+	metrics  *monitor.Recorder
 }
 
 func NewSubscribeCallback() SubscribeCallback {
 	ed := SubscribeCallback{}
 	ed.callbackFuncsMap = cache.NewConcurrentMap()
+	ed.changeFuncsMap = cache.NewConcurrentMap()
 	return ed
 }
 
 func (ed *SubscribeCallback) AddCallbackFuncs(serviceName string, clusters string, callbackFunc *func(services []model.SubscribeService, err error)) {
-	log.Printf("[INFO] adding " + serviceName + " with " + clusters + " to listener map")
+	logger.Infof("adding %s with %s to listener map", serviceName, clusters)
 	key := utils.GetServiceCacheKey(serviceName, clusters)
+	subscribed := ed.subscribed(key)
 	var funcs []*func(services []model.SubscribeService, err error)
 	old, ok := ed.callbackFuncsMap.Get(key)
 	if ok {
@@ -28,11 +36,15 @@ func (ed *SubscribeCallback) AddCallbackFuncs(serviceName string, clusters strin
 	}
 	funcs = append(funcs, callbackFunc)
 	ed.callbackFuncsMap.Set(key, funcs)
+	if !subscribed {
+		ed.metrics.AddSubscribedServices(1)
+	}
 }
 
 func (ed *SubscribeCallback) RemoveCallbackFuncs(serviceName string, clusters string, callbackFunc *func(services []model.SubscribeService, err error)) {
-	log.Printf("[INFO] removing " + serviceName + " with " + clusters + " to listener map")
+	logger.Infof("removing %s with %s to listener map", serviceName, clusters)
 	key := utils.GetServiceCacheKey(serviceName, clusters)
+	subscribed := ed.subscribed(key)
 	funcs, ok := ed.callbackFuncsMap.Get(key)
 	if ok && funcs != nil {
 		var newFuncs []*func(services []model.SubscribeService, err error)
@@ -43,7 +55,126 @@ func (ed *SubscribeCallback) RemoveCallbackFuncs(serviceName string, clusters st
 		}
 		ed.callbackFuncsMap.Set(key, newFuncs)
 	}
+	if subscribed && !ed.subscribed(key) {
+		ed.metrics.AddSubscribedServices(-1)
+	}
+}
+
+func (ed *SubscribeCallback) AddChangeFuncs(serviceName string, clusters string, changeFunc *func(event model.InstanceChangeEvent)) {
+	logger.Infof("adding %s with %s to change listener map", serviceName, clusters)
+	key := utils.GetServiceCacheKey(serviceName, clusters)
+	subscribed := ed.subscribed(key)
+	var funcs []*func(event model.InstanceChangeEvent)
+	old, ok := ed.changeFuncsMap.Get(key)
+	if ok {
+		funcs = append(funcs, old.([]*func(event model.InstanceChangeEvent))...)
+	}
+	funcs = append(funcs, changeFunc)
+	ed.changeFuncsMap.Set(key, funcs)
+	if !subscribed {
+		ed.metrics.AddSubscribedServices(1)
+	}
+}
+
+func (ed *SubscribeCallback) RemoveChangeFuncs(serviceName string, clusters string, changeFunc *func(event model.InstanceChangeEvent)) {
+	logger.Infof("removing %s with %s to change listener map", serviceName, clusters)
+	key := utils.GetServiceCacheKey(serviceName, clusters)
+	subscribed := ed.subscribed(key)
+	funcs, ok := ed.changeFuncsMap.Get(key)
+	if ok && funcs != nil {
+		var newFuncs []*func(event model.InstanceChangeEvent)
+		for _, funcItem := range funcs.([]*func(event model.InstanceChangeEvent)) {
+			if funcItem != changeFunc {
+				newFuncs = append(newFuncs, funcItem)
+			}
+		}
+		ed.changeFuncsMap.Set(key, newFuncs)
+	}
+	if subscribed && !ed.subscribed(key) {
+		ed.metrics.AddSubscribedServices(-1)
+	}
+}
+
+// 移除服务的所有回调
+func (ed *SubscribeCallback) removeAll(key string) {
+	subscribed := ed.subscribed(key)
+	ed.callbackFuncsMap.Remove(key)
+	ed.changeFuncsMap.Remove(key)
+	if subscribed {
+		ed.metrics.AddSubscribedServices(-1)
+	}
+}
+
+// 服务的回调数，包括SubscribeCallback和ChangeCallback
+func (ed *SubscribeCallback) subscribers(key string) int {
+	count := 0
+	if funcs, ok := ed.callbackFuncsMap.Get(key); ok {
+		count += len(funcs.([]*func(services []model.SubscribeService, err error)))
+	}
+	if funcs, ok := ed.changeFuncsMap.Get(key); ok {
+		count += len(funcs.([]*func(event model.InstanceChangeEvent)))
+	}
+	return count
+}
+
+// 返回有回调的服务缓存key
+func (ed *SubscribeCallback) subscribedKeys() []string {
+	var keys []string
+	for _, key := range append(ed.callbackFuncsMap.Keys(), ed.changeFuncsMap.Keys()...) {
+		if ed.subscribed(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	var result []string
+	for i, key := range keys {
+		if i == 0 || key != keys[i-1] {
+			result = append(result, key)
+		}
+	}
+	return result
+}
 
+// 服务是否还有任意一种回调
+func (ed *SubscribeCallback) subscribed(key string) bool {
+	if funcs, ok := ed.callbackFuncsMap.Get(key); ok && len(funcs.([]*func(services []model.SubscribeService, err error))) > 0 {
+		return true
+	}
+	if funcs, ok := ed.changeFuncsMap.Get(key); ok && len(funcs.([]*func(event model.InstanceChangeEvent))) > 0 {
+		return true
+	}
+	return false
+}
+
+func (ed *SubscribeCallback) execute(task *callbackTask) {
+	if ed.executor == nil {
+		task.run()
+		return
+	}
+	ed.executor.execute(task)
+}
+
+// 停止异步执行回调，尚未执行的回调被丢弃
+func (ed *SubscribeCallback) stop() {
+	if ed.executor != nil {
+		ed.executor.stop()
+	}
+}
+
+// 实例列表有变化时通知ChangeCallback
+func (ed *SubscribeCallback) InstancesChanged(event model.InstanceChangeEvent) {
+	if event.ServiceName == "" || event.IsEmpty() {
+		return
+	}
+	key := utils.GetServiceCacheKey(event.ServiceName, event.Clusters)
+	funcs, ok := ed.changeFuncsMap.Get(key)
+	if ok {
+		ed.execute(&callbackTask{key: key, run: func() {
+			for _, funcItem := range funcs.([]*func(event model.InstanceChangeEvent)) {
+				(*funcItem)(event)
+			}
+		}})
+	}
 }
 
 func (ed *SubscribeCallback) ServiceChanged(service *model.Service) {
@@ -53,26 +184,29 @@ func (ed *SubscribeCallback) ServiceChanged(service *model.Service) {
 	key := utils.GetServiceCacheKey(service.Name, service.Clusters)
 	funcs, ok := ed.callbackFuncsMap.Get(key)
 	if ok {
-		for _, funcItem := range funcs.([]*func(services []model.SubscribeService, err error)) {
-			var subscribeServices []model.SubscribeService
-			if len(service.Hosts) == 0 {
-				(*funcItem)(subscribeServices, errors.New("[client.Subscribe] subscribe failed,hosts is empty"))
-				return
-			}
-			for _, host := range service.Hosts {
-				var subscribeService model.SubscribeService
-				subscribeService.Valid = host.Valid
-				subscribeService.Port = host.Port
-				subscribeService.Ip = host.Ip
-				subscribeService.Metadata = host.Metadata
-				subscribeService.ServiceName = host.ServiceName
-				subscribeService.ClusterName = host.ClusterName
-				subscribeService.Weight = host.Weight
-				subscribeService.InstanceId = host.InstanceId
-				subscribeService.Enable = host.Enable
-				subscribeServices = append(subscribeServices, subscribeService)
+		service := *service
+		ed.execute(&callbackTask{key: key, coalesce: true, run: func() {
+			for _, funcItem := range funcs.([]*func(services []model.SubscribeService, err error)) {
+				var subscribeServices []model.SubscribeService
+				if len(service.Hosts) == 0 {
+					(*funcItem)(subscribeServices, errors.New("[client.Subscribe] subscribe failed,hosts is empty"))
+					return
+				}
+				for _, host := range service.Hosts {
+					var subscribeService model.SubscribeService
+					subscribeService.Valid = host.Valid
+					subscribeService.Port = host.Port
+					subscribeService.Ip = host.Ip
+					subscribeService.Metadata = host.Metadata
+					subscribeService.ServiceName = host.ServiceName
+					subscribeService.ClusterName = host.ClusterName
+					subscribeService.Weight = host.Weight
+					subscribeService.InstanceId = host.InstanceId
+					subscribeService.Enable = host.Enable
+					subscribeServices = append(subscribeServices, subscribeService)
+				}
+				(*funcItem)(subscribeServices, nil)
 			}
-			(*funcItem)(subscribeServices, nil)
-		}
+		}})
 	}
 }
@@ -0,0 +1,106 @@
+package naming_client
+
+import (
+	"context"
+	"github.com/nacos-group/nacos-sdk-go/clients/cache"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/utils"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"sync"
+)
+
+// 保存当前订阅的服务，客户端重启后恢复，见constant.RestoreSubscriptionsConfig
+type subscriptionRestorer struct {
+	mutex  sync.Mutex
+	file   string
+	config constant.RestoreSubscriptionsConfig
+	// 从文件恢复的订阅，key为服务缓存key，用户重新订阅同一服务后移除
+	restored map[string]*restoredSubscription
+}
+
+type restoredSubscription struct {
+	serviceName string
+	clusters    string
+	param       *vo.SubscribeParam
+}
+
+func newSubscriptionRestorer(clientConfig constant.ClientConfig) *subscriptionRestorer {
+	if clientConfig.RestoreSubscriptions == nil {
+		return nil
+	}
+	return &subscriptionRestorer{
+		file:     cache.GetSubscriptionFile(clientConfig.CacheDir, "naming", clientConfig.NamespaceId),
+		config:   *clientConfig.RestoreSubscriptions,
+		restored: map[string]*restoredSubscription{},
+	}
+}
+
+// 重新订阅上次运行时保存的服务，用户已订阅的服务不再重复订阅
+func (sc *NamingClient) restoreSubscriptions() {
+	r := sc.restorer
+	subscriptions, err := cache.ReadSubscriptions(r.file)
+	if err != nil {
+		logger.Errorf("[client.restoreSubscriptions] read subscriptions from %s failed:%s", r.file, err.Error())
+		return
+	}
+	var restored []*restoredSubscription
+	r.mutex.Lock()
+	for _, s := range subscriptions.Services {
+		key := utils.GetServiceCacheKey(s.ServiceName, s.Clusters)
+		if _, ok := r.restored[key]; ok || sc.subCallback.subscribed(key) {
+			continue
+		}
+		subscription := &restoredSubscription{serviceName: s.ServiceName, clusters: s.Clusters, param: &vo.SubscribeParam{
+			ChangeCallback: func(event model.InstanceChangeEvent) {
+				if r.config.OnServiceChange != nil {
+					r.config.OnServiceChange(event)
+				}
+			},
+		}}
+		r.restored[key] = subscription
+		sc.subCallback.AddChangeFuncs(s.ServiceName, s.Clusters, &subscription.param.ChangeCallback)
+		sc.hostReactor.markSubscribed(key)
+		restored = append(restored, subscription)
+	}
+	r.mutex.Unlock()
+
+	for _, subscription := range restored {
+		if _, err := sc.hostReactor.GetServiceInfo(context.Background(), subscription.serviceName, subscription.clusters); err != nil {
+			logger.Warnf("[client.restoreSubscriptions] get service:%s failed:%s", subscription.serviceName, err.Error())
+		}
+	}
+	logger.Infof("[client.restoreSubscriptions] restore %d subscriptions from %s", len(restored), r.file)
+	sc.saveSubscriptions()
+}
+
+// 用户订阅了恢复的服务后移除恢复时注册的回调
+func (sc *NamingClient) releaseRestoredSubscription(key string) {
+	if sc.restorer == nil {
+		return
+	}
+	sc.restorer.mutex.Lock()
+	subscription, ok := sc.restorer.restored[key]
+	delete(sc.restorer.restored, key)
+	sc.restorer.mutex.Unlock()
+	if ok {
+		sc.subCallback.RemoveChangeFuncs(subscription.serviceName, subscription.clusters, &subscription.param.ChangeCallback)
+	}
+}
+
+// 将当前订阅的服务写入文件
+func (sc *NamingClient) saveSubscriptions() {
+	if sc.restorer == nil {
+		return
+	}
+	var subscriptions cache.Subscriptions
+	for _, service := range sc.hostReactor.GetSubscribedServices() {
+		subscriptions.Services = append(subscriptions.Services, cache.ServiceSubscription{ServiceName: service.ServiceName, Clusters: service.Clusters})
+	}
+	sc.restorer.mutex.Lock()
+	defer sc.restorer.mutex.Unlock()
+	if err := cache.WriteSubscriptions(sc.restorer.file, subscriptions); err != nil {
+		logger.Errorf("[client.saveSubscriptions] write subscriptions to %s failed:%s", sc.restorer.file, err.Error())
+	}
+}
@@ -0,0 +1,86 @@
+package naming_client
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/nacos-group/nacos-sdk-go/clients/cache"
+	"github.com/nacos-group/nacos-sdk-go/clients/nacos_client"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/mock"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func createRestoreNamingClientTest(t *testing.T, ctrl *gomock.Controller, cacheDir string, restore *constant.RestoreSubscriptionsConfig) NamingClient {
+	nc := nacos_client.NacosClient{}
+	nc.SetServerConfig(nil)
+	clientConfig := clientConfigTest
+	clientConfig.ListenInterval = 30 * 1000
+	clientConfig.CacheDir = cacheDir
+	clientConfig.CacheOnly = true
+	clientConfig.NotLoadCacheAtStart = true
+	clientConfig.RestoreSubscriptions = restore
+	nc.SetClientConfig(clientConfig)
+	nc.SetHttpAgent(mock.NewMockIHttpAgent(ctrl))
+	client, err := NewNamingClient(&nc)
+	assert.Nil(t, err)
+	return client
+}
+
+func TestNamingClient_RestoreSubscriptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cacheDir, _ := ioutil.TempDir("", "nacos-naming")
+	defer os.RemoveAll(cacheDir)
+	file := cache.GetSubscriptionFile(cacheDir, "naming", "")
+
+	client := createRestoreNamingClientTest(t, ctrl, cacheDir, &constant.RestoreSubscriptionsConfig{})
+	assert.Nil(t, client.UpdateServiceCache(vo.UpdateServiceCacheParam{ServiceName: "DEMO", Clusters: []string{"a"}}))
+	assert.Nil(t, client.Subscribe(&vo.SubscribeParam{
+		ServiceName:    "DEMO",
+		Clusters:       []string{"a"},
+		ChangeCallback: func(event model.InstanceChangeEvent) {},
+	}))
+	subscriptions, err := cache.ReadSubscriptions(file)
+	assert.Nil(t, err)
+	assert.Equal(t, []cache.ServiceSubscription{{ServiceName: "DEFAULT_GROUP@@DEMO", Clusters: "a"}}, subscriptions.Services)
+	client.Close()
+
+	// 重启后自动恢复订阅，变化通过OnServiceChange回调
+	changed := make(chan model.InstanceChangeEvent, 2)
+	client = createRestoreNamingClientTest(t, ctrl, cacheDir, &constant.RestoreSubscriptionsConfig{
+		OnServiceChange: func(event model.InstanceChangeEvent) {
+			changed <- event
+		},
+	})
+	defer client.Close()
+	for i := 0; i < 100 && len(client.GetSubscribedServices()) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, []model.SubscribedService{{ServiceName: "DEFAULT_GROUP@@DEMO", Clusters: "a", Subscribers: 1}}, client.GetSubscribedServices())
+	assert.Nil(t, client.UpdateServiceCache(vo.UpdateServiceCacheParam{ServiceName: "DEMO", Clusters: []string{"a"},
+		Hosts: []model.Instance{{Ip: "10.0.0.10", Port: 80, Weight: 1, Enable: true, Healthy: true}}}))
+	select {
+	case event := <-changed:
+		assert.Equal(t, 1, len(event.Added))
+	case <-time.After(3 * time.Second):
+		t.Fatal("restored subscription was not notified")
+	}
+
+	// 重新订阅后移除恢复的回调，取消订阅后不再保存
+	param := &vo.SubscribeParam{
+		ServiceName:    "DEMO",
+		Clusters:       []string{"a"},
+		ChangeCallback: func(event model.InstanceChangeEvent) {},
+	}
+	assert.Nil(t, client.Subscribe(param))
+	assert.Equal(t, 1, client.GetSubscribedServices()[0].Subscribers)
+	assert.Nil(t, client.Unsubscribe(param))
+	subscriptions, err = cache.ReadSubscriptions(file)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(subscriptions.Services))
+}
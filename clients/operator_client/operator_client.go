@@ -0,0 +1,241 @@
+package operator_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/nacos-group/nacos-sdk-go/clients/nacos_client"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/common/nacos_error"
+	"github.com/nacos-group/nacos-sdk-go/common/nacos_server"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 访问服务端/v1/ns/operator和/v1/console下的运维接口
+type OperatorClient struct {
+	nacos_client.INacosClient
+	nacosServer nacos_server.NacosServer
+	closeOnce   *sync.Once
+}
+
+func NewOperatorClient(nc nacos_client.INacosClient) (OperatorClient, error) {
+	operator := OperatorClient{INacosClient: nc, closeOnce: &sync.Once{}}
+	clientConfig, err := nc.GetClientConfig()
+	if err != nil {
+		return operator, err
+	}
+	serverConfig, err := nc.GetServerConfig()
+	if err != nil {
+		return operator, err
+	}
+	httpAgent, err := nc.GetHttpAgent()
+	if err != nil {
+		return operator, err
+	}
+	if clientConfig.Logger != nil {
+		logger.SetLogger(clientConfig.Logger)
+	} else if err = logger.InitLog(clientConfig.LogDir, clientConfig.LogLevel); err != nil {
+		return operator, err
+	}
+	operator.nacosServer, err = nacos_server.NewNacosServer(serverConfig, clientConfig, httpAgent)
+	return operator, err
+}
+
+func (oc *OperatorClient) GetMetrics() (model.ServerMetrics, error) {
+	return oc.GetMetricsWithContext(context.Background())
+}
+
+func (oc *OperatorClient) GetMetricsWithContext(ctx context.Context) (model.ServerMetrics, error) {
+	var metrics model.ServerMetrics
+	err := oc.getJson(ctx, constant.SERVICE_OPERATOR_PATH+"/metrics", map[string]string{}, &metrics)
+	return metrics, err
+}
+
+func (oc *OperatorClient) ListClusterMembers(param vo.ListClusterMembersParam) ([]model.ClusterMember, error) {
+	return oc.ListClusterMembersWithContext(context.Background(), param)
+}
+
+func (oc *OperatorClient) ListClusterMembersWithContext(ctx context.Context, param vo.ListClusterMembersParam) ([]model.ClusterMember, error) {
+	var result struct {
+		Servers []model.ClusterMember `json:"servers"`
+	}
+	params := map[string]string{"healthy": strconv.FormatBool(param.Healthy)}
+	if err := oc.getJson(ctx, constant.SERVICE_OPERATOR_PATH+"/servers", params, &result); err != nil {
+		return nil, err
+	}
+	return result.Servers, nil
+}
+
+func (oc *OperatorClient) GetSwitches() (model.ServerSwitches, error) {
+	return oc.GetSwitchesWithContext(context.Background())
+}
+
+func (oc *OperatorClient) GetSwitchesWithContext(ctx context.Context) (model.ServerSwitches, error) {
+	var switches model.ServerSwitches
+	err := oc.getJson(ctx, constant.SERVICE_OPERATOR_PATH+"/switches", map[string]string{}, &switches)
+	return switches, err
+}
+
+func (oc *OperatorClient) UpdateSwitch(param vo.UpdateSwitchParam) (bool, error) {
+	return oc.UpdateSwitchWithContext(context.Background(), param)
+}
+
+func (oc *OperatorClient) UpdateSwitchWithContext(ctx context.Context, param vo.UpdateSwitchParam) (bool, error) {
+	if len(param.Entry) == 0 {
+		return false, errors.New("[client.UpdateSwitch] entry can not be empty")
+	}
+	logger.Infof("update server switch entry:<%s>,value:<%s>,debug:<%t>", param.Entry, param.Value, param.Debug)
+	params := map[string]string{
+		"entry": param.Entry,
+		"value": param.Value,
+		"debug": strconv.FormatBool(param.Debug),
+	}
+	result, err := oc.nacosServer.ReqApi(ctx, constant.SERVICE_OPERATOR_PATH+"/switches", params, http.MethodPut)
+	if err != nil {
+		return false, err
+	}
+	if strings.TrimSpace(result) != "ok" {
+		return false, errors.New("[client.UpdateSwitch] update switch failed: " + result)
+	}
+	return true, nil
+}
+
+func (oc *OperatorClient) GetServerState() (map[string]string, error) {
+	return oc.GetServerStateWithContext(context.Background())
+}
+
+func (oc *OperatorClient) GetServerStateWithContext(ctx context.Context) (map[string]string, error) {
+	state := map[string]string{}
+	err := oc.getJson(ctx, constant.CONSOLE_BASE_PATH+"/server/state", map[string]string{}, &state)
+	return state, err
+}
+
+func (oc *OperatorClient) CheckReadiness() error {
+	return oc.CheckReadinessWithContext(context.Background())
+}
+
+// 请求会按重试策略发往各服务端，任一服务端就绪即返回nil
+func (oc *OperatorClient) CheckReadinessWithContext(ctx context.Context) error {
+	_, err := oc.nacosServer.ReqApi(ctx, constant.CONSOLE_BASE_PATH+"/health/readiness", map[string]string{}, http.MethodGet)
+	return err
+}
+
+func (oc *OperatorClient) ListNamespaces() ([]model.Namespace, error) {
+	return oc.ListNamespacesWithContext(context.Background())
+}
+
+func (oc *OperatorClient) ListNamespacesWithContext(ctx context.Context) ([]model.Namespace, error) {
+	var result struct {
+		Code    int               `json:"code"`
+		Message string            `json:"message"`
+		Data    []model.Namespace `json:"data"`
+	}
+	if err := oc.getJson(ctx, constant.NAMESPACE_PATH, map[string]string{}, &result); err != nil {
+		return nil, err
+	}
+	if result.Code != http.StatusOK {
+		return nil, errors.New("[client.ListNamespaces] list namespaces failed: " + result.Message)
+	}
+	return result.Data, nil
+}
+
+func (oc *OperatorClient) CreateNamespace(param vo.CreateNamespaceParam) (string, error) {
+	return oc.CreateNamespaceWithContext(context.Background(), param)
+}
+
+// 服务端在NamespaceId为空时生成UUID作为ID，但不会在响应中返回，此时需要通过ListNamespaces按名称查找
+func (oc *OperatorClient) CreateNamespaceWithContext(ctx context.Context, param vo.CreateNamespaceParam) (string, error) {
+	if len(param.NamespaceName) == 0 {
+		return "", errors.New("[client.CreateNamespace] namespaceName can not be empty")
+	}
+	logger.Infof("create namespace id:<%s>,name:<%s>", param.NamespaceId, param.NamespaceName)
+	params := map[string]string{
+		"customNamespaceId": param.NamespaceId,
+		"namespaceName":     param.NamespaceName,
+		"namespaceDesc":     param.NamespaceDesc,
+	}
+	if _, err := oc.reqBool(ctx, "CreateNamespace", params, http.MethodPost); err != nil {
+		return "", err
+	}
+	if len(param.NamespaceId) > 0 {
+		return param.NamespaceId, nil
+	}
+	namespaces, err := oc.ListNamespacesWithContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, namespace := range namespaces {
+		if namespace.NamespaceShowName == param.NamespaceName {
+			return namespace.Namespace, nil
+		}
+	}
+	return "", errors.New("[client.CreateNamespace] namespace <" + param.NamespaceName + "> not found after creation")
+}
+
+func (oc *OperatorClient) UpdateNamespace(param vo.UpdateNamespaceParam) (bool, error) {
+	return oc.UpdateNamespaceWithContext(context.Background(), param)
+}
+
+func (oc *OperatorClient) UpdateNamespaceWithContext(ctx context.Context, param vo.UpdateNamespaceParam) (bool, error) {
+	if len(param.NamespaceId) == 0 {
+		return false, errors.New("[client.UpdateNamespace] namespaceId can not be empty")
+	}
+	if len(param.NamespaceName) == 0 {
+		return false, errors.New("[client.UpdateNamespace] namespaceName can not be empty")
+	}
+	logger.Infof("update namespace id:<%s>,name:<%s>", param.NamespaceId, param.NamespaceName)
+	params := map[string]string{
+		"namespace":         param.NamespaceId,
+		"namespaceShowName": param.NamespaceName,
+		"namespaceDesc":     param.NamespaceDesc,
+	}
+	return oc.reqBool(ctx, "UpdateNamespace", params, http.MethodPut)
+}
+
+func (oc *OperatorClient) DeleteNamespace(param vo.DeleteNamespaceParam) (bool, error) {
+	return oc.DeleteNamespaceWithContext(context.Background(), param)
+}
+
+func (oc *OperatorClient) DeleteNamespaceWithContext(ctx context.Context, param vo.DeleteNamespaceParam) (bool, error) {
+	if len(param.NamespaceId) == 0 {
+		return false, errors.New("[client.DeleteNamespace] namespaceId can not be empty")
+	}
+	logger.Infof("delete namespace id:<%s>", param.NamespaceId)
+	return oc.reqBool(ctx, "DeleteNamespace", map[string]string{"namespaceId": param.NamespaceId}, http.MethodDelete)
+}
+
+// 命名空间的写接口以true/false表示是否成功
+func (oc *OperatorClient) reqBool(ctx context.Context, method string, params map[string]string, httpMethod string) (bool, error) {
+	result, err := oc.nacosServer.ReqApi(ctx, constant.NAMESPACE_PATH, params, httpMethod)
+	if err != nil {
+		return false, err
+	}
+	if strings.TrimSpace(result) != "true" {
+		return false, errors.New("[client." + method + "] request failed: " + result)
+	}
+	return true, nil
+}
+
+func (oc *OperatorClient) getJson(ctx context.Context, api string, params map[string]string, v interface{}) error {
+	result, err := oc.nacosServer.ReqApi(ctx, api, params, http.MethodGet)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal([]byte(result), v); err != nil {
+		return nacos_error.Wrap("[client.Operator] parse result of <"+api+"> failed: "+result, err)
+	}
+	return nil
+}
+
+func (oc *OperatorClient) Close() error {
+	if oc.closeOnce != nil {
+		oc.closeOnce.Do(oc.nacosServer.Stop)
+	}
+	return nil
+}
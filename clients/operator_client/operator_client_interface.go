@@ -0,0 +1,48 @@
+package operator_client
+
+import (
+	"context"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+//go:generate mockgen -destination ../../mock/mock_operator_client_interface.go -package mock -source=./operator_client_interface.go
+
+// 服务端运维接口，用于监控集群状态和调整服务端开关
+type IOperatorClient interface {
+	// 获取服务端的服务数、实例数、raft任务数等运行指标
+	GetMetrics() (model.ServerMetrics, error)
+	// 获取集群成员及其健康状态
+	ListClusterMembers(param vo.ListClusterMembersParam) ([]model.ClusterMember, error)
+	// 获取服务端开关
+	GetSwitches() (model.ServerSwitches, error)
+	// 修改服务端开关
+	UpdateSwitch(param vo.UpdateSwitchParam) (bool, error)
+	// 获取服务端的版本、运行模式等状态
+	GetServerState() (map[string]string, error)
+	// 服务端就绪时返回nil
+	CheckReadiness() error
+	// 获取所有命名空间，包括public命名空间
+	ListNamespaces() ([]model.Namespace, error)
+	// 创建命名空间，返回命名空间ID
+	CreateNamespace(param vo.CreateNamespaceParam) (string, error)
+	// 修改命名空间的名称和描述
+	UpdateNamespace(param vo.UpdateNamespaceParam) (bool, error)
+	// 删除命名空间，命名空间下的配置不会被删除
+	DeleteNamespace(param vo.DeleteNamespaceParam) (bool, error)
+
+	// 以下方法与上面的同名方法一致，可通过ctx取消请求或设置超时
+	GetMetricsWithContext(ctx context.Context) (model.ServerMetrics, error)
+	ListClusterMembersWithContext(ctx context.Context, param vo.ListClusterMembersParam) ([]model.ClusterMember, error)
+	GetSwitchesWithContext(ctx context.Context) (model.ServerSwitches, error)
+	UpdateSwitchWithContext(ctx context.Context, param vo.UpdateSwitchParam) (bool, error)
+	GetServerStateWithContext(ctx context.Context) (map[string]string, error)
+	CheckReadinessWithContext(ctx context.Context) error
+	ListNamespacesWithContext(ctx context.Context) ([]model.Namespace, error)
+	CreateNamespaceWithContext(ctx context.Context, param vo.CreateNamespaceParam) (string, error)
+	UpdateNamespaceWithContext(ctx context.Context, param vo.UpdateNamespaceParam) (bool, error)
+	DeleteNamespaceWithContext(ctx context.Context, param vo.DeleteNamespaceParam) (bool, error)
+
+	// 关闭客户端，见nacos_client.CloseableClient
+	Close() error
+}
@@ -0,0 +1,165 @@
+package operator_client
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/nacos-group/nacos-sdk-go/clients/nacos_client"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/mock"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+var clientConfigTest = constant.ClientConfig{
+	TimeoutMs:      10 * 1000,
+	ListenInterval: 20 * 1000,
+	BeatInterval:   5 * 1000,
+}
+
+var serverConfigTest = constant.ServerConfig{
+	ContextPath: "/nacos",
+	Port:        80,
+	IpAddr:      "console.nacos.io",
+}
+
+func createOperatorClientTest(t *testing.T, httpAgent http_agent.IHttpAgent) OperatorClient {
+	nc := nacos_client.NacosClient{}
+	assert.Nil(t, nc.SetServerConfig([]constant.ServerConfig{serverConfigTest}))
+	assert.Nil(t, nc.SetClientConfig(clientConfigTest))
+	assert.Nil(t, nc.SetHttpAgent(httpAgent))
+	client, err := NewOperatorClient(&nc)
+	assert.Nil(t, err)
+	return client
+}
+
+func expectGet(mockHttpAgent *mock.MockIHttpAgent, path string, response string) *gomock.Call {
+	return mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos"+path),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		Return(http_agent.FakeHttpResponse(200, response), nil)
+}
+
+func TestOperatorClient_GetMetrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	expectGet(mockHttpAgent, "/v1/ns/operator/metrics",
+		`{"status":"UP","serviceCount":12,"instanceCount":30,"raftNotifyTaskCount":0,"load":0.5,"cpu":0.1,"mem":0.6}`)
+	client := createOperatorClientTest(t, mockHttpAgent)
+	defer client.Close()
+
+	metrics, err := client.GetMetrics()
+	assert.Nil(t, err)
+	assert.Equal(t, "UP", metrics.Status)
+	assert.Equal(t, 12, metrics.ServiceCount)
+	assert.Equal(t, 30, metrics.InstanceCount)
+	assert.Equal(t, 0.5, metrics.Load)
+}
+
+func TestOperatorClient_ListClusterMembers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	expectGet(mockHttpAgent, "/v1/ns/operator/servers",
+		`{"servers":[{"ip":"10.0.0.1","port":8848,"state":"UP","address":"10.0.0.1:8848","failAccessCnt":0,"extendInfo":{"raftPort":"7848"}},`+
+			`{"ip":"10.0.0.2","port":8848,"state":"DOWN","address":"10.0.0.2:8848","failAccessCnt":3}]}`).
+		Do(func(ctx, method, path, header, timeoutMs interface{}, params map[string]string) {
+			assert.Equal(t, "false", params["healthy"])
+		})
+	client := createOperatorClientTest(t, mockHttpAgent)
+	defer client.Close()
+
+	members, err := client.ListClusterMembers(vo.ListClusterMembersParam{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(members))
+	assert.Equal(t, "UP", members[0].State)
+	assert.Equal(t, "7848", members[0].ExtendInfo["raftPort"])
+	assert.Equal(t, 3, members[1].FailAccessCnt)
+}
+
+func TestOperatorClient_Switches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	expectGet(mockHttpAgent, "/v1/ns/operator/switches",
+		`{"name":"00-00---000-NACOS_SWITCH_DOMAIN-000---00-00","clientBeatInterval":5000,"healthCheckEnabled":true,"pushEnabled":true}`)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPut),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/operator/switches"),
+		gomock.Any(), gomock.Any(), gomock.Eq(map[string]string{"entry": "healthCheckEnabled", "value": "false", "debug": "false"})).Times(1).
+		Return(http_agent.FakeHttpResponse(200, "ok"), nil)
+	client := createOperatorClientTest(t, mockHttpAgent)
+	defer client.Close()
+
+	switches, err := client.GetSwitches()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5000), switches.ClientBeatInterval)
+	assert.True(t, switches.HealthCheckEnabled)
+
+	_, err = client.UpdateSwitch(vo.UpdateSwitchParam{Value: "false"})
+	assert.NotNil(t, err)
+	success, err := client.UpdateSwitch(vo.UpdateSwitchParam{Entry: "healthCheckEnabled", Value: "false"})
+	assert.Nil(t, err)
+	assert.True(t, success)
+}
+
+func TestOperatorClient_ServerState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	expectGet(mockHttpAgent, "/v1/console/server/state", `{"version":"1.4.1","standalone_mode":"cluster","function_mode":null}`)
+	expectGet(mockHttpAgent, "/v1/console/health/readiness", "OK")
+	client := createOperatorClientTest(t, mockHttpAgent)
+	defer client.Close()
+
+	state, err := client.GetServerState()
+	assert.Nil(t, err)
+	assert.Equal(t, "1.4.1", state["version"])
+	assert.Equal(t, "cluster", state["standalone_mode"])
+	assert.Nil(t, client.CheckReadiness())
+}
+
+func TestOperatorClient_Namespaces(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	namespacesUrl := "http://console.nacos.io:80/nacos/v1/console/namespaces"
+	listResponse := `{"code":200,"message":null,"data":[{"namespace":"","namespaceShowName":"public","quota":200,"configCount":3,"type":0},` +
+		`{"namespace":"5a8c-4f1b","namespaceShowName":"dev","namespaceDesc":"dev env","quota":200,"configCount":0,"type":2}]}`
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet), gomock.Eq(namespacesUrl),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
+		Return(http_agent.FakeHttpResponse(200, listResponse), nil)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost), gomock.Eq(namespacesUrl),
+		gomock.Any(), gomock.Any(), gomock.Eq(map[string]string{"customNamespaceId": "", "namespaceName": "dev", "namespaceDesc": "dev env"})).Times(1).
+		Return(http_agent.FakeHttpResponse(200, "true"), nil)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPut), gomock.Eq(namespacesUrl),
+		gomock.Any(), gomock.Any(), gomock.Eq(map[string]string{"namespace": "5a8c-4f1b", "namespaceShowName": "develop", "namespaceDesc": ""})).Times(1).
+		Return(http_agent.FakeHttpResponse(200, "true"), nil)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodDelete), gomock.Eq(namespacesUrl),
+		gomock.Any(), gomock.Any(), gomock.Eq(map[string]string{"namespaceId": "5a8c-4f1b"})).Times(1).
+		Return(http_agent.FakeHttpResponse(200, "false"), nil)
+	client := createOperatorClientTest(t, mockHttpAgent)
+	defer client.Close()
+
+	namespaces, err := client.ListNamespaces()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(namespaces))
+	assert.Equal(t, "public", namespaces[0].NamespaceShowName)
+	assert.Equal(t, 3, namespaces[0].ConfigCount)
+	assert.Equal(t, 2, namespaces[1].Type)
+
+	_, err = client.CreateNamespace(vo.CreateNamespaceParam{})
+	assert.NotNil(t, err)
+	namespaceId, err := client.CreateNamespace(vo.CreateNamespaceParam{NamespaceName: "dev", NamespaceDesc: "dev env"})
+	assert.Nil(t, err)
+	assert.Equal(t, "5a8c-4f1b", namespaceId)
+
+	success, err := client.UpdateNamespace(vo.UpdateNamespaceParam{NamespaceId: "5a8c-4f1b", NamespaceName: "develop"})
+	assert.Nil(t, err)
+	assert.True(t, success)
+
+	success, err = client.DeleteNamespace(vo.DeleteNamespaceParam{NamespaceId: "5a8c-4f1b"})
+	assert.NotNil(t, err)
+	assert.False(t, success)
+}
@@ -0,0 +1,92 @@
+package audit
+
+import (
+	"errors"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"time"
+)
+
+const (
+	Module_Naming = "naming"
+	Module_Config = "config"
+)
+
+// 配置的变化类型，除model.Config_Change_Added等外，未通过校验的变化记为Change_Rejected
+const Change_Rejected = "REJECTED"
+
+// 通道已满时ChanSink丢弃记录并返回该错误
+var ErrDropped = errors.New("audit record dropped")
+
+// 客户端观察到的一次变化
+// naming：服务的实例列表变化，Added、Removed、Modified为变化的实例，首次获取服务时所有实例计为Added
+// config：监听的配置内容变化，OldMd5和NewMd5为变化前后的md5，删除时NewMd5为空
+type Record struct {
+	// 客户端观察到变化的时间
+	Time      time.Time `json:"time"`
+	Module    string    `json:"module"`
+	Namespace string    `json:"namespace"`
+	Group     string    `json:"group"`
+	// 服务名（不带分组）或dataId
+	Name     string           `json:"name"`
+	Clusters string           `json:"clusters,omitempty"`
+	Added    []model.Instance `json:"added,omitempty"`
+	Removed  []model.Instance `json:"removed,omitempty"`
+	Modified []model.Instance `json:"modified,omitempty"`
+	// 配置的变化类型
+	ChangeType string `json:"changeType,omitempty"`
+	OldMd5     string `json:"oldMd5,omitempty"`
+	NewMd5     string `json:"newMd5,omitempty"`
+	// 返回变化内容的服务端地址，仅在从服务端拉取到变化时有值
+	Server string `json:"server,omitempty"`
+	// 服务端记录的修改时间，单位毫秒，取自配置响应的Last-Modified头或服务的lastRefTime，无法获取时为0
+	ServerTime int64 `json:"serverTime,omitempty"`
+}
+
+// 审计记录的写入目标，在产生变化的协程中同步调用，实现不应阻塞且需支持并发调用
+type Sink interface {
+	Write(record Record) error
+}
+
+// 将函数适配为Sink
+type SinkFunc func(record Record) error
+
+func (f SinkFunc) Write(record Record) error {
+	return f(record)
+}
+
+type chanSink struct {
+	ch chan<- Record
+}
+
+// 将记录写入ch，ch已满时丢弃记录并返回ErrDropped
+func NewChanSink(ch chan<- Record) Sink {
+	return chanSink{ch: ch}
+}
+
+func (s chanSink) Write(record Record) error {
+	select {
+	case s.ch <- record:
+		return nil
+	default:
+		return ErrDropped
+	}
+}
+
+// sink为nil时不记录，Time为空时使用当前时间，写入失败或panic时只记录日志
+func Write(sink Sink, record Record) {
+	if sink == nil {
+		return
+	}
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("[audit] sink panic, module:%s name:%s err:%v", record.Module, record.Name, r)
+		}
+	}()
+	if err := sink.Write(record); err != nil {
+		logger.Warnf("[audit] write record failed, module:%s name:%s err:%s", record.Module, record.Name, err.Error())
+	}
+}
@@ -0,0 +1,62 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	Write(nil, Record{Name: "nil sink"})
+
+	ch := make(chan Record, 1)
+	sink := NewChanSink(ch)
+	Write(sink, Record{Module: Module_Config, Name: "app.yaml"})
+	record := <-ch
+	assert.Equal(t, "app.yaml", record.Name)
+	assert.False(t, record.Time.IsZero())
+
+	ch <- Record{}
+	assert.Equal(t, ErrDropped, sink.Write(Record{}))
+
+	Write(SinkFunc(func(record Record) error {
+		panic("sink panic")
+	}), Record{})
+	Write(SinkFunc(func(record Record) error {
+		return errors.New("failed")
+	}), Record{})
+}
+
+func TestFileSink(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "nacos-audit")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit", "audit.log")
+	sink, err := NewFileSink(FileSinkConfig{Path: path, MaxSize: 200, MaxBackups: 2})
+	assert.Nil(t, err)
+	for i := 0; i < 10; i++ {
+		assert.Nil(t, sink.Write(Record{Module: Module_Config, Group: "DEFAULT_GROUP", Name: "app.yaml", NewMd5: "md5"}))
+	}
+	assert.Nil(t, sink.Close())
+	assert.NotNil(t, sink.Write(Record{}))
+
+	for _, p := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(p)
+		assert.Nil(t, err)
+		assert.True(t, info.Size() <= 200)
+	}
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+
+	file, _ := os.Open(path)
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	assert.True(t, scanner.Scan())
+	var record Record
+	assert.Nil(t, json.Unmarshal(scanner.Bytes(), &record))
+	assert.Equal(t, "app.yaml", record.Name)
+}
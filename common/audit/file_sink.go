@@ -0,0 +1,115 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+const (
+	// 单个审计文件的默认大小上限
+	Default_Max_File_Size = 100 * 1024 * 1024
+	// 默认保留的历史文件数
+	Default_Max_Backups = 5
+)
+
+// 按大小滚动的审计文件，每条记录一行JSON
+type FileSinkConfig struct {
+	Path string
+	// 当前文件超过该字节数时滚动，小于等于0时为Default_Max_File_Size
+	MaxSize int64
+	// 保留的历史文件数，历史文件为Path.1、Path.2……，数字越大越旧，小于等于0时为Default_Max_Backups
+	MaxBackups int
+}
+
+type FileSink struct {
+	mutex  sync.Mutex
+	config FileSinkConfig
+	file   *os.File
+	size   int64
+}
+
+// 目录不存在时自动创建，文件已存在时追加写入
+func NewFileSink(config FileSinkConfig) (*FileSink, error) {
+	if config.MaxSize <= 0 {
+		config.MaxSize = Default_Max_File_Size
+	}
+	if config.MaxBackups <= 0 {
+		config.MaxBackups = Default_Max_Backups
+	}
+	s := &FileSink{config: config}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileSink) open() error {
+	if err := os.MkdirAll(filepath.Dir(s.config.Path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(s.config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	s.file, s.size = file, info.Size()
+	return nil
+}
+
+func (s *FileSink) Write(record Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.file == nil {
+		return os.ErrClosed
+	}
+	if s.size > 0 && s.size+int64(len(line)) > s.config.MaxSize {
+		if err = s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	return err
+}
+
+// 依次将Path.n重命名为Path.n+1，超出MaxBackups的文件被覆盖，调用时需持有mutex
+func (s *FileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	s.file = nil
+	for i := s.config.MaxBackups - 1; i >= 1; i-- {
+		os.Rename(s.backupPath(i), s.backupPath(i+1))
+	}
+	if err := os.Rename(s.config.Path, s.backupPath(1)); err != nil {
+		return err
+	}
+	return s.open()
+}
+
+func (s *FileSink) backupPath(index int) string {
+	return s.config.Path + "." + strconv.Itoa(index)
+}
+
+func (s *FileSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
@@ -0,0 +1,65 @@
+package codec
+
+import (
+	"encoding/json"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+	"path"
+	"strings"
+	"sync"
+)
+
+const (
+	TYPE_JSON       = "json"
+	TYPE_PROPERTIES = "properties"
+	TYPE_YAML       = "yaml"
+	TYPE_TOML       = "toml"
+)
+
+// 将配置内容解析到v中，v应为指针
+type Codec interface {
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// 将函数适配为Codec，如codec.Register("xml", codec.CodecFunc(xml.Unmarshal))
+type CodecFunc func(data []byte, v interface{}) error
+
+func (f CodecFunc) Unmarshal(data []byte, v interface{}) error {
+	return f(data, v)
+}
+
+var (
+	mutex  sync.RWMutex
+	codecs = map[string]Codec{
+		TYPE_JSON:       CodecFunc(json.Unmarshal),
+		TYPE_PROPERTIES: CodecFunc(UnmarshalProperties),
+		TYPE_YAML:       CodecFunc(yaml.Unmarshal),
+		TYPE_TOML:       CodecFunc(toml.Unmarshal),
+	}
+)
+
+// 注册或替换指定配置类型的Codec，内置json、properties、yaml和toml
+func Register(configType string, codec Codec) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	codecs[strings.ToLower(configType)] = codec
+}
+
+func Get(configType string) (Codec, bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	codec, ok := codecs[strings.ToLower(configType)]
+	return codec, ok
+}
+
+// 根据dataId的扩展名推断配置类型，无法推断时返回空字符串
+func DetectType(dataId string) string {
+	switch ext := strings.ToLower(strings.TrimPrefix(path.Ext(dataId), ".")); ext {
+	case "yml":
+		return TYPE_YAML
+	case TYPE_JSON, TYPE_PROPERTIES, TYPE_YAML, TYPE_TOML:
+		return ext
+	default:
+		return ""
+	}
+}
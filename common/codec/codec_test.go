@@ -0,0 +1,76 @@
+package codec
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDetectType(t *testing.T) {
+	assert.Equal(t, TYPE_YAML, DetectType("application.yml"))
+	assert.Equal(t, TYPE_YAML, DetectType("application.YAML"))
+	assert.Equal(t, TYPE_PROPERTIES, DetectType("application.properties"))
+	assert.Equal(t, TYPE_JSON, DetectType("application.json"))
+	assert.Equal(t, "", DetectType("application"))
+}
+
+func TestRegister(t *testing.T) {
+	_, ok := Get("custom-json")
+	assert.False(t, ok)
+	Register("Custom-JSON", CodecFunc(json.Unmarshal))
+	c, ok := Get("custom-json")
+	assert.True(t, ok)
+	var v map[string]int
+	assert.Nil(t, c.Unmarshal([]byte(`{"port":8080}`), &v))
+	assert.Equal(t, 8080, v["port"])
+}
+
+func TestBuiltinCodecs(t *testing.T) {
+	type config struct {
+		Name string `yaml:"name" toml:"name"`
+		Port int    `yaml:"port" toml:"port"`
+	}
+	for configType, data := range map[string]string{
+		TYPE_YAML: "name: demo\nport: 8080\n",
+		TYPE_TOML: "name = \"demo\"\nport = 8080\n",
+	} {
+		c, ok := Get(configType)
+		assert.True(t, ok)
+		var v config
+		assert.Nil(t, c.Unmarshal([]byte(data), &v), configType)
+		assert.Equal(t, config{Name: "demo", Port: 8080}, v, configType)
+	}
+}
+
+func TestUnmarshalProperties(t *testing.T) {
+	data := []byte(`# comment
+name = demo
+port: 8080
+enabled=true
+tags=a, b
+description=first \
+  second
+`)
+	var m map[string]string
+	assert.Nil(t, UnmarshalProperties(data, &m))
+	assert.Equal(t, "first second", m["description"])
+
+	var config struct {
+		Name    string   `properties:"name"`
+		Port    int      `properties:"port"`
+		Enabled bool     `properties:"enabled"`
+		Tags    []string `properties:"tags"`
+		Missing string   `properties:"missing"`
+	}
+	assert.Nil(t, UnmarshalProperties(data, &config))
+	assert.Equal(t, "demo", config.Name)
+	assert.Equal(t, 8080, config.Port)
+	assert.True(t, config.Enabled)
+	assert.Equal(t, []string{"a", "b"}, config.Tags)
+
+	var invalid struct {
+		Port int `properties:"name"`
+	}
+	assert.NotNil(t, UnmarshalProperties(data, &invalid))
+	assert.NotNil(t, UnmarshalProperties(data, config))
+}
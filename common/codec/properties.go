@@ -0,0 +1,110 @@
+package codec
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// 解析properties格式的内容，支持#和!开头的注释、=或:分隔的键值以及行尾\续行
+func ParseProperties(data string) map[string]string {
+	result := map[string]string{}
+	lines := strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		for strings.HasSuffix(line, "\\") && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + strings.TrimSpace(lines[i])
+		}
+		index := strings.IndexAny(line, "=:")
+		if index < 0 {
+			result[line] = ""
+			continue
+		}
+		result[strings.TrimSpace(line[:index])] = strings.TrimSpace(line[index+1:])
+	}
+	return result
+}
+
+// 将properties内容解析到*map[string]string或结构体指针中
+// 结构体字段按properties标签匹配键，没有标签时使用字段名
+func UnmarshalProperties(data []byte, v interface{}) error {
+	properties := ParseProperties(string(data))
+	if m, ok := v.(*map[string]string); ok {
+		*m = properties
+		return nil
+	}
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return errors.New("[codec] properties can only be unmarshaled into *map[string]string or struct pointer")
+	}
+	value = value.Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		key := field.Tag.Get("properties")
+		if key == "-" {
+			continue
+		}
+		if key == "" {
+			key = field.Name
+		}
+		raw, ok := properties[key]
+		if !ok {
+			continue
+		}
+		if err := setValue(value.Field(i), raw); err != nil {
+			return errors.New("[codec] invalid value of " + key + ":" + err.Error())
+		}
+	}
+	return nil
+}
+
+func setValue(field reflect.Value, raw string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return errors.New("unsupported type " + field.Type().String())
+		}
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			items = append(items, strings.TrimSpace(item))
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return errors.New("unsupported type " + field.Type().String())
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package config_bus
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"sync"
+)
+
+// 客户端配置的变更总线，发布新配置后按订阅顺序同步通知各组件
+type ConfigBus struct {
+	publishMutex    sync.Mutex
+	mutex           sync.RWMutex
+	clientConfig    constant.ClientConfig
+	serverConfigs   []constant.ServerConfig
+	clientListeners []func(oldConfig constant.ClientConfig, newConfig constant.ClientConfig)
+	serverListeners []func(servers []constant.ServerConfig)
+}
+
+func NewConfigBus(clientConfig constant.ClientConfig, serverConfigs []constant.ServerConfig) *ConfigBus {
+	return &ConfigBus{clientConfig: clientConfig, serverConfigs: serverConfigs}
+}
+
+func (b *ConfigBus) ClientConfig() constant.ClientConfig {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return b.clientConfig
+}
+
+func (b *ConfigBus) ServerConfigs() []constant.ServerConfig {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return b.serverConfigs
+}
+
+func (b *ConfigBus) OnClientConfigChanged(listener func(oldConfig constant.ClientConfig, newConfig constant.ClientConfig)) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.clientListeners = append(b.clientListeners, listener)
+}
+
+func (b *ConfigBus) OnServerConfigChanged(listener func(servers []constant.ServerConfig)) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.serverListeners = append(b.serverListeners, listener)
+}
+
+func (b *ConfigBus) PublishClientConfig(config constant.ClientConfig) {
+	b.publishMutex.Lock()
+	defer b.publishMutex.Unlock()
+	b.mutex.Lock()
+	oldConfig := b.clientConfig
+	b.clientConfig = config
+	listeners := b.clientListeners
+	b.mutex.Unlock()
+	for _, listener := range listeners {
+		listener(oldConfig, config)
+	}
+}
+
+func (b *ConfigBus) PublishServerConfigs(servers []constant.ServerConfig) {
+	b.publishMutex.Lock()
+	defer b.publishMutex.Unlock()
+	b.mutex.Lock()
+	b.serverConfigs = servers
+	listeners := b.serverListeners
+	b.mutex.Unlock()
+	for _, listener := range listeners {
+		listener(servers)
+	}
+}
@@ -0,0 +1,27 @@
+package config_bus
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestConfigBus_Publish(t *testing.T) {
+	bus := NewConfigBus(constant.ClientConfig{TimeoutMs: 1000}, []constant.ServerConfig{{IpAddr: "127.0.0.1", Port: 8848}})
+	var changes []uint64
+	bus.OnClientConfigChanged(func(oldConfig constant.ClientConfig, newConfig constant.ClientConfig) {
+		changes = append(changes, oldConfig.TimeoutMs, newConfig.TimeoutMs)
+	})
+	var servers []constant.ServerConfig
+	bus.OnServerConfigChanged(func(s []constant.ServerConfig) {
+		servers = s
+	})
+
+	bus.PublishClientConfig(constant.ClientConfig{TimeoutMs: 2000})
+	assert.Equal(t, []uint64{1000, 2000}, changes)
+	assert.Equal(t, uint64(2000), bus.ClientConfig().TimeoutMs)
+
+	bus.PublishServerConfigs([]constant.ServerConfig{{IpAddr: "127.0.0.2", Port: 8848}})
+	assert.Equal(t, "127.0.0.2", servers[0].IpAddr)
+	assert.Equal(t, servers, bus.ServerConfigs())
+}
@@ -1,5 +1,23 @@
 package constant
 
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/audit"
+	"github.com/nacos-group/nacos-sdk-go/common/credentials"
+	"github.com/nacos-group/nacos-sdk-go/common/event"
+	"github.com/nacos-group/nacos-sdk-go/common/health_check"
+	"github.com/nacos-group/nacos-sdk-go/common/load_balancer"
+	"github.com/nacos-group/nacos-sdk-go/common/local_ip"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/common/monitor"
+	"github.com/nacos-group/nacos-sdk-go/common/rate_limiter"
+	"github.com/nacos-group/nacos-sdk-go/common/remote"
+	"github.com/nacos-group/nacos-sdk-go/common/retry"
+	"github.com/nacos-group/nacos-sdk-go/common/serializer"
+	"github.com/nacos-group/nacos-sdk-go/common/store"
+	"github.com/nacos-group/nacos-sdk-go/common/tracing"
+	"github.com/nacos-group/nacos-sdk-go/model"
+)
+
 /**
 *
 * @description :
@@ -13,21 +31,230 @@ type ServerConfig struct {
 	ContextPath string
 	IpAddr      string
 	Port        uint64
+	Scheme      string // http或https，为空时根据ClientConfig.TLSConfig.Enable决定
+}
+
+// 访问nacos服务端的TLS配置
+type TLSConfig struct {
+	Enable             bool   // 开启后未指定Scheme的服务端使用https
+	CaFile             string // 校验服务端证书的CA文件，为空时使用系统CA
+	CertFile           string // 客户端证书，与KeyFile一同配置时启用双向认证
+	KeyFile            string
+	InsecureSkipVerify bool
+	ServerName         string
+}
+
+// 通过本机的nacos sidecar代理访问服务端，同一主机上的多个进程共享sidecar的连接、缓存和推送接收
+// 设置后忽略ServerConfig和Endpoint，所有请求都发给sidecar
+type AgentConfig struct {
+	// sidecar监听的unix socket路径，设置后忽略Address
+	UnixSocket string
+	// sidecar监听的本机地址，如127.0.0.1:8848
+	Address string
+	// sidecar的上下文路径，为空时为/nacos
+	ContextPath string
+}
+
+// 配置内容的大小限制和gzip压缩，仅在ConfigClient中有效
+type ConfigContentConfig struct {
+	// 发布前校验的内容最大字节数，应与服务端的maxContent一致，为0时为validator.Max_Content_Size
+	MaxContentSize int
+	// 内容不小于该字节数时以gzip压缩的请求体发布，需要服务端或网关支持Content-Encoding: gzip，为0时不压缩
+	CompressThreshold int
+	// 获取配置时声明Accept-Encoding: gzip，服务端返回压缩的内容时自动解压
+	AcceptGzip bool
 }
 
 type ClientConfig struct {
 	TimeoutMs            uint64
+	Timeout              *TimeoutConfig
 	ListenInterval       uint64
 	BeatInterval         int64
 	NamespaceId          string
+	AppName              string
+	Labels               map[string]string
 	Endpoint             string
+	ContextPath          string
 	AccessKey            string
 	SecretKey            string
+	SecurityToken        string
+	RamRoleName          string
+	CredentialsProvider  credentials.CredentialsProvider
+	Username             string
+	Password             string
 	CacheDir             string
+	ConfigSnapshotDir    string
+	JavaCacheLayout      bool
+	CacheStore           store.Store
+	ConfigContent        *ConfigContentConfig
 	LogDir               string
+	LogLevel             string
+	Logger               logger.Logger // 替换进程内所有客户端共用的全局日志，后创建的客户端的设置生效
 	UpdateThreadNum      int
+	UpdateRateLimit      int
 	NotLoadCacheAtStart  bool
 	UpdateCacheWhenEmpty bool
+	CacheWriteDelayMs    uint64
+	CacheTTLMs           uint64
+	MaxCachedServices    int
+	CachedServiceIdleMs  uint64
+	UnsubscribeGraceMs   uint64
+	CacheOnly            bool
+	UdpIp                string
+	UdpPort              int
+	OnPushError          func(data []byte, err error)
+	EventListener        event.Listener
+	AuditSink            audit.Sink
+	DeregisterOnClose    bool
+	EnableAutoDeregister bool
+	InstancesEqual       func(oldHosts []model.Instance, newHosts []model.Instance) bool
+	LoadBalancer         load_balancer.LoadBalancer
+	HashRingVirtualNodes int
+	WarmUpMs             uint64
+	Locality             *load_balancer.LocalityConfig
+	HealthCheck          *health_check.HealthCheckConfig
+	TLSConfig            TLSConfig
+	ExtraHeaders         map[string]string
+	ExtraParams          map[string]string
+	Agent                *AgentConfig
+	RetryPolicy          *retry.RetryPolicy
+	CircuitBreaker       *retry.CircuitBreakerConfig
+	RateLimit            *rate_limiter.RateLimitConfig
 	OpenKMS              bool
 	RegionId             string
+	KMSKeyId             string
+	EnableMetrics        bool
+	MetricsRegistry      *monitor.Registry
+	TracerProvider       tracing.TracerProvider
+	NotReplayOnListen    bool
+	ProtectThreshold     float64
+	Serializer           serializer.Serializer
+	CacheSerializer      serializer.Serializer
+	LocalIp              *local_ip.LocalIpConfig
+	RestoreSubscriptions *RestoreSubscriptionsConfig
+	CallbackExecutor     *CallbackExecutorConfig
+	LongPoll             *LongPollConfig
+	ConfigCache          *ConfigCacheConfig
+	Transport            remote.TransportFactory // 设置后通过长连接订阅服务和监听配置，服务端不支持长连接时使用HTTP，如grpc_transport.New
+}
+
+// 将当前订阅的服务和监听的配置保存到CacheDir，客户端重启后自动恢复上次运行时的订阅和监听
+type RestoreSubscriptionsConfig struct {
+	// 恢复的服务订阅收到实例变化时回调，为nil时只恢复订阅和后台刷新
+	OnServiceChange func(event model.InstanceChangeEvent)
+	// 恢复的配置监听收到配置变化时回调，为nil时只恢复监听
+	OnConfigChange func(namespace, group, dataId, data string)
+}
+
+// 订阅回调的执行方式
+const (
+	// 在更新服务缓存的协程中同步执行
+	Callback_Mode_Sync = "sync"
+	// 由共享的协程池执行，同一服务同时最多执行一个回调，按通知顺序执行
+	Callback_Mode_Pool = "pool"
+	// 每个服务一个队列，同一服务的回调按顺序执行，不同服务的回调并发执行
+	Callback_Mode_Serial = "serial"
+)
+
+// 回调队列满时的处理方式
+const (
+	// 丢弃最早的回调
+	Overflow_Drop_Oldest = "drop-oldest"
+	// 同一服务尚未执行的SubscribeCallback只保留最新的一次，队列仍满时丢弃最早的回调
+	Overflow_Coalesce = "coalesce"
+)
+
+// 订阅回调的执行策略，避免耗时的回调阻塞服务缓存的更新
+type CallbackExecutorConfig struct {
+	// 为空时为Callback_Mode_Sync
+	Mode string
+	// Callback_Mode_Pool的协程数，小于等于0时为8
+	Workers int
+	// Callback_Mode_Pool的共享队列长度或Callback_Mode_Serial每个服务的队列长度，小于等于0时为64
+	QueueSize int
+	// 为空时为Overflow_Drop_Oldest
+	Overflow string
+}
+
+// 配置监听的长轮询参数，为nil时服务端挂起时间为ListenInterval、请求超时时间为TimeoutMs
+type LongPollConfig struct {
+	// 服务端挂起长轮询的最长时间，单位毫秒，为0时使用ListenInterval，请求超时时间为该值的1.5倍
+	TimeoutMs uint64
+	// 单个长轮询请求中最多包含的配置数，超出时分到多个并发的长轮询请求中，小于等于0时为3000
+	BatchSize int
+	// 长轮询因网络错误失败时将挂起时间减半（不低于5秒），成功后逐步恢复到TimeoutMs，用于挂起时间超过网关空闲超时的场景
+	Adaptive bool
+}
+
+// 请求服务端的分阶段超时和慢请求日志，为nil时每次请求的超时为TimeoutMs，重试不限总时长
+// 不影响配置监听的长轮询，见LongPollConfig
+type TimeoutConfig struct {
+	// 建立连接（含TLS握手）的超时时间，单位毫秒，为0时不单独限制；只在创建客户端时生效
+	ConnectTimeoutMs uint64
+	// 每次请求的超时时间，单位毫秒，为0时使用TimeoutMs
+	RequestTimeoutMs uint64
+	// 一次操作包括所有重试和重试间隔的总时长，单位毫秒，为0时不限制
+	TotalTimeoutMs uint64
+	// 一次操作的耗时不小于该值时记录慢请求日志和指标，单位毫秒，为0时不记录
+	SlowThresholdMs uint64
+}
+
+// GetConfig的内存缓存，为nil时每次GetConfig都请求服务端
+type ConfigCacheConfig struct {
+	// 缓存的有效期，单位毫秒，为0时不缓存，只合并同一配置的并发请求
+	TtlMs uint64
+	// 缓存的配置数上限，超出时先清理过期的配置，小于等于0时为1024
+	MaxEntries int
+}
+
+// 运行时更新客户端配置的选项，见UpdateClientConfig
+type ClientOption func(config *ClientConfig)
+
+func WithTimeoutMs(timeoutMs uint64) ClientOption {
+	return func(config *ClientConfig) {
+		config.TimeoutMs = timeoutMs
+	}
+}
+
+// 分阶段超时和慢请求阈值，ConnectTimeoutMs在运行时更新时不生效
+func WithTimeout(timeout *TimeoutConfig) ClientOption {
+	return func(config *ClientConfig) {
+		config.Timeout = timeout
+	}
+}
+
+// 服务端鉴权的用户名和密码，变化后立即重新登录
+func WithUsernamePassword(username string, password string) ClientOption {
+	return func(config *ClientConfig) {
+		config.Username = username
+		config.Password = password
+	}
+}
+
+// 签名使用的AccessKey/SecretKey，securityToken可为空
+func WithAccessKey(accessKey string, secretKey string, securityToken string) ClientOption {
+	return func(config *ClientConfig) {
+		config.AccessKey = accessKey
+		config.SecretKey = secretKey
+		config.SecurityToken = securityToken
+	}
+}
+
+func WithRetryPolicy(policy *retry.RetryPolicy) ClientOption {
+	return func(config *ClientConfig) {
+		config.RetryPolicy = policy
+	}
+}
+
+func WithCircuitBreaker(circuitBreaker *retry.CircuitBreakerConfig) ClientOption {
+	return func(config *ClientConfig) {
+		config.CircuitBreaker = circuitBreaker
+	}
+}
+
+// 按请求类别限制发往服务端的速率，为nil时不限制
+func WithRateLimit(rateLimit *rate_limiter.RateLimitConfig) ClientOption {
+	return func(config *ClientConfig) {
+		config.RateLimit = rateLimit
+	}
 }
@@ -31,11 +31,16 @@ const (
 	CONFIG_BASE_PATH            = "/v1/cs"
 	CONFIG_PATH                 = CONFIG_BASE_PATH + "/configs"
 	CONFIG_LISTEN_PATH          = CONFIG_BASE_PATH + "/configs/listener"
+	CONFIG_HISTORY_PATH         = CONFIG_BASE_PATH + "/history"
 	SERVICE_BASE_PATH           = "/v1/ns"
 	SERVICE_PATH                = SERVICE_BASE_PATH + "/instance"
 	SERVICE_INFO_PATH           = SERVICE_BASE_PATH + "/service"
 	SERVICE_SUBSCRIBE_PATH      = SERVICE_PATH + "/list"
-	NAMESPACE_PATH              = "/v1/console/namespaces"
+	SERVICE_BATCH_PATH          = SERVICE_PATH + "/batch"
+	SERVICE_OPERATOR_PATH       = SERVICE_BASE_PATH + "/operator"
+	CONSOLE_BASE_PATH           = "/v1/console"
+	NAMESPACE_PATH              = CONSOLE_BASE_PATH + "/namespaces"
+	AUTH_LOGIN_PATH             = "/v1/auth/login"
 	SPLIT_CONFIG                = string(rune(1))
 	SPLIT_CONFIG_INNER          = string(rune(2))
 	KEY_LISTEN_CONFIGS          = "Listening-Configs"
@@ -59,4 +64,15 @@ const (
 	DEFAULT_GROUP               = "DEFAULT_GROUP"
 	NAMING_INSTANCE_ID_SPLITTER = "#"
 	DefaultClientErrorCode      = "SDK.NacosError"
+	RESOURCE_NOT_FOUND          = 20404
 )
+
+// 上报客户端身份的请求头和参数
+const (
+	CLIENT_APP_NAME_HEADER = "Client-AppName"
+	CLIENT_LABELS_HEADER   = "Client-Labels"
+	KEY_APP                = "app"
+)
+
+// 获取配置时服务端返回的配置类型
+const CONFIG_TYPE_HEADER = "Config-Type"
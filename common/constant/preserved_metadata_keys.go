@@ -1,7 +1,18 @@
 package constant
 
+// 服务端识别的实例元数据，时长的单位为毫秒
 const (
-	HEART_BEAT_TIMEOUT  = "preserved.heart.beat.timeout"
-	IP_DELETE_TIMEOUT   = "preserved.ip.delete.timeout"
-	HEART_BEAT_INTERVAL = "preserved.heart.beat.interval"
+	HEART_BEAT_TIMEOUT    = "preserved.heart.beat.timeout"
+	IP_DELETE_TIMEOUT     = "preserved.ip.delete.timeout"
+	HEART_BEAT_INTERVAL   = "preserved.heart.beat.interval"
+	INSTANCE_ID_GENERATOR = "preserved.instance.id.generator"
+	REGISTER_SOURCE       = "preserved.register.source"
+)
+
+// INSTANCE_ID_GENERATOR的可选值
+const (
+	// 以ip#port#cluster#service作为实例id
+	SIMPLE_INSTANCE_ID_GENERATOR = "simple"
+	// 由服务端生成递增的实例id
+	SNOWFLAKE_INSTANCE_ID_GENERATOR = "snowflake"
 )
@@ -0,0 +1,133 @@
+package credentials
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 访问阿里云ACM/MSE时用于请求签名的凭证
+type Credentials struct {
+	AccessKey     string
+	SecretKey     string
+	SecurityToken string
+}
+
+type CredentialsProvider interface {
+	GetCredentials() (Credentials, error)
+}
+
+// 固定的AccessKey/SecretKey，SecurityToken不为空时即为STS临时凭证
+type StaticCredentialsProvider struct {
+	credentials Credentials
+}
+
+func NewStaticCredentialsProvider(accessKey, secretKey, securityToken string) *StaticCredentialsProvider {
+	return &StaticCredentialsProvider{credentials: Credentials{
+		AccessKey:     accessKey,
+		SecretKey:     secretKey,
+		SecurityToken: securityToken,
+	}}
+}
+
+func (p *StaticCredentialsProvider) GetCredentials() (Credentials, error) {
+	if p.credentials.AccessKey == "" || p.credentials.SecretKey == "" {
+		return Credentials{}, errors.New("[credentials] accessKey or secretKey is empty")
+	}
+	return p.credentials, nil
+}
+
+const (
+	Default_Ecs_Metadata_Url = "http://100.100.100.200/latest/meta-data/ram/security-credentials/"
+	// 距离过期不足该时间时重新获取
+	ecsRefreshAhead = 3 * time.Minute
+)
+
+type ecsRoleResult struct {
+	Code            string `json:"Code"`
+	AccessKeyId     string `json:"AccessKeyId"`
+	AccessKeySecret string `json:"AccessKeySecret"`
+	SecurityToken   string `json:"SecurityToken"`
+	Expiration      string `json:"Expiration"`
+}
+
+// 通过ECS实例元数据服务获取RAM角色的临时凭证，并在过期前自动重新获取
+type EcsRamRoleCredentialsProvider struct {
+	roleName    string
+	metadataUrl string
+	client      *http.Client
+	mutex       sync.Mutex
+	credentials Credentials
+	expiration  time.Time
+}
+
+func NewEcsRamRoleCredentialsProvider(roleName string) *EcsRamRoleCredentialsProvider {
+	return &EcsRamRoleCredentialsProvider{
+		roleName:    roleName,
+		metadataUrl: Default_Ecs_Metadata_Url,
+		client:      &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (p *EcsRamRoleCredentialsProvider) GetCredentials() (Credentials, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.credentials.AccessKey != "" && time.Now().Add(ecsRefreshAhead).Before(p.expiration) {
+		return p.credentials, nil
+	}
+	response, err := p.client.Get(p.metadataUrl + p.roleName)
+	if err != nil {
+		return Credentials{}, err
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return Credentials{}, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return Credentials{}, errors.New("[credentials] get ecs ram role credentials failed: " + string(body))
+	}
+	var result ecsRoleResult
+	if err = json.Unmarshal(body, &result); err != nil {
+		return Credentials{}, err
+	}
+	if !strings.EqualFold(result.Code, "Success") {
+		return Credentials{}, errors.New("[credentials] get ecs ram role credentials failed: " + string(body))
+	}
+	expiration, err := time.Parse(time.RFC3339, result.Expiration)
+	if err != nil {
+		return Credentials{}, err
+	}
+	p.credentials = Credentials{
+		AccessKey:     result.AccessKeyId,
+		SecretKey:     result.AccessKeySecret,
+		SecurityToken: result.SecurityToken,
+	}
+	p.expiration = expiration
+	return p.credentials, nil
+}
+
+// 依次尝试各个Provider，返回第一个成功获取的凭证
+type ChainCredentialsProvider struct {
+	providers []CredentialsProvider
+}
+
+func NewChainCredentialsProvider(providers ...CredentialsProvider) *ChainCredentialsProvider {
+	return &ChainCredentialsProvider{providers: providers}
+}
+
+func (p *ChainCredentialsProvider) GetCredentials() (Credentials, error) {
+	err := errors.New("[credentials] no credentials provider configured")
+	for _, provider := range p.providers {
+		var credentials Credentials
+		credentials, err = provider.GetCredentials()
+		if err == nil {
+			return credentials, nil
+		}
+	}
+	return Credentials{}, err
+}
@@ -0,0 +1,41 @@
+package credentials
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestChainCredentialsProvider(t *testing.T) {
+	chain := NewChainCredentialsProvider(NewStaticCredentialsProvider("", "", ""), NewStaticCredentialsProvider("ak", "sk", "token"))
+	credentials, err := chain.GetCredentials()
+	assert.Nil(t, err)
+	assert.Equal(t, Credentials{AccessKey: "ak", SecretKey: "sk", SecurityToken: "token"}, credentials)
+
+	_, err = NewChainCredentialsProvider().GetCredentials()
+	assert.NotNil(t, err)
+}
+
+func TestEcsRamRoleCredentialsProvider(t *testing.T) {
+	var requestCount int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requestCount, 1)
+		assert.Equal(t, "/nacos-role", r.URL.Path)
+		w.Write([]byte(`{"Code":"Success","AccessKeyId":"STS.ak","AccessKeySecret":"sk","SecurityToken":"token","Expiration":"` +
+			time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `"}`))
+	}))
+	defer server.Close()
+
+	provider := NewEcsRamRoleCredentialsProvider("nacos-role")
+	provider.metadataUrl = server.URL + "/"
+	credentials, err := provider.GetCredentials()
+	assert.Nil(t, err)
+	assert.Equal(t, Credentials{AccessKey: "STS.ak", SecretKey: "sk", SecurityToken: "token"}, credentials)
+
+	_, err = provider.GetCredentials()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&requestCount), "unexpired credentials should be cached")
+}
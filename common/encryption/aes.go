@@ -0,0 +1,70 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+)
+
+// 使用AES-GCM加密的插件，密文为base64编码的nonce+密文
+type AesPlugin struct {
+	aead cipher.AEAD
+}
+
+// secretKey为base64编码的16、24或32字节密钥，可通过GenerateSecretKey生成
+func NewAesPlugin(secretKey string) (*AesPlugin, error) {
+	key, err := base64.StdEncoding.DecodeString(secretKey)
+	if err != nil {
+		return nil, errors.New("[encryption] invalid aes secret key:" + err.Error())
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.New("[encryption] invalid aes secret key:" + err.Error())
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &AesPlugin{aead: aead}, nil
+}
+
+func (p *AesPlugin) Encrypt(content string) (string, error) {
+	nonce := make([]byte, p.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := p.aead.Seal(nonce, nonce, []byte(content), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (p *AesPlugin) Decrypt(content string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return "", errors.New("[encryption] aes decrypt failed:" + err.Error())
+	}
+	if len(data) < p.aead.NonceSize() {
+		return "", errors.New("[encryption] aes decrypt failed: content is too short")
+	}
+	nonceSize := p.aead.NonceSize()
+	plaintext, err := p.aead.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return "", errors.New("[encryption] aes decrypt failed:" + err.Error())
+	}
+	return string(plaintext), nil
+}
+
+// 生成base64编码的256位密钥
+func (p *AesPlugin) GenerateSecretKey() (string, error) {
+	return GenerateAesSecretKey()
+}
+
+func GenerateAesSecretKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
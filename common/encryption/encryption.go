@@ -0,0 +1,50 @@
+package encryption
+
+import (
+	"strings"
+	"sync"
+)
+
+// 加密配置的dataId前缀
+const Default_Cipher_Prefix = "cipher-"
+
+// 配置加密插件，dataId以注册的前缀开头的配置在发布时加密、读取时解密
+type Plugin interface {
+	Encrypt(content string) (string, error)
+	Decrypt(content string) (string, error)
+	// 生成一个可用于该插件的新密钥，用于初始化或轮换密钥
+	GenerateSecretKey() (string, error)
+}
+
+var (
+	mutex   sync.RWMutex
+	plugins = map[string]Plugin{}
+)
+
+// 按dataId前缀注册插件，同一前缀重复注册时覆盖
+func Register(prefix string, plugin Plugin) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	plugins[prefix] = plugin
+}
+
+func Unregister(prefix string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	delete(plugins, prefix)
+}
+
+// 返回与dataId匹配的最长前缀对应的插件
+func Find(dataId string) (Plugin, bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	var matched string
+	var plugin Plugin
+	for prefix, p := range plugins {
+		if strings.HasPrefix(dataId, prefix) && len(prefix) >= len(matched) {
+			matched = prefix
+			plugin = p
+		}
+	}
+	return plugin, plugin != nil
+}
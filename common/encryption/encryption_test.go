@@ -0,0 +1,47 @@
+package encryption
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAesPlugin(t *testing.T) {
+	secretKey, err := GenerateAesSecretKey()
+	assert.Nil(t, err)
+	plugin, err := NewAesPlugin(secretKey)
+	assert.Nil(t, err)
+
+	ciphertext, err := plugin.Encrypt("hello world")
+	assert.Nil(t, err)
+	assert.NotEqual(t, "hello world", ciphertext)
+	plaintext, err := plugin.Decrypt(ciphertext)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello world", plaintext)
+
+	other, _ := plugin.GenerateSecretKey()
+	otherPlugin, _ := NewAesPlugin(other)
+	_, err = otherPlugin.Decrypt(ciphertext)
+	assert.NotNil(t, err)
+
+	_, err = NewAesPlugin("short")
+	assert.NotNil(t, err)
+}
+
+func TestFind(t *testing.T) {
+	key, _ := GenerateAesSecretKey()
+	defaultPlugin, _ := NewAesPlugin(key)
+	customPlugin, _ := NewAesPlugin(key)
+	Register(Default_Cipher_Prefix, defaultPlugin)
+	Register("cipher-custom-", customPlugin)
+	defer Unregister(Default_Cipher_Prefix)
+	defer Unregister("cipher-custom-")
+
+	plugin, ok := Find("cipher-custom-app")
+	assert.True(t, ok)
+	assert.True(t, plugin == customPlugin)
+	plugin, ok = Find("cipher-app")
+	assert.True(t, ok)
+	assert.True(t, plugin == defaultPlugin)
+	_, ok = Find("app")
+	assert.False(t, ok)
+}
@@ -0,0 +1,63 @@
+package encryption
+
+import (
+	"errors"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/kms"
+)
+
+// 使用阿里云KMS加解密的插件，keyId为空时只能解密
+type KmsPlugin struct {
+	client *kms.Client
+	keyId  string
+}
+
+func NewKmsPlugin(client *kms.Client, keyId string) *KmsPlugin {
+	return &KmsPlugin{client: client, keyId: keyId}
+}
+
+func (p *KmsPlugin) Encrypt(content string) (string, error) {
+	if p.keyId == "" {
+		return "", errors.New("[encryption] kms key id is empty")
+	}
+	request := kms.CreateEncryptRequest()
+	request.Method = "POST"
+	request.Scheme = "https"
+	request.AcceptFormat = "json"
+	request.KeyId = p.keyId
+	request.Plaintext = content
+	response, err := p.client.Encrypt(request)
+	if err != nil {
+		return "", errors.New("[encryption] kms encrypt failed:" + err.Error())
+	}
+	return response.CiphertextBlob, nil
+}
+
+func (p *KmsPlugin) Decrypt(content string) (string, error) {
+	request := kms.CreateDecryptRequest()
+	request.Method = "POST"
+	request.Scheme = "https"
+	request.AcceptFormat = "json"
+	request.CiphertextBlob = content
+	response, err := p.client.Decrypt(request)
+	if err != nil {
+		return "", errors.New("[encryption] kms decrypt failed:" + err.Error())
+	}
+	return response.Plaintext, nil
+}
+
+// 通过KMS生成数据密钥，返回数据密钥的明文
+func (p *KmsPlugin) GenerateSecretKey() (string, error) {
+	if p.keyId == "" {
+		return "", errors.New("[encryption] kms key id is empty")
+	}
+	request := kms.CreateGenerateDataKeyRequest()
+	request.Method = "POST"
+	request.Scheme = "https"
+	request.AcceptFormat = "json"
+	request.KeyId = p.keyId
+	response, err := p.client.GenerateDataKey(request)
+	if err != nil {
+		return "", errors.New("[encryption] kms generate data key failed:" + err.Error())
+	}
+	return response.Plaintext, nil
+}
@@ -0,0 +1,181 @@
+package event
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"sync"
+)
+
+type EventType string
+
+const (
+	TYPE_SERVER_SWITCHED       EventType = "ServerSwitched"
+	TYPE_SERVER_UNHEALTHY      EventType = "ServerUnhealthy"
+	TYPE_PUSH_RECEIVED         EventType = "PushReceived"
+	TYPE_CACHE_LOADED          EventType = "CacheLoaded"
+	TYPE_HEARTBEAT_FAILED      EventType = "HeartbeatFailed"
+	TYPE_INSTANCE_REREGISTERED EventType = "InstanceReregistered"
+	TYPE_CONFIG_REJECTED       EventType = "ConfigRejected"
+	TYPE_SERVER_RECONNECTED    EventType = "ServerReconnected"
+)
+
+type Event interface {
+	Type() EventType
+}
+
+// 请求From失败后，重试切换到了To
+type ServerSwitchedEvent struct {
+	From string
+	To   string
+	Err  error
+}
+
+func (e ServerSwitchedEvent) Type() EventType { return TYPE_SERVER_SWITCHED }
+
+// 服务端连续失败Failures次，被标记为不健康
+type ServerUnhealthyEvent struct {
+	Address  string
+	Failures int
+}
+
+func (e ServerUnhealthyEvent) Type() EventType { return TYPE_SERVER_UNHEALTHY }
+
+// 请求在所有重试后仍失败，之后再次成功请求了Address
+type ServerReconnectedEvent struct {
+	Address string
+}
+
+func (e ServerReconnectedEvent) Type() EventType { return TYPE_SERVER_RECONNECTED }
+
+// 收到服务端的UDP推送，ServiceName和Clusters仅在推送服务变化时有值
+type PushReceivedEvent struct {
+	Namespace   string
+	PushType    string
+	ServiceName string
+	Clusters    string
+	From        string
+}
+
+func (e PushReceivedEvent) Type() EventType { return TYPE_PUSH_RECEIVED }
+
+// 启动时从磁盘缓存加载了Services个服务
+type CacheLoadedEvent struct {
+	Namespace string
+	CacheDir  string
+	Services  int
+}
+
+func (e CacheLoadedEvent) Type() EventType { return TYPE_CACHE_LOADED }
+
+// 实例心跳失败，Failures为连续失败次数
+type HeartbeatFailedEvent struct {
+	Namespace   string
+	ServiceName string
+	Ip          string
+	Port        uint64
+	Failures    int
+	Err         error
+}
+
+func (e HeartbeatFailedEvent) Type() EventType { return TYPE_HEARTBEAT_FAILED }
+
+// 服务端找不到发送心跳的实例时重新注册，Err为重新注册的结果
+type InstanceReregisteredEvent struct {
+	Namespace   string
+	ServiceName string
+	Ip          string
+	Port        uint64
+	Err         error
+}
+
+func (e InstanceReregisteredEvent) Type() EventType { return TYPE_INSTANCE_REREGISTERED }
+
+// 监听的配置变化后未通过ConfigParam.Validate校验，客户端继续使用上次校验通过的内容
+type ConfigRejectedEvent struct {
+	Namespace string
+	Group     string
+	DataId    string
+	Md5       string
+	Err       error
+}
+
+func (e ConfigRejectedEvent) Type() EventType { return TYPE_CONFIG_REJECTED }
+
+type Listener func(e Event)
+
+type subscription struct {
+	id       int64
+	listener Listener
+	types    map[EventType]bool
+}
+
+// SDK生命周期事件的总线，事件在产生事件的协程中按订阅顺序同步通知，监听者不应阻塞
+type EventBus struct {
+	mutex         sync.RWMutex
+	subscriptions []subscription
+	nextId        int64
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// 订阅事件，types为空时订阅所有类型，返回的id用于取消订阅
+func (b *EventBus) Subscribe(listener Listener, types ...EventType) int64 {
+	if b == nil || listener == nil {
+		return 0
+	}
+	sub := subscription{listener: listener}
+	if len(types) > 0 {
+		sub.types = map[EventType]bool{}
+		for _, t := range types {
+			sub.types[t] = true
+		}
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.nextId++
+	sub.id = b.nextId
+	subscriptions := make([]subscription, 0, len(b.subscriptions)+1)
+	subscriptions = append(subscriptions, b.subscriptions...)
+	b.subscriptions = append(subscriptions, sub)
+	return sub.id
+}
+
+func (b *EventBus) Unsubscribe(id int64) {
+	if b == nil {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	subscriptions := make([]subscription, 0, len(b.subscriptions))
+	for _, sub := range b.subscriptions {
+		if sub.id != id {
+			subscriptions = append(subscriptions, sub)
+		}
+	}
+	b.subscriptions = subscriptions
+}
+
+// 通知订阅了该类型的监听者，监听者panic时只记录日志
+func (b *EventBus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	b.mutex.RLock()
+	subscriptions := b.subscriptions
+	b.mutex.RUnlock()
+	for _, sub := range subscriptions {
+		if sub.types == nil || sub.types[e.Type()] {
+			notify(sub.listener, e)
+		}
+	}
+}
+
+func notify(listener Listener, e Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("event listener panic, event:%s, err:%v", e.Type(), r)
+		}
+	}()
+	listener(e)
+}
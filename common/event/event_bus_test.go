@@ -0,0 +1,41 @@
+package event
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEventBus_Subscribe(t *testing.T) {
+	bus := NewEventBus()
+	var all, heartbeats []Event
+	allId := bus.Subscribe(func(e Event) { all = append(all, e) })
+	bus.Subscribe(func(e Event) { heartbeats = append(heartbeats, e) }, TYPE_HEARTBEAT_FAILED)
+
+	bus.Publish(ServerUnhealthyEvent{Address: "127.0.0.1:8848", Failures: 3})
+	bus.Publish(HeartbeatFailedEvent{ServiceName: "DEFAULT_GROUP@@demo", Failures: 1, Err: errors.New("timeout")})
+	assert.Equal(t, 2, len(all))
+	assert.Equal(t, 1, len(heartbeats))
+	assert.Equal(t, TYPE_HEARTBEAT_FAILED, heartbeats[0].Type())
+
+	bus.Unsubscribe(allId)
+	bus.Publish(HeartbeatFailedEvent{ServiceName: "DEFAULT_GROUP@@demo", Failures: 2})
+	assert.Equal(t, 2, len(all))
+	assert.Equal(t, 2, len(heartbeats))
+}
+
+func TestEventBus_ListenerPanic(t *testing.T) {
+	bus := NewEventBus()
+	var received int
+	bus.Subscribe(func(e Event) { panic("listener panic") })
+	bus.Subscribe(func(e Event) { received++ })
+	bus.Publish(CacheLoadedEvent{Services: 1})
+	assert.Equal(t, 1, received)
+}
+
+func TestEventBus_Nil(t *testing.T) {
+	var bus *EventBus
+	assert.Equal(t, int64(0), bus.Subscribe(func(e Event) {}))
+	bus.Unsubscribe(1)
+	bus.Publish(CacheLoadedEvent{})
+}
@@ -0,0 +1,199 @@
+package health_check
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	nsema "github.com/toolkits/concurrent/semaphore"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	TYPE_TCP  = "TCP"
+	TYPE_HTTP = "HTTP"
+
+	Default_Interval_Ms = 5 * 1000
+	Default_Timeout_Ms  = 2 * 1000
+	Default_Fall        = 3
+	Default_Rise        = 2
+	Default_Concurrency = 16
+)
+
+// 客户端主动健康检查的配置
+// Type：TCP只检查端口能否连接，HTTP请求http://ip:port+Path，状态码小于400为成功，为空时使用TCP
+// Fall：连续失败Fall次后标记为不健康；Rise：不健康的实例连续成功Rise次后恢复健康
+// Concurrency：同时进行的检查数上限
+// 未设置的字段使用对应的默认值
+type HealthCheckConfig struct {
+	Type        string
+	Path        string
+	IntervalMs  uint64
+	TimeoutMs   uint64
+	Fall        int
+	Rise        int
+	Concurrency int
+}
+
+type checkState struct {
+	healthy   bool
+	successes int
+	failures  int
+}
+
+// 定期检查targets返回的地址，记录各地址在本地的健康状态
+type HealthChecker struct {
+	config   HealthCheckConfig
+	targets  func() []string
+	client   *http.Client
+	mutex    sync.RWMutex
+	states   map[string]*checkState
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+// 创建后立即在后台开始检查，targets返回需要检查的地址，见Address
+func NewHealthChecker(config HealthCheckConfig, targets func() []string) *HealthChecker {
+	if config.Type == "" {
+		config.Type = TYPE_TCP
+	}
+	if config.IntervalMs == 0 {
+		config.IntervalMs = Default_Interval_Ms
+	}
+	if config.TimeoutMs == 0 {
+		config.TimeoutMs = Default_Timeout_Ms
+	}
+	if config.Fall <= 0 {
+		config.Fall = Default_Fall
+	}
+	if config.Rise <= 0 {
+		config.Rise = Default_Rise
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = Default_Concurrency
+	}
+	hc := &HealthChecker{
+		config:   config,
+		targets:  targets,
+		client:   &http.Client{Timeout: time.Duration(config.TimeoutMs) * time.Millisecond},
+		states:   map[string]*checkState{},
+		stopChan: make(chan struct{}),
+	}
+	go hc.run()
+	return hc
+}
+
+// 未检查过的地址视为健康
+func (hc *HealthChecker) Healthy(ip string, port uint64) bool {
+	hc.mutex.RLock()
+	defer hc.mutex.RUnlock()
+	state, ok := hc.states[Address(ip, port)]
+	return !ok || state.healthy
+}
+
+func (hc *HealthChecker) Stop() {
+	hc.stopOnce.Do(func() {
+		close(hc.stopChan)
+	})
+}
+
+func (hc *HealthChecker) run() {
+	ticker := time.NewTicker(time.Duration(hc.config.IntervalMs) * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		hc.checkAll()
+		select {
+		case <-hc.stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// 检查所有地址，不再需要检查的地址的状态被移除
+func (hc *HealthChecker) checkAll() {
+	targets := hc.targets()
+	current := make(map[string]bool, len(targets))
+	sema := nsema.NewSemaphore(hc.config.Concurrency)
+	var wg sync.WaitGroup
+	for _, target := range targets {
+		if current[target] {
+			continue
+		}
+		current[target] = true
+		sema.Acquire()
+		wg.Add(1)
+		go func(target string) {
+			defer wg.Done()
+			defer sema.Release()
+			hc.record(target, hc.check(target))
+		}(target)
+	}
+	wg.Wait()
+	hc.mutex.Lock()
+	for target := range hc.states {
+		if !current[target] {
+			delete(hc.states, target)
+		}
+	}
+	hc.mutex.Unlock()
+}
+
+func (hc *HealthChecker) check(target string) error {
+	timeout := time.Duration(hc.config.TimeoutMs) * time.Millisecond
+	if hc.config.Type != TYPE_HTTP {
+		conn, err := net.DialTimeout("tcp", target, timeout)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+	response, err := hc.client.Get("http://" + target + hc.config.Path)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	if response.StatusCode >= http.StatusBadRequest {
+		return &statusError{statusCode: response.StatusCode}
+	}
+	return nil
+}
+
+func (hc *HealthChecker) record(target string, err error) {
+	hc.mutex.Lock()
+	defer hc.mutex.Unlock()
+	state, ok := hc.states[target]
+	if !ok {
+		state = &checkState{healthy: true}
+		hc.states[target] = state
+	}
+	if err == nil {
+		state.failures = 0
+		state.successes++
+		if !state.healthy && state.successes >= hc.config.Rise {
+			state.healthy = true
+			logger.Infof("[health_check] %s becomes healthy", target)
+		}
+		return
+	}
+	state.successes = 0
+	state.failures++
+	if state.healthy && state.failures >= hc.config.Fall {
+		state.healthy = false
+		logger.Warnf("[health_check] %s becomes unhealthy after %d failures, err:%s", target, state.failures, err.Error())
+	}
+}
+
+type statusError struct {
+	statusCode int
+}
+
+func (e *statusError) Error() string {
+	return "unexpected status code:" + strconv.Itoa(e.statusCode)
+}
+
+// 检查的地址格式，targets应返回该格式的地址
+func Address(ip string, port uint64) string {
+	return net.JoinHostPort(ip, strconv.FormatUint(port, 10))
+}
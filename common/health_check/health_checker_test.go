@@ -0,0 +1,87 @@
+package health_check
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHealthChecker_FallRise(t *testing.T) {
+	hc := &HealthChecker{config: HealthCheckConfig{Fall: 2, Rise: 2}, states: map[string]*checkState{}}
+	target := Address("10.0.0.1", 80)
+	assert.True(t, hc.Healthy("10.0.0.1", 80))
+
+	failure := errors.New("connection refused")
+	hc.record(target, failure)
+	assert.True(t, hc.Healthy("10.0.0.1", 80))
+	hc.record(target, failure)
+	assert.False(t, hc.Healthy("10.0.0.1", 80))
+
+	hc.record(target, nil)
+	assert.False(t, hc.Healthy("10.0.0.1", 80))
+	hc.record(target, failure)
+	hc.record(target, nil)
+	hc.record(target, nil)
+	assert.True(t, hc.Healthy("10.0.0.1", 80))
+}
+
+func TestHealthChecker_TCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+	// 取一个已关闭的端口
+	closed, _ := net.Listen("tcp", "127.0.0.1:0")
+	closedAddr := closed.Addr().(*net.TCPAddr)
+	closed.Close()
+	openAddr := listener.Addr().(*net.TCPAddr)
+
+	hc := NewHealthChecker(HealthCheckConfig{IntervalMs: 10, TimeoutMs: 500, Fall: 1}, func() []string {
+		return []string{listener.Addr().String(), closedAddr.String()}
+	})
+	defer hc.Stop()
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, hc.Healthy("127.0.0.1", uint64(openAddr.Port)))
+	assert.False(t, hc.Healthy("127.0.0.1", uint64(closedAddr.Port)))
+}
+
+func TestHealthChecker_HTTP(t *testing.T) {
+	var status int32 = http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/health", r.URL.Path)
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	port, _ := strconv.ParseUint(u.Port(), 10, 64)
+
+	var targets atomic.Value
+	targets.Store([]string{u.Host})
+	hc := NewHealthChecker(HealthCheckConfig{Type: TYPE_HTTP, Path: "/health", IntervalMs: 10, Fall: 2, Rise: 1}, func() []string {
+		return targets.Load().([]string)
+	})
+	defer hc.Stop()
+	time.Sleep(50 * time.Millisecond)
+	assert.True(t, hc.Healthy("127.0.0.1", port))
+
+	atomic.StoreInt32(&status, http.StatusServiceUnavailable)
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, hc.Healthy("127.0.0.1", port))
+
+	atomic.StoreInt32(&status, http.StatusOK)
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, hc.Healthy("127.0.0.1", port))
+
+	// 不再检查的地址的状态被移除
+	atomic.StoreInt32(&status, http.StatusServiceUnavailable)
+	time.Sleep(100 * time.Millisecond)
+	targets.Store([]string{})
+	time.Sleep(50 * time.Millisecond)
+	assert.True(t, hc.Healthy("127.0.0.1", port))
+}
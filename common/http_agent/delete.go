@@ -1,6 +1,7 @@
 package http_agent
 
 import (
+	"context"
 	"net/http"
 	"strings"
 	"time"
@@ -15,7 +16,7 @@ import (
 * @create : 2019-01-08 14:08
 **/
 
-func delete(path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
+func delete(ctx context.Context, transport http.RoundTripper, path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
 	if !strings.HasSuffix(path, "?") {
 		path = path + "?"
 	}
@@ -25,15 +26,15 @@ func delete(path string, header http.Header, timeoutMs uint64, params map[string
 	if strings.HasSuffix(path, "&") {
 		path = path[:len(path)-1]
 	}
-	client := http.Client{}
+	client := http.Client{Transport: transport}
 	client.Timeout = time.Millisecond * time.Duration(timeoutMs)
-	request, errNew := http.NewRequest(http.MethodDelete, path, nil)
+	request, errNew := http.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
 	if errNew != nil {
 		err = errNew
 		return
 	}
 	request.Header = header
-	resp, errDo := client.Do(request)
+	resp, errDo := do(&client, request)
 	if errDo != nil {
 		err = errDo
 	} else {
@@ -0,0 +1,49 @@
+package http_agent
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// 为每个请求附加固定的请求头和URL参数，用于经过网关访问nacos时网关要求的鉴权头、租户标识等
+// 请求自身已有的同名请求头和参数不会被覆盖
+type extraTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+	params  map[string]string
+}
+
+func newExtraTransport(base http.RoundTripper, headers map[string]string, params map[string]string) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &extraTransport{base: base, headers: headers, params: params}
+}
+
+func (t *extraTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	request = request.Clone(request.Context())
+	if request.Header == nil {
+		request.Header = http.Header{}
+	}
+	for k, v := range t.headers {
+		if request.Header.Get(k) == "" {
+			request.Header.Set(k, v)
+		}
+	}
+	if len(t.params) > 0 {
+		query := request.URL.Query()
+		extra := url.Values{}
+		for k, v := range t.params {
+			if _, ok := query[k]; !ok {
+				extra.Set(k, v)
+			}
+		}
+		if len(extra) > 0 {
+			if request.URL.RawQuery != "" {
+				request.URL.RawQuery += "&"
+			}
+			request.URL.RawQuery += extra.Encode()
+		}
+	}
+	return t.base.RoundTrip(request)
+}
@@ -1,11 +1,18 @@
 package http_agent
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"github.com/go-errors/errors"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
 	"github.com/nacos-group/nacos-sdk-go/utils"
 	"io/ioutil"
-	"log"
+	"net"
 	"net/http"
+	"time"
 )
 
 /**
@@ -17,11 +24,102 @@ import (
 * @create : 2019-01-10 11:26
 **/
 type HttpAgent struct {
+	transport http.RoundTripper
+}
+
+// 按TLS配置创建HttpAgent，未开启TLS时使用默认的Transport
+func NewHttpAgent(tlsConfig constant.TLSConfig) (*HttpAgent, error) {
+	if !tlsConfig.Enable {
+		return &HttpAgent{}, nil
+	}
+	config, err := NewTLSConfig(tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = config
+	return &HttpAgent{transport: transport}, nil
+}
+
+// 按ClientConfig创建HttpAgent，除TLS配置外，每个请求附加ExtraHeaders中的请求头和ExtraParams中的URL参数
+// 配置了Agent.UnixSocket时所有请求都通过该unix socket发送，配置了Timeout.ConnectTimeoutMs时限制建立连接的时间
+func NewHttpAgentWithConfig(clientConfig constant.ClientConfig) (*HttpAgent, error) {
+	agent, err := NewHttpAgent(clientConfig.TLSConfig)
+	if err != nil {
+		return nil, err
+	}
+	var connectTimeout time.Duration
+	if clientConfig.Timeout != nil {
+		connectTimeout = time.Duration(clientConfig.Timeout.ConnectTimeoutMs) * time.Millisecond
+	}
+	if clientConfig.Agent != nil && clientConfig.Agent.UnixSocket != "" {
+		agent.transport = newUnixTransport(agent.transport, clientConfig.Agent.UnixSocket, connectTimeout)
+	} else if connectTimeout > 0 {
+		agent.transport = newConnectTimeoutTransport(agent.transport, connectTimeout)
+	}
+	if len(clientConfig.ExtraHeaders) > 0 || len(clientConfig.ExtraParams) > 0 {
+		agent.transport = newExtraTransport(agent.transport, clientConfig.ExtraHeaders, clientConfig.ExtraParams)
+	}
+	return agent, nil
+}
+
+func cloneTransport(base http.RoundTripper) *http.Transport {
+	if t, ok := base.(*http.Transport); ok {
+		return t.Clone()
+	}
+	return http.DefaultTransport.(*http.Transport).Clone()
+}
+
+// 忽略请求地址，所有连接都建立到unix socket
+func newUnixTransport(base http.RoundTripper, socket string, connectTimeout time.Duration) http.RoundTripper {
+	transport := cloneTransport(base)
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialer := net.Dialer{Timeout: connectTimeout}
+		return dialer.DialContext(ctx, "unix", socket)
+	}
+	return transport
+}
+
+// TCP连接和TLS握手分别不超过connectTimeout
+func newConnectTimeoutTransport(base http.RoundTripper, connectTimeout time.Duration) http.RoundTripper {
+	transport := cloneTransport(base)
+	dialer := &net.Dialer{Timeout: connectTimeout, KeepAlive: 30 * time.Second}
+	transport.DialContext = dialer.DialContext
+	transport.TLSHandshakeTimeout = connectTimeout
+	return transport
+}
+
+// CaFile为空时使用系统CA，同时配置CertFile和KeyFile时启用双向认证，长连接与HTTP请求使用相同的TLS配置
+func NewTLSConfig(tlsConfig constant.TLSConfig) (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: tlsConfig.InsecureSkipVerify,
+		ServerName:         tlsConfig.ServerName,
+	}
+	if tlsConfig.CaFile != "" {
+		ca, err := ioutil.ReadFile(tlsConfig.CaFile)
+		if err != nil {
+			return nil, errors.New("[http_agent] read ca file failed:" + err.Error())
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, errors.New("[http_agent] no valid certificate in ca file:" + tlsConfig.CaFile)
+		}
+		config.RootCAs = pool
+	}
+	if tlsConfig.CertFile != "" || tlsConfig.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(tlsConfig.CertFile, tlsConfig.KeyFile)
+		if err != nil {
+			return nil, errors.New("[http_agent] load client certificate failed:" + err.Error())
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
 }
 
 func (agent *HttpAgent) Get(path string, header http.Header, timeoutMs uint64,
 	params map[string]string) (response *http.Response, err error) {
-	return get(path, header, timeoutMs, params)
+	return get(context.Background(), agent.transport, path, header, timeoutMs, params)
 }
 
 func (agent *HttpAgent) RequestOnlyResult(method string, path string, header http.Header, timeoutMs uint64, params map[string]string) string {
@@ -41,20 +139,20 @@ func (agent *HttpAgent) RequestOnlyResult(method string, path string, header htt
 		response, err = agent.Delete(path, header, timeoutMs, params)
 		break
 	default:
-		log.Printf("[ERROR]:request method[%s], path[%s],header:[%s],params:[%s], not avaliable method ", method, path, utils.ToJsonString(header), utils.ToJsonString(params))
+		logger.Errorf("request method[%s], path[%s],header:[%s],params:[%s], not avaliable method ", method, path, utils.ToJsonString(header), utils.ToJsonString(params))
 	}
 	if err != nil {
-		log.Printf("[ERROR]:request method[%s],request path[%s],header:[%s],params:[%s],err:%s", method, path, utils.ToJsonString(header), utils.ToJsonString(params), err.Error())
+		logger.Errorf("request method[%s],request path[%s],header:[%s],params:[%s],err:%s", method, path, utils.ToJsonString(header), utils.ToJsonString(params), err.Error())
 		return ""
 	}
 	if response.StatusCode != 200 {
-		log.Printf("[ERROR]:request method[%s],request path[%s],header:[%s],params:[%s],status code error:%d", method, path, utils.ToJsonString(header), utils.ToJsonString(params), response.StatusCode)
+		logger.Errorf("request method[%s],request path[%s],header:[%s],params:[%s],status code error:%d", method, path, utils.ToJsonString(header), utils.ToJsonString(params), response.StatusCode)
 		return ""
 	}
 	bytes, errRead := ioutil.ReadAll(response.Body)
 	defer response.Body.Close()
 	if errRead != nil {
-		log.Printf("[ERROR]:request method[%s],request path[%s],header:[%s],params:[%s],read error:%s", method, path, utils.ToJsonString(header), utils.ToJsonString(params), errRead.Error())
+		logger.Errorf("request method[%s],request path[%s],header:[%s],params:[%s],read error:%s", method, path, utils.ToJsonString(header), utils.ToJsonString(params), errRead.Error())
 		return ""
 	}
 	return string(bytes)
@@ -62,34 +160,50 @@ func (agent *HttpAgent) RequestOnlyResult(method string, path string, header htt
 }
 
 func (agent *HttpAgent) Request(method string, path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
+	return agent.RequestWithContext(context.Background(), method, path, header, timeoutMs, params)
+}
+
+func (agent *HttpAgent) RequestWithContext(ctx context.Context, method string, path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
 	switch method {
 	case http.MethodGet:
-		response, err = agent.Get(path, header, timeoutMs, params)
+		response, err = get(ctx, agent.transport, path, header, timeoutMs, params)
 		return
 	case http.MethodPost:
-		response, err = agent.Post(path, header, timeoutMs, params)
+		response, err = post(ctx, agent.transport, path, header, timeoutMs, params)
 		return
 	case http.MethodPut:
-		response, err = agent.Put(path, header, timeoutMs, params)
+		response, err = put(ctx, agent.transport, path, header, timeoutMs, params)
 		return
 	case http.MethodDelete:
-		response, err = agent.Delete(path, header, timeoutMs, params)
+		response, err = delete(ctx, agent.transport, path, header, timeoutMs, params)
 		return
 	default:
 		err = errors.New("not avaliable method")
-		log.Printf("[ERROR]:request method[%s], path[%s],header:[%s],params:[%s], not avaliable method ", method, path, utils.ToJsonString(header), utils.ToJsonString(params))
+		logger.Errorf("request method[%s], path[%s],header:[%s],params:[%s], not avaliable method ", method, path, utils.ToJsonString(header), utils.ToJsonString(params))
 	}
 	return
 }
+
+func (agent *HttpAgent) RequestWithBody(ctx context.Context, method string, path string, header http.Header, timeoutMs uint64, body []byte) (response *http.Response, err error) {
+	client := http.Client{Transport: agent.transport}
+	client.Timeout = time.Millisecond * time.Duration(timeoutMs)
+	request, err := http.NewRequestWithContext(ctx, method, path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header = header
+	return do(&client, request)
+}
+
 func (agent *HttpAgent) Post(path string, header http.Header, timeoutMs uint64,
 	params map[string]string) (response *http.Response, err error) {
-	return post(path, header, timeoutMs, params)
+	return post(context.Background(), agent.transport, path, header, timeoutMs, params)
 }
 func (agent *HttpAgent) Delete(path string, header http.Header, timeoutMs uint64,
 	params map[string]string) (response *http.Response, err error) {
-	return delete(path, header, timeoutMs, params)
+	return delete(context.Background(), agent.transport, path, header, timeoutMs, params)
 }
 func (agent *HttpAgent) Put(path string, header http.Header, timeoutMs uint64,
 	params map[string]string) (response *http.Response, err error) {
-	return put(path, header, timeoutMs, params)
+	return put(context.Background(), agent.transport, path, header, timeoutMs, params)
 }
@@ -1,6 +1,9 @@
 package http_agent
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+)
 
 /**
 *
@@ -20,4 +23,7 @@ type IHttpAgent interface {
 	Put(path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error)
 	RequestOnlyResult(method string, path string, header http.Header, timeoutMs uint64, params map[string]string) string
 	Request(method string, path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error)
+	RequestWithContext(ctx context.Context, method string, path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error)
+	// 以body作为请求体发送请求，用于上传文件等无法用表单参数表示的请求，参数需拼接在path中
+	RequestWithBody(ctx context.Context, method string, path string, header http.Header, timeoutMs uint64, body []byte) (response *http.Response, err error)
 }
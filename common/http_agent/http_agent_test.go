@@ -0,0 +1,117 @@
+package http_agent
+
+import (
+	"context"
+	"encoding/pem"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestNewHttpAgent_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	caFile, err := ioutil.TempFile("", "nacos-ca")
+	assert.Nil(t, err)
+	defer os.Remove(caFile.Name())
+	pem.Encode(caFile, &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	caFile.Close()
+
+	// 未信任服务端证书时请求失败
+	_, err = (&HttpAgent{}).Get(server.URL, http.Header{}, 3000, nil)
+	assert.NotNil(t, err)
+
+	agent, err := NewHttpAgent(constant.TLSConfig{Enable: true, CaFile: caFile.Name()})
+	assert.Nil(t, err)
+	assert.Equal(t, "ok", agent.RequestOnlyResult(http.MethodGet, server.URL, http.Header{}, 3000, nil))
+}
+
+func TestNewHttpAgent_InvalidTLSConfig(t *testing.T) {
+	_, err := NewHttpAgent(constant.TLSConfig{Enable: true, CaFile: "/not/exist/ca.pem"})
+	assert.NotNil(t, err)
+
+	_, err = NewHttpAgent(constant.TLSConfig{Enable: true, CertFile: "/not/exist/client.pem"})
+	assert.NotNil(t, err)
+
+	agent, err := NewHttpAgent(constant.TLSConfig{CaFile: "/not/exist/ca.pem"})
+	assert.Nil(t, err)
+	assert.Nil(t, agent.transport)
+}
+
+func TestHttpAgent_RequestWithBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "true", r.URL.Query().Get("import"))
+		assert.Equal(t, "application/octet-stream", r.Header.Get("Content-Type"))
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	header := http.Header{"Content-Type": []string{"application/octet-stream"}}
+	response, err := (&HttpAgent{}).RequestWithBody(context.Background(), http.MethodPost, server.URL+"?import=true", header, 3000, []byte("content"))
+	assert.Nil(t, err)
+	defer response.Body.Close()
+	body, _ := ioutil.ReadAll(response.Body)
+	assert.Equal(t, "content", string(body))
+}
+
+func TestNewHttpAgentWithConfig_Extra(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.Equal(t, "own", r.Header.Get("X-Tenant"), "should not override request header")
+		assert.Equal(t, "gw", r.URL.Query().Get("route"))
+		assert.Equal(t, "a@@b", r.URL.Query().Get("serviceName"))
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	agent, err := NewHttpAgentWithConfig(constant.ClientConfig{
+		ExtraHeaders: map[string]string{"Authorization": "Bearer token", "X-Tenant": "extra"},
+		ExtraParams:  map[string]string{"route": "gw"},
+	})
+	assert.Nil(t, err)
+	header := http.Header{"X-Tenant": []string{"own"}}
+	assert.Equal(t, "ok", agent.RequestOnlyResult(http.MethodGet, server.URL, header, 3000, map[string]string{"serviceName": "a@@b"}))
+	assert.Equal(t, []string{"own"}, header["X-Tenant"], "should not modify the request header")
+}
+
+func TestNewHttpAgentWithConfig_UnixSocket(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "nacos-agent")
+	defer os.RemoveAll(dir)
+	socket := dir + "/nacos.sock"
+	listener, err := net.Listen("unix", socket)
+	assert.Nil(t, err)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Path))
+	}))
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	agent, err := NewHttpAgentWithConfig(constant.ClientConfig{Agent: &constant.AgentConfig{UnixSocket: socket}})
+	assert.Nil(t, err)
+	assert.Equal(t, "/nacos/v1/ns/instance/list", agent.RequestOnlyResult(http.MethodGet, "http://nacos-agent:80/nacos/v1/ns/instance/list", http.Header{}, 3000, nil))
+}
+
+func TestNewHttpAgentWithConfig_ConnectTimeout(t *testing.T) {
+	agent, err := NewHttpAgentWithConfig(constant.ClientConfig{Timeout: &constant.TimeoutConfig{ConnectTimeoutMs: 200}})
+	assert.Nil(t, err)
+	transport := agent.transport.(*http.Transport)
+	assert.Equal(t, 200*time.Millisecond, transport.TLSHandshakeTimeout)
+
+	// 不可路由的地址，连接超时先于请求超时返回
+	start := time.Now()
+	_, err = agent.Get("http://10.255.255.1:8848/nacos", http.Header{}, 5000, nil)
+	assert.NotNil(t, err)
+	assert.True(t, time.Since(start) < 3*time.Second)
+}
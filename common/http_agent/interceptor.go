@@ -0,0 +1,62 @@
+package http_agent
+
+import (
+	"net/http"
+	"sync"
+)
+
+type interceptor struct {
+	id         int64
+	onRequest  func(request *http.Request)
+	onResponse func(response *http.Response)
+}
+
+var (
+	interceptorMutex sync.RWMutex
+	interceptors     []interceptor
+	lastId           int64
+)
+
+// 注册请求拦截器，对HttpAgent发出的所有naming和config请求生效，可用于添加请求头、记录审计日志等
+// onRequest在请求发送前按注册顺序调用，可修改请求；onResponse在收到响应后按注册顺序调用，请求失败时不调用
+// onRequest和onResponse均可为nil，返回的id用于UnregisterInterceptor
+func RegisterInterceptor(onRequest func(request *http.Request), onResponse func(response *http.Response)) int64 {
+	interceptorMutex.Lock()
+	defer interceptorMutex.Unlock()
+	lastId++
+	interceptors = append(interceptors, interceptor{id: lastId, onRequest: onRequest, onResponse: onResponse})
+	return lastId
+}
+
+func UnregisterInterceptor(id int64) {
+	interceptorMutex.Lock()
+	defer interceptorMutex.Unlock()
+	for i, ic := range interceptors {
+		if ic.id == id {
+			interceptors = append(interceptors[:i:i], interceptors[i+1:]...)
+			return
+		}
+	}
+}
+
+// 发送请求并依次调用拦截器，响应的Request字段为实际发送的请求
+func do(client *http.Client, request *http.Request) (*http.Response, error) {
+	interceptorMutex.RLock()
+	current := interceptors
+	interceptorMutex.RUnlock()
+	for _, ic := range current {
+		if ic.onRequest != nil {
+			ic.onRequest(request)
+		}
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	for _, ic := range current {
+		if ic.onResponse != nil {
+			ic.onResponse(response)
+		}
+	}
+	return response, nil
+}
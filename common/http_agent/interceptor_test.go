@@ -0,0 +1,56 @@
+package http_agent
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRegisterInterceptor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trace-Id", r.Header.Get("Trace-Id"))
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var requests []string
+	var traceIds []string
+	id := RegisterInterceptor(func(request *http.Request) {
+		request.Header.Set("Trace-Id", "trace-1")
+		requests = append(requests, request.Method)
+	}, func(response *http.Response) {
+		traceIds = append(traceIds, response.Header.Get("Trace-Id"))
+	})
+	onlyRequest := RegisterInterceptor(func(request *http.Request) {
+		// 后注册的拦截器可以看到之前拦截器的修改
+		assert.Equal(t, "trace-1", request.Header.Get("Trace-Id"))
+	}, nil)
+
+	agent := &HttpAgent{}
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete} {
+		response, err := agent.RequestWithContext(context.Background(), method, server.URL, http.Header{}, 3000, map[string]string{"k": "v"})
+		assert.Nil(t, err)
+		response.Body.Close()
+	}
+	response, err := agent.RequestWithBody(context.Background(), http.MethodPost, server.URL, http.Header{}, 3000, []byte("body"))
+	assert.Nil(t, err)
+	response.Body.Close()
+	assert.Equal(t, []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodPost}, requests)
+	assert.Equal(t, []string{"trace-1", "trace-1", "trace-1", "trace-1", "trace-1"}, traceIds)
+
+	// 请求失败时不调用响应拦截器
+	_, err = agent.Get("http://127.0.0.1:1", http.Header{}, 1000, nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, 6, len(requests))
+	assert.Equal(t, 5, len(traceIds))
+
+	UnregisterInterceptor(id)
+	UnregisterInterceptor(onlyRequest)
+	response, err = agent.Get(server.URL, http.Header{}, 3000, nil)
+	assert.Nil(t, err)
+	response.Body.Close()
+	assert.Equal(t, 6, len(requests))
+	assert.Equal(t, "", response.Header.Get("Trace-Id"))
+}
@@ -1,6 +1,7 @@
 package http_agent
 
 import (
+	"context"
 	"net/http"
 	"strings"
 	"time"
@@ -15,8 +16,8 @@ import (
 * @create : 2019-01-07 15:13
 **/
 
-func post(path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
-	client := http.Client{}
+func post(ctx context.Context, transport http.RoundTripper, path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
+	client := http.Client{Transport: transport}
 	client.Timeout = time.Millisecond * time.Duration(timeoutMs)
 	var body string
 	for key, value := range params {
@@ -27,13 +28,13 @@ func post(path string, header http.Header, timeoutMs uint64, params map[string]s
 	if strings.HasSuffix(body, "&") {
 		body = body[:len(body)-1]
 	}
-	request, errNew := http.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	request, errNew := http.NewRequestWithContext(ctx, http.MethodPost, path, strings.NewReader(body))
 	if errNew != nil {
 		err = errNew
 		return
 	}
 	request.Header = header
-	resp, errDo := client.Do(request)
+	resp, errDo := do(&client, request)
 	if errDo != nil {
 		err = errDo
 	} else {
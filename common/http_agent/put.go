@@ -1,7 +1,8 @@
 package http_agent
 
 import (
-	"log"
+	"context"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
 	"net/http"
 	"strings"
 	"time"
@@ -16,8 +17,8 @@ import (
 * @create : 2019-01-09 11:24
 **/
 
-func put(path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
-	client := http.Client{}
+func put(ctx context.Context, transport http.RoundTripper, path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
+	client := http.Client{Transport: transport}
 	client.Timeout = time.Millisecond * time.Duration(timeoutMs)
 	var body string
 	for key, value := range params {
@@ -28,15 +29,15 @@ func put(path string, header http.Header, timeoutMs uint64, params map[string]st
 	if strings.HasSuffix(body, "&") {
 		body = body[:len(body)-1]
 	}
-	request, errNew := http.NewRequest(http.MethodPut, path, strings.NewReader(body))
+	request, errNew := http.NewRequestWithContext(ctx, http.MethodPut, path, strings.NewReader(body))
 	if errNew != nil {
 		err = errNew
 		return
 	}
 	request.Header = header
-	resp, errDo := client.Do(request)
+	resp, errDo := do(&client, request)
 	if errDo != nil {
-		log.Println(errDo)
+		logger.Errorf("request path[%s] error:%s", path, errDo.Error())
 		err = errDo
 	} else {
 		response = resp
@@ -0,0 +1,307 @@
+package load_balancer
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"hash/crc32"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 从健康实例中选出一个实例，传入的实例列表不会为空
+type LoadBalancer interface {
+	Select(instances []model.Instance) model.Instance
+}
+
+func instanceKey(instance model.Instance) string {
+	return instance.Ip + ":" + strconv.Itoa(int(instance.Port))
+}
+
+// 按权重随机
+type WeightedRandomBalancer struct {
+}
+
+func NewWeightedRandomBalancer() *WeightedRandomBalancer {
+	return &WeightedRandomBalancer{}
+}
+
+func (b *WeightedRandomBalancer) Select(instances []model.Instance) model.Instance {
+	var totalWeight float64
+	for _, instance := range instances {
+		totalWeight += instance.Weight
+	}
+	if totalWeight <= 0 {
+		return instances[rand.Intn(len(instances))]
+	}
+	r := rand.Float64() * totalWeight
+	for _, instance := range instances {
+		r -= instance.Weight
+		if r < 0 {
+			return instance
+		}
+	}
+	return instances[len(instances)-1]
+}
+
+// 平滑加权轮询，与Nginx的实现一致，权重高的实例在一轮中被均匀地穿插选中而不是连续选中
+// 所有实例权重都不大于0时按相同权重轮询
+type SmoothWeightedRoundRobinBalancer struct {
+	mutex   sync.Mutex
+	current map[string]float64
+}
+
+func NewSmoothWeightedRoundRobinBalancer() *SmoothWeightedRoundRobinBalancer {
+	return &SmoothWeightedRoundRobinBalancer{current: map[string]float64{}}
+}
+
+func (b *SmoothWeightedRoundRobinBalancer) Select(instances []model.Instance) model.Instance {
+	weights := make([]float64, len(instances))
+	var totalWeight float64
+	for i, instance := range instances {
+		if instance.Weight > 0 {
+			weights[i] = instance.Weight
+			totalWeight += instance.Weight
+		}
+	}
+	if totalWeight <= 0 {
+		for i := range weights {
+			weights[i] = 1
+		}
+		totalWeight = float64(len(weights))
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	// 只保留本次传入的实例，已下线实例的状态随之丢弃
+	current := make(map[string]float64, len(instances))
+	selected := -1
+	for i, instance := range instances {
+		key := instanceKey(instance)
+		current[key] = b.current[key] + weights[i]
+		if selected < 0 || current[key] > current[instanceKey(instances[selected])] {
+			selected = i
+		}
+	}
+	current[instanceKey(instances[selected])] -= totalWeight
+	b.current = current
+	return instances[selected]
+}
+
+// 实例元数据中记录注册时间（毫秒时间戳）的key，与Dubbo一致
+const Default_Timestamp_Metadata_Key = "timestamp"
+
+// 预热，注册时间在warmUp之内的实例按已注册时长线性提升权重后再交给balancer选择
+// 没有注册时间元数据的实例视为已完成预热
+type WarmUpBalancer struct {
+	balancer     LoadBalancer
+	warmUp       time.Duration
+	timestampKey string
+}
+
+// timestampKey为空时使用Default_Timestamp_Metadata_Key
+func NewWarmUpBalancer(balancer LoadBalancer, warmUp time.Duration, timestampKey string) *WarmUpBalancer {
+	if timestampKey == "" {
+		timestampKey = Default_Timestamp_Metadata_Key
+	}
+	return &WarmUpBalancer{balancer: balancer, warmUp: warmUp, timestampKey: timestampKey}
+}
+
+func (b *WarmUpBalancer) Select(instances []model.Instance) model.Instance {
+	now := time.Now()
+	weighted := make([]model.Instance, len(instances))
+	for i, instance := range instances {
+		weighted[i] = instance
+		weighted[i].Weight = WarmUpWeight(instance, b.warmUp, b.timestampKey, now)
+	}
+	selected := b.balancer.Select(weighted)
+	key := instanceKey(selected)
+	for i := range weighted {
+		if instanceKey(weighted[i]) == key {
+			return instances[i]
+		}
+	}
+	return selected
+}
+
+// 预热期内的权重为weight*已注册时长/warmUp，最小为1（原权重小于1时为原权重）
+func WarmUpWeight(instance model.Instance, warmUp time.Duration, timestampKey string, now time.Time) float64 {
+	if warmUp <= 0 || instance.Weight <= 0 {
+		return instance.Weight
+	}
+	timestamp, err := strconv.ParseInt(instance.Metadata[timestampKey], 10, 64)
+	if err != nil || timestamp <= 0 {
+		return instance.Weight
+	}
+	uptime := now.Sub(time.Unix(0, timestamp*int64(time.Millisecond)))
+	if uptime <= 0 || uptime >= warmUp {
+		return instance.Weight
+	}
+	weight := instance.Weight * float64(uptime) / float64(warmUp)
+	return math.Max(weight, math.Min(1, instance.Weight))
+}
+
+// 实例元数据中记录所在可用区的key
+const Default_Zone_Metadata_Key = "zone"
+
+// 同可用区优先
+type LocalityConfig struct {
+	// 客户端所在的可用区，为空时不区分可用区
+	Zone string
+	// 实例元数据中记录可用区的key，为空时使用Default_Zone_Metadata_Key
+	MetadataKey string
+	// 同可用区的实例数少于该值时溢出到所有可用区的实例，小于等于0时为1
+	MinInstances int
+}
+
+// 优先从与客户端同可用区的实例中选择，同可用区的实例不足时从全部实例中选择
+type ZoneAwareBalancer struct {
+	balancer LoadBalancer
+	config   LocalityConfig
+}
+
+func NewZoneAwareBalancer(balancer LoadBalancer, config LocalityConfig) *ZoneAwareBalancer {
+	if config.MetadataKey == "" {
+		config.MetadataKey = Default_Zone_Metadata_Key
+	}
+	if config.MinInstances <= 0 {
+		config.MinInstances = 1
+	}
+	return &ZoneAwareBalancer{balancer: balancer, config: config}
+}
+
+func (b *ZoneAwareBalancer) Select(instances []model.Instance) model.Instance {
+	if local := b.Local(instances); len(local) >= b.config.MinInstances {
+		return b.balancer.Select(local)
+	}
+	return b.balancer.Select(instances)
+}
+
+// 返回与客户端同可用区的实例
+func (b *ZoneAwareBalancer) Local(instances []model.Instance) []model.Instance {
+	if b.config.Zone == "" {
+		return nil
+	}
+	var local []model.Instance
+	for _, instance := range instances {
+		if instance.Metadata[b.config.MetadataKey] == b.config.Zone {
+			local = append(local, instance)
+		}
+	}
+	return local
+}
+
+// 轮询
+type RoundRobinBalancer struct {
+	index uint64
+}
+
+func NewRoundRobinBalancer() *RoundRobinBalancer {
+	return &RoundRobinBalancer{}
+}
+
+func (b *RoundRobinBalancer) Select(instances []model.Instance) model.Instance {
+	index := atomic.AddUint64(&b.index, 1) - 1
+	return instances[index%uint64(len(instances))]
+}
+
+// 一致性哈希，相同的key总是落到同一个实例上，实例变化时只有少量key会迁移
+type ConsistentHashBalancer struct {
+	key          string
+	virtualNodes int
+}
+
+const Default_Virtual_Nodes = 160
+
+func NewConsistentHashBalancer(key string, virtualNodes int) *ConsistentHashBalancer {
+	if virtualNodes <= 0 {
+		virtualNodes = Default_Virtual_Nodes
+	}
+	return &ConsistentHashBalancer{key: key, virtualNodes: virtualNodes}
+}
+
+func (b *ConsistentHashBalancer) Select(instances []model.Instance) model.Instance {
+	instance, _ := NewHashRing(instances, b.virtualNodes).Get(b.key)
+	return instance
+}
+
+// 一致性哈希环，构建后只读，实例变化时需重新构建
+type HashRing struct {
+	hashes    []uint32
+	ring      map[uint32]int
+	instances []model.Instance
+}
+
+// virtualNodes为每个实例的虚拟节点数，小于等于0时使用Default_Virtual_Nodes
+func NewHashRing(instances []model.Instance, virtualNodes int) *HashRing {
+	if virtualNodes <= 0 {
+		virtualNodes = Default_Virtual_Nodes
+	}
+	r := &HashRing{
+		hashes:    make([]uint32, 0, len(instances)*virtualNodes),
+		ring:      make(map[uint32]int, len(instances)*virtualNodes),
+		instances: instances,
+	}
+	for i, instance := range instances {
+		key := instanceKey(instance)
+		for v := 0; v < virtualNodes; v++ {
+			hash := crc32.ChecksumIEEE([]byte(key + "#" + strconv.Itoa(v)))
+			if _, ok := r.ring[hash]; !ok {
+				r.hashes = append(r.hashes, hash)
+			}
+			r.ring[hash] = i
+		}
+	}
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+	return r
+}
+
+// 返回key落在环上的实例，环为空时返回false
+func (r *HashRing) Get(key string) (model.Instance, bool) {
+	if len(r.hashes) == 0 {
+		return model.Instance{}, false
+	}
+	hash := crc32.ChecksumIEEE([]byte(key))
+	pos := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= hash })
+	if pos == len(r.hashes) {
+		pos = 0
+	}
+	return r.instances[r.ring[r.hashes[pos]]], true
+}
+
+// 最少连接，调用方在请求结束后需要调用Release归还连接
+type LeastConnectionBalancer struct {
+	mutex       sync.Mutex
+	connections map[string]int64
+}
+
+func NewLeastConnectionBalancer() *LeastConnectionBalancer {
+	return &LeastConnectionBalancer{connections: map[string]int64{}}
+}
+
+func (b *LeastConnectionBalancer) Select(instances []model.Instance) model.Instance {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	selected := 0
+	for i := 1; i < len(instances); i++ {
+		if b.connections[instanceKey(instances[i])] < b.connections[instanceKey(instances[selected])] {
+			selected = i
+		}
+	}
+	b.connections[instanceKey(instances[selected])]++
+	return instances[selected]
+}
+
+func (b *LeastConnectionBalancer) Release(instance model.Instance) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	key := instanceKey(instance)
+	if b.connections[key] > 0 {
+		b.connections[key]--
+	}
+	if b.connections[key] == 0 {
+		delete(b.connections, key)
+	}
+}
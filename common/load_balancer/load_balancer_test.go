@@ -0,0 +1,138 @@
+package load_balancer
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var instancesTest = []model.Instance{
+	{Ip: "10.0.0.10", Port: 80, Weight: 1},
+	{Ip: "10.0.0.11", Port: 80, Weight: 1},
+	{Ip: "10.0.0.12", Port: 80, Weight: 1},
+}
+
+func TestRoundRobinBalancer_Select(t *testing.T) {
+	balancer := NewRoundRobinBalancer()
+	for i := 0; i < 6; i++ {
+		assert.Equal(t, instancesTest[i%3].Ip, balancer.Select(instancesTest).Ip)
+	}
+}
+
+func TestConsistentHashBalancer_Select(t *testing.T) {
+	selected := NewConsistentHashBalancer("user-1", 0).Select(instancesTest)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, selected, NewConsistentHashBalancer("user-1", 0).Select(instancesTest))
+	}
+	// 移除未被选中的实例不影响选择结果
+	var remains []model.Instance
+	for _, instance := range instancesTest {
+		if instance.Ip == selected.Ip || len(remains) == 0 {
+			remains = append(remains, instance)
+		}
+	}
+	assert.Equal(t, selected, NewConsistentHashBalancer("user-1", 0).Select(remains))
+}
+
+func TestLeastConnectionBalancer_Select(t *testing.T) {
+	balancer := NewLeastConnectionBalancer()
+	first := balancer.Select(instancesTest)
+	second := balancer.Select(instancesTest)
+	third := balancer.Select(instancesTest)
+	assert.NotEqual(t, first.Ip, second.Ip)
+	assert.NotEqual(t, second.Ip, third.Ip)
+	assert.NotEqual(t, first.Ip, third.Ip)
+	balancer.Release(second)
+	assert.Equal(t, second.Ip, balancer.Select(instancesTest).Ip)
+}
+
+func TestWeightedRandomBalancer_Select(t *testing.T) {
+	instances := []model.Instance{
+		{Ip: "10.0.0.10", Port: 80, Weight: 1},
+		{Ip: "10.0.0.11", Port: 80, Weight: 0},
+	}
+	balancer := NewWeightedRandomBalancer()
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, "10.0.0.10", balancer.Select(instances).Ip)
+	}
+}
+
+func TestSmoothWeightedRoundRobinBalancer_Select(t *testing.T) {
+	instances := []model.Instance{
+		{Ip: "a", Port: 80, Weight: 5},
+		{Ip: "b", Port: 80, Weight: 1},
+		{Ip: "c", Port: 80, Weight: 1},
+	}
+	balancer := NewSmoothWeightedRoundRobinBalancer()
+	var selected []string
+	for i := 0; i < 7; i++ {
+		selected = append(selected, balancer.Select(instances).Ip)
+	}
+	assert.Equal(t, []string{"a", "a", "b", "a", "c", "a", "a"}, selected)
+
+	// 权重都为0时按相同权重轮询
+	zero := []model.Instance{{Ip: "a", Port: 80}, {Ip: "b", Port: 80}}
+	balancer = NewSmoothWeightedRoundRobinBalancer()
+	assert.Equal(t, "a", balancer.Select(zero).Ip)
+	assert.Equal(t, "b", balancer.Select(zero).Ip)
+}
+
+func TestWarmUpWeight(t *testing.T) {
+	now := time.Now()
+	registeredAt := func(d time.Duration) map[string]string {
+		return map[string]string{Default_Timestamp_Metadata_Key: strconv.FormatInt(now.Add(-d).UnixNano()/int64(time.Millisecond), 10)}
+	}
+	warmUp := 10 * time.Minute
+	assert.InDelta(t, 50, WarmUpWeight(model.Instance{Weight: 100, Metadata: registeredAt(5 * time.Minute)}, warmUp, Default_Timestamp_Metadata_Key, now), 0.1)
+	assert.Equal(t, float64(1), WarmUpWeight(model.Instance{Weight: 100, Metadata: registeredAt(time.Millisecond)}, warmUp, Default_Timestamp_Metadata_Key, now))
+	assert.Equal(t, float64(100), WarmUpWeight(model.Instance{Weight: 100, Metadata: registeredAt(time.Hour)}, warmUp, Default_Timestamp_Metadata_Key, now))
+	assert.Equal(t, float64(100), WarmUpWeight(model.Instance{Weight: 100}, warmUp, Default_Timestamp_Metadata_Key, now))
+}
+
+func TestWarmUpBalancer_Select(t *testing.T) {
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	instances := []model.Instance{
+		{Ip: "old", Port: 80, Weight: 10},
+		{Ip: "new", Port: 80, Weight: 10, Metadata: map[string]string{"registerTime": strconv.FormatInt(now, 10)}},
+	}
+	balancer := NewWarmUpBalancer(NewSmoothWeightedRoundRobinBalancer(), time.Hour, "registerTime")
+	counts := map[string]int{}
+	for i := 0; i < 11; i++ {
+		selected := balancer.Select(instances)
+		assert.Equal(t, float64(10), selected.Weight, "should return the original instance")
+		counts[selected.Ip]++
+	}
+	assert.Equal(t, 10, counts["old"])
+	assert.Equal(t, 1, counts["new"])
+}
+
+func TestHashRing_Get(t *testing.T) {
+	_, ok := NewHashRing(nil, 0).Get("user-1")
+	assert.False(t, ok)
+	ring := NewHashRing(instancesTest, 10)
+	instance, ok := ring.Get("user-1")
+	assert.True(t, ok)
+	assert.Equal(t, NewConsistentHashBalancer("user-1", 10).Select(instancesTest), instance)
+}
+
+func TestZoneAwareBalancer_Select(t *testing.T) {
+	instances := []model.Instance{
+		{Ip: "10.0.0.10", Port: 80, Weight: 1, Metadata: map[string]string{"zone": "a"}},
+		{Ip: "10.0.0.11", Port: 80, Weight: 1, Metadata: map[string]string{"zone": "b"}},
+		{Ip: "10.0.0.12", Port: 80, Weight: 1},
+	}
+	balancer := NewZoneAwareBalancer(NewRoundRobinBalancer(), LocalityConfig{Zone: "a"})
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, "10.0.0.10", balancer.Select(instances).Ip)
+	}
+	// 同可用区的实例不足时溢出
+	balancer = NewZoneAwareBalancer(NewRoundRobinBalancer(), LocalityConfig{Zone: "a", MinInstances: 2})
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, instances[i].Ip, balancer.Select(instances).Ip)
+	}
+	balancer = NewZoneAwareBalancer(NewRoundRobinBalancer(), LocalityConfig{Zone: "c", MetadataKey: "idc"})
+	assert.Equal(t, 0, len(balancer.Local(instances)))
+	assert.Equal(t, "10.0.0.10", balancer.Select(instances).Ip)
+}
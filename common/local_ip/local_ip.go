@@ -0,0 +1,185 @@
+package local_ip
+
+import (
+	"errors"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 设置后直接使用该ip，优先于其他配置
+const Env_Advertise_Ip = "NACOS_ADVERTISE_IP"
+
+// 探测到达服务端的出口ip时的超时，只建立UDP socket不发送数据
+const probeTimeout = time.Second
+
+// 容器和虚拟化环境中常见的虚拟网卡，只在没有其他网卡时使用
+var virtualInterfacePrefixes = []string{"docker", "veth", "br-", "virbr", "cni", "flannel", "cali", "kube-"}
+
+// 选择上报给服务端的本机ip，按以下顺序选择：
+// 1. 环境变量NACOS_ADVERTISE_IP
+// 2. Interfaces：按顺序使用第一个存在可用ip的网卡，如eth0
+// 3. PreferredNetworks：按顺序使用第一个落在该网段内的ip，如10.0.0.0/8
+// 4. 访问服务端时的出口ip，容器中默认路由所在的网卡不一定能被服务端访问
+// 5. 第一个非回环、非虚拟网卡的ip
+// PreferIPv6为true时优先使用IPv6地址
+type LocalIpConfig struct {
+	Interfaces        []string
+	PreferredNetworks []string
+	PreferIPv6        bool
+}
+
+type interfaceAddr struct {
+	name string
+	ip   net.IP
+}
+
+// 测试中替换
+var listInterfaceAddrs = func() ([]interfaceAddr, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	var result []interfaceAddr
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if ipnet, ok := addr.(*net.IPNet); ok {
+				result = append(result, interfaceAddr{name: iface.Name, ip: ipnet.IP})
+			}
+		}
+	}
+	return result, nil
+}
+
+var probeLocalAddr = func(address string) (net.IP, error) {
+	conn, err := net.DialTimeout("udp", address, probeTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
+
+// probeAddrs为服务端的host:port，用于探测出口ip，可为空
+func Detect(config LocalIpConfig, probeAddrs []string) (string, error) {
+	if ip := strings.TrimSpace(os.Getenv(Env_Advertise_Ip)); ip != "" {
+		if net.ParseIP(ip) == nil {
+			return "", errors.New("invalid " + Env_Advertise_Ip + ": " + ip)
+		}
+		return ip, nil
+	}
+	addrs, err := listInterfaceAddrs()
+	if err != nil {
+		return "", err
+	}
+	var candidates []interfaceAddr
+	for _, addr := range addrs {
+		if addr.ip.IsLoopback() || addr.ip.IsLinkLocalUnicast() || addr.ip.IsUnspecified() {
+			continue
+		}
+		candidates = append(candidates, addr)
+	}
+	sortByFamily(candidates, config.PreferIPv6)
+
+	for _, name := range config.Interfaces {
+		for _, candidate := range candidates {
+			if candidate.name == name {
+				return candidate.ip.String(), nil
+			}
+		}
+	}
+	for _, network := range config.PreferredNetworks {
+		_, ipnet, err := net.ParseCIDR(network)
+		if err != nil {
+			return "", err
+		}
+		for _, candidate := range candidates {
+			if ipnet.Contains(candidate.ip) {
+				return candidate.ip.String(), nil
+			}
+		}
+	}
+	for _, address := range probeAddrs {
+		ip, err := probeLocalAddr(address)
+		if err != nil {
+			logger.Debugf("probe local ip by %s failed,err:%s", address, err.Error())
+			continue
+		}
+		if !ip.IsLoopback() && !ip.IsUnspecified() {
+			return ip.String(), nil
+		}
+	}
+	for _, candidate := range candidates {
+		if !isVirtualInterface(candidate.name) {
+			return candidate.ip.String(), nil
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates[0].ip.String(), nil
+	}
+	return "", errors.New("no available local ip")
+}
+
+// 稳定排序，保持同一地址族内网卡的原有顺序
+func sortByFamily(candidates []interfaceAddr, preferIPv6 bool) {
+	var preferred, others []interfaceAddr
+	for _, candidate := range candidates {
+		if (candidate.ip.To4() == nil) == preferIPv6 {
+			preferred = append(preferred, candidate)
+		} else {
+			others = append(others, candidate)
+		}
+	}
+	copy(candidates, append(preferred, others...))
+}
+
+func isVirtualInterface(name string) bool {
+	for _, prefix := range virtualInterfacePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// 首次调用IP时探测并缓存结果，探测失败时返回空字符串，下次调用时重新探测
+type Detector struct {
+	mutex      sync.Mutex
+	config     LocalIpConfig
+	probeAddrs func() []string
+	ip         string
+}
+
+func NewDetector(config LocalIpConfig, probeAddrs func() []string) *Detector {
+	return &Detector{config: config, probeAddrs: probeAddrs}
+}
+
+func (d *Detector) IP() string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.ip != "" {
+		return d.ip
+	}
+	var probeAddrs []string
+	if d.probeAddrs != nil {
+		probeAddrs = d.probeAddrs()
+	}
+	ip, err := Detect(d.config, probeAddrs)
+	if err != nil {
+		logger.Errorf("detect local ip failed,err:%s", err.Error())
+		return ""
+	}
+	logger.Infof("detect local ip:%s", ip)
+	d.ip = ip
+	return ip
+}
@@ -0,0 +1,91 @@
+package local_ip
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"os"
+	"testing"
+)
+
+// 返回恢复原实现的函数
+func mockInterfaces(addrs map[string][]string, order []string, probeIp string) func() {
+	oldList, oldProbe := listInterfaceAddrs, probeLocalAddr
+	listInterfaceAddrs = func() ([]interfaceAddr, error) {
+		var result []interfaceAddr
+		for _, name := range order {
+			for _, ip := range addrs[name] {
+				result = append(result, interfaceAddr{name: name, ip: net.ParseIP(ip)})
+			}
+		}
+		return result, nil
+	}
+	probeLocalAddr = func(address string) (net.IP, error) {
+		if probeIp == "" {
+			return nil, errors.New("unreachable")
+		}
+		return net.ParseIP(probeIp), nil
+	}
+	return func() {
+		listInterfaceAddrs, probeLocalAddr = oldList, oldProbe
+	}
+}
+
+func TestDetect(t *testing.T) {
+	defer mockInterfaces(map[string][]string{
+		"docker0": {"172.17.0.1"},
+		"eth0":    {"fe80::1", "192.168.1.10", "2001:db8::10"},
+		"eth1":    {"10.0.0.10"},
+	}, []string{"docker0", "eth0", "eth1"}, "")()
+
+	ip, err := Detect(LocalIpConfig{}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "192.168.1.10", ip, "virtual interfaces and link-local addresses should be skipped")
+
+	ip, _ = Detect(LocalIpConfig{Interfaces: []string{"eth2", "eth1"}}, nil)
+	assert.Equal(t, "10.0.0.10", ip)
+
+	ip, _ = Detect(LocalIpConfig{PreferredNetworks: []string{"10.0.0.0/8"}}, nil)
+	assert.Equal(t, "10.0.0.10", ip)
+	_, err = Detect(LocalIpConfig{PreferredNetworks: []string{"10.0.0.0"}}, nil)
+	assert.NotNil(t, err)
+
+	ip, _ = Detect(LocalIpConfig{PreferIPv6: true}, nil)
+	assert.Equal(t, "2001:db8::10", ip)
+}
+
+func TestDetect_Probe(t *testing.T) {
+	defer mockInterfaces(map[string][]string{
+		"eth0": {"192.168.1.10"},
+		"eth1": {"10.0.0.10"},
+	}, []string{"eth0", "eth1"}, "10.0.0.10")()
+	ip, _ := Detect(LocalIpConfig{}, []string{"10.0.0.1:8848"})
+	assert.Equal(t, "10.0.0.10", ip, "the source ip to the server should be preferred over the first interface")
+	ip, _ = Detect(LocalIpConfig{Interfaces: []string{"eth0"}}, []string{"10.0.0.1:8848"})
+	assert.Equal(t, "192.168.1.10", ip)
+}
+
+func TestDetect_Env(t *testing.T) {
+	defer mockInterfaces(map[string][]string{"eth0": {"192.168.1.10"}}, []string{"eth0"}, "")()
+	os.Setenv(Env_Advertise_Ip, "10.1.1.1")
+	defer os.Unsetenv(Env_Advertise_Ip)
+	ip, err := Detect(LocalIpConfig{Interfaces: []string{"eth0"}}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.1.1.1", ip)
+
+	os.Setenv(Env_Advertise_Ip, "invalid")
+	_, err = Detect(LocalIpConfig{}, nil)
+	assert.NotNil(t, err)
+}
+
+func TestDetector(t *testing.T) {
+	defer mockInterfaces(map[string][]string{"eth0": {"192.168.1.10"}}, []string{"eth0"}, "")()
+	probed := 0
+	detector := NewDetector(LocalIpConfig{}, func() []string {
+		probed++
+		return nil
+	})
+	assert.Equal(t, "192.168.1.10", detector.IP())
+	assert.Equal(t, "192.168.1.10", detector.IP())
+	assert.Equal(t, 1, probed, "the detected ip should be cached")
+}
@@ -1,16 +1,136 @@
 package logger
 
 import (
+	"errors"
+	"fmt"
 	"github.com/lestrrat/go-file-rotatelogs"
 	"github.com/nacos-group/nacos-sdk-go/common/util"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 )
 
-func InitLog(logDir string) error {
-	err := util.MkdirIfNecessary(logDir)
+type Level int
+
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+var levelNames = map[Level]string{
+	DebugLevel: "DEBUG",
+	InfoLevel:  "INFO",
+	WarnLevel:  "WARN",
+	ErrorLevel: "ERROR",
+}
+
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// 解析日志级别，空字符串默认为info
+func ParseLevel(level string) (Level, error) {
+	if level == "" {
+		return InfoLevel, nil
+	}
+	for l, name := range levelNames {
+		if strings.EqualFold(level, name) {
+			return l, nil
+		}
+	}
+	return InfoLevel, errors.New("[logger.ParseLevel] unknown log level: " + level)
+}
+
+type Fields map[string]interface{}
+
+// 日志接口，可通过ClientConfig.Logger接入logrus、zap等日志库
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	// 返回附带了结构化字段的Logger
+	WithFields(fields Fields) Logger
+}
+
+// 默认日志实现，格式为: 时间 [级别] 内容 key=value ...
+type defaultLogger struct {
+	out    *log.Logger
+	level  Level
+	fields Fields
+}
+
+func NewDefaultLogger(out io.Writer, level Level) Logger {
+	return &defaultLogger{
+		out:   log.New(out, "", log.LstdFlags),
+		level: level,
+	}
+}
+
+func (l *defaultLogger) output(level Level, format string, args ...interface{}) {
+	if level < l.level {
+		return
+	}
+	var sb strings.Builder
+	sb.WriteString("[" + level.String() + "] ")
+	sb.WriteString(strings.TrimRight(fmt.Sprintf(format, args...), " \n"))
+	keys := make([]string, 0, len(l.fields))
+	for k := range l.fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		sb.WriteString(fmt.Sprintf(" %s=%v", k, l.fields[k]))
+	}
+	l.out.Println(sb.String())
+}
+
+func (l *defaultLogger) Debugf(format string, args ...interface{}) {
+	l.output(DebugLevel, format, args...)
+}
+
+func (l *defaultLogger) Infof(format string, args ...interface{}) {
+	l.output(InfoLevel, format, args...)
+}
+
+func (l *defaultLogger) Warnf(format string, args ...interface{}) {
+	l.output(WarnLevel, format, args...)
+}
+
+func (l *defaultLogger) Errorf(format string, args ...interface{}) {
+	l.output(ErrorLevel, format, args...)
+}
+
+func (l *defaultLogger) WithFields(fields Fields) Logger {
+	merged := make(Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &defaultLogger{out: l.out, level: l.level, fields: merged}
+}
+
+var (
+	mutex  sync.RWMutex
+	logger = NewDefaultLogger(os.Stderr, InfoLevel)
+)
+
+// 使用logDir下按小时滚动的日志文件初始化默认日志
+func InitLog(logDir string, level string) error {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	err = util.MkdirIfNecessary(logDir)
 	if err != nil {
 		return err
 	}
@@ -19,7 +139,39 @@ func InitLog(logDir string) error {
 	if err != nil {
 		return err
 	}
-	log.SetOutput(rl)
-	log.SetFlags(log.LstdFlags)
+	SetLogger(NewDefaultLogger(rl, lvl))
 	return nil
 }
+
+// 替换SDK的全局日志，对进程内的所有客户端生效
+func SetLogger(l Logger) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	logger = l
+}
+
+func GetLogger() Logger {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return logger
+}
+
+func Debugf(format string, args ...interface{}) {
+	GetLogger().Debugf(format, args...)
+}
+
+func Infof(format string, args ...interface{}) {
+	GetLogger().Infof(format, args...)
+}
+
+func Warnf(format string, args ...interface{}) {
+	GetLogger().Warnf(format, args...)
+}
+
+func Errorf(format string, args ...interface{}) {
+	GetLogger().Errorf(format, args...)
+}
+
+func WithFields(fields Fields) Logger {
+	return GetLogger().WithFields(fields)
+}
@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestDefaultLogger_Level(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewDefaultLogger(&buf, WarnLevel)
+	l.Infof("ignored %d", 1)
+	l.Warnf("kept %d", 2)
+	assert.False(t, strings.Contains(buf.String(), "ignored"))
+	assert.True(t, strings.Contains(buf.String(), "[WARN] kept 2"))
+}
+
+func TestDefaultLogger_WithFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewDefaultLogger(&buf, DebugLevel).WithFields(Fields{"service": "DEMO", "cluster": "a"})
+	l.Errorf("failed")
+	assert.True(t, strings.HasSuffix(buf.String(), "[ERROR] failed cluster=a service=DEMO\n"))
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("debug")
+	assert.Nil(t, err)
+	assert.Equal(t, DebugLevel, level)
+	level, err = ParseLevel("")
+	assert.Nil(t, err)
+	assert.Equal(t, InfoLevel, level)
+	_, err = ParseLevel("trace")
+	assert.NotNil(t, err)
+}
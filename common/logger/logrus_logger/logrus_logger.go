@@ -0,0 +1,44 @@
+//go:build logrus
+// +build logrus
+
+// logrus的logger.Logger适配，需要以-tags logrus构建，未使用logrus的应用不引入其依赖
+package logrus_logger
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type logrusLogger struct {
+	entry *logrus.Entry
+}
+
+// 日志级别和输出由l决定，可直接设置到ClientConfig.Logger
+func New(l *logrus.Logger) logger.Logger {
+	return NewEntry(logrus.NewEntry(l))
+}
+
+// 使用附带了字段的Entry，SDK的日志都会带上这些字段
+func NewEntry(entry *logrus.Entry) logger.Logger {
+	return logrusLogger{entry: entry}
+}
+
+func (l logrusLogger) Debugf(format string, args ...interface{}) {
+	l.entry.Debugf(format, args...)
+}
+
+func (l logrusLogger) Infof(format string, args ...interface{}) {
+	l.entry.Infof(format, args...)
+}
+
+func (l logrusLogger) Warnf(format string, args ...interface{}) {
+	l.entry.Warnf(format, args...)
+}
+
+func (l logrusLogger) Errorf(format string, args ...interface{}) {
+	l.entry.Errorf(format, args...)
+}
+
+func (l logrusLogger) WithFields(fields logger.Fields) logger.Logger {
+	return logrusLogger{entry: l.entry.WithFields(logrus.Fields(fields))}
+}
@@ -0,0 +1,29 @@
+//go:build logrus
+// +build logrus
+
+package logrus_logger
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLogrusLogger(t *testing.T) {
+	l, hook := test.NewNullLogger()
+	l.SetLevel(logrus.InfoLevel)
+	log := New(l)
+	log.Debugf("debug %d", 1)
+	assert.Equal(t, 0, len(hook.AllEntries()))
+
+	log.WithFields(logger.Fields{"service": "demo"}).Warnf("beat failed: %s", "timeout")
+	entry := hook.LastEntry()
+	assert.Equal(t, logrus.WarnLevel, entry.Level)
+	assert.Equal(t, "beat failed: timeout", entry.Message)
+	assert.Equal(t, "demo", entry.Data["service"])
+
+	log.Errorf("error")
+	assert.Equal(t, 0, len(hook.LastEntry().Data), "fields should not leak into the parent logger")
+}
@@ -0,0 +1,54 @@
+//go:build zap
+// +build zap
+
+// zap的logger.Logger适配，需要以-tags zap构建，未使用zap的应用不引入其依赖
+package zap_logger
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"go.uber.org/zap"
+	"sort"
+)
+
+type zapLogger struct {
+	sugar *zap.SugaredLogger
+}
+
+// 日志级别和输出由l决定，可直接设置到ClientConfig.Logger
+func New(l *zap.Logger) logger.Logger {
+	return NewSugared(l.Sugar())
+}
+
+func NewSugared(sugar *zap.SugaredLogger) logger.Logger {
+	return zapLogger{sugar: sugar}
+}
+
+func (l zapLogger) Debugf(format string, args ...interface{}) {
+	l.sugar.Debugf(format, args...)
+}
+
+func (l zapLogger) Infof(format string, args ...interface{}) {
+	l.sugar.Infof(format, args...)
+}
+
+func (l zapLogger) Warnf(format string, args ...interface{}) {
+	l.sugar.Warnf(format, args...)
+}
+
+func (l zapLogger) Errorf(format string, args ...interface{}) {
+	l.sugar.Errorf(format, args...)
+}
+
+// 字段按key排序后转换为zap的键值对
+func (l zapLogger) WithFields(fields logger.Fields) logger.Logger {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	args := make([]interface{}, 0, 2*len(keys))
+	for _, k := range keys {
+		args = append(args, zap.Any(k, fields[k]))
+	}
+	return zapLogger{sugar: l.sugar.With(args...)}
+}
@@ -0,0 +1,30 @@
+//go:build zap
+// +build zap
+
+package zap_logger
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"testing"
+)
+
+func TestZapLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	log := New(zap.New(core))
+	log.Debugf("debug %d", 1)
+	assert.Equal(t, 0, logs.Len())
+
+	log.WithFields(logger.Fields{"service": "demo", "port": 80}).Warnf("beat failed: %s", "timeout")
+	entries := logs.TakeAll()
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+	assert.Equal(t, "beat failed: timeout", entries[0].Message)
+	assert.Equal(t, map[string]interface{}{"service": "demo", "port": int64(80)}, entries[0].ContextMap())
+
+	log.Errorf("error")
+	assert.Equal(t, 0, len(logs.TakeAll()[0].Context), "fields should not leak into the parent logger")
+}
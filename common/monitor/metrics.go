@@ -0,0 +1,361 @@
+package monitor
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 指标的注册表，按Prometheus文本格式输出其中的所有指标
+type Registry struct {
+	mutex   sync.Mutex
+	metrics map[string]metric
+}
+
+type metric interface {
+	name() string
+	writeTo(w io.Writer)
+	family() Family
+}
+
+// 指标的快照，用于导出到Prometheus client_golang等其他监控库
+type Family struct {
+	Name string
+	Help string
+	// counter、gauge或histogram
+	Type       string
+	LabelNames []string
+	Samples    []Sample
+}
+
+type Sample struct {
+	LabelValues []string
+	// counter和gauge的值
+	Value float64
+	// histogram的桶上界和每个桶的累计计数
+	Buckets      []float64
+	BucketCounts []uint64
+	Count        uint64
+	Sum          float64
+}
+
+func NewRegistry() *Registry {
+	return &Registry{metrics: map[string]metric{}}
+}
+
+// 同名指标只注册一次
+func (r *Registry) register(m metric) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.metrics[m.name()]; !ok {
+		r.metrics[m.name()] = m
+	}
+}
+
+// 按名称排序输出所有指标
+func (r *Registry) Write(w io.Writer) {
+	r.mutex.Lock()
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	metrics := make([]metric, 0, len(names))
+	for _, name := range names {
+		metrics = append(metrics, r.metrics[name])
+	}
+	r.mutex.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, m := range metrics {
+		m.writeTo(bw)
+	}
+	bw.Flush()
+}
+
+// 按名称排序返回所有指标的快照
+func (r *Registry) Gather() []Family {
+	r.mutex.Lock()
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	metrics := make([]metric, 0, len(names))
+	for _, name := range names {
+		metrics = append(metrics, r.metrics[name])
+	}
+	r.mutex.Unlock()
+
+	families := make([]Family, 0, len(metrics))
+	for _, m := range metrics {
+		families = append(families, m.family())
+	}
+	return families
+}
+
+// 供Prometheus抓取的http.Handler
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.Write(w)
+	})
+}
+
+type sample struct {
+	labelValues []string
+	value       float64
+}
+
+type valueVec struct {
+	metricName string
+	help       string
+	kind       string
+	labelNames []string
+	mutex      sync.Mutex
+	samples    map[string]*sample
+}
+
+func newValueVec(name, help, kind string, labelNames []string) *valueVec {
+	return &valueVec{metricName: name, help: help, kind: kind, labelNames: labelNames, samples: map[string]*sample{}}
+}
+
+func (v *valueVec) name() string {
+	return v.metricName
+}
+
+func (v *valueVec) add(delta float64, labelValues []string) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.get(labelValues).value += delta
+}
+
+func (v *valueVec) set(value float64, labelValues []string) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.get(labelValues).value = value
+}
+
+func (v *valueVec) get(labelValues []string) *sample {
+	key := strings.Join(labelValues, "\xff")
+	s, ok := v.samples[key]
+	if !ok {
+		s = &sample{labelValues: append([]string(nil), labelValues...)}
+		v.samples[key] = s
+	}
+	return s
+}
+
+func (v *valueVec) Value(labelValues ...string) float64 {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if s, ok := v.samples[strings.Join(labelValues, "\xff")]; ok {
+		return s.value
+	}
+	return 0
+}
+
+func (v *valueVec) writeTo(w io.Writer) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	writeHeader(w, v.metricName, v.help, v.kind)
+	for _, key := range sortedKeys(v.samples) {
+		s := v.samples[key]
+		fmt.Fprintf(w, "%s%s %s\n", v.metricName, formatLabels(v.labelNames, s.labelValues, "", ""), formatFloat(s.value))
+	}
+}
+
+func (v *valueVec) family() Family {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	f := Family{Name: v.metricName, Help: v.help, Type: v.kind, LabelNames: v.labelNames}
+	for _, key := range sortedKeys(v.samples) {
+		s := v.samples[key]
+		f.Samples = append(f.Samples, Sample{LabelValues: s.labelValues, Value: s.value})
+	}
+	return f
+}
+
+// 只增不减的计数器
+type CounterVec struct {
+	*valueVec
+}
+
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{newValueVec(name, help, "counter", labelNames)}
+}
+
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.add(1, labelValues)
+}
+
+// delta小于0时忽略
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+	c.add(delta, labelValues)
+}
+
+// 可增可减的瞬时值
+type GaugeVec struct {
+	*valueVec
+}
+
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{newValueVec(name, help, "gauge", labelNames)}
+}
+
+func (g *GaugeVec) Add(delta float64, labelValues ...string) {
+	g.add(delta, labelValues)
+}
+
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	g.set(value, labelValues)
+}
+
+var Default_Buckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+type histogramSample struct {
+	labelValues []string
+	counts      []uint64
+	sum         float64
+	count       uint64
+}
+
+// 分桶统计观测值的直方图
+type HistogramVec struct {
+	metricName string
+	help       string
+	labelNames []string
+	buckets    []float64
+	mutex      sync.Mutex
+	samples    map[string]*histogramSample
+}
+
+// buckets为空时使用Default_Buckets
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = Default_Buckets
+	}
+	return &HistogramVec{metricName: name, help: help, labelNames: labelNames, buckets: buckets, samples: map[string]*histogramSample{}}
+}
+
+func (h *HistogramVec) name() string {
+	return h.metricName
+}
+
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	key := strings.Join(labelValues, "\xff")
+	s, ok := h.samples[key]
+	if !ok {
+		s = &histogramSample{labelValues: append([]string(nil), labelValues...), counts: make([]uint64, len(h.buckets))}
+		h.samples[key] = s
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			s.counts[i]++
+		}
+	}
+	s.sum += value
+	s.count++
+}
+
+// 返回观测次数
+func (h *HistogramVec) Count(labelValues ...string) uint64 {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if s, ok := h.samples[strings.Join(labelValues, "\xff")]; ok {
+		return s.count
+	}
+	return 0
+}
+
+func (h *HistogramVec) writeTo(w io.Writer) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	writeHeader(w, h.metricName, h.help, "histogram")
+	for _, key := range sortedKeys(h.samples) {
+		s := h.samples[key]
+		for i, bound := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, formatLabels(h.labelNames, s.labelValues, "le", formatFloat(bound)), s.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, formatLabels(h.labelNames, s.labelValues, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.metricName, formatLabels(h.labelNames, s.labelValues, "", ""), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.metricName, formatLabels(h.labelNames, s.labelValues, "", ""), s.count)
+	}
+}
+
+func (h *HistogramVec) family() Family {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	f := Family{Name: h.metricName, Help: h.help, Type: "histogram", LabelNames: h.labelNames}
+	for _, key := range sortedKeys(h.samples) {
+		s := h.samples[key]
+		f.Samples = append(f.Samples, Sample{LabelValues: s.labelValues, Buckets: h.buckets,
+			BucketCounts: append([]uint64(nil), s.counts...), Count: s.count, Sum: s.sum})
+	}
+	return f
+}
+
+func writeHeader(w io.Writer, name, help, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch samples := m.(type) {
+	case map[string]*sample:
+		for k := range samples {
+			keys = append(keys, k)
+		}
+	case map[string]*histogramSample:
+		for k := range samples {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatLabels(names, values []string, extraName, extraValue string) string {
+	if len(names) == 0 && extraName == "" {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("{")
+	for i, name := range names {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		sb.WriteString(name + "=" + strconv.Quote(value))
+	}
+	if extraName != "" {
+		if len(names) > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(extraName + "=" + strconv.Quote(extraValue))
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
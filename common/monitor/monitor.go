@@ -0,0 +1,151 @@
+package monitor
+
+import (
+	"strconv"
+	"time"
+)
+
+// SDK内部指标，默认不记录，客户端的ClientConfig.EnableMetrics为true时记录该客户端的指标
+var (
+	RequestDuration = NewHistogramVec("nacos_client_request_duration_seconds",
+		"Latency of requests to nacos server.", nil, "module", "api", "code")
+	SubscribedServices = NewGaugeVec("nacos_client_subscribed_services",
+		"Number of services with subscribe callbacks.")
+	ListenConfigs = NewGaugeVec("nacos_client_listen_configs",
+		"Number of configs being listened.")
+	BeatFailures = NewCounterVec("nacos_client_beat_failures_total",
+		"Number of failed heartbeats.")
+	PushReceived = NewCounterVec("nacos_client_push_received_total",
+		"Number of udp push messages received.", "type")
+	PushErrors = NewCounterVec("nacos_client_push_errors_total",
+		"Number of udp push messages failed to decompress or parse.")
+	PushDropped = NewCounterVec("nacos_client_push_dropped_total",
+		"Number of udp push messages dropped without updating cache.", "reason")
+	DiskCache = NewCounterVec("nacos_client_disk_cache_total",
+		"Number of disk cache reads on server failure.", "module", "result")
+	ConfigRejected = NewCounterVec("nacos_client_config_rejected_total",
+		"Number of config changes rejected by validation.")
+	CallbackDropped = NewCounterVec("nacos_client_callback_dropped_total",
+		"Number of subscribe callbacks dropped because the callback queue is full.")
+	SlowRequests = NewHistogramVec("nacos_client_slow_requests_seconds",
+		"Latency of requests exceeding the slow threshold, including retries.", nil, "api")
+)
+
+var defaultRegistry = NewRegistry()
+
+func init() {
+	Register(defaultRegistry)
+}
+
+// 将SDK内部指标注册到registry，可用于与应用自身的指标一同暴露
+func Register(registry *Registry) {
+	registry.register(RequestDuration)
+	registry.register(SubscribedServices)
+	registry.register(ListenConfigs)
+	registry.register(BeatFailures)
+	registry.register(PushReceived)
+	registry.register(PushErrors)
+	registry.register(PushDropped)
+	registry.register(DiskCache)
+	registry.register(ConfigRejected)
+	registry.register(CallbackDropped)
+	registry.register(SlowRequests)
+}
+
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// 客户端的指标记录器，各客户端按ClientConfig.EnableMetrics分别开启，为nil时不记录
+// 开启指标的客户端都记录到上面的同一组指标中
+type Recorder struct{}
+
+// enabled为false时返回nil，registry不为空时额外注册SDK指标
+func NewRecorder(enabled bool, registry *Registry) *Recorder {
+	if !enabled {
+		return nil
+	}
+	if registry != nil {
+		Register(registry)
+	}
+	return &Recorder{}
+}
+
+// err不为空时code记为error
+func (r *Recorder) ObserveRequest(module, api string, statusCode int, err error, start time.Time) {
+	if r == nil {
+		return
+	}
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(statusCode)
+	}
+	RequestDuration.Observe(time.Since(start).Seconds(), module, api, code)
+}
+
+func (r *Recorder) AddSubscribedServices(delta float64) {
+	if r != nil {
+		SubscribedServices.Add(delta)
+	}
+}
+
+func (r *Recorder) AddListenConfigs(delta float64) {
+	if r != nil {
+		ListenConfigs.Add(delta)
+	}
+}
+
+func (r *Recorder) IncBeatFailures() {
+	if r != nil {
+		BeatFailures.Inc()
+	}
+}
+
+func (r *Recorder) IncPushReceived(pushType string) {
+	if r != nil {
+		PushReceived.Inc(pushType)
+	}
+}
+
+func (r *Recorder) IncPushErrors() {
+	if r != nil {
+		PushErrors.Inc()
+	}
+}
+
+// reason为invalid（无法解压或解析）、duplicate（重复推送）或out_of_order（比缓存旧的推送）
+func (r *Recorder) IncPushDropped(reason string) {
+	if r != nil {
+		PushDropped.Inc(reason)
+	}
+}
+
+func (r *Recorder) ObserveDiskCache(module string, hit bool) {
+	if r == nil {
+		return
+	}
+	if hit {
+		DiskCache.Inc(module, "hit")
+	} else {
+		DiskCache.Inc(module, "miss")
+	}
+}
+
+func (r *Recorder) IncConfigRejected() {
+	if r != nil {
+		ConfigRejected.Inc()
+	}
+}
+
+func (r *Recorder) IncCallbackDropped() {
+	if r != nil {
+		CallbackDropped.Inc()
+	}
+}
+
+// 记录耗时超过ClientConfig.Timeout.SlowThresholdMs的请求，elapsed包括所有重试
+func (r *Recorder) ObserveSlowRequest(api string, elapsed time.Duration) {
+	if r != nil {
+		SlowRequests.Observe(elapsed.Seconds(), api)
+	}
+}
@@ -0,0 +1,83 @@
+package monitor
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistry_Write(t *testing.T) {
+	registry := NewRegistry()
+	counter := NewCounterVec("test_total", "Test counter.", "result")
+	gauge := NewGaugeVec("test_gauge", "Test gauge.")
+	histogram := NewHistogramVec("test_seconds", "Test histogram.", []float64{0.1, 1}, "api")
+	registry.register(counter)
+	registry.register(gauge)
+	registry.register(histogram)
+
+	counter.Inc("hit")
+	counter.Add(2, "hit")
+	counter.Add(-1, "hit")
+	gauge.Add(3)
+	gauge.Add(-1)
+	histogram.Observe(0.5, "/v1/ns/instance")
+
+	var buf bytes.Buffer
+	registry.Write(&buf)
+	assert.Equal(t, `# HELP test_gauge Test gauge.
+# TYPE test_gauge gauge
+test_gauge 2
+# HELP test_seconds Test histogram.
+# TYPE test_seconds histogram
+test_seconds_bucket{api="/v1/ns/instance",le="0.1"} 0
+test_seconds_bucket{api="/v1/ns/instance",le="1"} 1
+test_seconds_bucket{api="/v1/ns/instance",le="+Inf"} 1
+test_seconds_sum{api="/v1/ns/instance"} 0.5
+test_seconds_count{api="/v1/ns/instance"} 1
+# HELP test_total Test counter.
+# TYPE test_total counter
+test_total{result="hit"} 3
+`, buf.String())
+}
+
+func TestRecorder(t *testing.T) {
+	var disabled *Recorder
+	disabled.ObserveRequest("naming", "/test/disabled", 200, nil, time.Now())
+	assert.Equal(t, uint64(0), RequestDuration.Count("naming", "/test/disabled", "200"))
+	assert.Nil(t, NewRecorder(false, nil))
+
+	custom := NewRegistry()
+	recorder := NewRecorder(true, custom)
+	recorder.ObserveRequest("naming", "/test", 200, nil, time.Now())
+	recorder.ObserveRequest("naming", "/test", 0, errors.New("timeout"), time.Now())
+	assert.Equal(t, uint64(1), RequestDuration.Count("naming", "/test", "200"))
+	assert.Equal(t, uint64(1), RequestDuration.Count("naming", "/test", "error"))
+	var buf bytes.Buffer
+	custom.Write(&buf)
+	assert.True(t, strings.Contains(buf.String(), `nacos_client_request_duration_seconds_count{module="naming",api="/test",code="error"} 1`))
+
+	recorder.ObserveSlowRequest("/test/slow", 1500*time.Millisecond)
+	assert.Equal(t, uint64(1), SlowRequests.Count("/test/slow"))
+}
+
+func TestRegistry_Gather(t *testing.T) {
+	registry := NewRegistry()
+	counter := NewCounterVec("test_total", "Test counter.", "result")
+	histogram := NewHistogramVec("test_seconds", "Test histogram.", []float64{0.1, 1})
+	registry.register(counter)
+	registry.register(histogram)
+	counter.Add(2, "hit")
+	histogram.Observe(0.5)
+
+	families := registry.Gather()
+	assert.Equal(t, 2, len(families))
+	assert.Equal(t, Family{Name: "test_seconds", Help: "Test histogram.", Type: "histogram", Samples: []Sample{
+		{Buckets: []float64{0.1, 1}, BucketCounts: []uint64{0, 1}, Count: 1, Sum: 0.5},
+	}}, families[0])
+	assert.Equal(t, Family{Name: "test_total", Help: "Test counter.", Type: "counter", LabelNames: []string{"result"}, Samples: []Sample{
+		{LabelValues: []string{"hit"}, Value: 2},
+	}}, families[1])
+}
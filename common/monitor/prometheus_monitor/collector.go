@@ -0,0 +1,66 @@
+//go:build prometheus
+// +build prometheus
+
+// 将SDK指标导出到Prometheus client_golang，需要以-tags prometheus构建，未使用client_golang的应用不引入其依赖
+package prometheus_monitor
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/monitor"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type collector struct {
+	registry *monitor.Registry
+}
+
+// 以client_golang的Collector导出registry中的指标，registry为nil时使用monitor.DefaultRegistry()
+func NewCollector(registry *monitor.Registry) prometheus.Collector {
+	if registry == nil {
+		registry = monitor.DefaultRegistry()
+	}
+	return &collector{registry: registry}
+}
+
+// 将SDK指标注册到调用方提供的Registerer，如prometheus.DefaultRegisterer，重复注册时返回AlreadyRegisteredError
+// 只导出ClientConfig.EnableMetrics为true的客户端记录的指标
+func Register(registerer prometheus.Registerer, registry *monitor.Registry) error {
+	return registerer.Register(NewCollector(registry))
+}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, family := range c.registry.Gather() {
+		ch <- newDesc(family)
+	}
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	for _, family := range c.registry.Gather() {
+		desc := newDesc(family)
+		for _, sample := range family.Samples {
+			var metric prometheus.Metric
+			var err error
+			switch family.Type {
+			case "counter":
+				metric, err = prometheus.NewConstMetric(desc, prometheus.CounterValue, sample.Value, sample.LabelValues...)
+			case "gauge":
+				metric, err = prometheus.NewConstMetric(desc, prometheus.GaugeValue, sample.Value, sample.LabelValues...)
+			case "histogram":
+				buckets := make(map[float64]uint64, len(sample.Buckets))
+				for i, bound := range sample.Buckets {
+					buckets[bound] = sample.BucketCounts[i]
+				}
+				metric, err = prometheus.NewConstHistogram(desc, sample.Count, sample.Sum, buckets, sample.LabelValues...)
+			default:
+				continue
+			}
+			if err != nil {
+				metric = prometheus.NewInvalidMetric(desc, err)
+			}
+			ch <- metric
+		}
+	}
+}
+
+func newDesc(family monitor.Family) *prometheus.Desc {
+	return prometheus.NewDesc(family.Name, family.Help, family.LabelNames, nil)
+}
@@ -0,0 +1,38 @@
+//go:build prometheus
+// +build prometheus
+
+package prometheus_monitor
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/monitor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegister(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	assert.Nil(t, Register(registry, nil))
+	assert.NotNil(t, Register(registry, nil), "collector should be registered only once")
+
+	recorder := monitor.NewRecorder(true, nil)
+	recorder.IncPushDropped("prometheus_test")
+	recorder.ObserveSlowRequest("/prometheus/test", 2*time.Second)
+
+	families, err := registry.Gather()
+	assert.Nil(t, err)
+	found := map[string]bool{}
+	for _, family := range families {
+		found[family.GetName()] = true
+	}
+	assert.True(t, found["nacos_client_push_dropped_total"])
+	assert.True(t, found["nacos_client_slow_requests_seconds"])
+	assert.Nil(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP nacos_client_push_dropped_total Number of udp push messages dropped without updating cache.
+# TYPE nacos_client_push_dropped_total counter
+nacos_client_push_dropped_total{reason="prometheus_test"} 1
+`), "nacos_client_push_dropped_total"))
+}
@@ -1,8 +1,11 @@
 package nacos_error
 
 import (
+	"errors"
 	"fmt"
 	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"strconv"
+	"strings"
 )
 
 /**
@@ -14,6 +17,17 @@ import (
 * @create : 2019-01-14 11:22
 **/
 
+// 常见的服务端错误，可通过errors.Is判断，如errors.Is(err, nacos_error.ErrNotFound)
+var (
+	ErrForbidden         = NewNacosError("403", "forbidden", nil)
+	ErrNotFound          = NewNacosError("404", "not found", nil)
+	ErrConflict          = NewNacosError("409", "conflict", nil)
+	ErrServerUnavailable = NewNacosError("503", "server unavailable", nil)
+)
+
+// 服务端报告资源冲突时响应中的错误码
+const Resource_Conflict = "RESOURCE_CONFLICT"
+
 type NacosError struct {
 	errorCode   string
 	errMsg      string
@@ -29,6 +43,11 @@ func NewNacosError(errorCode string, errMsg string, originError error) *NacosErr
 
 }
 
+// 包装客户端内部错误，originError中的错误码仍可通过errors.Is/As取得
+func Wrap(errMsg string, originError error) *NacosError {
+	return NewNacosError(constant.DefaultClientErrorCode, errMsg, originError)
+}
+
 func (err *NacosError) Error() (str string) {
 	nacosErrMsg := fmt.Sprintf("[%s] %s", err.ErrorCode(), err.errMsg)
 	if err.originError != nil {
@@ -44,3 +63,36 @@ func (err *NacosError) ErrorCode() string {
 		return err.errorCode
 	}
 }
+
+func (err *NacosError) ErrMsg() string {
+	return err.errMsg
+}
+
+func (err *NacosError) Unwrap() error {
+	return err.originError
+}
+
+// 错误码相同即视为同一类错误，ErrServerUnavailable匹配所有5xx错误
+func (err *NacosError) Is(target error) bool {
+	t, ok := target.(*NacosError)
+	if !ok {
+		return false
+	}
+	if t == ErrServerUnavailable {
+		code, e := strconv.Atoi(err.ErrorCode())
+		return e == nil && code >= 500 && code < 600
+	}
+	return err.ErrorCode() == t.ErrorCode()
+}
+
+// 服务端以409或错误码RESOURCE_CONFLICT报告资源冲突，如CAS发布时内容已被他人修改，重试不会成功
+func IsConflict(err error) bool {
+	var nacosErr *NacosError
+	for err != nil && errors.As(err, &nacosErr) {
+		if nacosErr.ErrorCode() == ErrConflict.ErrorCode() || strings.Contains(nacosErr.errMsg, Resource_Conflict) {
+			return true
+		}
+		err = nacosErr.originError
+	}
+	return false
+}
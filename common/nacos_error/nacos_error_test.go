@@ -0,0 +1,38 @@
+package nacos_error
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNacosError_Is(t *testing.T) {
+	err := NewNacosError("404", "config data not exist", nil)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.False(t, errors.Is(err, ErrForbidden))
+	assert.False(t, errors.Is(err, ErrServerUnavailable))
+
+	assert.True(t, errors.Is(NewNacosError("500", "", nil), ErrServerUnavailable))
+	assert.True(t, errors.Is(NewNacosError("502", "", nil), ErrServerUnavailable))
+	assert.False(t, errors.Is(NewNacosError("", "", errors.New("io error")), ErrServerUnavailable))
+}
+
+func TestNacosError_Wrap(t *testing.T) {
+	origin := NewNacosError("409", "conflict", nil)
+	err := Wrap("[client.PublishConfig] publish config failed", origin)
+	assert.True(t, errors.Is(err, ErrConflict))
+
+	var nacosErr *NacosError
+	assert.True(t, errors.As(err, &nacosErr))
+	assert.Equal(t, "[client.PublishConfig] publish config failed", nacosErr.ErrMsg())
+	assert.Equal(t, origin, errors.Unwrap(err))
+}
+
+func TestIsConflict(t *testing.T) {
+	assert.True(t, IsConflict(NewNacosError("409", "conflict", nil)))
+	assert.True(t, IsConflict(NewNacosError("500", `{"code":500,"message":"RESOURCE_CONFLICT","data":"Cas publish fail"}`, nil)))
+	assert.True(t, IsConflict(Wrap("retry 3 times request failed!", NewNacosError("409", "", nil))))
+	assert.False(t, IsConflict(NewNacosError("500", "server error", nil)))
+	assert.False(t, IsConflict(errors.New("RESOURCE_CONFLICT")))
+	assert.False(t, IsConflict(nil))
+}
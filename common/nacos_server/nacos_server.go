@@ -1,60 +1,519 @@
 package nacos_server
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha1"
 	"encoding/base64"
 	"errors"
-	"fmt"
+	"github.com/nacos-group/nacos-sdk-go/common/config_bus"
 	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/credentials"
+	"github.com/nacos-group/nacos-sdk-go/common/event"
 	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/common/monitor"
 	"github.com/nacos-group/nacos-sdk-go/common/nacos_error"
+	"github.com/nacos-group/nacos-sdk-go/common/rate_limiter"
+	"github.com/nacos-group/nacos-sdk-go/common/remote"
+	"github.com/nacos-group/nacos-sdk-go/common/retry"
+	"github.com/nacos-group/nacos-sdk-go/common/security"
+	"github.com/nacos-group/nacos-sdk-go/common/server_list"
+	"github.com/nacos-group/nacos-sdk-go/common/tracing"
 	"github.com/nacos-group/nacos-sdk-go/utils"
 	"github.com/satori/go.uuid"
 	"io/ioutil"
-	"log"
 	"math/rand"
+	"net"
 	"net/http"
-	"reflect"
+	neturl "net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type NacosServer struct {
-	sync.RWMutex
-	serverList          []constant.ServerConfig
-	httpAgent           http_agent.IHttpAgent
+	serverManager *server_list.ServerListManager
+	httpAgent     http_agent.IHttpAgent
+	securityLogin *security.AuthClient
+	settings      *serverSettings
+	bus           *config_bus.ConfigBus
+	events        *event.EventBus
+	tracer        tracing.Tracer
+	connection    *connectionState
+	tlsEnable     bool
+	shared        bool
+	metrics       *monitor.Recorder
+	transport     remote.Transport
+}
+
+// 与服务端的连接状态，请求在所有重试后仍失败时视为断开，之后首次请求成功时视为重新连接
+type connectionState struct {
+	disconnected int32
+}
+
+// 请求成功时的服务端地址和响应头，通过WithResponseInfo传入请求的ctx后由请求填充
+type ResponseInfo struct {
+	Server string
+	Header http.Header
+}
+
+type responseInfoKey struct{}
+
+// 返回的ctx用于请求服务端时，请求成功后info中为最后一次请求的服务端和响应头
+func WithResponseInfo(ctx context.Context) (context.Context, *ResponseInfo) {
+	info := &ResponseInfo{}
+	return context.WithValue(ctx, responseInfoKey{}, info), info
+}
+
+func setResponseInfo(ctx context.Context, server string, response *http.Response) {
+	if info, ok := ctx.Value(responseInfoKey{}).(*ResponseInfo); ok {
+		info.Server, info.Header = server, response.Header
+	}
+}
+
+type noRetryKey struct{}
+
+// 返回的ctx用于请求服务端时，服务端返回statusCodes中的状态码后直接返回错误，不重试也不视为连接断开
+// 用于探测服务端是否支持某个接口
+func WithNoRetry(ctx context.Context, statusCodes ...int) context.Context {
+	return context.WithValue(ctx, noRetryKey{}, statusCodes)
+}
+
+func noRetry(ctx context.Context, err error) bool {
+	codes, _ := ctx.Value(noRetryKey{}).([]int)
+	nacosErr, ok := err.(*nacos_error.NacosError)
+	if !ok {
+		return false
+	}
+	for _, code := range codes {
+		if nacosErr.ErrorCode() == strconv.Itoa(code) {
+			return true
+		}
+	}
+	return false
+}
+
+// 可在运行时更新的设置，NacosServer的各个副本共享同一份
+type serverSettings struct {
+	mutex               sync.RWMutex
 	timeoutMs           uint64
-	endpoint            string
-	lastSrvRefTime      int64
-	vipSrvRefInterMills int64
+	timeout             constant.TimeoutConfig
+	retryPolicy         *retry.RetryPolicy
+	credentialsProvider credentials.CredentialsProvider
+	rateLimit           *rate_limiter.RateLimitConfig
+	limiter             *rate_limiter.RequestLimiter
+	identityHeaders     map[string]string
+}
+
+func (s *serverSettings) update(clientCfg constant.ClientConfig) {
+	credentialsProvider := newCredentialsProvider(clientCfg)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.timeoutMs = clientCfg.TimeoutMs
+	s.timeout = constant.TimeoutConfig{}
+	if clientCfg.Timeout != nil {
+		s.timeout = *clientCfg.Timeout
+	}
+	s.retryPolicy = clientCfg.RetryPolicy
+	s.credentialsProvider = credentialsProvider
+	s.identityHeaders = identityHeaders(clientCfg)
+	// 限流配置不变时保留原限流器中的令牌
+	if clientCfg.RateLimit == nil {
+		s.rateLimit, s.limiter = nil, nil
+	} else if s.rateLimit == nil || *s.rateLimit != *clientCfg.RateLimit {
+		rateLimit := *clientCfg.RateLimit
+		s.rateLimit, s.limiter = &rateLimit, rate_limiter.NewRequestLimiter(rateLimit)
+	}
+}
+
+// 每个请求上报的应用名和标签，标签按key排序后以k1=v1,k2=v2的格式上报
+func identityHeaders(clientCfg constant.ClientConfig) map[string]string {
+	headers := map[string]string{}
+	if clientCfg.AppName != "" {
+		headers[constant.CLIENT_APP_NAME_HEADER] = clientCfg.AppName
+	}
+	if len(clientCfg.Labels) > 0 {
+		keys := make([]string, 0, len(clientCfg.Labels))
+		for k := range clientCfg.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		labels := make([]string, 0, len(keys))
+		for _, k := range keys {
+			labels = append(labels, k+"="+clientCfg.Labels[k])
+		}
+		headers[constant.CLIENT_LABELS_HEADER] = strings.Join(labels, ",")
+	}
+	return headers
 }
 
-func NewNacosServer(serverList []constant.ServerConfig, httpAgent http_agent.IHttpAgent, timeoutMs uint64, endpoint string) (NacosServer, error) {
-	if len(serverList) == 0 && endpoint == "" {
-		return NacosServer{}, errors.New("both serverlist  and  endpoint are empty")
+func NewNacosServer(serverList []constant.ServerConfig, clientCfg constant.ClientConfig, httpAgent http_agent.IHttpAgent) (NacosServer, error) {
+	if clientCfg.Agent != nil {
+		serverList = []constant.ServerConfig{agentServer(*clientCfg.Agent)}
+		clientCfg.Endpoint = ""
+	}
+	var serverManager *server_list.ServerListManager
+	if clientCfg.CacheOnly {
+		//仅使用缓存时不访问地址服务器，可以不配置服务端
+		serverManager = server_list.NewLocalServerListManager(serverList)
+	} else {
+		var err error
+		if serverManager, err = server_list.NewServerListManager(serverList, clientCfg.Endpoint, clientCfg.ContextPath, httpAgent, clientCfg.TimeoutMs); err != nil {
+			return NacosServer{}, err
+		}
+	}
+	if clientCfg.CircuitBreaker != nil {
+		serverManager.SetCircuitBreaker(*clientCfg.CircuitBreaker)
 	}
 	ns := NacosServer{
-		serverList:          serverList,
-		httpAgent:           httpAgent,
-		timeoutMs:           timeoutMs,
-		endpoint:            endpoint,
-		vipSrvRefInterMills: 10000,
+		serverManager: serverManager,
+		httpAgent:     httpAgent,
+		securityLogin: security.NewAuthClient(clientCfg, httpAgent),
+		settings:      &serverSettings{},
+		bus:           config_bus.NewConfigBus(clientCfg, serverList),
+		events:        event.NewEventBus(),
+		tracer:        tracing.NewTracer(clientCfg.TracerProvider),
+		connection:    &connectionState{},
+		tlsEnable:     clientCfg.TLSConfig.Enable,
+		metrics:       monitor.NewRecorder(clientCfg.EnableMetrics, clientCfg.MetricsRegistry),
 	}
-	ns.initRefreshSrvIfNeed()
+	ns.events.Subscribe(clientCfg.EventListener)
+	serverManager.SetEventBus(ns.events)
+	ns.settings.update(clientCfg)
+	ns.subscribeConfigChanges()
+	if clientCfg.CacheOnly {
+		return ns, nil
+	}
+	if _, err := ns.securityLogin.Login(ns.GetServerList()); err != nil {
+		logger.Errorf("login to nacos server failed,err:%s", err.Error())
+	}
+	ns.securityLogin.AutoRefresh(serverManager.GetServerList)
+	ns.transport = ns.startTransport(clientCfg)
 	return ns, nil
 }
 
-func (server *NacosServer) callConfigServer(api string, params map[string]string, newHeaders map[string]string, method string, curServer string, contextPath string) (result string, err error) {
+// 配置了ClientConfig.Transport时建立长连接，无法建立时返回nil，订阅和监听使用HTTP
+// 通过sidecar访问服务端时只使用HTTP
+func (server *NacosServer) startTransport(clientCfg constant.ClientConfig) remote.Transport {
+	if clientCfg.Transport == nil || clientCfg.Agent != nil {
+		return nil
+	}
+	config := remote.Config{
+		Servers: func() []remote.Server {
+			var servers []remote.Server
+			for _, srv := range server.GetServerList() {
+				servers = append(servers, remote.Server{IpAddr: srv.IpAddr, Port: srv.Port})
+			}
+			return servers
+		},
+		NamespaceId: clientCfg.NamespaceId,
+		AppName:     clientCfg.AppName,
+		Labels:      clientCfg.Labels,
+		TimeoutMs:   clientCfg.TimeoutMs,
+		Headers:     server.transportHeaders,
+	}
+	if clientCfg.TLSConfig.Enable {
+		tlsConfig, err := http_agent.NewTLSConfig(clientCfg.TLSConfig)
+		if err != nil {
+			logger.Errorf("create tls config for long connection failed, fall back to http,err:%s", err.Error())
+			return nil
+		}
+		config.TLSConfig = tlsConfig
+	}
+	transport, err := clientCfg.Transport(config)
+	if err != nil {
+		logger.Errorf("create long connection transport failed, fall back to http,err:%s", err.Error())
+		return nil
+	}
+	if err = transport.Start(); err != nil {
+		logger.Warnf("connect to nacos server with long connection failed, fall back to http,err:%s", err.Error())
+		transport.Close()
+		return nil
+	}
+	return transport
+}
+
+// 长连接的每个请求附加accessToken和客户端身份请求头
+func (server *NacosServer) transportHeaders() map[string]string {
+	headers := map[string]string{}
+	for k, v := range server.IdentityHeaders() {
+		headers[k] = v
+	}
+	if server.securityLogin != nil {
+		if accessToken := server.securityLogin.GetAccessToken(); accessToken != "" {
+			headers["accessToken"] = accessToken
+		}
+	}
+	return headers
+}
+
+// 与服务端的长连接，未配置ClientConfig.Transport或服务端不支持长连接时为nil
+func (server *NacosServer) Transport() remote.Transport {
+	return server.transport
+}
+
+// 通过unix socket访问sidecar时请求地址不会被使用，只用于日志和区分服务端
+const Agent_Unix_Host = "nacos-agent"
+
+// sidecar作为唯一的服务端
+func agentServer(agent constant.AgentConfig) constant.ServerConfig {
+	server := constant.ServerConfig{IpAddr: Agent_Unix_Host, Port: 80, Scheme: "http", ContextPath: agent.ContextPath}
+	if agent.UnixSocket == "" {
+		host, port, err := net.SplitHostPort(agent.Address)
+		if p, e := strconv.ParseUint(port, 10, 64); err == nil && e == nil {
+			server.IpAddr, server.Port = host, p
+		} else {
+			logger.Errorf("invalid agent address:%s", agent.Address)
+		}
+	}
+	if server.ContextPath == "" {
+		server.ContextPath = constant.DEFAULT_CONTEXT_PATH
+	}
+	return server
+}
+
+// 各组件通过配置总线感知超时、凭证、重试熔断策略和服务端列表的变化
+func (server *NacosServer) subscribeConfigChanges() {
+	serverManager := server.serverManager
+	securityLogin := server.securityLogin
+	server.bus.OnClientConfigChanged(func(oldConfig constant.ClientConfig, newConfig constant.ClientConfig) {
+		server.settings.update(newConfig)
+	})
+	server.bus.OnClientConfigChanged(func(oldConfig constant.ClientConfig, newConfig constant.ClientConfig) {
+		serverManager.SetTimeoutMs(newConfig.TimeoutMs)
+		if newConfig.CircuitBreaker != nil {
+			serverManager.SetCircuitBreaker(*newConfig.CircuitBreaker)
+		}
+	})
+	server.bus.OnClientConfigChanged(func(oldConfig constant.ClientConfig, newConfig constant.ClientConfig) {
+		if securityLogin.UpdateConfig(newConfig) {
+			if _, err := securityLogin.Login(serverManager.GetServerList()); err != nil {
+				logger.Errorf("login to nacos server with new credentials failed,err:%s", err.Error())
+			}
+		}
+	})
+	server.bus.OnServerConfigChanged(serverManager.SetServerList)
+	server.bus.OnServerConfigChanged(func(servers []constant.ServerConfig) {
+		if _, err := securityLogin.Login(servers); err != nil {
+			logger.Errorf("login to nacos server failed,err:%s", err.Error())
+		}
+	})
+}
+
+// 运行时更新客户端配置，生效的有TimeoutMs、用户名密码、签名凭证、RetryPolicy、CircuitBreaker和RateLimit
+// 多个客户端共享同一NacosServer时对所有客户端生效
+func (server *NacosServer) UpdateClientConfig(clientCfg constant.ClientConfig) {
+	if server.bus == nil {
+		return
+	}
+	server.bus.PublishClientConfig(clientCfg)
+}
+
+// 运行时替换服务端列表，配置了Endpoint时新列表会在下次从地址服务器拉取后被覆盖
+func (server *NacosServer) UpdateServerConfig(serverList []constant.ServerConfig) error {
+	if len(serverList) == 0 {
+		return errors.New("[client.UpdateServerConfig] server configs can not be empty")
+	}
+	if server.bus == nil {
+		return errors.New("[client.UpdateServerConfig] nacos server is not initialized")
+	}
+	server.bus.PublishServerConfigs(serverList)
+	return nil
+}
+
+// 生命周期事件总线，共享同一NacosServer的客户端共享同一总线
+func (server *NacosServer) Events() *event.EventBus {
+	return server.events
+}
+
+// 返回上报客户端身份的请求头，未配置AppName和Labels时为空
+func (server *NacosServer) IdentityHeaders() map[string]string {
+	if server.settings == nil {
+		return nil
+	}
+	server.settings.mutex.RLock()
+	defer server.settings.mutex.RUnlock()
+	return server.settings.identityHeaders
+}
+
+// 每次请求的超时时间，配置了Timeout.RequestTimeoutMs时优先使用
+func (server *NacosServer) getTimeoutMs() uint64 {
+	server.settings.mutex.RLock()
+	defer server.settings.mutex.RUnlock()
+	if server.settings.timeout.RequestTimeoutMs > 0 {
+		return server.settings.timeout.RequestTimeoutMs
+	}
+	return server.settings.timeoutMs
+}
+
+func (server *NacosServer) getTimeout() constant.TimeoutConfig {
+	if server.settings == nil {
+		return constant.TimeoutConfig{}
+	}
+	server.settings.mutex.RLock()
+	defer server.settings.mutex.RUnlock()
+	return server.settings.timeout
+}
+
+// 按ClientConfig.RateLimit等待category类别的令牌，FastFail时超出速率返回rate_limiter.ErrRateLimited
+func (server *NacosServer) WaitRateLimit(ctx context.Context, category string) error {
+	if server.settings == nil {
+		return nil
+	}
+	server.settings.mutex.RLock()
+	limiter := server.settings.limiter
+	server.settings.mutex.RUnlock()
+	return limiter.Wait(ctx, category)
+}
+
+// 心跳和查询类请求参与限流，注册、注销、发布等写请求不限流
+func requestCategory(api string, method string) string {
+	if api == constant.SERVICE_BASE_PATH+"/instance/beat" {
+		return rate_limiter.CATEGORY_BEAT
+	}
+	if method == http.MethodGet {
+		return rate_limiter.CATEGORY_QUERY
+	}
+	return ""
+}
+
+func (server *NacosServer) getCredentialsProvider() credentials.CredentialsProvider {
+	server.settings.mutex.RLock()
+	defer server.settings.mutex.RUnlock()
+	return server.settings.credentialsProvider
+}
+
+// 依次使用自定义Provider、AccessKey/SecretKey（STS）、ECS RAM角色获取签名凭证，均未配置时不签名
+func newCredentialsProvider(clientCfg constant.ClientConfig) credentials.CredentialsProvider {
+	if clientCfg.CredentialsProvider != nil {
+		return clientCfg.CredentialsProvider
+	}
+	var providers []credentials.CredentialsProvider
+	if clientCfg.AccessKey != "" {
+		providers = append(providers, credentials.NewStaticCredentialsProvider(clientCfg.AccessKey, clientCfg.SecretKey, clientCfg.SecurityToken))
+	}
+	if clientCfg.RamRoleName != "" {
+		providers = append(providers, credentials.NewEcsRamRoleCredentialsProvider(clientCfg.RamRoleName))
+	}
+	if len(providers) == 0 {
+		return nil
+	}
+	return credentials.NewChainCredentialsProvider(providers...)
+}
+
+// 获取签名凭证，未配置凭证时返回传入的AccessKey/SecretKey
+func (server *NacosServer) getCredentials(accessKey, secretKey string) credentials.Credentials {
+	credentialsProvider := server.getCredentialsProvider()
+	if credentialsProvider == nil {
+		return credentials.Credentials{AccessKey: accessKey, SecretKey: secretKey}
+	}
+	creds, err := credentialsProvider.GetCredentials()
+	if err != nil {
+		logger.Errorf("get credentials failed,err:%s", err.Error())
+		return credentials.Credentials{AccessKey: accessKey, SecretKey: secretKey}
+	}
+	return creds
+}
+
+// 返回共享的副本，副本与原对象使用同一服务端列表和token，调用副本的Stop不会停止后台任务
+func (server NacosServer) Shared() NacosServer {
+	server.shared = true
+	return server
+}
+
+// 停止后台的token刷新并关闭长连接
+func (server *NacosServer) Stop() {
+	if server.shared {
+		return
+	}
+	if server.transport != nil {
+		server.transport.Close()
+	}
+	if server.securityLogin != nil {
+		server.securityLogin.Stop()
+	}
+	if server.serverManager != nil {
+		server.serverManager.Stop()
+	}
+}
+
+// 网络错误或服务端5xx时计为一次失败
+func (server *NacosServer) markServer(curServer string, response *http.Response, err error) {
+	if server.serverManager == nil {
+		return
+	}
+	if err != nil || response.StatusCode >= http.StatusInternalServerError {
+		server.serverManager.MarkFailure(curServer)
+	} else {
+		server.serverManager.MarkSuccess(curServer)
+	}
+}
+
+func (server *NacosServer) observeRequest(module string, api string, response *http.Response, err error, start time.Time) {
+	statusCode := 0
+	if err == nil {
+		statusCode = response.StatusCode
+	}
+	server.metrics.ObserveRequest(module, api, statusCode, err, start)
+}
+
+// 客户端的指标记录器，未开启EnableMetrics时为nil
+func (server *NacosServer) Metrics() *monitor.Recorder {
+	return server.metrics
+}
+
+// 服务端开启鉴权时，在请求参数中附加accessToken
+func (server *NacosServer) injectSecurityInfo(params map[string]string) map[string]string {
+	if server.securityLogin == nil {
+		return params
+	}
+	accessToken := server.securityLogin.GetAccessToken()
+	if accessToken == "" {
+		return params
+	}
+	securedParams := make(map[string]string, len(params)+1)
+	for k, v := range params {
+		securedParams[k] = v
+	}
+	securedParams["accessToken"] = accessToken
+	return securedParams
+}
+
+// 配置请求的签名和客户端身份请求头
+func (server *NacosServer) configSecurityHeaders(params map[string]string, accessKey, secretKey string) map[string]string {
+	creds := server.getCredentials(accessKey, secretKey)
+	signHeaders := getSignHeaders(params, map[string]string{"secretKey": creds.SecretKey})
+	headers := map[string]string{
+		"Spas-AccessKey": creds.AccessKey,
+		"Timestamp":      signHeaders["timeStamp"],
+		"Spas-Signature": signHeaders["Spas-Signature"],
+	}
+	if creds.SecurityToken != "" {
+		headers["Spas-SecurityToken"] = creds.SecurityToken
+	}
+	for k, v := range server.IdentityHeaders() {
+		headers[k] = v
+	}
+	return headers
+}
+
+// 配置监听的长轮询由调用方逐个服务端直接发起，不经过ReqConfigApi的重试和熔断
+// 返回附加了accessToken的参数，以及与其他配置请求相同的签名和身份请求头，每次轮询前调用以使用最新的token
+func (server *NacosServer) SecureConfigRequest(params map[string]string, accessKey, secretKey string) (map[string]string, map[string]string) {
+	return server.injectSecurityInfo(params), server.configSecurityHeaders(params, accessKey, secretKey)
+}
+
+// body不为nil时以body作为请求体，params拼接在url中
+func (server *NacosServer) callConfigServer(ctx context.Context, api string, params map[string]string, newHeaders map[string]string, body []byte, method string, scheme string, curServer string, contextPath string) (result string, err error) {
 	if contextPath == "" {
 		contextPath = constant.WEB_CONTEXT
 	}
 
-	signHeaders := getSignHeaders(params, newHeaders)
-
-	url := "http://" + curServer + contextPath + api
+	url := scheme + "://" + curServer + contextPath + api
 	headers := map[string][]string{}
 	headers["Client-Version"] = []string{constant.CLIENT_VERSION}
 	headers["User-Agent"] = []string{constant.CLIENT_VERSION}
@@ -64,12 +523,28 @@ func (server *NacosServer) callConfigServer(api string, params map[string]string
 	headers["RequestId"] = []string{uuid.NewV4().String()}
 	headers["Request-Module"] = []string{"Naming"}
 	headers["Content-Type"] = []string{"application/x-www-form-urlencoded;charset=GBK"}
-	headers["Spas-AccessKey"] = []string{newHeaders["accessKey"]}
-	headers["Timestamp"] = []string{signHeaders["timeStamp"]}
-	headers["Spas-Signature"] = []string{signHeaders["Spas-Signature"]}
+	for k, v := range server.configSecurityHeaders(params, newHeaders["accessKey"], newHeaders["secretKey"]) {
+		headers[k] = []string{v}
+	}
+	for k, v := range newHeaders {
+		if k != "accessKey" && k != "secretKey" {
+			headers[k] = []string{v}
+		}
+	}
 
 	var response *http.Response
-	response, err = server.httpAgent.Request(method, url, headers, server.timeoutMs, params)
+	start := time.Now()
+	if body != nil {
+		query := neturl.Values{}
+		for k, v := range server.injectSecurityInfo(params) {
+			query.Set(k, v)
+		}
+		response, err = server.httpAgent.RequestWithBody(ctx, method, url+"?"+query.Encode(), headers, server.getTimeoutMs(), body)
+	} else {
+		response, err = server.httpAgent.RequestWithContext(ctx, method, url, headers, server.getTimeoutMs(), server.injectSecurityInfo(params))
+	}
+	server.markServer(curServer, response, err)
+	server.observeRequest("config", api, response, err, start)
 	if err != nil {
 		return
 	}
@@ -79,8 +554,15 @@ func (server *NacosServer) callConfigServer(api string, params map[string]string
 	if err != nil {
 		return
 	}
+	// 显式设置了Accept-Encoding时http.Transport不会自动解压
+	if strings.EqualFold(response.Header.Get("Content-Encoding"), "gzip") {
+		if bytes, err = utils.DecompressData(bytes); err != nil {
+			return
+		}
+	}
 	result = string(bytes)
 	if response.StatusCode == 200 {
+		setResponseInfo(ctx, curServer, response)
 		return
 	} else {
 		err = nacos_error.NewNacosError(strconv.Itoa(response.StatusCode), string(bytes), nil)
@@ -88,12 +570,12 @@ func (server *NacosServer) callConfigServer(api string, params map[string]string
 	}
 }
 
-func (server *NacosServer) callServer(api string, params map[string]string, method string, curServer string, contextPath string) (result string, err error) {
+func (server *NacosServer) callServer(ctx context.Context, api string, params map[string]string, method string, scheme string, curServer string, contextPath string) (result string, err error) {
 	if contextPath == "" {
 		contextPath = constant.WEB_CONTEXT
 	}
 
-	url := "http://" + curServer + contextPath + api
+	url := scheme + "://" + curServer + contextPath + api
 	headers := map[string][]string{}
 	headers["Client-Version"] = []string{constant.CLIENT_VERSION}
 	headers["User-Agent"] = []string{constant.CLIENT_VERSION}
@@ -102,9 +584,15 @@ func (server *NacosServer) callServer(api string, params map[string]string, meth
 	headers["RequestId"] = []string{uuid.NewV4().String()}
 	headers["Request-Module"] = []string{"Naming"}
 	headers["Content-Type"] = []string{"application/x-www-form-urlencoded;charset=GBK"}
+	for k, v := range server.IdentityHeaders() {
+		headers[k] = []string{v}
+	}
 
 	var response *http.Response
-	response, err = server.httpAgent.Request(method, url, headers, server.timeoutMs, params)
+	start := time.Now()
+	response, err = server.httpAgent.RequestWithContext(ctx, method, url, headers, server.getTimeoutMs(), server.injectSignature(server.injectSecurityInfo(params)))
+	server.markServer(curServer, response, err)
+	server.observeRequest("naming", api, response, err, start)
 	if err != nil {
 		return
 	}
@@ -116,134 +604,218 @@ func (server *NacosServer) callServer(api string, params map[string]string, meth
 	}
 	result = string(bytes)
 	if response.StatusCode == 200 {
+		setResponseInfo(ctx, curServer, response)
 		return
 	} else {
-		err = errors.New(fmt.Sprintf("request return error code %d", response.StatusCode))
+		err = nacos_error.NewNacosError(strconv.Itoa(response.StatusCode), string(bytes), nil)
 		return
 	}
 }
 
-func (server *NacosServer) ReqConfigApi(api string, params map[string]string, headers map[string]string, method string) (string, error) {
-	srvs := server.serverList
-	if srvs == nil || len(srvs) == 0 {
-		return "", errors.New("server list is empty")
+func (server *NacosServer) ReqConfigApi(ctx context.Context, api string, params map[string]string, headers map[string]string, method string) (string, error) {
+	return server.request(ctx, api, params, method, func(ctx context.Context, curServer constant.ServerConfig) (string, error) {
+		return server.callConfigServer(ctx, api, params, headers, nil, method, server_list.GetScheme(curServer, server.tlsEnable), getAddress(curServer), curServer.ContextPath)
+	})
+}
+
+// 以body作为请求体请求配置接口，headers中需指定Content-Type
+func (server *NacosServer) ReqConfigApiWithBody(ctx context.Context, api string, params map[string]string, headers map[string]string, body []byte, method string) (string, error) {
+	return server.request(ctx, api, params, method, func(ctx context.Context, curServer constant.ServerConfig) (string, error) {
+		return server.callConfigServer(ctx, api, params, headers, body, method, server_list.GetScheme(curServer, server.tlsEnable), getAddress(curServer), curServer.ContextPath)
+	})
+}
+
+func (server *NacosServer) ReqApi(ctx context.Context, api string, params map[string]string, method string) (string, error) {
+	return server.request(ctx, api, params, method, func(ctx context.Context, curServer constant.ServerConfig) (string, error) {
+		return server.callServer(ctx, api, params, method, server_list.GetScheme(curServer, server.tlsEnable), getAddress(curServer), curServer.ContextPath)
+	})
+}
+
+// 按重试策略轮流请求健康的服务端，熔断中的服务端不参与选择
+// 配置了Timeout.TotalTimeoutMs时所有重试共用该时长，超出后返回context.DeadlineExceeded
+func (server *NacosServer) request(ctx context.Context, api string, params map[string]string, method string,
+	call func(ctx context.Context, curServer constant.ServerConfig) (string, error)) (result string, err error) {
+	timeout := server.getTimeout()
+	if timeout.TotalTimeoutMs > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout.TotalTimeoutMs)*time.Millisecond)
+		defer cancel()
 	}
-	//only one server,retry request when error
-	var err error
-	var result string
-	if len(srvs) == 1 {
-		for i := 0; i < constant.REQUEST_DOMAIN_RETRY_TIME; i++ {
-			result, err = server.callConfigServer(api, params, headers, method, getAddress(srvs[0]), srvs[0].ContextPath)
-			if err == nil {
-				return result, nil
-			}
-			log.Printf("[ERROR] api<%s>,method:<%s>, params:<%s>, call domain error:<%s> , result:<%s> \n", api, method, utils.ToJsonString(params), err.Error(), result)
+	ctx, span := server.Tracer().Start(ctx, method+" "+api, requestAttributes(params)...)
+	start := time.Now()
+	var lastServer string
+	attempts := 0
+	defer func() {
+		span.SetAttributes(tracing.Attribute{Key: tracing.ATTR_SERVER_ADDRESS, Value: lastServer},
+			tracing.Attribute{Key: tracing.ATTR_ATTEMPTS, Value: strconv.Itoa(attempts)})
+		tracing.End(span, err)
+		server.logSlowRequest(timeout, api, method, params, lastServer, attempts, time.Since(start), err)
+	}()
+	srvs := server.GetHealthyServerList()
+	if len(srvs) == 0 {
+		server.markDisconnected()
+		return "", nacos_error.NewNacosError(strconv.Itoa(http.StatusServiceUnavailable), "server list is empty", nil)
+	}
+	policy := server.getRetryPolicy()
+	category := requestCategory(api, method)
+	index := rand.Intn(len(srvs))
+	for attempt := 1; attempt <= policy.Attempts(); attempt++ {
+		attempts = attempt
+		if attempt > 1 {
+			srvs = server.GetHealthyServerList()
 		}
-		return "", err
-	} else {
-		index := rand.Intn(len(srvs))
-		for i := 1; i <= len(srvs); i++ {
-			curServer := srvs[index]
-			result, err = server.callConfigServer(api, params, headers, method, getAddress(curServer), curServer.ContextPath)
-			if err == nil {
-				return result, nil
+		curServer := srvs[(index+attempt-1)%len(srvs)]
+		if address := getAddress(curServer); lastServer != "" && address != lastServer {
+			server.events.Publish(event.ServerSwitchedEvent{From: lastServer, To: address, Err: err})
+		}
+		lastServer = getAddress(curServer)
+		if limitErr := server.WaitRateLimit(ctx, category); limitErr != nil {
+			return "", limitErr
+		}
+		result, err = call(ctx, curServer)
+		if err == nil {
+			server.markConnected(lastServer)
+			return result, nil
+		}
+		logger.Errorf("api<%s>,method:<%s>, params:<%s>, call domain error:<%s> , result:<%s>", api, method, utils.ToJsonString(params), err.Error(), result)
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if !isRetryable(policy, err) || noRetry(ctx, err) {
+			return "", err
+		}
+		if attempt < policy.Attempts() {
+			if sleepErr := sleepWithContext(ctx, policy.Delay(attempt)); sleepErr != nil {
+				return "", sleepErr
 			}
-			log.Printf("[ERROR] api<%s>,method:<%s>, params:<%s>, call domain error:<%s> , result:<%s> \n", api, method, utils.ToJsonString(params), err.Error(), result)
-			index = (index + i) % len(srvs)
 		}
-		return "", err
 	}
+	server.markDisconnected()
+	return "", retryFailed(err, policy.Attempts())
 }
 
-func (server *NacosServer) ReqApi(api string, params map[string]string, method string) (string, error) {
-	srvs := server.serverList
-	if srvs == nil || len(srvs) == 0 {
-		return "", errors.New("server list is empty")
+// 耗时不小于Timeout.SlowThresholdMs的请求记录日志和指标，elapsed包括限流等待、所有重试和重试间隔
+func (server *NacosServer) logSlowRequest(timeout constant.TimeoutConfig, api string, method string, params map[string]string,
+	lastServer string, attempts int, elapsed time.Duration, err error) {
+	if timeout.SlowThresholdMs == 0 || elapsed < time.Duration(timeout.SlowThresholdMs)*time.Millisecond {
+		return
 	}
-	//only one server,retry request when error
-	if len(srvs) == 1 {
-		for i := 0; i < constant.REQUEST_DOMAIN_RETRY_TIME; i++ {
-			result, err := server.callServer(api, params, method, getAddress(srvs[0]), srvs[0].ContextPath)
-			if err == nil {
-				return result, nil
-			}
-			log.Printf("[ERROR] api<%s>,method:<%s>, params:<%s>, call domain error:<%s> , result:<%s> \n", api, method, utils.ToJsonString(params), err.Error(), result)
-		}
-		return "", errors.New("retry " + strconv.Itoa(constant.REQUEST_DOMAIN_RETRY_TIME) + " times request failed!")
-	} else {
-		index := rand.Intn(len(srvs))
-		for i := 1; i <= len(srvs); i++ {
-			curServer := srvs[index]
-			result, err := server.callServer(api, params, method, getAddress(curServer), curServer.ContextPath)
-			if err == nil {
-				return result, nil
-			}
-			log.Printf("[ERROR] api<%s>,method:<%s>, params:<%s>, call domain error:<%s> , result:<%s> \n", api, method, utils.ToJsonString(params), err.Error(), result)
-			index = (index + i) % len(srvs)
-		}
-		return "", errors.New("retry " + strconv.Itoa(constant.REQUEST_DOMAIN_RETRY_TIME) + " times request failed!")
+	errMsg := ""
+	if err != nil {
+		errMsg = err.Error()
 	}
+	logger.Warnf("slow request api<%s>,method:<%s>, server:<%s>, elapsed:<%dms>, attempts:<%d>, params:<%s>, error:<%s>",
+		api, method, lastServer, elapsed.Milliseconds(), attempts, utils.ToJsonString(params), errMsg)
+	server.metrics.ObserveSlowRequest(api, elapsed)
 }
 
-func (server *NacosServer) initRefreshSrvIfNeed() {
-	if server.endpoint == "" {
-		return
+func (server *NacosServer) markDisconnected() {
+	if server.connection != nil && atomic.CompareAndSwapInt32(&server.connection.disconnected, 0, 1) {
+		logger.Warnf("all attempts to nacos server failed, mark the connection as lost")
 	}
-	server.refreshServerSrvIfNeed()
-	go func() {
-		time.Sleep(time.Duration(1) * time.Second)
-		server.refreshServerSrvIfNeed()
-	}()
+}
 
+// 断开后首次请求成功时发布ServerReconnectedEvent，用于重新注册实例和订阅
+func (server *NacosServer) markConnected(address string) {
+	if server.connection != nil && atomic.CompareAndSwapInt32(&server.connection.disconnected, 1, 0) {
+		logger.Infof("connection to nacos server:%s is re-established", address)
+		server.events.Publish(event.ServerReconnectedEvent{Address: address})
+	}
 }
 
-func (server *NacosServer) refreshServerSrvIfNeed() {
-	if utils.CurrentMillis()-server.lastSrvRefTime < server.vipSrvRefInterMills && len(server.serverList) > 0 {
-		return
+// 未通过NewNacosServer创建时返回不记录任何内容的Tracer
+func (server *NacosServer) Tracer() tracing.Tracer {
+	if server.tracer == nil {
+		return tracing.NewTracer(nil)
 	}
+	return server.tracer
+}
 
-	var list []string
-	urlString := "http://" + server.endpoint + "/nacos/serverlist"
-	result := server.httpAgent.RequestOnlyResult(http.MethodGet, urlString, nil, server.timeoutMs, nil)
-	list = strings.Split(result, "\n")
-	log.Printf("[info] http nacos server list: <%s> \n", result)
-
-	var servers []constant.ServerConfig
-	for _, line := range list {
-		if line != "" {
-			splitLine := strings.Split(strings.TrimSpace(line), ":")
-			port := 8848
-			var err error
-			if len(splitLine) == 2 {
-				port, err = strconv.Atoi(splitLine[1])
-				if err != nil {
-					log.Printf("[ERROR] get port from server:<%s>  error: <%s> \n", line, err.Error())
-					continue
-				}
-			}
-			servers = append(servers, constant.ServerConfig{IpAddr: splitLine[0], Port: uint64(port), ContextPath: constant.WEB_CONTEXT})
+// 从请求参数中提取span属性，naming请求使用namespaceId，config请求使用tenant
+func requestAttributes(params map[string]string) []tracing.Attribute {
+	var attrs []tracing.Attribute
+	for _, key := range []struct{ param, attr string }{
+		{"namespaceId", tracing.ATTR_NAMESPACE},
+		{"tenant", tracing.ATTR_NAMESPACE},
+		{"groupName", tracing.ATTR_GROUP},
+		{"group", tracing.ATTR_GROUP},
+		{"dataId", tracing.ATTR_DATA_ID},
+		{"serviceName", tracing.ATTR_SERVICE_NAME},
+		{"clusters", tracing.ATTR_CLUSTERS},
+	} {
+		if value := params[key.param]; value != "" {
+			attrs = append(attrs, tracing.Attribute{Key: key.attr, Value: value})
 		}
 	}
-	if len(servers) > 0 {
-		if !reflect.DeepEqual(server.serverList, servers) {
-			server.Lock()
-			log.Printf("[info] server list is updated, old: <%v>,new:<%v> \n", server.serverList, servers)
-			server.serverList = servers
-			server.lastSrvRefTime = utils.CurrentMillis()
-			server.Unlock()
-		}
+	return attrs
+}
 
+func (server *NacosServer) getRetryPolicy() *retry.RetryPolicy {
+	server.settings.mutex.RLock()
+	policy := server.settings.retryPolicy
+	server.settings.mutex.RUnlock()
+	if policy == nil {
+		return retry.DefaultRetryPolicyForServers(len(server.GetServerList()))
 	}
+	return policy
+}
 
-	return
+// 网络错误总是可重试，服务端返回的错误按状态码判断
+func isRetryable(policy *retry.RetryPolicy, err error) bool {
+	if nacos_error.IsConflict(err) {
+		return false
+	}
+	nacosErr, ok := err.(*nacos_error.NacosError)
+	if !ok {
+		return true
+	}
+	statusCode, convErr := strconv.Atoi(nacosErr.ErrorCode())
+	if convErr != nil {
+		return true
+	}
+	return policy.IsRetryable(statusCode)
+}
+
+func sleepWithContext(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// 所有服务端均请求失败，服务端返回的错误保留原错误码，网络错误视为服务不可用
+func retryFailed(err error, attempts int) error {
+	errMsg := "retry " + strconv.Itoa(attempts) + " times request failed!"
+	if _, ok := err.(*nacos_error.NacosError); ok {
+		return nacos_error.Wrap(errMsg, err)
+	}
+	return nacos_error.NewNacosError(strconv.Itoa(http.StatusServiceUnavailable), errMsg, err)
 }
 
 func (server *NacosServer) GetServerList() []constant.ServerConfig {
-	return server.serverList
+	if server.serverManager == nil {
+		return nil
+	}
+	return server.serverManager.GetServerList()
+}
+
+// 当前健康的服务端
+func (server *NacosServer) GetHealthyServerList() []constant.ServerConfig {
+	if server.serverManager == nil {
+		return nil
+	}
+	return server.serverManager.GetHealthyServers()
 }
 
 func getAddress(cfg constant.ServerConfig) string {
-	return cfg.IpAddr + ":" + strconv.Itoa(int(cfg.Port))
+	return server_list.GetAddress(cfg)
 }
 
 func getSignHeaders(params map[string]string, newHeaders map[string]string) map[string]string {
@@ -273,6 +845,31 @@ func getSignHeaders(params map[string]string, newHeaders map[string]string) map[
 	return headers
 }
 
+// 配置了凭证时，按服务名对naming请求签名
+func (server *NacosServer) injectSignature(params map[string]string) map[string]string {
+	credentialsProvider := server.getCredentialsProvider()
+	if credentialsProvider == nil {
+		return params
+	}
+	creds, err := credentialsProvider.GetCredentials()
+	if err != nil {
+		logger.Errorf("get credentials failed,err:%s", err.Error())
+		return params
+	}
+	signedParams := make(map[string]string, len(params)+3)
+	for k, v := range params {
+		signedParams[k] = v
+	}
+	data := strconv.FormatInt(time.Now().UnixNano()/1e6, 10)
+	if serviceName := params["serviceName"]; serviceName != "" {
+		data = data + constant.SERVICE_INFO_SPLITER + serviceName
+	}
+	signedParams["signature"] = signWithhmacSHA1Encrypt(data, creds.SecretKey)
+	signedParams["data"] = data
+	signedParams["ak"] = creds.AccessKey
+	return signedParams
+}
+
 func signWithhmacSHA1Encrypt(encryptText, encryptKey string) string {
 	//hmac ,use sha1
 	key := []byte(encryptKey)
@@ -0,0 +1,71 @@
+package rate_limiter
+
+import (
+	"context"
+	"errors"
+)
+
+// 发往服务端的请求类别
+const (
+	CATEGORY_QUERY     = "query"
+	CATEGORY_BEAT      = "beat"
+	CATEGORY_LONG_POLL = "longPoll"
+)
+
+// FastFail模式下超出速率的请求返回该错误
+var ErrRateLimited = errors.New("[client.RateLimit] request is rate limited")
+
+// 按类别限制发往服务端的请求速率，避免服务端重启后大量客户端同时请求
+// QueryPerSecond：查询类请求（GET），BeatPerSecond：心跳，LongPollPerSecond：配置长轮询，为0时该类别不限制
+// Burst为各类别允许的突发请求数，为0时等于该类别的每秒请求数
+// FastFail为true时超出速率的请求立即返回ErrRateLimited，否则排队等待
+type RateLimitConfig struct {
+	QueryPerSecond    int
+	BeatPerSecond     int
+	LongPollPerSecond int
+	Burst             int
+	FastFail          bool
+}
+
+type RequestLimiter struct {
+	buckets  map[string]*TokenBucket
+	fastFail bool
+}
+
+func NewRequestLimiter(config RateLimitConfig) *RequestLimiter {
+	l := &RequestLimiter{buckets: map[string]*TokenBucket{}, fastFail: config.FastFail}
+	rates := map[string]int{
+		CATEGORY_QUERY:     config.QueryPerSecond,
+		CATEGORY_BEAT:      config.BeatPerSecond,
+		CATEGORY_LONG_POLL: config.LongPollPerSecond,
+	}
+	for category, rate := range rates {
+		if rate <= 0 {
+			continue
+		}
+		burst := config.Burst
+		if burst <= 0 {
+			burst = rate
+		}
+		l.buckets[category] = NewTokenBucket(float64(rate), burst)
+	}
+	return l
+}
+
+// 获取category类别的一个令牌，未限制的类别直接返回
+func (l *RequestLimiter) Wait(ctx context.Context, category string) error {
+	if l == nil {
+		return nil
+	}
+	bucket, ok := l.buckets[category]
+	if !ok {
+		return nil
+	}
+	if l.fastFail {
+		if !bucket.TryAcquire() {
+			return ErrRateLimited
+		}
+		return nil
+	}
+	return bucket.AcquireWithContext(ctx)
+}
@@ -0,0 +1,39 @@
+package rate_limiter
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRequestLimiter_FastFail(t *testing.T) {
+	l := NewRequestLimiter(RateLimitConfig{BeatPerSecond: 1, Burst: 2, FastFail: true})
+	ctx := context.Background()
+	assert.Nil(t, l.Wait(ctx, CATEGORY_BEAT))
+	assert.Nil(t, l.Wait(ctx, CATEGORY_BEAT))
+	assert.Equal(t, ErrRateLimited, l.Wait(ctx, CATEGORY_BEAT))
+	// 未配置的类别不限制
+	for i := 0; i < 10; i++ {
+		assert.Nil(t, l.Wait(ctx, CATEGORY_QUERY))
+	}
+}
+
+func TestRequestLimiter_Queue(t *testing.T) {
+	l := NewRequestLimiter(RateLimitConfig{QueryPerSecond: 20, Burst: 1})
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		assert.Nil(t, l.Wait(ctx, CATEGORY_QUERY))
+	}
+	assert.True(t, time.Since(start) >= 90*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, l.Wait(ctx, CATEGORY_QUERY))
+}
+
+func TestRequestLimiter_Nil(t *testing.T) {
+	var l *RequestLimiter
+	assert.Nil(t, l.Wait(context.Background(), CATEGORY_QUERY))
+}
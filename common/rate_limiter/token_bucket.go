@@ -0,0 +1,78 @@
+package rate_limiter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// 令牌桶限流器，rate 为每秒生成的令牌数，burst 为桶容量
+type TokenBucket struct {
+	mutex    sync.Mutex
+	rate     float64
+	burst    float64
+	tokens   float64
+	lastTime time.Time
+}
+
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:     rate,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		lastTime: time.Now(),
+	}
+}
+
+func (tb *TokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(tb.lastTime).Seconds()
+	if elapsed > 0 {
+		tb.tokens += elapsed * tb.rate
+		if tb.tokens > tb.burst {
+			tb.tokens = tb.burst
+		}
+	}
+	tb.lastTime = now
+}
+
+// 尝试获取一个令牌，获取不到立即返回false
+func (tb *TokenBucket) TryAcquire() bool {
+	tb.mutex.Lock()
+	defer tb.mutex.Unlock()
+	tb.refill(time.Now())
+	if tb.tokens >= 1 {
+		tb.tokens--
+		return true
+	}
+	return false
+}
+
+// 获取一个令牌，获取不到则阻塞等待
+func (tb *TokenBucket) Acquire() {
+	tb.AcquireWithContext(context.Background())
+}
+
+// 获取一个令牌，获取不到则等待，ctx结束时返回ctx.Err()
+func (tb *TokenBucket) AcquireWithContext(ctx context.Context) error {
+	for {
+		tb.mutex.Lock()
+		tb.refill(time.Now())
+		if tb.tokens >= 1 {
+			tb.tokens--
+			tb.mutex.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - tb.tokens) / tb.rate * float64(time.Second))
+		tb.mutex.Unlock()
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
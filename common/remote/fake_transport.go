@@ -0,0 +1,151 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// 用于测试的Transport，不建立连接，请求由OnRequest应答，推送通过Push模拟
+type FakeTransport struct {
+	// 不为nil时Start返回该错误，用于模拟不支持长连接的服务端
+	StartErr error
+	// 返回请求的响应，响应以JSON编码后解析到调用方的response，为nil时响应为空
+	OnRequest func(requestType string, request []byte) (interface{}, error)
+
+	mutex     sync.Mutex
+	config    Config
+	connected bool
+	closed    bool
+	handlers  map[string][]PushHandler
+	callbacks []func()
+	requests  map[string][][]byte
+}
+
+func NewFakeTransport() *FakeTransport {
+	return &FakeTransport{handlers: map[string][]PushHandler{}, requests: map[string][][]byte{}}
+}
+
+// 返回总是创建该FakeTransport的TransportFactory，用于ClientConfig.Transport
+func (f *FakeTransport) Factory() TransportFactory {
+	return func(config Config) (Transport, error) {
+		f.mutex.Lock()
+		f.config = config
+		f.mutex.Unlock()
+		return f, nil
+	}
+}
+
+// 创建时传入的配置
+func (f *FakeTransport) Config() Config {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.config
+}
+
+func (f *FakeTransport) Start() error {
+	if f.StartErr != nil {
+		return f.StartErr
+	}
+	f.mutex.Lock()
+	f.connected = true
+	f.mutex.Unlock()
+	return nil
+}
+
+func (f *FakeTransport) Connected() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.connected && !f.closed
+}
+
+func (f *FakeTransport) Request(ctx context.Context, requestType string, request interface{}, response interface{}) error {
+	if !f.Connected() {
+		return ErrNotConnected
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	f.mutex.Lock()
+	f.requests[requestType] = append(f.requests[requestType], body)
+	onRequest := f.OnRequest
+	f.mutex.Unlock()
+	if onRequest == nil {
+		return nil
+	}
+	result, err := onRequest(requestType, body)
+	if err != nil || result == nil {
+		return err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, response)
+}
+
+// 返回requestType类型的所有请求，每个请求为JSON编码
+func (f *FakeTransport) Requests(requestType string) [][]byte {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([][]byte(nil), f.requests[requestType]...)
+}
+
+func (f *FakeTransport) RegisterHandler(requestType string, handler PushHandler) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.handlers[requestType] = append(f.handlers[requestType], handler)
+}
+
+// 模拟服务端推送，同步调用所有处理函数
+func (f *FakeTransport) Push(requestType string, request interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	f.mutex.Lock()
+	handlers := append([]PushHandler(nil), f.handlers[requestType]...)
+	f.mutex.Unlock()
+	for _, handler := range handlers {
+		handler(body)
+	}
+	return nil
+}
+
+func (f *FakeTransport) OnReconnected(callback func()) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.callbacks = append(f.callbacks, callback)
+}
+
+// 模拟连接断开
+func (f *FakeTransport) Disconnect() {
+	f.mutex.Lock()
+	f.connected = false
+	f.mutex.Unlock()
+}
+
+// 模拟重连成功，同步调用所有重连回调
+func (f *FakeTransport) Reconnect() {
+	f.mutex.Lock()
+	f.connected = true
+	callbacks := append([]func(){}, f.callbacks...)
+	f.mutex.Unlock()
+	for _, callback := range callbacks {
+		callback()
+	}
+}
+
+func (f *FakeTransport) Close() error {
+	f.mutex.Lock()
+	f.closed = true
+	f.mutex.Unlock()
+	return nil
+}
+
+func (f *FakeTransport) Closed() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.closed
+}
@@ -0,0 +1,169 @@
+//go:build grpc
+// +build grpc
+
+package grpc_transport
+
+import (
+	"errors"
+	"google.golang.org/protobuf/encoding/protowire"
+	"sort"
+)
+
+// Nacos 2.x的gRPC服务只有一种消息Payload，请求类型和请求头在Metadata中，请求体为Any中以JSON编码的请求：
+//
+//	message Metadata { string type = 3; string clientIp = 8; map<string, string> headers = 7; }
+//	message Payload { Metadata metadata = 2; google.protobuf.Any body = 3; }
+//
+// 按该定义直接编解码，不依赖生成的代码
+type payload struct {
+	Type     string
+	ClientIp string
+	Headers  map[string]string
+	Body     []byte
+}
+
+const (
+	fieldPayloadMetadata  protowire.Number = 2
+	fieldPayloadBody      protowire.Number = 3
+	fieldMetadataType     protowire.Number = 3
+	fieldMetadataHeaders  protowire.Number = 7
+	fieldMetadataClientIp protowire.Number = 8
+	fieldAnyValue         protowire.Number = 2
+	fieldMapKey           protowire.Number = 1
+	fieldMapValue         protowire.Number = 2
+)
+
+var errInvalidPayload = errors.New("[grpc_transport] invalid payload")
+
+func (p *payload) marshal() []byte {
+	var metadata []byte
+	if p.Type != "" {
+		metadata = protowire.AppendTag(metadata, fieldMetadataType, protowire.BytesType)
+		metadata = protowire.AppendString(metadata, p.Type)
+	}
+	// 请求头按key排序，保证相同的请求编码结果相同
+	keys := make([]string, 0, len(p.Headers))
+	for k := range p.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		var entry []byte
+		entry = protowire.AppendTag(entry, fieldMapKey, protowire.BytesType)
+		entry = protowire.AppendString(entry, k)
+		entry = protowire.AppendTag(entry, fieldMapValue, protowire.BytesType)
+		entry = protowire.AppendString(entry, p.Headers[k])
+		metadata = protowire.AppendTag(metadata, fieldMetadataHeaders, protowire.BytesType)
+		metadata = protowire.AppendBytes(metadata, entry)
+	}
+	if p.ClientIp != "" {
+		metadata = protowire.AppendTag(metadata, fieldMetadataClientIp, protowire.BytesType)
+		metadata = protowire.AppendString(metadata, p.ClientIp)
+	}
+	var body []byte
+	body = protowire.AppendTag(body, fieldAnyValue, protowire.BytesType)
+	body = protowire.AppendBytes(body, p.Body)
+
+	var b []byte
+	b = protowire.AppendTag(b, fieldPayloadMetadata, protowire.BytesType)
+	b = protowire.AppendBytes(b, metadata)
+	b = protowire.AppendTag(b, fieldPayloadBody, protowire.BytesType)
+	return protowire.AppendBytes(b, body)
+}
+
+func (p *payload) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, value []byte) error {
+		switch num {
+		case fieldPayloadMetadata:
+			return p.unmarshalMetadata(value)
+		case fieldPayloadBody:
+			return consumeFields(value, func(num protowire.Number, value []byte) error {
+				if num == fieldAnyValue {
+					p.Body = append([]byte(nil), value...)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+}
+
+func (p *payload) unmarshalMetadata(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, value []byte) error {
+		switch num {
+		case fieldMetadataType:
+			p.Type = string(value)
+		case fieldMetadataClientIp:
+			p.ClientIp = string(value)
+		case fieldMetadataHeaders:
+			var key, val string
+			err := consumeFields(value, func(num protowire.Number, value []byte) error {
+				if num == fieldMapKey {
+					key = string(value)
+				} else if num == fieldMapValue {
+					val = string(value)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if p.Headers == nil {
+				p.Headers = map[string]string{}
+			}
+			p.Headers[key] = val
+		}
+		return nil
+	})
+}
+
+// 依次处理b中长度前缀类型的字段，其他类型的字段跳过
+func consumeFields(b []byte, field func(num protowire.Number, value []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return errInvalidPayload
+		}
+		b = b[n:]
+		if typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return errInvalidPayload
+			}
+			b = b[n:]
+			continue
+		}
+		value, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return errInvalidPayload
+		}
+		b = b[n:]
+		if err := field(num, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 以Nacos的Payload格式编解码的grpc编码器，名称与protobuf编码器相同，服务端按application/grpc+proto解析
+type payloadCodec struct{}
+
+func (payloadCodec) Marshal(v interface{}) ([]byte, error) {
+	p, ok := v.(*payload)
+	if !ok {
+		return nil, errInvalidPayload
+	}
+	return p.marshal(), nil
+}
+
+func (payloadCodec) Unmarshal(data []byte, v interface{}) error {
+	p, ok := v.(*payload)
+	if !ok {
+		return errInvalidPayload
+	}
+	return p.unmarshal(data)
+}
+
+func (payloadCodec) Name() string {
+	return "proto"
+}
@@ -0,0 +1,435 @@
+//go:build grpc
+// +build grpc
+
+// 基于gRPC的长连接，对应Nacos 2.x服务端的gRPC端口，需要以-tags grpc构建，未使用长连接的应用不引入grpc依赖
+package grpc_transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/common/nacos_error"
+	"github.com/nacos-group/nacos-sdk-go/common/remote"
+	"github.com/nacos-group/nacos-sdk-go/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// gRPC端口与HTTP端口的差，与服务端默认配置一致
+	Default_Port_Offset = 1000
+	// 健康检查的间隔，检查失败或连接断开后按该间隔重连
+	Default_Health_Check_Interval = 5 * time.Second
+	// 未配置TimeoutMs时每个请求的超时时间
+	Default_Request_Timeout = 3 * time.Second
+)
+
+const (
+	requestMethod  = "/Request/request"
+	biStreamMethod = "/BiRequestStream/requestBiStream"
+
+	typeServerCheck      = "ServerCheckRequest"
+	typeHealthCheck      = "HealthCheckRequest"
+	typeConnectionSetup  = "ConnectionSetupRequest"
+	typeConnectReset     = "ConnectResetRequest"
+	typeClientDetection  = "ClientDetectionRequest"
+	typeSetupAck         = "SetupAckRequest"
+	typeErrorResponse    = "ErrorResponse"
+	resultCodeFail       = 500
+	errorCodeUnsupported = 501
+)
+
+// PortOffset：gRPC端口与HTTP端口的差，为0时为Default_Port_Offset
+// HealthCheckInterval：健康检查和重连的间隔，为0时为Default_Health_Check_Interval
+// DialOptions：创建连接时追加的选项，如代理或自定义拨号
+type Options struct {
+	PortOffset          uint64
+	HealthCheckInterval time.Duration
+	DialOptions         []grpc.DialOption
+}
+
+// 使用默认选项创建长连接，用于ClientConfig.Transport
+func New(config remote.Config) (remote.Transport, error) {
+	return NewFactory(Options{})(config)
+}
+
+func NewFactory(options Options) remote.TransportFactory {
+	if options.PortOffset == 0 {
+		options.PortOffset = Default_Port_Offset
+	}
+	if options.HealthCheckInterval <= 0 {
+		options.HealthCheckInterval = Default_Health_Check_Interval
+	}
+	return func(config remote.Config) (remote.Transport, error) {
+		if config.Servers == nil {
+			return nil, errors.New("[grpc_transport] server list can not be empty")
+		}
+		return &transport{
+			config:        config,
+			options:       options,
+			handlers:      map[string][]remote.PushHandler{},
+			closeChan:     make(chan struct{}),
+			reconnectChan: make(chan struct{}, 1),
+		}, nil
+	}
+}
+
+// 同一时刻至多有一个连接，连接断开后由后台协程重连，重连期间Connected返回false
+type transport struct {
+	config        remote.Config
+	options       Options
+	mutex         sync.Mutex
+	conn          *connection
+	handlers      map[string][]remote.PushHandler
+	callbacks     []func()
+	serverIndex   int
+	closed        bool
+	closeChan     chan struct{}
+	reconnectChan chan struct{}
+	startOnce     sync.Once
+}
+
+// 与一个服务端的连接，请求使用一元调用，服务端推送和客户端的回复使用双向流
+type connection struct {
+	id        string
+	address   string
+	cc        *grpc.ClientConn
+	stream    grpc.ClientStream
+	cancel    context.CancelFunc
+	sendMutex sync.Mutex
+}
+
+func (t *transport) Start() error {
+	if err := t.connect(); err != nil {
+		return err
+	}
+	t.startOnce.Do(func() {
+		go t.keepAlive()
+	})
+	return nil
+}
+
+func (t *transport) Connected() bool {
+	return t.current() != nil
+}
+
+func (t *transport) current() *connection {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.conn
+}
+
+func (t *transport) RegisterHandler(requestType string, handler remote.PushHandler) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.handlers[requestType] = append(t.handlers[requestType], handler)
+}
+
+func (t *transport) OnReconnected(callback func()) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.callbacks = append(t.callbacks, callback)
+}
+
+func (t *transport) Close() error {
+	t.mutex.Lock()
+	if t.closed {
+		t.mutex.Unlock()
+		return nil
+	}
+	t.closed = true
+	conn := t.conn
+	t.conn = nil
+	close(t.closeChan)
+	t.mutex.Unlock()
+	if conn != nil {
+		conn.close()
+	}
+	return nil
+}
+
+func (t *transport) Request(ctx context.Context, requestType string, request interface{}, response interface{}) error {
+	conn := t.current()
+	if conn == nil {
+		return remote.ErrNotConnected
+	}
+	err := t.request(ctx, conn, requestType, request, response)
+	if err != nil {
+		if _, ok := err.(*nacos_error.NacosError); !ok && ctx.Err() == nil {
+			t.connectionLost(conn, err)
+		}
+	}
+	return err
+}
+
+// 服务端返回失败时返回*nacos_error.NacosError，错误码为响应中的errorCode
+func (t *transport) request(ctx context.Context, conn *connection, requestType string, request interface{}, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.timeout())
+		defer cancel()
+	}
+	headers := map[string]string{}
+	if t.config.Headers != nil {
+		for k, v := range t.config.Headers() {
+			headers[k] = v
+		}
+	}
+	in := &payload{Type: requestType, ClientIp: utils.LocalIP(), Headers: headers, Body: body}
+	out := &payload{}
+	if err = conn.cc.Invoke(ctx, requestMethod, in, out); err != nil {
+		return err
+	}
+	var result remote.Response
+	if err = json.Unmarshal(out.Body, &result); err != nil {
+		return err
+	}
+	if out.Type == typeErrorResponse || !result.Success() {
+		return nacos_error.NewNacosError(strconv.Itoa(result.ErrorCode), result.Message, nil)
+	}
+	if response == nil {
+		return nil
+	}
+	return json.Unmarshal(out.Body, response)
+}
+
+func (t *transport) timeout() time.Duration {
+	if t.config.TimeoutMs > 0 {
+		return time.Duration(t.config.TimeoutMs) * time.Millisecond
+	}
+	return Default_Request_Timeout
+}
+
+// 从上次连接的服务端之后依次尝试，连接成功后替换当前连接
+func (t *transport) connect() error {
+	servers := t.config.Servers()
+	if len(servers) == 0 {
+		return errors.New("[grpc_transport] server list is empty")
+	}
+	t.mutex.Lock()
+	if t.serverIndex == 0 {
+		t.serverIndex = rand.Intn(len(servers)) + 1
+	}
+	start := t.serverIndex
+	t.mutex.Unlock()
+	var err error
+	for i := 0; i < len(servers); i++ {
+		index := (start + i) % len(servers)
+		var conn *connection
+		if conn, err = t.dial(servers[index]); err != nil {
+			logger.Warnf("[grpc_transport] connect to %s failed,err:%s", t.address(servers[index]), err.Error())
+			continue
+		}
+		t.mutex.Lock()
+		if t.closed {
+			t.mutex.Unlock()
+			conn.close()
+			return errors.New("[grpc_transport] transport is closed")
+		}
+		t.conn = conn
+		t.serverIndex = index + 1
+		t.mutex.Unlock()
+		logger.Infof("[grpc_transport] connected to %s, connectionId:%s", conn.address, conn.id)
+		go t.receive(conn)
+		return nil
+	}
+	return err
+}
+
+func (t *transport) address(server remote.Server) string {
+	return net.JoinHostPort(server.IpAddr, strconv.FormatUint(server.Port+t.options.PortOffset, 10))
+}
+
+// 建立连接：检查服务端是否支持长连接，打开双向流并注册连接，1.x服务端没有gRPC端口时在检查时失败
+func (t *transport) dial(server remote.Server) (*connection, error) {
+	creds := insecure.NewCredentials()
+	if t.config.TLSConfig != nil {
+		creds = credentials.NewTLS(t.config.TLSConfig)
+	}
+	options := append([]grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(payloadCodec{})),
+	}, t.options.DialOptions...)
+	cc, err := grpc.NewClient(t.address(server), options...)
+	if err != nil {
+		return nil, err
+	}
+	conn := &connection{address: t.address(server), cc: cc}
+	var check struct {
+		remote.Response
+		ConnectionId string `json:"connectionId"`
+	}
+	if err = t.request(context.Background(), conn, typeServerCheck, struct{}{}, &check); err != nil {
+		cc.Close()
+		return nil, err
+	}
+	conn.id = check.ConnectionId
+	ctx, cancel := context.WithCancel(context.Background())
+	conn.cancel = cancel
+	conn.stream, err = cc.NewStream(ctx, &grpc.StreamDesc{StreamName: "requestBiStream", ServerStreams: true, ClientStreams: true}, biStreamMethod)
+	if err != nil {
+		conn.close()
+		return nil, err
+	}
+	labels := map[string]string{"source": "sdk"}
+	for k, v := range t.config.Labels {
+		labels[k] = v
+	}
+	if t.config.AppName != "" {
+		labels["AppName"] = t.config.AppName
+	}
+	if err = conn.send(typeConnectionSetup, map[string]interface{}{
+		"clientVersion": constant.CLIENT_VERSION,
+		"tenant":        t.config.NamespaceId,
+		"labels":        labels,
+		"abilities":     map[string]interface{}{},
+	}); err != nil {
+		conn.close()
+		return nil, err
+	}
+	if err = t.waitRegistered(conn); err != nil {
+		conn.close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// 服务端异步处理ConnectionSetupRequest，注册完成前连接上的请求会失败，以健康检查等待注册完成
+func (t *transport) waitRegistered(conn *connection) error {
+	deadline := time.Now().Add(t.timeout())
+	for {
+		err := t.request(context.Background(), conn, typeHealthCheck, struct{}{}, nil)
+		if err == nil {
+			return nil
+		}
+		if _, ok := err.(*nacos_error.NacosError); !ok || time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// 处理服务端在双向流上的推送，流断开时视为连接断开
+func (t *transport) receive(conn *connection) {
+	for {
+		in := &payload{}
+		if err := conn.stream.RecvMsg(in); err != nil {
+			t.connectionLost(conn, err)
+			return
+		}
+		t.handle(conn, in)
+	}
+}
+
+func (t *transport) handle(conn *connection, in *payload) {
+	var request struct {
+		RequestId string `json:"requestId"`
+	}
+	json.Unmarshal(in.Body, &request)
+	ack := remote.Response{ResultCode: remote.Result_Success, RequestId: request.RequestId}
+	switch in.Type {
+	case typeSetupAck:
+		return
+	case typeClientDetection:
+	case typeConnectReset:
+		logger.Infof("[grpc_transport] server %s requests to reset connection:%s", conn.address, conn.id)
+		conn.send(strings.TrimSuffix(in.Type, "Request")+"Response", ack)
+		t.connectionLost(conn, errors.New("connection reset by server"))
+		return
+	default:
+		t.mutex.Lock()
+		handlers := append([]remote.PushHandler(nil), t.handlers[in.Type]...)
+		t.mutex.Unlock()
+		if len(handlers) == 0 {
+			logger.Warnf("[grpc_transport] no handler for request type:%s", in.Type)
+			conn.send(typeErrorResponse, remote.Response{ResultCode: resultCodeFail, ErrorCode: errorCodeUnsupported,
+				Message: "unsupported request type:" + in.Type, RequestId: request.RequestId})
+			return
+		}
+		for _, handler := range handlers {
+			handler(in.Body)
+		}
+	}
+	if err := conn.send(strings.TrimSuffix(in.Type, "Request")+"Response", ack); err != nil {
+		logger.Warnf("[grpc_transport] reply to %s failed,err:%s", in.Type, err.Error())
+	}
+}
+
+// 关闭断开的连接并通知后台协程重连，conn已不是当前连接时忽略
+func (t *transport) connectionLost(conn *connection, err error) {
+	t.mutex.Lock()
+	if t.conn != conn {
+		t.mutex.Unlock()
+		return
+	}
+	t.conn = nil
+	closed := t.closed
+	t.mutex.Unlock()
+	conn.close()
+	if closed {
+		return
+	}
+	logger.Warnf("[grpc_transport] connection:%s to %s is lost,err:%s", conn.id, conn.address, err.Error())
+	select {
+	case t.reconnectChan <- struct{}{}:
+	default:
+	}
+}
+
+// 定期健康检查，连接断开后重连，重连成功后通知OnReconnected注册的回调
+func (t *transport) keepAlive() {
+	for {
+		select {
+		case <-t.closeChan:
+			return
+		case <-t.reconnectChan:
+		case <-time.After(t.options.HealthCheckInterval):
+		}
+		if conn := t.current(); conn != nil {
+			if err := t.request(context.Background(), conn, typeHealthCheck, struct{}{}, nil); err != nil {
+				t.connectionLost(conn, err)
+			}
+			continue
+		}
+		if err := t.connect(); err != nil {
+			logger.Warnf("[grpc_transport] reconnect failed, retry in %s,err:%s", t.options.HealthCheckInterval, err.Error())
+			continue
+		}
+		t.mutex.Lock()
+		callbacks := append([]func(){}, t.callbacks...)
+		t.mutex.Unlock()
+		for _, callback := range callbacks {
+			go callback()
+		}
+	}
+}
+
+func (conn *connection) send(requestType string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	conn.sendMutex.Lock()
+	defer conn.sendMutex.Unlock()
+	return conn.stream.SendMsg(&payload{Type: requestType, ClientIp: utils.LocalIP(), Body: data})
+}
+
+func (conn *connection) close() {
+	if conn.cancel != nil {
+		conn.cancel()
+	}
+	conn.cc.Close()
+}
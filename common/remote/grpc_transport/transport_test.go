@@ -0,0 +1,250 @@
+//go:build grpc
+// +build grpc
+
+package grpc_transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/nacos-group/nacos-sdk-go/common/nacos_error"
+	"github.com/nacos-group/nacos-sdk-go/common/remote"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 进程内的Nacos 2.x服务端，只实现连接管理和测试用到的请求
+type fakeServer struct {
+	listener *bufconn.Listener
+	server   *grpc.Server
+	mutex    sync.Mutex
+	conns    int
+	streams  []grpc.ServerStream
+	requests []*payload
+	acks     chan *payload
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	s := &fakeServer{listener: bufconn.Listen(1 << 20), acks: make(chan *payload, 16)}
+	s.server = grpc.NewServer(grpc.ForceServerCodec(payloadCodec{}))
+	s.server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "Request",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{{MethodName: "request", Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			in := &payload{}
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			return s.handleRequest(in), nil
+		}}},
+	}, s)
+	s.server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "BiRequestStream",
+		HandlerType: (*interface{})(nil),
+		Streams: []grpc.StreamDesc{{StreamName: "requestBiStream", ServerStreams: true, ClientStreams: true, Handler: func(srv interface{}, stream grpc.ServerStream) error {
+			return s.handleStream(stream)
+		}}},
+	}, s)
+	go s.server.Serve(s.listener)
+	t.Cleanup(s.server.Stop)
+	return s
+}
+
+func newPayload(requestType string, body interface{}) *payload {
+	data, _ := json.Marshal(body)
+	return &payload{Type: requestType, Body: data}
+}
+
+func (s *fakeServer) handleRequest(in *payload) *payload {
+	s.mutex.Lock()
+	s.requests = append(s.requests, in)
+	s.mutex.Unlock()
+	switch in.Type {
+	case typeServerCheck:
+		s.mutex.Lock()
+		s.conns++
+		id := "conn-" + strconv.Itoa(s.conns)
+		s.mutex.Unlock()
+		return newPayload("ServerCheckResponse", map[string]interface{}{"resultCode": 200, "connectionId": id})
+	case typeHealthCheck:
+		return newPayload("HealthCheckResponse", map[string]interface{}{"resultCode": 200})
+	case remote.TYPE_SUBSCRIBE_SERVICE:
+		var request remote.SubscribeServiceRequest
+		json.Unmarshal(in.Body, &request)
+		return newPayload("SubscribeServiceResponse", map[string]interface{}{"resultCode": 200, "serviceInfo": remote.ServiceInfo{
+			Name: request.ServiceName, GroupName: request.GroupName, LastRefTime: 1,
+		}})
+	}
+	return newPayload(typeErrorResponse, map[string]interface{}{"resultCode": 500, "errorCode": 403, "message": "forbidden"})
+}
+
+func (s *fakeServer) handleStream(stream grpc.ServerStream) error {
+	setup := &payload{}
+	if err := stream.RecvMsg(setup); err != nil || setup.Type != typeConnectionSetup {
+		return errors.New("connection setup expected")
+	}
+	s.mutex.Lock()
+	s.streams = append(s.streams, stream)
+	s.mutex.Unlock()
+	for {
+		ack := &payload{}
+		if err := stream.RecvMsg(ack); err != nil {
+			return err
+		}
+		s.acks <- ack
+	}
+}
+
+func (s *fakeServer) lastStream() grpc.ServerStream {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.streams) == 0 {
+		return nil
+	}
+	return s.streams[len(s.streams)-1]
+}
+
+func (s *fakeServer) requestsOf(requestType string) []*payload {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var requests []*payload
+	for _, request := range s.requests {
+		if request.Type == requestType {
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
+
+func (s *fakeServer) newTransport(t *testing.T, headers map[string]string) remote.Transport {
+	factory := NewFactory(Options{
+		HealthCheckInterval: 50 * time.Millisecond,
+		DialOptions: []grpc.DialOption{grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return s.listener.DialContext(ctx)
+		})},
+	})
+	transport, err := factory(remote.Config{
+		Servers:   func() []remote.Server { return []remote.Server{{IpAddr: "127.0.0.1", Port: 8848}} },
+		TimeoutMs: 1000,
+		Headers:   func() map[string]string { return headers },
+	})
+	assert.Nil(t, err)
+	t.Cleanup(func() { transport.Close() })
+	return transport
+}
+
+func TestPayloadCodec_RoundTrip(t *testing.T) {
+	codec := payloadCodec{}
+	in := &payload{Type: "HealthCheckRequest", ClientIp: "10.0.0.1", Headers: map[string]string{"accessToken": "token", "app": "demo"}, Body: []byte(`{"a":1}`)}
+	data, err := codec.Marshal(in)
+	assert.Nil(t, err)
+	out := &payload{}
+	assert.Nil(t, codec.Unmarshal(data, out))
+	assert.Equal(t, in, out)
+	assert.Equal(t, "proto", codec.Name())
+	assert.NotNil(t, codec.Unmarshal([]byte{0xff}, out))
+}
+
+func TestTransport_RequestAndPush(t *testing.T) {
+	server := newFakeServer(t)
+	transport := server.newTransport(t, map[string]string{"accessToken": "token"})
+	pushed := make(chan []byte, 1)
+	transport.RegisterHandler(remote.TYPE_NOTIFY_SUBSCRIBER, func(body []byte) {
+		pushed <- body
+	})
+	assert.Nil(t, transport.Start())
+	assert.True(t, transport.Connected())
+
+	var response remote.SubscribeServiceResponse
+	err := transport.Request(context.Background(), remote.TYPE_SUBSCRIBE_SERVICE, remote.SubscribeServiceRequest{ServiceName: "demo", GroupName: "g1", Subscribe: true}, &response)
+	assert.Nil(t, err)
+	assert.True(t, response.Success())
+	assert.Equal(t, "g1@@demo", response.ServiceInfo.Service().Name)
+	requests := server.requestsOf(remote.TYPE_SUBSCRIBE_SERVICE)
+	assert.Equal(t, 1, len(requests))
+	assert.Equal(t, "token", requests[0].Headers["accessToken"])
+
+	push := newPayload("NotifySubscriberRequest", map[string]interface{}{"requestId": "7", "serviceName": "demo", "groupName": "g1"})
+	assert.Nil(t, server.lastStream().SendMsg(push))
+	select {
+	case body := <-pushed:
+		var request remote.NotifySubscriberRequest
+		assert.Nil(t, json.Unmarshal(body, &request))
+		assert.Equal(t, "demo", request.ServiceName)
+	case <-time.After(3 * time.Second):
+		t.Fatal("push is not handled")
+	}
+	select {
+	case ack := <-server.acks:
+		assert.Equal(t, "NotifySubscriberResponse", ack.Type)
+		var result remote.Response
+		assert.Nil(t, json.Unmarshal(ack.Body, &result))
+		assert.Equal(t, "7", result.RequestId)
+		assert.True(t, result.Success())
+	case <-time.After(3 * time.Second):
+		t.Fatal("push is not acknowledged")
+	}
+}
+
+func TestTransport_ErrorResponse(t *testing.T) {
+	server := newFakeServer(t)
+	transport := server.newTransport(t, nil)
+	assert.Nil(t, transport.Start())
+	err := transport.Request(context.Background(), "UnknownRequest", struct{}{}, nil)
+	nacosErr, ok := err.(*nacos_error.NacosError)
+	assert.True(t, ok)
+	assert.Equal(t, "403", nacosErr.ErrorCode())
+	// 服务端返回的错误不影响连接
+	assert.True(t, transport.Connected())
+}
+
+func TestTransport_StartFailsWithoutGrpcServer(t *testing.T) {
+	var dialed []string
+	var mutex sync.Mutex
+	factory := NewFactory(Options{DialOptions: []grpc.DialOption{grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+		mutex.Lock()
+		dialed = append(dialed, addr)
+		mutex.Unlock()
+		return nil, errors.New("connection refused")
+	})}})
+	transport, err := factory(remote.Config{
+		Servers:   func() []remote.Server { return []remote.Server{{IpAddr: "127.0.0.1", Port: 8848}} },
+		TimeoutMs: 500,
+	})
+	assert.Nil(t, err)
+	defer transport.Close()
+	assert.NotNil(t, transport.Start())
+	assert.False(t, transport.Connected())
+	assert.Equal(t, remote.ErrNotConnected, transport.Request(context.Background(), typeHealthCheck, struct{}{}, nil))
+	// gRPC端口为HTTP端口加Default_Port_Offset
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Contains(t, dialed, "127.0.0.1:9848")
+}
+
+func TestTransport_ReconnectAfterStreamClosed(t *testing.T) {
+	server := newFakeServer(t)
+	transport := server.newTransport(t, nil)
+	reconnected := make(chan struct{}, 1)
+	transport.OnReconnected(func() {
+		reconnected <- struct{}{}
+	})
+	assert.Nil(t, transport.Start())
+
+	// 服务端要求重置连接
+	assert.Nil(t, server.lastStream().SendMsg(newPayload(typeConnectReset, map[string]interface{}{"requestId": "1"})))
+	select {
+	case <-reconnected:
+	case <-time.After(3 * time.Second):
+		t.Fatal("transport is not reconnected")
+	}
+	assert.True(t, transport.Connected())
+	assert.Equal(t, 2, len(server.requestsOf(typeServerCheck)))
+	assert.Nil(t, transport.Request(context.Background(), typeHealthCheck, struct{}{}, nil))
+}
@@ -0,0 +1,106 @@
+package remote
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"strings"
+)
+
+// 长连接协议中的请求类型，与Nacos 2.x服务端的请求类名一致，请求和响应以JSON编码
+const (
+	TYPE_SUBSCRIBE_SERVICE    = "SubscribeServiceRequest"
+	TYPE_NOTIFY_SUBSCRIBER    = "NotifySubscriberRequest"
+	TYPE_CONFIG_BATCH_LISTEN  = "ConfigBatchListenRequest"
+	TYPE_CONFIG_CHANGE_NOTIFY = "ConfigChangeNotifyRequest"
+)
+
+const Result_Success = 200
+
+type Response struct {
+	ResultCode int    `json:"resultCode"`
+	ErrorCode  int    `json:"errorCode"`
+	Message    string `json:"message"`
+	RequestId  string `json:"requestId"`
+}
+
+func (r Response) Success() bool {
+	return r.ResultCode == Result_Success
+}
+
+type SubscribeServiceRequest struct {
+	Namespace   string `json:"namespace"`
+	ServiceName string `json:"serviceName"`
+	GroupName   string `json:"groupName"`
+	Clusters    string `json:"clusters"`
+	Subscribe   bool   `json:"subscribe"`
+}
+
+type SubscribeServiceResponse struct {
+	Response
+	ServiceInfo ServiceInfo `json:"serviceInfo"`
+}
+
+// 服务端推送和订阅返回的服务，Name不含分组
+type ServiceInfo struct {
+	Name        string           `json:"name"`
+	GroupName   string           `json:"groupName"`
+	Clusters    string           `json:"clusters"`
+	CacheMillis uint64           `json:"cacheMillis"`
+	Hosts       []model.Instance `json:"hosts"`
+	LastRefTime uint64           `json:"lastRefTime"`
+	Checksum    string           `json:"checksum"`
+}
+
+// 转换为HTTP接口返回的格式，服务名为group@@service
+func (info ServiceInfo) Service() model.Service {
+	name := info.Name
+	if info.GroupName != "" && !strings.Contains(name, "@@") {
+		name = info.GroupName + "@@" + name
+	}
+	return model.Service{
+		Name:        name,
+		Clusters:    info.Clusters,
+		CacheMillis: info.CacheMillis,
+		Hosts:       info.Hosts,
+		LastRefTime: info.LastRefTime,
+		Checksum:    info.Checksum,
+	}
+}
+
+type NotifySubscriberRequest struct {
+	Namespace   string      `json:"namespace"`
+	ServiceName string      `json:"serviceName"`
+	GroupName   string      `json:"groupName"`
+	ServiceInfo ServiceInfo `json:"serviceInfo"`
+}
+
+type ConfigListenContext struct {
+	Group  string `json:"group"`
+	Md5    string `json:"md5"`
+	DataId string `json:"dataId"`
+	Tenant string `json:"tenant"`
+}
+
+// Listen为false时取消监听
+type ConfigBatchListenRequest struct {
+	Listen               bool                  `json:"listen"`
+	ConfigListenContexts []ConfigListenContext `json:"configListenContexts"`
+}
+
+type ConfigContext struct {
+	Group  string `json:"group"`
+	DataId string `json:"dataId"`
+	Tenant string `json:"tenant"`
+}
+
+// ChangedConfigs为监听时md5已与服务端不一致的配置
+type ConfigChangeBatchListenResponse struct {
+	Response
+	ChangedConfigs []ConfigContext `json:"changedConfigs"`
+}
+
+// 配置变化的通知只包含配置的标识，内容需要重新获取
+type ConfigChangeNotifyRequest struct {
+	Group  string `json:"group"`
+	DataId string `json:"dataId"`
+	Tenant string `json:"tenant"`
+}
@@ -0,0 +1,52 @@
+package remote
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+)
+
+// 与Nacos 2.x服务端的长连接，服务和配置的变化由服务端通过长连接推送，不再依赖UDP推送和长轮询
+// 本包只定义接口和协议消息，不引入gRPC依赖，gRPC实现见common/remote/grpc_transport
+
+// 服务端地址，Port为HTTP端口，实现按各自的协议换算长连接的端口
+type Server struct {
+	IpAddr string
+	Port   uint64
+}
+
+type Config struct {
+	// 返回当前的服务端列表，服务端列表可能在运行时更新
+	Servers     func() []Server
+	NamespaceId string
+	AppName     string
+	Labels      map[string]string
+	// 每次请求的超时时间，单位毫秒
+	TimeoutMs uint64
+	// 为nil时不使用TLS
+	TLSConfig *tls.Config
+	// 返回每个请求附加的请求头，如鉴权的accessToken
+	Headers func() map[string]string
+}
+
+// 服务端推送的处理函数，body为推送请求的JSON，返回后由Transport回复服务端
+type PushHandler func(body []byte)
+
+type Transport interface {
+	// 连接任一服务端，所有服务端都无法建立长连接时返回错误，如服务端为不支持长连接的1.x版本，调用方应降级使用HTTP
+	Start() error
+	// 连接断开期间返回false，调用方使用HTTP，Transport在后台重连
+	Connected() bool
+	// 发送请求并将响应解析到response，requestType为协议中的请求类型，服务端返回失败时返回*nacos_error.NacosError
+	Request(ctx context.Context, requestType string, request interface{}, response interface{}) error
+	// 注册requestType类型推送的处理函数，同一类型的多个处理函数按注册顺序调用
+	RegisterHandler(requestType string, handler PushHandler)
+	// 重连成功后回调，服务端不保留断开的连接上的订阅和监听，调用方需要重新注册
+	OnReconnected(callback func())
+	Close() error
+}
+
+// 创建Transport，通过ClientConfig.Transport配置
+type TransportFactory func(config Config) (Transport, error)
+
+var ErrNotConnected = errors.New("[remote] transport is not connected")
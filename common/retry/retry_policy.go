@@ -0,0 +1,91 @@
+package retry
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+const (
+	Default_Max_Attempts = 3
+	// 服务端连续失败该次数后熔断
+	Default_Failure_Threshold = 3
+	// 熔断的服务端经过该时间后重新参与选择
+	Default_Open_Duration = 30 * time.Second
+)
+
+// 请求失败时的重试策略，第n次重试前等待 InitialDelay*Multiplier^(n-1)，不超过MaxDelay
+type RetryPolicy struct {
+	// 包括首次请求在内的最大请求次数
+	MaxAttempts  int
+	InitialDelay time.Duration
+	// 退避倍数，小于1时按1处理，即固定间隔
+	Multiplier float64
+	MaxDelay   time.Duration
+	// 等待时间的随机抖动比例，取值0~1，如0.2表示在±20%范围内抖动
+	Jitter float64
+	// 可重试的服务端状态码，为空时所有失败均重试；网络错误总是重试
+	RetryableStatusCodes []int
+}
+
+// 默认策略与之前的行为一致：最多请求3次，失败后立即重试
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{MaxAttempts: Default_Max_Attempts}
+}
+
+// 未配置重试策略时使用，服务端多于3个时最多请求次数等于服务端个数，保证每个服务端都会被尝试一次
+func DefaultRetryPolicyForServers(serverCount int) *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	if serverCount > policy.MaxAttempts {
+		policy.MaxAttempts = serverCount
+	}
+	return policy
+}
+
+func (p *RetryPolicy) Attempts() int {
+	if p.MaxAttempts <= 0 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// 返回第attempt次失败后、下一次请求前的等待时间，attempt从1开始
+func (p *RetryPolicy) Delay(attempt int) time.Duration {
+	if p.InitialDelay <= 0 || attempt <= 0 {
+		return 0
+	}
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	delay := float64(p.InitialDelay) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delay)
+}
+
+func (p *RetryPolicy) IsRetryable(statusCode int) bool {
+	if len(p.RetryableStatusCodes) == 0 {
+		return true
+	}
+	for _, code := range p.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// 单个服务端的熔断配置：连续失败FailureThreshold次后熔断OpenDuration，期间请求直接切换到其他服务端
+type CircuitBreakerConfig struct {
+	FailureThreshold int
+	OpenDuration     time.Duration
+}
+
+func DefaultCircuitBreakerConfig() *CircuitBreakerConfig {
+	return &CircuitBreakerConfig{FailureThreshold: Default_Failure_Threshold, OpenDuration: Default_Open_Duration}
+}
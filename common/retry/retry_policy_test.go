@@ -0,0 +1,43 @@
+package retry
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxAttempts:  5,
+		InitialDelay: 100 * time.Millisecond,
+		Multiplier:   2,
+		MaxDelay:     300 * time.Millisecond,
+	}
+	assert.Equal(t, 100*time.Millisecond, policy.Delay(1))
+	assert.Equal(t, 200*time.Millisecond, policy.Delay(2))
+	assert.Equal(t, 300*time.Millisecond, policy.Delay(3))
+	assert.Equal(t, 300*time.Millisecond, policy.Delay(4))
+
+	policy.Jitter = 0.2
+	for i := 0; i < 10; i++ {
+		delay := policy.Delay(1)
+		assert.True(t, delay >= 80*time.Millisecond && delay <= 120*time.Millisecond)
+	}
+
+	assert.Equal(t, time.Duration(0), DefaultRetryPolicy().Delay(1))
+}
+
+func TestRetryPolicy_IsRetryable(t *testing.T) {
+	assert.True(t, DefaultRetryPolicy().IsRetryable(404))
+
+	policy := &RetryPolicy{RetryableStatusCodes: []int{502, 503}}
+	assert.True(t, policy.IsRetryable(503))
+	assert.False(t, policy.IsRetryable(404))
+	assert.Equal(t, 1, policy.Attempts())
+}
+
+func TestDefaultRetryPolicyForServers(t *testing.T) {
+	assert.Equal(t, Default_Max_Attempts, DefaultRetryPolicyForServers(1).Attempts())
+	assert.Equal(t, Default_Max_Attempts, DefaultRetryPolicyForServers(3).Attempts())
+	assert.Equal(t, 5, DefaultRetryPolicyForServers(5).Attempts())
+}
@@ -0,0 +1,156 @@
+package security
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/common/server_list"
+	"github.com/nacos-group/nacos-sdk-go/utils"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// 服务端开启鉴权时，通过用户名密码登录获取accessToken，并在过期前自动刷新
+type AuthClient struct {
+	username           string
+	password           string
+	agent              http_agent.IHttpAgent
+	timeoutMs          uint64
+	tlsEnable          bool
+	mutex              sync.RWMutex
+	accessToken        string
+	tokenTtl           int64
+	lastRefreshTime    int64
+	tokenRefreshWindow int64
+	stopChan           chan struct{}
+	stopOnce           sync.Once
+}
+
+type loginResult struct {
+	AccessToken string `json:"accessToken"`
+	TokenTtl    int64  `json:"tokenTtl"`
+	GlobalAdmin bool   `json:"globalAdmin"`
+}
+
+const refreshIntervalMs = 5 * 1000
+
+func NewAuthClient(clientCfg constant.ClientConfig, agent http_agent.IHttpAgent) *AuthClient {
+	return &AuthClient{
+		username:  clientCfg.Username,
+		password:  clientCfg.Password,
+		agent:     agent,
+		timeoutMs: clientCfg.TimeoutMs,
+		tlsEnable: clientCfg.TLSConfig.Enable,
+		stopChan:  make(chan struct{}),
+	}
+}
+
+// 更新用户名、密码和超时，用户名或密码变化时清空token，返回是否需要重新登录
+func (ac *AuthClient) UpdateConfig(clientCfg constant.ClientConfig) bool {
+	ac.mutex.Lock()
+	defer ac.mutex.Unlock()
+	ac.timeoutMs = clientCfg.TimeoutMs
+	if ac.username == clientCfg.Username && ac.password == clientCfg.Password {
+		return false
+	}
+	ac.username = clientCfg.Username
+	ac.password = clientCfg.Password
+	ac.accessToken = ""
+	ac.lastRefreshTime = 0
+	return true
+}
+
+func (ac *AuthClient) GetAccessToken() string {
+	ac.mutex.RLock()
+	defer ac.mutex.RUnlock()
+	return ac.accessToken
+}
+
+// token未过期时直接返回，否则依次尝试各个服务端直到登录成功
+func (ac *AuthClient) Login(servers []constant.ServerConfig) (bool, error) {
+	ac.mutex.RLock()
+	username := ac.username
+	valid := ac.accessToken != "" && utils.CurrentMillis()-ac.lastRefreshTime < (ac.tokenTtl-ac.tokenRefreshWindow)*1000
+	ac.mutex.RUnlock()
+	if username == "" || valid {
+		return true, nil
+	}
+	var err error
+	for _, server := range servers {
+		if err = ac.login(server); err == nil {
+			return true, nil
+		}
+		logger.Errorf("login to server:%s:%d failed,err:%s", server.IpAddr, server.Port, err.Error())
+	}
+	return false, err
+}
+
+func (ac *AuthClient) login(server constant.ServerConfig) error {
+	contextPath := server.ContextPath
+	if contextPath == "" {
+		contextPath = constant.WEB_CONTEXT
+	}
+	url := server_list.GetScheme(server, ac.tlsEnable) + "://" + server_list.GetAddress(server) + contextPath + constant.AUTH_LOGIN_PATH
+	ac.mutex.RLock()
+	params := map[string]string{
+		"username": ac.username,
+		"password": ac.password,
+	}
+	timeoutMs := ac.timeoutMs
+	ac.mutex.RUnlock()
+	header := http.Header{}
+	header["Content-Type"] = []string{"application/x-www-form-urlencoded"}
+	response, err := ac.agent.Post(url, header, timeoutMs, params)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return errors.New("login failed, status code:" + strconv.Itoa(response.StatusCode) + ", body:" + string(body))
+	}
+	var result loginResult
+	if err = json.Unmarshal(body, &result); err != nil {
+		return err
+	}
+	if result.AccessToken == "" {
+		return errors.New("login response does not contain accessToken: " + string(body))
+	}
+	ac.mutex.Lock()
+	ac.accessToken = result.AccessToken
+	ac.tokenTtl = result.TokenTtl
+	ac.tokenRefreshWindow = result.TokenTtl / 10
+	ac.lastRefreshTime = utils.CurrentMillis()
+	ac.mutex.Unlock()
+	return nil
+}
+
+// 后台定时检查token，在过期前重新登录，未配置用户名时不登录
+func (ac *AuthClient) AutoRefresh(getServers func() []constant.ServerConfig) {
+	go func() {
+		ticker := time.NewTicker(refreshIntervalMs * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ac.stopChan:
+				return
+			case <-ticker.C:
+				ac.Login(getServers())
+			}
+		}
+	}()
+}
+
+func (ac *AuthClient) Stop() {
+	ac.stopOnce.Do(func() {
+		close(ac.stopChan)
+	})
+}
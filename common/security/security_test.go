@@ -0,0 +1,51 @@
+package security
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/mock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var serverConfigTest = constant.ServerConfig{
+	IpAddr:      "console.nacos.io",
+	Port:        80,
+	ContextPath: "/nacos",
+}
+
+func TestAuthClient_Login(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().Post(gomock.Eq("http://console.nacos.io:80/nacos/v1/auth/login"),
+		gomock.Any(), gomock.Eq(uint64(10*1000)),
+		gomock.Eq(map[string]string{"username": "nacos", "password": "secret"})).Times(1).
+		Return(http_agent.FakeHttpResponse(200, `{"accessToken":"token","tokenTtl":18000,"globalAdmin":true}`), nil)
+
+	ac := NewAuthClient(constant.ClientConfig{TimeoutMs: 10 * 1000, Username: "nacos", Password: "secret"}, mockIHttpAgent)
+	success, err := ac.Login([]constant.ServerConfig{serverConfigTest})
+	assert.Nil(t, err)
+	assert.True(t, success)
+	assert.Equal(t, "token", ac.GetAccessToken())
+
+	// token未过期时不会重新登录
+	success, err = ac.Login([]constant.ServerConfig{serverConfigTest})
+	assert.Nil(t, err)
+	assert.True(t, success)
+}
+
+func TestAuthClient_LoginFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		Return(http_agent.FakeHttpResponse(403, `unknown user!`), nil)
+
+	ac := NewAuthClient(constant.ClientConfig{Username: "nacos", Password: "wrong"}, mockIHttpAgent)
+	success, err := ac.Login([]constant.ServerConfig{serverConfigTest})
+	assert.NotNil(t, err)
+	assert.False(t, success)
+	assert.Equal(t, "", ac.GetAccessToken())
+}
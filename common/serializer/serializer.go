@@ -0,0 +1,45 @@
+package serializer
+
+import (
+	"encoding/json"
+)
+
+// 与encoding/json输出相同的序列化方式的名称，缓存文件按名称判断能否读取
+const NAME_JSON = "json"
+
+// 服务数据的序列化方式，用于解析推送和查询结果、读写服务缓存文件
+// 可包装jsoniter、easyjson等实现，输出与encoding/json相同时Name应返回NAME_JSON
+type Serializer interface {
+	Name() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+type jsonSerializer struct{}
+
+func (jsonSerializer) Name() string {
+	return NAME_JSON
+}
+
+func (jsonSerializer) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonSerializer) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+var defaultSerializer Serializer = jsonSerializer{}
+
+// 使用encoding/json，未配置序列化方式时使用
+func Default() Serializer {
+	return defaultSerializer
+}
+
+// s为nil时返回Default()
+func OrDefault(s Serializer) Serializer {
+	if s == nil {
+		return Default()
+	}
+	return s
+}
@@ -0,0 +1,32 @@
+package serializer
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDefault(t *testing.T) {
+	service := model.Service{Name: "DEFAULT_GROUP@@DEMO", Hosts: []model.Instance{
+		{Ip: "10.0.0.10", Port: 80, Metadata: map[string]string{"zone": "a", "version": "1.0.0"}},
+	}}
+	data, err := Default().Marshal(service)
+	assert.Nil(t, err)
+	var decoded model.Service
+	assert.Nil(t, Default().Unmarshal(data, &decoded))
+	assert.Equal(t, service, decoded)
+	assert.Equal(t, NAME_JSON, Default().Name())
+}
+
+type namedSerializer struct {
+	jsonSerializer
+}
+
+func (namedSerializer) Name() string {
+	return "custom"
+}
+
+func TestOrDefault(t *testing.T) {
+	assert.Equal(t, Default(), OrDefault(nil))
+	assert.Equal(t, "custom", OrDefault(namedSerializer{}).Name())
+}
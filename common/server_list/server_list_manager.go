@@ -0,0 +1,268 @@
+package server_list
+
+import (
+	"errors"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/event"
+	"github.com/nacos-group/nacos-sdk-go/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/common/retry"
+	"math/rand"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	Default_Refresh_Interval = 30 * time.Second
+	// 连续失败该次数后将服务端标记为不健康
+	Default_Max_Failures = retry.Default_Failure_Threshold
+	// 不健康的服务端经过该时间后重新参与选择
+	Default_Unhealthy_Recover_Interval = retry.Default_Open_Duration
+)
+
+type serverHealth struct {
+	failures       int
+	unhealthySince time.Time
+}
+
+// 管理nacos服务端列表：从地址服务器定时拉取列表，按连续失败次数标记不健康节点，选择时跳过不健康节点
+type ServerListManager struct {
+	mutex           sync.RWMutex
+	servers         []constant.ServerConfig
+	health          map[string]*serverHealth
+	endpoint        string
+	contextPath     string
+	httpAgent       http_agent.IHttpAgent
+	timeoutMs       uint64
+	maxFailures     int
+	recoverInterval time.Duration
+	index           uint64
+	events          *event.EventBus
+	stopChan        chan struct{}
+	stopOnce        sync.Once
+}
+
+// contextPath用于访问地址服务器和从地址服务器获取的服务端，为空时为/nacos
+func NewServerListManager(servers []constant.ServerConfig, endpoint string, contextPath string, httpAgent http_agent.IHttpAgent, timeoutMs uint64) (*ServerListManager, error) {
+	if len(servers) == 0 && endpoint == "" {
+		return nil, errors.New("both serverlist  and  endpoint are empty")
+	}
+	m := newServerListManager(servers, httpAgent, timeoutMs)
+	m.endpoint = endpoint
+	m.contextPath = contextPath
+	if m.contextPath == "" {
+		m.contextPath = constant.WEB_CONTEXT
+	}
+	if endpoint != "" {
+		m.refreshFromEndpoint()
+		go m.refresher(Default_Refresh_Interval)
+	}
+	return m, nil
+}
+
+// 仅使用本地缓存时使用，服务端列表可为空，不从地址服务器拉取
+func NewLocalServerListManager(servers []constant.ServerConfig) *ServerListManager {
+	return newServerListManager(servers, nil, 0)
+}
+
+func newServerListManager(servers []constant.ServerConfig, httpAgent http_agent.IHttpAgent, timeoutMs uint64) *ServerListManager {
+	return &ServerListManager{
+		servers:         servers,
+		health:          map[string]*serverHealth{},
+		httpAgent:       httpAgent,
+		timeoutMs:       timeoutMs,
+		maxFailures:     Default_Max_Failures,
+		recoverInterval: Default_Unhealthy_Recover_Interval,
+		stopChan:        make(chan struct{}),
+	}
+}
+
+// 设置熔断配置，字段为零值时保留默认值
+func (m *ServerListManager) SetCircuitBreaker(config retry.CircuitBreakerConfig) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if config.FailureThreshold > 0 {
+		m.maxFailures = config.FailureThreshold
+	}
+	if config.OpenDuration > 0 {
+		m.recoverInterval = config.OpenDuration
+	}
+}
+
+// 服务端被标记为不健康时发布ServerUnhealthyEvent
+func (m *ServerListManager) SetEventBus(events *event.EventBus) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.events = events
+}
+
+func (m *ServerListManager) SetTimeoutMs(timeoutMs uint64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.timeoutMs = timeoutMs
+}
+
+func GetAddress(server constant.ServerConfig) string {
+	return server.IpAddr + ":" + strconv.Itoa(int(server.Port))
+}
+
+// 服务端未指定Scheme时，开启TLS使用https，否则使用http
+func GetScheme(server constant.ServerConfig, tlsEnable bool) string {
+	if server.Scheme != "" {
+		return server.Scheme
+	}
+	if tlsEnable {
+		return "https"
+	}
+	return "http"
+}
+
+// 返回全部服务端
+func (m *ServerListManager) GetServerList() []constant.ServerConfig {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.servers
+}
+
+// 返回当前健康的服务端，全部不健康时返回全部服务端
+func (m *ServerListManager) GetHealthyServers() []constant.ServerConfig {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	now := time.Now()
+	healthy := make([]constant.ServerConfig, 0, len(m.servers))
+	for _, server := range m.servers {
+		if m.isHealthy(GetAddress(server), now) {
+			healthy = append(healthy, server)
+		}
+	}
+	if len(healthy) == 0 {
+		return m.servers
+	}
+	return healthy
+}
+
+func (m *ServerListManager) isHealthy(address string, now time.Time) bool {
+	h, ok := m.health[address]
+	if !ok || h.failures < m.maxFailures {
+		return true
+	}
+	return now.Sub(h.unhealthySince) >= m.recoverInterval
+}
+
+// 轮询选择一个健康的服务端
+func (m *ServerListManager) NextServer() (constant.ServerConfig, error) {
+	servers := m.GetHealthyServers()
+	if len(servers) == 0 {
+		return constant.ServerConfig{}, errors.New("server list is empty")
+	}
+	index := atomic.AddUint64(&m.index, 1) - 1
+	return servers[index%uint64(len(servers))], nil
+}
+
+// 随机选择一个健康的服务端
+func (m *ServerListManager) RandomServer() (constant.ServerConfig, error) {
+	servers := m.GetHealthyServers()
+	if len(servers) == 0 {
+		return constant.ServerConfig{}, errors.New("server list is empty")
+	}
+	return servers[rand.Intn(len(servers))], nil
+}
+
+func (m *ServerListManager) MarkSuccess(address string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.health, address)
+}
+
+func (m *ServerListManager) MarkFailure(address string) {
+	m.mutex.Lock()
+	h, ok := m.health[address]
+	if !ok {
+		h = &serverHealth{}
+		m.health[address] = h
+	}
+	h.failures++
+	failures := h.failures
+	unhealthy := failures == m.maxFailures
+	if h.failures >= m.maxFailures {
+		h.unhealthySince = time.Now()
+	}
+	events := m.events
+	m.mutex.Unlock()
+	if unhealthy {
+		logger.Warnf("server:%s failed %d times in a row, mark it unhealthy", address, failures)
+		events.Publish(event.ServerUnhealthyEvent{Address: address, Failures: failures})
+	}
+}
+
+func (m *ServerListManager) refresher(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stopChan:
+			return
+		case <-ticker.C:
+			m.refreshFromEndpoint()
+		}
+	}
+}
+
+// 从地址服务器拉取服务端列表，列表为空时保留原列表
+func (m *ServerListManager) refreshFromEndpoint() {
+	urlString := "http://" + m.endpoint + m.contextPath + "/serverlist"
+	m.mutex.RLock()
+	timeoutMs := m.timeoutMs
+	m.mutex.RUnlock()
+	result := m.httpAgent.RequestOnlyResult(http.MethodGet, urlString, nil, timeoutMs, nil)
+	logger.Infof("http nacos server list: <%s>", result)
+
+	var servers []constant.ServerConfig
+	for _, line := range strings.Split(result, "\n") {
+		if line != "" {
+			splitLine := strings.Split(strings.TrimSpace(line), ":")
+			port := 8848
+			var err error
+			if len(splitLine) == 2 {
+				port, err = strconv.Atoi(splitLine[1])
+				if err != nil {
+					logger.Errorf("get port from server:<%s>  error: <%s>", line, err.Error())
+					continue
+				}
+			}
+			servers = append(servers, constant.ServerConfig{IpAddr: splitLine[0], Port: uint64(port), ContextPath: m.contextPath})
+		}
+	}
+	if len(servers) == 0 {
+		return
+	}
+	m.SetServerList(servers)
+}
+
+// 替换服务端列表，保留仍在列表中的服务端的健康状态
+func (m *ServerListManager) SetServerList(servers []constant.ServerConfig) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if !reflect.DeepEqual(m.servers, servers) {
+		logger.Infof("server list is updated, old: <%v>,new:<%v>", m.servers, servers)
+		m.servers = servers
+		health := map[string]*serverHealth{}
+		for _, server := range servers {
+			if h, ok := m.health[GetAddress(server)]; ok {
+				health[GetAddress(server)] = h
+			}
+		}
+		m.health = health
+	}
+}
+
+func (m *ServerListManager) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stopChan)
+	})
+}
@@ -0,0 +1,118 @@
+package server_list
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/event"
+	"github.com/nacos-group/nacos-sdk-go/mock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var serversTest = []constant.ServerConfig{
+	{IpAddr: "127.0.0.1", Port: 8848, ContextPath: "/nacos"},
+	{IpAddr: "127.0.0.2", Port: 8848, ContextPath: "/nacos"},
+}
+
+func TestNewServerListManager_Empty(t *testing.T) {
+	_, err := NewServerListManager(nil, "", "", nil, 10*1000)
+	assert.NotNil(t, err)
+}
+
+func TestServerListManager_MarkFailure(t *testing.T) {
+	m, err := NewServerListManager(serversTest, "", "", nil, 10*1000)
+	assert.Nil(t, err)
+	defer m.Stop()
+
+	for i := 0; i < Default_Max_Failures-1; i++ {
+		m.MarkFailure("127.0.0.1:8848")
+	}
+	assert.Equal(t, serversTest, m.GetHealthyServers())
+
+	m.MarkFailure("127.0.0.1:8848")
+	assert.Equal(t, serversTest[1:], m.GetHealthyServers())
+	for i := 0; i < 4; i++ {
+		server, err := m.NextServer()
+		assert.Nil(t, err)
+		assert.Equal(t, serversTest[1], server)
+	}
+
+	// 成功一次即恢复健康
+	m.MarkSuccess("127.0.0.1:8848")
+	assert.Equal(t, serversTest, m.GetHealthyServers())
+}
+
+func TestServerListManager_UnhealthyEvent(t *testing.T) {
+	m, err := NewServerListManager(serversTest, "", "", nil, 10*1000)
+	assert.Nil(t, err)
+	defer m.Stop()
+	bus := event.NewEventBus()
+	var events []event.Event
+	bus.Subscribe(func(e event.Event) { events = append(events, e) })
+	m.SetEventBus(bus)
+
+	for i := 0; i < Default_Max_Failures+2; i++ {
+		m.MarkFailure("127.0.0.1:8848")
+	}
+	assert.Equal(t, []event.Event{event.ServerUnhealthyEvent{Address: "127.0.0.1:8848", Failures: Default_Max_Failures}}, events)
+}
+
+func TestServerListManager_AllUnhealthy(t *testing.T) {
+	m, err := NewServerListManager(serversTest, "", "", nil, 10*1000)
+	assert.Nil(t, err)
+	defer m.Stop()
+
+	for _, server := range serversTest {
+		for i := 0; i < Default_Max_Failures; i++ {
+			m.MarkFailure(GetAddress(server))
+		}
+	}
+	assert.Equal(t, serversTest, m.GetHealthyServers())
+}
+
+func TestServerListManager_Recover(t *testing.T) {
+	m, err := NewServerListManager(serversTest, "", "", nil, 10*1000)
+	assert.Nil(t, err)
+	defer m.Stop()
+	m.recoverInterval = 10 * time.Millisecond
+
+	for i := 0; i < Default_Max_Failures; i++ {
+		m.MarkFailure("127.0.0.1:8848")
+	}
+	assert.Equal(t, serversTest[1:], m.GetHealthyServers())
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, serversTest, m.GetHealthyServers())
+}
+
+func TestServerListManager_RefreshFromEndpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	gomock.InOrder(
+		mockIHttpAgent.EXPECT().RequestOnlyResult(gomock.Eq("GET"), gomock.Eq("http://127.0.0.1:8080/nacos/serverlist"),
+			gomock.Nil(), gomock.Eq(uint64(10*1000)), gomock.Nil()).Times(1).
+			Return("127.0.0.1:8848\n127.0.0.2\n"),
+		mockIHttpAgent.EXPECT().RequestOnlyResult(gomock.Eq("GET"), gomock.Eq("http://127.0.0.1:8080/nacos/serverlist"),
+			gomock.Nil(), gomock.Eq(uint64(10*1000)), gomock.Nil()).Times(1).
+			Return(""),
+	)
+
+	m, err := NewServerListManager(nil, "127.0.0.1:8080", "", mockIHttpAgent, 10*1000)
+	assert.Nil(t, err)
+	defer m.Stop()
+	assert.Equal(t, []constant.ServerConfig{
+		{IpAddr: "127.0.0.1", Port: 8848, ContextPath: constant.WEB_CONTEXT},
+		{IpAddr: "127.0.0.2", Port: 8848, ContextPath: constant.WEB_CONTEXT},
+	}, m.GetServerList())
+
+	// 地址服务器返回空列表时保留原列表
+	m.refreshFromEndpoint()
+	assert.Len(t, m.GetServerList(), 2)
+}
+
+func TestGetScheme(t *testing.T) {
+	assert.Equal(t, "http", GetScheme(serversTest[0], false))
+	assert.Equal(t, "https", GetScheme(serversTest[0], true))
+	assert.Equal(t, "http", GetScheme(constant.ServerConfig{IpAddr: "127.0.0.1", Port: 8848, Scheme: "http"}, true))
+}
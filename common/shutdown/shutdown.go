@@ -0,0 +1,96 @@
+package shutdown
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// 收到退出信号后等待钩子执行的最长时间，超时后不再等待
+const Default_Hook_Timeout = 10 * time.Second
+
+var (
+	mutex    sync.Mutex
+	hooks    = map[int64]func(){}
+	nextId   int64
+	started  bool
+	signals  = []os.Signal{syscall.SIGTERM, os.Interrupt}
+	timeout  = Default_Hook_Timeout
+	reraise  = raise
+	notifyCh chan os.Signal
+)
+
+// 注册收到SIGTERM或SIGINT时执行的钩子，返回的函数用于取消注册
+// 首次注册时开始监听信号，收到信号后并发执行所有钩子，执行完或超时后恢复信号的默认处理并重新发送该信号，使进程按原有方式退出
+func Register(hook func()) (remove func()) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	nextId++
+	id := nextId
+	hooks[id] = hook
+	if !started {
+		started = true
+		notifyCh = make(chan os.Signal, 1)
+		signal.Notify(notifyCh, signals...)
+		go wait(notifyCh)
+	}
+	return func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+		delete(hooks, id)
+	}
+}
+
+func wait(ch chan os.Signal) {
+	sig := <-ch
+	logger.Infof("received signal %s, running %d shutdown hooks", sig.String(), len(snapshot()))
+	Run()
+	signal.Stop(ch)
+	mutex.Lock()
+	started = false
+	mutex.Unlock()
+	reraise(sig)
+}
+
+// 并发执行当前注册的所有钩子，最多等待Default_Hook_Timeout，可在自己的退出流程中直接调用
+func Run() {
+	var wg sync.WaitGroup
+	for _, hook := range snapshot() {
+		wg.Add(1)
+		go func(hook func()) {
+			defer wg.Done()
+			hook()
+		}(hook)
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		logger.Warnf("shutdown hooks did not finish in %s", timeout.String())
+	}
+}
+
+func snapshot() []func() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	result := make([]func(), 0, len(hooks))
+	for _, hook := range hooks {
+		result = append(result, hook)
+	}
+	return result
+}
+
+// 不支持向自身发送该信号的平台上直接退出
+func raise(sig os.Signal) {
+	if p, err := os.FindProcess(os.Getpid()); err == nil && p.Signal(sig) == nil {
+		return
+	}
+	os.Exit(1)
+}
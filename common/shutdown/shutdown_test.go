@@ -0,0 +1,45 @@
+package shutdown
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRegister_Signal(t *testing.T) {
+	raised := make(chan os.Signal, 1)
+	reraise = func(sig os.Signal) { raised <- sig }
+	defer func() { reraise = raise }()
+
+	var calls int32
+	removeA := Register(func() { atomic.AddInt32(&calls, 1) })
+	defer removeA()
+	removeB := Register(func() { atomic.AddInt32(&calls, 10) })
+	removeB()
+
+	p, _ := os.FindProcess(os.Getpid())
+	assert.Nil(t, p.Signal(syscall.SIGTERM))
+	select {
+	case sig := <-raised:
+		assert.Equal(t, syscall.SIGTERM, sig)
+	case <-time.After(3 * time.Second):
+		t.Fatal("signal was not re-raised")
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "removed hooks should not run")
+}
+
+func TestRun_Timeout(t *testing.T) {
+	timeout = 50 * time.Millisecond
+	defer func() { timeout = Default_Hook_Timeout }()
+	block := make(chan struct{})
+	defer close(block)
+	remove := Register(func() { <-block })
+	defer remove()
+
+	start := time.Now()
+	Run()
+	assert.True(t, time.Since(start) < time.Second)
+}
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// key不存在时Get返回的错误
+var ErrNotFound = errors.New("key not found")
+
+// 服务缓存和配置快照的持久化后端，默认写入本地文件
+// 文件系统不可持久化的容器中可实现为Redis、bolt等，使客户端重启后仍能从缓存启动
+// key以"/"分隔层级，如naming/public/DEFAULT_GROUP@@demo，实现需支持并发调用
+type Store interface {
+	// key不存在时返回ErrNotFound
+	Get(key string) ([]byte, error)
+	Put(key string, value []byte) error
+	// key不存在时不返回错误
+	Delete(key string) error
+	// 返回以prefix开头的所有key
+	Keys(prefix string) ([]string, error)
+}
+
+type memoryStore struct {
+	mutex sync.RWMutex
+	data  map[string][]byte
+}
+
+// 基于内存的Store，进程退出后数据丢失，用于单元测试或与其他Store组合使用
+func NewMemoryStore() Store {
+	return &memoryStore{data: map[string][]byte{}}
+}
+
+func (s *memoryStore) Get(key string) ([]byte, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	value, ok := s.data[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), value...), nil
+}
+
+func (s *memoryStore) Put(key string, value []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.data[key] = append([]byte(nil), value...)
+	return nil
+}
+
+func (s *memoryStore) Delete(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.data, key)
+	return nil
+}
+
+func (s *memoryStore) Keys(prefix string) ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	var keys []string
+	for key := range s.data {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
@@ -0,0 +1,30 @@
+package store
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore()
+	_, err := s.Get("naming/public/DEFAULT_GROUP@@demo")
+	assert.Equal(t, ErrNotFound, err)
+
+	value := []byte("v1")
+	assert.Nil(t, s.Put("naming/public/DEFAULT_GROUP@@demo", value))
+	assert.Nil(t, s.Put("naming/public/DEFAULT_GROUP@@order", []byte("v2")))
+	assert.Nil(t, s.Put("config/public/DEFAULT_GROUP/app.yaml", []byte("v3")))
+	value[0] = 'x'
+	b, err := s.Get("naming/public/DEFAULT_GROUP@@demo")
+	assert.Nil(t, err)
+	assert.Equal(t, "v1", string(b))
+
+	keys, err := s.Keys("naming/")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"naming/public/DEFAULT_GROUP@@demo", "naming/public/DEFAULT_GROUP@@order"}, keys)
+
+	assert.Nil(t, s.Delete("naming/public/DEFAULT_GROUP@@demo"))
+	assert.Nil(t, s.Delete("naming/public/DEFAULT_GROUP@@demo"))
+	keys, _ = s.Keys("naming/")
+	assert.Equal(t, []string{"naming/public/DEFAULT_GROUP@@order"}, keys)
+}
@@ -0,0 +1,83 @@
+package tracing
+
+import (
+	"context"
+)
+
+// SDK的Tracer名称
+const Instrumentation_Name = "github.com/nacos-group/nacos-sdk-go"
+
+// span属性名
+const (
+	ATTR_SERVER_ADDRESS = "server.address"
+	ATTR_NAMESPACE      = "nacos.namespace"
+	ATTR_GROUP          = "nacos.group"
+	ATTR_DATA_ID        = "nacos.data_id"
+	ATTR_SERVICE_NAME   = "nacos.service_name"
+	ATTR_CLUSTERS       = "nacos.clusters"
+	ATTR_ATTEMPTS       = "nacos.attempts"
+	ATTR_PUSH_TYPE      = "nacos.push_type"
+	ATTR_CHANGED        = "nacos.changed"
+)
+
+type StatusCode int
+
+// 与OpenTelemetry的codes一致
+const (
+	STATUS_UNSET StatusCode = iota
+	STATUS_ERROR
+	STATUS_OK
+)
+
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// 与OpenTelemetry trace API结构一致的最小接口，SDK不直接依赖OpenTelemetry，
+// 使用时将trace.TracerProvider包装为该接口设置到ClientConfig.TracerProvider
+type TracerProvider interface {
+	Tracer(name string) Tracer
+}
+
+type Tracer interface {
+	// 返回的ctx中包含新的span，后续请求在该span下进行
+	Start(ctx context.Context, spanName string, attrs ...Attribute) (context.Context, Span)
+}
+
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	SetStatus(code StatusCode, description string)
+	End()
+}
+
+// provider为nil时返回不记录任何内容的Tracer
+func NewTracer(provider TracerProvider) Tracer {
+	if provider == nil {
+		return noopTracer{}
+	}
+	return provider.Tracer(Instrumentation_Name)
+}
+
+// err不为nil时记录错误并将状态设为STATUS_ERROR，然后结束span
+func End(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(STATUS_ERROR, err.Error())
+	}
+	span.End()
+}
+
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, spanName string, attrs ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attrs ...Attribute)              {}
+func (noopSpan) RecordError(err error)                         {}
+func (noopSpan) SetStatus(code StatusCode, description string) {}
+func (noopSpan) End()                                          {}
@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type recordingSpan struct {
+	err    error
+	status StatusCode
+	ended  bool
+}
+
+func (s *recordingSpan) SetAttributes(attrs ...Attribute) {}
+func (s *recordingSpan) RecordError(err error)            { s.err = err }
+func (s *recordingSpan) SetStatus(code StatusCode, description string) {
+	s.status = code
+}
+func (s *recordingSpan) End() { s.ended = true }
+
+func TestEnd(t *testing.T) {
+	span := &recordingSpan{}
+	End(span, nil)
+	assert.True(t, span.ended)
+	assert.Nil(t, span.err)
+	assert.Equal(t, STATUS_UNSET, span.status)
+
+	span = &recordingSpan{}
+	err := errors.New("timeout")
+	End(span, err)
+	assert.True(t, span.ended)
+	assert.Equal(t, err, span.err)
+	assert.Equal(t, STATUS_ERROR, span.status)
+}
+
+func TestNewTracer_Noop(t *testing.T) {
+	ctx := context.Background()
+	newCtx, span := NewTracer(nil).Start(ctx, "GET /v1/ns/instance/list")
+	assert.Equal(t, ctx, newCtx)
+	End(span, errors.New("ignored"))
+}
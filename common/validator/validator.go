@@ -0,0 +1,161 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"strings"
+	"unicode"
+)
+
+const (
+	// 配置内容的最大字节数
+	Max_Content_Size = 100 * 1024
+	// dataId和group的最大长度
+	Max_Config_Key_Length = 256
+	// 实例权重的上限，与服务端一致
+	Max_Weight = 10000
+)
+
+// 一个不合法的字段
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) String() string {
+	return "param." + e.Field + " " + e.Message
+}
+
+// 参数校验失败，Errors列出所有不合法的字段，请求未发送到服务端
+// 可通过errors.As判断：var ve *validator.ValidationError; errors.As(err, &ve)
+type ValidationError struct {
+	Method string
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, fieldError := range e.Errors {
+		msgs = append(msgs, fieldError.String())
+	}
+	return "[client." + e.Method + "] " + strings.Join(msgs, "; ")
+}
+
+// 按顺序校验各字段并收集所有错误，通过Err返回
+// 如 validator.New("PublishConfig").DataId("dataId", param.DataId).Group("group", param.Group).Err()
+type Validator struct {
+	method string
+	errors []FieldError
+}
+
+func New(method string) *Validator {
+	return &Validator{method: method}
+}
+
+func (v *Validator) Add(field string, message string) *Validator {
+	v.errors = append(v.errors, FieldError{Field: field, Message: message})
+	return v
+}
+
+func (v *Validator) Required(field string, value string) *Validator {
+	if value == "" {
+		v.Add(field, "can not be empty")
+	}
+	return v
+}
+
+// 与服务端一致，只能包含字母、数字和_-.:
+func (v *Validator) DataId(field string, value string) *Validator {
+	return v.configKey(field, value)
+}
+
+func (v *Validator) Group(field string, value string) *Validator {
+	return v.configKey(field, value)
+}
+
+func (v *Validator) configKey(field string, value string) *Validator {
+	if value == "" {
+		return v.Add(field, "can not be empty")
+	}
+	if len(value) > Max_Config_Key_Length {
+		return v.Add(field, fmt.Sprintf("is longer than %d", Max_Config_Key_Length))
+	}
+	for _, c := range value {
+		if !isConfigKeyChar(c) {
+			return v.Add(field, fmt.Sprintf("contains invalid character %q, only letters, digits and _-.: are allowed", c))
+		}
+	}
+	return v
+}
+
+func isConfigKeyChar(c rune) bool {
+	return c < unicode.MaxASCII && (unicode.IsLetter(c) || unicode.IsDigit(c) || strings.ContainsRune("_-.:", c))
+}
+
+func (v *Validator) Content(field string, value string) *Validator {
+	return v.ContentWithLimit(field, value, Max_Content_Size)
+}
+
+// limit不大于0时使用Max_Content_Size
+func (v *Validator) ContentWithLimit(field string, value string, limit int) *Validator {
+	if limit <= 0 {
+		limit = Max_Content_Size
+	}
+	if value == "" {
+		return v.Add(field, "can not be empty")
+	}
+	if len(value) > limit {
+		v.Add(field, fmt.Sprintf("is %d bytes, exceeds the limit of %d bytes", len(value), limit))
+	}
+	return v
+}
+
+// 服务名不能为空，不能包含分组分隔符@@和空白字符
+func (v *Validator) ServiceName(field string, value string) *Validator {
+	if value == "" {
+		return v.Add(field, "can not be empty")
+	}
+	return v.namingName(field, value)
+}
+
+// 服务的分组名，为空时使用DEFAULT_GROUP
+func (v *Validator) GroupName(field string, value string) *Validator {
+	if value == "" {
+		return v
+	}
+	return v.namingName(field, value)
+}
+
+func (v *Validator) namingName(field string, value string) *Validator {
+	if strings.Contains(value, constant.SERVICE_INFO_SPLITER) {
+		return v.Add(field, "can not contain "+constant.SERVICE_INFO_SPLITER)
+	}
+	for _, c := range value {
+		if unicode.IsSpace(c) || unicode.IsControl(c) {
+			return v.Add(field, fmt.Sprintf("contains invalid character %q", c))
+		}
+	}
+	return v
+}
+
+func (v *Validator) Weight(field string, value float64) *Validator {
+	if value < 0 || value > Max_Weight {
+		v.Add(field, fmt.Sprintf("%v is out of range [0, %d]", value, Max_Weight))
+	}
+	return v
+}
+
+func (v *Validator) Port(field string, value uint64) *Validator {
+	if value == 0 || value > 65535 {
+		v.Add(field, fmt.Sprintf("%d is out of range [1, 65535]", value))
+	}
+	return v
+}
+
+// 没有不合法的字段时返回nil，否则返回*ValidationError
+func (v *Validator) Err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return &ValidationError{Method: v.method, Errors: v.errors}
+}
@@ -0,0 +1,41 @@
+package validator
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestValidator_Config(t *testing.T) {
+	assert.Nil(t, New("PublishConfig").DataId("dataId", "app.json").Group("group", "DEFAULT_GROUP").Content("content", "a=b").Err())
+
+	err := New("PublishConfig").DataId("dataId", "app json").Group("group", "").Content("content", strings.Repeat("a", Max_Content_Size+1)).Err()
+	var ve *ValidationError
+	assert.True(t, errors.As(err, &ve))
+	assert.Equal(t, "PublishConfig", ve.Method)
+	assert.Equal(t, []string{"dataId", "group", "content"}, []string{ve.Errors[0].Field, ve.Errors[1].Field, ve.Errors[2].Field},
+		"all invalid fields should be reported")
+	assert.True(t, strings.HasPrefix(err.Error(), "[client.PublishConfig] param.dataId contains invalid character ' '"))
+
+	assert.NotNil(t, New("GetConfig").DataId("dataId", "配置").Group("group", "g").Err())
+	assert.NotNil(t, New("GetConfig").DataId("dataId", strings.Repeat("a", Max_Config_Key_Length+1)).Group("group", "g").Err())
+
+	assert.Nil(t, New("PublishConfig").ContentWithLimit("content", strings.Repeat("a", Max_Content_Size+1), 2*Max_Content_Size).Err())
+	assert.NotNil(t, New("PublishConfig").ContentWithLimit("content", "abc", 2).Err())
+}
+
+func TestValidator_Naming(t *testing.T) {
+	assert.Nil(t, New("RegisterInstance").ServiceName("serviceName", "demo.go").GroupName("groupName", "").
+		Port("port", 8848).Weight("weight", 1).Err())
+
+	err := New("RegisterInstance").ServiceName("serviceName", "DEFAULT_GROUP@@demo").GroupName("groupName", "my group").
+		Port("port", 70000).Weight("weight", -1).Err()
+	var ve *ValidationError
+	assert.True(t, errors.As(err, &ve))
+	assert.Equal(t, 4, len(ve.Errors))
+
+	assert.NotNil(t, New("RegisterInstance").ServiceName("serviceName", "").Err())
+	assert.NotNil(t, New("RegisterInstance").Port("port", 0).Err())
+	assert.NotNil(t, New("RegisterInstance").Weight("weight", Max_Weight+1).Err())
+}
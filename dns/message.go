@@ -0,0 +1,160 @@
+package dns
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+)
+
+// 只实现本地DNS服务需要的报文格式，见RFC 1035和RFC 2782
+const (
+	headerLen = 12
+	// 不支持EDNS时UDP报文的最大长度
+	maxUdpLen = 512
+
+	typeA   uint16 = 1
+	typeSRV uint16 = 33
+	classIN uint16 = 1
+
+	rcodeSuccess  = 0
+	rcodeFormErr  = 1
+	rcodeServFail = 2
+	rcodeNXDomain = 3
+	rcodeNotImp   = 4
+	rcodeRefused  = 5
+
+	flagQR = 1 << 15
+	flagAA = 1 << 10
+	flagTC = 1 << 9
+	flagRD = 1 << 8
+)
+
+var errInvalidMessage = errors.New("[dns] invalid message")
+
+type question struct {
+	Name  string
+	Type  uint16
+	Class uint16
+	// 问题在请求中的原始字节，响应中原样返回
+	raw []byte
+}
+
+type resource struct {
+	Name string
+	Type uint16
+	TTL  uint32
+	Data []byte
+}
+
+// 解析请求头和第一个问题，请求中的域名不应使用压缩
+func parseQuery(msg []byte) (id uint16, flags uint16, q question, err error) {
+	if len(msg) < headerLen {
+		return 0, 0, q, errInvalidMessage
+	}
+	id = binary.BigEndian.Uint16(msg[0:])
+	flags = binary.BigEndian.Uint16(msg[2:])
+	if binary.BigEndian.Uint16(msg[4:]) == 0 {
+		return id, flags, q, errInvalidMessage
+	}
+	var labels []string
+	offset := headerLen
+	for {
+		if offset >= len(msg) {
+			return id, flags, q, errInvalidMessage
+		}
+		length := int(msg[offset])
+		offset++
+		if length == 0 {
+			break
+		}
+		if length > 63 || offset+length > len(msg) {
+			return id, flags, q, errInvalidMessage
+		}
+		labels = append(labels, string(msg[offset:offset+length]))
+		offset += length
+	}
+	if offset+4 > len(msg) {
+		return id, flags, q, errInvalidMessage
+	}
+	q.Name = strings.Join(labels, ".")
+	q.Type = binary.BigEndian.Uint16(msg[offset:])
+	q.Class = binary.BigEndian.Uint16(msg[offset+2:])
+	q.raw = msg[headerLen : offset+4]
+	return id, flags, q, nil
+}
+
+// 生成响应，answers的名称使用指向问题的压缩指针，超过maxLen时丢弃多余记录并设置TC
+func buildResponse(id uint16, queryFlags uint16, rcode int, q *question, answers, additionals []resource, maxLen int) []byte {
+	flags := uint16(flagQR|flagAA) | queryFlags&flagRD | queryFlags&(0xF<<11) | uint16(rcode)
+	msg := make([]byte, headerLen, maxUdpLen)
+	binary.BigEndian.PutUint16(msg[0:], id)
+	if q == nil {
+		binary.BigEndian.PutUint16(msg[2:], flags)
+		return msg
+	}
+	binary.BigEndian.PutUint16(msg[4:], 1)
+	msg = append(msg, q.raw...)
+	var ancount, arcount uint16
+	for _, answer := range answers {
+		record := appendResource(nil, answer, true)
+		if maxLen > 0 && len(msg)+len(record) > maxLen {
+			flags |= flagTC
+			break
+		}
+		msg = append(msg, record...)
+		ancount++
+	}
+	// 附加记录放不下时不设置TC，客户端可以再查询A记录
+	for _, additional := range additionals {
+		record := appendResource(nil, additional, false)
+		if flags&flagTC != 0 || maxLen > 0 && len(msg)+len(record) > maxLen {
+			break
+		}
+		msg = append(msg, record...)
+		arcount++
+	}
+	binary.BigEndian.PutUint16(msg[2:], flags)
+	binary.BigEndian.PutUint16(msg[6:], ancount)
+	binary.BigEndian.PutUint16(msg[10:], arcount)
+	return msg
+}
+
+func appendResource(msg []byte, r resource, compressName bool) []byte {
+	if compressName {
+		// 0xC00C指向报文头之后的问题中的域名
+		msg = append(msg, 0xC0, headerLen)
+	} else {
+		msg = appendName(msg, r.Name)
+	}
+	msg = appendUint16(msg, r.Type)
+	msg = appendUint16(msg, classIN)
+	msg = appendUint32(msg, r.TTL)
+	msg = appendUint16(msg, uint16(len(r.Data)))
+	return append(msg, r.Data...)
+}
+
+func appendName(msg []byte, name string) []byte {
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		if label == "" {
+			continue
+		}
+		msg = append(msg, byte(len(label)))
+		msg = append(msg, label...)
+	}
+	return append(msg, 0)
+}
+
+func srvData(priority, weight, port uint16, target string) []byte {
+	data := appendUint16(nil, priority)
+	data = appendUint16(data, weight)
+	data = appendUint16(data, port)
+	return appendName(data, target)
+}
+
+func appendUint16(msg []byte, v uint16) []byte {
+	return append(msg, byte(v>>8), byte(v))
+}
+
+func appendUint32(msg []byte, v uint32) []byte {
+	return append(msg, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
@@ -0,0 +1,321 @@
+package dns
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"io"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	Default_Addr   = "127.0.0.1:8053"
+	Default_Domain = "nacos"
+	// 记录的默认TTL，秒
+	Default_TTL = 5
+	// TCP连接的空闲超时
+	tcpIdleTimeout = 10 * time.Second
+)
+
+// 基于服务发现缓存的本地DNS服务，使同一主机上的非Go进程无需改造即可通过DNS发现服务
+// 域名格式为service-name.group.namespace.nacos，group和namespace可省略，服务名本身不能包含"."
+// A记录返回健康实例的IP，SRV记录返回健康实例的端口和权重，SRV的目标为a-b-c-d.<查询的域名>形式的主机名，其A记录即实例IP
+// 服务没有健康实例时返回NXDOMAIN，获取实例失败时返回SERVFAIL
+type Server struct {
+	// 监听的UDP和TCP地址，为空时使用Default_Addr
+	Addr string
+	// 域名后缀，为空时使用Default_Domain
+	Domain string
+	// 返回指定命名空间的服务发现客户端，可直接使用MultiTenantClient.NamingClient
+	NamingClient func(namespaceId string) (naming_client.INamingClient, error)
+	Clusters     []string
+	// 为0时使用Default_TTL
+	TTL uint32
+
+	mutex  sync.Mutex
+	udp    net.PacketConn
+	tcp    net.Listener
+	conns  map[net.Conn]struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// 只解析client所在命名空间的服务
+func NewServer(client naming_client.INamingClient) *Server {
+	return &Server{
+		NamingClient: func(string) (naming_client.INamingClient, error) {
+			return client, nil
+		},
+	}
+}
+
+// 监听Addr并在后台处理请求，TCP与UDP使用同一端口
+func (s *Server) Start() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.udp != nil || s.closed {
+		return errors.New("[dns] server already started or closed")
+	}
+	addr := s.Addr
+	if addr == "" {
+		addr = Default_Addr
+	}
+	udp, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return err
+	}
+	tcp, err := net.Listen("tcp", udp.LocalAddr().String())
+	if err != nil {
+		udp.Close()
+		return err
+	}
+	s.udp, s.tcp = udp, tcp
+	s.conns = map[net.Conn]struct{}{}
+	s.wg.Add(2)
+	go s.serveUdp(udp)
+	go s.serveTcp(tcp)
+	logger.Infof("[dns] server listening on %s", udp.LocalAddr().String())
+	return nil
+}
+
+// 实际监听的地址，未启动时返回nil
+func (s *Server) LocalAddr() net.Addr {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.udp == nil {
+		return nil
+	}
+	return s.udp.LocalAddr()
+}
+
+// 停止监听，关闭所有TCP连接并等待处理中的请求结束
+func (s *Server) Close() error {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return nil
+	}
+	s.closed = true
+	if s.udp != nil {
+		s.udp.Close()
+		s.tcp.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mutex.Unlock()
+	s.wg.Wait()
+	return nil
+}
+
+func (s *Server) serveUdp(conn net.PacketConn) {
+	defer s.wg.Done()
+	buf := make([]byte, 65535)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if s.isClosed() {
+				return
+			}
+			logger.Warnf("[dns] read udp failed, err:%s", err.Error())
+			continue
+		}
+		if response := s.handle(buf[:n], maxUdpLen); response != nil {
+			conn.WriteTo(response, addr)
+		}
+	}
+}
+
+func (s *Server) serveTcp(listener net.Listener) {
+	defer s.wg.Done()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return
+			}
+			logger.Warnf("[dns] accept tcp failed, err:%s", err.Error())
+			continue
+		}
+		s.mutex.Lock()
+		if s.closed {
+			s.mutex.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mutex.Unlock()
+		go s.serveTcpConn(conn)
+	}
+}
+
+// TCP报文以2字节的长度开头，一个连接上可以有多个请求
+func (s *Server) serveTcpConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mutex.Lock()
+		delete(s.conns, conn)
+		s.mutex.Unlock()
+		conn.Close()
+	}()
+	for {
+		conn.SetDeadline(time.Now().Add(tcpIdleTimeout))
+		var length [2]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return
+		}
+		query := make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, query); err != nil {
+			return
+		}
+		response := s.handle(query, 0)
+		if response == nil {
+			return
+		}
+		if _, err := conn.Write(append(appendUint16(nil, uint16(len(response))), response...)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) isClosed() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.closed
+}
+
+// 处理一个请求，maxLen为0时不限制响应长度，请求无法解析时返回nil
+func (s *Server) handle(query []byte, maxLen int) []byte {
+	id, flags, q, err := parseQuery(query)
+	if err != nil {
+		if len(query) < headerLen {
+			return nil
+		}
+		return buildResponse(id, flags, rcodeFormErr, nil, nil, nil, maxLen)
+	}
+	if flags&flagQR != 0 {
+		return nil
+	}
+	if flags&(0xF<<11) != 0 {
+		return buildResponse(id, flags, rcodeNotImp, &q, nil, nil, maxLen)
+	}
+	name, ok := s.trimDomain(q.Name)
+	if !ok || q.Class != classIN {
+		return buildResponse(id, flags, rcodeRefused, &q, nil, nil, maxLen)
+	}
+	rcode, answers, additionals := s.resolve(q, name)
+	return buildResponse(id, flags, rcode, &q, answers, additionals, maxLen)
+}
+
+// 去掉域名后缀，域名比较不区分大小写
+func (s *Server) trimDomain(name string) (string, bool) {
+	domain := s.Domain
+	if domain == "" {
+		domain = Default_Domain
+	}
+	suffix := "." + strings.Trim(domain, ".")
+	if len(name) <= len(suffix) || !strings.EqualFold(name[len(name)-len(suffix):], suffix) {
+		return "", false
+	}
+	return name[:len(name)-len(suffix)], true
+}
+
+func (s *Server) resolve(q question, name string) (rcode int, answers, additionals []resource) {
+	ttl := s.TTL
+	if ttl == 0 {
+		ttl = Default_TTL
+	}
+	// SRV目标主机名的A记录
+	if label := strings.SplitN(name, ".", 2); len(label) == 2 {
+		if ip := parseIpLabel(label[0]); ip != nil {
+			if q.Type == typeA {
+				answers = append(answers, resource{Type: typeA, TTL: ttl, Data: ip})
+			}
+			return rcodeSuccess, answers, nil
+		}
+	}
+	serviceName, groupName, namespaceId := parseName(name)
+	client, err := s.NamingClient(namespaceId)
+	if err != nil {
+		logger.Warnf("[dns] get naming client of namespace %s failed, err:%s", namespaceId, err.Error())
+		return rcodeServFail, nil, nil
+	}
+	instances, err := client.SelectInstances(vo.SelectInstancesParam{
+		ServiceName: serviceName,
+		GroupName:   groupName,
+		Clusters:    s.Clusters,
+		HealthyOnly: true,
+	})
+	if err != nil {
+		logger.Warnf("[dns] select instances of %s@@%s failed, err:%s", groupName, serviceName, err.Error())
+		return rcodeServFail, nil, nil
+	}
+	if len(instances) == 0 {
+		return rcodeNXDomain, nil, nil
+	}
+	seen := map[string]bool{}
+	for _, instance := range instances {
+		ip := net.ParseIP(instance.Ip).To4()
+		if ip == nil {
+			continue
+		}
+		switch q.Type {
+		case typeA:
+			if !seen[instance.Ip] {
+				seen[instance.Ip] = true
+				answers = append(answers, resource{Type: typeA, TTL: ttl, Data: ip})
+			}
+		case typeSRV:
+			target := strings.Replace(instance.Ip, ".", "-", -1) + "." + q.Name
+			answers = append(answers, resource{Type: typeSRV, TTL: ttl,
+				Data: srvData(0, srvWeight(instance.Weight), uint16(instance.Port), target)})
+			if !seen[instance.Ip] {
+				seen[instance.Ip] = true
+				additionals = append(additionals, resource{Name: target, Type: typeA, TTL: ttl, Data: ip})
+			}
+		}
+	}
+	return rcodeSuccess, answers, additionals
+}
+
+// 解析域名中的服务名、分组和命名空间，与resolver.Transport的主机名格式一致
+func parseName(name string) (serviceName, groupName, namespaceId string) {
+	parts := strings.SplitN(name, ".", 3)
+	serviceName = parts[0]
+	groupName = constant.DEFAULT_GROUP
+	if len(parts) > 1 {
+		groupName = parts[1]
+	}
+	if len(parts) > 2 {
+		namespaceId = parts[2]
+	}
+	return
+}
+
+// 解析a-b-c-d形式的IPv4地址
+func parseIpLabel(label string) net.IP {
+	if strings.Count(label, "-") != 3 {
+		return nil
+	}
+	return net.ParseIP(strings.Replace(label, "-", ".", -1)).To4()
+}
+
+// SRV的权重为16位整数，实例权重放大100倍以保留两位小数
+func srvWeight(weight float64) uint16 {
+	w := math.Round(weight * 100)
+	if w < 1 {
+		return 1
+	}
+	if w > math.MaxUint16 {
+		return math.MaxUint16
+	}
+	return uint16(w)
+}
@@ -0,0 +1,141 @@
+package dns
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/mock"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+)
+
+var instancesTest = []model.Instance{
+	{Ip: "10.0.0.1", Port: 8080, Weight: 1, Healthy: true, Enable: true},
+	{Ip: "10.0.0.1", Port: 8081, Weight: 0.5, Healthy: true, Enable: true},
+	{Ip: "10.0.0.2", Port: 8080, Weight: 2, Healthy: true, Enable: true},
+}
+
+func buildQueryTest(id uint16, name string, qtype uint16) []byte {
+	msg := make([]byte, headerLen)
+	binary.BigEndian.PutUint16(msg[0:], id)
+	binary.BigEndian.PutUint16(msg[2:], flagRD)
+	binary.BigEndian.PutUint16(msg[4:], 1)
+	msg = appendName(msg, name)
+	msg = appendUint16(msg, qtype)
+	return appendUint16(msg, classIN)
+}
+
+func parseResponseTest(t *testing.T, msg []byte) (rcode int, ancount, arcount uint16, truncated bool) {
+	assert.True(t, len(msg) >= headerLen)
+	flags := binary.BigEndian.Uint16(msg[2:])
+	assert.True(t, flags&flagQR != 0)
+	return int(flags & 0xF), binary.BigEndian.Uint16(msg[6:]), binary.BigEndian.Uint16(msg[10:]), flags&flagTC != 0
+}
+
+func newServerTest(t *testing.T, ctrl *gomock.Controller) (*Server, *mock.MockINamingClient) {
+	client := mock.NewMockINamingClient(ctrl)
+	return NewServer(client), client
+}
+
+func TestServer_Handle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	server, client := newServerTest(t, ctrl)
+	client.EXPECT().SelectInstances(vo.SelectInstancesParam{ServiceName: "demo", GroupName: "group", HealthyOnly: true}).
+		Return(instancesTest, nil).Times(2)
+	client.EXPECT().SelectInstances(vo.SelectInstancesParam{ServiceName: "empty", GroupName: "DEFAULT_GROUP", HealthyOnly: true}).
+		Return([]model.Instance{}, nil).Times(1)
+	client.EXPECT().SelectInstances(vo.SelectInstancesParam{ServiceName: "missing", GroupName: "DEFAULT_GROUP", HealthyOnly: true}).
+		Return([]model.Instance{}, errors.New("instance list is empty!")).Times(1)
+
+	// A记录按IP去重
+	rcode, ancount, _, _ := parseResponseTest(t, server.handle(buildQueryTest(1, "demo.group.NACOS", typeA), maxUdpLen))
+	assert.Equal(t, rcodeSuccess, rcode)
+	assert.Equal(t, uint16(2), ancount)
+
+	rcode, ancount, arcount, _ := parseResponseTest(t, server.handle(buildQueryTest(2, "demo.group.nacos", typeSRV), maxUdpLen))
+	assert.Equal(t, rcodeSuccess, rcode)
+	assert.Equal(t, uint16(3), ancount)
+	assert.Equal(t, uint16(2), arcount)
+
+	rcode, _, _, _ = parseResponseTest(t, server.handle(buildQueryTest(3, "empty.nacos", typeA), maxUdpLen))
+	assert.Equal(t, rcodeNXDomain, rcode)
+	rcode, _, _, _ = parseResponseTest(t, server.handle(buildQueryTest(4, "missing.nacos", typeA), maxUdpLen))
+	assert.Equal(t, rcodeServFail, rcode)
+	rcode, _, _, _ = parseResponseTest(t, server.handle(buildQueryTest(5, "demo.example.com", typeA), maxUdpLen))
+	assert.Equal(t, rcodeRefused, rcode)
+
+	// SRV目标主机名无需查询服务
+	rcode, ancount, _, _ = parseResponseTest(t, server.handle(buildQueryTest(6, "10-0-0-2.demo.group.nacos", typeA), maxUdpLen))
+	assert.Equal(t, rcodeSuccess, rcode)
+	assert.Equal(t, uint16(1), ancount)
+
+	rcode, _, _, _ = parseResponseTest(t, server.handle(append(buildQueryTest(7, "", typeA)[:headerLen], 0xC0), maxUdpLen))
+	assert.Equal(t, rcodeFormErr, rcode)
+	assert.Nil(t, server.handle([]byte{0, 1}, maxUdpLen))
+}
+
+func TestServer_Truncate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	server, client := newServerTest(t, ctrl)
+	var instances []model.Instance
+	for i := 0; i < 50; i++ {
+		instances = append(instances, model.Instance{Ip: net.IPv4(10, 0, 1, byte(i)).String(), Port: 80, Weight: 1, Healthy: true, Enable: true})
+	}
+	client.EXPECT().SelectInstances(gomock.Any()).Return(instances, nil).Times(2)
+
+	response := server.handle(buildQueryTest(1, "demo.nacos", typeSRV), maxUdpLen)
+	assert.True(t, len(response) <= maxUdpLen)
+	_, ancount, _, truncated := parseResponseTest(t, response)
+	assert.True(t, truncated)
+	assert.True(t, ancount < 50)
+
+	_, ancount, arcount, truncated := parseResponseTest(t, server.handle(buildQueryTest(2, "demo.nacos", typeSRV), 0))
+	assert.False(t, truncated)
+	assert.Equal(t, uint16(50), ancount)
+	assert.Equal(t, uint16(50), arcount)
+}
+
+func TestServer_Lookup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock.NewMockINamingClient(ctrl)
+	client.EXPECT().SelectInstances(gomock.Any()).Return(instancesTest, nil).AnyTimes()
+	server := &Server{
+		Addr: "127.0.0.1:0",
+		NamingClient: func(namespaceId string) (naming_client.INamingClient, error) {
+			assert.Equal(t, "dev", namespaceId)
+			return client, nil
+		},
+	}
+	assert.Nil(t, server.Start())
+	defer server.Close()
+
+	for _, network := range []string{"udp", "tcp"} {
+		resolver := &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, network, server.LocalAddr().String())
+			},
+		}
+		addrs, err := resolver.LookupHost(context.Background(), "demo.group.dev.nacos.")
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"10.0.0.1", "10.0.0.2"}, addrs)
+
+		_, srvs, err := resolver.LookupSRV(context.Background(), "", "", "demo.group.dev.nacos.")
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(srvs))
+		for _, srv := range srvs {
+			if srv.Target == "10-0-0-2.demo.group.dev.nacos." {
+				assert.Equal(t, uint16(200), srv.Weight)
+			}
+		}
+	}
+	assert.Nil(t, server.Close())
+}
@@ -0,0 +1,235 @@
+package mock_test
+
+import (
+	"errors"
+	"github.com/nacos-group/nacos-sdk-go/clients/config_client"
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/common/nacos_error"
+	"github.com/nacos-group/nacos-sdk-go/common/util"
+	"github.com/nacos-group/nacos-sdk-go/mock"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var _ naming_client.INamingClient = (*mock.FakeNamingClient)(nil)
+var _ config_client.IConfigClient = (*mock.FakeConfigClient)(nil)
+
+func TestFakeNamingClient(t *testing.T) {
+	client := mock.NewFakeNamingClient()
+	var received [][]model.SubscribeService
+	var events []model.InstanceChangeEvent
+	param := &vo.SubscribeParam{
+		ServiceName: "DEMO",
+		SubscribeCallback: func(services []model.SubscribeService, err error) {
+			received = append(received, services)
+		},
+		ChangeCallback: func(event model.InstanceChangeEvent) {
+			events = append(events, event)
+		},
+	}
+	assert.Nil(t, client.Subscribe(param))
+	assert.Equal(t, 0, len(received), "no callback before any instance is registered")
+
+	_, err := client.RegisterInstance(vo.RegisterInstanceParam{ServiceName: "DEMO", Ip: "10.0.0.10", Port: 80, Weight: 1, Enable: true, Healthy: true,
+		Metadata: map[string]string{"env": "prod"}})
+	assert.Nil(t, err)
+	_, err = client.RegisterInstance(vo.RegisterInstanceParam{ServiceName: "DEMO", Ip: "10.0.0.11", Port: 80, Weight: 1, Enable: true, Healthy: false})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(received))
+	assert.Equal(t, 2, len(received[1]))
+	assert.Equal(t, "DEFAULT_GROUP@@DEMO", received[1][0].ServiceName)
+	assert.Equal(t, mock.Fake_Default_Cluster, received[1][0].ClusterName)
+	assert.Equal(t, 1, len(events[1].Added))
+
+	instances, err := client.SelectInstances(vo.SelectInstancesParam{ServiceName: "DEMO", HealthyOnly: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(instances))
+	instances, _ = client.SelectAllInstances(vo.SelectAllInstancesParam{ServiceName: "DEMO", Selector: "CONSUMER.label.env=prod"})
+	assert.Equal(t, 1, len(instances))
+	instance, err := client.SelectOneHealthyInstance(vo.SelectOneHealthInstanceParam{ServiceName: "DEMO"})
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.0.10", instance.Ip)
+
+	_, err = client.UpdateInstance(vo.UpdateInstanceParam{ServiceName: "DEMO", Ip: "10.0.0.11", Port: 80, Weight: 2, Enable: true, Healthy: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(events[2].Modified))
+	_, err = client.UpdateInstance(vo.UpdateInstanceParam{ServiceName: "DEMO", Ip: "10.0.0.12", Port: 80})
+	assert.NotNil(t, err)
+
+	assert.Equal(t, []model.SubscribedService{{ServiceName: "DEFAULT_GROUP@@DEMO", Subscribers: 1}}, client.GetSubscribedServices())
+	list, _ := client.GetAllServicesInfo(vo.GetAllServiceInfoParam{})
+	assert.Equal(t, []string{"DEMO"}, list.Doms)
+	_, err = client.DeleteService(vo.DeleteServiceParam{ServiceName: "DEMO"})
+	assert.NotNil(t, err, "service with instances can not be deleted")
+
+	_, err = client.DeregisterInstance(vo.DeregisterInstanceParam{ServiceName: "DEMO", Ip: "10.0.0.11", Port: 80})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(events[3].Removed))
+	assert.Nil(t, client.Unsubscribe(param))
+	_, err = client.DeregisterInstance(vo.DeregisterInstanceParam{ServiceName: "DEMO", Ip: "10.0.0.10", Port: 80})
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(events), "unsubscribed callback should not be called")
+	_, err = client.SelectAllInstances(vo.SelectAllInstancesParam{ServiceName: "DEMO"})
+	assert.NotNil(t, err)
+}
+
+func TestFakeNamingClient_WatchAllServices(t *testing.T) {
+	client := mock.NewFakeNamingClient()
+	_, err := client.CreateService(vo.CreateServiceParam{ServiceName: "order"})
+	assert.Nil(t, err)
+	var events []model.ServiceListChangeEvent
+	assert.Nil(t, client.WatchAllServices(vo.WatchServicesParam{Callback: func(event model.ServiceListChangeEvent) {
+		events = append(events, event)
+	}}))
+	assert.Equal(t, []string{"order"}, events[0].Added)
+
+	_, err = client.RegisterInstance(vo.RegisterInstanceParam{ServiceName: "user", Ip: "10.0.0.10", Port: 80, Weight: 1, Enable: true, Healthy: true})
+	assert.Nil(t, err)
+	_, err = client.DeleteService(vo.DeleteServiceParam{ServiceName: "order"})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(events))
+	assert.Equal(t, []string{"user"}, events[1].Added)
+	assert.Equal(t, []string{"order"}, events[2].Removed)
+}
+
+func TestFakeNamingClient_DrainInstance(t *testing.T) {
+	client := mock.NewFakeNamingClient()
+	var weights []float64
+	assert.Nil(t, client.Subscribe(&vo.SubscribeParam{
+		ServiceName: "DEMO",
+		ChangeCallback: func(event model.InstanceChangeEvent) {
+			for _, instance := range event.Modified {
+				weights = append(weights, instance.Weight)
+			}
+		},
+	}))
+	_, err := client.RegisterInstance(vo.RegisterInstanceParam{ServiceName: "DEMO", Ip: "10.0.0.10", Port: 80, Weight: 4, Enable: true, Healthy: true})
+	assert.Nil(t, err)
+	_, err = client.DrainInstance(vo.DrainInstanceParam{ServiceName: "DEMO", Ip: "10.0.0.10", Port: 80, Steps: 2}, time.Minute)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{2, 0, 0}, weights)
+	instances, _ := client.SelectAllInstances(vo.SelectAllInstancesParam{ServiceName: "DEMO"})
+	assert.False(t, instances[0].Enable)
+	_, err = client.DrainInstance(vo.DrainInstanceParam{ServiceName: "DEMO", Ip: "10.0.0.11", Port: 80}, time.Minute)
+	assert.NotNil(t, err)
+}
+
+func TestFakeConfigClient(t *testing.T) {
+	client := mock.NewFakeConfigClient("public")
+	_, err := client.GetConfig(vo.ConfigParam{DataId: "app.json", Group: "DEFAULT_GROUP"})
+	assert.True(t, errors.Is(err, nacos_error.ErrNotFound))
+
+	var received []string
+	assert.Nil(t, client.ListenConfigWithPrefix(vo.ConfigPrefixParam{Group: "DEFAULT_GROUP", DataIdPrefix: "app", OnChange: func(namespace, group, dataId, data string) {
+		received = append(received, dataId+"="+data)
+	}}))
+	_, err = client.PublishConfig(vo.ConfigParam{DataId: "app.json", Group: "DEFAULT_GROUP", Content: `{"port":80}`})
+	assert.Nil(t, err)
+	var value struct {
+		Port int `json:"port"`
+	}
+	assert.Nil(t, client.GetConfigAs(vo.ConfigParam{DataId: "app.json", Group: "DEFAULT_GROUP"}, &value))
+	assert.Equal(t, 80, value.Port)
+
+	_, err = client.PublishConfigCas(vo.ConfigParam{DataId: "app.json", Group: "DEFAULT_GROUP", Content: `{"port":81}`, CasMd5: "other"})
+	assert.True(t, errors.Is(err, config_client.ErrConfigCasConflict))
+	_, err = client.PublishConfigCas(vo.ConfigParam{DataId: "app.json", Group: "DEFAULT_GROUP", Content: `{"port":81}`, CasMd5: util.Md5(`{"port":80}`)})
+	assert.Nil(t, err)
+
+	var replayed string
+	assert.Nil(t, client.ListenConfig(vo.ConfigParam{DataId: "app.json", Group: "DEFAULT_GROUP", OnChange: func(namespace, group, dataId, data string) {
+		replayed = data
+	}}))
+	assert.Equal(t, `{"port":81}`, replayed, "the current value should be replayed on listen")
+
+	_, err = client.DeleteConfig(vo.ConfigParam{DataId: "app.json", Group: "DEFAULT_GROUP"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{`app.json={"port":80}`, `app.json={"port":81}`, "app.json="}, received)
+
+	page, err := client.GetConfigHistory(vo.ConfigHistoryParam{DataId: "app.json", Group: "DEFAULT_GROUP"})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, page.TotalCount)
+	assert.Equal(t, "D", page.PageItems[0].OpType)
+	_, err = client.RollbackConfig(vo.ConfigHistoryDetailParam{Id: page.PageItems[0].Id, DataId: "app.json", Group: "DEFAULT_GROUP"})
+	assert.Nil(t, err)
+	content, _ := client.GetConfig(vo.ConfigParam{DataId: "app.json", Group: "DEFAULT_GROUP"})
+	assert.Equal(t, `{"port":81}`, content)
+	assert.Equal(t, 4, len(received), "the prefix listener should not be registered twice")
+}
+
+func TestFakeConfigClient_ListenConfigChangeEvent(t *testing.T) {
+	client := mock.NewFakeConfigClient("dev")
+	var events []model.ConfigChangeEvent
+	assert.Nil(t, client.ListenConfig(vo.ConfigParam{DataId: "app.json", Group: "DEFAULT_GROUP", OnChangeEvent: func(event model.ConfigChangeEvent) {
+		events = append(events, event)
+	}}))
+	for _, content := range []string{"v1", "v2"} {
+		_, err := client.PublishConfig(vo.ConfigParam{DataId: "app.json", Group: "DEFAULT_GROUP", Content: content})
+		assert.Nil(t, err)
+	}
+	_, err := client.DeleteConfig(vo.ConfigParam{DataId: "app.json", Group: "DEFAULT_GROUP"})
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(events))
+	assert.Equal(t, model.Config_Change_Added, events[0].ChangeType)
+	assert.Equal(t, "v1", events[0].NewContent)
+	assert.Equal(t, model.ConfigChangeEvent{Namespace: "dev", Group: "DEFAULT_GROUP", DataId: "app.json", OldContent: "v1", NewContent: "v2",
+		OldMd5: util.Md5("v1"), NewMd5: util.Md5("v2"), ChangeType: model.Config_Change_Modified}, events[1])
+	assert.Equal(t, model.Config_Change_Deleted, events[2].ChangeType)
+	assert.Equal(t, "v2", events[2].OldContent)
+}
+
+func TestFakeConfigClient_ListenConfigGroup(t *testing.T) {
+	client := mock.NewFakeConfigClient("public")
+	_, err := client.PublishConfig(vo.ConfigParam{DataId: "app.json", Group: "APP_GROUP", Content: "v1"})
+	assert.Nil(t, err)
+	assert.NotNil(t, client.ListenConfigGroup(vo.ConfigGroupParam{Group: "APP_GROUP"}))
+
+	var received []string
+	assert.Nil(t, client.ListenConfigGroup(vo.ConfigGroupParam{Group: "APP_GROUP", OnChangeEvent: func(event model.ConfigChangeEvent) {
+		received = append(received, event.ChangeType+":"+event.DataId+"="+event.NewContent)
+	}}))
+	_, err = client.PublishConfig(vo.ConfigParam{DataId: "db.yaml", Group: "APP_GROUP", Content: "v1"})
+	assert.Nil(t, err)
+	_, err = client.PublishConfig(vo.ConfigParam{DataId: "db.yaml", Group: "OTHER_GROUP", Content: "v1"})
+	assert.Nil(t, err)
+	_, err = client.PublishConfig(vo.ConfigParam{DataId: "app.json", Group: "APP_GROUP", Content: "v2"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"ADDED:app.json=v1", "ADDED:db.yaml=v1", "MODIFIED:app.json=v2"}, received)
+}
+
+func TestFakeNamingClient_DeregisterAllInstances(t *testing.T) {
+	client := mock.NewFakeNamingClient()
+	_, _ = client.RegisterInstance(vo.RegisterInstanceParam{ServiceName: "DEMO", Ip: "10.0.0.10", Port: 80, Ephemeral: true})
+	_, _ = client.RegisterInstance(vo.RegisterInstanceParam{ServiceName: "DEMO", Ip: "10.0.0.11", Port: 80})
+
+	assert.Nil(t, client.DeregisterAllInstances())
+	instances, err := client.SelectAllInstances(vo.SelectAllInstancesParam{ServiceName: "DEMO"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(instances))
+	assert.Equal(t, "10.0.0.11", instances[0].Ip)
+}
+
+func TestFakeNamingClient_PauseHeartbeat(t *testing.T) {
+	client := mock.NewFakeNamingClient()
+	_, err := client.RegisterInstance(vo.RegisterInstanceParam{ServiceName: "order", Ip: "10.0.0.10", Port: 80, Weight: 1, Enable: true, Healthy: true, Ephemeral: true})
+	assert.Nil(t, err)
+	_, err = client.RegisterInstance(vo.RegisterInstanceParam{ServiceName: "order", Ip: "10.0.0.11", Port: 80, Weight: 1, Enable: true, Healthy: true})
+	assert.Nil(t, err)
+
+	_, err = client.PauseHeartbeat(vo.HeartbeatParam{ServiceName: "order", Ip: "10.0.0.11", Port: 80})
+	assert.NotNil(t, err, "persistent instance has no heartbeat")
+	_, err = client.PauseHeartbeat(vo.HeartbeatParam{ServiceName: "order", Ip: "10.0.0.10", Port: 80})
+	assert.Nil(t, err)
+	instances, _ := client.SelectInstances(vo.SelectInstancesParam{ServiceName: "order", HealthyOnly: true})
+	assert.Equal(t, 1, len(instances))
+	assert.Equal(t, "10.0.0.11", instances[0].Ip)
+
+	_, err = client.ResumeHeartbeat(vo.HeartbeatParam{ServiceName: "order", Ip: "10.0.0.10", Port: 80})
+	assert.Nil(t, err)
+	instances, _ = client.SelectInstances(vo.SelectInstancesParam{ServiceName: "order", HealthyOnly: true})
+	assert.Equal(t, 2, len(instances))
+}
@@ -0,0 +1,589 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"github.com/nacos-group/nacos-sdk-go/common/codec"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/nacos_error"
+	"github.com/nacos-group/nacos-sdk-go/common/util"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 内存实现不支持导入导出
+var ErrFakeNotSupported = errors.New("not supported by fake client")
+
+type fakeConfigListener struct {
+	onChange      func(namespace, group, dataId, data string)
+	onChangeEvent func(event model.ConfigChangeEvent)
+	validate      func(content string) error
+}
+
+// dataIds记录已注册监听的dataId，DataIdPrefix为空时为分组监听
+type fakePrefixListener struct {
+	param         vo.ConfigPrefixParam
+	onChangeEvent func(event model.ConfigChangeEvent)
+	dataIds       map[string]bool
+}
+
+// 内存中的IConfigClient实现，下游项目无需启动nacos服务端或手写mock即可做单元测试
+// 发布和删除配置后同步回调监听，监听时配置已存在则立即回调一次当前内容，与真实客户端首次长轮询的行为一致；
+// 每次发布和删除都会记录历史，可用于GetConfigHistory和RollbackConfig
+type FakeConfigClient struct {
+	mutex           sync.Mutex
+	namespace       string
+	configs         map[string]model.ConfigItem
+	betas           map[string]string
+	listeners       map[string][]fakeConfigListener
+	prefixListeners []*fakePrefixListener
+	histories       []model.ConfigHistory
+}
+
+// namespace为回调中的命名空间
+func NewFakeConfigClient(namespace string) *FakeConfigClient {
+	return &FakeConfigClient{
+		namespace: namespace,
+		configs:   map[string]model.ConfigItem{},
+		betas:     map[string]string{},
+		listeners: map[string][]fakeConfigListener{},
+	}
+}
+
+func fakeConfigKey(dataId, group string) string {
+	return dataId + constant.CONFIG_INFO_SPLITER + group
+}
+
+func checkFakeConfigParam(method string, param vo.ConfigParam) error {
+	if len(param.DataId) <= 0 {
+		return errors.New("[client." + method + "] param.dataId can not be empty")
+	}
+	if len(param.Group) <= 0 {
+		return errors.New("[client." + method + "] param.group can not be empty")
+	}
+	return nil
+}
+
+func (c *FakeConfigClient) GetConfig(param vo.ConfigParam) (string, error) {
+	return c.GetConfigWithContext(context.Background(), param)
+}
+
+// 配置不存在时与真实客户端一样返回404错误，可通过errors.Is(err, nacos_error.ErrNotFound)判断
+func (c *FakeConfigClient) GetConfigWithContext(ctx context.Context, param vo.ConfigParam) (string, error) {
+	if err := checkFakeConfigParam("GetConfig", param); err != nil {
+		return "", err
+	}
+	key := fakeConfigKey(param.DataId, param.Group)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if param.Beta {
+		if content, ok := c.betas[key]; ok {
+			return content, nil
+		}
+		return "", nacos_error.NewNacosError(strconv.Itoa(http.StatusNotFound), "[client.GetConfig] beta config not found", nil)
+	}
+	if item, ok := c.configs[key]; ok {
+		return item.Content, nil
+	}
+	return "", nacos_error.NewNacosError(strconv.Itoa(http.StatusNotFound), "config not found", nil)
+}
+
+func (c *FakeConfigClient) GetConfigAs(param vo.ConfigParam, v interface{}) error {
+	return c.GetConfigAsWithContext(context.Background(), param, v)
+}
+
+func (c *FakeConfigClient) GetConfigAsWithContext(ctx context.Context, param vo.ConfigParam, v interface{}) error {
+	decoder, err := fakeCodec(param)
+	if err != nil {
+		return err
+	}
+	content, err := c.GetConfigWithContext(ctx, param)
+	if err != nil {
+		return err
+	}
+	return decoder.Unmarshal([]byte(content), v)
+}
+
+func fakeCodec(param vo.ConfigParam) (codec.Codec, error) {
+	configType := param.Type
+	if configType == "" {
+		configType = codec.DetectType(param.DataId)
+	}
+	if configType == "" {
+		return nil, errors.New("[client.GetConfigAs] can not detect config type of dataId:" + param.DataId + ", please set param.Type")
+	}
+	decoder, ok := codec.Get(configType)
+	if !ok {
+		return nil, errors.New("[client.GetConfigAs] no codec registered for config type:" + configType)
+	}
+	return decoder, nil
+}
+
+func (c *FakeConfigClient) PublishConfig(param vo.ConfigParam) (bool, error) {
+	return c.PublishConfigWithContext(context.Background(), param)
+}
+
+// BetaIps不为空时只保存为beta配置，不回调监听
+func (c *FakeConfigClient) PublishConfigWithContext(ctx context.Context, param vo.ConfigParam) (bool, error) {
+	if err := checkFakeConfigParam("PublishConfig", param); err != nil {
+		return false, err
+	}
+	if len(param.Content) <= 0 {
+		return false, errors.New("[client.PublishConfig] param.content can not be empty")
+	}
+	if param.BetaIps != "" {
+		c.mutex.Lock()
+		c.betas[fakeConfigKey(param.DataId, param.Group)] = param.Content
+		c.mutex.Unlock()
+		return true, nil
+	}
+	c.publish(param, "")
+	return true, nil
+}
+
+// casMd5不为空时仅当当前内容的md5相同时发布
+// 与真实客户端一样在后台执行，结果写入有缓冲的channel
+func (c *FakeConfigClient) GetConfigAsync(ctx context.Context, param vo.ConfigParam) <-chan model.ConfigResult {
+	result := make(chan model.ConfigResult, 1)
+	go func() {
+		content, err := c.GetConfigWithContext(ctx, param)
+		result <- model.ConfigResult{Content: content, Err: err}
+	}()
+	return result
+}
+
+func (c *FakeConfigClient) PublishConfigAsync(ctx context.Context, param vo.ConfigParam) <-chan model.AsyncResult {
+	result := make(chan model.AsyncResult, 1)
+	go func() {
+		success, err := c.PublishConfigWithContext(ctx, param)
+		result <- model.AsyncResult{Success: success, Err: err}
+	}()
+	return result
+}
+
+func (c *FakeConfigClient) publish(param vo.ConfigParam, casMd5 string) bool {
+	key := fakeConfigKey(param.DataId, param.Group)
+	c.mutex.Lock()
+	old, exists := c.configs[key]
+	if casMd5 != "" && (!exists || old.Md5 != casMd5) {
+		c.mutex.Unlock()
+		return false
+	}
+	item := model.ConfigItem{
+		DataId:  param.DataId,
+		Group:   param.Group,
+		Content: param.Content,
+		Md5:     util.Md5(param.Content),
+		Tenant:  c.namespace,
+		AppName: param.AppName,
+		Type:    param.Type,
+	}
+	c.configs[key] = item
+	if exists {
+		c.addHistory(old, "U")
+	} else {
+		c.addHistory(item, "I")
+	}
+	c.mutex.Unlock()
+	if !exists {
+		c.discoverPrefixListeners(param.DataId, param.Group)
+	}
+	if !exists || old.Md5 != item.Md5 {
+		changeEvent := model.ConfigChangeEvent{Namespace: c.namespace, Group: param.Group, DataId: param.DataId,
+			NewContent: item.Content, NewMd5: item.Md5, ChangeType: model.Config_Change_Added}
+		if exists {
+			changeEvent.OldContent, changeEvent.OldMd5, changeEvent.ChangeType = old.Content, old.Md5, model.Config_Change_Modified
+		}
+		c.notify(changeEvent)
+	}
+	return true
+}
+
+// 与服务端一致，新建时记录新内容，修改和删除时记录操作前的内容，调用方需持有锁
+func (c *FakeConfigClient) addHistory(item model.ConfigItem, opType string) {
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	c.histories = append(c.histories, model.ConfigHistory{
+		Id:               strconv.Itoa(len(c.histories) + 1),
+		DataId:           item.DataId,
+		Group:            item.Group,
+		Tenant:           c.namespace,
+		AppName:          item.AppName,
+		Md5:              item.Md5,
+		Content:          item.Content,
+		OpType:           opType,
+		CreatedTime:      now,
+		LastModifiedTime: now,
+	})
+}
+
+// 在锁外回调，回调中可以再调用客户端，未通过该监听的Validate校验时不回调，删除不经过校验
+func (c *FakeConfigClient) notify(changeEvent model.ConfigChangeEvent) {
+	c.mutex.Lock()
+	listeners := append([]fakeConfigListener(nil), c.listeners[fakeConfigKey(changeEvent.DataId, changeEvent.Group)]...)
+	c.mutex.Unlock()
+	deleted := changeEvent.ChangeType == model.Config_Change_Deleted
+	for _, listener := range listeners {
+		if !deleted && listener.validate != nil && listener.validate(changeEvent.NewContent) != nil {
+			continue
+		}
+		if listener.onChangeEvent != nil {
+			listener.onChangeEvent(changeEvent)
+		} else {
+			listener.onChange(c.namespace, changeEvent.Group, changeEvent.DataId, changeEvent.NewContent)
+		}
+	}
+}
+
+// 前缀监听在新配置发布后立即注册监听
+func (c *FakeConfigClient) discoverPrefixListeners(dataId, group string) {
+	c.mutex.Lock()
+	var matched []*fakePrefixListener
+	for _, listener := range c.prefixListeners {
+		if listener.param.Group == group && strings.HasPrefix(dataId, listener.param.DataIdPrefix) && !listener.dataIds[dataId] {
+			listener.dataIds[dataId] = true
+			matched = append(matched, listener)
+		}
+	}
+	c.mutex.Unlock()
+	for _, listener := range matched {
+		c.addListener(vo.ConfigParam{DataId: dataId, Group: group, OnChange: listener.param.OnChange, OnChangeEvent: listener.onChangeEvent}, false)
+	}
+}
+
+func (c *FakeConfigClient) PublishConfigCas(param vo.ConfigParam) (bool, error) {
+	return c.PublishConfigCasWithContext(context.Background(), param)
+}
+
+// 当前内容的md5不等于param.CasMd5时返回409错误，与config_client.ErrConfigCasConflict的错误码相同
+func (c *FakeConfigClient) PublishConfigCasWithContext(ctx context.Context, param vo.ConfigParam) (bool, error) {
+	if err := checkFakeConfigParam("PublishConfigCas", param); err != nil {
+		return false, err
+	}
+	if len(param.Content) <= 0 {
+		return false, errors.New("[client.PublishConfigCas] param.content can not be empty")
+	}
+	if len(param.CasMd5) <= 0 {
+		return false, errors.New("[client.PublishConfigCas] param.casMd5 can not be empty")
+	}
+	if !c.publish(param, param.CasMd5) {
+		return false, nacos_error.NewNacosError(strconv.Itoa(http.StatusConflict), "[client.PublishConfigCas] config has been modified by others", nil)
+	}
+	return true, nil
+}
+
+func (c *FakeConfigClient) StopBeta(param vo.ConfigParam) (bool, error) {
+	return c.StopBetaWithContext(context.Background(), param)
+}
+
+func (c *FakeConfigClient) StopBetaWithContext(ctx context.Context, param vo.ConfigParam) (bool, error) {
+	if err := checkFakeConfigParam("StopBeta", param); err != nil {
+		return false, err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.betas, fakeConfigKey(param.DataId, param.Group))
+	return true, nil
+}
+
+func (c *FakeConfigClient) DeleteConfig(param vo.ConfigParam) (bool, error) {
+	return c.DeleteConfigWithContext(context.Background(), param)
+}
+
+// 删除后以空内容回调OnChange，以DELETED事件回调OnChangeEvent
+func (c *FakeConfigClient) DeleteConfigWithContext(ctx context.Context, param vo.ConfigParam) (bool, error) {
+	if err := checkFakeConfigParam("DeleteConfig", param); err != nil {
+		return false, err
+	}
+	key := fakeConfigKey(param.DataId, param.Group)
+	c.mutex.Lock()
+	old, exists := c.configs[key]
+	if exists {
+		delete(c.configs, key)
+		c.addHistory(old, "D")
+	}
+	c.mutex.Unlock()
+	if exists {
+		c.notify(model.ConfigChangeEvent{Namespace: c.namespace, Group: param.Group, DataId: param.DataId,
+			OldContent: old.Content, OldMd5: old.Md5, ChangeType: model.Config_Change_Deleted})
+	}
+	return true, nil
+}
+
+func (c *FakeConfigClient) ListenConfig(param vo.ConfigParam) error {
+	return c.ListenConfigWithContext(context.Background(), param)
+}
+
+func (c *FakeConfigClient) ListenConfigWithContext(ctx context.Context, param vo.ConfigParam) error {
+	if len(param.DataId) <= 0 {
+		return errors.New("[client.ListenConfig] DataId can not be empty")
+	}
+	if len(param.Group) <= 0 {
+		return errors.New("[client.ListenConfig] Group can not be empty")
+	}
+	if param.OnChange == nil && param.OnChangeEvent == nil {
+		return errors.New("[client.ListenConfig] OnChange can not be nil")
+	}
+	c.addListener(param, true)
+	return nil
+}
+
+// replay为true时配置已存在则立即回调一次当前内容
+func (c *FakeConfigClient) addListener(param vo.ConfigParam, replay bool) {
+	key := fakeConfigKey(param.DataId, param.Group)
+	c.mutex.Lock()
+	c.listeners[key] = append(c.listeners[key], fakeConfigListener{onChange: param.OnChange, onChangeEvent: param.OnChangeEvent, validate: param.Validate})
+	item, exists := c.configs[key]
+	c.mutex.Unlock()
+	if !replay || !exists {
+		return
+	}
+	if param.OnChangeEvent != nil {
+		param.OnChangeEvent(model.ConfigChangeEvent{Namespace: c.namespace, Group: param.Group, DataId: param.DataId,
+			NewContent: item.Content, NewMd5: item.Md5, ChangeType: model.Config_Change_Added})
+	} else {
+		param.OnChange(c.namespace, param.Group, param.DataId, item.Content)
+	}
+}
+
+// 取消dataId和group的所有监听
+func (c *FakeConfigClient) CancelListenConfig(param vo.ConfigParam) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.listeners, fakeConfigKey(param.DataId, param.Group))
+	return nil
+}
+
+func (c *FakeConfigClient) ListenConfigAs(param vo.ConfigParam, newValue func() interface{},
+	onChange func(value interface{}, err error)) error {
+	if newValue == nil || onChange == nil {
+		return errors.New("[client.ListenConfigAs] newValue and onChange can not be nil")
+	}
+	decoder, err := fakeCodec(param)
+	if err != nil {
+		return err
+	}
+	param.OnChange = func(namespace, group, dataId, data string) {
+		value := newValue()
+		onChange(value, decoder.Unmarshal([]byte(data), value))
+	}
+	return c.ListenConfig(param)
+}
+
+func (c *FakeConfigClient) SearchConfig(param vo.SearchConfigParam) (*model.ConfigPage, error) {
+	return c.SearchConfigWithContext(context.Background(), param)
+}
+
+// Search为accurate时精确匹配DataId和Group，否则可使用*通配符，为空时不过滤
+func (c *FakeConfigClient) SearchConfigWithContext(ctx context.Context, param vo.SearchConfigParam) (*model.ConfigPage, error) {
+	match := func(pattern, value string) bool {
+		if pattern == "" {
+			return true
+		}
+		if param.Search == "accurate" {
+			return pattern == value
+		}
+		ok, _ := path.Match(pattern, value)
+		return ok
+	}
+	c.mutex.Lock()
+	var items []model.ConfigItem
+	for _, item := range c.configs {
+		if match(param.DataId, item.DataId) && match(param.Group, item.Group) && (param.AppName == "" || param.AppName == item.AppName) {
+			items = append(items, item)
+		}
+	}
+	c.mutex.Unlock()
+	sort.Slice(items, func(i, j int) bool {
+		return fakeConfigKey(items[i].DataId, items[i].Group) < fakeConfigKey(items[j].DataId, items[j].Group)
+	})
+	pageNo, pageSize, start, end := fakePage(param.PageNo, param.PageSize, len(items))
+	return &model.ConfigPage{
+		TotalCount:     len(items),
+		PageNumber:     pageNo,
+		PagesAvailable: (len(items) + pageSize - 1) / pageSize,
+		PageItems:      items[start:end],
+	}, nil
+}
+
+// 返回页码、页大小和当页在total中的范围
+func fakePage(pageNo, pageSize uint32, total int) (int, int, int, int) {
+	if pageNo == 0 {
+		pageNo = 1
+	}
+	if pageSize == 0 {
+		pageSize = 10
+	}
+	start := int(pageNo-1) * int(pageSize)
+	if start > total {
+		start = total
+	}
+	end := start + int(pageSize)
+	if end > total {
+		end = total
+	}
+	return int(pageNo), int(pageSize), start, end
+}
+
+func (c *FakeConfigClient) ListenConfigWithPrefix(param vo.ConfigPrefixParam) error {
+	return c.ListenConfigWithPrefixWithContext(context.Background(), param)
+}
+
+// 新发布的匹配配置会立即被监听，无需等待DiscoveryInterval
+func (c *FakeConfigClient) ListenConfigWithPrefixWithContext(ctx context.Context, param vo.ConfigPrefixParam) error {
+	if len(param.DataIdPrefix) <= 0 {
+		return errors.New("[client.ListenConfigWithPrefix] DataIdPrefix can not be empty")
+	}
+	if len(param.Group) <= 0 {
+		return errors.New("[client.ListenConfigWithPrefix] Group can not be empty")
+	}
+	if param.OnChange == nil {
+		return errors.New("[client.ListenConfigWithPrefix] OnChange can not be nil")
+	}
+	c.addPrefixListener(&fakePrefixListener{param: param, dataIds: map[string]bool{}})
+	return nil
+}
+
+func (c *FakeConfigClient) ListenConfigGroup(param vo.ConfigGroupParam) error {
+	return c.ListenConfigGroupWithContext(context.Background(), param)
+}
+
+// 新发布的配置会立即被监听，无需等待DiscoveryInterval
+func (c *FakeConfigClient) ListenConfigGroupWithContext(ctx context.Context, param vo.ConfigGroupParam) error {
+	if len(param.Group) <= 0 {
+		return errors.New("[client.ListenConfigGroup] Group can not be empty")
+	}
+	if param.OnChangeEvent == nil {
+		return errors.New("[client.ListenConfigGroup] OnChangeEvent can not be nil")
+	}
+	c.addPrefixListener(&fakePrefixListener{param: vo.ConfigPrefixParam{Group: param.Group}, onChangeEvent: param.OnChangeEvent, dataIds: map[string]bool{}})
+	return nil
+}
+
+// 为已存在的匹配配置注册监听并立即回调一次当前内容
+func (c *FakeConfigClient) addPrefixListener(listener *fakePrefixListener) {
+	c.mutex.Lock()
+	c.prefixListeners = append(c.prefixListeners, listener)
+	var dataIds []string
+	for _, item := range c.configs {
+		if item.Group == listener.param.Group && strings.HasPrefix(item.DataId, listener.param.DataIdPrefix) {
+			listener.dataIds[item.DataId] = true
+			dataIds = append(dataIds, item.DataId)
+		}
+	}
+	c.mutex.Unlock()
+	sort.Strings(dataIds)
+	for _, dataId := range dataIds {
+		c.addListener(vo.ConfigParam{DataId: dataId, Group: listener.param.Group, OnChange: listener.param.OnChange, OnChangeEvent: listener.onChangeEvent}, true)
+	}
+}
+
+func (c *FakeConfigClient) GetConfigHistory(param vo.ConfigHistoryParam) (*model.ConfigHistoryPage, error) {
+	return c.GetConfigHistoryWithContext(context.Background(), param)
+}
+
+// 按修改时间倒序
+func (c *FakeConfigClient) GetConfigHistoryWithContext(ctx context.Context, param vo.ConfigHistoryParam) (*model.ConfigHistoryPage, error) {
+	if len(param.DataId) <= 0 {
+		return nil, errors.New("[client.GetConfigHistory] param.dataId can not be empty")
+	}
+	if len(param.Group) <= 0 {
+		return nil, errors.New("[client.GetConfigHistory] param.group can not be empty")
+	}
+	histories := c.historiesOf(param.DataId, param.Group)
+	pageNo, pageSize, start, end := fakePage(param.PageNo, param.PageSize, len(histories))
+	return &model.ConfigHistoryPage{
+		TotalCount:     len(histories),
+		PageNumber:     pageNo,
+		PagesAvailable: (len(histories) + pageSize - 1) / pageSize,
+		PageItems:      histories[start:end],
+	}, nil
+}
+
+// 按修改时间倒序
+func (c *FakeConfigClient) historiesOf(dataId, group string) []model.ConfigHistory {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	var histories []model.ConfigHistory
+	for i := len(c.histories) - 1; i >= 0; i-- {
+		if c.histories[i].DataId == dataId && c.histories[i].Group == group {
+			histories = append(histories, c.histories[i])
+		}
+	}
+	return histories
+}
+
+func (c *FakeConfigClient) GetPreviousConfig(param vo.ConfigHistoryDetailParam) (*model.ConfigHistory, error) {
+	return c.GetPreviousConfigWithContext(context.Background(), param)
+}
+
+func (c *FakeConfigClient) GetPreviousConfigWithContext(ctx context.Context, param vo.ConfigHistoryDetailParam) (*model.ConfigHistory, error) {
+	histories := c.historiesOf(param.DataId, param.Group)
+	for i, history := range histories {
+		if history.Id == param.Id && i+1 < len(histories) {
+			previous := histories[i+1]
+			return &previous, nil
+		}
+	}
+	return nil, nacos_error.NewNacosError(strconv.Itoa(http.StatusNotFound), "[client.GetPreviousConfig] previous config history of <"+param.Id+"> not found", nil)
+}
+
+func (c *FakeConfigClient) RollbackConfig(param vo.ConfigHistoryDetailParam) (bool, error) {
+	return c.RollbackConfigWithContext(context.Background(), param)
+}
+
+// 新建操作的回滚会删除配置，修改和删除操作的回滚会重新发布操作前的内容
+func (c *FakeConfigClient) RollbackConfigWithContext(ctx context.Context, param vo.ConfigHistoryDetailParam) (bool, error) {
+	for _, history := range c.historiesOf(param.DataId, param.Group) {
+		if history.Id != param.Id {
+			continue
+		}
+		if history.OpType == "I" {
+			return c.DeleteConfigWithContext(ctx, vo.ConfigParam{DataId: param.DataId, Group: param.Group})
+		}
+		return c.PublishConfigWithContext(ctx, vo.ConfigParam{
+			DataId:  param.DataId,
+			Group:   param.Group,
+			Content: history.Content,
+			AppName: history.AppName,
+		})
+	}
+	return false, nacos_error.NewNacosError(strconv.Itoa(http.StatusNotFound), "[client.RollbackConfig] config history <"+param.Id+"> not found", nil)
+}
+
+func (c *FakeConfigClient) ExportConfigs(param vo.ExportConfigParam) ([]byte, error) {
+	return c.ExportConfigsWithContext(context.Background(), param)
+}
+
+func (c *FakeConfigClient) ExportConfigsWithContext(ctx context.Context, param vo.ExportConfigParam) ([]byte, error) {
+	return nil, ErrFakeNotSupported
+}
+
+func (c *FakeConfigClient) ImportConfigs(param vo.ImportConfigParam) (*model.ConfigImportResult, error) {
+	return c.ImportConfigsWithContext(context.Background(), param)
+}
+
+func (c *FakeConfigClient) ImportConfigsWithContext(ctx context.Context, param vo.ImportConfigParam) (*model.ConfigImportResult, error) {
+	return nil, ErrFakeNotSupported
+}
+
+func (c *FakeConfigClient) UpdateClientConfig(opts ...constant.ClientOption) error {
+	return nil
+}
+
+func (c *FakeConfigClient) UpdateServerConfig(serverConfigs []constant.ServerConfig) error {
+	if len(serverConfigs) == 0 {
+		return errors.New("[client.UpdateServerConfig] server configs can not be empty")
+	}
+	return nil
+}
+
+func (c *FakeConfigClient) Close() error {
+	return nil
+}
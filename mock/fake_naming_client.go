@@ -0,0 +1,942 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/event"
+	"github.com/nacos-group/nacos-sdk-go/common/load_balancer"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/utils"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 注册时未指定集群的实例所在的集群，与服务端一致
+const Fake_Default_Cluster = "DEFAULT"
+
+type fakeService struct {
+	meta  model.ServiceMeta
+	hosts []model.Instance
+	// 最后一次修改的时间，单位毫秒
+	updateTime uint64
+}
+
+type fakeSubscriber struct {
+	param       *vo.SubscribeParam
+	serviceName string
+	hosts       []model.Instance
+}
+
+// 内存中的INamingClient实现，下游项目无需启动nacos服务端或手写mock即可做单元测试
+// 注册、注销和修改实例后同步回调订阅者，与真实客户端收到服务端推送时的回调一致；
+// 实例无需心跳，注册后一直存在直到注销。只有一个命名空间，参数中的NameSpace和Tenant不生效
+type FakeNamingClient struct {
+	mutex       sync.Mutex
+	services    map[string]*fakeService
+	subscribers []*fakeSubscriber
+	balancers   map[string]load_balancer.LoadBalancer
+	listeners   map[int64]event.Listener
+	listenerId  int64
+	watchers    []*fakeServiceWatcher
+}
+
+type fakeServiceWatcher struct {
+	param    vo.WatchServicesParam
+	services []string
+}
+
+func NewFakeNamingClient() *FakeNamingClient {
+	return &FakeNamingClient{
+		services:  map[string]*fakeService{},
+		balancers: map[string]load_balancer.LoadBalancer{},
+		listeners: map[int64]event.Listener{},
+	}
+}
+
+func fakeServiceName(serviceName, groupName string) string {
+	if groupName == "" {
+		groupName = constant.DEFAULT_GROUP
+	}
+	return utils.GetGroupName(serviceName, groupName)
+}
+
+func fakeInstanceId(instance model.Instance) string {
+	return instance.Ip + constant.NAMING_INSTANCE_ID_SPLITTER + strconv.FormatUint(instance.Port, 10) +
+		constant.NAMING_INSTANCE_ID_SPLITTER + instance.ClusterName + constant.NAMING_INSTANCE_ID_SPLITTER + instance.ServiceName
+}
+
+// 调用方需持有锁
+func (c *FakeNamingClient) service(serviceName string) *fakeService {
+	service, ok := c.services[serviceName]
+	if !ok {
+		groupName := constant.DEFAULT_GROUP
+		name := serviceName
+		if parts := strings.SplitN(serviceName, constant.SERVICE_INFO_SPLITER, 2); len(parts) == 2 {
+			groupName, name = parts[0], parts[1]
+		}
+		service = &fakeService{meta: model.ServiceMeta{GroupName: groupName, Name: name}}
+		c.services[serviceName] = service
+	}
+	return service
+}
+
+// 在锁外回调，回调中可以再调用客户端
+func (c *FakeNamingClient) notify(serviceName string) {
+	type notification struct {
+		param    *vo.SubscribeParam
+		services []model.SubscribeService
+		event    model.InstanceChangeEvent
+	}
+	var notifications []notification
+	c.mutex.Lock()
+	if service, ok := c.services[serviceName]; ok {
+		service.updateTime = uint64(utils.CurrentMillis())
+	}
+	for _, subscriber := range c.subscribers {
+		if subscriber.serviceName != serviceName {
+			continue
+		}
+		hosts := c.hosts(serviceName, subscriber.param.Clusters, subscriber.param.Selector)
+		if reflect.DeepEqual(hosts, subscriber.hosts) {
+			continue
+		}
+		notifications = append(notifications, notification{
+			param:    subscriber.param,
+			services: toSubscribeServices(hosts),
+			event:    diffFakeInstances(serviceName, strings.Join(subscriber.param.Clusters, ","), subscriber.hosts, hosts),
+		})
+		subscriber.hosts = hosts
+	}
+	c.mutex.Unlock()
+	for _, n := range notifications {
+		if n.param.SubscribeCallback != nil {
+			if len(n.services) == 0 {
+				n.param.SubscribeCallback(nil, errors.New("[client.Subscribe] subscribe failed,hosts is empty"))
+			} else {
+				n.param.SubscribeCallback(n.services, nil)
+			}
+		}
+		if n.param.ChangeCallback != nil && !n.event.IsEmpty() {
+			n.param.ChangeCallback(n.event)
+		}
+	}
+	c.notifyWatchers()
+}
+
+// 按集群和元数据选择器过滤，按集群、ip和端口排序，调用方需持有锁
+func (c *FakeNamingClient) hosts(serviceName string, clusters []string, selector string) []model.Instance {
+	service, ok := c.services[serviceName]
+	if !ok {
+		return nil
+	}
+	var hosts []model.Instance
+	for _, host := range service.hosts {
+		if len(clusters) > 0 && !containsString(clusters, host.ClusterName) {
+			continue
+		}
+		if !matchFakeSelector(selector, host.Metadata) {
+			continue
+		}
+		hosts = append(hosts, host)
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		return fakeInstanceId(hosts[i]) < fakeInstanceId(hosts[j])
+	})
+	return hosts
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// 与SubscribeParam.Selector格式一致，如CONSUMER.label.env=prod&label.zone!=a
+func matchFakeSelector(selector string, metadata map[string]string) bool {
+	for _, clause := range strings.Split(selector, "&") {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			continue
+		}
+		notEqual := strings.Contains(clause, "!=")
+		kv := strings.SplitN(strings.Replace(clause, "!=", "=", 1), "=", 2)
+		if len(kv) != 2 {
+			return false
+		}
+		key := strings.TrimSpace(kv[0])
+		if i := strings.Index(key, ".label."); i >= 0 {
+			key = key[i+len(".label."):]
+		} else {
+			key = strings.TrimPrefix(key, "label.")
+		}
+		value, ok := metadata[key]
+		if notEqual == (ok && value == strings.TrimSpace(kv[1])) {
+			return false
+		}
+	}
+	return true
+}
+
+func toSubscribeServices(hosts []model.Instance) []model.SubscribeService {
+	var services []model.SubscribeService
+	for _, host := range hosts {
+		services = append(services, model.SubscribeService{
+			ClusterName: host.ClusterName,
+			Enable:      host.Enable,
+			InstanceId:  host.InstanceId,
+			Ip:          host.Ip,
+			Metadata:    host.Metadata,
+			Port:        host.Port,
+			ServiceName: host.ServiceName,
+			Valid:       host.Valid,
+			Weight:      host.Weight,
+		})
+	}
+	return services
+}
+
+// oldHosts和newHosts均已排序
+func diffFakeInstances(serviceName, clusters string, oldHosts, newHosts []model.Instance) model.InstanceChangeEvent {
+	e := model.InstanceChangeEvent{ServiceName: serviceName, Clusters: clusters}
+	oldMap := map[string]model.Instance{}
+	for _, host := range oldHosts {
+		oldMap[fakeInstanceId(host)] = host
+	}
+	for _, host := range newHosts {
+		old, ok := oldMap[fakeInstanceId(host)]
+		if !ok {
+			e.Added = append(e.Added, host)
+		} else if !reflect.DeepEqual(old, host) {
+			e.Modified = append(e.Modified, host)
+		}
+		delete(oldMap, fakeInstanceId(host))
+	}
+	for _, host := range oldHosts {
+		if _, ok := oldMap[fakeInstanceId(host)]; ok {
+			e.Removed = append(e.Removed, host)
+		}
+	}
+	return e
+}
+
+func (c *FakeNamingClient) RegisterInstance(param vo.RegisterInstanceParam) (bool, error) {
+	return c.RegisterInstanceWithContext(context.Background(), param)
+}
+
+// 同一集群下ip和端口相同的实例会被覆盖
+func (c *FakeNamingClient) RegisterInstanceWithContext(ctx context.Context, param vo.RegisterInstanceParam) (bool, error) {
+	if param.ServiceName == "" {
+		return false, errors.New("[client.RegisterInstance] serviceName can not be empty")
+	}
+	if param.Ip == "" {
+		return false, errors.New("[client.RegisterInstance] Ip can not be empty")
+	}
+	serviceName := fakeServiceName(param.ServiceName, param.GroupName)
+	instance := model.Instance{
+		Valid:       true,
+		Ip:          param.Ip,
+		Port:        param.Port,
+		Weight:      param.Weight,
+		Metadata:    param.Metadata,
+		ClusterName: param.ClusterName,
+		ServiceName: serviceName,
+		Enable:      param.Enable,
+		Healthy:     param.Healthy,
+		Ephemeral:   param.Ephemeral,
+	}
+	if instance.ClusterName == "" {
+		instance.ClusterName = Fake_Default_Cluster
+	}
+	instance.InstanceId = fakeInstanceId(instance)
+	c.mutex.Lock()
+	service := c.service(serviceName)
+	service.hosts = append(removeFakeInstance(service.hosts, instance.Ip, instance.Port, instance.ClusterName), instance)
+	c.mutex.Unlock()
+	c.notify(serviceName)
+	return true, nil
+}
+
+// cluster为空时移除所有集群中ip和端口相同的实例
+func removeFakeInstance(hosts []model.Instance, ip string, port uint64, cluster string) []model.Instance {
+	result := make([]model.Instance, 0, len(hosts))
+	for _, host := range hosts {
+		if host.Ip == ip && host.Port == port && (cluster == "" || host.ClusterName == cluster) {
+			continue
+		}
+		result = append(result, host)
+	}
+	return result
+}
+
+func (c *FakeNamingClient) DeregisterInstance(param vo.DeregisterInstanceParam) (bool, error) {
+	return c.DeregisterInstanceWithContext(context.Background(), param)
+}
+
+func (c *FakeNamingClient) DeregisterInstanceWithContext(ctx context.Context, param vo.DeregisterInstanceParam) (bool, error) {
+	serviceName := fakeServiceName(param.ServiceName, param.GroupName)
+	c.mutex.Lock()
+	if service, ok := c.services[serviceName]; ok {
+		service.hosts = removeFakeInstance(service.hosts, param.Ip, param.Port, param.Cluster)
+	}
+	c.mutex.Unlock()
+	c.notify(serviceName)
+	return true, nil
+}
+
+func (c *FakeNamingClient) DeregisterAllInstances() error {
+	return c.DeregisterAllInstancesWithContext(context.Background())
+}
+
+// 移除所有服务中的临时实例
+func (c *FakeNamingClient) DeregisterAllInstancesWithContext(ctx context.Context) error {
+	var changed []string
+	c.mutex.Lock()
+	for serviceName, service := range c.services {
+		hosts := make([]model.Instance, 0, len(service.hosts))
+		for _, host := range service.hosts {
+			if !host.Ephemeral {
+				hosts = append(hosts, host)
+			}
+		}
+		if len(hosts) != len(service.hosts) {
+			service.hosts = hosts
+			changed = append(changed, serviceName)
+		}
+	}
+	c.mutex.Unlock()
+	for _, serviceName := range changed {
+		c.notify(serviceName)
+	}
+	return nil
+}
+
+// 与真实客户端一样在后台执行，结果写入有缓冲的channel
+func (c *FakeNamingClient) RegisterInstanceAsync(ctx context.Context, param vo.RegisterInstanceParam) <-chan model.AsyncResult {
+	result := make(chan model.AsyncResult, 1)
+	go func() {
+		success, err := c.RegisterInstanceWithContext(ctx, param)
+		result <- model.AsyncResult{Success: success, Err: err}
+	}()
+	return result
+}
+
+func (c *FakeNamingClient) DeregisterInstanceAsync(ctx context.Context, param vo.DeregisterInstanceParam) <-chan model.AsyncResult {
+	result := make(chan model.AsyncResult, 1)
+	go func() {
+		success, err := c.DeregisterInstanceWithContext(ctx, param)
+		result <- model.AsyncResult{Success: success, Err: err}
+	}()
+	return result
+}
+
+func (c *FakeNamingClient) UpdateInstance(param vo.UpdateInstanceParam) (bool, error) {
+	return c.UpdateInstanceWithContext(context.Background(), param)
+}
+
+// 实例不存在时返回错误
+func (c *FakeNamingClient) UpdateInstanceWithContext(ctx context.Context, param vo.UpdateInstanceParam) (bool, error) {
+	serviceName := fakeServiceName(param.ServiceName, param.GroupName)
+	cluster := param.ClusterName
+	if cluster == "" {
+		cluster = Fake_Default_Cluster
+	}
+	c.mutex.Lock()
+	updated := false
+	if service, ok := c.services[serviceName]; ok {
+		for i, host := range service.hosts {
+			if host.Ip == param.Ip && host.Port == param.Port && host.ClusterName == cluster {
+				host.Weight, host.Enable, host.Healthy, host.Metadata = param.Weight, param.Enable, param.Healthy, param.Metadata
+				service.hosts[i] = host
+				updated = true
+			}
+		}
+	}
+	c.mutex.Unlock()
+	if !updated {
+		return false, errors.New("[client.UpdateInstance] instance not found")
+	}
+	c.notify(serviceName)
+	return true, nil
+}
+
+func (c *FakeNamingClient) DrainInstance(param vo.DrainInstanceParam, duration time.Duration) (bool, error) {
+	return c.DrainInstanceWithContext(context.Background(), param, duration)
+}
+
+// 与真实客户端一样逐步降低权重并通知订阅者，但不等待duration
+func (c *FakeNamingClient) DrainInstanceWithContext(ctx context.Context, param vo.DrainInstanceParam, duration time.Duration) (bool, error) {
+	serviceName := fakeServiceName(param.ServiceName, param.GroupName)
+	var current *model.Instance
+	c.mutex.Lock()
+	if service, ok := c.services[serviceName]; ok {
+		for _, host := range service.hosts {
+			if host.Ip == param.Ip && host.Port == param.Port && (param.ClusterName == "" || host.ClusterName == param.ClusterName) {
+				current = &host
+				break
+			}
+		}
+	}
+	c.mutex.Unlock()
+	if current == nil {
+		return false, errors.New("[client.DrainInstance] instance not found")
+	}
+	steps := param.Steps
+	if steps <= 0 {
+		steps = 10
+	}
+	update := vo.UpdateInstanceParam{Ip: param.Ip, Port: param.Port, ClusterName: current.ClusterName, ServiceName: param.ServiceName,
+		GroupName: param.GroupName, Healthy: current.Healthy, Metadata: current.Metadata, Enable: true}
+	for i := 1; i <= steps+1; i++ {
+		if i <= steps {
+			update.Weight = current.Weight * float64(steps-i) / float64(steps)
+		} else {
+			update.Enable = false
+		}
+		if _, err := c.UpdateInstanceWithContext(ctx, update); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// 实例没有心跳，暂停时立即将临时实例标记为不健康，恢复时标记为健康
+func (c *FakeNamingClient) PauseHeartbeat(param vo.HeartbeatParam) (bool, error) {
+	return c.setHeartbeatHealthy("PauseHeartbeat", param, false)
+}
+
+func (c *FakeNamingClient) ResumeHeartbeat(param vo.HeartbeatParam) (bool, error) {
+	return c.setHeartbeatHealthy("ResumeHeartbeat", param, true)
+}
+
+func (c *FakeNamingClient) setHeartbeatHealthy(method string, param vo.HeartbeatParam, healthy bool) (bool, error) {
+	serviceName := fakeServiceName(param.ServiceName, param.GroupName)
+	c.mutex.Lock()
+	found := false
+	if service, ok := c.services[serviceName]; ok {
+		for i, host := range service.hosts {
+			if host.Ip == param.Ip && host.Port == param.Port && host.Ephemeral {
+				service.hosts[i].Healthy = healthy
+				found = true
+			}
+		}
+	}
+	c.mutex.Unlock()
+	if !found {
+		return false, errors.New("[client." + method + "] instance is not registered as ephemeral instance")
+	}
+	c.notify(serviceName)
+	return true, nil
+}
+
+func (c *FakeNamingClient) BatchRegisterInstance(param vo.BatchRegisterInstanceParam) (bool, error) {
+	return c.BatchRegisterInstanceWithContext(context.Background(), param)
+}
+
+func (c *FakeNamingClient) BatchRegisterInstanceWithContext(ctx context.Context, param vo.BatchRegisterInstanceParam) (bool, error) {
+	for _, instance := range param.Instances {
+		if _, err := c.RegisterInstanceWithContext(ctx, instance); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (c *FakeNamingClient) BatchDeregisterInstance(param vo.BatchDeregisterInstanceParam) (bool, error) {
+	return c.BatchDeregisterInstanceWithContext(context.Background(), param)
+}
+
+func (c *FakeNamingClient) BatchDeregisterInstanceWithContext(ctx context.Context, param vo.BatchDeregisterInstanceParam) (bool, error) {
+	for _, instance := range param.Instances {
+		if _, err := c.DeregisterInstanceWithContext(ctx, instance); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (c *FakeNamingClient) GetService(param vo.GetServiceParam) (model.Service, error) {
+	return c.GetServiceWithContext(context.Background(), param)
+}
+
+func (c *FakeNamingClient) GetServiceWithContext(ctx context.Context, param vo.GetServiceParam) (model.Service, error) {
+	return c.getService(fakeServiceName(param.ServiceName, param.GroupName), param.Clusters, ""), nil
+}
+
+func (c *FakeNamingClient) getService(serviceName string, clusters []string, selector string) model.Service {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	service := model.Service{Name: serviceName, Clusters: strings.Join(clusters, ","), Hosts: c.hosts(serviceName, clusters, selector)}
+	if s, ok := c.services[serviceName]; ok {
+		service.ProtectThreshold = s.meta.ProtectThreshold
+		service.Metadata = s.meta.Metadata
+	}
+	return service
+}
+
+func (c *FakeNamingClient) SelectAllInstances(param vo.SelectAllInstancesParam) ([]model.Instance, error) {
+	return c.SelectAllInstancesWithContext(context.Background(), param)
+}
+
+func (c *FakeNamingClient) SelectAllInstancesWithContext(ctx context.Context, param vo.SelectAllInstancesParam) ([]model.Instance, error) {
+	service := c.getService(fakeServiceName(param.ServiceName, param.GroupName), param.Clusters, param.Selector)
+	if len(service.Hosts) == 0 {
+		return []model.Instance{}, errors.New("instance list is empty!")
+	}
+	return service.Hosts, nil
+}
+
+func (c *FakeNamingClient) SelectInstances(param vo.SelectInstancesParam) ([]model.Instance, error) {
+	return c.SelectInstancesWithContext(context.Background(), param)
+}
+
+func (c *FakeNamingClient) SelectInstancesWithContext(ctx context.Context, param vo.SelectInstancesParam) ([]model.Instance, error) {
+	service := c.getService(fakeServiceName(param.ServiceName, param.GroupName), param.Clusters, param.Selector)
+	if len(service.Hosts) == 0 {
+		return []model.Instance{}, errors.New("instance list is empty!")
+	}
+	return selectFakeInstances(service, param.HealthyOnly), nil
+}
+
+// 与真实客户端一致，健康实例占比不高于服务的保护阈值时不健康的实例也参与选择
+func selectFakeInstances(service model.Service, healthy bool) []model.Instance {
+	protected := false
+	if healthy && service.ProtectThreshold > 0 {
+		count := 0
+		for _, host := range service.Hosts {
+			if host.Healthy {
+				count++
+			}
+		}
+		protected = float64(count)/float64(len(service.Hosts)) <= service.ProtectThreshold
+	}
+	var result []model.Instance
+	for _, host := range service.Hosts {
+		if (host.Healthy == healthy || protected) && host.Enable && host.Weight > 0 {
+			result = append(result, host)
+		}
+	}
+	return result
+}
+
+func (c *FakeNamingClient) SelectOneHealthyInstance(param vo.SelectOneHealthInstanceParam) (*model.Instance, error) {
+	return c.SelectOneHealthyInstanceWithContext(context.Background(), param)
+}
+
+// 未指定LoadBalancer时每个服务使用独立的平滑加权轮询
+func (c *FakeNamingClient) SelectOneHealthyInstanceWithContext(ctx context.Context, param vo.SelectOneHealthInstanceParam) (*model.Instance, error) {
+	service := c.getService(fakeServiceName(param.ServiceName, param.GroupName), param.Clusters, param.Selector)
+	if len(service.Hosts) == 0 {
+		return nil, errors.New("instance list is empty!")
+	}
+	hosts := selectFakeInstances(service, true)
+	if len(hosts) == 0 {
+		return nil, errors.New("healthy instance list is empty!")
+	}
+	balancer := param.LoadBalancer
+	if balancer == nil {
+		key := utils.GetServiceCacheKey(service.Name, service.Clusters)
+		c.mutex.Lock()
+		if balancer = c.balancers[key]; balancer == nil {
+			balancer = load_balancer.NewSmoothWeightedRoundRobinBalancer()
+			c.balancers[key] = balancer
+		}
+		c.mutex.Unlock()
+	}
+	instance := balancer.Select(hosts)
+	return &instance, nil
+}
+
+func (c *FakeNamingClient) SelectInstanceByHash(param vo.SelectOneHealthInstanceParam, hashKey string) (*model.Instance, error) {
+	return c.SelectInstanceByHashWithContext(context.Background(), param, hashKey)
+}
+
+func (c *FakeNamingClient) SelectInstanceByHashWithContext(ctx context.Context, param vo.SelectOneHealthInstanceParam, hashKey string) (*model.Instance, error) {
+	param.LoadBalancer = load_balancer.NewConsistentHashBalancer(hashKey, 0)
+	return c.SelectOneHealthyInstanceWithContext(ctx, param)
+}
+
+func (c *FakeNamingClient) Subscribe(param *vo.SubscribeParam) error {
+	return c.SubscribeWithContext(context.Background(), param)
+}
+
+// 服务下已有实例时立即回调一次，之后实例变化时同步回调
+func (c *FakeNamingClient) SubscribeWithContext(ctx context.Context, param *vo.SubscribeParam) error {
+	serviceName := fakeServiceName(param.ServiceName, param.GroupName)
+	c.mutex.Lock()
+	c.subscribers = append(c.subscribers, &fakeSubscriber{param: param, serviceName: serviceName})
+	c.mutex.Unlock()
+	c.notify(serviceName)
+	return nil
+}
+
+// 按Subscribe时的param取消
+func (c *FakeNamingClient) Unsubscribe(param *vo.SubscribeParam) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	subscribers := c.subscribers[:0]
+	for _, subscriber := range c.subscribers {
+		if subscriber.param != param {
+			subscribers = append(subscribers, subscriber)
+		}
+	}
+	c.subscribers = subscribers
+	return nil
+}
+
+func (c *FakeNamingClient) GetSubscribedServices() []model.SubscribedService {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	counts := map[model.SubscribedService]int{}
+	for _, subscriber := range c.subscribers {
+		counts[model.SubscribedService{ServiceName: subscriber.serviceName, Clusters: strings.Join(subscriber.param.Clusters, ",")}]++
+	}
+	var services []model.SubscribedService
+	for service, count := range counts {
+		service.Subscribers = count
+		services = append(services, service)
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return utils.GetServiceCacheKey(services[i].ServiceName, services[i].Clusters) < utils.GetServiceCacheKey(services[j].ServiceName, services[j].Clusters)
+	})
+	return services
+}
+
+func (c *FakeNamingClient) CreateService(param vo.CreateServiceParam) (bool, error) {
+	return c.CreateServiceWithContext(context.Background(), param)
+}
+
+func (c *FakeNamingClient) CreateServiceWithContext(ctx context.Context, param vo.CreateServiceParam) (bool, error) {
+	if len(param.ServiceName) == 0 {
+		return false, errors.New("[client.CreateService] serviceName can not be empty")
+	}
+	serviceName := fakeServiceName(param.ServiceName, param.GroupName)
+	defer c.notifyWatchers()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.services[serviceName]; ok {
+		return false, errors.New("[client.CreateService] service already exists:" + serviceName)
+	}
+	c.service(serviceName).setMeta(param.ProtectThreshold, param.Metadata, param.Selector)
+	return true, nil
+}
+
+func (s *fakeService) setMeta(protectThreshold float64, metadata map[string]string, selector *model.ExpressionSelector) {
+	s.meta.ProtectThreshold = protectThreshold
+	s.meta.Metadata = metadata
+	s.meta.Selector = model.ExpressionSelector{}
+	if selector != nil {
+		s.meta.Selector = *selector
+	}
+}
+
+func (c *FakeNamingClient) UpdateService(param vo.UpdateServiceParam) (bool, error) {
+	return c.UpdateServiceWithContext(context.Background(), param)
+}
+
+func (c *FakeNamingClient) UpdateServiceWithContext(ctx context.Context, param vo.UpdateServiceParam) (bool, error) {
+	serviceName := fakeServiceName(param.ServiceName, param.GroupName)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	service, ok := c.services[serviceName]
+	if !ok {
+		return false, errors.New("[client.UpdateService] service not found:" + serviceName)
+	}
+	service.setMeta(param.ProtectThreshold, param.Metadata, param.Selector)
+	return true, nil
+}
+
+func (c *FakeNamingClient) DeleteService(param vo.DeleteServiceParam) (bool, error) {
+	return c.DeleteServiceWithContext(context.Background(), param)
+}
+
+// 与服务端一致，服务下仍有实例时拒绝删除
+func (c *FakeNamingClient) DeleteServiceWithContext(ctx context.Context, param vo.DeleteServiceParam) (bool, error) {
+	serviceName := fakeServiceName(param.ServiceName, param.GroupName)
+	defer c.notifyWatchers()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	service, ok := c.services[serviceName]
+	if !ok {
+		return false, errors.New("[client.DeleteService] service not found:" + serviceName)
+	}
+	if len(service.hosts) > 0 {
+		return false, errors.New("[client.DeleteService] service has instances:" + serviceName)
+	}
+	delete(c.services, serviceName)
+	return true, nil
+}
+
+func (c *FakeNamingClient) GetServiceDetail(param vo.GetServiceDetailParam) (model.ServiceMeta, error) {
+	return c.GetServiceDetailWithContext(context.Background(), param)
+}
+
+func (c *FakeNamingClient) GetServiceDetailWithContext(ctx context.Context, param vo.GetServiceDetailParam) (model.ServiceMeta, error) {
+	serviceName := fakeServiceName(param.ServiceName, param.GroupName)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	service, ok := c.services[serviceName]
+	if !ok {
+		return model.ServiceMeta{}, errors.New("[client.GetServiceDetail] service not found:" + serviceName)
+	}
+	return service.meta, nil
+}
+
+func (c *FakeNamingClient) GetAllServicesInfo(param vo.GetAllServiceInfoParam) (model.ServiceList, error) {
+	return c.GetAllServicesInfoWithContext(context.Background(), param)
+}
+
+func (c *FakeNamingClient) GetAllServicesInfoWithContext(ctx context.Context, param vo.GetAllServiceInfoParam) (model.ServiceList, error) {
+	return c.SearchServiceWithContext(ctx, vo.SearchServiceParam{GroupName: param.GroupName, PageNo: param.PageNo, PageSize: param.PageSize})
+}
+
+func (c *FakeNamingClient) SearchService(param vo.SearchServiceParam) (model.ServiceList, error) {
+	return c.SearchServiceWithContext(context.Background(), param)
+}
+
+// 只按Pattern过滤，Selector不生效
+func (c *FakeNamingClient) SearchServiceWithContext(ctx context.Context, param vo.SearchServiceParam) (model.ServiceList, error) {
+	if param.GroupName == "" {
+		param.GroupName = constant.DEFAULT_GROUP
+	}
+	if param.Pattern != "" {
+		if _, err := path.Match(param.Pattern, ""); err != nil {
+			return model.ServiceList{}, errors.New("[client.SearchService] invalid pattern:" + param.Pattern)
+		}
+	}
+	c.mutex.Lock()
+	var names []string
+	for _, service := range c.services {
+		if service.meta.GroupName != param.GroupName {
+			continue
+		}
+		if ok, _ := path.Match(param.Pattern, service.meta.Name); param.Pattern == "" || ok {
+			names = append(names, service.meta.Name)
+		}
+	}
+	c.mutex.Unlock()
+	sort.Strings(names)
+	pageNo, pageSize := int(param.PageNo), int(param.PageSize)
+	if pageNo == 0 {
+		pageNo = 1
+	}
+	if pageSize == 0 {
+		pageSize = 10
+	}
+	list := model.ServiceList{Count: int64(len(names)), Doms: []string{}}
+	if start := (pageNo - 1) * pageSize; start < len(names) {
+		end := start + pageSize
+		if end > len(names) {
+			end = len(names)
+		}
+		list.Doms = names[start:end]
+	}
+	return list, nil
+}
+
+func (c *FakeNamingClient) WatchAllServices(param vo.WatchServicesParam) error {
+	return c.WatchAllServicesWithContext(context.Background(), param)
+}
+
+// 服务创建、删除或注册实例后同步回调，Interval不生效
+func (c *FakeNamingClient) WatchAllServicesWithContext(ctx context.Context, param vo.WatchServicesParam) error {
+	if param.Callback == nil {
+		return errors.New("[client.WatchAllServices] callback can not be nil")
+	}
+	if param.Pattern != "" {
+		if _, err := path.Match(param.Pattern, ""); err != nil {
+			return errors.New("[client.WatchAllServices] invalid pattern:" + param.Pattern)
+		}
+	}
+	if param.GroupName == "" {
+		param.GroupName = constant.DEFAULT_GROUP
+	}
+	watcher := &fakeServiceWatcher{param: param}
+	c.mutex.Lock()
+	c.watchers = append(c.watchers, watcher)
+	c.mutex.Unlock()
+	c.notifyWatchers()
+	if ctx.Done() != nil {
+		go func() {
+			<-ctx.Done()
+			c.mutex.Lock()
+			defer c.mutex.Unlock()
+			for i, w := range c.watchers {
+				if w == watcher {
+					c.watchers = append(c.watchers[:i], c.watchers[i+1:]...)
+					break
+				}
+			}
+		}()
+	}
+	return nil
+}
+
+// 在锁外回调服务名列表发生变化的监听者
+func (c *FakeNamingClient) notifyWatchers() {
+	type notification struct {
+		callback func(event model.ServiceListChangeEvent)
+		event    model.ServiceListChangeEvent
+	}
+	var notifications []notification
+	c.mutex.Lock()
+	for _, watcher := range c.watchers {
+		var names []string
+		for _, service := range c.services {
+			if service.meta.GroupName != watcher.param.GroupName {
+				continue
+			}
+			if ok, _ := path.Match(watcher.param.Pattern, service.meta.Name); watcher.param.Pattern == "" || ok {
+				names = append(names, service.meta.Name)
+			}
+		}
+		sort.Strings(names)
+		event := model.ServiceListChangeEvent{NameSpace: watcher.param.NameSpace, GroupName: watcher.param.GroupName}
+		event.Added = subtractStrings(names, watcher.services)
+		event.Removed = subtractStrings(watcher.services, names)
+		watcher.services = names
+		if len(event.Added) > 0 || len(event.Removed) > 0 {
+			notifications = append(notifications, notification{callback: watcher.param.Callback, event: event})
+		}
+	}
+	c.mutex.Unlock()
+	for _, n := range notifications {
+		n.callback(n.event)
+	}
+}
+
+// 返回a中不在b中的元素
+func subtractStrings(a, b []string) []string {
+	var result []string
+	for _, s := range a {
+		if !containsString(b, s) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// 返回所有有实例的服务
+func (c *FakeNamingClient) GetCachedServices() []model.Service {
+	c.mutex.Lock()
+	var names []string
+	for name, service := range c.services {
+		if len(service.hosts) > 0 {
+			names = append(names, name)
+		}
+	}
+	c.mutex.Unlock()
+	sort.Strings(names)
+	var services []model.Service
+	for _, name := range names {
+		services = append(services, c.getService(name, nil, ""))
+	}
+	return services
+}
+
+// 与GetCachedServices一致，key为服务名
+func (c *FakeNamingClient) GetCachedServiceInfoMapSnapshot() map[string]model.Service {
+	snapshot := map[string]model.Service{}
+	for _, service := range c.GetCachedServices() {
+		snapshot[service.Name] = service
+	}
+	return snapshot
+}
+
+// 返回有实例的服务最后一次注册、注销或修改实例的时间
+func (c *FakeNamingClient) GetUpdateTimeMap() map[string]uint64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	updateTimes := map[string]uint64{}
+	for name, service := range c.services {
+		if len(service.hosts) > 0 {
+			updateTimes[name] = service.updateTime
+		}
+	}
+	return updateTimes
+}
+
+// 移除服务下的所有实例并取消该服务的订阅
+func (c *FakeNamingClient) PurgeServiceCache(param vo.PurgeServiceCacheParam) bool {
+	serviceName := fakeServiceName(param.ServiceName, param.GroupName)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.services[serviceName]; !ok {
+		return false
+	}
+	delete(c.services, serviceName)
+	subscribers := c.subscribers[:0]
+	for _, subscriber := range c.subscribers {
+		if subscriber.serviceName != serviceName {
+			subscribers = append(subscribers, subscriber)
+		}
+	}
+	c.subscribers = subscribers
+	return true
+}
+
+// 将服务的实例列表替换为param.Hosts
+func (c *FakeNamingClient) UpdateServiceCache(param vo.UpdateServiceCacheParam) error {
+	if param.ServiceName == "" {
+		return errors.New("[client.UpdateServiceCache] serviceName can not be empty")
+	}
+	serviceName := fakeServiceName(param.ServiceName, param.GroupName)
+	hosts := make([]model.Instance, len(param.Hosts))
+	for i, host := range param.Hosts {
+		if host.ServiceName == "" {
+			host.ServiceName = serviceName
+		}
+		if host.ClusterName == "" {
+			host.ClusterName = Fake_Default_Cluster
+		}
+		hosts[i] = host
+	}
+	c.mutex.Lock()
+	service := c.service(serviceName)
+	service.hosts = hosts
+	if param.Metadata != nil {
+		service.meta.Metadata = param.Metadata
+	}
+	c.mutex.Unlock()
+	c.notify(serviceName)
+	return nil
+}
+
+// 内存实现不产生生命周期事件，只记录监听
+func (c *FakeNamingClient) SubscribeEvent(listener event.Listener, types ...event.EventType) int64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.listenerId++
+	c.listeners[c.listenerId] = listener
+	return c.listenerId
+}
+
+func (c *FakeNamingClient) UnsubscribeEvent(id int64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.listeners, id)
+}
+
+func (c *FakeNamingClient) UpdateClientConfig(opts ...constant.ClientOption) error {
+	return nil
+}
+
+func (c *FakeNamingClient) UpdateServerConfig(serverConfigs []constant.ServerConfig) error {
+	if len(serverConfigs) == 0 {
+		return errors.New("[client.UpdateServerConfig] server configs can not be empty")
+	}
+	return nil
+}
+
+func (c *FakeNamingClient) Close() error {
+	return nil
+}
@@ -5,7 +5,10 @@
 package mock
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
+	constant "github.com/nacos-group/nacos-sdk-go/common/constant"
+	model "github.com/nacos-group/nacos-sdk-go/model"
 	vo "github.com/nacos-group/nacos-sdk-go/vo"
 	reflect "reflect"
 )
@@ -48,6 +51,20 @@ func (mr *MockIConfigClientMockRecorder) GetConfig(param interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfig", reflect.TypeOf((*MockIConfigClient)(nil).GetConfig), param)
 }
 
+// GetConfigAs mocks base method
+func (m *MockIConfigClient) GetConfigAs(param vo.ConfigParam, v interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigAs", param, v)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetConfigAs indicates an expected call of GetConfigAs
+func (mr *MockIConfigClientMockRecorder) GetConfigAs(param, v interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigAs", reflect.TypeOf((*MockIConfigClient)(nil).GetConfigAs), param, v)
+}
+
 // PublishConfig mocks base method
 func (m *MockIConfigClient) PublishConfig(param vo.ConfigParam) (bool, error) {
 	m.ctrl.T.Helper()
@@ -63,6 +80,36 @@ func (mr *MockIConfigClientMockRecorder) PublishConfig(param interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishConfig", reflect.TypeOf((*MockIConfigClient)(nil).PublishConfig), param)
 }
 
+// PublishConfigCas mocks base method
+func (m *MockIConfigClient) PublishConfigCas(param vo.ConfigParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishConfigCas", param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishConfigCas indicates an expected call of PublishConfigCas
+func (mr *MockIConfigClientMockRecorder) PublishConfigCas(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishConfigCas", reflect.TypeOf((*MockIConfigClient)(nil).PublishConfigCas), param)
+}
+
+// StopBeta mocks base method
+func (m *MockIConfigClient) StopBeta(param vo.ConfigParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopBeta", param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StopBeta indicates an expected call of StopBeta
+func (mr *MockIConfigClientMockRecorder) StopBeta(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopBeta", reflect.TypeOf((*MockIConfigClient)(nil).StopBeta), param)
+}
+
 // DeleteConfig mocks base method
 func (m *MockIConfigClient) DeleteConfig(param vo.ConfigParam) (bool, error) {
 	m.ctrl.T.Helper()
@@ -79,7 +126,7 @@ func (mr *MockIConfigClientMockRecorder) DeleteConfig(param interface{}) *gomock
 }
 
 // ListenConfig mocks base method
-func (m *MockIConfigClient) ListenConfig(params []vo.ConfigParam) error {
+func (m *MockIConfigClient) ListenConfig(params vo.ConfigParam) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListenConfig", params)
 	ret0, _ := ret[0].(error)
@@ -92,43 +139,444 @@ func (mr *MockIConfigClientMockRecorder) ListenConfig(params interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListenConfig", reflect.TypeOf((*MockIConfigClient)(nil).ListenConfig), params)
 }
 
-// AddConfigToListen mocks base method
-func (m *MockIConfigClient) AddConfigToListen(params []vo.ConfigParam) error {
+// CancelListenConfig mocks base method
+func (m *MockIConfigClient) CancelListenConfig(params vo.ConfigParam) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelListenConfig", params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelListenConfig indicates an expected call of CancelListenConfig
+func (mr *MockIConfigClientMockRecorder) CancelListenConfig(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelListenConfig", reflect.TypeOf((*MockIConfigClient)(nil).CancelListenConfig), params)
+}
+
+// ListenConfigAs mocks base method
+func (m *MockIConfigClient) ListenConfigAs(param vo.ConfigParam, newValue func() interface{}, onChange func(value interface{}, err error)) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddConfigToListen", params)
+	ret := m.ctrl.Call(m, "ListenConfigAs", param, newValue, onChange)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// AddConfigToListen indicates an expected call of AddConfigToListen
-func (mr *MockIConfigClientMockRecorder) AddConfigToListen(params interface{}) *gomock.Call {
+// ListenConfigAs indicates an expected call of ListenConfigAs
+func (mr *MockIConfigClientMockRecorder) ListenConfigAs(param, newValue, onChange interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListenConfigAs", reflect.TypeOf((*MockIConfigClient)(nil).ListenConfigAs), param, newValue, onChange)
+}
+
+// SearchConfig mocks base method
+func (m *MockIConfigClient) SearchConfig(param vo.SearchConfigParam) (*model.ConfigPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchConfig", param)
+	ret0, _ := ret[0].(*model.ConfigPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchConfig indicates an expected call of SearchConfig
+func (mr *MockIConfigClientMockRecorder) SearchConfig(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchConfig", reflect.TypeOf((*MockIConfigClient)(nil).SearchConfig), param)
+}
+
+// ListenConfigWithPrefix mocks base method
+func (m *MockIConfigClient) ListenConfigWithPrefix(param vo.ConfigPrefixParam) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListenConfigWithPrefix", param)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ListenConfigWithPrefix indicates an expected call of ListenConfigWithPrefix
+func (mr *MockIConfigClientMockRecorder) ListenConfigWithPrefix(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListenConfigWithPrefix", reflect.TypeOf((*MockIConfigClient)(nil).ListenConfigWithPrefix), param)
+}
+
+// ListenConfigGroup mocks base method
+func (m *MockIConfigClient) ListenConfigGroup(param vo.ConfigGroupParam) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListenConfigGroup", param)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ListenConfigGroup indicates an expected call of ListenConfigGroup
+func (mr *MockIConfigClientMockRecorder) ListenConfigGroup(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListenConfigGroup", reflect.TypeOf((*MockIConfigClient)(nil).ListenConfigGroup), param)
+}
+
+// GetConfigHistory mocks base method
+func (m *MockIConfigClient) GetConfigHistory(param vo.ConfigHistoryParam) (*model.ConfigHistoryPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigHistory", param)
+	ret0, _ := ret[0].(*model.ConfigHistoryPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigHistory indicates an expected call of GetConfigHistory
+func (mr *MockIConfigClientMockRecorder) GetConfigHistory(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigHistory", reflect.TypeOf((*MockIConfigClient)(nil).GetConfigHistory), param)
+}
+
+// GetPreviousConfig mocks base method
+func (m *MockIConfigClient) GetPreviousConfig(param vo.ConfigHistoryDetailParam) (*model.ConfigHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreviousConfig", param)
+	ret0, _ := ret[0].(*model.ConfigHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreviousConfig indicates an expected call of GetPreviousConfig
+func (mr *MockIConfigClientMockRecorder) GetPreviousConfig(param interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddConfigToListen", reflect.TypeOf((*MockIConfigClient)(nil).AddConfigToListen), params)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreviousConfig", reflect.TypeOf((*MockIConfigClient)(nil).GetPreviousConfig), param)
 }
 
-// StopListenConfig mocks base method
-func (m *MockIConfigClient) StopListenConfig() {
+// RollbackConfig mocks base method
+func (m *MockIConfigClient) RollbackConfig(param vo.ConfigHistoryDetailParam) (bool, error) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "StopListenConfig")
+	ret := m.ctrl.Call(m, "RollbackConfig", param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollbackConfig indicates an expected call of RollbackConfig
+func (mr *MockIConfigClientMockRecorder) RollbackConfig(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackConfig", reflect.TypeOf((*MockIConfigClient)(nil).RollbackConfig), param)
+}
+
+// ExportConfigs mocks base method
+func (m *MockIConfigClient) ExportConfigs(param vo.ExportConfigParam) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportConfigs", param)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportConfigs indicates an expected call of ExportConfigs
+func (mr *MockIConfigClientMockRecorder) ExportConfigs(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportConfigs", reflect.TypeOf((*MockIConfigClient)(nil).ExportConfigs), param)
+}
+
+// ImportConfigs mocks base method
+func (m *MockIConfigClient) ImportConfigs(param vo.ImportConfigParam) (*model.ConfigImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportConfigs", param)
+	ret0, _ := ret[0].(*model.ConfigImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// StopListenConfig indicates an expected call of StopListenConfig
-func (mr *MockIConfigClientMockRecorder) StopListenConfig() *gomock.Call {
+// ImportConfigs indicates an expected call of ImportConfigs
+func (mr *MockIConfigClientMockRecorder) ImportConfigs(param interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopListenConfig", reflect.TypeOf((*MockIConfigClient)(nil).StopListenConfig))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportConfigs", reflect.TypeOf((*MockIConfigClient)(nil).ImportConfigs), param)
 }
 
-// GetConfigContent mocks base method
-func (m *MockIConfigClient) GetConfigContent(dataId, groupId string) (string, error) {
+// GetConfigWithContext mocks base method
+func (m *MockIConfigClient) GetConfigWithContext(ctx context.Context, param vo.ConfigParam) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetConfigContent", dataId, groupId)
+	ret := m.ctrl.Call(m, "GetConfigWithContext", ctx, param)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetConfigContent indicates an expected call of GetConfigContent
-func (mr *MockIConfigClientMockRecorder) GetConfigContent(dataId, groupId interface{}) *gomock.Call {
+// GetConfigWithContext indicates an expected call of GetConfigWithContext
+func (mr *MockIConfigClientMockRecorder) GetConfigWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigWithContext", reflect.TypeOf((*MockIConfigClient)(nil).GetConfigWithContext), ctx, param)
+}
+
+// GetConfigAsWithContext mocks base method
+func (m *MockIConfigClient) GetConfigAsWithContext(ctx context.Context, param vo.ConfigParam, v interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigAsWithContext", ctx, param, v)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetConfigAsWithContext indicates an expected call of GetConfigAsWithContext
+func (mr *MockIConfigClientMockRecorder) GetConfigAsWithContext(ctx, param, v interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigAsWithContext", reflect.TypeOf((*MockIConfigClient)(nil).GetConfigAsWithContext), ctx, param, v)
+}
+
+// PublishConfigWithContext mocks base method
+func (m *MockIConfigClient) PublishConfigWithContext(ctx context.Context, param vo.ConfigParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishConfigWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishConfigWithContext indicates an expected call of PublishConfigWithContext
+func (mr *MockIConfigClientMockRecorder) PublishConfigWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishConfigWithContext", reflect.TypeOf((*MockIConfigClient)(nil).PublishConfigWithContext), ctx, param)
+}
+
+// PublishConfigCasWithContext mocks base method
+func (m *MockIConfigClient) PublishConfigCasWithContext(ctx context.Context, param vo.ConfigParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishConfigCasWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishConfigCasWithContext indicates an expected call of PublishConfigCasWithContext
+func (mr *MockIConfigClientMockRecorder) PublishConfigCasWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishConfigCasWithContext", reflect.TypeOf((*MockIConfigClient)(nil).PublishConfigCasWithContext), ctx, param)
+}
+
+// StopBetaWithContext mocks base method
+func (m *MockIConfigClient) StopBetaWithContext(ctx context.Context, param vo.ConfigParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopBetaWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StopBetaWithContext indicates an expected call of StopBetaWithContext
+func (mr *MockIConfigClientMockRecorder) StopBetaWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopBetaWithContext", reflect.TypeOf((*MockIConfigClient)(nil).StopBetaWithContext), ctx, param)
+}
+
+// DeleteConfigWithContext mocks base method
+func (m *MockIConfigClient) DeleteConfigWithContext(ctx context.Context, param vo.ConfigParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConfigWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteConfigWithContext indicates an expected call of DeleteConfigWithContext
+func (mr *MockIConfigClientMockRecorder) DeleteConfigWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfigWithContext", reflect.TypeOf((*MockIConfigClient)(nil).DeleteConfigWithContext), ctx, param)
+}
+
+// ListenConfigWithContext mocks base method
+func (m *MockIConfigClient) ListenConfigWithContext(ctx context.Context, params vo.ConfigParam) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListenConfigWithContext", ctx, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ListenConfigWithContext indicates an expected call of ListenConfigWithContext
+func (mr *MockIConfigClientMockRecorder) ListenConfigWithContext(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListenConfigWithContext", reflect.TypeOf((*MockIConfigClient)(nil).ListenConfigWithContext), ctx, params)
+}
+
+// SearchConfigWithContext mocks base method
+func (m *MockIConfigClient) SearchConfigWithContext(ctx context.Context, param vo.SearchConfigParam) (*model.ConfigPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchConfigWithContext", ctx, param)
+	ret0, _ := ret[0].(*model.ConfigPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchConfigWithContext indicates an expected call of SearchConfigWithContext
+func (mr *MockIConfigClientMockRecorder) SearchConfigWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchConfigWithContext", reflect.TypeOf((*MockIConfigClient)(nil).SearchConfigWithContext), ctx, param)
+}
+
+// ListenConfigWithPrefixWithContext mocks base method
+func (m *MockIConfigClient) ListenConfigWithPrefixWithContext(ctx context.Context, param vo.ConfigPrefixParam) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListenConfigWithPrefixWithContext", ctx, param)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ListenConfigWithPrefixWithContext indicates an expected call of ListenConfigWithPrefixWithContext
+func (mr *MockIConfigClientMockRecorder) ListenConfigWithPrefixWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListenConfigWithPrefixWithContext", reflect.TypeOf((*MockIConfigClient)(nil).ListenConfigWithPrefixWithContext), ctx, param)
+}
+
+// ListenConfigGroupWithContext mocks base method
+func (m *MockIConfigClient) ListenConfigGroupWithContext(ctx context.Context, param vo.ConfigGroupParam) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListenConfigGroupWithContext", ctx, param)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ListenConfigGroupWithContext indicates an expected call of ListenConfigGroupWithContext
+func (mr *MockIConfigClientMockRecorder) ListenConfigGroupWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListenConfigGroupWithContext", reflect.TypeOf((*MockIConfigClient)(nil).ListenConfigGroupWithContext), ctx, param)
+}
+
+// GetConfigHistoryWithContext mocks base method
+func (m *MockIConfigClient) GetConfigHistoryWithContext(ctx context.Context, param vo.ConfigHistoryParam) (*model.ConfigHistoryPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigHistoryWithContext", ctx, param)
+	ret0, _ := ret[0].(*model.ConfigHistoryPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigHistoryWithContext indicates an expected call of GetConfigHistoryWithContext
+func (mr *MockIConfigClientMockRecorder) GetConfigHistoryWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigHistoryWithContext", reflect.TypeOf((*MockIConfigClient)(nil).GetConfigHistoryWithContext), ctx, param)
+}
+
+// GetPreviousConfigWithContext mocks base method
+func (m *MockIConfigClient) GetPreviousConfigWithContext(ctx context.Context, param vo.ConfigHistoryDetailParam) (*model.ConfigHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreviousConfigWithContext", ctx, param)
+	ret0, _ := ret[0].(*model.ConfigHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreviousConfigWithContext indicates an expected call of GetPreviousConfigWithContext
+func (mr *MockIConfigClientMockRecorder) GetPreviousConfigWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreviousConfigWithContext", reflect.TypeOf((*MockIConfigClient)(nil).GetPreviousConfigWithContext), ctx, param)
+}
+
+// RollbackConfigWithContext mocks base method
+func (m *MockIConfigClient) RollbackConfigWithContext(ctx context.Context, param vo.ConfigHistoryDetailParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackConfigWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollbackConfigWithContext indicates an expected call of RollbackConfigWithContext
+func (mr *MockIConfigClientMockRecorder) RollbackConfigWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackConfigWithContext", reflect.TypeOf((*MockIConfigClient)(nil).RollbackConfigWithContext), ctx, param)
+}
+
+// ExportConfigsWithContext mocks base method
+func (m *MockIConfigClient) ExportConfigsWithContext(ctx context.Context, param vo.ExportConfigParam) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportConfigsWithContext", ctx, param)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportConfigsWithContext indicates an expected call of ExportConfigsWithContext
+func (mr *MockIConfigClientMockRecorder) ExportConfigsWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportConfigsWithContext", reflect.TypeOf((*MockIConfigClient)(nil).ExportConfigsWithContext), ctx, param)
+}
+
+// ImportConfigsWithContext mocks base method
+func (m *MockIConfigClient) ImportConfigsWithContext(ctx context.Context, param vo.ImportConfigParam) (*model.ConfigImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportConfigsWithContext", ctx, param)
+	ret0, _ := ret[0].(*model.ConfigImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportConfigsWithContext indicates an expected call of ImportConfigsWithContext
+func (mr *MockIConfigClientMockRecorder) ImportConfigsWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportConfigsWithContext", reflect.TypeOf((*MockIConfigClient)(nil).ImportConfigsWithContext), ctx, param)
+}
+
+// UpdateClientConfig mocks base method
+func (m *MockIConfigClient) UpdateClientConfig(opts ...constant.ClientOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateClientConfig", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateClientConfig indicates an expected call of UpdateClientConfig
+func (mr *MockIConfigClientMockRecorder) UpdateClientConfig(opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateClientConfig", reflect.TypeOf((*MockIConfigClient)(nil).UpdateClientConfig), varargs...)
+}
+
+// UpdateServerConfig mocks base method
+func (m *MockIConfigClient) UpdateServerConfig(serverConfigs []constant.ServerConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateServerConfig", serverConfigs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateServerConfig indicates an expected call of UpdateServerConfig
+func (mr *MockIConfigClientMockRecorder) UpdateServerConfig(serverConfigs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateServerConfig", reflect.TypeOf((*MockIConfigClient)(nil).UpdateServerConfig), serverConfigs)
+}
+
+// Close mocks base method
+func (m *MockIConfigClient) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockIConfigClientMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIConfigClient)(nil).Close))
+}
+
+// GetConfigAsync mocks base method
+func (m *MockIConfigClient) GetConfigAsync(ctx context.Context, param vo.ConfigParam) <-chan model.ConfigResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigAsync", ctx, param)
+	ret0, _ := ret[0].(<-chan model.ConfigResult)
+	return ret0
+}
+
+// GetConfigAsync indicates an expected call of GetConfigAsync
+func (mr *MockIConfigClientMockRecorder) GetConfigAsync(ctx interface{}, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigAsync", reflect.TypeOf((*MockIConfigClient)(nil).GetConfigAsync), ctx, param)
+}
+
+// PublishConfigAsync mocks base method
+func (m *MockIConfigClient) PublishConfigAsync(ctx context.Context, param vo.ConfigParam) <-chan model.AsyncResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishConfigAsync", ctx, param)
+	ret0, _ := ret[0].(<-chan model.AsyncResult)
+	return ret0
+}
+
+// PublishConfigAsync indicates an expected call of PublishConfigAsync
+func (mr *MockIConfigClientMockRecorder) PublishConfigAsync(ctx interface{}, param interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigContent", reflect.TypeOf((*MockIConfigClient)(nil).GetConfigContent), dataId, groupId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishConfigAsync", reflect.TypeOf((*MockIConfigClient)(nil).PublishConfigAsync), ctx, param)
 }
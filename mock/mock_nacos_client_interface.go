@@ -120,3 +120,40 @@ func (mr *MockINacosClientMockRecorder) GetHttpAgent() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHttpAgent", reflect.TypeOf((*MockINacosClient)(nil).GetHttpAgent))
 }
+
+// MockCloseableClient is a mock of CloseableClient interface
+type MockCloseableClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockCloseableClientMockRecorder
+}
+
+// MockCloseableClientMockRecorder is the mock recorder for MockCloseableClient
+type MockCloseableClientMockRecorder struct {
+	mock *MockCloseableClient
+}
+
+// NewMockCloseableClient creates a new mock instance
+func NewMockCloseableClient(ctrl *gomock.Controller) *MockCloseableClient {
+	mock := &MockCloseableClient{ctrl: ctrl}
+	mock.recorder = &MockCloseableClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCloseableClient) EXPECT() *MockCloseableClientMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method
+func (m *MockCloseableClient) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockCloseableClientMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockCloseableClient)(nil).Close))
+}
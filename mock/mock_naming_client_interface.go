@@ -5,10 +5,14 @@
 package mock
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
+	constant "github.com/nacos-group/nacos-sdk-go/common/constant"
+	event "github.com/nacos-group/nacos-sdk-go/common/event"
 	model "github.com/nacos-group/nacos-sdk-go/model"
 	vo "github.com/nacos-group/nacos-sdk-go/vo"
 	reflect "reflect"
+	time "time"
 )
 
 // MockINamingClient is a mock of INamingClient interface
@@ -64,6 +68,110 @@ func (mr *MockINamingClientMockRecorder) DeregisterInstance(param interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeregisterInstance", reflect.TypeOf((*MockINamingClient)(nil).DeregisterInstance), param)
 }
 
+// UpdateInstance mocks base method
+func (m *MockINamingClient) UpdateInstance(param vo.UpdateInstanceParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateInstance", param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateInstance indicates an expected call of UpdateInstance
+func (mr *MockINamingClientMockRecorder) UpdateInstance(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInstance", reflect.TypeOf((*MockINamingClient)(nil).UpdateInstance), param)
+}
+
+// DrainInstance mocks base method
+func (m *MockINamingClient) DrainInstance(param vo.DrainInstanceParam, duration time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DrainInstance", param, duration)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DrainInstance indicates an expected call of DrainInstance
+func (mr *MockINamingClientMockRecorder) DrainInstance(param, duration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrainInstance", reflect.TypeOf((*MockINamingClient)(nil).DrainInstance), param, duration)
+}
+
+// BatchRegisterInstance mocks base method
+func (m *MockINamingClient) BatchRegisterInstance(param vo.BatchRegisterInstanceParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchRegisterInstance", param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchRegisterInstance indicates an expected call of BatchRegisterInstance
+func (mr *MockINamingClientMockRecorder) BatchRegisterInstance(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchRegisterInstance", reflect.TypeOf((*MockINamingClient)(nil).BatchRegisterInstance), param)
+}
+
+// BatchDeregisterInstance mocks base method
+func (m *MockINamingClient) BatchDeregisterInstance(param vo.BatchDeregisterInstanceParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchDeregisterInstance", param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchDeregisterInstance indicates an expected call of BatchDeregisterInstance
+func (mr *MockINamingClientMockRecorder) BatchDeregisterInstance(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchDeregisterInstance", reflect.TypeOf((*MockINamingClient)(nil).BatchDeregisterInstance), param)
+}
+
+// PauseHeartbeat mocks base method
+func (m *MockINamingClient) PauseHeartbeat(param vo.HeartbeatParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseHeartbeat", param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PauseHeartbeat indicates an expected call of PauseHeartbeat
+func (mr *MockINamingClientMockRecorder) PauseHeartbeat(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseHeartbeat", reflect.TypeOf((*MockINamingClient)(nil).PauseHeartbeat), param)
+}
+
+// ResumeHeartbeat mocks base method
+func (m *MockINamingClient) ResumeHeartbeat(param vo.HeartbeatParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeHeartbeat", param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumeHeartbeat indicates an expected call of ResumeHeartbeat
+func (mr *MockINamingClientMockRecorder) ResumeHeartbeat(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeHeartbeat", reflect.TypeOf((*MockINamingClient)(nil).ResumeHeartbeat), param)
+}
+
+// DeregisterAllInstances mocks base method
+func (m *MockINamingClient) DeregisterAllInstances() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeregisterAllInstances")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeregisterAllInstances indicates an expected call of DeregisterAllInstances
+func (mr *MockINamingClientMockRecorder) DeregisterAllInstances() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeregisterAllInstances", reflect.TypeOf((*MockINamingClient)(nil).DeregisterAllInstances))
+}
+
 // GetService mocks base method
 func (m *MockINamingClient) GetService(param vo.GetServiceParam) (model.Service, error) {
 	m.ctrl.T.Helper()
@@ -79,6 +187,21 @@ func (mr *MockINamingClientMockRecorder) GetService(param interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetService", reflect.TypeOf((*MockINamingClient)(nil).GetService), param)
 }
 
+// SelectAllInstances mocks base method
+func (m *MockINamingClient) SelectAllInstances(param vo.SelectAllInstancesParam) ([]model.Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectAllInstances", param)
+	ret0, _ := ret[0].([]model.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectAllInstances indicates an expected call of SelectAllInstances
+func (mr *MockINamingClientMockRecorder) SelectAllInstances(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAllInstances", reflect.TypeOf((*MockINamingClient)(nil).SelectAllInstances), param)
+}
+
 // SelectInstances mocks base method
 func (m *MockINamingClient) SelectInstances(param vo.SelectInstancesParam) ([]model.Instance, error) {
 	m.ctrl.T.Helper()
@@ -109,6 +232,21 @@ func (mr *MockINamingClientMockRecorder) SelectOneHealthyInstance(param interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectOneHealthyInstance", reflect.TypeOf((*MockINamingClient)(nil).SelectOneHealthyInstance), param)
 }
 
+// SelectInstanceByHash mocks base method
+func (m *MockINamingClient) SelectInstanceByHash(param vo.SelectOneHealthInstanceParam, hashKey string) (*model.Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectInstanceByHash", param, hashKey)
+	ret0, _ := ret[0].(*model.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectInstanceByHash indicates an expected call of SelectInstanceByHash
+func (mr *MockINamingClientMockRecorder) SelectInstanceByHash(param, hashKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectInstanceByHash", reflect.TypeOf((*MockINamingClient)(nil).SelectInstanceByHash), param, hashKey)
+}
+
 // Subscribe mocks base method
 func (m *MockINamingClient) Subscribe(param *vo.SubscribeParam) error {
 	m.ctrl.T.Helper()
@@ -136,3 +274,594 @@ func (mr *MockINamingClientMockRecorder) Unsubscribe(param interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockINamingClient)(nil).Unsubscribe), param)
 }
+
+// GetSubscribedServices mocks base method
+func (m *MockINamingClient) GetSubscribedServices() []model.SubscribedService {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscribedServices")
+	ret0, _ := ret[0].([]model.SubscribedService)
+	return ret0
+}
+
+// GetSubscribedServices indicates an expected call of GetSubscribedServices
+func (mr *MockINamingClientMockRecorder) GetSubscribedServices() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscribedServices", reflect.TypeOf((*MockINamingClient)(nil).GetSubscribedServices))
+}
+
+// CreateService mocks base method
+func (m *MockINamingClient) CreateService(param vo.CreateServiceParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateService", param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateService indicates an expected call of CreateService
+func (mr *MockINamingClientMockRecorder) CreateService(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateService", reflect.TypeOf((*MockINamingClient)(nil).CreateService), param)
+}
+
+// UpdateService mocks base method
+func (m *MockINamingClient) UpdateService(param vo.UpdateServiceParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateService", param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateService indicates an expected call of UpdateService
+func (mr *MockINamingClientMockRecorder) UpdateService(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateService", reflect.TypeOf((*MockINamingClient)(nil).UpdateService), param)
+}
+
+// DeleteService mocks base method
+func (m *MockINamingClient) DeleteService(param vo.DeleteServiceParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteService", param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteService indicates an expected call of DeleteService
+func (mr *MockINamingClientMockRecorder) DeleteService(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteService", reflect.TypeOf((*MockINamingClient)(nil).DeleteService), param)
+}
+
+// GetServiceDetail mocks base method
+func (m *MockINamingClient) GetServiceDetail(param vo.GetServiceDetailParam) (model.ServiceMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceDetail", param)
+	ret0, _ := ret[0].(model.ServiceMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceDetail indicates an expected call of GetServiceDetail
+func (mr *MockINamingClientMockRecorder) GetServiceDetail(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceDetail", reflect.TypeOf((*MockINamingClient)(nil).GetServiceDetail), param)
+}
+
+// GetAllServicesInfo mocks base method
+func (m *MockINamingClient) GetAllServicesInfo(param vo.GetAllServiceInfoParam) (model.ServiceList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllServicesInfo", param)
+	ret0, _ := ret[0].(model.ServiceList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllServicesInfo indicates an expected call of GetAllServicesInfo
+func (mr *MockINamingClientMockRecorder) GetAllServicesInfo(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllServicesInfo", reflect.TypeOf((*MockINamingClient)(nil).GetAllServicesInfo), param)
+}
+
+// SearchService mocks base method
+func (m *MockINamingClient) SearchService(param vo.SearchServiceParam) (model.ServiceList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchService", param)
+	ret0, _ := ret[0].(model.ServiceList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchService indicates an expected call of SearchService
+func (mr *MockINamingClientMockRecorder) SearchService(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchService", reflect.TypeOf((*MockINamingClient)(nil).SearchService), param)
+}
+
+// WatchAllServices mocks base method
+func (m *MockINamingClient) WatchAllServices(param vo.WatchServicesParam) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchAllServices", param)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WatchAllServices indicates an expected call of WatchAllServices
+func (mr *MockINamingClientMockRecorder) WatchAllServices(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchAllServices", reflect.TypeOf((*MockINamingClient)(nil).WatchAllServices), param)
+}
+
+// GetCachedServices mocks base method
+func (m *MockINamingClient) GetCachedServices() []model.Service {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCachedServices")
+	ret0, _ := ret[0].([]model.Service)
+	return ret0
+}
+
+// GetCachedServices indicates an expected call of GetCachedServices
+func (mr *MockINamingClientMockRecorder) GetCachedServices() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCachedServices", reflect.TypeOf((*MockINamingClient)(nil).GetCachedServices))
+}
+
+// GetCachedServiceInfoMapSnapshot mocks base method
+func (m *MockINamingClient) GetCachedServiceInfoMapSnapshot() map[string]model.Service {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCachedServiceInfoMapSnapshot")
+	ret0, _ := ret[0].(map[string]model.Service)
+	return ret0
+}
+
+// GetCachedServiceInfoMapSnapshot indicates an expected call of GetCachedServiceInfoMapSnapshot
+func (mr *MockINamingClientMockRecorder) GetCachedServiceInfoMapSnapshot() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCachedServiceInfoMapSnapshot", reflect.TypeOf((*MockINamingClient)(nil).GetCachedServiceInfoMapSnapshot))
+}
+
+// GetUpdateTimeMap mocks base method
+func (m *MockINamingClient) GetUpdateTimeMap() map[string]uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUpdateTimeMap")
+	ret0, _ := ret[0].(map[string]uint64)
+	return ret0
+}
+
+// GetUpdateTimeMap indicates an expected call of GetUpdateTimeMap
+func (mr *MockINamingClientMockRecorder) GetUpdateTimeMap() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUpdateTimeMap", reflect.TypeOf((*MockINamingClient)(nil).GetUpdateTimeMap))
+}
+
+// PurgeServiceCache mocks base method
+func (m *MockINamingClient) PurgeServiceCache(param vo.PurgeServiceCacheParam) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeServiceCache", param)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// PurgeServiceCache indicates an expected call of PurgeServiceCache
+func (mr *MockINamingClientMockRecorder) PurgeServiceCache(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeServiceCache", reflect.TypeOf((*MockINamingClient)(nil).PurgeServiceCache), param)
+}
+
+// UpdateServiceCache mocks base method
+func (m *MockINamingClient) UpdateServiceCache(param vo.UpdateServiceCacheParam) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateServiceCache", param)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateServiceCache indicates an expected call of UpdateServiceCache
+func (mr *MockINamingClientMockRecorder) UpdateServiceCache(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateServiceCache", reflect.TypeOf((*MockINamingClient)(nil).UpdateServiceCache), param)
+}
+
+// SubscribeEvent mocks base method
+func (m *MockINamingClient) SubscribeEvent(listener event.Listener, types ...event.EventType) int64 {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{listener}
+	for _, a := range types {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SubscribeEvent", varargs...)
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// SubscribeEvent indicates an expected call of SubscribeEvent
+func (mr *MockINamingClientMockRecorder) SubscribeEvent(listener interface{}, types ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{listener}, types...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeEvent", reflect.TypeOf((*MockINamingClient)(nil).SubscribeEvent), varargs...)
+}
+
+// UnsubscribeEvent mocks base method
+func (m *MockINamingClient) UnsubscribeEvent(id int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UnsubscribeEvent", id)
+}
+
+// UnsubscribeEvent indicates an expected call of UnsubscribeEvent
+func (mr *MockINamingClientMockRecorder) UnsubscribeEvent(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsubscribeEvent", reflect.TypeOf((*MockINamingClient)(nil).UnsubscribeEvent), id)
+}
+
+// RegisterInstanceWithContext mocks base method
+func (m *MockINamingClient) RegisterInstanceWithContext(ctx context.Context, param vo.RegisterInstanceParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterInstanceWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterInstanceWithContext indicates an expected call of RegisterInstanceWithContext
+func (mr *MockINamingClientMockRecorder) RegisterInstanceWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterInstanceWithContext", reflect.TypeOf((*MockINamingClient)(nil).RegisterInstanceWithContext), ctx, param)
+}
+
+// DeregisterInstanceWithContext mocks base method
+func (m *MockINamingClient) DeregisterInstanceWithContext(ctx context.Context, param vo.DeregisterInstanceParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeregisterInstanceWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeregisterInstanceWithContext indicates an expected call of DeregisterInstanceWithContext
+func (mr *MockINamingClientMockRecorder) DeregisterInstanceWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeregisterInstanceWithContext", reflect.TypeOf((*MockINamingClient)(nil).DeregisterInstanceWithContext), ctx, param)
+}
+
+// UpdateInstanceWithContext mocks base method
+func (m *MockINamingClient) UpdateInstanceWithContext(ctx context.Context, param vo.UpdateInstanceParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateInstanceWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateInstanceWithContext indicates an expected call of UpdateInstanceWithContext
+func (mr *MockINamingClientMockRecorder) UpdateInstanceWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInstanceWithContext", reflect.TypeOf((*MockINamingClient)(nil).UpdateInstanceWithContext), ctx, param)
+}
+
+// DrainInstanceWithContext mocks base method
+func (m *MockINamingClient) DrainInstanceWithContext(ctx context.Context, param vo.DrainInstanceParam, duration time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DrainInstanceWithContext", ctx, param, duration)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DrainInstanceWithContext indicates an expected call of DrainInstanceWithContext
+func (mr *MockINamingClientMockRecorder) DrainInstanceWithContext(ctx, param, duration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrainInstanceWithContext", reflect.TypeOf((*MockINamingClient)(nil).DrainInstanceWithContext), ctx, param, duration)
+}
+
+// BatchRegisterInstanceWithContext mocks base method
+func (m *MockINamingClient) BatchRegisterInstanceWithContext(ctx context.Context, param vo.BatchRegisterInstanceParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchRegisterInstanceWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchRegisterInstanceWithContext indicates an expected call of BatchRegisterInstanceWithContext
+func (mr *MockINamingClientMockRecorder) BatchRegisterInstanceWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchRegisterInstanceWithContext", reflect.TypeOf((*MockINamingClient)(nil).BatchRegisterInstanceWithContext), ctx, param)
+}
+
+// BatchDeregisterInstanceWithContext mocks base method
+func (m *MockINamingClient) BatchDeregisterInstanceWithContext(ctx context.Context, param vo.BatchDeregisterInstanceParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchDeregisterInstanceWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchDeregisterInstanceWithContext indicates an expected call of BatchDeregisterInstanceWithContext
+func (mr *MockINamingClientMockRecorder) BatchDeregisterInstanceWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchDeregisterInstanceWithContext", reflect.TypeOf((*MockINamingClient)(nil).BatchDeregisterInstanceWithContext), ctx, param)
+}
+
+// DeregisterAllInstancesWithContext mocks base method
+func (m *MockINamingClient) DeregisterAllInstancesWithContext(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeregisterAllInstancesWithContext", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeregisterAllInstancesWithContext indicates an expected call of DeregisterAllInstancesWithContext
+func (mr *MockINamingClientMockRecorder) DeregisterAllInstancesWithContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeregisterAllInstancesWithContext", reflect.TypeOf((*MockINamingClient)(nil).DeregisterAllInstancesWithContext), ctx)
+}
+
+// GetServiceWithContext mocks base method
+func (m *MockINamingClient) GetServiceWithContext(ctx context.Context, param vo.GetServiceParam) (model.Service, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceWithContext", ctx, param)
+	ret0, _ := ret[0].(model.Service)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceWithContext indicates an expected call of GetServiceWithContext
+func (mr *MockINamingClientMockRecorder) GetServiceWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceWithContext", reflect.TypeOf((*MockINamingClient)(nil).GetServiceWithContext), ctx, param)
+}
+
+// SelectAllInstancesWithContext mocks base method
+func (m *MockINamingClient) SelectAllInstancesWithContext(ctx context.Context, param vo.SelectAllInstancesParam) ([]model.Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectAllInstancesWithContext", ctx, param)
+	ret0, _ := ret[0].([]model.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectAllInstancesWithContext indicates an expected call of SelectAllInstancesWithContext
+func (mr *MockINamingClientMockRecorder) SelectAllInstancesWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAllInstancesWithContext", reflect.TypeOf((*MockINamingClient)(nil).SelectAllInstancesWithContext), ctx, param)
+}
+
+// SelectInstancesWithContext mocks base method
+func (m *MockINamingClient) SelectInstancesWithContext(ctx context.Context, param vo.SelectInstancesParam) ([]model.Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectInstancesWithContext", ctx, param)
+	ret0, _ := ret[0].([]model.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectInstancesWithContext indicates an expected call of SelectInstancesWithContext
+func (mr *MockINamingClientMockRecorder) SelectInstancesWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectInstancesWithContext", reflect.TypeOf((*MockINamingClient)(nil).SelectInstancesWithContext), ctx, param)
+}
+
+// SelectOneHealthyInstanceWithContext mocks base method
+func (m *MockINamingClient) SelectOneHealthyInstanceWithContext(ctx context.Context, param vo.SelectOneHealthInstanceParam) (*model.Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectOneHealthyInstanceWithContext", ctx, param)
+	ret0, _ := ret[0].(*model.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectOneHealthyInstanceWithContext indicates an expected call of SelectOneHealthyInstanceWithContext
+func (mr *MockINamingClientMockRecorder) SelectOneHealthyInstanceWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectOneHealthyInstanceWithContext", reflect.TypeOf((*MockINamingClient)(nil).SelectOneHealthyInstanceWithContext), ctx, param)
+}
+
+// SelectInstanceByHashWithContext mocks base method
+func (m *MockINamingClient) SelectInstanceByHashWithContext(ctx context.Context, param vo.SelectOneHealthInstanceParam, hashKey string) (*model.Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectInstanceByHashWithContext", ctx, param, hashKey)
+	ret0, _ := ret[0].(*model.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectInstanceByHashWithContext indicates an expected call of SelectInstanceByHashWithContext
+func (mr *MockINamingClientMockRecorder) SelectInstanceByHashWithContext(ctx, param, hashKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectInstanceByHashWithContext", reflect.TypeOf((*MockINamingClient)(nil).SelectInstanceByHashWithContext), ctx, param, hashKey)
+}
+
+// SubscribeWithContext mocks base method
+func (m *MockINamingClient) SubscribeWithContext(ctx context.Context, param *vo.SubscribeParam) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeWithContext", ctx, param)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubscribeWithContext indicates an expected call of SubscribeWithContext
+func (mr *MockINamingClientMockRecorder) SubscribeWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeWithContext", reflect.TypeOf((*MockINamingClient)(nil).SubscribeWithContext), ctx, param)
+}
+
+// CreateServiceWithContext mocks base method
+func (m *MockINamingClient) CreateServiceWithContext(ctx context.Context, param vo.CreateServiceParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateServiceWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateServiceWithContext indicates an expected call of CreateServiceWithContext
+func (mr *MockINamingClientMockRecorder) CreateServiceWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateServiceWithContext", reflect.TypeOf((*MockINamingClient)(nil).CreateServiceWithContext), ctx, param)
+}
+
+// UpdateServiceWithContext mocks base method
+func (m *MockINamingClient) UpdateServiceWithContext(ctx context.Context, param vo.UpdateServiceParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateServiceWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateServiceWithContext indicates an expected call of UpdateServiceWithContext
+func (mr *MockINamingClientMockRecorder) UpdateServiceWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateServiceWithContext", reflect.TypeOf((*MockINamingClient)(nil).UpdateServiceWithContext), ctx, param)
+}
+
+// DeleteServiceWithContext mocks base method
+func (m *MockINamingClient) DeleteServiceWithContext(ctx context.Context, param vo.DeleteServiceParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteServiceWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteServiceWithContext indicates an expected call of DeleteServiceWithContext
+func (mr *MockINamingClientMockRecorder) DeleteServiceWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServiceWithContext", reflect.TypeOf((*MockINamingClient)(nil).DeleteServiceWithContext), ctx, param)
+}
+
+// GetServiceDetailWithContext mocks base method
+func (m *MockINamingClient) GetServiceDetailWithContext(ctx context.Context, param vo.GetServiceDetailParam) (model.ServiceMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceDetailWithContext", ctx, param)
+	ret0, _ := ret[0].(model.ServiceMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceDetailWithContext indicates an expected call of GetServiceDetailWithContext
+func (mr *MockINamingClientMockRecorder) GetServiceDetailWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceDetailWithContext", reflect.TypeOf((*MockINamingClient)(nil).GetServiceDetailWithContext), ctx, param)
+}
+
+// GetAllServicesInfoWithContext mocks base method
+func (m *MockINamingClient) GetAllServicesInfoWithContext(ctx context.Context, param vo.GetAllServiceInfoParam) (model.ServiceList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllServicesInfoWithContext", ctx, param)
+	ret0, _ := ret[0].(model.ServiceList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllServicesInfoWithContext indicates an expected call of GetAllServicesInfoWithContext
+func (mr *MockINamingClientMockRecorder) GetAllServicesInfoWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllServicesInfoWithContext", reflect.TypeOf((*MockINamingClient)(nil).GetAllServicesInfoWithContext), ctx, param)
+}
+
+// SearchServiceWithContext mocks base method
+func (m *MockINamingClient) SearchServiceWithContext(ctx context.Context, param vo.SearchServiceParam) (model.ServiceList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchServiceWithContext", ctx, param)
+	ret0, _ := ret[0].(model.ServiceList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchServiceWithContext indicates an expected call of SearchServiceWithContext
+func (mr *MockINamingClientMockRecorder) SearchServiceWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchServiceWithContext", reflect.TypeOf((*MockINamingClient)(nil).SearchServiceWithContext), ctx, param)
+}
+
+// WatchAllServicesWithContext mocks base method
+func (m *MockINamingClient) WatchAllServicesWithContext(ctx context.Context, param vo.WatchServicesParam) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchAllServicesWithContext", ctx, param)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WatchAllServicesWithContext indicates an expected call of WatchAllServicesWithContext
+func (mr *MockINamingClientMockRecorder) WatchAllServicesWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchAllServicesWithContext", reflect.TypeOf((*MockINamingClient)(nil).WatchAllServicesWithContext), ctx, param)
+}
+
+// UpdateClientConfig mocks base method
+func (m *MockINamingClient) UpdateClientConfig(opts ...constant.ClientOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateClientConfig", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateClientConfig indicates an expected call of UpdateClientConfig
+func (mr *MockINamingClientMockRecorder) UpdateClientConfig(opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateClientConfig", reflect.TypeOf((*MockINamingClient)(nil).UpdateClientConfig), varargs...)
+}
+
+// UpdateServerConfig mocks base method
+func (m *MockINamingClient) UpdateServerConfig(serverConfigs []constant.ServerConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateServerConfig", serverConfigs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateServerConfig indicates an expected call of UpdateServerConfig
+func (mr *MockINamingClientMockRecorder) UpdateServerConfig(serverConfigs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateServerConfig", reflect.TypeOf((*MockINamingClient)(nil).UpdateServerConfig), serverConfigs)
+}
+
+// Close mocks base method
+func (m *MockINamingClient) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockINamingClientMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockINamingClient)(nil).Close))
+}
+
+// RegisterInstanceAsync mocks base method
+func (m *MockINamingClient) RegisterInstanceAsync(ctx context.Context, param vo.RegisterInstanceParam) <-chan model.AsyncResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterInstanceAsync", ctx, param)
+	ret0, _ := ret[0].(<-chan model.AsyncResult)
+	return ret0
+}
+
+// RegisterInstanceAsync indicates an expected call of RegisterInstanceAsync
+func (mr *MockINamingClientMockRecorder) RegisterInstanceAsync(ctx interface{}, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterInstanceAsync", reflect.TypeOf((*MockINamingClient)(nil).RegisterInstanceAsync), ctx, param)
+}
+
+// DeregisterInstanceAsync mocks base method
+func (m *MockINamingClient) DeregisterInstanceAsync(ctx context.Context, param vo.DeregisterInstanceParam) <-chan model.AsyncResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeregisterInstanceAsync", ctx, param)
+	ret0, _ := ret[0].(<-chan model.AsyncResult)
+	return ret0
+}
+
+// DeregisterInstanceAsync indicates an expected call of DeregisterInstanceAsync
+func (mr *MockINamingClientMockRecorder) DeregisterInstanceAsync(ctx interface{}, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeregisterInstanceAsync", reflect.TypeOf((*MockINamingClient)(nil).DeregisterInstanceAsync), ctx, param)
+}
@@ -0,0 +1,348 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: clients/operator_client/operator_client_interface.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/nacos-group/nacos-sdk-go/model"
+	vo "github.com/nacos-group/nacos-sdk-go/vo"
+	reflect "reflect"
+)
+
+// MockIOperatorClient is a mock of IOperatorClient interface
+type MockIOperatorClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockIOperatorClientMockRecorder
+}
+
+// MockIOperatorClientMockRecorder is the mock recorder for MockIOperatorClient
+type MockIOperatorClientMockRecorder struct {
+	mock *MockIOperatorClient
+}
+
+// NewMockIOperatorClient creates a new mock instance
+func NewMockIOperatorClient(ctrl *gomock.Controller) *MockIOperatorClient {
+	mock := &MockIOperatorClient{ctrl: ctrl}
+	mock.recorder = &MockIOperatorClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockIOperatorClient) EXPECT() *MockIOperatorClientMockRecorder {
+	return m.recorder
+}
+
+// GetMetrics mocks base method
+func (m *MockIOperatorClient) GetMetrics() (model.ServerMetrics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMetrics")
+	ret0, _ := ret[0].(model.ServerMetrics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMetrics indicates an expected call of GetMetrics
+func (mr *MockIOperatorClientMockRecorder) GetMetrics() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetrics", reflect.TypeOf((*MockIOperatorClient)(nil).GetMetrics))
+}
+
+// ListClusterMembers mocks base method
+func (m *MockIOperatorClient) ListClusterMembers(param vo.ListClusterMembersParam) ([]model.ClusterMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClusterMembers", param)
+	ret0, _ := ret[0].([]model.ClusterMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClusterMembers indicates an expected call of ListClusterMembers
+func (mr *MockIOperatorClientMockRecorder) ListClusterMembers(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClusterMembers", reflect.TypeOf((*MockIOperatorClient)(nil).ListClusterMembers), param)
+}
+
+// GetSwitches mocks base method
+func (m *MockIOperatorClient) GetSwitches() (model.ServerSwitches, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSwitches")
+	ret0, _ := ret[0].(model.ServerSwitches)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSwitches indicates an expected call of GetSwitches
+func (mr *MockIOperatorClientMockRecorder) GetSwitches() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSwitches", reflect.TypeOf((*MockIOperatorClient)(nil).GetSwitches))
+}
+
+// UpdateSwitch mocks base method
+func (m *MockIOperatorClient) UpdateSwitch(param vo.UpdateSwitchParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSwitch", param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSwitch indicates an expected call of UpdateSwitch
+func (mr *MockIOperatorClientMockRecorder) UpdateSwitch(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSwitch", reflect.TypeOf((*MockIOperatorClient)(nil).UpdateSwitch), param)
+}
+
+// GetServerState mocks base method
+func (m *MockIOperatorClient) GetServerState() (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServerState")
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServerState indicates an expected call of GetServerState
+func (mr *MockIOperatorClientMockRecorder) GetServerState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServerState", reflect.TypeOf((*MockIOperatorClient)(nil).GetServerState))
+}
+
+// CheckReadiness mocks base method
+func (m *MockIOperatorClient) CheckReadiness() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckReadiness")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckReadiness indicates an expected call of CheckReadiness
+func (mr *MockIOperatorClientMockRecorder) CheckReadiness() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckReadiness", reflect.TypeOf((*MockIOperatorClient)(nil).CheckReadiness))
+}
+
+// ListNamespaces mocks base method
+func (m *MockIOperatorClient) ListNamespaces() ([]model.Namespace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNamespaces")
+	ret0, _ := ret[0].([]model.Namespace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNamespaces indicates an expected call of ListNamespaces
+func (mr *MockIOperatorClientMockRecorder) ListNamespaces() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNamespaces", reflect.TypeOf((*MockIOperatorClient)(nil).ListNamespaces))
+}
+
+// CreateNamespace mocks base method
+func (m *MockIOperatorClient) CreateNamespace(param vo.CreateNamespaceParam) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNamespace", param)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNamespace indicates an expected call of CreateNamespace
+func (mr *MockIOperatorClientMockRecorder) CreateNamespace(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNamespace", reflect.TypeOf((*MockIOperatorClient)(nil).CreateNamespace), param)
+}
+
+// UpdateNamespace mocks base method
+func (m *MockIOperatorClient) UpdateNamespace(param vo.UpdateNamespaceParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNamespace", param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNamespace indicates an expected call of UpdateNamespace
+func (mr *MockIOperatorClientMockRecorder) UpdateNamespace(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNamespace", reflect.TypeOf((*MockIOperatorClient)(nil).UpdateNamespace), param)
+}
+
+// DeleteNamespace mocks base method
+func (m *MockIOperatorClient) DeleteNamespace(param vo.DeleteNamespaceParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNamespace", param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteNamespace indicates an expected call of DeleteNamespace
+func (mr *MockIOperatorClientMockRecorder) DeleteNamespace(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNamespace", reflect.TypeOf((*MockIOperatorClient)(nil).DeleteNamespace), param)
+}
+
+// GetMetricsWithContext mocks base method
+func (m *MockIOperatorClient) GetMetricsWithContext(ctx context.Context) (model.ServerMetrics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMetricsWithContext", ctx)
+	ret0, _ := ret[0].(model.ServerMetrics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMetricsWithContext indicates an expected call of GetMetricsWithContext
+func (mr *MockIOperatorClientMockRecorder) GetMetricsWithContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetricsWithContext", reflect.TypeOf((*MockIOperatorClient)(nil).GetMetricsWithContext), ctx)
+}
+
+// ListClusterMembersWithContext mocks base method
+func (m *MockIOperatorClient) ListClusterMembersWithContext(ctx context.Context, param vo.ListClusterMembersParam) ([]model.ClusterMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClusterMembersWithContext", ctx, param)
+	ret0, _ := ret[0].([]model.ClusterMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClusterMembersWithContext indicates an expected call of ListClusterMembersWithContext
+func (mr *MockIOperatorClientMockRecorder) ListClusterMembersWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClusterMembersWithContext", reflect.TypeOf((*MockIOperatorClient)(nil).ListClusterMembersWithContext), ctx, param)
+}
+
+// GetSwitchesWithContext mocks base method
+func (m *MockIOperatorClient) GetSwitchesWithContext(ctx context.Context) (model.ServerSwitches, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSwitchesWithContext", ctx)
+	ret0, _ := ret[0].(model.ServerSwitches)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSwitchesWithContext indicates an expected call of GetSwitchesWithContext
+func (mr *MockIOperatorClientMockRecorder) GetSwitchesWithContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSwitchesWithContext", reflect.TypeOf((*MockIOperatorClient)(nil).GetSwitchesWithContext), ctx)
+}
+
+// UpdateSwitchWithContext mocks base method
+func (m *MockIOperatorClient) UpdateSwitchWithContext(ctx context.Context, param vo.UpdateSwitchParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSwitchWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSwitchWithContext indicates an expected call of UpdateSwitchWithContext
+func (mr *MockIOperatorClientMockRecorder) UpdateSwitchWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSwitchWithContext", reflect.TypeOf((*MockIOperatorClient)(nil).UpdateSwitchWithContext), ctx, param)
+}
+
+// GetServerStateWithContext mocks base method
+func (m *MockIOperatorClient) GetServerStateWithContext(ctx context.Context) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServerStateWithContext", ctx)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServerStateWithContext indicates an expected call of GetServerStateWithContext
+func (mr *MockIOperatorClientMockRecorder) GetServerStateWithContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServerStateWithContext", reflect.TypeOf((*MockIOperatorClient)(nil).GetServerStateWithContext), ctx)
+}
+
+// CheckReadinessWithContext mocks base method
+func (m *MockIOperatorClient) CheckReadinessWithContext(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckReadinessWithContext", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckReadinessWithContext indicates an expected call of CheckReadinessWithContext
+func (mr *MockIOperatorClientMockRecorder) CheckReadinessWithContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckReadinessWithContext", reflect.TypeOf((*MockIOperatorClient)(nil).CheckReadinessWithContext), ctx)
+}
+
+// ListNamespacesWithContext mocks base method
+func (m *MockIOperatorClient) ListNamespacesWithContext(ctx context.Context) ([]model.Namespace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNamespacesWithContext", ctx)
+	ret0, _ := ret[0].([]model.Namespace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNamespacesWithContext indicates an expected call of ListNamespacesWithContext
+func (mr *MockIOperatorClientMockRecorder) ListNamespacesWithContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNamespacesWithContext", reflect.TypeOf((*MockIOperatorClient)(nil).ListNamespacesWithContext), ctx)
+}
+
+// CreateNamespaceWithContext mocks base method
+func (m *MockIOperatorClient) CreateNamespaceWithContext(ctx context.Context, param vo.CreateNamespaceParam) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNamespaceWithContext", ctx, param)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNamespaceWithContext indicates an expected call of CreateNamespaceWithContext
+func (mr *MockIOperatorClientMockRecorder) CreateNamespaceWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNamespaceWithContext", reflect.TypeOf((*MockIOperatorClient)(nil).CreateNamespaceWithContext), ctx, param)
+}
+
+// UpdateNamespaceWithContext mocks base method
+func (m *MockIOperatorClient) UpdateNamespaceWithContext(ctx context.Context, param vo.UpdateNamespaceParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNamespaceWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNamespaceWithContext indicates an expected call of UpdateNamespaceWithContext
+func (mr *MockIOperatorClientMockRecorder) UpdateNamespaceWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNamespaceWithContext", reflect.TypeOf((*MockIOperatorClient)(nil).UpdateNamespaceWithContext), ctx, param)
+}
+
+// DeleteNamespaceWithContext mocks base method
+func (m *MockIOperatorClient) DeleteNamespaceWithContext(ctx context.Context, param vo.DeleteNamespaceParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNamespaceWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteNamespaceWithContext indicates an expected call of DeleteNamespaceWithContext
+func (mr *MockIOperatorClientMockRecorder) DeleteNamespaceWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNamespaceWithContext", reflect.TypeOf((*MockIOperatorClient)(nil).DeleteNamespaceWithContext), ctx, param)
+}
+
+// Close mocks base method
+func (m *MockIOperatorClient) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockIOperatorClientMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIOperatorClient)(nil).Close))
+}
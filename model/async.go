@@ -0,0 +1,13 @@
+package model
+
+// 异步注册、注销、发布等操作的结果
+type AsyncResult struct {
+	Success bool
+	Err     error
+}
+
+// 异步获取配置的结果
+type ConfigResult struct {
+	Content string
+	Err     error
+}
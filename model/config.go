@@ -0,0 +1,74 @@
+package model
+
+// 配置搜索结果的一页
+type ConfigPage struct {
+	TotalCount     int          `json:"totalCount"`
+	PageNumber     int          `json:"pageNumber"`
+	PagesAvailable int          `json:"pagesAvailable"`
+	PageItems      []ConfigItem `json:"pageItems"`
+}
+
+type ConfigItem struct {
+	Id      string `json:"id"`
+	DataId  string `json:"dataId"`
+	Group   string `json:"group"`
+	Content string `json:"content"`
+	Md5     string `json:"md5"`
+	Tenant  string `json:"tenant"`
+	AppName string `json:"appName"`
+	Type    string `json:"type"`
+}
+
+// 配置历史的一页，按修改时间倒序
+type ConfigHistoryPage struct {
+	TotalCount     int             `json:"totalCount"`
+	PageNumber     int             `json:"pageNumber"`
+	PagesAvailable int             `json:"pagesAvailable"`
+	PageItems      []ConfigHistory `json:"pageItems"`
+}
+
+// 一条配置历史，OpType为I(新建)、U(修改)或D(删除)
+// 新建时Content为新建的内容，修改和删除时Content为操作前的内容
+type ConfigHistory struct {
+	Id               string `json:"id"`
+	LastId           int64  `json:"lastId"`
+	DataId           string `json:"dataId"`
+	Group            string `json:"group"`
+	Tenant           string `json:"tenant"`
+	AppName          string `json:"appName"`
+	Md5              string `json:"md5"`
+	Content          string `json:"content"`
+	SrcIp            string `json:"srcIp"`
+	SrcUser          string `json:"srcUser"`
+	OpType           string `json:"opType"`
+	CreatedTime      int64  `json:"createdTime"`
+	LastModifiedTime int64  `json:"lastModifiedTime"`
+}
+
+// 导入配置的结果，FailData和SkipData中只包含DataId和Group
+type ConfigImportResult struct {
+	SuccCount int          `json:"succCount"`
+	SkipCount int          `json:"skipCount"`
+	FailData  []ConfigItem `json:"failData"`
+	SkipData  []ConfigItem `json:"skipData"`
+}
+
+// 配置变化的类型
+const (
+	Config_Change_Added    = "ADDED"
+	Config_Change_Modified = "MODIFIED"
+	Config_Change_Deleted  = "DELETED"
+)
+
+// 监听的配置的一次变化，内容为解密后的内容
+// 此前内容未知时ChangeType为ADDED，OldContent和OldMd5为空；配置被删除时NewContent和NewMd5为空
+type ConfigChangeEvent struct {
+	Namespace  string `json:"namespace"`
+	Group      string `json:"group"`
+	DataId     string `json:"dataId"`
+	OldContent string `json:"oldContent"`
+	NewContent string `json:"newContent"`
+	OldMd5     string `json:"oldMd5"`
+	NewMd5     string `json:"newMd5"`
+	ChangeType string `json:"changeType"`
+}
@@ -0,0 +1,59 @@
+package model
+
+// 服务端的运行指标，计数为当前节点的统计
+type ServerMetrics struct {
+	Status                   string  `json:"status"`
+	ServiceCount             int     `json:"serviceCount"`
+	InstanceCount            int     `json:"instanceCount"`
+	SubscribeCount           int     `json:"subscribeCount"`
+	ResponsibleServiceCount  int     `json:"responsibleServiceCount"`
+	ResponsibleInstanceCount int     `json:"responsibleInstanceCount"`
+	ClientCount              int     `json:"clientCount"`
+	RaftNotifyTaskCount      int     `json:"raftNotifyTaskCount"`
+	Cpu                      float64 `json:"cpu"`
+	Load                     float64 `json:"load"`
+	Mem                      float64 `json:"mem"`
+}
+
+// 集群成员，State为UP、DOWN或SUSPICIOUS，ExtendInfo中包含raft等元数据
+type ClusterMember struct {
+	Ip            string                 `json:"ip"`
+	Port          uint64                 `json:"port"`
+	State         string                 `json:"state"`
+	Address       string                 `json:"address"`
+	FailAccessCnt int                    `json:"failAccessCnt"`
+	ExtendInfo    map[string]interface{} `json:"extendInfo"`
+}
+
+// 服务端开关中常用的部分，完整的开关见服务端SwitchDomain
+type ServerSwitches struct {
+	Name                         string         `json:"name"`
+	Masters                      []string       `json:"masters"`
+	AdWeightMap                  map[string]int `json:"adWeightMap"`
+	DefaultPushCacheMillis       int64          `json:"defaultPushCacheMillis"`
+	ClientBeatInterval           int64          `json:"clientBeatInterval"`
+	DefaultCacheMillis           int64          `json:"defaultCacheMillis"`
+	DistroThreshold              float64        `json:"distroThreshold"`
+	HealthCheckEnabled           bool           `json:"healthCheckEnabled"`
+	AutoChangeHealthCheckEnabled bool           `json:"autoChangeHealthCheckEnabled"`
+	DistroEnabled                bool           `json:"distroEnabled"`
+	EnableStandalone             bool           `json:"enableStandalone"`
+	PushEnabled                  bool           `json:"pushEnabled"`
+	CheckTimes                   int            `json:"checkTimes"`
+	DisableAddIP                 bool           `json:"disableAddIP"`
+	SendBeatOnly                 bool           `json:"sendBeatOnly"`
+	LightBeatEnabled             bool           `json:"lightBeatEnabled"`
+	DefaultInstanceEphemeral     bool           `json:"defaultInstanceEphemeral"`
+	ServerStatus                 string         `json:"serverStatus"`
+	LimitedUrlMap                map[string]int `json:"limitedUrlMap"`
+}
+
+// 命名空间，Namespace为命名空间ID，public命名空间的ID为空，Type为0(public)、1(全局配置)或2(自定义)
+type Namespace struct {
+	Namespace         string `json:"namespace"`
+	NamespaceShowName string `json:"namespaceShowName"`
+	NamespaceDesc     string `json:"namespaceDesc"`
+	Quota             int    `json:"quota"`
+	ConfigCount       int    `json:"configCount"`
+	Type              int    `json:"type"`
+}
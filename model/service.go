@@ -51,6 +51,8 @@ type Service struct {
 	Clusters        string            `json:"clusters"`
 	Metadata        map[string]string `json:"metadata"`
 	Name            string            `json:"name"`
+	// 服务的保护阈值，服务端返回时优先于ClientConfig.ProtectThreshold
+	ProtectThreshold float64 `json:"protectThreshold"`
 }
 
 type ServiceDetail struct {
@@ -68,6 +70,17 @@ type ServiceInfo struct {
 	Selector         ServiceSelector   `json:"selector"`
 }
 
+// 服务端保存的服务信息，Selector.Type为label时按Expression过滤实例，none表示不过滤
+type ServiceMeta struct {
+	NamespaceId      string             `json:"namespaceId"`
+	GroupName        string             `json:"groupName"`
+	Name             string             `json:"name"`
+	ProtectThreshold float64            `json:"protectThreshold"`
+	Metadata         map[string]string  `json:"metadata"`
+	Selector         ExpressionSelector `json:"selector"`
+	Clusters         []Cluster          `json:"clusters"`
+}
+
 type ServiceSelector struct {
 	Selector string
 }
@@ -98,6 +111,34 @@ type SubscribeService struct {
 	Weight      float64           `json:"weight"`
 }
 
+// 服务实例列表的变化，按ClusterName、Ip和Port识别同一实例
+type InstanceChangeEvent struct {
+	ServiceName string     `json:"serviceName"`
+	Clusters    string     `json:"clusters"`
+	Added       []Instance `json:"added"`
+	Removed     []Instance `json:"removed"`
+	Modified    []Instance `json:"modified"`
+}
+
+func (e InstanceChangeEvent) IsEmpty() bool {
+	return len(e.Added) == 0 && len(e.Removed) == 0 && len(e.Modified) == 0
+}
+
+// 分组下服务名列表的变化，服务名不带分组前缀
+type ServiceListChangeEvent struct {
+	NameSpace string   `json:"namespace"`
+	GroupName string   `json:"groupName"`
+	Added     []string `json:"added"`
+	Removed   []string `json:"removed"`
+}
+
+// 有订阅回调的服务，ServiceName带分组前缀，Subscribers为回调数
+type SubscribedService struct {
+	ServiceName string `json:"serviceName"`
+	Clusters    string `json:"clusters"`
+	Subscribers int    `json:"subscribers"`
+}
+
 type BeatInfo struct {
 	Ip          string            `json:"ip"`
 	Port        uint64            `json:"port"`
@@ -108,6 +149,10 @@ type BeatInfo struct {
 	Scheduled   bool              `json:"scheduled"`
 	Period      time.Duration     `json:"-"`
 	Stopped     bool              `json:"-"`
+	// 实例被设置为不可用，心跳时重新注册沿用该状态
+	Disabled bool `json:"-"`
+	// 暂停发送心跳，服务端在心跳超时后将实例标记为不健康
+	Paused bool `json:"-"`
 }
 
 type ExpressionSelector struct {
@@ -0,0 +1,100 @@
+//go:build grpc
+// +build grpc
+
+// gRPC的服务发现，需要以-tags grpc构建，未使用gRPC的应用不引入grpc依赖
+package grpc_resolver
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	nacos_resolver "github.com/nacos-group/nacos-sdk-go/resolver"
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/resolver"
+	"reflect"
+)
+
+type weightKey struct{}
+
+type metadataKey struct{}
+
+// 实例的元数据，实现Equal供gRPC比较地址属性
+type metadata map[string]string
+
+func (m metadata) Equal(o interface{}) bool {
+	other, ok := o.(metadata)
+	return ok && reflect.DeepEqual(m, other)
+}
+
+// 返回地址对应实例的权重，供自定义balancer按权重选择
+func Weight(addr resolver.Address) (float64, bool) {
+	weight, ok := addr.BalancerAttributes.Value(weightKey{}).(float64)
+	return weight, ok
+}
+
+// 返回地址对应实例的元数据
+func Metadata(addr resolver.Address) map[string]string {
+	m, _ := addr.BalancerAttributes.Value(metadataKey{}).(metadata)
+	return m
+}
+
+type builder struct {
+	client naming_client.INamingClient
+}
+
+// 基于服务发现客户端的resolver.Builder，通过grpc.WithResolvers使用，解析nacos:///my-service?cluster=c1&group=g1
+func NewBuilder(client naming_client.INamingClient) resolver.Builder {
+	return &builder{client: client}
+}
+
+// 全局注册nacos scheme，需在grpc.Dial之前调用且不能并发调用
+func Register(client naming_client.INamingClient) {
+	resolver.Register(NewBuilder(client))
+}
+
+func (b *builder) Build(target resolver.Target, cc resolver.ClientConn, opts resolver.BuildOptions) (resolver.Resolver, error) {
+	t, err := nacos_resolver.ParseTarget(target.URL.String())
+	if err != nil {
+		return nil, err
+	}
+	r := &nacosResolver{cc: cc}
+	if r.watcher, err = nacos_resolver.NewWatcher(b.client, t, r.update); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (b *builder) Scheme() string {
+	return nacos_resolver.Scheme
+}
+
+type nacosResolver struct {
+	cc      resolver.ClientConn
+	watcher *nacos_resolver.Watcher
+}
+
+// 订阅推送的实例变化转换为gRPC地址，权重和元数据作为BalancerAttributes
+func (r *nacosResolver) update(addresses []nacos_resolver.Address, err error) {
+	if err != nil {
+		r.cc.ReportError(err)
+		return
+	}
+	state := resolver.State{Addresses: make([]resolver.Address, 0, len(addresses))}
+	for _, address := range addresses {
+		state.Addresses = append(state.Addresses, resolver.Address{
+			Addr:               address.Addr,
+			BalancerAttributes: attributes.New(weightKey{}, address.Weight).WithValue(metadataKey{}, metadata(address.Metadata)),
+		})
+	}
+	if err = r.cc.UpdateState(state); err != nil {
+		logger.Warnf("[resolver] update grpc state failed, addresses:%d err:%s", len(state.Addresses), err.Error())
+	}
+}
+
+// 实例变化由订阅推送，无需主动解析
+func (r *nacosResolver) ResolveNow(resolver.ResolveNowOptions) {}
+
+func (r *nacosResolver) Close() {
+	if err := r.watcher.Close(); err != nil {
+		logger.Warnf("[resolver] unsubscribe failed, err:%s", err.Error())
+	}
+}
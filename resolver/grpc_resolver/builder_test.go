@@ -0,0 +1,74 @@
+//go:build grpc
+// +build grpc
+
+package grpc_resolver
+
+import (
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/nacos-group/nacos-sdk-go/mock"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/resolver"
+	"net/url"
+	"testing"
+)
+
+type fakeClientConn struct {
+	resolver.ClientConn
+	states []resolver.State
+	errs   []error
+}
+
+func (cc *fakeClientConn) UpdateState(state resolver.State) error {
+	cc.states = append(cc.states, state)
+	return nil
+}
+
+func (cc *fakeClientConn) ReportError(err error) {
+	cc.errs = append(cc.errs, err)
+}
+
+func TestBuilder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock.NewMockINamingClient(ctrl)
+	var param *vo.SubscribeParam
+	client.EXPECT().Subscribe(gomock.Any()).DoAndReturn(func(p *vo.SubscribeParam) error {
+		param = p
+		return nil
+	})
+
+	b := NewBuilder(client)
+	assert.Equal(t, "nacos", b.Scheme())
+	u, _ := url.Parse("nacos:///demo?cluster=c1&group=g1")
+	cc := &fakeClientConn{}
+	r, err := b.Build(resolver.Target{URL: *u}, cc, resolver.BuildOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "demo", param.ServiceName)
+	assert.Equal(t, "g1", param.GroupName)
+
+	param.SubscribeCallback([]model.SubscribeService{
+		{Ip: "10.0.0.1", Port: 8080, Weight: 2, Enable: true, Valid: true, Metadata: map[string]string{"zone": "a"}},
+		{Ip: "10.0.0.2", Port: 8080, Weight: 1, Enable: true, Valid: false},
+	}, nil)
+	assert.Equal(t, 1, len(cc.states))
+	addresses := cc.states[0].Addresses
+	assert.Equal(t, 1, len(addresses))
+	assert.Equal(t, "10.0.0.1:8080", addresses[0].Addr)
+	weight, ok := Weight(addresses[0])
+	assert.True(t, ok)
+	assert.Equal(t, float64(2), weight)
+	assert.Equal(t, map[string]string{"zone": "a"}, Metadata(addresses[0]))
+	assert.True(t, addresses[0].BalancerAttributes.Equal(addresses[0].BalancerAttributes))
+
+	param.SubscribeCallback(nil, errors.New("unavailable"))
+	assert.Equal(t, 1, len(cc.errs))
+
+	client.EXPECT().Unsubscribe(gomock.Eq(param)).Return(nil)
+	r.Close()
+
+	_, err = b.Build(resolver.Target{URL: url.URL{Scheme: "nacos", Path: "/"}}, cc, resolver.BuildOptions{})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,97 @@
+package resolver
+
+import (
+	"errors"
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// 服务发现地址的scheme，如nacos:///my-service?cluster=c1&group=g1
+const Scheme = "nacos"
+
+type Target struct {
+	ServiceName string
+	GroupName   string
+	Clusters    []string
+}
+
+// 解析nacos:///my-service?cluster=c1,c2&group=g1，group为空时使用DEFAULT_GROUP
+func ParseTarget(target string) (Target, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return Target{}, err
+	}
+	if u.Scheme != Scheme {
+		return Target{}, errors.New("[resolver] unsupported scheme:" + u.Scheme)
+	}
+	serviceName := strings.Trim(u.Path, "/")
+	if serviceName == "" {
+		return Target{}, errors.New("[resolver] service name can not be empty:" + target)
+	}
+	query := u.Query()
+	t := Target{ServiceName: serviceName, GroupName: query.Get("group")}
+	if t.GroupName == "" {
+		t.GroupName = constant.DEFAULT_GROUP
+	}
+	for _, cluster := range strings.Split(query.Get("cluster"), ",") {
+		if cluster != "" {
+			t.Clusters = append(t.Clusters, cluster)
+		}
+	}
+	return t, nil
+}
+
+// 可用于负载均衡的实例地址，Weight和Metadata可作为balancer的属性
+type Address struct {
+	Addr     string
+	Weight   float64
+	Metadata map[string]string
+}
+
+// 订阅服务，实例列表变化时将可用实例的地址推送给onUpdate
+// 用于对接各类框架的服务发现，gRPC可直接使用grpc_resolver包
+type Watcher struct {
+	client naming_client.INamingClient
+	param  *vo.SubscribeParam
+}
+
+func NewWatcher(client naming_client.INamingClient, target Target, onUpdate func(addresses []Address, err error)) (*Watcher, error) {
+	param := &vo.SubscribeParam{
+		ServiceName: target.ServiceName,
+		GroupName:   target.GroupName,
+		Clusters:    target.Clusters,
+		SubscribeCallback: func(services []model.SubscribeService, err error) {
+			onUpdate(toAddresses(services), err)
+		},
+	}
+	if err := client.Subscribe(param); err != nil {
+		return nil, err
+	}
+	return &Watcher{client: client, param: param}, nil
+}
+
+func (w *Watcher) Close() error {
+	return w.client.Unsubscribe(w.param)
+}
+
+// 只保留启用、健康且权重大于0的实例
+func toAddresses(services []model.SubscribeService) []Address {
+	addresses := make([]Address, 0, len(services))
+	for _, service := range services {
+		if !service.Enable || !service.Valid || service.Weight <= 0 {
+			continue
+		}
+		addresses = append(addresses, Address{
+			Addr:     net.JoinHostPort(service.Ip, strconv.FormatUint(service.Port, 10)),
+			Weight:   service.Weight,
+			Metadata: service.Metadata,
+		})
+	}
+	return addresses
+}
@@ -0,0 +1,57 @@
+package resolver
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/nacos-group/nacos-sdk-go/mock"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseTarget(t *testing.T) {
+	target, err := ParseTarget("nacos:///my-service?cluster=c1,c2&group=g1")
+	assert.Nil(t, err)
+	assert.Equal(t, Target{ServiceName: "my-service", GroupName: "g1", Clusters: []string{"c1", "c2"}}, target)
+
+	target, err = ParseTarget("nacos:///my-service")
+	assert.Nil(t, err)
+	assert.Equal(t, Target{ServiceName: "my-service", GroupName: "DEFAULT_GROUP"}, target)
+
+	_, err = ParseTarget("dns:///my-service")
+	assert.NotNil(t, err)
+	_, err = ParseTarget("nacos:///")
+	assert.NotNil(t, err)
+}
+
+func TestWatcher(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock.NewMockINamingClient(ctrl)
+
+	var param *vo.SubscribeParam
+	client.EXPECT().Subscribe(gomock.Any()).DoAndReturn(func(p *vo.SubscribeParam) error {
+		param = p
+		return nil
+	})
+	var addresses []Address
+	w, err := NewWatcher(client, Target{ServiceName: "demo", GroupName: "g1", Clusters: []string{"c1"}}, func(a []Address, err error) {
+		addresses = a
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "demo", param.ServiceName)
+	assert.Equal(t, []string{"c1"}, param.Clusters)
+
+	param.SubscribeCallback([]model.SubscribeService{
+		{Ip: "10.0.0.1", Port: 8080, Weight: 2, Enable: true, Valid: true, Metadata: map[string]string{"zone": "a"}},
+		{Ip: "10.0.0.2", Port: 8080, Weight: 1, Enable: false, Valid: true},
+		{Ip: "::1", Port: 8080, Weight: 1, Enable: true, Valid: true},
+	}, nil)
+	assert.Equal(t, []Address{
+		{Addr: "10.0.0.1:8080", Weight: 2, Metadata: map[string]string{"zone": "a"}},
+		{Addr: "[::1]:8080", Weight: 1},
+	}, addresses)
+
+	client.EXPECT().Unsubscribe(gomock.Eq(param)).Return(nil)
+	assert.Nil(t, w.Close())
+}
@@ -0,0 +1,125 @@
+package resolver
+
+import (
+	"errors"
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/load_balancer"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// 连接失败时默认最多换2个实例重试
+const Default_Max_Retries = 2
+
+// 通过服务发现访问上游服务的http.RoundTripper
+// 请求地址的主机名格式为service-name.group.namespace，group和namespace可省略，如http://demo.go.DEFAULT_GROUP/hello
+// 每次请求通过SelectOneHealthyInstance选择一个实例，连接失败时换下一个实例重试
+type Transport struct {
+	// 返回指定命名空间的服务发现客户端，可直接使用MultiTenantClient.NamingClient
+	NamingClient func(namespaceId string) (naming_client.INamingClient, error)
+	// 为空时使用http.DefaultTransport
+	Base http.RoundTripper
+	// 为空时使用ClientConfig.LoadBalancer
+	LoadBalancer load_balancer.LoadBalancer
+	Clusters     []string
+	// 为0时使用Default_Max_Retries，小于0时不重试
+	MaxRetries int
+}
+
+// 只访问client所在命名空间的服务
+func NewTransport(client naming_client.INamingClient) *Transport {
+	return &Transport{
+		NamingClient: func(string) (naming_client.INamingClient, error) {
+			return client, nil
+		},
+	}
+}
+
+// 解析主机名中的服务名、分组和命名空间，服务名本身不能包含"."
+func parseHost(host string) (serviceName, groupName, namespaceId string) {
+	parts := strings.SplitN(host, ".", 3)
+	serviceName = parts[0]
+	groupName = constant.DEFAULT_GROUP
+	if len(parts) > 1 {
+		groupName = parts[1]
+	}
+	if len(parts) > 2 {
+		namespaceId = parts[2]
+	}
+	return
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	serviceName, groupName, namespaceId := parseHost(req.URL.Hostname())
+	client, err := t.NamingClient(namespaceId)
+	if err != nil {
+		return nil, err
+	}
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	retries := t.MaxRetries
+	if retries == 0 {
+		retries = Default_Max_Retries
+	}
+	// 请求体无法重放时不重试
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		retries = 0
+	}
+	param := vo.SelectOneHealthInstanceParam{
+		ServiceName:  serviceName,
+		GroupName:    groupName,
+		Clusters:     t.Clusters,
+		LoadBalancer: t.LoadBalancer,
+	}
+	tried := map[string]bool{}
+	for attempt := 0; ; attempt++ {
+		instance, err := client.SelectOneHealthyInstanceWithContext(req.Context(), param)
+		if err != nil {
+			return nil, err
+		}
+		addr := net.JoinHostPort(instance.Ip, strconv.FormatUint(instance.Port, 10))
+		// 尽量换一个未尝试过的实例
+		for i := 0; i < 3 && tried[addr]; i++ {
+			if instance, err = client.SelectOneHealthyInstanceWithContext(req.Context(), param); err != nil {
+				return nil, err
+			}
+			addr = net.JoinHostPort(instance.Ip, strconv.FormatUint(instance.Port, 10))
+		}
+		tried[addr] = true
+
+		outReq, err := rewriteRequest(req, addr, attempt > 0)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := base.RoundTrip(outReq)
+		if err == nil {
+			return resp, nil
+		}
+		if attempt >= retries || req.Context().Err() != nil {
+			return nil, err
+		}
+		logger.Warnf("[resolver.Transport] request to %s of service %s@@%s failed, retry with another instance, err:%s", addr, groupName, serviceName, err.Error())
+	}
+}
+
+// 将请求地址替换为实例地址，重试时重新生成请求体
+func rewriteRequest(req *http.Request, addr string, retry bool) (*http.Request, error) {
+	outReq := req.Clone(req.Context())
+	outReq.URL.Host = addr
+	outReq.Host = ""
+	if retry && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, errors.New("[resolver.Transport] get request body failed:" + err.Error())
+		}
+		outReq.Body = body
+	}
+	return outReq, nil
+}
@@ -0,0 +1,57 @@
+package resolver
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/nacos-group/nacos-sdk-go/mock"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestParseHost(t *testing.T) {
+	serviceName, groupName, namespaceId := parseHost("demo")
+	assert.Equal(t, []string{"demo", "DEFAULT_GROUP", ""}, []string{serviceName, groupName, namespaceId})
+	serviceName, groupName, namespaceId = parseHost("demo.g1.ns1")
+	assert.Equal(t, []string{"demo", "g1", "ns1"}, []string{serviceName, groupName, namespaceId})
+}
+
+func instanceOf(t *testing.T, address string) *model.Instance {
+	host, port, err := net.SplitHostPort(address)
+	assert.Nil(t, err)
+	p, _ := strconv.Atoi(port)
+	return &model.Instance{Ip: host, Port: uint64(p), Healthy: true, Enable: true, Weight: 1}
+}
+
+func TestTransport_RetryOnConnectionError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello " + r.URL.Path))
+	}))
+	defer server.Close()
+	// 获取一个没有监听的地址
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	deadAddress := listener.Addr().String()
+	listener.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock.NewMockINamingClient(ctrl)
+	param := vo.SelectOneHealthInstanceParam{ServiceName: "demo", GroupName: "g1"}
+	gomock.InOrder(
+		client.EXPECT().SelectOneHealthyInstanceWithContext(gomock.Any(), gomock.Eq(param)).Return(instanceOf(t, deadAddress), nil),
+		client.EXPECT().SelectOneHealthyInstanceWithContext(gomock.Any(), gomock.Eq(param)).Return(instanceOf(t, server.Listener.Addr().String()), nil),
+	)
+
+	httpClient := &http.Client{Transport: NewTransport(client)}
+	resp, err := httpClient.Get("http://demo.g1/world")
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(t, "hello /world", string(body))
+}
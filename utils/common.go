@@ -6,9 +6,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/common/logger"
 	"github.com/nacos-group/nacos-sdk-go/model"
 	"io/ioutil"
-	"log"
 	"net"
 	"os"
 	"path/filepath"
@@ -25,30 +25,38 @@ var (
 )
 
 func TryDecompressData(data []byte) string {
+	bs, err := DecompressData(data)
+	if err != nil {
+		logger.Errorf("failed to decompress gzip data,err:%s", err.Error())
+		return ""
+	}
+	return string(bs)
+}
 
+// gzip格式的数据解压后返回，其他数据原样返回
+func DecompressData(data []byte) ([]byte, error) {
 	if !IsGzipFile(data) {
-		//fmt.Println("data format: plain text")
-		return string(data)
+		return data, nil
 	}
-
-	//fmt.Println("data format: gzip")
-
 	reader, err := gzip.NewReader(bytes.NewReader(data))
-
 	if err != nil {
-		log.Printf("[ERROR]:failed to decompress gzip data,err:%s \n", err.Error())
-		return ""
+		return nil, err
 	}
-
 	defer reader.Close()
-	bs, err1 := ioutil.ReadAll(reader)
+	return ioutil.ReadAll(reader)
+}
 
-	if err1 != nil {
-		log.Printf("[ERROR]:failed to decompress gzip data,err:%s \n", err1.Error())
-		return ""
+// 以gzip压缩数据
+func CompressData(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
 	}
-
-	return string(bs)
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
 func IsGzipFile(data []byte) bool {
@@ -63,11 +71,11 @@ func JsonToService(result string) *model.Service {
 	var service model.Service
 	err := json.Unmarshal([]byte(result), &service)
 	if err != nil {
-		log.Printf("[ERROR]:failed to unmarshal json string:%s err:%v \n", result, err.Error())
+		logger.Errorf("failed to unmarshal json string:%s err:%v", result, err.Error())
 		return nil
 	}
 	if len(service.Hosts) == 0 {
-		log.Printf("[WARN]:instance list is empty,json string:%s \n", result)
+		logger.Warnf("instance list is empty,json string:%s", result)
 		return nil
 	}
 	return &service
@@ -110,14 +118,14 @@ func LocalIP() string {
 	if localIP == "" {
 		addrs, err := net.InterfaceAddrs()
 		if err != nil {
-			log.Printf("[ERROR]:get InterfaceAddres failed,err:%s \n", err.Error())
+			logger.Errorf("get InterfaceAddres failed,err:%s", err.Error())
 			return ""
 		}
 		for _, address := range addrs {
 			if ipnet, ok := address.(*net.IPNet); ok && !ipnet.IP.IsLoopback() {
 				if ipnet.IP.To4() != nil {
 					localIP = ipnet.IP.String()
-					log.Printf("InitLocalIp, LocalIp:%s \n", localIP)
+					logger.Infof("InitLocalIp, LocalIp:%s", localIP)
 					break
 				}
 			}
@@ -126,15 +134,16 @@ func LocalIP() string {
 	return localIP
 }
 
+// 读取元数据中以毫秒为单位的时长，不存在或格式错误时返回defaultDuration
 func GetDurationWithDefault(metadata map[string]string, key string, defaultDuration time.Duration) time.Duration {
 	data, ok := metadata[key]
 	if ok {
 		value, err := strconv.ParseInt(data, 10, 64)
 		if err != nil {
-			log.Printf("key:%s is not a number \n", key)
+			logger.Warnf("key:%s is not a number", key)
 			return defaultDuration
 		}
-		return time.Duration(value)
+		return time.Duration(value) * time.Millisecond
 	}
 	return defaultDuration
 }
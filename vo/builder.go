@@ -0,0 +1,166 @@
+package vo
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"strconv"
+	"time"
+)
+
+// 构造注册实例的参数，默认权重为1，可用、健康的临时实例
+//
+//	param := vo.NewInstance("10.0.0.1", 8080).Service("demo").Weight(10).Metadata("version", "v2").Cluster("c1").Build()
+type InstanceBuilder struct {
+	param RegisterInstanceParam
+}
+
+func NewInstance(ip string, port uint64) *InstanceBuilder {
+	return &InstanceBuilder{param: RegisterInstanceParam{
+		Ip:        ip,
+		Port:      port,
+		Weight:    1,
+		Enable:    true,
+		Healthy:   true,
+		Ephemeral: true,
+	}}
+}
+
+func (b *InstanceBuilder) Service(serviceName string) *InstanceBuilder {
+	b.param.ServiceName = serviceName
+	return b
+}
+
+func (b *InstanceBuilder) Group(groupName string) *InstanceBuilder {
+	b.param.GroupName = groupName
+	return b
+}
+
+func (b *InstanceBuilder) Cluster(clusterName string) *InstanceBuilder {
+	b.param.ClusterName = clusterName
+	return b
+}
+
+func (b *InstanceBuilder) Weight(weight float64) *InstanceBuilder {
+	b.param.Weight = weight
+	return b
+}
+
+func (b *InstanceBuilder) Enable(enable bool) *InstanceBuilder {
+	b.param.Enable = enable
+	return b
+}
+
+func (b *InstanceBuilder) Healthy(healthy bool) *InstanceBuilder {
+	b.param.Healthy = healthy
+	return b
+}
+
+// 为false时注册持久化实例，持久化实例不发送心跳
+func (b *InstanceBuilder) Ephemeral(ephemeral bool) *InstanceBuilder {
+	b.param.Ephemeral = ephemeral
+	return b
+}
+
+func (b *InstanceBuilder) Metadata(key, value string) *InstanceBuilder {
+	if b.param.Metadata == nil {
+		b.param.Metadata = map[string]string{}
+	}
+	b.param.Metadata[key] = value
+	return b
+}
+
+// 心跳间隔，服务端也以该值通知客户端的心跳间隔
+func (b *InstanceBuilder) HeartBeatInterval(interval time.Duration) *InstanceBuilder {
+	return b.Metadata(constant.HEART_BEAT_INTERVAL, formatMillis(interval))
+}
+
+// 超过该时间未收到心跳时服务端将实例标记为不健康
+func (b *InstanceBuilder) HeartBeatTimeout(timeout time.Duration) *InstanceBuilder {
+	return b.Metadata(constant.HEART_BEAT_TIMEOUT, formatMillis(timeout))
+}
+
+// 超过该时间未收到心跳时服务端删除实例
+func (b *InstanceBuilder) IpDeleteTimeout(timeout time.Duration) *InstanceBuilder {
+	return b.Metadata(constant.IP_DELETE_TIMEOUT, formatMillis(timeout))
+}
+
+// 实例id的生成方式，可选constant.SIMPLE_INSTANCE_ID_GENERATOR和constant.SNOWFLAKE_INSTANCE_ID_GENERATOR
+func (b *InstanceBuilder) InstanceIdGenerator(generator string) *InstanceBuilder {
+	return b.Metadata(constant.INSTANCE_ID_GENERATOR, generator)
+}
+
+// 返回的参数不与builder共享Metadata，之后修改builder不影响已构造的参数
+func (b *InstanceBuilder) Build() RegisterInstanceParam {
+	param := b.param
+	param.Metadata = copyMetadata(b.param.Metadata)
+	return param
+}
+
+// 构造注销同一实例的参数
+func (b *InstanceBuilder) BuildDeregister() DeregisterInstanceParam {
+	return DeregisterInstanceParam{
+		Ip:          b.param.Ip,
+		Port:        b.param.Port,
+		Tenant:      b.param.Tenant,
+		Cluster:     b.param.ClusterName,
+		ServiceName: b.param.ServiceName,
+		GroupName:   b.param.GroupName,
+		Ephemeral:   b.param.Ephemeral,
+	}
+}
+
+// 构造创建服务的参数
+//
+//	param := vo.NewService("demo").Group("group").ProtectThreshold(0.5).Metadata("owner", "team-a").Build()
+type ServiceBuilder struct {
+	param CreateServiceParam
+}
+
+func NewService(serviceName string) *ServiceBuilder {
+	return &ServiceBuilder{param: CreateServiceParam{ServiceName: serviceName}}
+}
+
+func (b *ServiceBuilder) Group(groupName string) *ServiceBuilder {
+	b.param.GroupName = groupName
+	return b
+}
+
+func (b *ServiceBuilder) ProtectThreshold(threshold float64) *ServiceBuilder {
+	b.param.ProtectThreshold = threshold
+	return b
+}
+
+func (b *ServiceBuilder) Metadata(key, value string) *ServiceBuilder {
+	if b.param.Metadata == nil {
+		b.param.Metadata = map[string]string{}
+	}
+	b.param.Metadata[key] = value
+	return b
+}
+
+// 按标签表达式过滤实例，如CONSUMER.label.env = PROVIDER.label.env
+func (b *ServiceBuilder) Selector(expression string) *ServiceBuilder {
+	b.param.Selector = &model.ExpressionSelector{Type: "label", Expression: expression}
+	return b
+}
+
+func (b *ServiceBuilder) Build() CreateServiceParam {
+	param := b.param
+	param.Metadata = copyMetadata(b.param.Metadata)
+	return param
+}
+
+func formatMillis(duration time.Duration) string {
+	return strconv.FormatInt(int64(duration/time.Millisecond), 10)
+}
+
+func copyMetadata(metadata map[string]string) map[string]string {
+	if metadata == nil {
+		return nil
+	}
+	copied := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		copied[key] = value
+	}
+	return copied
+}
@@ -0,0 +1,53 @@
+package vo
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestInstanceBuilder(t *testing.T) {
+	builder := NewInstance("10.0.0.1", 8080).Service("demo").Group("group").Cluster("c1").
+		Weight(10).Metadata("version", "v2").HeartBeatInterval(3 * time.Second).
+		HeartBeatTimeout(15 * time.Second).InstanceIdGenerator(constant.SNOWFLAKE_INSTANCE_ID_GENERATOR)
+	param := builder.Build()
+	assert.Equal(t, RegisterInstanceParam{
+		Ip:          "10.0.0.1",
+		Port:        8080,
+		Weight:      10,
+		Enable:      true,
+		Healthy:     true,
+		Ephemeral:   true,
+		ClusterName: "c1",
+		ServiceName: "demo",
+		GroupName:   "group",
+		Metadata: map[string]string{
+			"version":                      "v2",
+			constant.HEART_BEAT_INTERVAL:   "3000",
+			constant.HEART_BEAT_TIMEOUT:    "15000",
+			constant.INSTANCE_ID_GENERATOR: "snowflake",
+		},
+	}, param)
+
+	builder.Metadata("version", "v3")
+	assert.Equal(t, "v2", param.Metadata["version"])
+	assert.Equal(t, DeregisterInstanceParam{
+		Ip:          "10.0.0.1",
+		Port:        8080,
+		Cluster:     "c1",
+		ServiceName: "demo",
+		GroupName:   "group",
+		Ephemeral:   true,
+	}, builder.BuildDeregister())
+}
+
+func TestServiceBuilder(t *testing.T) {
+	param := NewService("demo").Group("group").ProtectThreshold(0.5).Metadata("owner", "team-a").
+		Selector("CONSUMER.label.env = PROVIDER.label.env").Build()
+	assert.Equal(t, "demo", param.ServiceName)
+	assert.Equal(t, "group", param.GroupName)
+	assert.Equal(t, 0.5, param.ProtectThreshold)
+	assert.Equal(t, map[string]string{"owner": "team-a"}, param.Metadata)
+	assert.Equal(t, "label", param.Selector.Type)
+}
@@ -1,5 +1,10 @@
 package vo
 
+import (
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"time"
+)
+
 /**
 *
 * @description :
@@ -10,10 +15,86 @@ package vo
 **/
 
 type ConfigParam struct {
+	DataId  string `param:"dataId"`
+	Group   string `param:"group"`
+	Content string `param:"content"`
+	Tag     string `param:"tag"`
+	AppName string `param:"appName"`
+	// 配置类型，如json、properties、yaml，发布时写入服务端，GetConfigAs时用于选择解析方式
+	Type string `param:"type"`
+	// 发布时写入的配置标签，多个标签以逗号分隔，SearchConfig可按标签过滤
+	ConfigTags string `param:"config_tags"`
+	// 发布时写入的配置描述
+	Desc string `param:"desc"`
+	// PublishConfigCas时期望的服务端当前内容的md5
+	CasMd5 string
+	// 发布时只推送给这些客户端ip，多个ip以逗号分隔
+	BetaIps string
+	// 为true时GetConfig获取beta配置
+	Beta     bool
+	OnChange func(namespace, group, dataId, data string)
+	// 监听时接收包含变化前后内容和变化类型的事件，配置被删除时也会通知，与OnChange至少设置一个，同时设置时忽略OnChange
+	OnChangeEvent func(event model.ConfigChangeEvent)
+	// 监听时校验变化后的内容，返回错误时不通知监听者，GetConfig继续返回上次校验通过的内容，配置再次变化时重新校验
+	Validate func(content string) error
+}
+
+type SearchConfigParam struct {
+	// blur为模糊搜索，DataId和Group中可使用*通配符；accurate为精确搜索，为空时使用blur
+	Search string `param:"search"`
+	DataId string `param:"dataId"`
+	Group  string `param:"group"`
+	// 按发布时的ConfigTags过滤，多个标签以逗号分隔
+	Tag      string `param:"config_tags"`
+	AppName  string `param:"appName"`
+	PageNo   uint32 `param:"pageNo"`
+	PageSize uint32 `param:"pageSize"`
+	// 搜索的命名空间，为空时使用ClientConfig.NamespaceId
+	NamespaceId string
+}
+
+type ConfigHistoryParam struct {
 	DataId   string `param:"dataId"`
 	Group    string `param:"group"`
-	Content  string `param:"content"`
-	Tag      string `param:"tag"`
-	AppName  string `param:"appName"`
-	OnChange func(namespace, group, dataId, data string)
+	PageNo   uint32 `param:"pageNo"`
+	PageSize uint32 `param:"pageSize"`
+}
+
+// 指定一条配置历史，Id为ConfigHistory.Id
+type ConfigHistoryDetailParam struct {
+	Id     string `param:"id"`
+	DataId string `param:"dataId"`
+	Group  string `param:"group"`
+}
+
+// 导出配置，NamespaceId为空时使用ClientConfig.NamespaceId，Group、AppName和DataId为空时不过滤
+type ExportConfigParam struct {
+	NamespaceId string `param:"tenant"`
+	Group       string `param:"group"`
+	AppName     string `param:"appName"`
+	DataId      string `param:"dataId"`
+}
+
+// 导入ExportConfigs导出的zip，Policy为ABORT(默认)、SKIP或OVERWRITE，决定遇到已存在的配置时的处理方式
+type ImportConfigParam struct {
+	NamespaceId string
+	Content     []byte
+	Policy      string
+}
+
+type ConfigPrefixParam struct {
+	Group        string
+	DataIdPrefix string
+	// 定时搜索新创建的匹配配置的间隔，为0时使用Default_Prefix_Discovery_Interval
+	DiscoveryInterval time.Duration
+	OnChange          func(namespace, group, dataId, data string)
+}
+
+// 监听分组下的所有配置，之后新创建的配置也会被监听
+type ConfigGroupParam struct {
+	Group string
+	// 定时搜索新创建的配置的间隔，为0时使用Default_Prefix_Discovery_Interval
+	DiscoveryInterval time.Duration
+	// 已存在的配置在首次监听时以ADDED回调一次，新创建的配置同样以ADDED回调
+	OnChangeEvent func(event model.ConfigChangeEvent)
 }
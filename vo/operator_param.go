@@ -0,0 +1,30 @@
+package vo
+
+type ListClusterMembersParam struct {
+	// 为true时只返回健康的成员
+	Healthy bool `param:"healthy"`
+}
+
+// 修改服务端开关，Debug为true时只修改当前节点，否则同步到整个集群
+type UpdateSwitchParam struct {
+	Entry string `param:"entry"`
+	Value string `param:"value"`
+	Debug bool   `param:"debug"`
+}
+
+// 创建命名空间，NamespaceId为空时由服务端生成
+type CreateNamespaceParam struct {
+	NamespaceId   string `param:"customNamespaceId"`
+	NamespaceName string `param:"namespaceName"`
+	NamespaceDesc string `param:"namespaceDesc"`
+}
+
+type UpdateNamespaceParam struct {
+	NamespaceId   string `param:"namespace"`
+	NamespaceName string `param:"namespaceShowName"`
+	NamespaceDesc string `param:"namespaceDesc"`
+}
+
+type DeleteNamespaceParam struct {
+	NamespaceId string `param:"namespaceId"`
+}
@@ -1,6 +1,10 @@
 package vo
 
-import "github.com/nacos-group/nacos-sdk-go/model"
+import (
+	"github.com/nacos-group/nacos-sdk-go/common/load_balancer"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"time"
+)
 
 /**
 *
@@ -11,6 +15,7 @@ import "github.com/nacos-group/nacos-sdk-go/model"
 * @create : 2019-01-09 10:03
 **/
 
+// Ip为空时使用探测到的本机ip，见ClientConfig.LocalIp
 type RegisterInstanceParam struct {
 	Ip          string            `param:"ip"`
 	Port        uint64            `param:"port"`
@@ -25,6 +30,42 @@ type RegisterInstanceParam struct {
 	Ephemeral   bool              `param:"ephemeral"`
 }
 
+// 修改实例的权重、可用状态和元数据，通过该客户端注册的临时实例之后的心跳也会携带新的信息
+type UpdateInstanceParam struct {
+	Ip          string            `param:"ip"`
+	Port        uint64            `param:"port"`
+	Tenant      string            `param:"tenant"`
+	Weight      float64           `param:"weight"`
+	Enable      bool              `param:"enabled"`
+	Healthy     bool              `param:"healthy"`
+	Metadata    map[string]string `param:"metadata"`
+	ClusterName string            `param:"clusterName"`
+	ServiceName string            `param:"serviceName"`
+	GroupName   string            `param:"groupName"`
+	Ephemeral   bool              `param:"ephemeral"`
+}
+
+// 逐步将实例权重降为0后下线实例，实例的权重和元数据取自当前的服务实例列表
+type DrainInstanceParam struct {
+	Ip          string
+	Port        uint64
+	ClusterName string
+	ServiceName string
+	GroupName   string
+	Ephemeral   bool
+	// 降低权重的次数，小于等于0时为10
+	Steps int
+}
+
+// 暂停或恢复心跳的实例，只对通过该客户端注册的临时实例有效
+type HeartbeatParam struct {
+	Ip          string
+	Port        uint64
+	ServiceName string
+	GroupName   string
+}
+
+// Ip为空时使用探测到的本机ip，见ClientConfig.LocalIp
 type DeregisterInstanceParam struct {
 	Ip          string `param:"ip"`
 	Port        uint64 `param:"port"`
@@ -35,16 +76,63 @@ type DeregisterInstanceParam struct {
 	Ephemeral   bool   `param:"ephemeral"`
 }
 
+type BatchRegisterInstanceParam struct {
+	Instances []RegisterInstanceParam
+}
+
+type BatchDeregisterInstanceParam struct {
+	Instances []DeregisterInstanceParam
+}
+
 type GetServiceParam struct {
 	Clusters    []string `param:"clusters"`
 	ServiceName string   `param:"serviceName"`
 	GroupName   string   `param:"groupName"`
 }
 
+type PurgeServiceCacheParam struct {
+	Clusters    []string
+	ServiceName string
+	GroupName   string
+}
+
+// Hosts为写入后的完整实例列表，实例的ServiceName为空时使用分组后的服务名
+type UpdateServiceCacheParam struct {
+	Clusters    []string
+	ServiceName string
+	GroupName   string
+	Hosts       []model.Instance
+	Metadata    map[string]string
+}
+
 type GetAllServiceInfoParam struct {
-	Clusters  []string `param:"clusters"`
-	NameSpace string   `param:"nameSpace"`
-	GroupName string   `param:"groupName"`
+	NameSpace string `param:"nameSpace"`
+	GroupName string `param:"groupName"`
+	PageNo    uint32 `param:"pageNo"`
+	PageSize  uint32 `param:"pageSize"`
+}
+
+type SearchServiceParam struct {
+	NameSpace string `param:"nameSpace"`
+	GroupName string `param:"groupName"`
+	// 服务名的通配符表达式，支持*和?，如order-*，为空时不过滤
+	Pattern string
+	// 服务端按服务的标签表达式过滤
+	Selector *model.ExpressionSelector
+	PageNo   uint32 `param:"pageNo"`
+	PageSize uint32 `param:"pageSize"`
+}
+
+// 监听分组下的服务名列表，服务新增或删除时回调
+type WatchServicesParam struct {
+	NameSpace string
+	GroupName string
+	// 服务名的通配符表达式，格式同SearchServiceParam.Pattern，为空时监听分组下的全部服务
+	Pattern string
+	// 拉取服务列表的间隔，为0时使用Default_Watch_Services_Interval
+	Interval time.Duration
+	// 首次拉取后以全部服务作为Added回调一次
+	Callback func(event model.ServiceListChangeEvent)
 }
 
 type GetServiceListParam struct {
@@ -65,6 +153,32 @@ type GetServiceInstanceParam struct {
 
 type GetServiceDetailParam struct {
 	ServiceName string `param:"serviceName"`
+	GroupName   string `param:"groupName"`
+}
+
+// 创建服务，ProtectThreshold为保护阈值（0~1），健康实例占比低于该值时服务端返回全部实例
+// Selector为空时不按标签过滤实例
+type CreateServiceParam struct {
+	ServiceName      string                    `param:"serviceName"`
+	GroupName        string                    `param:"groupName"`
+	ProtectThreshold float64                   `param:"protectThreshold"`
+	Metadata         map[string]string         `param:"metadata"`
+	Selector         *model.ExpressionSelector `param:"selector"`
+}
+
+// 修改服务的保护阈值、元数据和实例选择器，未设置的字段会被置为默认值
+type UpdateServiceParam struct {
+	ServiceName      string                    `param:"serviceName"`
+	GroupName        string                    `param:"groupName"`
+	ProtectThreshold float64                   `param:"protectThreshold"`
+	Metadata         map[string]string         `param:"metadata"`
+	Selector         *model.ExpressionSelector `param:"selector"`
+}
+
+// 删除服务，服务下仍有实例时服务端会拒绝删除
+type DeleteServiceParam struct {
+	ServiceName string `param:"serviceName"`
+	GroupName   string `param:"groupName"`
 }
 
 type SubscribeParam struct {
@@ -72,12 +186,18 @@ type SubscribeParam struct {
 	Clusters          []string `param:"clusters"`
 	GroupName         string   `param:"groupName"`
 	SubscribeCallback func(services []model.SubscribeService, err error)
+	// 实例变化时只回调新增、删除和修改的实例，可与SubscribeCallback同时使用
+	ChangeCallback func(event model.InstanceChangeEvent)
+	// 按实例元数据过滤回调中的实例，如CONSUMER.label.env=prod，多个条件以&连接
+	Selector string
 }
 
 type SelectAllInstancesParam struct {
 	Clusters    []string `param:"clusters"`
 	ServiceName string   `param:"serviceName"`
 	GroupName   string   `param:"groupName"`
+	// 按实例元数据过滤实例，格式同SubscribeParam.Selector
+	Selector string
 }
 
 type SelectInstancesParam struct {
@@ -85,10 +205,36 @@ type SelectInstancesParam struct {
 	ServiceName string   `param:"serviceName"`
 	GroupName   string   `param:"groupName"`
 	HealthyOnly bool     `param:"healthyOnly"`
+	Selector    string
+}
+
+// 在多个分组中查询同一个服务，结果按分组返回
+type SelectInstancesInGroupsParam struct {
+	Clusters    []string
+	ServiceName string
+	GroupNames  []string
+	HealthyOnly bool
+	Selector    string
+	// 并发查询的分组数，小于等于0时为Default_Cross_Query_Concurrency
+	Concurrency int
+}
+
+// 在多个命名空间中查询同一个服务，结果按命名空间返回
+type SelectInstancesInNamespacesParam struct {
+	NamespaceIds []string
+	Clusters     []string
+	ServiceName  string
+	GroupName    string
+	HealthyOnly  bool
+	Selector     string
+	// 并发查询的命名空间数，小于等于0时为Default_Cross_Query_Concurrency
+	Concurrency int
 }
 
 type SelectOneHealthInstanceParam struct {
-	Clusters    []string `param:"clusters"`
-	ServiceName string   `param:"serviceName"`
-	GroupName   string   `param:"groupName"`
+	Clusters     []string `param:"clusters"`
+	ServiceName  string   `param:"serviceName"`
+	GroupName    string   `param:"groupName"`
+	LoadBalancer load_balancer.LoadBalancer
+	Selector     string
 }